@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"sync"
 	"testing"
 
 	"github.com/cosmos/cosmos-sdk/client"
@@ -12,6 +13,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/tx"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
@@ -27,6 +29,9 @@ type User interface {
 }
 
 type Broadcaster struct {
+	// mu serializes BroadcastTx calls, which share buf and keyrings.
+	mu sync.Mutex
+
 	// buf stores the output sdk.TxResponse when broadcast.Tx is invoked.
 	buf *bytes.Buffer
 	// keyrings is a mapping of keyrings which point to a temporary test directory. The contents
@@ -35,8 +40,8 @@ type Broadcaster struct {
 
 	// chain is a reference to the CosmosChain instance which will be the target of the messages.
 	chain *CosmosChain
-	// t is the testing.T for the current test.
-	t *testing.T
+	// t is the current test or benchmark.
+	t testing.TB
 
 	// factoryOptions is a slice of broadcast.FactoryOpt which enables arbitrary configuration of the tx.Factory.
 	factoryOptions []FactoryOpt
@@ -46,7 +51,7 @@ type Broadcaster struct {
 
 // NewBroadcaster returns a instance of Broadcaster which can be used with broadcast.Tx to
 // broadcast messages sdk messages.
-func NewBroadcaster(t *testing.T, chain *CosmosChain) *Broadcaster {
+func NewBroadcaster(t testing.TB, chain *CosmosChain) *Broadcaster {
 	return &Broadcaster{
 		t:        t,
 		chain:    chain,
@@ -181,6 +186,9 @@ func (b *Broadcaster) defaultTxFactory(clientCtx client.Context, accountNumber u
 
 // BroadcastTx uses the provided Broadcaster to broadcast all the provided messages which will be signed
 // by the User provided. The sdk.TxResponse and an error are returned.
+//
+// Transactions of the same user are signed one at a time through CosmosChain.WithKeySequence,
+// so BroadcastTx may be called from parallel goroutines sharing a user.
 func BroadcastTx(ctx context.Context, broadcaster *Broadcaster, broadcastingUser User, msgs ...sdk.Msg) (sdk.TxResponse, error) {
 	for _, msg := range msgs {
 		if err := msg.ValidateBasic(); err != nil {
@@ -188,6 +196,21 @@ func BroadcastTx(ctx context.Context, broadcaster *Broadcaster, broadcastingUser
 		}
 	}
 
+	var resp sdk.TxResponse
+	err := broadcaster.chain.WithKeySequence(ctx, broadcastingUser.GetKeyName(), func() (err error) {
+		resp, err = broadcastTx(ctx, broadcaster, broadcastingUser, msgs...)
+		if err == nil && resp.Codespace == sdkerrors.ErrWrongSequence.Codespace() && resp.Code == sdkerrors.ErrWrongSequence.ABCICode() {
+			return fmt.Errorf("%w: %s", sdkerrors.ErrWrongSequence, resp.RawLog)
+		}
+		return err
+	})
+	return resp, err
+}
+
+func broadcastTx(ctx context.Context, broadcaster *Broadcaster, broadcastingUser User, msgs ...sdk.Msg) (sdk.TxResponse, error) {
+	broadcaster.mu.Lock()
+	defer broadcaster.mu.Unlock()
+
 	f, err := broadcaster.GetFactory(ctx, broadcastingUser)
 	if err != nil {
 		return sdk.TxResponse{}, err
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/fnv"
+	"math/big"
 	"os"
 	"path"
 	"path/filepath"
@@ -32,6 +33,8 @@ import (
 	"github.com/strangelove-ventures/ibctest/v6/internal/configutil"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
 	"github.com/strangelove-ventures/ibctest/v6/test"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/strangelove-ventures/ibctest/v6/transcript"
 	tmjson "github.com/tendermint/tendermint/libs/json"
 	"github.com/tendermint/tendermint/p2p"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
@@ -59,9 +62,21 @@ type ChainNode struct {
 
 	containerID string
 
+	// Shared with the other nodes of the chain; follows the node's logs once started.
+	logWatcher *dockerutil.LogWatcher
+
+	// Shared with the other nodes of the chain; restarts the node's container if it crashes.
+	supervisor *dockerutil.Supervisor
+
 	// Ports set during StartContainer.
-	hostRPCPort  string
-	hostGRPCPort string
+	hostRPCPort   string
+	hostGRPCPort  string
+	hostPprofPort string
+	hostAPIPort   string
+
+	// Horcrux cosigners signing for the validator, by shard ID - 1, if it has a threshold remote signer.
+	// A stopped cosigner is nil.
+	cosigners []*dockerutil.Container
 }
 
 // ChainNodes is a collection of ChainNode
@@ -75,6 +90,7 @@ const (
 	grpcPort    = "9090/tcp"
 	apiPort     = "1317/tcp"
 	privValPort = "1234/tcp"
+	pprofPort   = "6060/tcp"
 )
 
 var (
@@ -84,6 +100,7 @@ var (
 		nat.Port(grpcPort):    {},
 		nat.Port(apiPort):     {},
 		nat.Port(privValPort): {},
+		nat.Port(pprofPort):   {},
 	}
 )
 
@@ -154,6 +171,15 @@ func (tn *ChainNode) overwriteGenesisFile(ctx context.Context, content []byte) e
 	return nil
 }
 
+func (tn *ChainNode) overwritePrivValidatorKey(ctx context.Context, content []byte) error {
+	fw := dockerutil.NewFileWriter(tn.logger(), tn.DockerClient, tn.TestName)
+	if err := fw.WriteFile(ctx, tn.VolumeName, "config/priv_validator_key.json", content); err != nil {
+		return fmt.Errorf("overwriting priv_validator_key.json: %w", err)
+	}
+
+	return nil
+}
+
 func (tn *ChainNode) copyGentx(ctx context.Context, destVal *ChainNode) error {
 	nid, err := tn.NodeID(ctx)
 	if err != nil {
@@ -224,9 +250,20 @@ func (tn *ChainNode) SetTestConfig(ctx context.Context) error {
 	// Enable public RPC
 	rpc["laddr"] = "tcp://0.0.0.0:26657"
 
+	cfg := tn.Chain.Config()
+	if cfg.EnablePprof {
+		// Serve pprof handlers for profiling
+		rpc["pprof_laddr"] = "0.0.0.0:6060"
+	}
+
 	c["rpc"] = rpc
 
-	return configutil.ModifyTomlConfigFile(
+	if tn.Validator && cfg.RemoteSigner != nil {
+		// Sign through the remote signer connected to the priv-validator socket
+		c["priv_validator_laddr"] = privValLaddr
+	}
+
+	if err := configutil.ModifyTomlConfigFile(
 		ctx,
 		tn.logger(),
 		tn.DockerClient,
@@ -234,9 +271,51 @@ func (tn *ChainNode) SetTestConfig(ctx context.Context) error {
 		tn.VolumeName,
 		"config/config.toml",
 		c,
+	); err != nil {
+		return err
+	}
+
+	a := testAppToml(cfg, tn.Validator)
+	if len(a) == 0 {
+		return nil
+	}
+
+	return configutil.ModifyTomlConfigFile(
+		ctx,
+		tn.logger(),
+		tn.DockerClient,
+		tn.TestName,
+		tn.VolumeName,
+		"config/app.toml",
+		a,
 	)
 }
 
+// testAppToml returns the app.toml values that SetTestConfig sets for a validator, or for a full node if validator is false.
+func testAppToml(cfg ibc.ChainConfig, validator bool) configutil.Toml {
+	a := make(configutil.Toml)
+
+	if cfg.EnableAPI {
+		// Enable the public REST API
+		api := make(configutil.Toml)
+		api["enable"] = true
+		api["address"] = "tcp://0.0.0.0:1317"
+		api["enabled-unsafe-cors"] = true
+
+		a["api"] = api
+	}
+
+	if cfg.MinGasPrices != "" {
+		a["minimum-gas-prices"] = cfg.MinGasPrices
+	}
+
+	for k, v := range pruningAppToml(cfg.NodePruning(validator)) {
+		a[k] = v
+	}
+
+	return a
+}
+
 // SetPeers modifies the config persistent_peers for a node
 func (tn *ChainNode) SetPeers(ctx context.Context, peers string) error {
 	c := make(configutil.Toml)
@@ -518,13 +597,7 @@ func (tn *ChainNode) RecoverKey(ctx context.Context, keyName, mnemonic string) e
 
 // AddGenesisAccount adds a genesis account for each key
 func (tn *ChainNode) AddGenesisAccount(ctx context.Context, address string, genesisAmount []types.Coin) error {
-	amount := ""
-	for i, coin := range genesisAmount {
-		if i != 0 {
-			amount += ","
-		}
-		amount += fmt.Sprintf("%d%s", coin.Amount.Int64(), coin.Denom)
-	}
+	amount := coinsArg(genesisAmount)
 
 	tn.lock.Lock()
 	defer tn.lock.Unlock()
@@ -538,13 +611,23 @@ func (tn *ChainNode) AddGenesisAccount(ctx context.Context, address string, gene
 	return err
 }
 
+// coinsArg formats coins as a comma separated command line argument, e.g. "100uatom,5stake".
+// Amounts are formatted in full, so that amounts beyond the range of int64 are passed unchanged.
+func coinsArg(coins []types.Coin) string {
+	args := make([]string, len(coins))
+	for i, coin := range coins {
+		args[i] = coin.Amount.String() + coin.Denom
+	}
+	return strings.Join(args, ",")
+}
+
 // Gentx generates the gentx for a given node
 func (tn *ChainNode) Gentx(ctx context.Context, name string, genesisSelfDelegation types.Coin) error {
 	tn.lock.Lock()
 	defer tn.lock.Unlock()
 
 	_, _, err := tn.ExecBin(ctx,
-		"gentx", valKey, fmt.Sprintf("%d%s", genesisSelfDelegation.Amount.Int64(), genesisSelfDelegation.Denom),
+		"gentx", valKey, coinsArg([]types.Coin{genesisSelfDelegation}),
 		"--keyring-backend", keyring.BackendTest,
 		"--chain-id", tn.Chain.Config().ChainID,
 	)
@@ -571,9 +654,85 @@ type CosmosTx struct {
 }
 
 func (tn *ChainNode) SendIBCTransfer(ctx context.Context, channelID string, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (string, error) {
+	return tn.ExecTx(ctx, keyName, ibcTransferCommand(channelID, amount, timeout)...)
+}
+
+// SendIBCTransferWithMemo is like SendIBCTransfer, setting the memo of the transfer's packet data.
+func (tn *ChainNode) SendIBCTransferWithMemo(ctx context.Context, channelID string, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout, memo string) (string, error) {
+	command := ibcTransferCommand(channelID, amount, timeout)
+	if memo != "" {
+		command = append(command, "--memo", memo)
+	}
+	return tn.ExecTx(ctx, keyName, command...)
+}
+
+// SendIBCTransferBatch sends one IBC transfer per amount in a single transaction,
+// waits for 2 blocks if successful, then returns the tx hash.
+func (tn *ChainNode) SendIBCTransferBatch(ctx context.Context, channelID string, keyName string, amounts []ibc.WalletAmount, timeout *ibc.IBCTimeout) (string, error) {
+	if len(amounts) == 0 {
+		return "", fmt.Errorf("no transfer amounts")
+	}
+
+	tn.lock.Lock()
+	defer tn.lock.Unlock()
+
+	// Generate an unsigned tx per transfer, then merge their messages into a single tx.
+	unsigned := make([][]byte, len(amounts))
+	for i, amount := range amounts {
+		command := append(ibcTransferCommand(channelID, amount, timeout), "--generate-only")
+		stdout, _, err := tn.Exec(ctx, tn.TxCommand(keyName, command...), nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate transfer %d: %w", i, err)
+		}
+		unsigned[i] = stdout
+	}
+	merged, err := mergeUnsignedTxs(unsigned)
+	if err != nil {
+		return "", err
+	}
+
+	const (
+		unsignedFile = "ibc-transfer-batch-unsigned.json"
+		signedFile   = "ibc-transfer-batch-signed.json"
+	)
+	fw := dockerutil.NewFileWriter(tn.logger(), tn.DockerClient, tn.TestName)
+	if err := fw.WriteFile(ctx, tn.VolumeName, unsignedFile, merged); err != nil {
+		return "", fmt.Errorf("failed to write unsigned tx file: %w", err)
+	}
+
+	if _, _, err := tn.Exec(ctx, tn.NodeCommand(
+		"tx", "sign", filepath.Join(tn.HomeDir(), unsignedFile),
+		"--from", keyName,
+		"--keyring-backend", keyring.BackendTest,
+		"--output-document", filepath.Join(tn.HomeDir(), signedFile),
+	), nil); err != nil {
+		return "", fmt.Errorf("failed to sign batch tx: %w", err)
+	}
+
+	stdout, _, err := tn.Exec(ctx, tn.NodeCommand(
+		"tx", "broadcast", filepath.Join(tn.HomeDir(), signedFile),
+		"--output", "json",
+	), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to broadcast batch tx: %w", err)
+	}
+	output := CosmosTx{}
+	if err := json.Unmarshal(stdout, &output); err != nil {
+		return "", err
+	}
+	if output.Code != 0 {
+		return output.TxHash, fmt.Errorf("transaction failed with code %d: %s", output.Code, output.RawLog)
+	}
+	if err := test.WaitForBlocks(ctx, 2, tn); err != nil {
+		return "", err
+	}
+	return output.TxHash, nil
+}
+
+func ibcTransferCommand(channelID string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) []string {
 	command := []string{
 		"ibc-transfer", "transfer", "transfer", channelID,
-		amount.Address, fmt.Sprintf("%d%s", amount.Amount, amount.Denom),
+		amount.Address, fmt.Sprintf("%s%s", amount.Amount, amount.Denom),
 	}
 	if timeout != nil {
 		if timeout.NanoSeconds > 0 {
@@ -582,13 +741,94 @@ func (tn *ChainNode) SendIBCTransfer(ctx context.Context, channelID string, keyN
 			command = append(command, "--packet-timeout-height", fmt.Sprintf("0-%d", timeout.Height))
 		}
 	}
-	return tn.ExecTx(ctx, keyName, command...)
+	return command
+}
+
+// mergeUnsignedTxs merges the JSON encoded unsigned txs, as output by --generate-only,
+// into a single unsigned tx containing every message.
+// Gas limits and fees are summed so the merged tx pays for all of its messages.
+func mergeUnsignedTxs(txs [][]byte) ([]byte, error) {
+	type coin struct {
+		Denom  string `json:"denom"`
+		Amount string `json:"amount"`
+	}
+
+	var merged map[string]any
+	var messages []any
+	gasLimit := uint64(0)
+	fees := map[string]*big.Int{}
+	var feeDenoms []string
+
+	for i, bz := range txs {
+		var tx struct {
+			Body struct {
+				Messages []any `json:"messages"`
+			} `json:"body"`
+			AuthInfo struct {
+				Fee struct {
+					Amount   []coin `json:"amount"`
+					GasLimit string `json:"gas_limit"`
+				} `json:"fee"`
+			} `json:"auth_info"`
+		}
+		if err := json.Unmarshal(bz, &tx); err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+		if i == 0 {
+			if err := json.Unmarshal(bz, &merged); err != nil {
+				return nil, fmt.Errorf("tx %d: %w", i, err)
+			}
+		}
+
+		messages = append(messages, tx.Body.Messages...)
+
+		gas, err := strconv.ParseUint(tx.AuthInfo.Fee.GasLimit, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("tx %d: invalid gas limit %q: %w", i, tx.AuthInfo.Fee.GasLimit, err)
+		}
+		gasLimit += gas
+
+		for _, c := range tx.AuthInfo.Fee.Amount {
+			amt, ok := new(big.Int).SetString(c.Amount, 10)
+			if !ok {
+				return nil, fmt.Errorf("tx %d: invalid fee amount %q", i, c.Amount)
+			}
+			if fees[c.Denom] == nil {
+				fees[c.Denom] = new(big.Int)
+				feeDenoms = append(feeDenoms, c.Denom)
+			}
+			fees[c.Denom].Add(fees[c.Denom], amt)
+		}
+	}
+
+	feeAmount := make([]coin, len(feeDenoms))
+	for i, denom := range feeDenoms {
+		feeAmount[i] = coin{Denom: denom, Amount: fees[denom].String()}
+	}
+
+	body, ok := merged["body"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("tx 0: missing body")
+	}
+	body["messages"] = messages
+	authInfo, ok := merged["auth_info"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("tx 0: missing auth_info")
+	}
+	fee, ok := authInfo["fee"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("tx 0: missing fee")
+	}
+	fee["amount"] = feeAmount
+	fee["gas_limit"] = strconv.FormatUint(gasLimit, 10)
+
+	return json.Marshal(merged)
 }
 
 func (tn *ChainNode) SendFunds(ctx context.Context, keyName string, amount ibc.WalletAmount) error {
 	_, err := tn.ExecTx(ctx,
 		keyName, "bank", "send", keyName,
-		amount.Address, fmt.Sprintf("%d%s", amount.Amount, amount.Denom),
+		amount.Address, fmt.Sprintf("%s%s", amount.Amount, amount.Denom),
 	)
 	return err
 }
@@ -775,37 +1015,75 @@ func (tn *ChainNode) UnsafeResetAll(ctx context.Context) error {
 	return err
 }
 
-func (tn *ChainNode) CreateNodeContainer(ctx context.Context) error {
+// startCmd returns the command that starts the node.
+// Without a host mount, the node runs on a copy of its home directory, made by a shell;
+// every argument of the shell command line is quoted.
+func (tn *ChainNode) startCmd() []string {
 	chainCfg := tn.Chain.Config()
-	cmd := []string{chainCfg.Bin, "start", "--home", tn.HomeDir(), "--x-crisis-skip-assert-invariants"}
-	if chainCfg.NoHostMount {
-		cmd = []string{"sh", "-c", fmt.Sprintf("cp -r %s %s_nomnt && %s start --home %s_nomnt --x-crisis-skip-assert-invariants", tn.HomeDir(), tn.HomeDir(), chainCfg.Bin, tn.HomeDir())}
+	startArgs := chainCfg.AdditionalStartArgs
+	if chainCfg.LogLevel != "" {
+		startArgs = append([]string{"--log_level", chainCfg.LogLevel}, startArgs...)
+	}
+	if !chainCfg.NoHostMount {
+		cmd := []string{chainCfg.Bin, "start", "--home", tn.HomeDir(), "--x-crisis-skip-assert-invariants"}
+		return append(cmd, startArgs...)
+	}
+
+	home := tn.HomeDir() + "_nomnt"
+	cp := shellJoin([]string{"cp", "-r", tn.HomeDir(), home})
+	start := shellJoin(append([]string{chainCfg.Bin, "start", "--home", home, "--x-crisis-skip-assert-invariants"}, startArgs...))
+	return []string{"sh", "-c", cp + " && " + start}
+}
+
+// shellJoin returns args as a sh command line, with each argument single quoted.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
 	}
+	return strings.Join(quoted, " ")
+}
+
+func (tn *ChainNode) CreateNodeContainer(ctx context.Context) error {
+	chainCfg := tn.Chain.Config()
+	start := chainCfg.StartCommand(tn.startCmd())
 	imageRef := tn.Image.Ref()
 	tn.logger().
 		Info("Running command",
-			zap.String("command", strings.Join(cmd, " ")),
+			zap.String("command", strings.Join(start.Cmd, " ")),
+			zap.Strings("env", start.Env),
 			zap.String("container", tn.Name()),
 			zap.String("image", imageRef),
 		)
+	testreporter.TrackNodeCommand(ctx, tn.Name(), start.Cmd, start.Env)
 
+	platform, err := dockerutil.ParsePlatform(tn.Image.Platform)
+	if err != nil {
+		return err
+	}
+	publishAll, portBindings, err := dockerutil.PortPublishing(tn.Chain.Config().HostPorts).ReserveHostConfig(tn.Name(), sentryPorts)
+	if err != nil {
+		return err
+	}
 	cc, err := tn.DockerClient.ContainerCreate(
 		ctx,
 		&container.Config{
 			Image: imageRef,
 
 			Entrypoint: []string{},
-			Cmd:        cmd,
+			Cmd:        start.Cmd,
+			Env:        start.Env,
 
 			Hostname: tn.HostName(),
 
-			Labels: map[string]string{dockerutil.CleanupLabel: tn.TestName},
+			Labels: dockerutil.Labels(tn.TestName),
 
 			ExposedPorts: sentryPorts,
 		},
 		&container.HostConfig{
 			Binds:           tn.Bind(),
-			PublishAllPorts: true,
+			PublishAllPorts: publishAll,
+			PortBindings:    portBindings,
 			AutoRemove:      false,
 			DNS:             []string{},
 		},
@@ -814,10 +1092,11 @@ func (tn *ChainNode) CreateNodeContainer(ctx context.Context) error {
 				tn.NetworkID: {},
 			},
 		},
-		nil,
+		platform,
 		tn.Name(),
 	)
 	if err != nil {
+		dockerutil.ReleaseHostPorts(tn.Name())
 		return err
 	}
 	tn.containerID = cc.ID
@@ -829,14 +1108,20 @@ func (tn *ChainNode) StartContainer(ctx context.Context) error {
 		return err
 	}
 
-	c, err := tn.DockerClient.ContainerInspect(ctx, tn.containerID)
+	// Set the host ports once since they will not change after the container has started.
+	publishing := dockerutil.PortPublishing(tn.Chain.Config().HostPorts)
+	hostPorts, err := dockerutil.ResolveHostPorts(ctx, tn.DockerClient, tn.containerID, tn.HostName(), publishing, rpcPort, grpcPort, pprofPort, apiPort)
 	if err != nil {
 		return err
 	}
+	tn.hostRPCPort, tn.hostGRPCPort, tn.hostPprofPort, tn.hostAPIPort = hostPorts[0], hostPorts[1], hostPorts[2], hostPorts[3]
 
-	// Set the host ports once since they will not change after the container has started.
-	tn.hostRPCPort = dockerutil.GetHostPort(c, rpcPort)
-	tn.hostGRPCPort = dockerutil.GetHostPort(c, grpcPort)
+	if tn.logWatcher != nil {
+		tn.logWatcher.Watch(tn.containerID, tn.Name())
+	}
+	if tn.supervisor != nil {
+		tn.supervisor.Watch(tn.containerID, tn.Name())
+	}
 
 	tn.logger().Info("Cosmos chain node started", zap.String("container", tn.Name()), zap.String("rpc_port", tn.hostRPCPort))
 
@@ -860,9 +1145,10 @@ func (tn *ChainNode) StartContainer(ctx context.Context) error {
 	}, retry.Context(ctx), retry.Attempts(40), retry.Delay(3*time.Second), retry.DelayType(retry.FixedDelay))
 }
 
+// StopContainer gracefully stops the node container,
+// returning an error if the node did not exit cleanly, which may leave its database corrupted.
 func (tn *ChainNode) StopContainer(ctx context.Context) error {
-	timeout := 30 * time.Second
-	return tn.DockerClient.ContainerStop(ctx, tn.containerID, &timeout)
+	return dockerutil.StopContainer(ctx, tn.DockerClient, tn.containerID)
 }
 
 func (tn *ChainNode) RemoveContainer(ctx context.Context) error {
@@ -873,6 +1159,7 @@ func (tn *ChainNode) RemoveContainer(ctx context.Context) error {
 	if err != nil && !errdefs.IsNotFound(err) {
 		return fmt.Errorf("remove container %s: %w", tn.Name(), err)
 	}
+	dockerutil.ReleaseHostPorts(tn.Name())
 	return nil
 }
 
@@ -883,7 +1170,10 @@ func (tn *ChainNode) InitValidatorGenTx(
 	genesisAmounts []types.Coin,
 	genesisSelfDelegation types.Coin,
 ) error {
-	if err := tn.CreateKey(ctx, valKey); err != nil {
+	keysDone := testreporter.StartPhase(ctx, testreporter.PhaseKeyDerivation, tn.Name())
+	err := tn.CreateKey(ctx, valKey)
+	keysDone()
+	if err != nil {
 		return err
 	}
 	bech32, err := tn.AccountKeyBech32(ctx, valKey)
@@ -992,10 +1282,12 @@ func (nodes ChainNodes) logger() *zap.Logger {
 }
 
 func (tn *ChainNode) Exec(ctx context.Context, cmd []string, env []string) ([]byte, []byte, error) {
-	job := dockerutil.NewImage(tn.logger(), tn.DockerClient, tn.NetworkID, tn.TestName, tn.Image.Repository, tn.Image.Version)
+	transcript.RecordChainExec(ctx, tn.Chain.Config().ChainID, tn.Name(), cmd, env)
+	job := dockerutil.NewImage(tn.logger(), tn.DockerClient, tn.NetworkID, tn.TestName, tn.Image.Repository, tn.Image.Tag())
 	opts := dockerutil.ContainerOptions{
-		Env:   env,
-		Binds: tn.Bind(),
+		Env:      env,
+		Binds:    tn.Bind(),
+		Platform: tn.Image.Platform,
 	}
 	res := job.Run(ctx, cmd, opts)
 	return res.Stdout, res.Stderr, res.Err
@@ -1045,7 +1337,7 @@ func (tn *ChainNode) SendICABankTransfer(ctx context.Context, connectionID, from
 		"amount": []map[string]any{
 			{
 				"denom":  amount.Denom,
-				"amount": amount.Amount,
+				"amount": amount.Amount.String(),
 			},
 		},
 	})
@@ -0,0 +1,153 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/configutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMergeUnsignedTxs(t *testing.T) {
+	t.Parallel()
+
+	unsignedTx := func(receiver, amount string) []byte {
+		return []byte(`{"body":{"messages":[{"@type":"/ibc.applications.transfer.v1.MsgTransfer","receiver":"` + receiver + `"}],"memo":""},` +
+			`"auth_info":{"signer_infos":[],"fee":{"amount":[{"denom":"uatom","amount":"` + amount + `"}],"gas_limit":"200000","payer":"","granter":""}},` +
+			`"signatures":[]}`)
+	}
+
+	merged, err := mergeUnsignedTxs([][]byte{
+		unsignedTx("a", "100"),
+		unsignedTx("b", "250"),
+	})
+	require.NoError(t, err)
+
+	var tx map[string]any
+	require.NoError(t, json.Unmarshal(merged, &tx))
+
+	body := tx["body"].(map[string]any)
+	require.Equal(t, "", body["memo"])
+	messages := body["messages"].([]any)
+	require.Len(t, messages, 2)
+	require.Equal(t, "a", messages[0].(map[string]any)["receiver"])
+	require.Equal(t, "b", messages[1].(map[string]any)["receiver"])
+
+	fee := tx["auth_info"].(map[string]any)["fee"].(map[string]any)
+	require.Equal(t, "400000", fee["gas_limit"])
+	require.Equal(t, []any{map[string]any{"denom": "uatom", "amount": "350"}}, fee["amount"])
+
+	_, err = mergeUnsignedTxs([][]byte{[]byte(`{"body":{},"auth_info":{"fee":{"gas_limit":"x"}}}`)})
+	require.ErrorContains(t, err, "invalid gas limit")
+}
+
+func TestCoinsArg(t *testing.T) {
+	t.Parallel()
+
+	huge, ok := math.NewIntFromString("100000000000000000000000") // Beyond the range of int64.
+	require.True(t, ok)
+
+	require.Equal(t, "", coinsArg(nil))
+	require.Equal(t, "100uatom", coinsArg([]types.Coin{{Denom: "uatom", Amount: math.NewInt(100)}}))
+	require.Equal(t, "100000000000000000000000uatom,5stake", coinsArg([]types.Coin{
+		{Denom: "uatom", Amount: huge},
+		{Denom: "stake", Amount: math.NewInt(5)},
+	}))
+}
+
+func TestChainNode_StartCmd(t *testing.T) {
+	t.Parallel()
+
+	cfg := ibc.ChainConfig{
+		Name:                "gaia",
+		Bin:                 "gaiad",
+		AdditionalStartArgs: []string{"--moniker", "it's"},
+	}
+	tn := &ChainNode{Chain: mock.NewChain(cfg)}
+	require.Equal(t, []string{
+		"gaiad", "start", "--home", "/var/cosmos-chain/gaia", "--x-crisis-skip-assert-invariants",
+		"--moniker", "it's",
+	}, tn.startCmd())
+
+	cfg.NoHostMount = true
+	tn = &ChainNode{Chain: mock.NewChain(cfg)}
+	require.Equal(t, []string{
+		"sh", "-c",
+		`'cp' '-r' '/var/cosmos-chain/gaia' '/var/cosmos-chain/gaia_nomnt' && ` +
+			`'gaiad' 'start' '--home' '/var/cosmos-chain/gaia_nomnt' '--x-crisis-skip-assert-invariants' '--moniker' 'it'\''s'`,
+	}, tn.startCmd())
+
+	// The log level is quoted like the other arguments, so it cannot run other commands.
+	cfg.LogLevel = "*:info,consensus:debug; echo injected"
+	tn = &ChainNode{Chain: mock.NewChain(cfg)}
+	require.Equal(t, []string{
+		"sh", "-c",
+		`'cp' '-r' '/var/cosmos-chain/gaia' '/var/cosmos-chain/gaia_nomnt' && ` +
+			`'gaiad' 'start' '--home' '/var/cosmos-chain/gaia_nomnt' '--x-crisis-skip-assert-invariants' ` +
+			`'--log_level' '*:info,consensus:debug; echo injected' '--moniker' 'it'\''s'`,
+	}, tn.startCmd())
+}
+
+func TestTestAppToml(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, testAppToml(ibc.ChainConfig{}, true))
+
+	cfg := ibc.ChainConfig{
+		EnableAPI:       true,
+		MinGasPrices:    "0.01uatom",
+		FullNodePruning: &ibc.Pruning{Archive: true},
+	}
+	a := testAppToml(cfg, false)
+	require.Equal(t, configutil.Toml{
+		"enable":              true,
+		"address":             "tcp://0.0.0.0:1317",
+		"enabled-unsafe-cors": true,
+	}, a["api"])
+	require.Equal(t, "0.01uatom", a["minimum-gas-prices"])
+	require.Equal(t, "nothing", a["pruning"])
+
+	// Validators do not take the full node pruning.
+	a = testAppToml(cfg, true)
+	require.Contains(t, a, "api")
+	require.NotContains(t, a, "pruning")
+}
+
+func TestCosmosChain_GetHostAPIAddress(t *testing.T) {
+	t.Parallel()
+
+	c := NewCosmosChain(t.Name(), ibc.ChainConfig{ChainID: "gaia-1", Denom: "uatom"}, 1, 0, zap.NewNop())
+	c.Validators = ChainNodes{{}}
+	require.Empty(t, c.GetHostAPIAddress())
+
+	c.Validators[0].hostAPIPort = "127.0.0.1:49170"
+	require.Equal(t, "http://127.0.0.1:49170", c.GetHostAPIAddress())
+}
+
+func TestCosmosChain_ProfileEndpoints(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cfg := ibc.ChainConfig{ChainID: "gaia-1", Denom: "uatom"}
+	c := NewCosmosChain(t.Name(), cfg, 1, 0, zap.NewNop())
+	c.Validators = ChainNodes{{Chain: c, TestName: t.Name(), Validator: true, hostPprofPort: "127.0.0.1:49171"}}
+	endpoints, err := c.ProfileEndpoints(ctx)
+	require.NoError(t, err)
+	require.Empty(t, endpoints)
+
+	cfg.EnablePprof = true
+	c.cfg = cfg
+	endpoints, err = c.ProfileEndpoints(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []ibc.ProfileEndpoint{{
+		Container:   c.Validators[0].Name(),
+		Kind:        ibc.ProfileKindPprof,
+		HostAddress: "127.0.0.1:49171",
+	}}, endpoints)
+}
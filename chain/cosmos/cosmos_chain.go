@@ -4,19 +4,18 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
+	"cosmossdk.io/math"
 	"github.com/avast/retry-go/v4"
 	"github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
 	authTx "github.com/cosmos/cosmos-sdk/x/auth/tx"
 	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	chanTypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
-	dockertypes "github.com/docker/docker/api/types"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/strangelove-ventures/ibctest/v6/chain/internal/tendermint"
@@ -25,10 +24,11 @@ import (
 	"github.com/strangelove-ventures/ibctest/v6/internal/configutil"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
 	"github.com/strangelove-ventures/ibctest/v6/test"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/strangelove-ventures/ibctest/v6/transcript"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
 // CosmosChain is a local docker testnet for a Cosmos SDK chain.
@@ -43,7 +43,22 @@ type CosmosChain struct {
 
 	log *zap.Logger
 
+	// Set during Initialize; watches all node containers for fatal log lines.
+	logWatcher *dockerutil.LogWatcher
+
+	// Set during Initialize; restarts crashed node containers.
+	supervisor *dockerutil.Supervisor
+
+	// Guards cfg, whose gas prices and binary are set after the chain is created.
+	cfgMu sync.RWMutex
+
 	findTxMu sync.Mutex
+
+	// Serializes the transactions of each key; see WithKeySequence.
+	keyLocks keyLocks
+
+	// Added to genesis at Start; see AddGenesisFeeAllowances.
+	genesisFeeAllowances []ibc.GenesisFeeAllowance
 }
 
 func NewCosmosHeighlinerChainConfig(name string,
@@ -96,6 +111,13 @@ func (c *CosmosChain) Nodes() ChainNodes {
 func (c *CosmosChain) AddFullNodes(ctx context.Context, configFileOverrides map[string]any, inc int) error {
 	// Get peer string for existing nodes
 	peers := c.Nodes().PeerString(ctx)
+	var addrs map[string]string
+	if c.cfg.P2P != nil {
+		var err error
+		if addrs, err = c.Nodes().peerAddrs(ctx); err != nil {
+			return err
+		}
+	}
 
 	// Get genesis.json
 	genbz, err := c.Validators[0].genesisFileContent(ctx)
@@ -117,7 +139,7 @@ func (c *CosmosChain) AddFullNodes(ctx context.Context, configFileOverrides map[
 			if err := fn.InitFullNodeFiles(ctx); err != nil {
 				return err
 			}
-			if err := fn.SetPeers(ctx, peers); err != nil {
+			if err := c.setNodePeers(ctx, fn, peers, addrs); err != nil {
 				return err
 			}
 			if err := fn.overwriteGenesisFile(ctx, genbz); err != nil {
@@ -151,12 +173,41 @@ func (c *CosmosChain) AddFullNodes(ctx context.Context, configFileOverrides map[
 
 // Implements Chain interface
 func (c *CosmosChain) Config() ibc.ChainConfig {
+	c.cfgMu.RLock()
+	defer c.cfgMu.RUnlock()
 	return c.cfg
 }
 
+// Decimals returns the number of decimals of the display unit of the chain's denom, e.g. 6 for atom.
+// Implements ibc.DisplayUnits.
+func (c *CosmosChain) Decimals() int64 {
+	return c.Config().Decimals()
+}
+
+// DisplayDenom returns the display unit of the chain's denom, from its CoinDisplayDenom or its Denom.
+// Implements ibc.DisplayUnits.
+func (c *CosmosChain) DisplayDenom() string {
+	return c.Config().DisplayDenom()
+}
+
+// SupportsFeature reports whether the chain supports f.
+// Cosmos chains support transfers and their timeouts. Other features are supported only if the chain config
+// lists them in AdditionalFeatures, or, for the features of ibc-go modules, if its IBCGoVersion includes them.
+// Implements ibc.ChainCapabilities.
+func (c *CosmosChain) SupportsFeature(f ibc.ChainFeature) bool {
+	switch f {
+	case ibc.FeatureTransfer, ibc.FeatureHeightTimeout, ibc.FeatureTimestampTimeout:
+		return true
+	}
+	if c.cfg.HasAdditionalFeature(f) {
+		return true
+	}
+	return ibcGoSupportsFeature(c.cfg.IBCGoVersion, f)
+}
+
 // Implements Chain interface
 func (c *CosmosChain) Initialize(ctx context.Context, testName string, cli *client.Client, networkID string) error {
-	return c.initializeChainNodes(ctx, testName, cli, networkID)
+	return dockerutil.Diagnose(c.initializeChainNodes(ctx, testName, cli, networkID))
 }
 
 func (c *CosmosChain) getFullNode() *ChainNode {
@@ -185,6 +236,12 @@ func (c *CosmosChain) GetGRPCAddress() string {
 	return fmt.Sprintf("%s:9090", c.getFullNode().HostName())
 }
 
+// GetAPIAddress returns the address of the REST API server within the docker network.
+// The server only listens if the chain is configured with EnableAPI.
+func (c *CosmosChain) GetAPIAddress() string {
+	return fmt.Sprintf("http://%s:1317", c.getFullNode().HostName())
+}
+
 // GetHostRPCAddress returns the address of the RPC server accessible by the host.
 // This will not return a valid address until the chain has been started.
 func (c *CosmosChain) GetHostRPCAddress() string {
@@ -197,6 +254,18 @@ func (c *CosmosChain) GetHostGRPCAddress() string {
 	return c.getFullNode().hostGRPCPort
 }
 
+// GetHostAPIAddress returns the address of the REST API server accessible by the host,
+// or an empty string if the API port is not published to the host.
+// This will not return a valid address until the chain has been started,
+// and the server only listens if the chain is configured with EnableAPI.
+func (c *CosmosChain) GetHostAPIAddress() string {
+	port := c.getFullNode().hostAPIPort
+	if port == "" {
+		return ""
+	}
+	return "http://" + port
+}
+
 // HomeDir implements ibc.Chain.
 func (c *CosmosChain) HomeDir() string {
 	return c.getFullNode().HomeDir()
@@ -224,12 +293,32 @@ func (c *CosmosChain) GetAddress(ctx context.Context, keyName string) ([]byte, e
 
 // Implements Chain interface
 func (c *CosmosChain) SendFunds(ctx context.Context, keyName string, amount ibc.WalletAmount) error {
-	return c.getFullNode().SendFunds(ctx, keyName, amount)
+	return c.WithKeySequence(ctx, keyName, func() error {
+		return c.getFullNode().SendFunds(ctx, keyName, amount)
+	})
 }
 
 // Implements Chain interface
-func (c *CosmosChain) SendIBCTransfer(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (tx ibc.Tx, _ error) {
-	txHash, err := c.getFullNode().SendIBCTransfer(ctx, channelID, keyName, amount, timeout)
+func (c *CosmosChain) SendIBCTransfer(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (ibc.Tx, error) {
+	return c.SendIBCTransferWithMemo(ctx, channelID, keyName, amount, timeout, "")
+}
+
+// SendIBCTransferWithMemo is like SendIBCTransfer, setting the memo of the transfer's packet data.
+// Implements ibc.MemoIBCTransferer.
+func (c *CosmosChain) SendIBCTransferWithMemo(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout, memo string) (tx ibc.Tx, _ error) {
+	ctx = transcript.RecordTransfer(ctx, c.Config().ChainID, transcript.Transfer{
+		ChannelID: channelID,
+		KeyName:   keyName,
+		Amounts:   []ibc.WalletAmount{amount},
+		Timeout:   timeout,
+		Memo:      memo,
+	})
+
+	var txHash string
+	err := c.WithKeySequence(ctx, keyName, func() (err error) {
+		txHash, err = c.getFullNode().SendIBCTransferWithMemo(ctx, channelID, keyName, amount, timeout, memo)
+		return err
+	})
 	if err != nil {
 		return tx, fmt.Errorf("send ibc transfer: %w", err)
 	}
@@ -242,39 +331,64 @@ func (c *CosmosChain) SendIBCTransfer(ctx context.Context, channelID, keyName st
 	// In cosmos, user is charged for entire gas requested, not the actual gas used.
 	tx.GasSpent = txResp.GasWanted
 
-	const evType = "send_packet"
-	events := txResp.Events
+	for _, e := range txResp.Events {
+		pe, ok, err := parsePacketEvent(tx.Height, e)
+		if err != nil {
+			return tx, fmt.Errorf("invalid send_packet event: %w", err)
+		}
+		if ok && pe.Type == PacketEventSend {
+			tx.Packet = pe.Packet
+			return tx, nil
+		}
+	}
+	return tx, fmt.Errorf("no send_packet event in transaction %s", txHash)
+}
 
-	var (
-		seq, _           = tendermint.AttributeValue(events, evType, "packet_sequence")
-		srcPort, _       = tendermint.AttributeValue(events, evType, "packet_src_port")
-		srcChan, _       = tendermint.AttributeValue(events, evType, "packet_src_channel")
-		dstPort, _       = tendermint.AttributeValue(events, evType, "packet_dst_port")
-		dstChan, _       = tendermint.AttributeValue(events, evType, "packet_dst_channel")
-		timeoutHeight, _ = tendermint.AttributeValue(events, evType, "packet_timeout_height")
-		timeoutTs, _     = tendermint.AttributeValue(events, evType, "packet_timeout_timestamp")
-		data, _          = tendermint.AttributeValue(events, evType, "packet_data")
-	)
-	tx.Packet.SourcePort = srcPort
-	tx.Packet.SourceChannel = srcChan
-	tx.Packet.DestPort = dstPort
-	tx.Packet.DestChannel = dstChan
-	tx.Packet.TimeoutHeight = timeoutHeight
-	tx.Packet.Data = []byte(data)
-
-	seqNum, err := strconv.Atoi(seq)
+// SendIBCTransferBatch sends one IBC transfer per amount in a single transaction.
+// Implements ibc.BatchIBCTransferer.
+func (c *CosmosChain) SendIBCTransferBatch(ctx context.Context, channelID, keyName string, amounts []ibc.WalletAmount, timeout *ibc.IBCTimeout) ([]ibc.Tx, error) {
+	ctx = transcript.RecordTransfer(ctx, c.Config().ChainID, transcript.Transfer{
+		ChannelID: channelID,
+		KeyName:   keyName,
+		Amounts:   amounts,
+		Batch:     true,
+		Timeout:   timeout,
+	})
+
+	var txHash string
+	err := c.WithKeySequence(ctx, keyName, func() (err error) {
+		txHash, err = c.getFullNode().SendIBCTransferBatch(ctx, channelID, keyName, amounts, timeout)
+		return err
+	})
 	if err != nil {
-		return tx, fmt.Errorf("invalid packet sequence from events %s: %w", seq, err)
+		return nil, fmt.Errorf("send ibc transfer batch: %w", err)
 	}
-	tx.Packet.Sequence = uint64(seqNum)
-
-	timeoutNano, err := strconv.ParseUint(timeoutTs, 10, 64)
+	txResp, err := c.getTransaction(txHash)
 	if err != nil {
-		return tx, fmt.Errorf("invalid packet timestamp timeout %s: %w", timeoutTs, err)
+		return nil, fmt.Errorf("failed to get transaction %s: %w", txHash, err)
 	}
-	tx.Packet.TimeoutTimestamp = ibc.Nanoseconds(timeoutNano)
 
-	return tx, nil
+	// Messages are executed in order, so send_packet events are emitted in the same order as amounts.
+	var txs []ibc.Tx
+	for _, e := range txResp.Events {
+		pe, ok, err := parsePacketEvent(uint64(txResp.Height), e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid send_packet event: %w", err)
+		}
+		if !ok || pe.Type != PacketEventSend {
+			continue
+		}
+		txs = append(txs, ibc.Tx{
+			Height:   uint64(txResp.Height),
+			TxHash:   txHash,
+			GasSpent: txResp.GasWanted,
+			Packet:   pe.Packet,
+		})
+	}
+	if len(txs) != len(amounts) {
+		return nil, fmt.Errorf("found %d send_packet events in transaction %s, expected %d", len(txs), txHash, len(amounts))
+	}
+	return txs, nil
 }
 
 // QueryProposal returns the state and details of a governance proposal.
@@ -343,12 +457,77 @@ func (c *CosmosChain) ExportState(ctx context.Context, height int64) (string, er
 
 // GetBalance fetches the current balance for a specific account address and denom.
 // Implements Chain interface
-func (c *CosmosChain) GetBalance(ctx context.Context, address string, denom string) (int64, error) {
+func (c *CosmosChain) GetBalance(ctx context.Context, address string, denom string) (math.Int, error) {
+	return c.queryBalance(ctx, address, denom)
+}
+
+// BalanceAtHeight fetches the balance for a specific account address and denom
+// in the state committed at height, using a historical gRPC query.
+// The height must not have been pruned by the nodes.
+// Implements ibc.BalanceAtHeightQuerier.
+func (c *CosmosChain) BalanceAtHeight(ctx context.Context, address, denom string, height uint64) (math.Int, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatUint(height, 10))
+	return c.queryBalance(ctx, address, denom)
+}
+
+// ResolvedConfig returns the effective configuration of the started chain,
+// including the digests of the images run by its nodes.
+// Implements ibc.ConfigResolver.
+func (c *CosmosChain) ResolvedConfig(ctx context.Context) (ibc.ResolvedChainConfig, error) {
+	cfg := c.Config()
+	resolved := ibc.ResolvedChainConfig{
+		Type:            cfg.Type,
+		Name:            cfg.Name,
+		ChainID:         cfg.ChainID,
+		Denom:           cfg.Denom,
+		Bech32Prefix:    cfg.Bech32Prefix,
+		HostRPCAddress:  c.GetHostRPCAddress(),
+		HostGRPCAddress: c.GetHostGRPCAddress(),
+	}
+	seen := make(map[string]bool)
+	for _, n := range c.Nodes() {
+		ref := n.Image.Ref()
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		digest, err := dockerutil.ImageDigest(ctx, n.DockerClient, ref)
+		if err != nil {
+			return ibc.ResolvedChainConfig{}, err
+		}
+		resolved.Images = append(resolved.Images, ibc.ResolvedImage{DockerImage: n.Image, Digest: digest})
+	}
+	return resolved, nil
+}
+
+// ProfileEndpoints returns the pprof endpoints of the started nodes,
+// which only serve pprof handlers if the chain is configured with EnablePprof.
+// Implements ibc.Profiler.
+func (c *CosmosChain) ProfileEndpoints(ctx context.Context) ([]ibc.ProfileEndpoint, error) {
+	if !c.Config().EnablePprof {
+		return nil, nil
+	}
+	var endpoints []ibc.ProfileEndpoint
+	for _, n := range c.Nodes() {
+		if n.hostPprofPort == "" {
+			continue
+		}
+		endpoints = append(endpoints, ibc.ProfileEndpoint{
+			Container:   n.Name(),
+			Kind:        ibc.ProfileKindPprof,
+			HostAddress: n.hostPprofPort,
+		})
+	}
+	return endpoints, nil
+}
+
+// queryBalance queries the bank balance over gRPC,
+// in the state at the height set in ctx's outgoing metadata, if any, or else the latest state.
+func (c *CosmosChain) queryBalance(ctx context.Context, address string, denom string) (math.Int, error) {
 	params := &bankTypes.QueryBalanceRequest{Address: address, Denom: denom}
-	grpcAddress := c.getFullNode().hostGRPCPort
-	conn, err := grpc.Dial(grpcAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := c.dialGRPC()
 	if err != nil {
-		return 0, err
+		return math.Int{}, err
 	}
 	defer conn.Close()
 
@@ -356,10 +535,10 @@ func (c *CosmosChain) GetBalance(ctx context.Context, address string, denom stri
 	res, err := queryClient.Balance(ctx, params)
 
 	if err != nil {
-		return 0, err
+		return math.Int{}, err
 	}
 
-	return res.Balance.Amount.Int64(), nil
+	return res.Balance.Amount, nil
 }
 
 func (c *CosmosChain) getTransaction(txHash string) (*types.TxResponse, error) {
@@ -379,41 +558,37 @@ func (c *CosmosChain) getTransaction(txHash string) (*types.TxResponse, error) {
 	return txResp, err
 }
 
-func (c *CosmosChain) GetGasFeesInNativeDenom(gasPaid int64) int64 {
-	gasPrice, _ := strconv.ParseFloat(strings.Replace(c.cfg.GasPrices, c.cfg.Denom, "", 1), 64)
-	fees := float64(gasPaid) * gasPrice
-	return int64(fees)
+func (c *CosmosChain) GetGasFeesInNativeDenom(gasPaid int64) math.Int {
+	gasPrice, err := types.ParseDecCoin(c.Config().GasPrices)
+	if err != nil {
+		return math.ZeroInt()
+	}
+	return gasPrice.Amount.MulInt64(gasPaid).TruncateInt()
 }
 
 func (c *CosmosChain) UpgradeVersion(ctx context.Context, cli *client.Client, version string) {
+	c.cfgMu.Lock()
 	c.cfg.Images[0].Version = version
+	c.cfgMu.Unlock()
 	for _, n := range c.Validators {
 		n.Image.Version = version
 	}
 	for _, n := range c.FullNodes {
 		n.Image.Version = version
 	}
-	c.pullImages(ctx, cli)
+	if err := c.pullImages(ctx, cli); err != nil {
+		c.log.Error("Failed to pull upgrade image", zap.Error(err))
+	}
 }
 
-func (c *CosmosChain) pullImages(ctx context.Context, cli *client.Client) {
+// pullImages pulls the chain's images according to the configured image pull mode.
+func (c *CosmosChain) pullImages(ctx context.Context, cli *client.Client) error {
 	for _, image := range c.Config().Images {
-		rc, err := cli.ImagePull(
-			ctx,
-			image.Repository+":"+image.Version,
-			dockertypes.ImagePullOptions{},
-		)
-		if err != nil {
-			c.log.Error("Failed to pull image",
-				zap.Error(err),
-				zap.String("repository", image.Repository),
-				zap.String("tag", image.Version),
-			)
-		} else {
-			_, _ = io.Copy(io.Discard, rc)
-			_ = rc.Close()
+		if err := dockerutil.EnsureImage(ctx, c.log, cli, image.Ref(), image.Platform); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // NewChainNode constructs a new cosmos chain node with a docker volume.
@@ -430,6 +605,9 @@ func (c *CosmosChain) NewChainNode(
 	tn := &ChainNode{
 		log: c.log,
 
+		logWatcher: c.logWatcher,
+		supervisor: c.supervisor,
+
 		Validator: validator,
 
 		Chain:        c,
@@ -440,11 +618,7 @@ func (c *CosmosChain) NewChainNode(
 	}
 
 	v, err := cli.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
-		Labels: map[string]string{
-			dockerutil.CleanupLabel: testName,
-
-			dockerutil.NodeOwnerLabel: tn.Name(),
-		},
+		Labels: dockerutil.VolumeLabels(testName, tn.Name()),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("creating volume for chain node: %w", err)
@@ -474,9 +648,32 @@ func (c *CosmosChain) initializeChainNodes(
 	networkID string,
 ) error {
 	chainCfg := c.Config()
-	c.pullImages(ctx, cli)
+	if err := c.pullImages(ctx, cli); err != nil {
+		return err
+	}
 	image := chainCfg.Images[0]
 
+	if chainCfg.Bin == "" {
+		bin, err := dockerutil.DetectBinary(ctx, c.log, cli, networkID, testName, image.Repository, image.Tag())
+		if err != nil {
+			return err
+		}
+		c.cfgMu.Lock()
+		c.cfg.Bin = bin
+		c.cfgMu.Unlock()
+	}
+
+	if c.logWatcher == nil {
+		c.logWatcher = dockerutil.NewLogWatcher(c.log, cli)
+	}
+	if c.supervisor == nil {
+		supervisor, err := dockerutil.NewSupervisor(c.log, cli, c.logWatcher)
+		if err != nil {
+			return err
+		}
+		c.supervisor = supervisor
+	}
+
 	newVals := make(ChainNodes, c.numValidators)
 	copy(newVals, c.Validators)
 	newFullNodes := make(ChainNodes, c.numFullNodes)
@@ -540,19 +737,31 @@ type ValidatorWithIntPower struct {
 // Bootstraps the chain and starts it from genesis
 func (c *CosmosChain) Start(testName string, ctx context.Context, additionalGenesisWallets ...ibc.WalletAmount) error {
 	chainCfg := c.Config()
+	if chainCfg.RemoteSigner != nil {
+		if err := chainCfg.RemoteSigner.Validate(); err != nil {
+			return fmt.Errorf("invalid remote signer: %w", err)
+		}
+	}
+	if chainCfg.P2P != nil {
+		if err := chainCfg.P2P.Validate(c.numValidators, c.numFullNodes); err != nil {
+			return fmt.Errorf("invalid p2p topology: %w", err)
+		}
+	}
 
-	genesisAmount := types.Coin{
-		Amount: types.NewInt(10_000_000_000_000),
-		Denom:  chainCfg.Denom,
+	// Validators hold every genesis denom, and self-delegate the staking denom.
+	var genesisAmounts []types.Coin
+	for _, denom := range chainCfg.GenesisDenoms() {
+		genesisAmounts = append(genesisAmounts, types.Coin{
+			Amount: types.NewInt(10_000_000_000_000),
+			Denom:  denom,
+		})
 	}
 
 	genesisSelfDelegation := types.Coin{
 		Amount: types.NewInt(5_000_000_000_000),
-		Denom:  chainCfg.Denom,
+		Denom:  chainCfg.StakingDenom(),
 	}
 
-	genesisAmounts := []types.Coin{genesisAmount}
-
 	configFileOverrides := chainCfg.ConfigFileOverrides
 
 	eg := new(errgroup.Group)
@@ -581,6 +790,10 @@ func (c *CosmosChain) Start(testName string, ctx context.Context, additionalGene
 					return err
 				}
 			}
+			if chainCfg.GenesisFile != "" {
+				// The imported genesis already contains the validator set.
+				return nil
+			}
 			return v.InitValidatorGenTx(ctx, &chainCfg, genesisAmounts, genesisSelfDelegation)
 		})
 	}
@@ -622,39 +835,72 @@ func (c *CosmosChain) Start(testName string, ctx context.Context, additionalGene
 	// for the validators we need to collect the gentxs and the accounts
 	// to the first node's genesis file
 	validator0 := c.Validators[0]
-	for i := 1; i < len(c.Validators); i++ {
-		validatorN := c.Validators[i]
-
-		bech32, err := validatorN.AccountKeyBech32(ctx, valKey)
-		if err != nil {
+	if chainCfg.GenesisFile != "" {
+		// Genesis accounts are added to the imported genesis rather than a generated one.
+		if err := c.importGenesis(ctx, chainCfg); err != nil {
 			return err
 		}
+	} else {
+		for i := 1; i < len(c.Validators); i++ {
+			validatorN := c.Validators[i]
 
-		if err := validator0.AddGenesisAccount(ctx, bech32, genesisAmounts); err != nil {
-			return err
+			bech32, err := validatorN.AccountKeyBech32(ctx, valKey)
+			if err != nil {
+				return err
+			}
+
+			if err := validator0.AddGenesisAccount(ctx, bech32, genesisAmounts); err != nil {
+				return err
+			}
+
+			if err := validatorN.copyGentx(ctx, validator0); err != nil {
+				return err
+			}
 		}
+	}
 
-		if err := validatorN.copyGentx(ctx, validator0); err != nil {
+	// A genesis account may only be added once, so merge the wallet amounts of each address,
+	// such as the faucet's amounts in every genesis denom.
+	var walletAddresses []string
+	walletCoins := make(map[string]types.Coins)
+	for _, wallet := range additionalGenesisWallets {
+		if _, ok := walletCoins[wallet.Address]; !ok {
+			walletAddresses = append(walletAddresses, wallet.Address)
+		}
+		walletCoins[wallet.Address] = walletCoins[wallet.Address].Add(types.Coin{Denom: wallet.Denom, Amount: wallet.Amount})
+	}
+	for _, address := range walletAddresses {
+		if err := validator0.AddGenesisAccount(ctx, address, walletCoins[address]); err != nil {
 			return err
 		}
 	}
 
-	for _, wallet := range additionalGenesisWallets {
-		if err := validator0.AddGenesisAccount(ctx, wallet.Address, []types.Coin{{Denom: wallet.Denom, Amount: types.NewInt(wallet.Amount)}}); err != nil {
+	if chainCfg.GenesisFile == "" {
+		if err := validator0.CollectGentxs(ctx); err != nil {
 			return err
 		}
 	}
 
-	if err := validator0.CollectGentxs(ctx); err != nil {
+	genbz, err := validator0.genesisFileContent(ctx)
+	if err != nil {
 		return err
 	}
 
-	genbz, err := validator0.genesisFileContent(ctx)
+	if chainCfg.GenesisFile == "" {
+		// The default genesis uses "stake" as the bond, mint, and deposit denom.
+		genbz = bytes.ReplaceAll(genbz, []byte(`"stake"`), []byte(fmt.Sprintf(`"%s"`, chainCfg.StakingDenom())))
+	}
+
+	genbz, err = c.modifyGenesisFees(chainCfg, genbz)
 	if err != nil {
 		return err
 	}
 
-	genbz = bytes.ReplaceAll(genbz, []byte(`"stake"`), []byte(fmt.Sprintf(`"%s"`, chainCfg.Denom)))
+	if !chainCfg.GenesisTime.IsZero() {
+		if genbz, err = ModifyGenesisTime(chainCfg.GenesisTime)(chainCfg, genbz); err != nil {
+			return err
+		}
+	}
 
 	if c.cfg.ModifyGenesis != nil {
 		genbz, err = c.cfg.ModifyGenesis(chainCfg, genbz)
@@ -697,14 +943,26 @@ func (c *CosmosChain) Start(testName string, ctx context.Context, additionalGene
 		return err
 	}
 
+	if chainCfg.RemoteSigner != nil {
+		if err := c.startRemoteSigners(ctx, *chainCfg.RemoteSigner); err != nil {
+			return err
+		}
+	}
+
 	peers := chainNodes.PeerString(ctx)
+	var addrs map[string]string
+	if c.cfg.P2P != nil {
+		if addrs, err = chainNodes.peerAddrs(ctx); err != nil {
+			return err
+		}
+	}
 
 	eg, egCtx = errgroup.WithContext(ctx)
 	for _, n := range chainNodes {
 		n := n
 		c.log.Info("Starting container", zap.String("container", n.Name()))
 		eg.Go(func() error {
-			if err := n.SetPeers(egCtx, peers); err != nil {
+			if err := c.setNodePeers(egCtx, n, peers, addrs); err != nil {
 				return err
 			}
 			return n.StartContainer(egCtx)
@@ -714,8 +972,126 @@ func (c *CosmosChain) Start(testName string, ctx context.Context, additionalGene
 		return err
 	}
 
-	// Wait for 5 blocks before considering the chains "started"
-	return test.WaitForBlocks(ctx, 5, c.getFullNode())
+	if !chainCfg.StartBeforeGenesis {
+		if err := c.WaitForGenesis(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Match the gas prices enforced by the nodes before the relayer config is generated from them.
+	if _, err := c.DiscoverGasPrices(ctx); err != nil {
+		return fmt.Errorf("failed to discover gas prices: %w", err)
+	}
+	return nil
+}
+
+// importGenesis places the genesis file at cfg.GenesisFile into the first validator's volume,
+// so that genesis accounts can be added to it before it is shared with every node,
+// and gives each validator its key from cfg.GenesisValidatorKeyFiles.
+func (c *CosmosChain) importGenesis(ctx context.Context, cfg ibc.ChainConfig) error {
+	if len(cfg.GenesisValidatorKeyFiles) > len(c.Validators) {
+		return fmt.Errorf("%d genesis validator key files provided for %d validators", len(cfg.GenesisValidatorKeyFiles), len(c.Validators))
+	}
+
+	genbz, err := os.ReadFile(cfg.GenesisFile)
+	if err != nil {
+		return fmt.Errorf("reading genesis file: %w", err)
+	}
+	if err := c.Validators[0].overwriteGenesisFile(ctx, genbz); err != nil {
+		return err
+	}
+
+	for i, keyFile := range cfg.GenesisValidatorKeyFiles {
+		key, err := os.ReadFile(keyFile)
+		if err != nil {
+			return fmt.Errorf("reading genesis validator key file: %w", err)
+		}
+		if err := c.Validators[i].overwritePrivValidatorKey(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fatalError returns an error if any node of the chain has logged a fatal error,
+// such as a consensus failure or an app hash mismatch.
+func (c *CosmosChain) fatalError() error {
+	if c.logWatcher != nil {
+		if err := c.logWatcher.Err(); err != nil {
+			return err
+		}
+	}
+	if c.supervisor != nil {
+		return c.supervisor.Err()
+	}
+	return nil
+}
+
+// HealthCheck reports the chain as not live once a node has failed, e.g. logged a consensus failure,
+// and as ready once no node is catching up and, with more than one node, every node has peers.
+// Implements ibc.HealthChecker.
+func (c *CosmosChain) HealthCheck(ctx context.Context) (ibc.HealthStatus, error) {
+	if err := c.fatalError(); err != nil {
+		return ibc.HealthStatus{Reason: err.Error()}, nil
+	}
+
+	nodes := c.Nodes()
+	for _, n := range nodes {
+		if n.Client == nil {
+			return ibc.HealthStatus{}, fmt.Errorf("node %s has not started", n.Name())
+		}
+		stat, err := n.Client.Status(ctx)
+		if err != nil {
+			return ibc.HealthStatus{}, fmt.Errorf("failed to get status of node %s: %w", n.Name(), err)
+		}
+		if stat.SyncInfo.CatchingUp {
+			return ibc.HealthStatus{
+				Live:   true,
+				Reason: fmt.Sprintf("node %s is catching up at height %d", n.Name(), stat.SyncInfo.LatestBlockHeight),
+			}, nil
+		}
+		if len(nodes) < 2 {
+			continue
+		}
+		netInfo, err := n.Client.NetInfo(ctx)
+		if err != nil {
+			return ibc.HealthStatus{}, fmt.Errorf("failed to get net info of node %s: %w", n.Name(), err)
+		}
+		if netInfo.NPeers == 0 {
+			return ibc.HealthStatus{Live: true, Reason: fmt.Sprintf("node %s has no peers", n.Name())}, nil
+		}
+	}
+	return ibc.HealthStatus{Live: true, Ready: true}, nil
+}
+
+// WaitForGenesis waits until the chain has produced its first blocks, i.e. until after its genesis time.
+// Start calls it, unless the chain is configured with StartBeforeGenesis.
+func (c *CosmosChain) WaitForGenesis(ctx context.Context) error {
+	if wait := time.Until(c.cfg.GenesisTime); wait > 0 {
+		c.log.Info("Waiting for genesis time",
+			zap.String("chain_id", c.cfg.ChainID),
+			zap.Time("genesis_time", c.cfg.GenesisTime),
+			zap.Duration("wait", wait),
+		)
+	}
+
+	// Wait for 5 blocks before considering the chain "started"
+	defer testreporter.StartPhase(ctx, testreporter.PhaseFirstBlock, c.cfg.ChainID)()
+	if err := test.WaitForBlocks(ctx, 5, c); err != nil {
+		return err
+	}
+
+	if c.supervisor != nil {
+		c.supervisor.WatchHealth(c.supervisorHealthCheck)
+	}
+	return nil
+}
+
+// supervisorHealthCheck adapts HealthCheck to the chain's supervisor.
+func (c *CosmosChain) supervisorHealthCheck(ctx context.Context) (bool, string, error) {
+	status, err := c.HealthCheck(ctx)
+	return status.Live, status.Reason, err
 }
 
 // Height implements ibc.Chain
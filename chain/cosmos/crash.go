@@ -0,0 +1,50 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+)
+
+// CrashableValidators implements ibc.ValidatorCrasher.
+func (c *CosmosChain) CrashableValidators() int {
+	return len(c.Validators)
+}
+
+// KillValidator implements ibc.ValidatorCrasher.
+func (c *CosmosChain) KillValidator(ctx context.Context, i int) error {
+	v, err := c.validator(i)
+	if err != nil {
+		return err
+	}
+	return dockerutil.KillContainer(ctx, v.DockerClient, v.containerID)
+}
+
+// RestartValidator implements ibc.ValidatorCrasher.
+func (c *CosmosChain) RestartValidator(ctx context.Context, i int) error {
+	v, err := c.validator(i)
+	if err != nil {
+		return err
+	}
+	if err := v.StartContainer(ctx); err != nil {
+		return fmt.Errorf("failed to restart validator %s: %w", v.Name(), err)
+	}
+	return nil
+}
+
+// ValidatorHeight implements ibc.ValidatorCrasher.
+func (c *CosmosChain) ValidatorHeight(ctx context.Context, i int) (uint64, error) {
+	v, err := c.validator(i)
+	if err != nil {
+		return 0, err
+	}
+	return v.Height(ctx)
+}
+
+func (c *CosmosChain) validator(i int) (*ChainNode, error) {
+	if i < 0 || i >= len(c.Validators) {
+		return nil, fmt.Errorf("chain %s has no validator %d", c.cfg.ChainID, i)
+	}
+	return c.Validators[i], nil
+}
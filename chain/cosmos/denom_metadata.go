@@ -0,0 +1,112 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"github.com/icza/dyno"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// NewDenomMetadata returns the bank metadata of base, displayed as display with exponent decimals,
+// e.g. NewDenomMetadata("uatom", "atom", 6).
+func NewDenomMetadata(base, display string, exponent uint32) banktypes.Metadata {
+	return banktypes.Metadata{
+		Description: fmt.Sprintf("The %s token", display),
+		DenomUnits: []*banktypes.DenomUnit{
+			{Denom: base, Exponent: 0},
+			{Denom: display, Exponent: exponent},
+		},
+		Base:    base,
+		Display: display,
+		Name:    display,
+		Symbol:  display,
+	}
+}
+
+// ModifyGenesisDenomMetadata returns a ChainConfig.ModifyGenesis function registering the bank metadata of denoms,
+// such as NewDenomMetadata of the chain's Denom, or of ibc/ vouchers expected to be received,
+// so that tests can verify the display denoms seen by wallets and front-ends.
+// Metadata replaces any existing metadata of the same base denom.
+func ModifyGenesisDenomMetadata(metadata ...banktypes.Metadata) func(ibc.ChainConfig, []byte) ([]byte, error) {
+	return func(_ ibc.ChainConfig, genbz []byte) ([]byte, error) {
+		g := make(map[string]interface{})
+		if err := json.Unmarshal(genbz, &g); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal genesis file: %w", err)
+		}
+
+		metadataPath := []interface{}{"app_state", "bank", "denom_metadata"}
+		existing, err := dyno.GetSlice(g, metadataPath...)
+		if err != nil {
+			// The metadata may be null in an exported genesis.
+			existing = nil
+		}
+
+		for _, m := range metadata {
+			if err := m.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid metadata of %s: %w", m.Base, err)
+			}
+			bz, err := json.Marshal(m)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal metadata of %s: %w", m.Base, err)
+			}
+			var entry map[string]interface{}
+			if err := json.Unmarshal(bz, &entry); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal metadata of %s: %w", m.Base, err)
+			}
+
+			replaced := false
+			for i, e := range existing {
+				if em, ok := e.(map[string]interface{}); ok && em["base"] == m.Base {
+					existing[i] = entry
+					replaced = true
+				}
+			}
+			if !replaced {
+				existing = append(existing, entry)
+			}
+		}
+		if err := dyno.Set(g, existing, metadataPath...); err != nil {
+			return nil, fmt.Errorf("failed to set denom metadata in genesis: %w", err)
+		}
+
+		out, err := json.Marshal(g)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal genesis: %w", err)
+		}
+		return out, nil
+	}
+}
+
+// QueryDenomMetadata returns the bank metadata of denom.
+func (c *CosmosChain) QueryDenomMetadata(ctx context.Context, denom string) (banktypes.Metadata, error) {
+	conn, err := c.dialGRPC()
+	if err != nil {
+		return banktypes.Metadata{}, err
+	}
+	defer conn.Close()
+
+	res, err := banktypes.NewQueryClient(conn).DenomMetadata(ctx, &banktypes.QueryDenomMetadataRequest{Denom: denom})
+	if err != nil {
+		return banktypes.Metadata{}, fmt.Errorf("failed to query denom metadata of %s: %w", denom, err)
+	}
+	return res.Metadata, nil
+}
+
+// QueryDenomTrace returns the denom trace of the ibc/ voucher denom ibcDenom.
+func (c *CosmosChain) QueryDenomTrace(ctx context.Context, ibcDenom string) (transfertypes.DenomTrace, error) {
+	conn, err := c.dialGRPC()
+	if err != nil {
+		return transfertypes.DenomTrace{}, err
+	}
+	defer conn.Close()
+
+	res, err := transfertypes.NewQueryClient(conn).DenomTrace(ctx, &transfertypes.QueryDenomTraceRequest{Hash: ibcDenom})
+	if err != nil {
+		return transfertypes.DenomTrace{}, fmt.Errorf("failed to query denom trace of %s: %w", ibcDenom, err)
+	}
+	return *res.DenomTrace, nil
+}
@@ -0,0 +1,55 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestModifyGenesisDenomMetadata(t *testing.T) {
+	t.Parallel()
+
+	modify := ModifyGenesisDenomMetadata(NewDenomMetadata("uatom", "atom", 6))
+	out, err := modify(ibc.ChainConfig{}, []byte(`{"app_state": {"bank": {"denom_metadata": [
+		{"base": "uatom", "display": "uatom"},
+		{"base": "uosmo", "display": "osmo"}
+	]}}}`))
+	require.NoError(t, err)
+
+	var g struct {
+		AppState struct {
+			Bank struct {
+				DenomMetadata []struct {
+					Base    string `json:"base"`
+					Display string `json:"display"`
+				} `json:"denom_metadata"`
+			} `json:"bank"`
+		} `json:"app_state"`
+	}
+	require.NoError(t, json.Unmarshal(out, &g))
+	metadata := g.AppState.Bank.DenomMetadata
+	require.Len(t, metadata, 2)
+	require.Equal(t, "atom", metadata[0].Display)
+	require.Equal(t, "osmo", metadata[1].Display)
+
+	_, err = ModifyGenesisDenomMetadata(NewDenomMetadata("uatom", "", 6))(ibc.ChainConfig{}, out)
+	require.ErrorContains(t, err, "invalid metadata of uatom")
+}
+
+func TestCosmosChain_DisplayUnits(t *testing.T) {
+	t.Parallel()
+
+	eighteen := int64(18)
+	c := NewCosmosChain(t.Name(), ibc.ChainConfig{ChainID: "evmos-1", Denom: "aevmos", CoinDecimals: &eighteen}, 1, 0, zap.NewNop())
+	var du ibc.DisplayUnits = c
+	require.EqualValues(t, 18, du.Decimals())
+	require.Equal(t, "evmos", du.DisplayDenom())
+
+	amount, err := ibc.ToBaseUnits(c, "1.5 EVMOS")
+	require.NoError(t, err)
+	require.Equal(t, "1500000000000000000", amount.String())
+	require.Equal(t, "1.5", ibc.ToDisplayUnits(c, amount))
+}
@@ -0,0 +1,183 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	chanTypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// PacketEventType is the ABCI event type of an IBC packet event.
+type PacketEventType string
+
+const (
+	PacketEventSend                 PacketEventType = chanTypes.EventTypeSendPacket
+	PacketEventRecv                 PacketEventType = chanTypes.EventTypeRecvPacket
+	PacketEventWriteAcknowledgement PacketEventType = chanTypes.EventTypeWriteAck
+	PacketEventAcknowledge          PacketEventType = chanTypes.EventTypeAcknowledgePacket
+	PacketEventTimeout              PacketEventType = chanTypes.EventTypeTimeoutPacket
+)
+
+var packetEventTypes = map[PacketEventType]bool{
+	PacketEventSend:                 true,
+	PacketEventRecv:                 true,
+	PacketEventWriteAcknowledgement: true,
+	PacketEventAcknowledge:          true,
+	PacketEventTimeout:              true,
+}
+
+// PacketEvent is an IBC packet event decoded from a block's ABCI events.
+type PacketEvent struct {
+	// Height of the block that emitted the event.
+	Height uint64
+
+	Type PacketEventType
+
+	Packet ibc.Packet
+
+	// Acknowledgement written by the receiving chain.
+	// Only set for PacketEventWriteAcknowledgement.
+	Acknowledgement []byte
+
+	// Transfer is the packet data decoded as an ICS-20 fungible token transfer,
+	// or nil if the packet data is not a fungible token transfer.
+	Transfer *transfertypes.FungibleTokenPacketData
+}
+
+type blockResultsClient interface {
+	BlockResults(ctx context.Context, height *int64) (*tmtypes.ResultBlockResults, error)
+}
+
+// RangeBlockEvents iterates through all IBC packet events emitted at height, yielding each to done.
+// Events from transactions are yielded in transaction order, followed by any from begin and end block.
+// Return true from done to stop iteration.
+func (c *CosmosChain) RangeBlockEvents(ctx context.Context, height uint64, done func(PacketEvent) bool) error {
+	return rangeBlockPacketEvents(ctx, c.getFullNode().Client, height, done)
+}
+
+// PacketEvents returns all IBC packet events of type typ emitted at height.
+func (c *CosmosChain) PacketEvents(ctx context.Context, height uint64, typ PacketEventType) ([]PacketEvent, error) {
+	var events []PacketEvent
+	err := c.RangeBlockEvents(ctx, height, func(e PacketEvent) bool {
+		if e.Type == typ {
+			events = append(events, e)
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func rangeBlockPacketEvents(ctx context.Context, client blockResultsClient, height uint64, done func(PacketEvent) bool) error {
+	h := int64(height)
+	res, err := client.BlockResults(ctx, &h)
+	if err != nil {
+		return fmt.Errorf("tendermint rpc get block results: %w", err)
+	}
+
+	var all [][]abcitypes.Event
+	for _, tx := range res.TxsResults {
+		all = append(all, tx.Events)
+	}
+	all = append(all, res.BeginBlockEvents, res.EndBlockEvents)
+
+	for _, events := range all {
+		for _, e := range events {
+			pe, ok, err := parsePacketEvent(height, e)
+			if err != nil {
+				return fmt.Errorf("height %d: %w", height, err)
+			}
+			if !ok {
+				continue
+			}
+			if done(pe) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// parsePacketEvent decodes e into a PacketEvent.
+// It returns false if e is not an IBC packet event.
+func parsePacketEvent(height uint64, e abcitypes.Event) (PacketEvent, bool, error) {
+	typ := PacketEventType(e.Type)
+	if !packetEventTypes[typ] {
+		return PacketEvent{}, false, nil
+	}
+
+	attrs := make(map[string]string, len(e.Attributes))
+	for _, a := range e.Attributes {
+		attrs[string(a.Key)] = string(a.Value)
+	}
+
+	pe := PacketEvent{
+		Height: height,
+		Type:   typ,
+		Packet: ibc.Packet{
+			SourcePort:    attrs[chanTypes.AttributeKeySrcPort],
+			SourceChannel: attrs[chanTypes.AttributeKeySrcChannel],
+			DestPort:      attrs[chanTypes.AttributeKeyDstPort],
+			DestChannel:   attrs[chanTypes.AttributeKeyDstChannel],
+			TimeoutHeight: attrs[chanTypes.AttributeKeyTimeoutHeight],
+		},
+	}
+
+	seq, err := strconv.ParseUint(attrs[chanTypes.AttributeKeySequence], 10, 64)
+	if err != nil {
+		return pe, false, fmt.Errorf("%s: invalid packet sequence %q: %w", typ, attrs[chanTypes.AttributeKeySequence], err)
+	}
+	pe.Packet.Sequence = seq
+
+	if ts := attrs[chanTypes.AttributeKeyTimeoutTimestamp]; ts != "" {
+		n, err := strconv.ParseUint(ts, 10, 64)
+		if err != nil {
+			return pe, false, fmt.Errorf("%s: invalid packet timeout timestamp %q: %w", typ, ts, err)
+		}
+		pe.Packet.TimeoutTimestamp = ibc.Nanoseconds(n)
+	}
+
+	pe.Packet.Data, err = hexOrRawAttr(attrs, chanTypes.AttributeKeyDataHex, chanTypes.AttributeKeyData)
+	if err != nil {
+		return pe, false, fmt.Errorf("%s: %w", typ, err)
+	}
+	if typ == PacketEventWriteAcknowledgement {
+		pe.Acknowledgement, err = hexOrRawAttr(attrs, chanTypes.AttributeKeyAckHex, chanTypes.AttributeKeyAck)
+		if err != nil {
+			return pe, false, fmt.Errorf("%s: %w", typ, err)
+		}
+	}
+
+	// Acknowledge and timeout events do not include packet data.
+	if len(pe.Packet.Data) > 0 {
+		if data, err := ibc.DecodeTransferPacketData(pe.Packet.Data); err == nil {
+			pe.Transfer = &data
+		}
+	}
+
+	return pe, true, nil
+}
+
+// hexOrRawAttr returns the hex decoded value of hexKey,
+// falling back to the deprecated raw value of rawKey if hexKey is absent.
+func hexOrRawAttr(attrs map[string]string, hexKey, rawKey string) ([]byte, error) {
+	if v, ok := attrs[hexKey]; ok {
+		b, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", hexKey, v, err)
+		}
+		return b, nil
+	}
+	if v, ok := attrs[rawKey]; ok {
+		return []byte(v), nil
+	}
+	return nil, nil
+}
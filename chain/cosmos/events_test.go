@@ -0,0 +1,108 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	chanTypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
+	"github.com/stretchr/testify/require"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+type mockBlockResultsClient struct {
+	Res *tmtypes.ResultBlockResults
+}
+
+func (m mockBlockResultsClient) BlockResults(ctx context.Context, height *int64) (*tmtypes.ResultBlockResults, error) {
+	return m.Res, nil
+}
+
+func testPacketEvent(typ string, extra ...abcitypes.EventAttribute) abcitypes.Event {
+	attrs := []abcitypes.EventAttribute{
+		{Key: []byte(chanTypes.AttributeKeySequence), Value: []byte("3")},
+		{Key: []byte(chanTypes.AttributeKeySrcPort), Value: []byte("transfer")},
+		{Key: []byte(chanTypes.AttributeKeySrcChannel), Value: []byte("channel-0")},
+		{Key: []byte(chanTypes.AttributeKeyDstPort), Value: []byte("transfer")},
+		{Key: []byte(chanTypes.AttributeKeyDstChannel), Value: []byte("channel-1")},
+		{Key: []byte(chanTypes.AttributeKeyTimeoutHeight), Value: []byte("0-100")},
+		{Key: []byte(chanTypes.AttributeKeyTimeoutTimestamp), Value: []byte("1000")},
+	}
+	return abcitypes.Event{Type: typ, Attributes: append(attrs, extra...)}
+}
+
+func TestRangeBlockPacketEvents(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"amount":"100","denom":"uatom","receiver":"osmo1abc","sender":"cosmos1abc"}`)
+	ack := []byte(`{"result":"AQ=="}`)
+
+	client := mockBlockResultsClient{Res: &tmtypes.ResultBlockResults{
+		TxsResults: []*abcitypes.ResponseDeliverTx{
+			{Events: []abcitypes.Event{
+				{Type: "message", Attributes: []abcitypes.EventAttribute{{Key: []byte("action"), Value: []byte("recv")}}},
+				testPacketEvent(chanTypes.EventTypeRecvPacket,
+					abcitypes.EventAttribute{Key: []byte(chanTypes.AttributeKeyDataHex), Value: []byte(hex.EncodeToString(data))},
+				),
+				testPacketEvent(chanTypes.EventTypeWriteAck,
+					abcitypes.EventAttribute{Key: []byte(chanTypes.AttributeKeyDataHex), Value: []byte(hex.EncodeToString(data))},
+					abcitypes.EventAttribute{Key: []byte(chanTypes.AttributeKeyAck), Value: ack},
+				),
+			}},
+		},
+		EndBlockEvents: []abcitypes.Event{
+			testPacketEvent(chanTypes.EventTypeTimeoutPacket),
+		},
+	}}
+
+	var events []PacketEvent
+	err := rangeBlockPacketEvents(context.Background(), client, 7, func(e PacketEvent) bool {
+		events = append(events, e)
+		return false
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	recv := events[0]
+	require.Equal(t, PacketEventRecv, recv.Type)
+	require.Equal(t, uint64(7), recv.Height)
+	require.Equal(t, uint64(3), recv.Packet.Sequence)
+	require.Equal(t, "channel-0", recv.Packet.SourceChannel)
+	require.Equal(t, "channel-1", recv.Packet.DestChannel)
+	require.Equal(t, "0-100", recv.Packet.TimeoutHeight)
+	require.EqualValues(t, 1000, recv.Packet.TimeoutTimestamp)
+	require.Equal(t, data, recv.Packet.Data)
+	require.NotNil(t, recv.Transfer)
+	require.Equal(t, "uatom", recv.Transfer.Denom)
+	require.Equal(t, "100", recv.Transfer.Amount)
+
+	writeAck := events[1]
+	require.Equal(t, PacketEventWriteAcknowledgement, writeAck.Type)
+	require.Equal(t, ack, writeAck.Acknowledgement)
+
+	timeout := events[2]
+	require.Equal(t, PacketEventTimeout, timeout.Type)
+	require.Empty(t, timeout.Packet.Data)
+	require.Nil(t, timeout.Transfer)
+
+	// Stop iteration early.
+	var n int
+	err = rangeBlockPacketEvents(context.Background(), client, 7, func(e PacketEvent) bool {
+		n++
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
+
+func TestParsePacketEvent_InvalidSequence(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := parsePacketEvent(1, abcitypes.Event{Type: chanTypes.EventTypeSendPacket})
+	require.ErrorContains(t, err, "invalid packet sequence")
+
+	_, ok, err := parsePacketEvent(1, abcitypes.Event{Type: "transfer"})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
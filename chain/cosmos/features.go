@@ -0,0 +1,56 @@
+package cosmos
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// ibcGoFeatureVersions are the first ibc-go versions including the modules of each feature.
+var ibcGoFeatureVersions = map[ibc.ChainFeature][3]int{
+	ibc.FeatureInterchainAccounts: {3, 0, 0},
+	ibc.FeatureFeeMiddleware:      {4, 0, 0},
+	ibc.FeatureMemo:               {6, 0, 0},
+}
+
+// ibcGoSupportsFeature reports whether ibc-go at version, e.g. "v6.1.0", includes the modules of f.
+// It reports false for an empty or unparsable version, and for features that depend on more than the ibc-go version.
+func ibcGoSupportsFeature(version string, f ibc.ChainFeature) bool {
+	want, ok := ibcGoFeatureVersions[f]
+	if !ok {
+		return false
+	}
+	have, ok := parseIBCGoVersion(version)
+	if !ok {
+		return false
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			return have[i] > want[i]
+		}
+	}
+	return true
+}
+
+// parseIBCGoVersion parses the major, minor and patch numbers of version, e.g. "v6.1.0" or "6.1.0-rc1".
+// Missing minor and patch numbers are zero. Pre-release and build suffixes are ignored.
+func parseIBCGoVersion(version string) ([3]int, bool) {
+	var v [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if version == "" || len(parts) > len(v) {
+		return v, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
@@ -0,0 +1,63 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCosmosChainSupportsFeature(t *testing.T) {
+	t.Parallel()
+
+	chain := func(cfg ibc.ChainConfig) *CosmosChain { return &CosmosChain{cfg: cfg} }
+
+	unknown := chain(ibc.ChainConfig{})
+	require.True(t, unknown.SupportsFeature(ibc.FeatureTransfer))
+	require.True(t, unknown.SupportsFeature(ibc.FeatureHeightTimeout))
+	require.True(t, unknown.SupportsFeature(ibc.FeatureTimestampTimeout))
+	for _, f := range []ibc.ChainFeature{
+		ibc.FeatureMemo, ibc.FeatureInterchainAccounts, ibc.FeatureFeeMiddleware, ibc.FeatureWasmClients, ibc.FeatureChannelClose,
+	} {
+		require.False(t, unknown.SupportsFeature(f), f)
+	}
+
+	v4 := chain(ibc.ChainConfig{IBCGoVersion: "v4.1.0"})
+	require.True(t, v4.SupportsFeature(ibc.FeatureInterchainAccounts))
+	require.True(t, v4.SupportsFeature(ibc.FeatureFeeMiddleware))
+	require.False(t, v4.SupportsFeature(ibc.FeatureMemo))
+	require.False(t, v4.SupportsFeature(ibc.FeatureWasmClients))
+
+	explicit := chain(ibc.ChainConfig{
+		IBCGoVersion:       "v4.1.0",
+		AdditionalFeatures: []ibc.ChainFeature{ibc.FeatureMemo, ibc.FeatureWasmClients},
+	})
+	require.True(t, explicit.SupportsFeature(ibc.FeatureMemo))
+	require.True(t, explicit.SupportsFeature(ibc.FeatureWasmClients))
+	require.False(t, explicit.SupportsFeature(ibc.FeatureChannelClose))
+}
+
+func TestIBCGoSupportsFeature(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		version string
+		feature ibc.ChainFeature
+		want    bool
+	}{
+		{"", ibc.FeatureInterchainAccounts, false},
+		{"main", ibc.FeatureInterchainAccounts, false},
+		{"v2.3.0", ibc.FeatureInterchainAccounts, false},
+		{"v3.0.0", ibc.FeatureInterchainAccounts, true},
+		{"3.4", ibc.FeatureInterchainAccounts, true},
+		{"v3.4.0", ibc.FeatureFeeMiddleware, false},
+		{"v4.0.0-rc0", ibc.FeatureFeeMiddleware, true},
+		{"v5.2.0", ibc.FeatureMemo, false},
+		{"v6.0.0-alpha1", ibc.FeatureMemo, true},
+		{"v7.0.0", ibc.FeatureMemo, true},
+		{"v7.0.0", ibc.FeatureChannelClose, false},
+		{"v7.0.0.1", ibc.FeatureMemo, false},
+	} {
+		require.Equal(t, tt.want, ibcGoSupportsFeature(tt.version, tt.feature), "%s %s", tt.version, tt.feature)
+	}
+}
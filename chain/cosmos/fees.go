@@ -0,0 +1,119 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/icza/dyno"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// basicAllowanceType is the type URL of feegrant basic allowances.
+const basicAllowanceType = "/cosmos.feegrant.v1beta1.BasicAllowance"
+
+// ModifyGenesisGlobalFee returns a ChainConfig.ModifyGenesis function setting the minimum gas prices
+// of the globalfee module, e.g. "0.01uatom", for chains such as gaia that enforce fees chain-wide.
+// See also ChainConfig.GlobalFeeMinGasPrices.
+func ModifyGenesisGlobalFee(minGasPrices string) func(ibc.ChainConfig, []byte) ([]byte, error) {
+	return func(_ ibc.ChainConfig, genbz []byte) ([]byte, error) {
+		prices, err := sdk.ParseDecCoins(minGasPrices)
+		if err != nil {
+			return nil, fmt.Errorf("invalid global fee min gas prices %q: %w", minGasPrices, err)
+		}
+
+		g := make(map[string]interface{})
+		if err := json.Unmarshal(genbz, &g); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal genesis file: %w", err)
+		}
+
+		if _, err := dyno.Get(g, "app_state", "globalfee"); err != nil {
+			return nil, fmt.Errorf("chain has no globalfee module: %w", err)
+		}
+		coins := make([]interface{}, 0, len(prices))
+		for _, p := range prices {
+			coins = append(coins, map[string]interface{}{"denom": p.Denom, "amount": p.Amount.String()})
+		}
+		if err := dyno.Set(g, coins, "app_state", "globalfee", "params", "minimum_gas_prices"); err != nil {
+			return nil, fmt.Errorf("failed to set global fee min gas prices in genesis: %w", err)
+		}
+
+		out, err := json.Marshal(g)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal genesis: %w", err)
+		}
+		return out, nil
+	}
+}
+
+// ModifyGenesisFeeAllowances returns a ChainConfig.ModifyGenesis function adding feegrant basic allowances.
+// The granters must be genesis accounts.
+// See also ChainConfig.RelayerFeeAllowance, which grants the relayer wallets an allowance from the faucet.
+func ModifyGenesisFeeAllowances(allowances ...ibc.GenesisFeeAllowance) func(ibc.ChainConfig, []byte) ([]byte, error) {
+	return func(_ ibc.ChainConfig, genbz []byte) ([]byte, error) {
+		g := make(map[string]interface{})
+		if err := json.Unmarshal(genbz, &g); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal genesis file: %w", err)
+		}
+
+		allowancesPath := []interface{}{"app_state", "feegrant", "allowances"}
+		if _, err := dyno.Get(g, "app_state", "feegrant"); err != nil {
+			return nil, fmt.Errorf("chain has no feegrant module: %w", err)
+		}
+		existing, err := dyno.GetSlice(g, allowancesPath...)
+		if err != nil {
+			// The allowances may be null in an exported genesis.
+			existing = nil
+		}
+
+		for _, a := range allowances {
+			spendLimit, err := sdk.ParseCoinsNormalized(a.SpendLimit)
+			if err != nil {
+				return nil, fmt.Errorf("invalid spend limit %q of fee allowance for %s: %w", a.SpendLimit, a.Grantee, err)
+			}
+			coins := make([]interface{}, 0, len(spendLimit))
+			for _, c := range spendLimit {
+				coins = append(coins, map[string]interface{}{"denom": c.Denom, "amount": c.Amount.String()})
+			}
+			existing = append(existing, map[string]interface{}{
+				"granter": a.Granter,
+				"grantee": a.Grantee,
+				"allowance": map[string]interface{}{
+					"@type":       basicAllowanceType,
+					"spend_limit": coins,
+					"expiration":  nil,
+				},
+			})
+		}
+		if err := dyno.Set(g, existing, allowancesPath...); err != nil {
+			return nil, fmt.Errorf("failed to set fee allowances in genesis: %w", err)
+		}
+
+		out, err := json.Marshal(g)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal genesis: %w", err)
+		}
+		return out, nil
+	}
+}
+
+// AddGenesisFeeAllowances implements ibc.GenesisFeeGranter.
+func (c *CosmosChain) AddGenesisFeeAllowances(allowances ...ibc.GenesisFeeAllowance) {
+	c.genesisFeeAllowances = append(c.genesisFeeAllowances, allowances...)
+}
+
+// modifyGenesisFees applies the fee settings of cfg and the allowances added through AddGenesisFeeAllowances to genbz.
+func (c *CosmosChain) modifyGenesisFees(cfg ibc.ChainConfig, genbz []byte) ([]byte, error) {
+	var err error
+	if cfg.GlobalFeeMinGasPrices != "" {
+		if genbz, err = ModifyGenesisGlobalFee(cfg.GlobalFeeMinGasPrices)(cfg, genbz); err != nil {
+			return nil, err
+		}
+	}
+	if len(c.genesisFeeAllowances) > 0 {
+		if genbz, err = ModifyGenesisFeeAllowances(c.genesisFeeAllowances...)(cfg, genbz); err != nil {
+			return nil, err
+		}
+	}
+	return genbz, nil
+}
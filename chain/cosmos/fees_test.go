@@ -0,0 +1,56 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifyGenesisGlobalFee(t *testing.T) {
+	t.Parallel()
+
+	out, err := ModifyGenesisGlobalFee("0.01uatom")(ibc.ChainConfig{}, []byte(`{"app_state": {"globalfee": {"params": {"minimum_gas_prices": []}}}}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"app_state": {"globalfee": {"params": {"minimum_gas_prices": [
+		{"denom": "uatom", "amount": "0.010000000000000000"}
+	]}}}}`, string(out))
+
+	_, err = ModifyGenesisGlobalFee("0.01uatom")(ibc.ChainConfig{}, []byte(`{"app_state": {}}`))
+	require.ErrorContains(t, err, "chain has no globalfee module")
+
+	_, err = ModifyGenesisGlobalFee("uatom")(ibc.ChainConfig{}, []byte(`{"app_state": {}}`))
+	require.ErrorContains(t, err, `invalid global fee min gas prices "uatom"`)
+}
+
+func TestModifyGenesisFeeAllowances(t *testing.T) {
+	t.Parallel()
+
+	modify := ModifyGenesisFeeAllowances(
+		ibc.GenesisFeeAllowance{Granter: "cosmos1faucet", Grantee: "cosmos1relayer"},
+		ibc.GenesisFeeAllowance{Granter: "cosmos1faucet", Grantee: "cosmos1user", FeeAllowance: ibc.FeeAllowance{SpendLimit: "1000uatom"}},
+	)
+	out, err := modify(ibc.ChainConfig{}, []byte(`{"app_state": {"feegrant": {"allowances": null}}}`))
+	require.NoError(t, err)
+
+	var g struct {
+		AppState struct {
+			Feegrant struct {
+				Allowances []json.RawMessage `json:"allowances"`
+			} `json:"feegrant"`
+		} `json:"app_state"`
+	}
+	require.NoError(t, json.Unmarshal(out, &g))
+	allowances := g.AppState.Feegrant.Allowances
+	require.Len(t, allowances, 2)
+	require.JSONEq(t, `{"granter": "cosmos1faucet", "grantee": "cosmos1relayer", "allowance": {
+		"@type": "/cosmos.feegrant.v1beta1.BasicAllowance", "spend_limit": [], "expiration": null
+	}}`, string(allowances[0]))
+	require.JSONEq(t, `{"granter": "cosmos1faucet", "grantee": "cosmos1user", "allowance": {
+		"@type": "/cosmos.feegrant.v1beta1.BasicAllowance", "spend_limit": [{"denom": "uatom", "amount": "1000"}], "expiration": null
+	}}`, string(allowances[1]))
+
+	_, err = modify(ibc.ChainConfig{}, []byte(`{"app_state": {}}`))
+	require.ErrorContains(t, err, "chain has no feegrant module")
+}
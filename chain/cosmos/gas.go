@@ -0,0 +1,183 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+	"go.uber.org/zap"
+)
+
+// MinGasPrices returns the minimum gas prices that the node accepts for transactions,
+// as set by minimum-gas-prices in its app.toml.
+func (tn *ChainNode) MinGasPrices(ctx context.Context) (sdk.DecCoins, error) {
+	fr := dockerutil.NewFileRetriever(tn.logger(), tn.DockerClient, tn.TestName)
+	bz, err := fr.SingleFileContent(ctx, tn.VolumeName, "config/app.toml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve app.toml: %w", err)
+	}
+	return parseAppMinGasPrices(bz)
+}
+
+// FeemarketGasPrices returns the gas prices required by the chain's feemarket module,
+// supporting both the evmos and skip feemarket modules.
+// It returns no prices if the chain has no feemarket module or the module is disabled.
+func (tn *ChainNode) FeemarketGasPrices(ctx context.Context) (sdk.DecCoins, error) {
+	stdout, _, err := tn.ExecQuery(ctx, "feemarket", "params")
+	if err != nil {
+		// The query command does not exist if the chain has no feemarket module.
+		tn.logger().Debug("No feemarket params", zap.Error(err))
+		return nil, nil
+	}
+	return parseFeemarketGasPrices(tn.Chain.Config().Denom, stdout)
+}
+
+// DiscoverGasPrices raises the configured gas prices to the prices that the chain's full node enforces,
+// through its minimum-gas-prices and feemarket params, so that transactions from the relayer,
+// the Broadcaster, and the chain's CLI are not rejected for insufficient fees.
+// Only the prices of denoms in the configured GasPrices are raised; the configured prices are never lowered.
+//
+// DiscoverGasPrices is called once the chain has started, and returns the resulting gas prices.
+func (c *CosmosChain) DiscoverGasPrices(ctx context.Context) (string, error) {
+	tn := c.getFullNode()
+	minGasPrices, err := tn.MinGasPrices(ctx)
+	if err != nil {
+		return "", err
+	}
+	feemarket, err := tn.FeemarketGasPrices(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// The configured prices are read under the lock, as concurrent callers may raise them too.
+	c.cfgMu.Lock()
+	defer c.cfgMu.Unlock()
+	configured, err := sdk.ParseDecCoins(c.cfg.GasPrices)
+	if err != nil {
+		return "", fmt.Errorf("invalid gas prices %q: %w", c.cfg.GasPrices, err)
+	}
+	if raised, ok := raiseGasPrices(configured, minGasPrices, feemarket); ok {
+		c.log.Info(
+			"Raising gas prices to the node's minimum",
+			zap.String("chain_id", c.cfg.ChainID),
+			zap.String("configured", c.cfg.GasPrices),
+			zap.String("gas_prices", raised.String()),
+		)
+		c.cfg.GasPrices = raised.String()
+	}
+	return c.cfg.GasPrices, nil
+}
+
+// parseAppMinGasPrices parses minimum-gas-prices from the contents of an app.toml.
+func parseAppMinGasPrices(appToml []byte) (sdk.DecCoins, error) {
+	var app struct {
+		MinGasPrices string `toml:"minimum-gas-prices"`
+	}
+	if err := toml.Unmarshal(appToml, &app); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal app.toml: %w", err)
+	}
+	prices, err := sdk.ParseDecCoins(app.MinGasPrices)
+	if err != nil {
+		return nil, fmt.Errorf("invalid minimum-gas-prices %q in app.toml: %w", app.MinGasPrices, err)
+	}
+	return prices, nil
+}
+
+// feemarketParams holds the fields of the evmos and skip feemarket module params that determine gas prices.
+type feemarketParams struct {
+	// evmos feemarket, priced in the chain's denom.
+	NoBaseFee   bool   `json:"no_base_fee"`
+	BaseFee     string `json:"base_fee"`
+	MinGasPrice string `json:"min_gas_price"`
+
+	// skip feemarket.
+	Enabled         *bool  `json:"enabled"`
+	FeeDenom        string `json:"fee_denom"`
+	MinBaseGasPrice string `json:"min_base_gas_price"`
+}
+
+// parseFeemarketGasPrices returns the gas prices required by the feemarket params in the query output bz.
+func parseFeemarketGasPrices(denom string, bz []byte) (sdk.DecCoins, error) {
+	var resp struct {
+		// The evmos query wraps the params, while the skip query does not.
+		Params *feemarketParams `json:"params"`
+		feemarketParams
+	}
+	if err := json.Unmarshal(bz, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal feemarket params: %w", err)
+	}
+	params := resp.feemarketParams
+	if resp.Params != nil {
+		params = *resp.Params
+	}
+
+	var prices sdk.DecCoins
+	addPrice := func(denom, amount string) error {
+		if amount == "" {
+			return nil
+		}
+		price, err := sdk.NewDecFromStr(amount)
+		if err != nil {
+			return fmt.Errorf("invalid feemarket gas price %q: %w", amount, err)
+		}
+		prices = raiseDenomPrice(prices, sdk.NewDecCoinFromDec(denom, price))
+		return nil
+	}
+
+	if params.MinBaseGasPrice != "" {
+		if params.Enabled != nil && !*params.Enabled {
+			return nil, nil
+		}
+		feeDenom := params.FeeDenom
+		if feeDenom == "" {
+			feeDenom = denom
+		}
+		if err := addPrice(feeDenom, params.MinBaseGasPrice); err != nil {
+			return nil, err
+		}
+		return prices, nil
+	}
+
+	if err := addPrice(denom, params.MinGasPrice); err != nil {
+		return nil, err
+	}
+	if !params.NoBaseFee {
+		if err := addPrice(denom, params.BaseFee); err != nil {
+			return nil, err
+		}
+	}
+	return prices.Sort(), nil
+}
+
+// raiseGasPrices raises each price in configured to the highest price of the same denom in required.
+// It reports whether any price was raised.
+func raiseGasPrices(configured sdk.DecCoins, required ...sdk.DecCoins) (sdk.DecCoins, bool) {
+	raised := false
+	out := make(sdk.DecCoins, len(configured))
+	copy(out, configured)
+	for i, price := range out {
+		for _, prices := range required {
+			if req := prices.AmountOf(price.Denom); req.GT(out[i].Amount) {
+				out[i].Amount = req
+				raised = true
+			}
+		}
+	}
+	return out, raised
+}
+
+// raiseDenomPrice sets the price of the denom of price in prices, if it is higher than the existing price.
+func raiseDenomPrice(prices sdk.DecCoins, price sdk.DecCoin) sdk.DecCoins {
+	for i, p := range prices {
+		if p.Denom == price.Denom {
+			if price.Amount.GT(p.Amount) {
+				prices[i].Amount = price.Amount
+			}
+			return prices
+		}
+	}
+	return append(prices, price)
+}
@@ -0,0 +1,71 @@
+package cosmos
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAppMinGasPrices(t *testing.T) {
+	t.Parallel()
+
+	prices, err := parseAppMinGasPrices([]byte(`
+minimum-gas-prices = "0.01uatom,0.5ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+pruning = "default"
+`))
+	require.NoError(t, err)
+	require.Equal(t, "0.010000000000000000", prices.AmountOf("uatom").String())
+
+	prices, err = parseAppMinGasPrices([]byte(`minimum-gas-prices = ""`))
+	require.NoError(t, err)
+	require.Empty(t, prices)
+
+	_, err = parseAppMinGasPrices([]byte(`minimum-gas-prices = "cheap"`))
+	require.ErrorContains(t, err, "invalid minimum-gas-prices")
+}
+
+func TestParseFeemarketGasPrices(t *testing.T) {
+	t.Parallel()
+
+	t.Run("evmos", func(t *testing.T) {
+		prices, err := parseFeemarketGasPrices("aevmos", []byte(`{"params":{"no_base_fee":false,"base_fee":"1000000000","min_gas_price":"500000000.000000000000000000"}}`))
+		require.NoError(t, err)
+		require.Equal(t, "1000000000.000000000000000000aevmos", prices.String())
+
+		prices, err = parseFeemarketGasPrices("aevmos", []byte(`{"params":{"no_base_fee":true,"base_fee":"1000000000","min_gas_price":"0"}}`))
+		require.NoError(t, err)
+		require.True(t, prices.AmountOf("aevmos").IsZero())
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		prices, err := parseFeemarketGasPrices("stake", []byte(`{"enabled":true,"fee_denom":"uatom","min_base_gas_price":"0.0025"}`))
+		require.NoError(t, err)
+		require.Equal(t, "0.002500000000000000uatom", prices.String())
+
+		prices, err = parseFeemarketGasPrices("stake", []byte(`{"enabled":false,"fee_denom":"uatom","min_base_gas_price":"0.0025"}`))
+		require.NoError(t, err)
+		require.Empty(t, prices)
+	})
+
+	_, err := parseFeemarketGasPrices("stake", []byte(`{"params":{"base_fee":"lots"}}`))
+	require.ErrorContains(t, err, "invalid feemarket gas price")
+}
+
+func TestRaiseGasPrices(t *testing.T) {
+	t.Parallel()
+
+	configured, err := sdk.ParseDecCoins("0.01uatom")
+	require.NoError(t, err)
+
+	raised, ok := raiseGasPrices(configured,
+		sdk.NewDecCoins(sdk.NewDecCoinFromDec("uatom", sdk.MustNewDecFromStr("0.025")), sdk.NewDecCoin("uosmo", sdk.NewInt(1))),
+		sdk.NewDecCoins(sdk.NewDecCoinFromDec("uatom", sdk.MustNewDecFromStr("0.02"))),
+	)
+	require.True(t, ok)
+	require.Equal(t, "0.025000000000000000uatom", raised.String())
+	require.Equal(t, "0.010000000000000000uatom", configured.String())
+
+	_, ok = raiseGasPrices(configured, sdk.NewDecCoins(sdk.NewDecCoinFromDec("uatom", sdk.MustNewDecFromStr("0.001"))), nil)
+	require.False(t, ok)
+}
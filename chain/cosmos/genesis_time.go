@@ -0,0 +1,28 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// ModifyGenesisTime returns a ChainConfig.ModifyGenesis function setting the genesis time of the chain to t.
+// Nodes produce no blocks before the genesis time, so a genesis time in the future delays the chain's first block.
+func ModifyGenesisTime(t time.Time) func(ibc.ChainConfig, []byte) ([]byte, error) {
+	return func(_ ibc.ChainConfig, genbz []byte) ([]byte, error) {
+		g := make(map[string]interface{})
+		if err := json.Unmarshal(genbz, &g); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal genesis file: %w", err)
+		}
+
+		g["genesis_time"] = t.UTC().Format(time.RFC3339Nano)
+
+		out, err := json.Marshal(g)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal genesis: %w", err)
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,88 @@
+package cosmos
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+	tmmock "github.com/tendermint/tendermint/rpc/client/mock"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"go.uber.org/zap"
+)
+
+func TestModifyGenesisTime(t *testing.T) {
+	t.Parallel()
+
+	genesisTime := time.Date(2030, time.January, 2, 3, 4, 5, 6, time.FixedZone("UTC+1", 3600))
+	out, err := ModifyGenesisTime(genesisTime)(ibc.ChainConfig{}, []byte(`{"genesis_time": "2022-01-01T00:00:00Z", "chain_id": "test-1"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"genesis_time": "2030-01-02T02:04:05.000000006Z", "chain_id": "test-1"}`, string(out))
+
+	_, err = ModifyGenesisTime(genesisTime)(ibc.ChainConfig{}, []byte(`not json`))
+	require.ErrorContains(t, err, "failed to unmarshal genesis file")
+}
+
+// genesisStatusClient reports no blocks until genesis is closed,
+// and a new block on every status query after.
+type genesisStatusClient struct {
+	genesis chan struct{}
+
+	mu     sync.Mutex
+	height int64
+}
+
+func (c *genesisStatusClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.genesis:
+		c.height++
+	default:
+	}
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: c.height}}, nil
+}
+
+func TestCosmosChain_WaitForGenesis(t *testing.T) {
+	t.Parallel()
+
+	newChain := func() (*CosmosChain, *genesisStatusClient) {
+		c := NewCosmosChain(t.Name(), ibc.ChainConfig{ChainID: "gaia-1", StartBeforeGenesis: true}, 1, 0, zap.NewNop())
+		client := &genesisStatusClient{genesis: make(chan struct{})}
+		c.Validators = ChainNodes{{Client: tmmock.Client{StatusClient: client}}}
+		return c, client
+	}
+
+	t.Run("waits for blocks after genesis", func(t *testing.T) {
+		c, client := newChain()
+
+		errc := make(chan error, 1)
+		go func() { errc <- c.WaitForGenesis(context.Background()) }()
+
+		select {
+		case err := <-errc:
+			t.Fatalf("WaitForGenesis returned before genesis: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(client.genesis)
+		require.NoError(t, <-errc)
+
+		height, err := c.Height(context.Background())
+		require.NoError(t, err)
+		require.Greater(t, height, uint64(5))
+	})
+
+	t.Run("canceled before genesis", func(t *testing.T) {
+		c, _ := newChain()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, c.WaitForGenesis(ctx), context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,233 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	clienttypes "github.com/cosmos/ibc-go/v6/modules/core/02-client/types"
+	conntypes "github.com/cosmos/ibc-go/v6/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
+	ibcexported "github.com/cosmos/ibc-go/v6/modules/core/exported"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// The IBC queries below use the ibc-go gRPC services of the full node,
+// so that assertions on IBC state do not depend on the output of a relayer's query commands.
+// Like BalanceAtHeight, they query the state at the height set in ctx's outgoing metadata, if any.
+
+// QueryClientState returns the state of the light client clientID, such as the *ClientState of the 07-tendermint light client.
+func (c *CosmosChain) QueryClientState(ctx context.Context, clientID string) (ibcexported.ClientState, error) {
+	conn, err := c.dialGRPC()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	res, err := clienttypes.NewQueryClient(conn).ClientState(ctx, &clienttypes.QueryClientStateRequest{ClientId: clientID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query client state of %s: %w", clientID, err)
+	}
+	return unpackClientState(c.cfg.EncodingConfig.InterfaceRegistry, res.ClientState)
+}
+
+// QueryConnection returns the connection end of connectionID.
+func (c *CosmosChain) QueryConnection(ctx context.Context, connectionID string) (*conntypes.ConnectionEnd, error) {
+	conn, err := c.dialGRPC()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	res, err := conntypes.NewQueryClient(conn).Connection(ctx, &conntypes.QueryConnectionRequest{ConnectionId: connectionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connection %s: %w", connectionID, err)
+	}
+	return res.Connection, nil
+}
+
+// QueryChannel returns the channel end of channelID on portID.
+func (c *CosmosChain) QueryChannel(ctx context.Context, portID, channelID string) (*chantypes.Channel, error) {
+	conn, err := c.dialGRPC()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	res, err := chantypes.NewQueryClient(conn).Channel(ctx, &chantypes.QueryChannelRequest{PortId: portID, ChannelId: channelID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query channel %s/%s: %w", portID, channelID, err)
+	}
+	return res.Channel, nil
+}
+
+// QueryPacketCommitments returns the commitments of the packets sent on channelID and not yet acknowledged or timed out.
+func (c *CosmosChain) QueryPacketCommitments(ctx context.Context, portID, channelID string) ([]*chantypes.PacketState, error) {
+	conn, err := c.dialGRPC()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	qc := chantypes.NewQueryClient(conn)
+	commitments, err := collectPacketStates(func(page *query.PageRequest) ([]*chantypes.PacketState, *query.PageResponse, error) {
+		res, err := qc.PacketCommitments(ctx, &chantypes.QueryPacketCommitmentsRequest{PortId: portID, ChannelId: channelID, Pagination: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Commitments, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query packet commitments of %s/%s: %w", portID, channelID, err)
+	}
+	return commitments, nil
+}
+
+// QueryPacketReceipt reports whether the packet with sequence, sent to channelID, was received.
+// Only unordered channels record packet receipts.
+func (c *CosmosChain) QueryPacketReceipt(ctx context.Context, portID, channelID string, sequence uint64) (bool, error) {
+	conn, err := c.dialGRPC()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	res, err := chantypes.NewQueryClient(conn).PacketReceipt(ctx, &chantypes.QueryPacketReceiptRequest{PortId: portID, ChannelId: channelID, Sequence: sequence})
+	if err != nil {
+		return false, fmt.Errorf("failed to query receipt of packet %d on %s/%s: %w", sequence, portID, channelID, err)
+	}
+	return res.Received, nil
+}
+
+// QueryPacketAcknowledgements returns the acknowledgements written for the packets received on channelID.
+func (c *CosmosChain) QueryPacketAcknowledgements(ctx context.Context, portID, channelID string) ([]*chantypes.PacketState, error) {
+	conn, err := c.dialGRPC()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	qc := chantypes.NewQueryClient(conn)
+	acks, err := collectPacketStates(func(page *query.PageRequest) ([]*chantypes.PacketState, *query.PageResponse, error) {
+		res, err := qc.PacketAcknowledgements(ctx, &chantypes.QueryPacketAcknowledgementsRequest{PortId: portID, ChannelId: channelID, Pagination: page})
+		if err != nil {
+			return nil, nil, err
+		}
+		return res.Acknowledgements, res.Pagination, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query packet acknowledgements of %s/%s: %w", portID, channelID, err)
+	}
+	return acks, nil
+}
+
+// QueryConnections returns all connections of the chain, in any state.
+// Implements ibc.HandshakeStateQuerier.
+func (c *CosmosChain) QueryConnections(ctx context.Context) (ibc.ConnectionOutputs, error) {
+	conn, err := c.dialGRPC()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	qc := conntypes.NewQueryClient(conn)
+	var conns ibc.ConnectionOutputs
+	page := &query.PageRequest{}
+	for {
+		res, err := qc.Connections(ctx, &conntypes.QueryConnectionsRequest{Pagination: page})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query connections: %w", err)
+		}
+		for _, ic := range res.Connections {
+			counterparty := ic.Counterparty
+			conns = append(conns, &ibc.ConnectionOutput{
+				ID:           ic.Id,
+				ClientID:     ic.ClientId,
+				Versions:     ic.Versions,
+				State:        ic.State.String(),
+				Counterparty: &counterparty,
+				DelayPeriod:  strconv.FormatUint(ic.DelayPeriod, 10),
+			})
+		}
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return conns, nil
+		}
+		page = &query.PageRequest{Key: res.Pagination.NextKey}
+	}
+}
+
+// QueryChannels returns all channels of the chain, in any state.
+// Implements ibc.HandshakeStateQuerier.
+func (c *CosmosChain) QueryChannels(ctx context.Context) ([]ibc.ChannelOutput, error) {
+	conn, err := c.dialGRPC()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	qc := chantypes.NewQueryClient(conn)
+	var channels []ibc.ChannelOutput
+	page := &query.PageRequest{}
+	for {
+		res, err := qc.Channels(ctx, &chantypes.QueryChannelsRequest{Pagination: page})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query channels: %w", err)
+		}
+		for _, ic := range res.Channels {
+			channels = append(channels, ibc.ChannelOutput{
+				State:    ic.State.String(),
+				Ordering: ic.Ordering.String(),
+				Counterparty: ibc.ChannelCounterparty{
+					PortID:    ic.Counterparty.PortId,
+					ChannelID: ic.Counterparty.ChannelId,
+				},
+				ConnectionHops: ic.ConnectionHops,
+				Version:        ic.Version,
+				PortID:         ic.PortId,
+				ChannelID:      ic.ChannelId,
+			})
+		}
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return channels, nil
+		}
+		page = &query.PageRequest{Key: res.Pagination.NextKey}
+	}
+}
+
+// dialGRPC connects to the gRPC server of the full node.
+func (c *CosmosChain) dialGRPC() (*grpc.ClientConn, error) {
+	return grpc.Dial(c.getFullNode().hostGRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+// collectPacketStates calls fetch with each page, starting from the first, until the last page.
+func collectPacketStates(fetch func(*query.PageRequest) ([]*chantypes.PacketState, *query.PageResponse, error)) ([]*chantypes.PacketState, error) {
+	var states []*chantypes.PacketState
+	page := &query.PageRequest{}
+	for {
+		pageStates, res, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, pageStates...)
+		if res == nil || len(res.NextKey) == 0 {
+			return states, nil
+		}
+		page = &query.PageRequest{Key: res.NextKey}
+	}
+}
+
+// unpackClientState unpacks a client state returned by a query, whose concrete type must be registered in registry.
+func unpackClientState(registry codectypes.InterfaceRegistry, packed *codectypes.Any) (ibcexported.ClientState, error) {
+	if packed == nil {
+		return nil, fmt.Errorf("missing client state")
+	}
+	var clientState ibcexported.ClientState
+	if err := registry.UnpackAny(packed, &clientState); err != nil {
+		return nil, fmt.Errorf("failed to unpack client state %s: %w", packed.TypeUrl, err)
+	}
+	return clientState, nil
+}
@@ -0,0 +1,53 @@
+package cosmos
+
+import (
+	"errors"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	chantypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
+	ibctmtypes "github.com/cosmos/ibc-go/v6/modules/light-clients/07-tendermint/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectPacketStates(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string][]*chantypes.PacketState{
+		"":      {{Sequence: 1}, {Sequence: 2}},
+		"page2": {{Sequence: 3}},
+	}
+	next := map[string]string{"": "page2"}
+
+	states, err := collectPacketStates(func(page *query.PageRequest) ([]*chantypes.PacketState, *query.PageResponse, error) {
+		key := string(page.Key)
+		return pages[key], &query.PageResponse{NextKey: []byte(next[key])}, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, states, 3)
+	require.Equal(t, uint64(3), states[2].Sequence)
+
+	_, err = collectPacketStates(func(*query.PageRequest) ([]*chantypes.PacketState, *query.PageResponse, error) {
+		return nil, nil, errors.New("unavailable")
+	})
+	require.ErrorContains(t, err, "unavailable")
+}
+
+func TestUnpackClientState(t *testing.T) {
+	t.Parallel()
+
+	registry := DefaultEncoding().InterfaceRegistry
+
+	packed, err := codectypes.NewAnyWithValue(&ibctmtypes.ClientState{ChainId: "gaia-1"})
+	require.NoError(t, err)
+	// Queried client states are not cached in the Any.
+	packed = &codectypes.Any{TypeUrl: packed.TypeUrl, Value: packed.Value}
+
+	clientState, err := unpackClientState(registry, packed)
+	require.NoError(t, err)
+	require.Equal(t, "gaia-1", clientState.(*ibctmtypes.ClientState).ChainId)
+
+	_, err = unpackClientState(registry, nil)
+	require.ErrorContains(t, err, "missing client state")
+}
@@ -0,0 +1,90 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/configutil"
+)
+
+// topologyName returns the name of the node in an ibc.P2PTopology.
+func (tn *ChainNode) topologyName() string {
+	return ibc.TopologyNodeName(tn.Validator, tn.Index)
+}
+
+// peerAddr returns the address that other nodes dial to peer with the node.
+func (tn *ChainNode) peerAddr(ctx context.Context) (string, error) {
+	id, err := tn.NodeID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get node ID of %s: %w", tn.Name(), err)
+	}
+	return fmt.Sprintf("%s@%s:%s", id, tn.HostName(), strings.TrimSuffix(p2pPort, "/tcp")), nil
+}
+
+// peerAddrs returns the peer address of each node, by topology name.
+func (nodes ChainNodes) peerAddrs(ctx context.Context) (map[string]string, error) {
+	addrs := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		addr, err := n.peerAddr(ctx)
+		if err != nil {
+			return nil, err
+		}
+		addrs[n.topologyName()] = addr
+	}
+	return addrs, nil
+}
+
+// setNodePeers peers n with every node in peers, as returned by ChainNodes.PeerString,
+// or, if the chain defines a P2P topology, as the topology defines given the peer addresses of the chain's nodes.
+func (c *CosmosChain) setNodePeers(ctx context.Context, n *ChainNode, peers string, addrs map[string]string) error {
+	if c.cfg.P2P == nil {
+		return n.SetPeers(ctx, peers)
+	}
+	return configutil.ModifyTomlConfigFile(
+		ctx,
+		n.logger(),
+		n.DockerClient,
+		n.TestName,
+		n.VolumeName,
+		"config/config.toml",
+		configutil.Toml{"p2p": topologyP2PConfig(*c.cfg.P2P, n.topologyName(), addrs)},
+	)
+}
+
+// topologyP2PConfig returns the p2p section of the config.toml of the node named name in topology t,
+// given the peer addresses of the chain's nodes by name. Nodes without an address are left out.
+func topologyP2PConfig(t ibc.P2PTopology, name string, addrs map[string]string) configutil.Toml {
+	join := func(names []string) string {
+		var joined []string
+		for _, n := range names {
+			if addr, ok := addrs[n]; ok && n != name {
+				joined = append(joined, addr)
+			}
+		}
+		return strings.Join(joined, ",")
+	}
+
+	pex := true
+	for _, n := range t.DisablePEX {
+		if n == name {
+			pex = false
+		}
+	}
+
+	var private []string
+	for _, n := range t.Private {
+		if addr, ok := addrs[n]; ok {
+			id, _, _ := strings.Cut(addr, "@")
+			private = append(private, id)
+		}
+	}
+
+	return configutil.Toml{
+		"persistent_peers": join(t.Peers[name]),
+		"seeds":            join(t.Seeds),
+		"pex":              pex,
+		"private_peer_ids": strings.Join(private, ","),
+	}
+}
@@ -0,0 +1,48 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/configutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopologyP2PConfig(t *testing.T) {
+	t.Parallel()
+
+	// Validators behind a sentry full node, which is also the seed of the other full node.
+	topology := ibc.P2PTopology{
+		Peers: map[string][]string{
+			"val-0": {"fn-0"},
+			"val-1": {"fn-0"},
+			"fn-0":  {"val-0", "val-1"},
+		},
+		Seeds:      []string{"fn-0"},
+		DisablePEX: []string{"val-0", "val-1"},
+		Private:    []string{"val-0", "val-1"},
+	}
+	addrs := map[string]string{
+		"val-0": "a@val0:26656",
+		"val-1": "b@val1:26656",
+		"fn-0":  "c@fn0:26656",
+		"fn-1":  "d@fn1:26656",
+	}
+
+	require.Equal(t,
+		configutil.Toml{"persistent_peers": "c@fn0:26656", "seeds": "c@fn0:26656", "pex": false, "private_peer_ids": "a,b"},
+		topologyP2PConfig(topology, "val-0", addrs),
+	)
+	require.Equal(t,
+		configutil.Toml{"persistent_peers": "a@val0:26656,b@val1:26656", "seeds": "", "pex": true, "private_peer_ids": "a,b"},
+		topologyP2PConfig(topology, "fn-0", addrs),
+	)
+	require.Equal(t,
+		configutil.Toml{"persistent_peers": "", "seeds": "c@fn0:26656", "pex": true, "private_peer_ids": "a,b"},
+		topologyP2PConfig(topology, "fn-1", addrs),
+	)
+
+	// Nodes without an address, such as nodes not yet added, are left out.
+	delete(addrs, "val-1")
+	require.Equal(t, "a@val0:26656", topologyP2PConfig(topology, "fn-0", addrs)["persistent_peers"])
+}
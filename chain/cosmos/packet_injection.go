@@ -0,0 +1,147 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	clienttypes "github.com/cosmos/ibc-go/v6/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
+	host "github.com/cosmos/ibc-go/v6/modules/core/24-host"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// The helpers below build MsgRecvPacket and MsgAcknowledgement messages the way a relayer would,
+// so that tests can tamper with their packet data, acknowledgement or proof
+// and submit them directly to a chain with ExpectRejection, bypassing the relayer.
+// The relayer should be stopped while doing so, or it may relay the untampered messages first.
+
+// counterpartyClientHeight returns the latest height of the light client underlying channelID on portID,
+// which tracks the counterparty chain of the channel.
+func (c *CosmosChain) counterpartyClientHeight(ctx context.Context, portID, channelID string) (clienttypes.Height, error) {
+	channel, err := c.QueryChannel(ctx, portID, channelID)
+	if err != nil {
+		return clienttypes.Height{}, err
+	}
+	if len(channel.ConnectionHops) == 0 {
+		return clienttypes.Height{}, fmt.Errorf("channel %s/%s has no connection", portID, channelID)
+	}
+	conn, err := c.QueryConnection(ctx, channel.ConnectionHops[0])
+	if err != nil {
+		return clienttypes.Height{}, err
+	}
+	clientState, err := c.QueryClientState(ctx, conn.ClientId)
+	if err != nil {
+		return clienttypes.Height{}, err
+	}
+	h, ok := clientState.GetLatestHeight().(clienttypes.Height)
+	if !ok {
+		return clienttypes.Height{}, fmt.Errorf("unexpected height type %T of client %s", clientState.GetLatestHeight(), conn.ClientId)
+	}
+	return h, nil
+}
+
+// provenOnCounterparty queries the proof of key on c, at the latest height of the light client of c on counterparty,
+// so that the proof is verified against a consensus state counterparty already has.
+func (c *CosmosChain) provenOnCounterparty(ctx context.Context, counterparty *CosmosChain, portID, channelID string, key []byte) (value, proof []byte, proofHeight clienttypes.Height, err error) {
+	clientHeight, err := counterparty.counterpartyClientHeight(ctx, portID, channelID)
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, fmt.Errorf("failed to get client height on %s: %w", counterparty.cfg.ChainID, err)
+	}
+	if clientHeight.RevisionHeight < 2 {
+		return nil, nil, clienttypes.Height{}, fmt.Errorf("client on %s has not been updated past height %s", counterparty.cfg.ChainID, clientHeight)
+	}
+	value, proof, proofHeight, err = c.QueryProof(ctx, key, clientHeight.RevisionHeight-1)
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, err
+	}
+	if len(value) == 0 {
+		return nil, nil, clienttypes.Height{}, fmt.Errorf("%s not found on %s at height %d", key, c.cfg.ChainID, clientHeight.RevisionHeight-1)
+	}
+	return value, proof, proofHeight, nil
+}
+
+// NewMsgRecvPacket builds the MsgRecvPacket that relays packet, sent from src, to dst,
+// with a proof of the packet commitment on src at the latest height of dst's light client of src.
+// The packet must not have been received yet, and dst's light client must have been updated after the packet was sent.
+func NewMsgRecvPacket(ctx context.Context, src, dst *CosmosChain, packet ibc.Packet, signer string) (*chantypes.MsgRecvPacket, error) {
+	p, err := chanPacket(packet)
+	if err != nil {
+		return nil, err
+	}
+	key := host.PacketCommitmentKey(p.SourcePort, p.SourceChannel, p.Sequence)
+	_, proof, proofHeight, err := src.provenOnCounterparty(ctx, dst, p.DestinationPort, p.DestinationChannel, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove packet commitment: %w", err)
+	}
+	return chantypes.NewMsgRecvPacket(p, proof, proofHeight, signer), nil
+}
+
+// NewMsgAcknowledgement builds the MsgAcknowledgement that relays ack, the acknowledgement of packet written on dst, back to src,
+// with a proof of the acknowledgement on dst at the latest height of src's light client of dst.
+// A successful ICS-20 acknowledgement is chantypes.NewResultAcknowledgement([]byte{1}).Acknowledgement();
+// any other ack is rejected, as its commitment does not match the proof.
+func NewMsgAcknowledgement(ctx context.Context, src, dst *CosmosChain, packet ibc.Packet, ack []byte, signer string) (*chantypes.MsgAcknowledgement, error) {
+	p, err := chanPacket(packet)
+	if err != nil {
+		return nil, err
+	}
+	key := host.PacketAcknowledgementKey(p.DestinationPort, p.DestinationChannel, p.Sequence)
+	_, proof, proofHeight, err := dst.provenOnCounterparty(ctx, src, p.SourcePort, p.SourceChannel, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove acknowledgement: %w", err)
+	}
+	return chantypes.NewMsgAcknowledgement(p, ack, proof, proofHeight, signer), nil
+}
+
+// chanPacket converts packet to its ibc-go representation.
+func chanPacket(packet ibc.Packet) (chantypes.Packet, error) {
+	var timeoutHeight clienttypes.Height
+	if packet.TimeoutHeight != "" {
+		var err error
+		if timeoutHeight, err = clienttypes.ParseHeight(packet.TimeoutHeight); err != nil {
+			return chantypes.Packet{}, fmt.Errorf("invalid packet timeout height %q: %w", packet.TimeoutHeight, err)
+		}
+	}
+	return chantypes.NewPacket(
+		packet.Data, packet.Sequence,
+		packet.SourcePort, packet.SourceChannel,
+		packet.DestPort, packet.DestChannel,
+		timeoutHeight, uint64(packet.TimeoutTimestamp),
+	), nil
+}
+
+// CorruptProof returns a copy of proof with its last byte flipped,
+// so that it no longer proves the value it was queried for.
+// It returns an error if proof is empty, e.g. because it was queried at a pruned height.
+func CorruptProof(proof []byte) ([]byte, error) {
+	if len(proof) == 0 {
+		return nil, errors.New("cannot corrupt an empty proof")
+	}
+	corrupted := append([]byte(nil), proof...)
+	corrupted[len(corrupted)-1] ^= 0xff
+	return corrupted, nil
+}
+
+// ExpectRejection broadcasts msgs, such as tampered messages built with NewMsgRecvPacket or NewMsgAcknowledgement,
+// and returns nil only if they were rejected with want,
+// whether by their ValidateBasic before broadcasting or by the chain.
+// For example, a corrupted proof is rejected with commitmenttypes.ErrInvalidProof.
+func ExpectRejection(ctx context.Context, broadcaster *Broadcaster, user User, want *sdkerrors.Error, msgs ...sdk.Msg) error {
+	resp, err := BroadcastTx(ctx, broadcaster, user, msgs...)
+	if err != nil {
+		if errors.Is(err, want) {
+			return nil
+		}
+		return fmt.Errorf("expected rejection with %q, got: %w", want, err)
+	}
+	switch {
+	case resp.Code == 0:
+		return fmt.Errorf("expected rejection with %q, but tx %s was accepted", want, resp.TxHash)
+	case resp.Codespace != want.Codespace() || resp.Code != want.ABCICode():
+		return fmt.Errorf("expected rejection with %q (%s code %d), got %s code %d: %s", want, want.Codespace(), want.ABCICode(), resp.Codespace, resp.Code, resp.RawLog)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package cosmos
+
+import (
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v6/modules/core/02-client/types"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChanPacket(t *testing.T) {
+	t.Parallel()
+
+	packet := ibc.Packet{
+		Sequence:         3,
+		SourcePort:       "transfer",
+		SourceChannel:    "channel-0",
+		DestPort:         "transfer",
+		DestChannel:      "channel-1",
+		Data:             []byte(`{"amount":"1"}`),
+		TimeoutHeight:    "1-100",
+		TimeoutTimestamp: 5,
+	}
+	p, err := chanPacket(packet)
+	require.NoError(t, err)
+	require.NoError(t, p.ValidateBasic())
+	require.Equal(t, clienttypes.NewHeight(1, 100), p.TimeoutHeight)
+	require.Equal(t, uint64(5), p.TimeoutTimestamp)
+	require.Equal(t, "channel-1", p.DestinationChannel)
+
+	packet.TimeoutHeight = ""
+	p, err = chanPacket(packet)
+	require.NoError(t, err)
+	require.True(t, p.TimeoutHeight.IsZero())
+
+	packet.TimeoutHeight = "100"
+	_, err = chanPacket(packet)
+	require.Error(t, err)
+}
+
+func TestCorruptProof(t *testing.T) {
+	t.Parallel()
+
+	proof := []byte{1, 2, 3}
+	corrupted, err := CorruptProof(proof)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, proof, "proof must not be modified")
+	require.Equal(t, []byte{1, 2, 0xfc}, corrupted)
+
+	_, err = CorruptProof(nil)
+	require.EqualError(t, err, "cannot corrupt an empty proof")
+}
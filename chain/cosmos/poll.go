@@ -27,3 +27,32 @@ func PollForProposalStatus(ctx context.Context, chain *CosmosChain, startHeight,
 	}
 	return p.(ProposalResponse), nil
 }
+
+// PollForPacketEvent attempts to find an IBC packet event for which match returns true,
+// searching each block from startHeight through maxHeight as the chain produces it.
+func PollForPacketEvent(ctx context.Context, chain *CosmosChain, startHeight, maxHeight uint64, match func(PacketEvent) bool) (PacketEvent, error) {
+	var zero PacketEvent
+	doPoll := func(ctx context.Context, height uint64) (any, error) {
+		var (
+			found PacketEvent
+			ok    bool
+		)
+		err := chain.RangeBlockEvents(ctx, height, func(e PacketEvent) bool {
+			found, ok = e, match(e)
+			return ok
+		})
+		if err != nil {
+			return zero, err
+		}
+		if !ok {
+			return zero, fmt.Errorf("no matching packet event at height %d", height)
+		}
+		return found, nil
+	}
+	bp := test.BlockPoller{CurrentHeight: chain.Height, PollFunc: doPoll}
+	p, err := bp.DoPoll(ctx, startHeight, maxHeight)
+	if err != nil {
+		return zero, err
+	}
+	return p.(PacketEvent), nil
+}
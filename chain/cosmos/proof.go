@@ -0,0 +1,87 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+
+	clienttypes "github.com/cosmos/ibc-go/v6/modules/core/02-client/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v6/modules/core/23-commitment/types"
+	host "github.com/cosmos/ibc-go/v6/modules/core/24-host"
+	ibcexported "github.com/cosmos/ibc-go/v6/modules/core/exported"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+)
+
+// QueryProof returns the value of key in the IBC store as of height, and its proof encoded as an ICS-23 merkle proof.
+// If the key is set, the proof is a membership proof of the value;
+// otherwise the value is empty and the proof is a non-membership proof of the key.
+// A zero height queries the latest height.
+// The proof is verified against the app hash of the next block, which is returned as the proof height.
+func (c *CosmosChain) QueryProof(ctx context.Context, key []byte, height uint64) (value, proof []byte, proofHeight clienttypes.Height, err error) {
+	res, err := c.getFullNode().Client.ABCIQueryWithOptions(ctx, "store/"+host.StoreKey+"/key", key, rpcclient.ABCIQueryOptions{
+		Height: int64(height),
+		Prove:  true,
+	})
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, fmt.Errorf("failed to query proof of %s: %w", key, err)
+	}
+	if res.Response.Code != 0 {
+		return nil, nil, clienttypes.Height{}, fmt.Errorf("failed to query proof of %s: %s", key, res.Response.Log)
+	}
+
+	merkleProof, err := commitmenttypes.ConvertProofs(res.Response.ProofOps)
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, fmt.Errorf("failed to convert proof of %s: %w", key, err)
+	}
+	proof, err = c.cfg.EncodingConfig.Codec.Marshal(&merkleProof)
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, err
+	}
+
+	revision := clienttypes.ParseChainID(c.cfg.ChainID)
+	return res.Response.Value, proof, clienttypes.NewHeight(revision, uint64(res.Response.Height)+1), nil
+}
+
+// The proof queries below prove the IBC state at the ICS-24 paths of ibc-go, with QueryProof.
+
+// QueryClientStateProof proves the client state of clientID as of height.
+func (c *CosmosChain) QueryClientStateProof(ctx context.Context, clientID string, height uint64) (value, proof []byte, proofHeight clienttypes.Height, err error) {
+	return c.QueryProof(ctx, host.FullClientStateKey(clientID), height)
+}
+
+// QueryConsensusStateProof proves the consensus state of clientID at consensusHeight, as of height.
+func (c *CosmosChain) QueryConsensusStateProof(ctx context.Context, clientID string, consensusHeight ibcexported.Height, height uint64) (value, proof []byte, proofHeight clienttypes.Height, err error) {
+	return c.QueryProof(ctx, host.FullConsensusStateKey(clientID, consensusHeight), height)
+}
+
+// QueryConnectionProof proves the connection end of connectionID as of height.
+func (c *CosmosChain) QueryConnectionProof(ctx context.Context, connectionID string, height uint64) (value, proof []byte, proofHeight clienttypes.Height, err error) {
+	return c.QueryProof(ctx, host.ConnectionKey(connectionID), height)
+}
+
+// QueryChannelProof proves the channel end of channelID on portID as of height.
+func (c *CosmosChain) QueryChannelProof(ctx context.Context, portID, channelID string, height uint64) (value, proof []byte, proofHeight clienttypes.Height, err error) {
+	return c.QueryProof(ctx, host.ChannelKey(portID, channelID), height)
+}
+
+// QueryNextSequenceRecvProof proves the next sequence to be received on channelID as of height,
+// as needed to time out packets of ordered channels.
+func (c *CosmosChain) QueryNextSequenceRecvProof(ctx context.Context, portID, channelID string, height uint64) (value, proof []byte, proofHeight clienttypes.Height, err error) {
+	return c.QueryProof(ctx, host.NextSequenceRecvKey(portID, channelID), height)
+}
+
+// QueryPacketCommitmentProof proves the commitment of the packet with sequence sent on channelID as of height.
+func (c *CosmosChain) QueryPacketCommitmentProof(ctx context.Context, portID, channelID string, sequence, height uint64) (value, proof []byte, proofHeight clienttypes.Height, err error) {
+	return c.QueryProof(ctx, host.PacketCommitmentKey(portID, channelID, sequence), height)
+}
+
+// QueryPacketReceiptProof proves the receipt of the packet with sequence received on channelID as of height.
+// A non-membership proof proves that an unordered channel has not received the packet, as needed to time it out.
+func (c *CosmosChain) QueryPacketReceiptProof(ctx context.Context, portID, channelID string, sequence, height uint64) (value, proof []byte, proofHeight clienttypes.Height, err error) {
+	return c.QueryProof(ctx, host.PacketReceiptKey(portID, channelID, sequence), height)
+}
+
+// QueryPacketAcknowledgementProof proves the commitment of the acknowledgement written for the packet with sequence
+// received on channelID as of height.
+func (c *CosmosChain) QueryPacketAcknowledgementProof(ctx context.Context, portID, channelID string, sequence, height uint64) (value, proof []byte, proofHeight clienttypes.Height, err error) {
+	return c.QueryProof(ctx, host.PacketAcknowledgementKey(portID, channelID, sequence), height)
+}
@@ -0,0 +1,105 @@
+package cosmos
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	clienttypes "github.com/cosmos/ibc-go/v6/modules/core/02-client/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v6/modules/core/23-commitment/types"
+	host "github.com/cosmos/ibc-go/v6/modules/core/24-host"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	tmmock "github.com/tendermint/tendermint/rpc/client/mock"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	dbm "github.com/tendermint/tm-db"
+	"go.uber.org/zap"
+)
+
+// storeABCIClient answers ABCI store queries from an in-memory multistore with an IBC store,
+// as baseapp does for "store/" queries.
+type storeABCIClient struct {
+	rpcclient.ABCIClient
+
+	store *rootmulti.Store
+}
+
+func (c storeABCIClient) ABCIQueryWithOptions(ctx context.Context, path string, data bytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	height := opts.Height
+	if height == 0 {
+		height = c.store.LastCommitID().Version
+	}
+	res := c.store.Query(abci.RequestQuery{
+		Path:   "/" + strings.TrimPrefix(path, "store/"),
+		Data:   data,
+		Height: height,
+		Prove:  opts.Prove,
+	})
+	return &ctypes.ResultABCIQuery{Response: res}, nil
+}
+
+// newProofFixture returns a chain whose node queries a multistore with value set at key in the IBC store,
+// and the commit of the multistore.
+func newProofFixture(t *testing.T, key, value []byte) (*CosmosChain, storetypes.CommitID) {
+	t.Helper()
+
+	ms := rootmulti.NewStore(dbm.NewMemDB(), log.NewNopLogger())
+	storeKey := storetypes.NewKVStoreKey(host.StoreKey)
+	ms.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	ms.GetKVStore(storeKey).Set(key, value)
+	cid := ms.Commit()
+
+	c := NewCosmosChain(t.Name(), ibc.ChainConfig{ChainID: "gaia-2", Denom: "uatom"}, 1, 0, zap.NewNop())
+	c.Validators = ChainNodes{{Client: tmmock.Client{ABCIClient: storeABCIClient{store: ms}}}}
+	return c, cid
+}
+
+func TestCosmosChain_QueryProof(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key := host.FullClientStateKey("07-tendermint-0")
+	value := []byte("client state")
+	c, cid := newProofFixture(t, key, value)
+
+	root := commitmenttypes.NewMerkleRoot(cid.Hash)
+	specs := commitmenttypes.GetSDKSpecs()
+
+	t.Run("membership", func(t *testing.T) {
+		gotValue, proof, proofHeight, err := c.QueryProof(ctx, key, uint64(cid.Version))
+		require.NoError(t, err)
+		require.Equal(t, value, gotValue)
+		require.Equal(t, clienttypes.NewHeight(2, uint64(cid.Version)+1), proofHeight)
+
+		var merkleProof commitmenttypes.MerkleProof
+		require.NoError(t, c.Config().EncodingConfig.Codec.Unmarshal(proof, &merkleProof))
+		path := commitmenttypes.NewMerklePath(host.StoreKey, string(key))
+		require.NoError(t, merkleProof.VerifyMembership(specs, root, path, value))
+		require.Error(t, merkleProof.VerifyMembership(specs, root, path, []byte("other client state")))
+	})
+
+	t.Run("non-membership", func(t *testing.T) {
+		absent := host.FullClientStateKey("07-tendermint-1")
+		gotValue, proof, _, err := c.QueryClientStateProof(ctx, "07-tendermint-1", 0)
+		require.NoError(t, err)
+		require.Empty(t, gotValue)
+
+		var merkleProof commitmenttypes.MerkleProof
+		require.NoError(t, c.Config().EncodingConfig.Codec.Unmarshal(proof, &merkleProof))
+		require.NoError(t, merkleProof.VerifyNonMembership(specs, root, commitmenttypes.NewMerklePath(host.StoreKey, string(absent))))
+		require.Error(t, merkleProof.VerifyNonMembership(specs, root, commitmenttypes.NewMerklePath(host.StoreKey, string(key))))
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		_, _, _, err := c.QueryProof(ctx, key, uint64(cid.Version)+1)
+		require.ErrorContains(t, err, "failed to query proof of")
+	})
+}
@@ -0,0 +1,58 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	clienttypes "github.com/cosmos/ibc-go/v6/modules/core/02-client/types"
+	host "github.com/cosmos/ibc-go/v6/modules/core/24-host"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/configutil"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+)
+
+// defaultPruningInterval is the pruning-interval of nodes keeping the state of KeepRecent heights.
+const defaultPruningInterval = 10
+
+// pruningAppToml returns the app.toml settings that prune the state and blocks of a node as configured by p.
+func pruningAppToml(p ibc.Pruning) configutil.Toml {
+	a := make(configutil.Toml)
+	switch {
+	case p.Archive:
+		a["pruning"] = "nothing"
+		a["min-retain-blocks"] = 0
+		return a
+	case p.KeepRecent > 0:
+		interval := p.Interval
+		if interval == 0 {
+			interval = defaultPruningInterval
+		}
+		a["pruning"] = "custom"
+		a["pruning-keep-recent"] = fmt.Sprint(p.KeepRecent)
+		a["pruning-interval"] = fmt.Sprint(interval)
+	}
+	if p.KeepBlocks > 0 {
+		a["min-retain-blocks"] = p.KeepBlocks
+	}
+	return a
+}
+
+// QueryHistoricalState queries the IBC store of the node as of height,
+// returning an error if the node has pruned the state at height.
+// Implements test.HistoricalStateQuerier.
+func (tn *ChainNode) QueryHistoricalState(ctx context.Context, height uint64) error {
+	if height == 0 || height > math.MaxInt64 {
+		return fmt.Errorf("invalid height %d: must be positive and at most %d", height, int64(math.MaxInt64))
+	}
+	res, err := tn.Client.ABCIQueryWithOptions(ctx, "store/"+host.StoreKey+"/key", []byte(clienttypes.KeyNextClientSequence), rpcclient.ABCIQueryOptions{
+		Height: int64(height),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query state at height %d from %s: %w", height, tn.Name(), err)
+	}
+	if res.Response.Code != 0 {
+		return fmt.Errorf("failed to query state at height %d from %s: %s", height, tn.Name(), res.Response.Log)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package cosmos
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/configutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruningAppToml(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, pruningAppToml(ibc.Pruning{}))
+	require.Equal(t,
+		configutil.Toml{"pruning": "nothing", "min-retain-blocks": 0},
+		pruningAppToml(ibc.Pruning{Archive: true, KeepRecent: 100, KeepBlocks: 100}),
+	)
+	require.Equal(t,
+		configutil.Toml{"pruning": "custom", "pruning-keep-recent": "100", "pruning-interval": "10"},
+		pruningAppToml(ibc.Pruning{KeepRecent: 100}),
+	)
+	require.Equal(t,
+		configutil.Toml{"pruning": "custom", "pruning-keep-recent": "100", "pruning-interval": "5", "min-retain-blocks": uint64(200)},
+		pruningAppToml(ibc.Pruning{KeepRecent: 100, Interval: 5, KeepBlocks: 200}),
+	)
+
+	validators := ibc.Pruning{KeepRecent: 100}
+	cfg := ibc.ChainConfig{Pruning: validators, FullNodePruning: &ibc.Pruning{Archive: true}}
+	require.Equal(t, validators, cfg.NodePruning(true))
+	require.True(t, cfg.NodePruning(false).Archive)
+	require.Equal(t, validators, ibc.ChainConfig{Pruning: validators}.NodePruning(false))
+}
+
+func TestChainNode_QueryHistoricalState_InvalidHeight(t *testing.T) {
+	t.Parallel()
+
+	var tn ChainNode
+	require.ErrorContains(t, tn.QueryHistoricalState(context.Background(), 0), "invalid height 0")
+	require.ErrorContains(t, tn.QueryHistoricalState(context.Background(), math.MaxUint64), "invalid height")
+}
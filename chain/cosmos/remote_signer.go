@@ -0,0 +1,321 @@
+package cosmos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultHorcruxRepository = "ghcr.io/strangelove-ventures/horcrux"
+	defaultHorcruxVersion    = "v3.0.0"
+
+	// Where the signer's volume is mounted in its containers.
+	remoteSignerHome = "/signer"
+
+	// Port on which each horcrux cosigner listens for the other cosigners.
+	horcruxP2PPort = "2222"
+
+	// Address on which a validator listens for its remote signer.
+	privValLaddr = "tcp://0.0.0.0:1234"
+)
+
+// startRemoteSigners starts the remote signer of each validator, configured by signer.
+// The validators must not be started yet, as a validator only signs through the signers connected to it.
+func (c *CosmosChain) startRemoteSigners(ctx context.Context, signer ibc.RemoteSigner) error {
+	var eg errgroup.Group
+	for _, v := range c.Validators {
+		v := v
+		eg.Go(func() error {
+			if err := v.startRemoteSigner(ctx, signer); err != nil {
+				return fmt.Errorf("failed to start remote signer of %s: %w", v.Name(), err)
+			}
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// startRemoteSigner starts containers signing with the validator's key, connected to its priv-validator socket,
+// which are removed along with the test's other containers.
+func (tn *ChainNode) startRemoteSigner(ctx context.Context, signer ibc.RemoteSigner) error {
+	if !tn.Validator {
+		panic(fmt.Errorf("%s is not a validator", tn.Name()))
+	}
+
+	fr := dockerutil.NewFileRetriever(tn.logger(), tn.DockerClient, tn.TestName)
+	key, err := fr.SingleFileContent(ctx, tn.VolumeName, "config/priv_validator_key.json")
+	if err != nil {
+		return fmt.Errorf("getting priv_validator_key.json content: %w", err)
+	}
+
+	v, err := tn.DockerClient.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
+		Labels: dockerutil.VolumeLabels(tn.TestName, tn.signerName()),
+	})
+	if err != nil {
+		return fmt.Errorf("creating volume for remote signer: %w", err)
+	}
+
+	s := remoteSignerNode{
+		ChainNode:  tn,
+		signer:     signer,
+		volumeName: v.Name,
+		fw:         dockerutil.NewFileWriter(tn.logger(), tn.DockerClient, tn.TestName),
+	}
+	switch {
+	case signer.Type == ibc.RemoteSignerTMKMS:
+		return s.startTMKMS(ctx, key)
+	case signer.Shards > 0:
+		return s.startHorcruxCosigners(ctx, key)
+	default:
+		return s.startHorcrux(ctx, key)
+	}
+}
+
+// signerName returns the name of the validator's single remote signer,
+// which prefixes the names of its cosigners.
+func (tn *ChainNode) signerName() string {
+	return tn.Name() + "-signer"
+}
+
+// privValAddr returns the address of the validator's priv-validator socket, as dialed by its remote signer.
+func (tn *ChainNode) privValAddr() string {
+	return "tcp://" + tn.HostName() + ":" + strings.TrimSuffix(privValPort, "/tcp")
+}
+
+// remoteSignerNode starts the remote signer of a validator, whose files are in volumeName.
+type remoteSignerNode struct {
+	*ChainNode
+
+	signer     ibc.RemoteSigner
+	volumeName string
+	fw         *dockerutil.FileWriter
+}
+
+func (s remoteSignerNode) image() *dockerutil.Image {
+	repository, version := s.signer.Image.Repository, s.signer.Image.Version
+	if repository == "" {
+		repository, version = defaultHorcruxRepository, defaultHorcruxVersion
+	}
+	return dockerutil.NewImage(s.logger(), s.DockerClient, s.NetworkID, s.TestName, repository, version)
+}
+
+func (s remoteSignerNode) containerOptions(name string) dockerutil.ContainerOptions {
+	return dockerutil.ContainerOptions{
+		Binds:    []string{s.volumeName + ":" + remoteSignerHome},
+		User:     dockerutil.GetRootUserString(),
+		Platform: s.signer.Image.Platform,
+		Name:     name,
+	}
+}
+
+// start starts a signer container named name running cmd.
+func (s remoteSignerNode) start(ctx context.Context, name string, cmd ...string) (*dockerutil.Container, error) {
+	c, err := s.image().Start(ctx, cmd, s.containerOptions(name))
+	if err != nil {
+		return nil, err
+	}
+	s.logger().Info("Remote signer started",
+		zap.String("validator", s.Name()),
+		zap.String("container", name),
+		zap.String("type", s.signer.Type),
+	)
+	return c, nil
+}
+
+// run runs cmd to completion in a one-off signer container.
+func (s remoteSignerNode) run(ctx context.Context, cmd ...string) error {
+	res := s.image().Run(ctx, cmd, s.containerOptions(""))
+	if res.Err != nil {
+		return fmt.Errorf("%s: %w", strings.Join(cmd, " "), res.Err)
+	}
+	return nil
+}
+
+func (s remoteSignerNode) writeFile(ctx context.Context, relPath string, content []byte) error {
+	if err := s.fw.WriteFile(ctx, s.volumeName, relPath, content); err != nil {
+		return fmt.Errorf("writing %s: %w", relPath, err)
+	}
+	return nil
+}
+
+// startHorcrux starts a single horcrux signer holding the validator's key.
+func (s remoteSignerNode) startHorcrux(ctx context.Context, key []byte) error {
+	chainID := s.Chain.Config().ChainID
+	config, err := horcruxConfig(s.signer, nil, s.privValAddr())
+	if err != nil {
+		return err
+	}
+	if err := s.writeFile(ctx, "config.yaml", config); err != nil {
+		return err
+	}
+	if err := s.writeFile(ctx, chainID+"_priv_validator_key.json", key); err != nil {
+		return err
+	}
+	_, err = s.start(ctx, s.signerName(), "horcrux", "start", "--home", remoteSignerHome)
+	return err
+}
+
+// startHorcruxCosigners splits the validator's key into shards,
+// and starts a cluster of horcrux cosigners, each holding one shard.
+func (s remoteSignerNode) startHorcruxCosigners(ctx context.Context, key []byte) error {
+	chainID := s.Chain.Config().ChainID
+	keyFile := path.Join(remoteSignerHome, "priv_validator_key.json")
+	if err := s.writeFile(ctx, "priv_validator_key.json", key); err != nil {
+		return err
+	}
+	shards := fmt.Sprint(s.signer.Shards)
+	if err := s.run(ctx,
+		"horcrux", "create-ed25519-shards",
+		"--chain-id", chainID,
+		"--key-file", keyFile,
+		"--threshold", fmt.Sprint(s.signer.Threshold),
+		"--shards", shards,
+		"--out", remoteSignerHome,
+	); err != nil {
+		return err
+	}
+	if err := s.run(ctx, "horcrux", "create-ecies-shards", "--shards", shards, "--out", remoteSignerHome); err != nil {
+		return err
+	}
+
+	names := make([]string, s.signer.Shards)
+	hosts := make([]string, s.signer.Shards)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s-cosigner-%d", s.Name(), i+1)
+		hosts[i] = dockerutil.CondenseHostName(names[i])
+	}
+	config, err := horcruxConfig(s.signer, hosts, s.privValAddr())
+	if err != nil {
+		return err
+	}
+
+	s.cosigners = make([]*dockerutil.Container, len(names))
+	var eg errgroup.Group
+	for i, name := range names {
+		i, name := i, name
+		home := fmt.Sprintf("cosigner_%d", i+1)
+		eg.Go(func() (err error) {
+			if err := s.writeFile(ctx, path.Join(home, "config.yaml"), config); err != nil {
+				return err
+			}
+			s.cosigners[i], err = s.start(ctx, name, "horcrux", "start", "--home", path.Join(remoteSignerHome, home))
+			return err
+		})
+	}
+	return eg.Wait()
+}
+
+// startTMKMS starts tmkms with the validator's key imported into its softsign provider.
+func (s remoteSignerNode) startTMKMS(ctx context.Context, key []byte) error {
+	cfg := s.Chain.Config()
+	config, err := tmkmsConfig(cfg.ChainID, cfg.Bech32Prefix, s.privValAddr())
+	if err != nil {
+		return err
+	}
+	if err := s.writeFile(ctx, "tmkms.toml", config); err != nil {
+		return err
+	}
+	if err := s.writeFile(ctx, "priv_validator_key.json", key); err != nil {
+		return err
+	}
+	script := fmt.Sprintf(
+		"mkdir -p %[1]s/secrets %[1]s/state && "+
+			"tmkms softsign import %[1]s/priv_validator_key.json %[1]s/secrets/priv_validator_key && "+
+			"tmkms softsign keygen %[1]s/secrets/kms-identity.key && "+
+			"exec tmkms start -c %[1]s/tmkms.toml",
+		remoteSignerHome,
+	)
+	_, err = s.start(ctx, s.signerName(), "sh", "-c", script)
+	return err
+}
+
+// horcruxConfig returns the config.yaml of a horcrux signer dialing privValAddr:
+// a single signer if cosignerHosts is empty, or else a cosigner of the cluster on cosignerHosts.
+func horcruxConfig(signer ibc.RemoteSigner, cosignerHosts []string, privValAddr string) ([]byte, error) {
+	type cosigner struct {
+		ShardID int    `yaml:"shardID"`
+		P2PAddr string `yaml:"p2pAddr"`
+	}
+	type thresholdMode struct {
+		Threshold   int        `yaml:"threshold"`
+		Cosigners   []cosigner `yaml:"cosigners"`
+		GRPCTimeout string     `yaml:"grpcTimeout"`
+		RaftTimeout string     `yaml:"raftTimeout"`
+	}
+	type chainNode struct {
+		PrivValAddr string `yaml:"privValAddr"`
+	}
+	config := struct {
+		SignMode      string         `yaml:"signMode"`
+		ThresholdMode *thresholdMode `yaml:"thresholdMode,omitempty"`
+		ChainNodes    []chainNode    `yaml:"chainNodes"`
+	}{
+		SignMode:   "single",
+		ChainNodes: []chainNode{{PrivValAddr: privValAddr}},
+	}
+	if len(cosignerHosts) > 0 {
+		mode := &thresholdMode{
+			Threshold:   signer.Threshold,
+			GRPCTimeout: "1000ms",
+			RaftTimeout: "1000ms",
+		}
+		for i, host := range cosignerHosts {
+			mode.Cosigners = append(mode.Cosigners, cosigner{
+				ShardID: i + 1,
+				P2PAddr: "tcp://" + host + ":" + horcruxP2PPort,
+			})
+		}
+		config.SignMode, config.ThresholdMode = "threshold", mode
+	}
+	bz, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal horcrux config: %w", err)
+	}
+	return bz, nil
+}
+
+// tmkmsConfig returns the tmkms.toml of tmkms signing for chainID, whose keys have bech32Prefix, dialing privValAddr.
+func tmkmsConfig(chainID, bech32Prefix, privValAddr string) ([]byte, error) {
+	secrets, state := path.Join(remoteSignerHome, "secrets"), path.Join(remoteSignerHome, "state")
+	config := map[string]any{
+		"chain": []map[string]any{{
+			"id": chainID,
+			"key_format": map[string]any{
+				"type":                 "bech32",
+				"account_key_prefix":   bech32Prefix + "pub",
+				"consensus_key_prefix": bech32Prefix + "valconspub",
+			},
+			"state_file": path.Join(state, "priv_validator_state.json"),
+		}},
+		"providers": map[string]any{
+			"softsign": []map[string]any{{
+				"chain_ids": []string{chainID},
+				"key_type":  "consensus",
+				"path":      path.Join(secrets, "priv_validator_key"),
+			}},
+		},
+		"validator": []map[string]any{{
+			"chain_id":         chainID,
+			"addr":             privValAddr,
+			"secret_key":       path.Join(secrets, "kms-identity.key"),
+			"protocol_version": "v0.34",
+			"reconnect":        true,
+		}},
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(config); err != nil {
+		return nil, fmt.Errorf("failed to marshal tmkms config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,89 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHorcruxConfig(t *testing.T) {
+	t.Parallel()
+
+	signer := ibc.RemoteSigner{Type: ibc.RemoteSignerHorcrux, Shards: 2, Threshold: 2}
+	bz, err := horcruxConfig(signer, nil, "tcp://val-0:1234")
+	require.NoError(t, err)
+	require.YAMLEq(t, `
+signMode: single
+chainNodes:
+  - privValAddr: tcp://val-0:1234
+`, string(bz))
+
+	bz, err = horcruxConfig(signer, []string{"cosigner-1", "cosigner-2"}, "tcp://val-0:1234")
+	require.NoError(t, err)
+	require.YAMLEq(t, `
+signMode: threshold
+thresholdMode:
+  threshold: 2
+  cosigners:
+    - shardID: 1
+      p2pAddr: tcp://cosigner-1:2222
+    - shardID: 2
+      p2pAddr: tcp://cosigner-2:2222
+  grpcTimeout: 1000ms
+  raftTimeout: 1000ms
+chainNodes:
+  - privValAddr: tcp://val-0:1234
+`, string(bz))
+}
+
+func TestTMKMSConfig(t *testing.T) {
+	t.Parallel()
+
+	bz, err := tmkmsConfig("gaia-1", "cosmos", "tcp://val-0:1234")
+	require.NoError(t, err)
+
+	var config struct {
+		Chain []struct {
+			ID        string `toml:"id"`
+			KeyFormat struct {
+				Type               string `toml:"type"`
+				AccountKeyPrefix   string `toml:"account_key_prefix"`
+				ConsensusKeyPrefix string `toml:"consensus_key_prefix"`
+			} `toml:"key_format"`
+			StateFile string `toml:"state_file"`
+		} `toml:"chain"`
+		Providers struct {
+			Softsign []struct {
+				ChainIDs []string `toml:"chain_ids"`
+				KeyType  string   `toml:"key_type"`
+				Path     string   `toml:"path"`
+			} `toml:"softsign"`
+		} `toml:"providers"`
+		Validator []struct {
+			ChainID         string `toml:"chain_id"`
+			Addr            string `toml:"addr"`
+			SecretKey       string `toml:"secret_key"`
+			ProtocolVersion string `toml:"protocol_version"`
+			Reconnect       bool   `toml:"reconnect"`
+		} `toml:"validator"`
+	}
+	_, err = toml.Decode(string(bz), &config)
+	require.NoError(t, err)
+
+	require.Len(t, config.Chain, 1)
+	require.Equal(t, "gaia-1", config.Chain[0].ID)
+	require.Equal(t, "cosmospub", config.Chain[0].KeyFormat.AccountKeyPrefix)
+	require.Equal(t, "cosmosvalconspub", config.Chain[0].KeyFormat.ConsensusKeyPrefix)
+	require.Equal(t, "/signer/state/priv_validator_state.json", config.Chain[0].StateFile)
+
+	require.Len(t, config.Providers.Softsign, 1)
+	require.Equal(t, []string{"gaia-1"}, config.Providers.Softsign[0].ChainIDs)
+	require.Equal(t, "/signer/secrets/priv_validator_key", config.Providers.Softsign[0].Path)
+
+	require.Len(t, config.Validator, 1)
+	require.Equal(t, "tcp://val-0:1234", config.Validator[0].Addr)
+	require.Equal(t, "v0.34", config.Validator[0].ProtocolVersion)
+	require.True(t, config.Validator[0].Reconnect)
+}
@@ -0,0 +1,67 @@
+package cosmos
+
+import (
+	"context"
+	"sync"
+
+	"github.com/strangelove-ventures/ibctest/v6/test"
+)
+
+// maxSequenceRetries is how many times a transaction rejected for an account sequence mismatch is retried.
+const maxSequenceRetries = 3
+
+var _ test.KeySequencer = (*CosmosChain)(nil)
+
+// keyLocks serializes the transactions signed by each key of a chain.
+// The zero value is ready to use.
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock blocks until no other transaction from keyName is in flight, and returns the function to release the key.
+func (l *keyLocks) lock(keyName string) (unlock func()) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*sync.Mutex)
+	}
+	keyLock, ok := l.locks[keyName]
+	if !ok {
+		keyLock = new(sync.Mutex)
+		l.locks[keyName] = keyLock
+	}
+	l.mu.Unlock()
+
+	keyLock.Lock()
+	return keyLock.Unlock
+}
+
+// WithKeySequence runs tx, which signs and broadcasts a transaction from keyName, such as SendFunds,
+// while no other transaction from keyName is signed through WithKeySequence.
+// Each transaction then signs with the account sequence left by the previous one,
+// so that parallel test goroutines can safely share a funded account.
+//
+// If tx fails with an account sequence mismatch, e.g. because a transaction from keyName
+// was broadcast without WithKeySequence, it is retried after the next block, up to 3 times.
+//
+// SendFunds, SendIBCTransfer, SendIBCTransferBatch, and BroadcastTx already use WithKeySequence.
+func (c *CosmosChain) WithKeySequence(ctx context.Context, keyName string, tx func() error) error {
+	return withKeySequence(ctx, &c.keyLocks, keyName, tx, func(ctx context.Context) error {
+		return test.WaitForBlocks(ctx, 1, c)
+	})
+}
+
+func withKeySequence(ctx context.Context, locks *keyLocks, keyName string, tx func() error, waitForBlock func(context.Context) error) error {
+	unlock := locks.lock(keyName)
+	defer unlock()
+
+	for attempt := 0; ; attempt++ {
+		err := tx()
+		if err == nil || attempt == maxSequenceRetries || !test.IsSequenceMismatch(err) {
+			return err
+		}
+		if err := waitForBlock(ctx); err != nil {
+			return err
+		}
+	}
+}
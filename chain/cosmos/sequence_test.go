@@ -0,0 +1,93 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithKeySequence(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	noWait := func(context.Context) error { return nil }
+
+	t.Run("serializes each key", func(t *testing.T) {
+		var locks keyLocks
+		var mu sync.Mutex
+		inFlight := make(map[string]int)
+		maxInFlight := 0
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			keyName := fmt.Sprint("key", i%2)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.NoError(t, withKeySequence(ctx, &locks, keyName, func() error {
+					mu.Lock()
+					inFlight[keyName]++
+					if inFlight[keyName] > maxInFlight {
+						maxInFlight = inFlight[keyName]
+					}
+					mu.Unlock()
+
+					time.Sleep(time.Millisecond)
+
+					mu.Lock()
+					inFlight[keyName]--
+					mu.Unlock()
+					return nil
+				}, noWait))
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, 1, maxInFlight)
+	})
+
+	t.Run("retries sequence mismatches", func(t *testing.T) {
+		var locks keyLocks
+		attempts, waits := 0, 0
+		err := withKeySequence(ctx, &locks, "faucet", func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("transaction failed with code 32: account sequence mismatch, expected 7, got 6: incorrect account sequence")
+			}
+			return nil
+		}, func(context.Context) error {
+			waits++
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, attempts)
+		require.Equal(t, 2, waits)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		var locks keyLocks
+		attempts := 0
+		err := withKeySequence(ctx, &locks, "faucet", func() error {
+			attempts++
+			return fmt.Errorf("%w: expected 7, got 6", sdkerrors.ErrWrongSequence)
+		}, noWait)
+		require.ErrorIs(t, err, sdkerrors.ErrWrongSequence)
+		require.Equal(t, maxSequenceRetries+1, attempts)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		var locks keyLocks
+		attempts := 0
+		err := withKeySequence(ctx, &locks, "faucet", func() error {
+			attempts++
+			return errors.New("insufficient funds")
+		}, noWait)
+		require.EqualError(t, err, "insufficient funds")
+		require.Equal(t, 1, attempts)
+	})
+}
@@ -0,0 +1,99 @@
+package cosmos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+)
+
+type signingClient interface {
+	Block(ctx context.Context, height *int64) (*coretypes.ResultBlock, error)
+	Commit(ctx context.Context, height *int64) (*coretypes.ResultCommit, error)
+}
+
+// StopCosigner stops the horcrux cosigner holding shard, numbered from 1, of the key of the validator at index validator,
+// as if it crashed. The validator keeps signing through its other cosigners while at least the threshold of them remain.
+// The validator must have been started with a threshold ibc.RemoteSigner.
+func (c *CosmosChain) StopCosigner(ctx context.Context, validator, shard int) error {
+	if validator < 0 || validator >= len(c.Validators) {
+		return fmt.Errorf("no validator %d of %d validators", validator, len(c.Validators))
+	}
+	v := c.Validators[validator]
+	if shard < 1 || shard > len(v.cosigners) {
+		return fmt.Errorf("no shard %d of the %d cosigners of %s", shard, len(v.cosigners), v.Name())
+	}
+	cosigner := v.cosigners[shard-1]
+	if cosigner == nil {
+		return fmt.Errorf("cosigner of shard %d of %s is already stopped", shard, v.Name())
+	}
+	c.log.Info("Stopping cosigner", zap.String("validator", v.Name()), zap.String("container", cosigner.Name))
+	if err := cosigner.Stop(time.Second); err != nil {
+		return err
+	}
+	v.cosigners[shard-1] = nil
+	return nil
+}
+
+// VerifySigning returns an error if the validator at index validator double-signed,
+// as evidenced in the blocks from startHeight to endHeight,
+// or if it did not sign the commits of more than maxMissed of these heights.
+// Evidence is committed in a block after the double-sign, so endHeight should be a few blocks past the heights of interest.
+func (c *CosmosChain) VerifySigning(ctx context.Context, validator int, startHeight, endHeight uint64, maxMissed int) error {
+	if validator < 0 || validator >= len(c.Validators) {
+		return fmt.Errorf("no validator %d of %d validators", validator, len(c.Validators))
+	}
+	v := c.Validators[validator]
+	stat, err := v.Client.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get status of %s: %w", v.Name(), err)
+	}
+	return verifySigning(ctx, c.getFullNode().Client, stat.ValidatorInfo.Address, startHeight, endHeight, maxMissed)
+}
+
+func verifySigning(ctx context.Context, client signingClient, address tmtypes.Address, startHeight, endHeight uint64, maxMissed int) error {
+	var missed []int64
+	for h := int64(startHeight); h <= int64(endHeight); h++ {
+		h := h
+		block, err := client.Block(ctx, &h)
+		if err != nil {
+			return fmt.Errorf("failed to get block at height %d: %w", h, err)
+		}
+		for _, ev := range block.Block.Evidence.Evidence {
+			dup, ok := ev.(*tmtypes.DuplicateVoteEvidence)
+			if !ok || dup.VoteA == nil || !bytes.Equal(dup.VoteA.ValidatorAddress, address) {
+				continue
+			}
+			return fmt.Errorf("validator %s double-signed at height %d, as evidenced at height %d", address, dup.VoteA.Height, h)
+		}
+
+		commit, err := client.Commit(ctx, &h)
+		if err != nil {
+			return fmt.Errorf("failed to get commit at height %d: %w", h, err)
+		}
+		if !signedCommit(commit.Commit, address) {
+			missed = append(missed, h)
+		}
+	}
+	if len(missed) > maxMissed {
+		return fmt.Errorf("validator %s did not sign %d commits, more than %d, at heights %v", address, len(missed), maxMissed, missed)
+	}
+	return nil
+}
+
+// signedCommit reports whether address signed commit.
+func signedCommit(commit *tmtypes.Commit, address tmtypes.Address) bool {
+	if commit == nil {
+		return false
+	}
+	for _, sig := range commit.Signatures {
+		if sig.BlockIDFlag == tmtypes.BlockIDFlagCommit && bytes.Equal(sig.ValidatorAddress, address) {
+			return true
+		}
+	}
+	return false
+}
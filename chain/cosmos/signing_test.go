@@ -0,0 +1,53 @@
+package cosmos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// mockSigningClient serves blocks with evidence, and commits signed by signers, by height.
+type mockSigningClient struct {
+	evidence map[int64]tmtypes.EvidenceList
+	signers  map[int64][]tmtypes.Address
+}
+
+func (m mockSigningClient) Block(ctx context.Context, height *int64) (*coretypes.ResultBlock, error) {
+	return &coretypes.ResultBlock{Block: &tmtypes.Block{
+		Evidence: tmtypes.EvidenceData{Evidence: m.evidence[*height]},
+	}}, nil
+}
+
+func (m mockSigningClient) Commit(ctx context.Context, height *int64) (*coretypes.ResultCommit, error) {
+	commit := &tmtypes.Commit{Height: *height}
+	for _, addr := range m.signers[*height] {
+		commit.Signatures = append(commit.Signatures, tmtypes.CommitSig{BlockIDFlag: tmtypes.BlockIDFlagCommit, ValidatorAddress: addr})
+	}
+	return &coretypes.ResultCommit{SignedHeader: tmtypes.SignedHeader{Commit: commit}}, nil
+}
+
+func TestVerifySigning(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	val, other := tmtypes.Address("validator-address-0"), tmtypes.Address("validator-address-1")
+	client := mockSigningClient{signers: map[int64][]tmtypes.Address{
+		10: {val, other},
+		11: {other},
+		12: {val, other},
+	}}
+
+	require.NoError(t, verifySigning(ctx, client, val, 10, 12, 1))
+	require.ErrorContains(t, verifySigning(ctx, client, val, 10, 12, 0), "did not sign 1 commits, more than 0, at heights [11]")
+
+	client.evidence = map[int64]tmtypes.EvidenceList{
+		12: {&tmtypes.DuplicateVoteEvidence{VoteA: &tmtypes.Vote{Height: 11, ValidatorAddress: other}}},
+	}
+	require.NoError(t, verifySigning(ctx, client, val, 10, 12, 1))
+
+	client.evidence[12] = append(client.evidence[12], &tmtypes.DuplicateVoteEvidence{VoteA: &tmtypes.Vote{Height: 10, ValidatorAddress: val}})
+	require.ErrorContains(t, verifySigning(ctx, client, val, 10, 12, 1), "double-signed at height 10, as evidenced at height 12")
+}
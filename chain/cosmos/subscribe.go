@@ -0,0 +1,153 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	tmtypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+)
+
+// Queries for the Tendermint events most commonly subscribed to.
+// Tx events can be narrowed down by appending conditions on their ABCI events,
+// e.g. QueryTx + " AND transfer.recipient = 'cosmos1...'".
+var (
+	QueryNewBlock = types.EventQueryNewBlock.String()
+	QueryTx       = types.EventQueryTx.String()
+)
+
+// subscriberCounter generates unique subscriber names across chains.
+var subscriberCounter int64
+
+// HostWebsocketAddress returns the address of the full node's Tendermint RPC websocket accessible by the host,
+// which external tools may use to subscribe to events while a test runs.
+// This will not return a valid address until the chain has been started.
+func (c *CosmosChain) HostWebsocketAddress() string {
+	return "ws://" + c.getFullNode().hostRPCPort + "/websocket"
+}
+
+// Subscribe subscribes to the Tendermint events matching query through the full node's RPC websocket,
+// so that tests can react to events such as new blocks or transactions instead of polling.
+// See QueryNewBlock and QueryTx for common queries.
+//
+// Each call uses its own websocket connection.
+// The subscription ends, and the returned channel is closed, when ctx is done.
+func (c *CosmosChain) Subscribe(ctx context.Context, query string) (<-chan tmtypes.ResultEvent, error) {
+	addr := "tcp://" + c.getFullNode().hostRPCPort
+	client, err := rpchttp.New(addr, "/websocket")
+	if err != nil {
+		return nil, fmt.Errorf("create rpc client for %s: %w", addr, err)
+	}
+	if err := client.Start(); err != nil {
+		return nil, fmt.Errorf("start rpc websocket for %s: %w", addr, err)
+	}
+
+	return c.subscribe(ctx, client, query)
+}
+
+// eventsClient is the part of the Tendermint RPC client that Subscribe uses.
+type eventsClient interface {
+	rpcclient.EventsClient
+	Start() error
+	Stop() error
+}
+
+// subscribe subscribes to the events matching query with the started client,
+// and stops the client when ctx is done.
+func (c *CosmosChain) subscribe(ctx context.Context, client eventsClient, query string) (<-chan tmtypes.ResultEvent, error) {
+	subscriber := fmt.Sprintf("ibctest-%d", atomic.AddInt64(&subscriberCounter, 1))
+	in, err := client.Subscribe(ctx, subscriber, query)
+	if err != nil {
+		_ = client.Stop()
+		return nil, fmt.Errorf("subscribe to %q: %w", query, err)
+	}
+
+	// The client does not close its channel when unsubscribing, so forward events to a channel that is closed.
+	out := make(chan tmtypes.ResultEvent)
+	go func() {
+		defer close(out)
+		defer func() {
+			if err := client.UnsubscribeAll(context.Background(), subscriber); err != nil {
+				c.log.Debug("Failed to unsubscribe from events", zap.String("query", query), zap.Error(err))
+			}
+			_ = client.Stop()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// SubscribeNewBlocks returns the blocks committed by the chain until ctx is done.
+func (c *CosmosChain) SubscribeNewBlocks(ctx context.Context) (<-chan *types.Block, error) {
+	events, err := c.Subscribe(ctx, QueryNewBlock)
+	if err != nil {
+		return nil, err
+	}
+	return newBlocks(ctx, events), nil
+}
+
+// newBlocks returns the blocks of the new block events, until events is closed or ctx is done.
+func newBlocks(ctx context.Context, events <-chan tmtypes.ResultEvent) <-chan *types.Block {
+	blocks := make(chan *types.Block)
+	go func() {
+		defer close(blocks)
+		for ev := range events {
+			data, ok := ev.Data.(types.EventDataNewBlock)
+			if !ok {
+				continue
+			}
+			select {
+			case blocks <- data.Block:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return blocks
+}
+
+// SubscribeHeights returns the heights of the blocks committed by the chain until ctx is done.
+// Implements test.HeightSubscriber.
+func (c *CosmosChain) SubscribeHeights(ctx context.Context) (<-chan uint64, error) {
+	blocks, err := c.SubscribeNewBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return blockHeights(ctx, blocks), nil
+}
+
+// blockHeights returns the heights of blocks, until blocks is closed or ctx is done.
+func blockHeights(ctx context.Context, blocks <-chan *types.Block) <-chan uint64 {
+	heights := make(chan uint64)
+	go func() {
+		defer close(heights)
+		for b := range blocks {
+			select {
+			case heights <- uint64(b.Height):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return heights
+}
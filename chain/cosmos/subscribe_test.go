@@ -0,0 +1,152 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	tmtypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+	"go.uber.org/zap"
+)
+
+// fakeEventsClient sends the events written to Events to its subscriber.
+type fakeEventsClient struct {
+	rpcclient.EventsClient
+
+	Events       chan tmtypes.ResultEvent
+	SubscribeErr error
+
+	mu           sync.Mutex
+	query        string
+	unsubscribed []string
+	stopped      bool
+}
+
+func newFakeEventsClient() *fakeEventsClient {
+	return &fakeEventsClient{Events: make(chan tmtypes.ResultEvent)}
+}
+
+func (c *fakeEventsClient) Subscribe(ctx context.Context, subscriber, query string, outCapacity ...int) (<-chan tmtypes.ResultEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.query = query
+	return c.Events, c.SubscribeErr
+}
+
+func (c *fakeEventsClient) UnsubscribeAll(ctx context.Context, subscriber string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unsubscribed = append(c.unsubscribed, subscriber)
+	return nil
+}
+
+func (c *fakeEventsClient) Start() error { return nil }
+
+func (c *fakeEventsClient) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopped = true
+	return nil
+}
+
+func (c *fakeEventsClient) Stopped() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stopped
+}
+
+func newBlockEvent(height int64) tmtypes.ResultEvent {
+	return tmtypes.ResultEvent{
+		Query: QueryNewBlock,
+		Data:  types.EventDataNewBlock{Block: &types.Block{Header: types.Header{Height: height}}},
+	}
+}
+
+func TestCosmosChain_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	c := NewCosmosChain(t.Name(), ibc.ChainConfig{ChainID: "gaia-1", Denom: "uatom"}, 1, 0, zap.NewNop())
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		client := newFakeEventsClient()
+		events, err := c.subscribe(ctx, client, QueryTx)
+		require.NoError(t, err)
+		require.Equal(t, QueryTx, client.query)
+
+		client.Events <- tmtypes.ResultEvent{Query: QueryTx}
+		require.Equal(t, QueryTx, (<-events).Query)
+
+		cancel()
+		_, ok := <-events
+		require.False(t, ok)
+		require.True(t, client.Stopped())
+		require.Len(t, client.unsubscribed, 1)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		client := newFakeEventsClient()
+		events, err := c.subscribe(context.Background(), client, QueryTx)
+		require.NoError(t, err)
+
+		close(client.Events)
+		_, ok := <-events
+		require.False(t, ok)
+		require.True(t, client.Stopped())
+	})
+
+	t.Run("subscribe error", func(t *testing.T) {
+		client := newFakeEventsClient()
+		client.SubscribeErr = errors.New("subscription limit reached")
+		_, err := c.subscribe(context.Background(), client, QueryTx)
+		require.EqualError(t, err, `subscribe to "`+QueryTx+`": subscription limit reached`)
+		require.True(t, client.Stopped())
+		require.Empty(t, client.unsubscribed)
+	})
+}
+
+func TestBlockHeights(t *testing.T) {
+	t.Parallel()
+
+	t.Run("streams heights", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		client := newFakeEventsClient()
+		c := NewCosmosChain(t.Name(), ibc.ChainConfig{ChainID: "gaia-1", Denom: "uatom"}, 1, 0, zap.NewNop())
+		events, err := c.subscribe(ctx, client, QueryNewBlock)
+		require.NoError(t, err)
+		heights := blockHeights(ctx, newBlocks(ctx, events))
+
+		client.Events <- newBlockEvent(5)
+		require.Equal(t, uint64(5), <-heights)
+
+		// Events of other types are skipped.
+		client.Events <- tmtypes.ResultEvent{Query: QueryTx, Data: types.EventDataTx{}}
+		client.Events <- newBlockEvent(6)
+		require.Equal(t, uint64(6), <-heights)
+
+		cancel()
+		for range heights {
+		}
+		require.Eventually(t, client.Stopped, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("blocks closed", func(t *testing.T) {
+		events := make(chan tmtypes.ResultEvent, 2)
+		events <- newBlockEvent(7)
+		events <- newBlockEvent(8)
+		close(events)
+
+		blocks := newBlocks(context.Background(), events)
+		var got []uint64
+		for h := range blockHeights(context.Background(), blocks) {
+			got = append(got, h)
+		}
+		require.Equal(t, []uint64{7, 8}, got)
+	})
+}
@@ -0,0 +1,118 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/strangelove-ventures/ibctest/v6/test"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUpgradeHaltHeightDelta    = 10
+	defaultUpgradeBlocksAfterUpgrade = 5
+)
+
+// UpgradeConfig describes a software upgrade of a chain through governance, see CosmosChain.Upgrade.
+type UpgradeConfig struct {
+	// Key name of the account submitting the proposal, which pays its deposit.
+	KeyName string
+
+	// Name of the upgrade, as registered by an upgrade handler of the new version.
+	Name string
+
+	// Docker image version that the chain's nodes run after the upgrade.
+	Version string
+
+	// Optional. Deposit of the proposal. Defaults to 500000000 of the chain's denom.
+	Deposit string
+
+	// Optional. How many blocks after the proposal is submitted the chain halts for the upgrade. Defaults to 10.
+	// The proposal must pass before then, so the chain's voting period must be short,
+	// e.g. through a ModifyGenesis function such as ModifyGenesisWasmClients.
+	HaltHeightDelta uint64
+
+	// Optional. How many blocks the upgraded chain must produce. Defaults to 5.
+	BlocksAfterUpgrade int
+}
+
+// Upgrade upgrades the chain to cfg.Version through a software upgrade proposal:
+// it submits the proposal, votes yes from every validator, waits for the chain to halt at the upgrade height,
+// restarts every node with the new version, and waits for the upgraded chain to produce blocks.
+func (c *CosmosChain) Upgrade(ctx context.Context, cli *client.Client, cfg UpgradeConfig) error {
+	if cfg.Name == "" || cfg.Version == "" {
+		return errors.New("upgrade needs a name and a version")
+	}
+	haltDelta := cfg.HaltHeightDelta
+	if haltDelta == 0 {
+		haltDelta = defaultUpgradeHaltHeightDelta
+	}
+	blocksAfter := cfg.BlocksAfterUpgrade
+	if blocksAfter == 0 {
+		blocksAfter = defaultUpgradeBlocksAfterUpgrade
+	}
+	deposit := cfg.Deposit
+	if deposit == "" {
+		deposit = "500000000" + c.cfg.Denom
+	}
+
+	height, err := c.Height(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get height before upgrade proposal: %w", err)
+	}
+	haltHeight := height + haltDelta
+
+	tx, err := c.UpgradeProposal(ctx, cfg.KeyName, SoftwareUpgradeProposal{
+		Deposit:     deposit,
+		Title:       "Upgrade to " + cfg.Name,
+		Name:        cfg.Name,
+		Description: fmt.Sprintf("Software upgrade %s to version %s", cfg.Name, cfg.Version),
+		Height:      haltHeight,
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.VoteOnProposalAllValidators(ctx, tx.ProposalID, ProposalVoteYes); err != nil {
+		return fmt.Errorf("failed to vote on upgrade proposal: %w", err)
+	}
+	if _, err := PollForProposalStatus(ctx, c, height, haltHeight, tx.ProposalID, ProposalStatusPassed); err != nil {
+		return fmt.Errorf("upgrade proposal %s did not pass before the upgrade height %d: %w", tx.ProposalID, haltHeight, err)
+	}
+
+	if height, err = c.Height(ctx); err != nil {
+		return fmt.Errorf("failed to get height before upgrade: %w", err)
+	}
+	// The chain halts at the upgrade height, so waiting for the block after it times out.
+	haltCtx, cancel := context.WithTimeout(ctx, time.Duration(haltHeight-height+5)*blockTime*time.Second)
+	_ = test.WaitForBlocks(haltCtx, int(haltHeight-height)+1, c)
+	cancel()
+	if height, err = c.Height(ctx); err != nil {
+		return fmt.Errorf("failed to get height at upgrade: %w", err)
+	}
+	if height != haltHeight {
+		return fmt.Errorf("chain is at height %d, want it halted at the upgrade height %d", height, haltHeight)
+	}
+
+	c.log.Info(
+		"Upgrading chain",
+		zap.String("chain_id", c.cfg.ChainID),
+		zap.String("upgrade", cfg.Name),
+		zap.String("version", cfg.Version),
+		zap.Uint64("height", haltHeight),
+	)
+	if err := c.StopAllNodes(ctx); err != nil {
+		return fmt.Errorf("failed to stop nodes for upgrade: %w", err)
+	}
+	c.UpgradeVersion(ctx, cli, cfg.Version)
+	if err := c.StartAllNodes(ctx); err != nil {
+		return fmt.Errorf("failed to start upgraded nodes: %w", err)
+	}
+
+	if err := test.WaitForBlocks(ctx, blocksAfter, c); err != nil {
+		return fmt.Errorf("upgraded chain did not produce blocks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package cosmos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+	tmmock "github.com/tendermint/tendermint/rpc/client/mock"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"go.uber.org/zap"
+)
+
+type statusErrClient struct{}
+
+func (statusErrClient) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestCosmosChain_Upgrade(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := NewCosmosChain(t.Name(), ibc.ChainConfig{ChainID: "gaia-1", Denom: "uatom"}, 1, 0, zap.NewNop())
+	c.Validators = ChainNodes{{Client: tmmock.Client{StatusClient: statusErrClient{}}}}
+
+	for _, cfg := range []UpgradeConfig{
+		{Version: "v8.0.0"},
+		{Name: "v8"},
+	} {
+		require.EqualError(t, c.Upgrade(ctx, nil, cfg), "upgrade needs a name and a version")
+	}
+
+	err := c.Upgrade(ctx, nil, UpgradeConfig{KeyName: "validator", Name: "v8", Version: "v8.0.0"})
+	require.ErrorContains(t, err, "failed to get height before upgrade proposal")
+	require.ErrorContains(t, err, "connection refused")
+}
@@ -0,0 +1,71 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/icza/dyno"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// WasmClientType is the client type of light clients implemented as wasm contracts, by ibc-go's 08-wasm module.
+const WasmClientType = "08-wasm"
+
+// ModifyGenesisWasmClients returns a ChainConfig.ModifyGenesis function for ibc-go simd built with the 08-wasm module.
+// It allows wasm light clients, whose code is stored through governance proposals,
+// and shortens the deposit and voting periods of proposals to votingPeriod, e.g. "10s",
+// so that tests can store light client code, such as for GRANDPA clients of polkadot chains, without waiting days.
+func ModifyGenesisWasmClients(votingPeriod string) func(ibc.ChainConfig, []byte) ([]byte, error) {
+	return func(cfg ibc.ChainConfig, genbz []byte) ([]byte, error) {
+		g := make(map[string]interface{})
+		if err := json.Unmarshal(genbz, &g); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal genesis file: %w", err)
+		}
+
+		allowedClientsPath := []interface{}{"app_state", "ibc", "client_genesis", "params", "allowed_clients"}
+		allowedClients, err := dyno.GetSlice(g, allowedClientsPath...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get allowed clients from genesis: %w", err)
+		}
+		if !containsValue(allowedClients, WasmClientType) {
+			allowedClients = append(allowedClients, WasmClientType)
+		}
+		if err := dyno.Set(g, allowedClients, allowedClientsPath...); err != nil {
+			return nil, fmt.Errorf("failed to set allowed clients in genesis: %w", err)
+		}
+
+		// Governance parameters are in a single params object since cosmos-sdk v0.47, and split up before.
+		votingPeriodPath := []interface{}{"app_state", "gov", "voting_params", "voting_period"}
+		depositPeriodPath := []interface{}{"app_state", "gov", "deposit_params", "max_deposit_period"}
+		minDepositPath := []interface{}{"app_state", "gov", "deposit_params", "min_deposit", 0, "denom"}
+		if _, err := dyno.Get(g, "app_state", "gov", "params"); err == nil {
+			votingPeriodPath = []interface{}{"app_state", "gov", "params", "voting_period"}
+			depositPeriodPath = []interface{}{"app_state", "gov", "params", "max_deposit_period"}
+			minDepositPath = []interface{}{"app_state", "gov", "params", "min_deposit", 0, "denom"}
+		}
+		if err := dyno.Set(g, votingPeriod, votingPeriodPath...); err != nil {
+			return nil, fmt.Errorf("failed to set voting period in genesis: %w", err)
+		}
+		if err := dyno.Set(g, votingPeriod, depositPeriodPath...); err != nil {
+			return nil, fmt.Errorf("failed to set max deposit period in genesis: %w", err)
+		}
+		if err := dyno.Set(g, cfg.Denom, minDepositPath...); err != nil {
+			return nil, fmt.Errorf("failed to set min deposit denom in genesis: %w", err)
+		}
+
+		out, err := json.Marshal(g)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal genesis: %w", err)
+		}
+		return out, nil
+	}
+}
+
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifyGenesisWasmClients(t *testing.T) {
+	t.Parallel()
+
+	modify := ModifyGenesisWasmClients("10s")
+	cfg := ibc.ChainConfig{Denom: "stake"}
+
+	t.Run("split gov params", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := modify(cfg, []byte(`{"app_state": {
+			"ibc": {"client_genesis": {"params": {"allowed_clients": ["06-solomachine", "07-tendermint"]}}},
+			"gov": {
+				"deposit_params": {"min_deposit": [{"denom": "token", "amount": "1"}], "max_deposit_period": "172800s"},
+				"voting_params": {"voting_period": "172800s"}
+			}
+		}}`))
+		require.NoError(t, err)
+
+		var g struct {
+			AppState struct {
+				IBC struct {
+					ClientGenesis struct {
+						Params struct {
+							AllowedClients []string `json:"allowed_clients"`
+						} `json:"params"`
+					} `json:"client_genesis"`
+				} `json:"ibc"`
+				Gov struct {
+					DepositParams struct {
+						MinDeposit       []struct{ Denom string } `json:"min_deposit"`
+						MaxDepositPeriod string                   `json:"max_deposit_period"`
+					} `json:"deposit_params"`
+					VotingParams struct {
+						VotingPeriod string `json:"voting_period"`
+					} `json:"voting_params"`
+				} `json:"gov"`
+			} `json:"app_state"`
+		}
+		require.NoError(t, json.Unmarshal(out, &g))
+		require.Equal(t, []string{"06-solomachine", "07-tendermint", "08-wasm"}, g.AppState.IBC.ClientGenesis.Params.AllowedClients)
+		require.Equal(t, "10s", g.AppState.Gov.VotingParams.VotingPeriod)
+		require.Equal(t, "10s", g.AppState.Gov.DepositParams.MaxDepositPeriod)
+		require.Equal(t, "stake", g.AppState.Gov.DepositParams.MinDeposit[0].Denom)
+
+		// Modifying again does not allow the wasm client type twice.
+		again, err := modify(cfg, out)
+		require.NoError(t, err)
+		require.JSONEq(t, string(out), string(again))
+	})
+
+	t.Run("gov params", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := modify(cfg, []byte(`{"app_state": {
+			"ibc": {"client_genesis": {"params": {"allowed_clients": ["07-tendermint"]}}},
+			"gov": {"params": {"min_deposit": [{"denom": "token", "amount": "1"}], "max_deposit_period": "172800s", "voting_period": "172800s"}}
+		}}`))
+		require.NoError(t, err)
+		require.Contains(t, string(out), `"voting_period":"10s"`)
+		require.Contains(t, string(out), `"max_deposit_period":"10s"`)
+		require.NotContains(t, string(out), "voting_params")
+	})
+}
@@ -11,13 +11,17 @@ import (
 	"time"
 
 	"github.com/avast/retry-go/v4"
+	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	dockerclient "github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-connections/nat"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/internal/configutil"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/strangelove-ventures/ibctest/v6/transcript"
 	tmjson "github.com/tendermint/tendermint/libs/json"
 	"github.com/tendermint/tendermint/p2p"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
@@ -39,6 +43,9 @@ type TendermintNode struct {
 	TestName     string
 	Image        ibc.DockerImage
 
+	// Optional. Restarts the node's container if it crashes.
+	Supervisor *dockerutil.Supervisor
+
 	containerID string
 }
 
@@ -193,26 +200,41 @@ func (tn *TendermintNode) InitHomeFolder(ctx context.Context, mode string) error
 func (tn *TendermintNode) CreateNodeContainer(ctx context.Context, additionalFlags ...string) error {
 	chainCfg := tn.Chain.Config()
 	cmd := []string{chainCfg.Bin, "start", "--home", tn.HomeDir()}
+	if chainCfg.LogLevel != "" {
+		cmd = append(cmd, "--log_level", chainCfg.LogLevel)
+	}
 	cmd = append(cmd, additionalFlags...)
-	fmt.Printf("{%s} -> '%s'\n", tn.Name(), strings.Join(cmd, " "))
+	start := chainCfg.StartCommand(cmd)
+	fmt.Printf("{%s} -> '%s' %v\n", tn.Name(), strings.Join(start.Cmd, " "), start.Env)
+	testreporter.TrackNodeCommand(ctx, tn.Name(), start.Cmd, start.Env)
 
+	platform, err := dockerutil.ParsePlatform(tn.Image.Platform)
+	if err != nil {
+		return err
+	}
+	publishAll, portBindings, err := dockerutil.PortPublishing(tn.Chain.Config().HostPorts).ReserveHostConfig(tn.Name(), sentryPorts)
+	if err != nil {
+		return err
+	}
 	cc, err := tn.DockerClient.ContainerCreate(
 		ctx,
 		&container.Config{
 			Image: tn.Image.Ref(),
 
 			Entrypoint: []string{},
-			Cmd:        cmd,
+			Cmd:        start.Cmd,
+			Env:        start.Env,
 
 			Hostname: tn.HostName(),
 
-			Labels: map[string]string{dockerutil.CleanupLabel: tn.TestName},
+			Labels: dockerutil.Labels(tn.TestName),
 
 			ExposedPorts: sentryPorts,
 		},
 		&container.HostConfig{
 			Binds:           tn.Bind(),
-			PublishAllPorts: true,
+			PublishAllPorts: publishAll,
+			PortBindings:    portBindings,
 			AutoRemove:      false,
 			DNS:             []string{},
 		},
@@ -221,10 +243,11 @@ func (tn *TendermintNode) CreateNodeContainer(ctx context.Context, additionalFla
 				tn.NetworkID: {},
 			},
 		},
-		nil,
+		platform,
 		tn.Name(),
 	)
 	if err != nil {
+		dockerutil.ReleaseHostPorts(tn.Name())
 		return err
 	}
 	tn.containerID = cc.ID
@@ -232,21 +255,37 @@ func (tn *TendermintNode) CreateNodeContainer(ctx context.Context, additionalFla
 }
 
 func (tn *TendermintNode) StopContainer(ctx context.Context) error {
-	timeout := 30 * time.Second
-	return tn.DockerClient.ContainerStop(ctx, tn.containerID, &timeout)
+	return dockerutil.StopContainer(ctx, tn.DockerClient, tn.containerID)
+}
+
+// RemoveContainer removes the tendermint node container and releases its reserved host ports.
+func (tn *TendermintNode) RemoveContainer(ctx context.Context) error {
+	err := tn.DockerClient.ContainerRemove(ctx, tn.containerID, dockertypes.ContainerRemoveOptions{
+		Force:         true,
+		RemoveVolumes: true,
+	})
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("remove container %s: %w", tn.Name(), err)
+	}
+	dockerutil.ReleaseHostPorts(tn.Name())
+	return nil
 }
 
 func (tn *TendermintNode) StartContainer(ctx context.Context) error {
 	if err := dockerutil.StartContainer(ctx, tn.DockerClient, tn.containerID); err != nil {
 		return err
 	}
+	if tn.Supervisor != nil {
+		tn.Supervisor.Watch(tn.containerID, tn.Name())
+	}
 
-	c, err := tn.DockerClient.ContainerInspect(ctx, tn.containerID)
+	publishing := dockerutil.PortPublishing(tn.Chain.Config().HostPorts)
+	hostPorts, err := dockerutil.ResolveHostPorts(ctx, tn.DockerClient, tn.containerID, tn.HostName(), publishing, rpcPort)
 	if err != nil {
 		return err
 	}
 
-	port := dockerutil.GetHostPort(c, rpcPort)
+	port := hostPorts[0]
 	fmt.Printf("{%s} RPC => %s\n", tn.Name(), port)
 
 	err = tn.NewClient(fmt.Sprintf("tcp://%s", port))
@@ -333,10 +372,12 @@ func (tn TendermintNodes) LogGenesisHashes(ctx context.Context) error {
 }
 
 func (tn *TendermintNode) Exec(ctx context.Context, cmd []string, env []string) ([]byte, []byte, error) {
-	job := dockerutil.NewImage(tn.Log, tn.DockerClient, tn.NetworkID, tn.TestName, tn.Image.Repository, tn.Image.Version)
+	transcript.RecordChainExec(ctx, tn.Chain.Config().ChainID, tn.Name(), cmd, env)
+	job := dockerutil.NewImage(tn.Log, tn.DockerClient, tn.NetworkID, tn.TestName, tn.Image.Repository, tn.Image.Tag())
 	opts := dockerutil.ContainerOptions{
-		Env:   env,
-		Binds: tn.Bind(),
+		Env:      env,
+		Binds:    tn.Bind(),
+		Platform: tn.Image.Platform,
 	}
 	res := job.Run(ctx, cmd, opts)
 	return res.Stdout, res.Stderr, res.Err
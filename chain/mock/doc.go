@@ -0,0 +1,4 @@
+// Package mock provides an in-memory implementation of ibc.Chain that does not require docker,
+// for unit testing code built on ibc.Chain, such as the helpers in the test package,
+// and for prototyping tests offline.
+package mock
@@ -0,0 +1,327 @@
+package mock
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/docker/docker/client"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+var _ ibc.Chain = (*Chain)(nil)
+
+// errNotSupported is returned by the methods of Chain that have no meaningful in-memory behavior.
+var errNotSupported = errors.New("not supported by mock chain")
+
+// Chain is an in-memory ibc.Chain.
+//
+// Each call to Height advances the chain by one block, so that helpers waiting for blocks make progress.
+// Keys, balances, acknowledgements, and timeouts are held in memory and can be scripted
+// through the Set and Add methods, or a behavior can be replaced entirely by setting
+// the corresponding Func field before the chain is used.
+type Chain struct {
+	// HeightFunc, if set, replaces the default behavior of Height.
+	HeightFunc func(ctx context.Context) (uint64, error)
+	// AcknowledgementsFunc, if set, replaces the default behavior of Acknowledgements.
+	AcknowledgementsFunc func(ctx context.Context, height uint64) ([]ibc.PacketAcknowledgement, error)
+	// TimeoutsFunc, if set, replaces the default behavior of Timeouts.
+	TimeoutsFunc func(ctx context.Context, height uint64) ([]ibc.PacketTimeout, error)
+	// SendIBCTransferFunc, if set, replaces the default behavior of SendIBCTransfer.
+	SendIBCTransferFunc func(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (ibc.Tx, error)
+	// ExecFunc, if set, is called by Exec, which otherwise returns an error.
+	ExecFunc func(ctx context.Context, cmd []string, env []string) (stdout, stderr []byte, err error)
+
+	cfg ibc.ChainConfig
+
+	mu       sync.Mutex
+	height   uint64
+	keys     map[string][]byte
+	balances map[string]map[string]math.Int // Keyed by bech32 address, then denom.
+	acks     map[uint64][]ibc.PacketAcknowledgement
+	timeouts map[uint64][]ibc.PacketTimeout
+	txs      uint64
+	seqs     map[string]uint64 // Next packet sequence, keyed by source channel.
+}
+
+// NewChain returns a mock chain with the given config at height 1.
+func NewChain(cfg ibc.ChainConfig) *Chain {
+	return &Chain{
+		cfg:      cfg,
+		height:   1,
+		keys:     make(map[string][]byte),
+		balances: make(map[string]map[string]math.Int),
+		acks:     make(map[uint64][]ibc.PacketAcknowledgement),
+		timeouts: make(map[uint64][]ibc.PacketTimeout),
+		seqs:     make(map[string]uint64),
+	}
+}
+
+// Config implements ibc.Chain.
+func (c *Chain) Config() ibc.ChainConfig {
+	return c.cfg
+}
+
+// Initialize implements ibc.Chain. It does nothing.
+func (c *Chain) Initialize(ctx context.Context, testName string, cli *client.Client, networkID string) error {
+	return nil
+}
+
+// Start implements ibc.Chain by crediting the additional genesis wallets.
+func (c *Chain) Start(testName string, ctx context.Context, additionalGenesisWallets ...ibc.WalletAmount) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, w := range additionalGenesisWallets {
+		c.addBalance(w.Address, w.Denom, w.Amount)
+	}
+	return nil
+}
+
+// Exec implements ibc.Chain by calling ExecFunc.
+func (c *Chain) Exec(ctx context.Context, cmd []string, env []string) (stdout, stderr []byte, err error) {
+	if c.ExecFunc != nil {
+		return c.ExecFunc(ctx, cmd, env)
+	}
+	return nil, nil, fmt.Errorf("exec %v: %w", cmd, errNotSupported)
+}
+
+// ExportState implements ibc.Chain. It always returns an error.
+func (c *Chain) ExportState(ctx context.Context, height int64) (string, error) {
+	return "", fmt.Errorf("export state: %w", errNotSupported)
+}
+
+// GetRPCAddress implements ibc.Chain.
+func (c *Chain) GetRPCAddress() string {
+	return fmt.Sprintf("http://%s:26657", c.cfg.ChainID)
+}
+
+// GetGRPCAddress implements ibc.Chain.
+func (c *Chain) GetGRPCAddress() string {
+	return fmt.Sprintf("%s:9090", c.cfg.ChainID)
+}
+
+// GetHostRPCAddress implements ibc.Chain. The mock chain has no host address.
+func (c *Chain) GetHostRPCAddress() string {
+	return ""
+}
+
+// GetHostGRPCAddress implements ibc.Chain. The mock chain has no host address.
+func (c *Chain) GetHostGRPCAddress() string {
+	return ""
+}
+
+// HomeDir implements ibc.Chain.
+func (c *Chain) HomeDir() string {
+	return "/home/mock"
+}
+
+// CreateKey implements ibc.Chain. The key's address is derived from its name.
+func (c *Chain) CreateKey(ctx context.Context, keyName string) error {
+	return c.addKey(keyName, keyName)
+}
+
+// RecoverKey implements ibc.Chain. The key's address is derived from the mnemonic,
+// so recovering the same mnemonic under different names yields the same address.
+func (c *Chain) RecoverKey(ctx context.Context, keyName, mnemonic string) error {
+	return c.addKey(keyName, mnemonic)
+}
+
+func (c *Chain) addKey(keyName, seed string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.keys[keyName]; ok {
+		return fmt.Errorf("key %q already exists", keyName)
+	}
+	addr := sha256.Sum256([]byte(seed))
+	c.keys[keyName] = addr[:20]
+	return nil
+}
+
+// GetAddress implements ibc.Chain.
+func (c *Chain) GetAddress(ctx context.Context, keyName string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	addr, ok := c.keys[keyName]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found", keyName)
+	}
+	return append([]byte(nil), addr...), nil
+}
+
+// SendFunds implements ibc.Chain by moving amount from the key's balance to the destination address.
+func (c *Chain) SendFunds(ctx context.Context, keyName string, amount ibc.WalletAmount) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.debit(keyName, amount.Denom, amount.Amount); err != nil {
+		return err
+	}
+	c.addBalance(amount.Address, amount.Denom, amount.Amount)
+	c.height++
+	return nil
+}
+
+// SendIBCTransfer implements ibc.Chain. Unless SendIBCTransferFunc is set,
+// it debits the key's balance and returns a transaction in the next block
+// whose packet has the channel's next sequence, starting at 1.
+// The packet's destination is on the same port and channel ID.
+func (c *Chain) SendIBCTransfer(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (ibc.Tx, error) {
+	if c.SendIBCTransferFunc != nil {
+		return c.SendIBCTransferFunc(ctx, channelID, keyName, amount, timeout)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.debit(keyName, amount.Denom, amount.Amount); err != nil {
+		return ibc.Tx{}, err
+	}
+
+	c.height++
+	c.txs++
+	c.seqs[channelID]++
+	packet := ibc.Packet{
+		Sequence:      c.seqs[channelID],
+		SourcePort:    "transfer",
+		SourceChannel: channelID,
+		DestPort:      "transfer",
+		DestChannel:   channelID,
+		Data:          []byte(fmt.Sprintf(`{"amount":%q,"denom":%q,"receiver":%q}`, amount.Amount, amount.Denom, amount.Address)),
+		TimeoutHeight: "0-1000000",
+	}
+	if timeout != nil {
+		if timeout.Height > 0 {
+			packet.TimeoutHeight = fmt.Sprintf("0-%d", timeout.Height)
+		}
+		if timeout.NanoSeconds > 0 {
+			packet.TimeoutHeight = ""
+			packet.TimeoutTimestamp = ibc.Nanoseconds(timeout.NanoSeconds)
+		}
+	}
+	return ibc.Tx{
+		Height:   c.height,
+		TxHash:   fmt.Sprintf("%064X", c.txs),
+		GasSpent: 100_000,
+		Packet:   packet,
+	}, nil
+}
+
+// Height implements ibc.Chain. Unless HeightFunc is set,
+// it returns the current height and then advances the chain by one block.
+func (c *Chain) Height(ctx context.Context) (uint64, error) {
+	if c.HeightFunc != nil {
+		return c.HeightFunc(ctx)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h := c.height
+	c.height++
+	return h, nil
+}
+
+// SetHeight sets the current height of the chain.
+func (c *Chain) SetHeight(height uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.height = height
+}
+
+// GetBalance implements ibc.Chain.
+func (c *Chain) GetBalance(ctx context.Context, address string, denom string) (math.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if bal, ok := c.balances[address][denom]; ok {
+		return bal, nil
+	}
+	return math.ZeroInt(), nil
+}
+
+// SetBalance sets the balance of address in denom.
+func (c *Chain) SetBalance(address, denom string, amount math.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.balances[address] == nil {
+		c.balances[address] = make(map[string]math.Int)
+	}
+	c.balances[address][denom] = amount
+}
+
+// GetGasFeesInNativeDenom implements ibc.Chain. Transactions on the mock chain are free.
+func (c *Chain) GetGasFeesInNativeDenom(gasPaid int64) math.Int {
+	return math.ZeroInt()
+}
+
+// Acknowledgements implements ibc.Chain, returning the acknowledgements added at height
+// unless AcknowledgementsFunc is set.
+func (c *Chain) Acknowledgements(ctx context.Context, height uint64) ([]ibc.PacketAcknowledgement, error) {
+	if c.AcknowledgementsFunc != nil {
+		return c.AcknowledgementsFunc(ctx, height)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ibc.PacketAcknowledgement(nil), c.acks[height]...), nil
+}
+
+// AddAcknowledgement adds an acknowledgement to the block at height.
+func (c *Chain) AddAcknowledgement(height uint64, ack ibc.PacketAcknowledgement) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.acks[height] = append(c.acks[height], ack)
+}
+
+// Timeouts implements ibc.Chain, returning the timeouts added at height
+// unless TimeoutsFunc is set.
+func (c *Chain) Timeouts(ctx context.Context, height uint64) ([]ibc.PacketTimeout, error) {
+	if c.TimeoutsFunc != nil {
+		return c.TimeoutsFunc(ctx, height)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ibc.PacketTimeout(nil), c.timeouts[height]...), nil
+}
+
+// AddTimeout adds a timeout to the block at height.
+func (c *Chain) AddTimeout(height uint64, timeout ibc.PacketTimeout) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timeouts[height] = append(c.timeouts[height], timeout)
+}
+
+// debit subtracts amount from the balance of the key's address,
+// returning an error if amount is not positive, as the real chain would, or exceeds the balance. c.mu must be held.
+func (c *Chain) debit(keyName, denom string, amount math.Int) error {
+	if amount.IsNil() || !amount.IsPositive() {
+		return fmt.Errorf("invalid amount %s%s: must be positive", amount, denom)
+	}
+	addr, ok := c.keys[keyName]
+	if !ok {
+		return fmt.Errorf("key %q not found", keyName)
+	}
+	bech32, err := types.Bech32ifyAddressBytes(c.cfg.Bech32Prefix, addr)
+	if err != nil {
+		return fmt.Errorf("encoding address of key %q: %w", keyName, err)
+	}
+	bal, ok := c.balances[bech32][denom]
+	if !ok {
+		bal = math.ZeroInt()
+	}
+	if bal.LT(amount) {
+		return fmt.Errorf("insufficient funds: %s has %s%s, needs %s%s", keyName, bal, denom, amount, denom)
+	}
+	c.addBalance(bech32, denom, amount.Neg())
+	return nil
+}
+
+// addBalance adds amount to the balance of address. c.mu must be held.
+func (c *Chain) addBalance(address, denom string, amount math.Int) {
+	if c.balances[address] == nil {
+		c.balances[address] = make(map[string]math.Int)
+	}
+	bal, ok := c.balances[address][denom]
+	if !ok {
+		bal = math.ZeroInt()
+	}
+	c.balances[address][denom] = bal.Add(amount)
+}
@@ -0,0 +1,92 @@
+package mock_test
+
+import (
+	"context"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain_Transfer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := mock.NewChain(ibc.ChainConfig{ChainID: "mock-1", Bech32Prefix: "cosmos", Denom: "umock"})
+
+	require.NoError(t, c.CreateKey(ctx, "user"))
+	addrBytes, err := c.GetAddress(ctx, "user")
+	require.NoError(t, err)
+	user := ibc.Wallet{KeyName: "user", Address: string(addrBytes)}
+	addr := user.Bech32Address("cosmos")
+
+	require.NoError(t, c.Start(t.Name(), ctx, ibc.WalletAmount{Address: addr, Denom: "umock", Amount: math.NewInt(100)}))
+
+	tx, err := c.SendIBCTransfer(ctx, "channel-0", "user", ibc.WalletAmount{Address: "osmo1dest", Denom: "umock", Amount: math.NewInt(60)}, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Validate())
+	require.EqualValues(t, 1, tx.Packet.Sequence)
+
+	bal, err := c.GetBalance(ctx, addr, "umock")
+	require.NoError(t, err)
+	require.True(t, bal.Equal(math.NewInt(40)))
+
+	_, err = c.SendIBCTransfer(ctx, "channel-0", "user", ibc.WalletAmount{Address: "osmo1dest", Denom: "umock", Amount: math.NewInt(60)}, nil)
+	require.ErrorContains(t, err, "insufficient funds")
+}
+
+func TestChain_TransferInvalidAmount(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := mock.NewChain(ibc.ChainConfig{ChainID: "mock-1", Bech32Prefix: "cosmos", Denom: "umock"})
+
+	// The key was never funded, so its address has no balances at all.
+	require.NoError(t, c.CreateKey(ctx, "user"))
+
+	for _, amount := range []math.Int{math.ZeroInt(), math.NewInt(-5)} {
+		err := c.SendFunds(ctx, "user", ibc.WalletAmount{Address: "cosmos1dest", Denom: "umock", Amount: amount})
+		require.ErrorContains(t, err, "must be positive")
+
+		_, err = c.SendIBCTransfer(ctx, "channel-0", "user", ibc.WalletAmount{Address: "osmo1dest", Denom: "umock", Amount: amount}, nil)
+		require.ErrorContains(t, err, "must be positive")
+	}
+
+	bal, err := c.GetBalance(ctx, "cosmos1dest", "umock")
+	require.NoError(t, err)
+	require.True(t, bal.IsZero())
+}
+
+func TestChain_PollForAck(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := mock.NewChain(ibc.ChainConfig{ChainID: "mock-1"})
+
+	packet := ibc.Packet{Sequence: 7, SourceChannel: "channel-0"}
+	c.AddAcknowledgement(3, ibc.PacketAcknowledgement{Packet: packet, Acknowledgement: []byte(`{"result":"AQ=="}`)})
+
+	ack, err := test.PollForAck(ctx, c, 1, 5, packet)
+	require.NoError(t, err)
+	require.Equal(t, packet, ack.Packet)
+
+	_, err = test.PollForTimeout(ctx, c, 1, 5, packet)
+	require.ErrorIs(t, err, test.ErrNotFound)
+}
+
+func TestChain_WaitForBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := mock.NewChain(ibc.ChainConfig{ChainID: "mock-1"})
+	c.SetHeight(10)
+
+	require.NoError(t, test.WaitForBlocks(ctx, 3, c))
+
+	h, err := c.Height(ctx)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, h, uint64(13))
+}
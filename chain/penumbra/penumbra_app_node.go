@@ -7,14 +7,17 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
-	"time"
 
+	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-connections/nat"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/strangelove-ventures/ibctest/v6/transcript"
 	"go.uber.org/zap"
 )
 
@@ -29,6 +32,9 @@ type PenumbraAppNode struct {
 	DockerClient *client.Client
 	Image        ibc.DockerImage
 
+	// Shared with the other nodes of the chain; restarts the node's container if it crashes.
+	supervisor *dockerutil.Supervisor
+
 	containerID string
 
 	// Set during StartContainer.
@@ -127,7 +133,7 @@ func (p *PenumbraAppNode) GenerateGenesisFile(
 	}
 	allocationsCsv := []byte(`"amount","denom","address"\n`)
 	for _, allocation := range allocations {
-		allocationsCsv = append(allocationsCsv, []byte(fmt.Sprintf(`"%d","%s","%s"\n`, allocation.Amount, allocation.Denom, allocation.Address))...)
+		allocationsCsv = append(allocationsCsv, []byte(fmt.Sprintf(`"%s","%s","%s"\n`, allocation.Amount, allocation.Denom, allocation.Address))...)
 	}
 	if err := fw.WriteFile(ctx, p.VolumeName, "allocations.csv", allocationsCsv); err != nil {
 		return fmt.Errorf("error writing allocations to file: %w", err)
@@ -193,26 +199,42 @@ func (p *PenumbraAppNode) SendIBCTransfer(ctx context.Context, channelID, keyNam
 
 func (p *PenumbraAppNode) CreateNodeContainer(ctx context.Context) error {
 	cmd := []string{"pd", "start", "--host", "0.0.0.0", "--home", p.HomeDir()}
-	fmt.Printf("{%s} -> '%s'\n", p.Name(), strings.Join(cmd, " "))
+	var env []string
+	if level := p.Chain.Config().LogLevel; level != "" {
+		env = append(env, "RUST_LOG="+level)
+	}
+	start := p.Chain.Config().StartCommand(cmd, env...)
+	fmt.Printf("{%s} -> '%s' %v\n", p.Name(), strings.Join(start.Cmd, " "), start.Env)
+	testreporter.TrackNodeCommand(ctx, p.Name(), start.Cmd, start.Env)
 
+	platform, err := dockerutil.ParsePlatform(p.Image.Platform)
+	if err != nil {
+		return err
+	}
+	publishAll, portBindings, err := dockerutil.PortPublishing(p.Chain.Config().HostPorts).ReserveHostConfig(p.Name(), exposedPorts)
+	if err != nil {
+		return err
+	}
 	cc, err := p.DockerClient.ContainerCreate(
 		ctx,
 		&container.Config{
 			Image: p.Image.Ref(),
 
 			Entrypoint: []string{},
-			Cmd:        cmd,
+			Cmd:        start.Cmd,
+			Env:        start.Env,
 
 			Hostname: p.HostName(),
 			User:     dockerutil.GetRootUserString(),
 
-			Labels: map[string]string{dockerutil.CleanupLabel: p.TestName},
+			Labels: dockerutil.Labels(p.TestName),
 
 			ExposedPorts: exposedPorts,
 		},
 		&container.HostConfig{
 			Binds:           p.Bind(),
-			PublishAllPorts: true,
+			PublishAllPorts: publishAll,
+			PortBindings:    portBindings,
 			AutoRemove:      false,
 			DNS:             []string{},
 		},
@@ -221,10 +243,11 @@ func (p *PenumbraAppNode) CreateNodeContainer(ctx context.Context) error {
 				p.NetworkID: {},
 			},
 		},
-		nil,
+		platform,
 		p.Name(),
 	)
 	if err != nil {
+		dockerutil.ReleaseHostPorts(p.Name())
 		return err
 	}
 	p.containerID = cc.ID
@@ -232,33 +255,49 @@ func (p *PenumbraAppNode) CreateNodeContainer(ctx context.Context) error {
 }
 
 func (p *PenumbraAppNode) StopContainer(ctx context.Context) error {
-	timeout := 30 * time.Second
-	return p.DockerClient.ContainerStop(ctx, p.containerID, &timeout)
+	return dockerutil.StopContainer(ctx, p.DockerClient, p.containerID)
+}
+
+// RemoveContainer removes the penumbra app node container and releases its reserved host ports.
+func (p *PenumbraAppNode) RemoveContainer(ctx context.Context) error {
+	err := p.DockerClient.ContainerRemove(ctx, p.containerID, dockertypes.ContainerRemoveOptions{
+		Force:         true,
+		RemoveVolumes: true,
+	})
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("remove container %s: %w", p.Name(), err)
+	}
+	dockerutil.ReleaseHostPorts(p.Name())
+	return nil
 }
 
 func (p *PenumbraAppNode) StartContainer(ctx context.Context) error {
 	if err := dockerutil.StartContainer(ctx, p.DockerClient, p.containerID); err != nil {
 		return err
 	}
+	if p.supervisor != nil {
+		p.supervisor.Watch(p.containerID, p.Name())
+	}
 
-	c, err := p.DockerClient.ContainerInspect(ctx, p.containerID)
+	publishing := dockerutil.PortPublishing(p.Chain.Config().HostPorts)
+	hostPorts, err := dockerutil.ResolveHostPorts(ctx, p.DockerClient, p.containerID, p.HostName(), publishing, rpcPort, grpcPort)
 	if err != nil {
 		return err
 	}
-
-	p.hostRPCPort = dockerutil.GetHostPort(c, rpcPort)
-	p.hostGRPCPort = dockerutil.GetHostPort(c, grpcPort)
+	p.hostRPCPort, p.hostGRPCPort = hostPorts[0], hostPorts[1]
 
 	return nil
 }
 
 // Exec run a container for a specific job and block until the container exits
 func (p *PenumbraAppNode) Exec(ctx context.Context, cmd []string, env []string) ([]byte, []byte, error) {
-	job := dockerutil.NewImage(p.log, p.DockerClient, p.NetworkID, p.TestName, p.Image.Repository, p.Image.Version)
+	transcript.RecordChainExec(ctx, p.Chain.Config().ChainID, p.Name(), cmd, env)
+	job := dockerutil.NewImage(p.log, p.DockerClient, p.NetworkID, p.TestName, p.Image.Repository, p.Image.Tag())
 	opts := dockerutil.ContainerOptions{
-		Binds: p.Bind(),
-		Env:   env,
-		User:  dockerutil.GetRootUserString(),
+		Binds:    p.Bind(),
+		Env:      env,
+		User:     dockerutil.GetRootUserString(),
+		Platform: p.Image.Platform,
 	}
 	res := job.Run(ctx, cmd, opts)
 	return res.Stdout, res.Stderr, res.Err
@@ -5,17 +5,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"strconv"
-	"strings"
 
-	"github.com/docker/docker/api/types"
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/strangelove-ventures/ibctest/v6/chain/internal/tendermint"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
 	"github.com/strangelove-ventures/ibctest/v6/test"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/strangelove-ventures/ibctest/v6/transcript"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -34,6 +34,9 @@ type PenumbraChain struct {
 	numValidators int
 	numFullNodes  int
 	PenumbraNodes PenumbraNodes
+
+	// Set during Initialize; restarts crashed tendermint and penumbra app containers.
+	supervisor *dockerutil.Supervisor
 }
 
 type PenumbraValidatorDefinition struct {
@@ -52,9 +55,9 @@ type PenumbraValidatorFundingStream struct {
 }
 
 type PenumbraGenesisAppStateAllocation struct {
-	Amount  int64  `json:"amount"`
-	Denom   string `json:"denom"`
-	Address string `json:"address"`
+	Amount  math.Int `json:"amount"`
+	Denom   string   `json:"denom"`
+	Address string   `json:"address"`
 }
 
 func NewPenumbraChain(log *zap.Logger, testName string, chainConfig ibc.ChainConfig, numValidators int, numFullNodes int) *PenumbraChain {
@@ -75,14 +78,33 @@ func (c *PenumbraChain) Timeouts(ctx context.Context, height uint64) ([]ibc.Pack
 	panic("implement me")
 }
 
+// SupportsFeature reports whether the chain supports f.
+// Penumbra chains do not support any feature yet, as IBC transfers and balance queries are not implemented.
+// Implements ibc.ChainCapabilities.
+func (c *PenumbraChain) SupportsFeature(f ibc.ChainFeature) bool {
+	return false
+}
+
 // Implements Chain interface
 func (c *PenumbraChain) Config() ibc.ChainConfig {
 	return c.cfg
 }
 
+// Decimals returns the number of decimals of the display unit of the chain's denom, e.g. 6 for penumbra.
+// Implements ibc.DisplayUnits.
+func (c *PenumbraChain) Decimals() int64 {
+	return c.Config().Decimals()
+}
+
+// DisplayDenom returns the display unit of the chain's denom, from its CoinDisplayDenom or its Denom.
+// Implements ibc.DisplayUnits.
+func (c *PenumbraChain) DisplayDenom() string {
+	return c.Config().DisplayDenom()
+}
+
 // Implements Chain interface
 func (c *PenumbraChain) Initialize(ctx context.Context, testName string, cli *client.Client, networkID string) error {
-	return c.initializeChainNodes(ctx, testName, cli, networkID)
+	return dockerutil.Diagnose(c.initializeChainNodes(ctx, testName, cli, networkID))
 }
 
 // Exec implements chain interface.
@@ -141,11 +163,18 @@ func (c *PenumbraChain) GetAddress(ctx context.Context, keyName string) ([]byte,
 
 // Implements Chain interface
 func (c *PenumbraChain) SendFunds(ctx context.Context, keyName string, amount ibc.WalletAmount) error {
+	ctx = transcript.RecordSendFunds(ctx, c.cfg.ChainID, transcript.Funds{KeyName: keyName, Amount: amount})
 	return c.getRelayerNode().PenumbraAppNode.SendFunds(ctx, keyName, amount)
 }
 
 // Implements Chain interface
 func (c *PenumbraChain) SendIBCTransfer(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (ibc.Tx, error) {
+	ctx = transcript.RecordTransfer(ctx, c.cfg.ChainID, transcript.Transfer{
+		ChannelID: channelID,
+		KeyName:   keyName,
+		Amounts:   []ibc.WalletAmount{amount},
+		Timeout:   timeout,
+	})
 	return c.getRelayerNode().PenumbraAppNode.SendIBCTransfer(ctx, channelID, keyName, amount, timeout)
 }
 
@@ -158,16 +187,57 @@ func (c *PenumbraChain) Height(ctx context.Context) (uint64, error) {
 	return c.getRelayerNode().TendermintNode.Height(ctx)
 }
 
+// HealthCheck reports the chain as not live once a node container exited unexpectedly more often than it may be restarted,
+// and as ready once no tendermint node is catching up and, with more than one node, every tendermint node has peers.
+// Implements ibc.HealthChecker.
+func (c *PenumbraChain) HealthCheck(ctx context.Context) (ibc.HealthStatus, error) {
+	if c.supervisor != nil {
+		if err := c.supervisor.Err(); err != nil {
+			return ibc.HealthStatus{Reason: err.Error()}, nil
+		}
+	}
+
+	for _, n := range c.PenumbraNodes {
+		tn := n.TendermintNode
+		if tn.Client == nil {
+			return ibc.HealthStatus{}, fmt.Errorf("node %s has not started", tn.Name())
+		}
+		stat, err := tn.Client.Status(ctx)
+		if err != nil {
+			return ibc.HealthStatus{}, fmt.Errorf("failed to get status of node %s: %w", tn.Name(), err)
+		}
+		if stat.SyncInfo.CatchingUp {
+			return ibc.HealthStatus{
+				Live:   true,
+				Reason: fmt.Sprintf("node %s is catching up at height %d", tn.Name(), stat.SyncInfo.LatestBlockHeight),
+			}, nil
+		}
+		if len(c.PenumbraNodes) < 2 {
+			continue
+		}
+		netInfo, err := tn.Client.NetInfo(ctx)
+		if err != nil {
+			return ibc.HealthStatus{}, fmt.Errorf("failed to get net info of node %s: %w", tn.Name(), err)
+		}
+		if netInfo.NPeers == 0 {
+			return ibc.HealthStatus{Live: true, Reason: fmt.Sprintf("node %s has no peers", tn.Name())}, nil
+		}
+	}
+	return ibc.HealthStatus{Live: true, Ready: true}, nil
+}
+
 // Implements Chain interface
-func (c *PenumbraChain) GetBalance(ctx context.Context, address string, denom string) (int64, error) {
+func (c *PenumbraChain) GetBalance(ctx context.Context, address string, denom string) (math.Int, error) {
 	panic("implement me")
 }
 
 // Implements Chain interface
-func (c *PenumbraChain) GetGasFeesInNativeDenom(gasPaid int64) int64 {
-	gasPrice, _ := strconv.ParseFloat(strings.Replace(c.cfg.GasPrices, c.cfg.Denom, "", 1), 64)
-	fees := float64(gasPaid) * gasPrice
-	return int64(fees)
+func (c *PenumbraChain) GetGasFeesInNativeDenom(gasPaid int64) math.Int {
+	gasPrice, err := sdk.ParseDecCoin(c.cfg.GasPrices)
+	if err != nil {
+		return math.ZeroInt()
+	}
+	return gasPrice.Amount.MulInt64(gasPaid).TruncateInt()
 }
 
 // creates the test node objects required for bootstrapping tests
@@ -181,32 +251,21 @@ func (c *PenumbraChain) initializeChainNodes(
 	count := c.numValidators + c.numFullNodes
 	chainCfg := c.Config()
 	for _, image := range chainCfg.Images {
-		rc, err := cli.ImagePull(
-			ctx,
-			image.Repository+":"+image.Version,
-			types.ImagePullOptions{},
-		)
-		if err != nil {
-			c.log.Error("Failed to pull image",
-				zap.Error(err),
-				zap.String("repository", image.Repository),
-				zap.String("tag", image.Version),
-			)
-		} else {
-			_, _ = io.Copy(io.Discard, rc)
-			_ = rc.Close()
+		if err := dockerutil.EnsureImage(ctx, c.log, cli, image.Ref(), image.Platform); err != nil {
+			return err
 		}
 	}
+	supervisor, err := dockerutil.NewSupervisor(c.log, cli, nil)
+	if err != nil {
+		return err
+	}
+	c.supervisor = supervisor
 	for i := 0; i < count; i++ {
 		tn := &tendermint.TendermintNode{Log: c.log, Index: i, Chain: c,
-			DockerClient: cli, NetworkID: networkID, TestName: testName, Image: chainCfg.Images[0]}
+			DockerClient: cli, NetworkID: networkID, TestName: testName, Image: chainCfg.Images[0], Supervisor: c.supervisor}
 
 		tv, err := cli.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
-			Labels: map[string]string{
-				dockerutil.CleanupLabel: testName,
-
-				dockerutil.NodeOwnerLabel: tn.Name(),
-			},
+			Labels: dockerutil.VolumeLabels(testName, tn.Name()),
 		})
 		if err != nil {
 			return fmt.Errorf("creating tendermint volume: %w", err)
@@ -225,14 +284,10 @@ func (c *PenumbraChain) initializeChainNodes(
 			return fmt.Errorf("set tendermint volume owner: %w", err)
 		}
 
-		pn := &PenumbraAppNode{log: c.log, Index: i, Chain: c,
+		pn := &PenumbraAppNode{log: c.log, supervisor: c.supervisor, Index: i, Chain: c,
 			DockerClient: cli, NetworkID: networkID, TestName: testName, Image: chainCfg.Images[1]}
 		pv, err := cli.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
-			Labels: map[string]string{
-				dockerutil.CleanupLabel: testName,
-
-				dockerutil.NodeOwnerLabel: pn.Name(),
-			},
+			Labels: dockerutil.VolumeLabels(testName, pn.Name()),
 		})
 		if err != nil {
 			return fmt.Errorf("creating penumbra volume: %w", err)
@@ -279,6 +334,10 @@ type ValidatorWithIntPower struct {
 }
 
 func (c *PenumbraChain) Start(testName string, ctx context.Context, additionalGenesisWallets ...ibc.WalletAmount) error {
+	if !c.cfg.GenesisTime.IsZero() || c.cfg.StartBeforeGenesis {
+		return fmt.Errorf("chain %s: genesis time is not supported for penumbra chains", c.cfg.ChainID)
+	}
+
 	validators := c.PenumbraNodes[:c.numValidators]
 	fullnodes := c.PenumbraNodes[c.numValidators:]
 
@@ -332,13 +391,13 @@ func (c *PenumbraChain) Start(testName string, ctx context.Context, additionalGe
 
 			// self delegation
 			allocations[2*i] = PenumbraGenesisAppStateAllocation{
-				Amount:  100_000_000_000,
+				Amount:  math.NewInt(100_000_000_000),
 				Denom:   fmt.Sprintf("udelegation_%s", validatorTemplateDefinition.IdentityKey),
 				Address: validatorTemplateDefinition.FundingStreams[0].Address,
 			}
 			// liquid
 			allocations[2*i+1] = PenumbraGenesisAppStateAllocation{
-				Amount:  1_000_000_000_000,
+				Amount:  math.NewInt(1_000_000_000_000),
 				Denom:   chainCfg.Denom,
 				Address: validatorTemplateDefinition.FundingStreams[0].Address,
 			}
@@ -400,6 +459,28 @@ func (c *PenumbraChain) Start(testName string, ctx context.Context, additionalGe
 	return c.start(ctx)
 }
 
+// StopAllNodes stops and removes the tendermint and penumbra app containers of all nodes,
+// releasing their reserved host ports.
+func (c *PenumbraChain) StopAllNodes(ctx context.Context) error {
+	var eg errgroup.Group
+	for _, n := range c.PenumbraNodes {
+		n := n
+		eg.Go(func() error {
+			if err := n.TendermintNode.StopContainer(ctx); err != nil {
+				return err
+			}
+			return n.TendermintNode.RemoveContainer(ctx)
+		})
+		eg.Go(func() error {
+			if err := n.PenumbraAppNode.StopContainer(ctx); err != nil {
+				return err
+			}
+			return n.PenumbraAppNode.RemoveContainer(ctx)
+		})
+	}
+	return eg.Wait()
+}
+
 // Bootstraps the chain and starts it from genesis
 func (c *PenumbraChain) start(ctx context.Context) error {
 	// Copy the penumbra genesis to all tendermint nodes.
@@ -461,5 +542,6 @@ func (c *PenumbraChain) start(ctx context.Context) error {
 	}
 
 	// Wait for 5 blocks before considering the chains "started"
+	defer testreporter.StartPhase(ctx, testreporter.PhaseFirstBlock, c.cfg.ChainID)()
 	return test.WaitForBlocks(ctx, 5, c.getRelayerNode().TendermintNode)
 }
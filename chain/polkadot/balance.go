@@ -0,0 +1,204 @@
+package polkadot
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"cosmossdk.io/math"
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	gstypes "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+// Location identifies the relay chain, or one of the parachains, of a PolkadotChain.
+type Location struct {
+	// Chain ID of the parachain, or empty for the relay chain.
+	ParachainID string
+}
+
+// RelayChain is the Location of the relay chain.
+var RelayChain = Location{}
+
+// Parachain returns the Location of the parachain with the given chain ID.
+func Parachain(chainID string) Location {
+	return Location{ParachainID: chainID}
+}
+
+func (l Location) String() string {
+	if l.ParachainID == "" {
+		return "relay chain"
+	}
+	return "parachain " + l.ParachainID
+}
+
+// GetBalanceOn fetches the free balance of the SS58 address on the chain at loc.
+// If assetID is empty, the native balance from the System pallet is returned;
+// otherwise, the balance of the asset with that ID in the Assets pallet is returned.
+// Accounts that do not exist have a balance of zero.
+func (c *PolkadotChain) GetBalanceOn(ctx context.Context, loc Location, address, assetID string) (math.Int, error) {
+	return c.getBalanceOn(loc, address, assetID, nil)
+}
+
+// GetBalanceOnAtHeight is like GetBalanceOn, but returns the balance in the state of the block at height,
+// so that tests can tell the exact block in which a balance changed.
+func (c *PolkadotChain) GetBalanceOnAtHeight(ctx context.Context, loc Location, address, assetID string, height uint64) (math.Int, error) {
+	api, err := c.locationAPI(loc)
+	if err != nil {
+		return math.Int{}, err
+	}
+	hash, err := api.RPC.Chain.GetBlockHash(height)
+	if err != nil {
+		return math.Int{}, fmt.Errorf("getting %s block hash at height %d: %w", loc, height, err)
+	}
+	return c.getBalanceOn(loc, address, assetID, &hash)
+}
+
+// getBalanceOn returns the balance in the state of the block with hash at, or of the latest block if at is nil.
+func (c *PolkadotChain) getBalanceOn(loc Location, address, assetID string, at *gstypes.Hash) (math.Int, error) {
+	api, err := c.locationAPI(loc)
+	if err != nil {
+		return math.Int{}, err
+	}
+
+	accountID, err := DecodeAddressSS58(address)
+	if err != nil {
+		return math.Int{}, err
+	}
+
+	var meta *gstypes.Metadata
+	if at == nil {
+		meta, err = api.RPC.State.GetMetadataLatest()
+	} else {
+		meta, err = api.RPC.State.GetMetadata(*at)
+	}
+	if err != nil {
+		return math.Int{}, fmt.Errorf("getting %s metadata: %w", loc, err)
+	}
+	sd, err := newScaleDecoder(meta)
+	if err != nil {
+		return math.Int{}, err
+	}
+
+	if assetID == "" {
+		account, err := queryStorageMap(api, meta, sd, at, "System", "Account", accountID)
+		if err != nil {
+			return math.Int{}, fmt.Errorf("querying %s account %s: %w", loc, address, err)
+		}
+		data, _ := account["data"].(map[string]any)
+		return balanceField(data, "free")
+	}
+
+	entry, err := storageEntryType(meta, "Assets", "Account")
+	if err != nil {
+		return math.Int{}, err
+	}
+	keyType, ok := sd.lookup[entry.AsMap.Key.Int64()]
+	if !entry.IsMap || !ok || !keyType.Def.IsTuple || len(keyType.Def.Tuple) != 2 {
+		return math.Int{}, fmt.Errorf("unexpected Assets.Account storage key type on %s", loc)
+	}
+	encodedID, err := sd.EncodeUint(keyType.Def.Tuple[0].Int64(), assetID)
+	if err != nil {
+		return math.Int{}, fmt.Errorf("encoding asset ID: %w", err)
+	}
+
+	account, err := queryStorageMap(api, meta, sd, at, "Assets", "Account", encodedID, accountID)
+	if err != nil {
+		return math.Int{}, fmt.Errorf("querying %s asset %s account %s: %w", loc, assetID, address, err)
+	}
+	return balanceField(account, "balance")
+}
+
+// denomLocation returns the location and asset ID that GetBalance uses for denom.
+func (c *PolkadotChain) denomLocation(denom string) (Location, string, error) {
+	if denom == c.cfg.Denom {
+		return RelayChain, "", nil
+	}
+	for _, pc := range c.parachainConfig {
+		if pc.Denom != "" && denom == pc.Denom {
+			return Parachain(pc.ChainID), "", nil
+		}
+		if assetID, ok := pc.AssetIDs[denom]; ok {
+			return Parachain(pc.ChainID), assetID, nil
+		}
+	}
+	return Location{}, "", fmt.Errorf("denom %s is not the relay chain denom, nor a parachain denom or asset", denom)
+}
+
+// locationAPI returns the client for the first node of the chain at loc.
+func (c *PolkadotChain) locationAPI(loc Location) (*gsrpc.SubstrateAPI, error) {
+	if loc.ParachainID == "" {
+		return c.RelayChainNodes[0].api, nil
+	}
+	for i, pc := range c.parachainConfig {
+		if pc.ChainID == loc.ParachainID && i < len(c.ParachainNodes) && len(c.ParachainNodes[i]) > 0 {
+			return c.ParachainNodes[i][0].api, nil
+		}
+	}
+	return nil, fmt.Errorf("no nodes for %s", loc)
+}
+
+// queryStorageMap returns the decoded struct value of the storage map entry at keys in pallet.item,
+// or nil if the entry does not exist.
+// The storage of the block with hash at is queried, or of the latest block if at is nil.
+func queryStorageMap(api *gsrpc.SubstrateAPI, meta *gstypes.Metadata, sd *scaleDecoder, at *gstypes.Hash, pallet, item string, keys ...[]byte) (map[string]any, error) {
+	v, err := queryStorage(api, meta, sd, at, pallet, item, keys...)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s.%s type %T", pallet, item, v)
+	}
+	return m, nil
+}
+
+// queryStorage returns the decoded value of the storage map entry at keys in pallet.item,
+// or nil if the entry does not exist.
+// The storage of the block with hash at is queried, or of the latest block if at is nil.
+func queryStorage(api *gsrpc.SubstrateAPI, meta *gstypes.Metadata, sd *scaleDecoder, at *gstypes.Hash, pallet, item string, keys ...[]byte) (any, error) {
+	entry, err := storageEntryType(meta, pallet, item)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.IsMap {
+		return nil, fmt.Errorf("%s.%s storage is not a map", pallet, item)
+	}
+
+	key, err := gstypes.CreateStorageKey(meta, pallet, item, keys...)
+	if err != nil {
+		return nil, fmt.Errorf("creating storage key: %w", err)
+	}
+	var raw *gstypes.StorageDataRaw
+	if at == nil {
+		raw, err = api.RPC.State.GetStorageRawLatest(key)
+	} else {
+		raw, err = api.RPC.State.GetStorageRaw(key, *at)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting storage: %w", err)
+	}
+	if raw == nil || len(*raw) == 0 {
+		return nil, nil
+	}
+
+	v, err := sd.Decode(*raw, entry.AsMap.Value.Int64())
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s.%s: %w", pallet, item, err)
+	}
+	return v, nil
+}
+
+// balanceField returns the balance in field name of fields, or zero if fields is nil.
+func balanceField(fields map[string]any, name string) (math.Int, error) {
+	if fields == nil {
+		return math.ZeroInt(), nil
+	}
+	switch v := fields[name].(type) {
+	case *big.Int:
+		return math.NewIntFromBigInt(v), nil
+	case uint64:
+		return math.NewIntFromUint64(v), nil
+	default:
+		return math.Int{}, fmt.Errorf("unexpected balance type %T", v)
+	}
+}
@@ -0,0 +1,104 @@
+package polkadot
+
+import (
+	"bytes"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/mr-tron/base58"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDenomLocation(t *testing.T) {
+	t.Parallel()
+
+	c := NewPolkadotChain(nil, t.Name(), ibc.ChainConfig{Denom: "uDOT"}, 1, []ParachainConfig{
+		{ChainID: "dali-dev", Denom: "PICA", AssetIDs: map[string]string{"ibc/ATOM": "130"}},
+	})
+
+	for _, tt := range []struct {
+		denom   string
+		loc     Location
+		assetID string
+	}{
+		{"uDOT", RelayChain, ""},
+		{"PICA", Parachain("dali-dev"), ""},
+		{"ibc/ATOM", Parachain("dali-dev"), "130"},
+	} {
+		loc, assetID, err := c.denomLocation(tt.denom)
+		require.NoError(t, err, tt.denom)
+		require.Equal(t, tt.loc, loc, tt.denom)
+		require.Equal(t, tt.assetID, assetID, tt.denom)
+	}
+
+	_, _, err := c.denomLocation("uatom")
+	require.Error(t, err)
+}
+
+func TestDecodeAddressSS58(t *testing.T) {
+	t.Parallel()
+
+	key := bytes.Repeat([]byte{7}, 32)
+	addr, err := EncodeAddressSS58(key)
+	require.NoError(t, err)
+
+	got, err := DecodeAddressSS58(addr)
+	require.NoError(t, err)
+	require.Equal(t, key, got)
+
+	// Alice's well known development address.
+	_, err = DecodeAddressSS58("5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY")
+	require.NoError(t, err)
+
+	raw, err := base58.Decode(addr)
+	require.NoError(t, err)
+	raw[len(raw)-1] ^= 1
+	_, err = DecodeAddressSS58(base58.Encode(raw))
+	require.ErrorContains(t, err, "checksum mismatch")
+}
+
+func TestEncodeUint(t *testing.T) {
+	t.Parallel()
+
+	sd := &scaleDecoder{lookup: testRegistry()}
+
+	b, err := sd.EncodeUint(testTypeU64, "258")
+	require.NoError(t, err)
+	require.Equal(t, encodeTestU64(258), b)
+
+	_, err = sd.EncodeUint(testTypeU8, "256")
+	require.Error(t, err)
+
+	_, err = sd.EncodeUint(testTypeBytes, "1")
+	require.Error(t, err)
+}
+
+func TestGenesisBalances(t *testing.T) {
+	t.Parallel()
+
+	c := NewPolkadotChain(nil, t.Name(), ibc.ChainConfig{Denom: "uDOT"}, 1, []ParachainConfig{
+		{ChainID: "dali-dev", AssetIDs: map[string]string{"ibc/ATOM": "130"}},
+		{ChainID: "karura-dev", Denom: "KAR"},
+	})
+	require.Equal(t, []string{"uDOT", "KAR"}, c.RelayerFeeDenoms())
+
+	const alice = "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"
+	relayChain, parachains, err := c.genesisBalances([]ibc.WalletAmount{
+		{Address: alice, Denom: "uDOT", Amount: math.NewInt(100)},
+		{Address: alice, Denom: "KAR", Amount: math.NewInt(200)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]interface{}{{alice, uint64(100)}}, relayChain)
+	// dali-dev has no denom of its own, so it is funded in the relay chain denom.
+	require.Equal(t, map[string][][]interface{}{
+		"dali-dev":   {{alice, uint64(100)}},
+		"karura-dev": {{alice, uint64(200)}},
+	}, parachains)
+
+	_, _, err = c.genesisBalances([]ibc.WalletAmount{{Address: alice, Denom: "ibc/ATOM", Amount: math.NewInt(1)}})
+	require.ErrorContains(t, err, "not supported")
+
+	_, _, err = c.genesisBalances([]ibc.WalletAmount{{Address: "cosmos1abc", Denom: "uDOT", Amount: math.NewInt(1)}})
+	require.Error(t, err)
+}
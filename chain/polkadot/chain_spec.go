@@ -0,0 +1,264 @@
+package polkadot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/icza/dyno"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+)
+
+// relayChainNodeBalance is the genesis balance of the stash and of the account of each relay chain node.
+const relayChainNodeBalance = uint64(1000000000000000000)
+
+// GenesisRelayChainNode is the data of a relay chain node that the relay chain's genesis includes.
+type GenesisRelayChainNode struct {
+	// Address that the other nodes boot from.
+	MultiAddress string
+	// SS58 addresses of the node's session keys.
+	StashAddress   string
+	AccountAddress string
+	GrandpaAddress string
+	BeefyAddress   string
+}
+
+// GenesisParachain is a parachain registered in the relay chain's genesis.
+type GenesisParachain struct {
+	ID int
+	// Genesis state and wasm exported by the parachain's nodes, hex encoded.
+	GenesisState string
+	GenesisWasm  string
+}
+
+// ChainSpecInputs is the data, gathered from a relay chain's nodes, that ModifyChainSpec sets in the relay chain spec.
+type ChainSpecInputs struct {
+	// Nodes of the relay chain, the first of which is the sudo account.
+	RelayChainNodes []GenesisRelayChainNode
+	Parachains      []GenesisParachain
+	// Genesis balances besides those of the relay chain nodes, as pairs of SS58 address and amount.
+	Balances [][]interface{}
+	// If set, the network protocol ID of the relay chain, which isolates its network
+	// from those of other relay chains generated from the same built-in chain spec.
+	ProtocolID string
+}
+
+// builtinChainSpec returns the name of the built-in chain spec that the chain's genesis is generated from.
+func builtinChainSpec(cfg ibc.ChainConfig) string {
+	if cfg.BuiltinChainSpec != "" {
+		return cfg.BuiltinChainSpec
+	}
+	return cfg.ChainID
+}
+
+// ModifyChainSpec sets the boot nodes, protocol ID, authorities, balances, sudo key, and parachains of chainSpec,
+// a relay chain spec as generated by build-spec and unmarshaled from JSON, from in.
+// It modifies the chain spec as PolkadotChain.Start does, without needing the chain's nodes.
+func ModifyChainSpec(chainSpec interface{}, in ChainSpecInputs) error {
+	bootNodes := []string{}
+	authorities := [][]interface{}{}
+	balances := [][]interface{}{}
+	var sudoAddress string
+	for i, n := range in.RelayChainNodes {
+		bootNodes = append(bootNodes, n.MultiAddress)
+		balances = append(balances,
+			[]interface{}{n.StashAddress, relayChainNodeBalance},
+			[]interface{}{n.AccountAddress, relayChainNodeBalance},
+		)
+		if i == 0 {
+			sudoAddress = n.AccountAddress
+		}
+		authority := []interface{}{n.StashAddress, n.StashAddress, PolkadotAuthority{
+			Grandpa:            n.GrandpaAddress,
+			Babe:               n.AccountAddress,
+			IMOnline:           n.AccountAddress,
+			ParachainValidator: n.AccountAddress,
+			AuthorityDiscovery: n.AccountAddress,
+			ParaValidator:      n.AccountAddress,
+			ParaAssignment:     n.AccountAddress,
+			Beefy:              n.BeefyAddress,
+		}}
+		authorities = append(authorities, authority)
+	}
+
+	balances = append(balances, in.Balances...)
+
+	if err := dyno.Set(chainSpec, bootNodes, "bootNodes"); err != nil {
+		return fmt.Errorf("error setting boot nodes: %w", err)
+	}
+	if in.ProtocolID != "" {
+		if err := dyno.Set(chainSpec, in.ProtocolID, "protocolId"); err != nil {
+			return fmt.Errorf("error setting protocol ID: %w", err)
+		}
+	}
+	if err := dyno.Set(chainSpec, authorities, runtimeGenesisPath("session", "keys")...); err != nil {
+		return fmt.Errorf("error setting authorities: %w", err)
+	}
+	if err := dyno.Set(chainSpec, balances, runtimeGenesisPath("balances", "balances")...); err != nil {
+		return fmt.Errorf("error setting balances: %w", err)
+	}
+	if err := dyno.Set(chainSpec, sudoAddress, runtimeGenesisPath("sudo", "key")...); err != nil {
+		return fmt.Errorf("error setting sudo key: %w", err)
+	}
+	if err := dyno.Set(chainSpec, sudoAddress, runtimeGenesisPath("bridgeRococoGrandpa", "owner")...); err != nil {
+		return fmt.Errorf("error setting bridgeRococoGrandpa owner: %w", err)
+	}
+	if err := dyno.Set(chainSpec, sudoAddress, runtimeGenesisPath("bridgeWococoGrandpa", "owner")...); err != nil {
+		return fmt.Errorf("error setting bridgeWococoGrandpa owner: %w", err)
+	}
+	if err := dyno.Set(chainSpec, sudoAddress, runtimeGenesisPath("bridgeRococoMessages", "owner")...); err != nil {
+		return fmt.Errorf("error setting bridgeRococoMessages owner: %w", err)
+	}
+	if err := dyno.Set(chainSpec, sudoAddress, runtimeGenesisPath("bridgeWococoMessages", "owner")...); err != nil {
+		return fmt.Errorf("error setting bridgeWococoMessages owner: %w", err)
+	}
+	if err := dyno.Set(chainSpec, 2, runtimeGenesisPath("configuration", "config", "validation_upgrade_delay")...); err != nil {
+		return fmt.Errorf("error setting validation upgrade delay: %w", err)
+	}
+
+	parachains := [][]interface{}{}
+	registered := make(map[int]bool, len(in.Parachains))
+	for _, p := range in.Parachains {
+		if registered[p.ID] {
+			return fmt.Errorf("parachain ID %d is registered more than once; set distinct ParachainConfig.ParaID overrides", p.ID)
+		}
+		registered[p.ID] = true
+		parachains = append(parachains, []interface{}{p.ID, PolkadotParachainSpec{
+			GenesisHead:    p.GenesisState,
+			ValidationCode: p.GenesisWasm,
+			Parachain:      true,
+		}})
+	}
+	if err := dyno.Set(chainSpec, parachains, runtimeGenesisPath("paras", "paras")...); err != nil {
+		return fmt.Errorf("error setting parachains: %w", err)
+	}
+	return nil
+}
+
+// genesisNode returns the data of the node that the relay chain's genesis includes.
+func (p *RelayChainNode) genesisNode() (GenesisRelayChainNode, error) {
+	multiAddress, err := p.MultiAddress()
+	if err != nil {
+		return GenesisRelayChainNode{}, err
+	}
+	stashAddress, err := p.StashAddress()
+	if err != nil {
+		return GenesisRelayChainNode{}, fmt.Errorf("error getting stash address: %w", err)
+	}
+	accountAddress, err := p.AccountAddress()
+	if err != nil {
+		return GenesisRelayChainNode{}, fmt.Errorf("error getting account address: %w", err)
+	}
+	grandpaAddress, err := p.GrandpaAddress()
+	if err != nil {
+		return GenesisRelayChainNode{}, fmt.Errorf("error getting grandpa address: %w", err)
+	}
+	beefyAddress, err := p.EcdsaAddress()
+	if err != nil {
+		return GenesisRelayChainNode{}, fmt.Errorf("error getting beefy address: %w", err)
+	}
+	return GenesisRelayChainNode{
+		MultiAddress:   multiAddress,
+		StashAddress:   stashAddress,
+		AccountAddress: accountAddress,
+		GrandpaAddress: grandpaAddress,
+		BeefyAddress:   beefyAddress,
+	}, nil
+}
+
+// chainSpecInputs gathers the data of the relay chain's nodes and of its parachains that its genesis includes,
+// along with balances.
+func (c *PolkadotChain) chainSpecInputs(ctx context.Context, balances [][]interface{}) (ChainSpecInputs, error) {
+	in := ChainSpecInputs{Balances: balances, ProtocolID: c.cfg.ChainID}
+	for _, n := range c.RelayChainNodes {
+		node, err := n.genesisNode()
+		if err != nil {
+			return ChainSpecInputs{}, err
+		}
+		in.RelayChainNodes = append(in.RelayChainNodes, node)
+	}
+
+	for _, parachainNodes := range c.ParachainNodes {
+		firstParachainNode := parachainNodes[0]
+		parachainID, err := firstParachainNode.ParachainID(ctx)
+		if err != nil {
+			return ChainSpecInputs{}, fmt.Errorf("error getting parachain ID: %w", err)
+		}
+		genesisState, err := firstParachainNode.ExportGenesisState(ctx)
+		if err != nil {
+			return ChainSpecInputs{}, fmt.Errorf("error exporting genesis state: %w", err)
+		}
+		genesisWasm, err := firstParachainNode.ExportGenesisWasm(ctx)
+		if err != nil {
+			return ChainSpecInputs{}, fmt.Errorf("error exporting genesis wasm: %w", err)
+		}
+		in.Parachains = append(in.Parachains, GenesisParachain{
+			ID:           parachainID,
+			GenesisState: genesisState,
+			GenesisWasm:  genesisWasm,
+		})
+	}
+	return in, nil
+}
+
+// buildChainSpec generates the chain spec of the relay chain on its first node,
+// with the genesis balances of wallets added to the relay chain and to the parachains,
+// and returns the modified chain spec.
+// The modified chain specs of the parachains are written to their nodes, as the relay chain spec includes their genesis.
+func (c *PolkadotChain) buildChainSpec(ctx context.Context, wallets []ibc.WalletAmount) ([]byte, error) {
+	relayChainBalances, parachainBalances, err := c.genesisBalances(wallets)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, pc := range c.parachainConfig {
+		if balances := parachainBalances[pc.ChainID]; len(balances) > 0 || pc.ParaID != 0 {
+			if err := c.modifyParachainGenesis(ctx, c.ParachainNodes[i], pc.ParaID, balances); err != nil {
+				return nil, fmt.Errorf("error modifying genesis of parachain %s: %w", pc.ChainID, err)
+			}
+		}
+	}
+	firstNode := c.RelayChainNodes[0]
+	if err := firstNode.GenerateChainSpec(ctx); err != nil {
+		return nil, fmt.Errorf("error generating chain spec: %w", err)
+	}
+	fr := dockerutil.NewFileRetriever(c.logger(), firstNode.DockerClient, c.testName)
+	chainSpecBytes, err := fr.SingleFileContent(ctx, firstNode.VolumeName, firstNode.ChainSpecFilePathContainer())
+	if err != nil {
+		return nil, fmt.Errorf("error reading chain spec: %w", err)
+	}
+
+	var chainSpec interface{}
+	if err := json.Unmarshal(chainSpecBytes, &chainSpec); err != nil {
+		return nil, fmt.Errorf("error unmarshaling chain spec: %w", err)
+	}
+
+	in, err := c.chainSpecInputs(ctx, relayChainBalances)
+	if err != nil {
+		return nil, err
+	}
+	if err := ModifyChainSpec(chainSpec, in); err != nil {
+		return nil, fmt.Errorf("error modifying genesis: %w", err)
+	}
+
+	editedChainSpec, err := json.MarshalIndent(chainSpec, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling modified chain spec: %w", err)
+	}
+	return editedChainSpec, nil
+}
+
+// PreviewChainSpec returns the relay chain spec, before its conversion to a raw chain spec,
+// that Start would start the chain from with the same additional genesis wallets.
+// It must be called after Initialize and before Start.
+//
+// PreviewChainSpec is not read-only: it needs docker, as it runs the same steps as Start.
+// It runs the relay chain binary to generate the chain spec on the first relay chain node's volume,
+// and runs the parachain binaries to generate, modify, and write the parachain chain specs to every parachain node's volume,
+// since the relay chain spec includes the parachains' genesis state exported from those specs.
+// Start regenerates and rewrites the same files, so a preview does not change the chain that Start starts.
+// To preview the framework's changes to a chain spec without docker, use ModifyChainSpec.
+func (c *PolkadotChain) PreviewChainSpec(ctx context.Context, additionalGenesisWallets ...ibc.WalletAmount) ([]byte, error) {
+	return c.buildChainSpec(ctx, additionalGenesisWallets)
+}
@@ -0,0 +1,115 @@
+package polkadot_test
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/icza/dyno"
+	"github.com/strangelove-ventures/ibctest/v6/chain/polkadot"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var (
+	//go:embed testdata/chain_spec.json
+	chainSpecFixture []byte
+)
+
+func TestModifyChainSpec(t *testing.T) {
+	t.Parallel()
+
+	var chainSpec interface{}
+	require.NoError(t, json.Unmarshal(chainSpecFixture, &chainSpec))
+
+	require.NoError(t, polkadot.ModifyChainSpec(chainSpec, polkadot.ChainSpecInputs{
+		RelayChainNodes: []polkadot.GenesisRelayChainNode{
+			{MultiAddress: "/dns4/node-0/tcp/27451/p2p/peer-0", StashAddress: "stash-0", AccountAddress: "account-0", GrandpaAddress: "grandpa-0", BeefyAddress: "beefy-0"},
+			{MultiAddress: "/dns4/node-1/tcp/27451/p2p/peer-1", StashAddress: "stash-1", AccountAddress: "account-1", GrandpaAddress: "grandpa-1", BeefyAddress: "beefy-1"},
+		},
+		Parachains: []polkadot.GenesisParachain{
+			{ID: 2000, GenesisState: "0xstate", GenesisWasm: "0xwasm"},
+		},
+		Balances:   [][]interface{}{{"user", uint64(100)}},
+		ProtocolID: "rococo-local-2",
+	}))
+
+	out, err := json.Marshal(chainSpec)
+	require.NoError(t, err)
+	var modified map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &modified))
+
+	require.Equal(t, []interface{}{"/dns4/node-0/tcp/27451/p2p/peer-0", "/dns4/node-1/tcp/27451/p2p/peer-1"}, modified["bootNodes"])
+	require.Equal(t, "rococo-local-2", modified["protocolId"])
+	// Fields that the relay chain nodes do not determine are kept.
+	require.Equal(t, "rococo_local_testnet", modified["id"])
+
+	runtime, err := dyno.Get(modified, "genesis", "runtime", "runtime_genesis_config")
+	require.NoError(t, err)
+	runtimeJSON, err := json.Marshal(runtime)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"system": {"code": "0x00"},
+		"balances": {"balances": [
+			["stash-0", 1000000000000000000],
+			["account-0", 1000000000000000000],
+			["stash-1", 1000000000000000000],
+			["account-1", 1000000000000000000],
+			["user", 100]
+		]},
+		"session": {"keys": [
+			["stash-0", "stash-0", {
+				"grandpa": "grandpa-0", "babe": "account-0", "im_online": "account-0", "parachain_validator": "account-0",
+				"authority_discovery": "account-0", "para_validator": "account-0", "para_assignment": "account-0", "beefy": "beefy-0"
+			}],
+			["stash-1", "stash-1", {
+				"grandpa": "grandpa-1", "babe": "account-1", "im_online": "account-1", "parachain_validator": "account-1",
+				"authority_discovery": "account-1", "para_validator": "account-1", "para_assignment": "account-1", "beefy": "beefy-1"
+			}]
+		]},
+		"sudo": {"key": "account-0"},
+		"bridgeRococoGrandpa": {"owner": "account-0"},
+		"bridgeWococoGrandpa": {"owner": "account-0"},
+		"bridgeRococoMessages": {"owner": "account-0"},
+		"bridgeWococoMessages": {"owner": "account-0"},
+		"configuration": {"config": {"validation_upgrade_delay": 2}},
+		"paras": {"paras": [
+			[2000, {"genesis_head": "0xstate", "validation_code": "0xwasm", "parachain": true}]
+		]}
+	}`, string(runtimeJSON))
+}
+
+func TestModifyChainSpec_MissingRuntime(t *testing.T) {
+	t.Parallel()
+
+	chainSpec := map[string]interface{}{"bootNodes": []interface{}{}}
+	err := polkadot.ModifyChainSpec(chainSpec, polkadot.ChainSpecInputs{})
+	require.ErrorContains(t, err, "error setting authorities")
+}
+
+func TestModifyChainSpec_DuplicateParachainID(t *testing.T) {
+	t.Parallel()
+
+	var chainSpec interface{}
+	require.NoError(t, json.Unmarshal(chainSpecFixture, &chainSpec))
+
+	err := polkadot.ModifyChainSpec(chainSpec, polkadot.ChainSpecInputs{
+		Parachains: []polkadot.GenesisParachain{{ID: 2000}, {ID: 2000}},
+	})
+	require.ErrorContains(t, err, "parachain ID 2000 is registered more than once")
+}
+
+func TestPolkadotChain_Start_GenesisTime(t *testing.T) {
+	t.Parallel()
+
+	for _, cfg := range []ibc.ChainConfig{
+		{ChainID: "rococo-local", GenesisTime: time.Now().Add(time.Minute)},
+		{ChainID: "rococo-local", StartBeforeGenesis: true},
+	} {
+		c := polkadot.NewPolkadotChain(zap.NewNop(), t.Name(), cfg, 1, nil)
+		require.ErrorContains(t, c.Start(t.Name(), context.Background()), "genesis time is not supported for polkadot chains")
+	}
+}
@@ -0,0 +1,56 @@
+package polkadot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+)
+
+// The relay chain nodes are the validators of a polkadot chain; collators are not crashed.
+
+// CrashableValidators implements ibc.ValidatorCrasher.
+func (c *PolkadotChain) CrashableValidators() int {
+	return len(c.RelayChainNodes)
+}
+
+// KillValidator implements ibc.ValidatorCrasher.
+func (c *PolkadotChain) KillValidator(ctx context.Context, i int) error {
+	n, err := c.validator(i)
+	if err != nil {
+		return err
+	}
+	return dockerutil.KillContainer(ctx, n.DockerClient, n.containerID)
+}
+
+// RestartValidator implements ibc.ValidatorCrasher.
+func (c *PolkadotChain) RestartValidator(ctx context.Context, i int) error {
+	n, err := c.validator(i)
+	if err != nil {
+		return err
+	}
+	if err := n.StartContainer(ctx); err != nil {
+		return fmt.Errorf("failed to restart relay chain node %s: %w", n.Name(), err)
+	}
+	return nil
+}
+
+// ValidatorHeight implements ibc.ValidatorCrasher, returning the relay chain height seen by the node.
+func (c *PolkadotChain) ValidatorHeight(ctx context.Context, i int) (uint64, error) {
+	n, err := c.validator(i)
+	if err != nil {
+		return 0, err
+	}
+	header, err := n.api.RPC.Chain.GetHeaderLatest()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest header of relay chain node %s: %w", n.Name(), err)
+	}
+	return uint64(header.Number), nil
+}
+
+func (c *PolkadotChain) validator(i int) (*RelayChainNode, error) {
+	if i < 0 || i >= len(c.RelayChainNodes) {
+		return nil, fmt.Errorf("chain %s has no relay chain node %d", c.cfg.ChainID, i)
+	}
+	return c.RelayChainNodes[i], nil
+}
@@ -0,0 +1,226 @@
+package polkadot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cosmossdk.io/math"
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	gstypes "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+// SendFundsOn transfers amount of the native token of the chain at loc, the relay chain or a parachain,
+// to the SS58 address from the account named keyName in the chain's keyring, with a Balances.transfer extrinsic.
+// It returns once the transfer is included in a block, or an error if the transfer failed.
+func (c *PolkadotChain) SendFundsOn(ctx context.Context, loc Location, keyName, address string, amount math.Int) error {
+	kp, err := c.keyringPair(keyName)
+	if err != nil {
+		return err
+	}
+	dest, err := DecodeAddressSS58(address)
+	if err != nil {
+		return err
+	}
+	if amount.IsNegative() {
+		return fmt.Errorf("cannot transfer negative amount %s", amount)
+	}
+
+	api, err := c.locationAPI(loc)
+	if err != nil {
+		return err
+	}
+	meta, err := api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return fmt.Errorf("getting %s metadata: %w", loc, err)
+	}
+	call, err := gstypes.NewCall(meta, "Balances.transfer", gstypes.NewMultiAddressFromAccountID(dest), gstypes.NewUCompact(amount.BigInt()))
+	if err != nil {
+		return fmt.Errorf("creating transfer call: %w", err)
+	}
+
+	if _, err := c.submitExtrinsic(ctx, loc, api, kp, call); err != nil {
+		return fmt.Errorf("transferring %s from %s to %s on %s: %w", amount, keyName, address, loc, err)
+	}
+	return nil
+}
+
+// submitExtrinsic signs call with kp, using the next nonce of kp on the chain at loc,
+// submits it through api, and waits for its inclusion in a block.
+// It returns the hash of the block including the extrinsic, or an error if the extrinsic was not included
+// or its dispatch failed.
+func (c *PolkadotChain) submitExtrinsic(ctx context.Context, loc Location, api *gsrpc.SubstrateAPI, kp signature.KeyringPair, call gstypes.Call) (gstypes.Hash, error) {
+	genesisHash, err := api.RPC.Chain.GetBlockHash(0)
+	if err != nil {
+		return gstypes.Hash{}, fmt.Errorf("getting genesis hash: %w", err)
+	}
+	rv, err := api.RPC.State.GetRuntimeVersionLatest()
+	if err != nil {
+		return gstypes.Hash{}, fmt.Errorf("getting runtime version: %w", err)
+	}
+
+	nonces := c.Nonces(loc)
+	nonce, err := nonces.Next(kp.Address)
+	if err != nil {
+		return gstypes.Hash{}, err
+	}
+
+	ext := gstypes.NewExtrinsic(call)
+	if err := ext.Sign(kp, gstypes.SignatureOptions{
+		BlockHash:          genesisHash,
+		Era:                gstypes.ExtrinsicEra{IsImmortalEra: true},
+		GenesisHash:        genesisHash,
+		Nonce:              gstypes.NewUCompactFromUInt(nonce),
+		SpecVersion:        rv.SpecVersion,
+		Tip:                gstypes.NewUCompactFromUInt(0),
+		TransactionVersion: rv.TransactionVersion,
+	}); err != nil {
+		nonces.Reset(kp.Address)
+		return gstypes.Hash{}, fmt.Errorf("signing extrinsic: %w", err)
+	}
+
+	sub, err := api.RPC.Author.SubmitAndWatchExtrinsic(ext)
+	if err != nil {
+		nonces.Reset(kp.Address)
+		return gstypes.Hash{}, fmt.Errorf("submitting extrinsic: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	blockHash, err := waitForInclusion(ctx, sub.Chan(), sub.Err())
+	if err != nil {
+		// The next nonce is fetched again, accounting for the extrinsic if it is still in the transaction pool.
+		nonces.Reset(kp.Address)
+		return gstypes.Hash{}, err
+	}
+
+	encoded, err := gstypes.EncodeToHex(ext)
+	if err != nil {
+		return gstypes.Hash{}, fmt.Errorf("encoding extrinsic: %w", err)
+	}
+	if err := checkExtrinsicResult(api, blockHash, encoded); err != nil {
+		return gstypes.Hash{}, err
+	}
+	return blockHash, nil
+}
+
+// waitForInclusion waits for the status updates of a submitted extrinsic to report its inclusion in a block,
+// and returns the block's hash.
+// It returns an error if the extrinsic is dropped, invalid, or usurped by another extrinsic with the same nonce,
+// if the subscription fails, or if ctx is done.
+func waitForInclusion(ctx context.Context, statuses <-chan gstypes.ExtrinsicStatus, errs <-chan error) (gstypes.Hash, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return gstypes.Hash{}, ctx.Err()
+		case err := <-errs:
+			return gstypes.Hash{}, fmt.Errorf("watching extrinsic: %w", err)
+		case st := <-statuses:
+			switch {
+			case st.IsInBlock:
+				return st.AsInBlock, nil
+			case st.IsFinalized:
+				return st.AsFinalized, nil
+			case st.IsDropped:
+				return gstypes.Hash{}, fmt.Errorf("extrinsic dropped from the transaction pool")
+			case st.IsInvalid:
+				return gstypes.Hash{}, fmt.Errorf("extrinsic is invalid")
+			case st.IsUsurped:
+				return gstypes.Hash{}, fmt.Errorf("extrinsic usurped by extrinsic %s", st.AsUsurped.Hex())
+			}
+		}
+	}
+}
+
+// checkExtrinsicResult finds the hex encoded extrinsic in the block with hash blockHash,
+// and returns an error if the block's events report that its dispatch failed.
+func checkExtrinsicResult(api *gsrpc.SubstrateAPI, blockHash gstypes.Hash, encoded string) error {
+	var block struct {
+		Block struct {
+			Extrinsics []string `json:"extrinsics"`
+		} `json:"block"`
+	}
+	if err := api.Client.Call(&block, "chain_getBlock", blockHash.Hex()); err != nil {
+		return fmt.Errorf("getting block %s: %w", blockHash.Hex(), err)
+	}
+	index := -1
+	for i, xt := range block.Block.Extrinsics {
+		if strings.EqualFold(xt, encoded) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("extrinsic not found in block %s", blockHash.Hex())
+	}
+
+	meta, err := api.RPC.State.GetMetadata(blockHash)
+	if err != nil {
+		return fmt.Errorf("getting metadata: %w", err)
+	}
+	sd, err := newScaleDecoder(meta)
+	if err != nil {
+		return err
+	}
+	key, err := gstypes.CreateStorageKey(meta, "System", "Events")
+	if err != nil {
+		return fmt.Errorf("creating events storage key: %w", err)
+	}
+	ty, err := eventsStorageType(meta)
+	if err != nil {
+		return err
+	}
+	raw, err := api.RPC.State.GetStorageRaw(key, blockHash)
+	if err != nil {
+		return fmt.Errorf("getting events: %w", err)
+	}
+	if raw == nil || len(*raw) == 0 {
+		return fmt.Errorf("no events in block %s", blockHash.Hex())
+	}
+	records, err := sd.Decode(*raw, ty)
+	if err != nil {
+		return fmt.Errorf("decoding events: %w", err)
+	}
+	return extrinsicResult(records, uint64(index))
+}
+
+// extrinsicResult returns the result of the dispatch of the extrinsic at index in a block,
+// from the block's decoded System.Events storage:
+// nil for a System.ExtrinsicSuccess event of the extrinsic, or an error for a System.ExtrinsicFailed event.
+// Each record is a composite with a "phase" field, which is ApplyExtrinsic with the extrinsic's index
+// for the events emitted by an extrinsic, and an "event" field, as read by eventsFromRecords.
+func extrinsicResult(records any, index uint64) error {
+	list, ok := records.([]any)
+	if !ok {
+		return fmt.Errorf("unexpected events type %T", records)
+	}
+	for i, r := range list {
+		rec, ok := r.(map[string]any)
+		if !ok {
+			return fmt.Errorf("event record %d: unexpected type %T", i, r)
+		}
+		phase, ok := rec["phase"].(ScaleVariant)
+		if !ok || phase.Name != "ApplyExtrinsic" {
+			continue
+		}
+		if phaseIndex, ok := phase.Fields.(uint64); !ok || phaseIndex != index {
+			continue
+		}
+		pallet, ok := rec["event"].(ScaleVariant)
+		if !ok || pallet.Name != "System" {
+			continue
+		}
+		ev, ok := pallet.Fields.(ScaleVariant)
+		if !ok {
+			continue
+		}
+		switch ev.Name {
+		case "ExtrinsicSuccess":
+			return nil
+		case "ExtrinsicFailed":
+			fields, _ := ev.Fields.(map[string]any)
+			return fmt.Errorf("extrinsic failed with dispatch error %v", fields["dispatch_error"])
+		}
+	}
+	return fmt.Errorf("no result event for extrinsic %d", index)
+}
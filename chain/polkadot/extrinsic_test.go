@@ -0,0 +1,96 @@
+package polkadot
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gstypes "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForInclusion(t *testing.T) {
+	t.Parallel()
+
+	blockHash := gstypes.NewHash([]byte{1, 2, 3})
+
+	for _, tt := range []struct {
+		name     string
+		statuses []gstypes.ExtrinsicStatus
+		wantErr  string
+	}{
+		{name: "in block", statuses: []gstypes.ExtrinsicStatus{{IsReady: true}, {IsBroadcast: true}, {IsInBlock: true, AsInBlock: blockHash}}},
+		{name: "finalized", statuses: []gstypes.ExtrinsicStatus{{IsFinalized: true, AsFinalized: blockHash}}},
+		{name: "dropped", statuses: []gstypes.ExtrinsicStatus{{IsReady: true}, {IsDropped: true}}, wantErr: "dropped"},
+		{name: "invalid", statuses: []gstypes.ExtrinsicStatus{{IsInvalid: true}}, wantErr: "invalid"},
+		{name: "usurped", statuses: []gstypes.ExtrinsicStatus{{IsUsurped: true}}, wantErr: "usurped"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			statuses := make(chan gstypes.ExtrinsicStatus, len(tt.statuses))
+			for _, st := range tt.statuses {
+				statuses <- st
+			}
+			got, err := waitForInclusion(context.Background(), statuses, make(chan error))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, blockHash, got)
+		})
+	}
+
+	t.Run("subscription error", func(t *testing.T) {
+		t.Parallel()
+
+		errs := make(chan error, 1)
+		errs <- errors.New("connection closed")
+		_, err := waitForInclusion(context.Background(), make(chan gstypes.ExtrinsicStatus), errs)
+		require.ErrorContains(t, err, "connection closed")
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := waitForInclusion(ctx, make(chan gstypes.ExtrinsicStatus), make(chan error))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestExtrinsicResult(t *testing.T) {
+	t.Parallel()
+
+	record := func(phase ScaleVariant, pallet, name string, fields any) map[string]any {
+		return map[string]any{
+			"phase": phase,
+			"event": ScaleVariant{Name: pallet, Fields: ScaleVariant{Name: name, Fields: fields}},
+		}
+	}
+	applyExtrinsic := func(index uint64) ScaleVariant {
+		return ScaleVariant{Name: "ApplyExtrinsic", Fields: index}
+	}
+	dispatchError := ScaleVariant{Name: "Module", Fields: map[string]any{"index": uint64(5), "error": []byte{2, 0, 0, 0}}}
+
+	records := []any{
+		record(applyExtrinsic(0), "System", "ExtrinsicSuccess", nil),
+		record(applyExtrinsic(1), "Balances", "Transfer", nil),
+		record(applyExtrinsic(1), "System", "ExtrinsicSuccess", nil),
+		record(applyExtrinsic(2), "System", "ExtrinsicFailed", map[string]any{"dispatch_error": dispatchError}),
+		record(ScaleVariant{Name: "Finalization"}, "System", "ExtrinsicFailed", nil),
+	}
+
+	require.NoError(t, extrinsicResult(records, 0))
+	require.NoError(t, extrinsicResult(records, 1))
+
+	err := extrinsicResult(records, 2)
+	require.ErrorContains(t, err, "extrinsic failed")
+	require.ErrorContains(t, err, "Module")
+
+	require.ErrorContains(t, extrinsicResult(records, 3), "no result event")
+	require.Error(t, extrinsicResult("not a list", 0))
+}
@@ -0,0 +1,85 @@
+package polkadot
+
+import (
+	"strings"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// DefaultRelayChainFlags are passed to every relay chain node, including the relay chain node embedded in parachain nodes,
+// so that test networks neither report to public telemetry nor spend resources on production defaults.
+// ChainConfig.AdditionalStartArgs and ParachainConfig.RelayChainFlags override these per chain,
+// e.g. "--pruning=1000" to keep only recent state, or "--db-cache=1024" for a larger database cache.
+//
+// Nodes keep all state by default, as the Indexer and historical queries and proofs read past blocks.
+var DefaultRelayChainFlags = []string{
+	"--no-telemetry",
+	"--no-mdns",
+	"--db-cache=64",
+	"--pruning=archive",
+}
+
+// DefaultParachainFlags are passed to every parachain node.
+// ParachainConfig.Flags overrides these per parachain.
+var DefaultParachainFlags = []string{
+	"--no-telemetry",
+	"--no-mdns",
+	"--db-cache=64",
+	"--pruning=archive",
+}
+
+// logFlags returns the flags that set the log filter of substrate nodes to the chain's LogLevel, if any.
+// Flags set after them, e.g. in ChainConfig.AdditionalStartArgs, take precedence.
+func logFlags(cfg ibc.ChainConfig) []string {
+	if cfg.LogLevel == "" {
+		return nil
+	}
+	return []string{"--log=" + cfg.LogLevel}
+}
+
+// MergeFlags returns the defaults followed by the overrides,
+// omitting each default whose flag name is also set in overrides.
+// Flags may be given either as "--flag=value" or as "--flag value";
+// an argument following a flag without "=" is taken as that flag's value unless it starts with "-".
+func MergeFlags(defaults, overrides []string) []string {
+	overridden := make(map[string]bool, len(overrides))
+	for _, f := range splitFlags(overrides) {
+		overridden[flagName(f[0])] = true
+	}
+
+	merged := make([]string, 0, len(defaults)+len(overrides))
+	for _, f := range splitFlags(defaults) {
+		if !overridden[flagName(f[0])] {
+			merged = append(merged, f...)
+		}
+	}
+	return append(merged, overrides...)
+}
+
+// splitFlags groups args into flags, each followed by its separate value argument, if any.
+func splitFlags(args []string) [][]string {
+	var flags [][]string
+	for i := 0; i < len(args); i++ {
+		f := args[i : i+1]
+		if strings.HasPrefix(args[i], "-") && !strings.Contains(args[i], "=") &&
+			i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			f = args[i : i+2]
+			i++
+		}
+		flags = append(flags, f)
+	}
+	return flags
+}
+
+// flagAliases maps each alias of a substrate flag to the flag's name, so that setting either overrides the other.
+var flagAliases = map[string]string{
+	"--pruning": "--state-pruning",
+}
+
+func flagName(flag string) string {
+	name, _, _ := strings.Cut(flag, "=")
+	if alias, ok := flagAliases[name]; ok {
+		return alias
+	}
+	return name
+}
@@ -0,0 +1,81 @@
+package polkadot
+
+import (
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeFlags(t *testing.T) {
+	t.Parallel()
+
+	defaults := []string{"--no-telemetry", "--pruning=1000", "--db-cache=64"}
+
+	require.Equal(t, defaults, MergeFlags(defaults, nil))
+	require.Equal(t,
+		[]string{"--no-telemetry", "--db-cache=64", "--pruning=archive", "--execution=wasm"},
+		MergeFlags(defaults, []string{"--pruning=archive", "--execution=wasm"}),
+	)
+	require.Equal(t, []string{"--execution=wasm"}, MergeFlags(nil, []string{"--execution=wasm"}))
+
+	// Flags with separate values override, and are overridden, like flags with "=".
+	require.Equal(t,
+		[]string{"--no-telemetry", "--db-cache=64", "--pruning", "archive"},
+		MergeFlags(defaults, []string{"--pruning", "archive"}),
+	)
+	require.Equal(t,
+		[]string{"--no-telemetry", "--db-cache", "64", "--pruning=archive"},
+		MergeFlags([]string{"--no-telemetry", "--pruning", "1000", "--db-cache", "64"}, []string{"--pruning=archive"}),
+	)
+}
+
+func TestDefaultFlags_Archive(t *testing.T) {
+	t.Parallel()
+
+	// Historical queries, proofs, and the indexer need the state of past blocks.
+	require.Contains(t, DefaultRelayChainFlags, "--pruning=archive")
+	require.Contains(t, DefaultParachainFlags, "--pruning=archive")
+}
+
+func TestLogFlags(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, logFlags(ibc.ChainConfig{}))
+	require.Equal(t, []string{"--log=runtime=trace"}, logFlags(ibc.ChainConfig{LogLevel: "runtime=trace"}))
+	require.Equal(t,
+		[]string{"--no-telemetry", "--log=debug", "--pruning=archive"},
+		MergeFlags([]string{"--no-telemetry"}, append(logFlags(ibc.ChainConfig{LogLevel: "debug"}), "--pruning=archive")),
+	)
+}
+
+func TestPruningFlags(t *testing.T) {
+	t.Parallel()
+
+	flags := func(p ibc.Pruning, version string) []string {
+		f, err := pruningFlags(p, version)
+		require.NoError(t, err)
+		return f
+	}
+
+	require.Empty(t, flags(ibc.Pruning{}, "v0.9.29"))
+	require.Equal(t, []string{"--pruning=archive", "--blocks-pruning=archive"}, flags(ibc.Pruning{Archive: true, KeepRecent: 100}, "v0.9.29"))
+	require.Equal(t, []string{"--pruning=100", "--blocks-pruning=200"}, flags(ibc.Pruning{KeepRecent: 100, KeepBlocks: 200}, "v0.9.30"))
+	require.Equal(t, []string{"--pruning=100", "--blocks-pruning=200"}, flags(ibc.Pruning{KeepRecent: 100, KeepBlocks: 200}, "latest"))
+
+	// Older nodes only prune state.
+	require.Equal(t, []string{"--pruning=archive"}, flags(ibc.Pruning{Archive: true}, "v0.9.26"))
+	require.Equal(t, []string{"--pruning=100"}, flags(ibc.Pruning{KeepRecent: 100}, "v0.9.26"))
+	_, err := pruningFlags(ibc.Pruning{KeepBlocks: 200}, "v0.9.26")
+	require.ErrorContains(t, err, "requires polkadot v0.9.29 or later, got v0.9.26")
+
+	// --pruning is an alias of --state-pruning, so either overrides the other.
+	require.Equal(t,
+		[]string{"--no-telemetry", "--pruning=100"},
+		MergeFlags([]string{"--no-telemetry", "--state-pruning=1000"}, flags(ibc.Pruning{KeepRecent: 100}, "v0.9.29")),
+	)
+	require.Equal(t,
+		[]string{"--state-pruning=archive"},
+		MergeFlags(flags(ibc.Pruning{KeepRecent: 100}, "v0.9.29"), []string{"--state-pruning=archive"}),
+	)
+}
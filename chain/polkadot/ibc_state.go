@@ -0,0 +1,266 @@
+package polkadot
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+	gstypes "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/xxhash"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	conntypes "github.com/cosmos/ibc-go/v6/modules/core/03-connection/types"
+	chantypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
+)
+
+// Storage of the parachain's IBC pallet, keyed by SCALE encoded identifiers,
+// whose values are the protobuf encoded IBC state, as in ibc-go.
+const (
+	ibcPallet = "Ibc"
+
+	// Maps client IDs to protobuf Any encoded client states.
+	ibcClientStates = "ClientStates"
+
+	// Maps connection IDs to connection ends.
+	ibcConnections = "Connections"
+
+	// Map port and channel IDs to channel ends.
+	ibcChannels = "Channels"
+
+	// Map port and channel IDs, and packet sequences, to packet commitments, receipts and acknowledgements.
+	ibcPacketCommitments = "PacketCommitment"
+	ibcPacketReceipts    = "PacketReceipt"
+	ibcAcknowledgements  = "Acknowledgements"
+)
+
+// QueryClientState returns the state of the light client clientID in the IBC pallet of the parachain at loc.
+// The client state is returned as an Any, since parachains run light clients,
+// such as GRANDPA and BEEFY clients, that ibc-go does not implement.
+func (c *PolkadotChain) QueryClientState(ctx context.Context, loc Location, clientID string) (*codectypes.Any, error) {
+	bz, err := c.queryIBCStorage(ctx, loc, ibcClientStates, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if bz == nil {
+		return nil, fmt.Errorf("client %s not found on %s", clientID, loc)
+	}
+	var clientState codectypes.Any
+	if err := clientState.Unmarshal(bz); err != nil {
+		return nil, fmt.Errorf("decoding client state of %s on %s: %w", clientID, loc, err)
+	}
+	return &clientState, nil
+}
+
+// QueryConnection returns the connection end of connectionID in the IBC pallet of the parachain at loc.
+func (c *PolkadotChain) QueryConnection(ctx context.Context, loc Location, connectionID string) (*conntypes.ConnectionEnd, error) {
+	bz, err := c.queryIBCStorage(ctx, loc, ibcConnections, connectionID)
+	if err != nil {
+		return nil, err
+	}
+	if bz == nil {
+		return nil, fmt.Errorf("connection %s not found on %s", connectionID, loc)
+	}
+	var connection conntypes.ConnectionEnd
+	if err := connection.Unmarshal(bz); err != nil {
+		return nil, fmt.Errorf("decoding connection %s on %s: %w", connectionID, loc, err)
+	}
+	return &connection, nil
+}
+
+// QueryChannel returns the channel end of channelID on portID in the IBC pallet of the parachain at loc.
+func (c *PolkadotChain) QueryChannel(ctx context.Context, loc Location, portID, channelID string) (*chantypes.Channel, error) {
+	bz, err := c.queryIBCStorage(ctx, loc, ibcChannels, portID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if bz == nil {
+		return nil, fmt.Errorf("channel %s/%s not found on %s", portID, channelID, loc)
+	}
+	var channel chantypes.Channel
+	if err := channel.Unmarshal(bz); err != nil {
+		return nil, fmt.Errorf("decoding channel %s/%s on %s: %w", portID, channelID, loc, err)
+	}
+	return &channel, nil
+}
+
+// QueryPacketCommitments returns the commitments of the packets sent on channelID, and not yet acknowledged or timed out,
+// by the parachain at loc.
+func (c *PolkadotChain) QueryPacketCommitments(ctx context.Context, loc Location, portID, channelID string) ([]*chantypes.PacketState, error) {
+	return c.queryIBCPacketStates(ctx, loc, ibcPacketCommitments, portID, channelID)
+}
+
+// QueryPacketReceipt reports whether the parachain at loc received the packet with sequence sent to channelID.
+func (c *PolkadotChain) QueryPacketReceipt(ctx context.Context, loc Location, portID, channelID string, sequence uint64) (bool, error) {
+	bz, err := c.queryIBCStorage(ctx, loc, ibcPacketReceipts, portID, channelID, sequence)
+	if err != nil {
+		return false, err
+	}
+	return len(bz) > 0, nil
+}
+
+// QueryPacketAcknowledgements returns the acknowledgements written by the parachain at loc for the packets received on channelID.
+func (c *PolkadotChain) QueryPacketAcknowledgements(ctx context.Context, loc Location, portID, channelID string) ([]*chantypes.PacketState, error) {
+	return c.queryIBCPacketStates(ctx, loc, ibcAcknowledgements, portID, channelID)
+}
+
+// queryIBCStorage returns the bytes stored in the IBC pallet's item at keys, which are identifiers or packet sequences,
+// or nil if the entry does not exist.
+func (c *PolkadotChain) queryIBCStorage(ctx context.Context, loc Location, item string, keys ...any) ([]byte, error) {
+	api, meta, sd, err := c.ibcStorageAPI(ctx, loc)
+	if err != nil {
+		return nil, err
+	}
+	encodedKeys, err := encodeIBCStorageKeys(keys...)
+	if err != nil {
+		return nil, err
+	}
+	v, err := queryStorage(api, meta, sd, nil, ibcPallet, item, encodedKeys...)
+	if err != nil {
+		return nil, fmt.Errorf("querying %s %s.%s: %w", loc, ibcPallet, item, err)
+	}
+	if v == nil {
+		return nil, nil
+	}
+	bz, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %s.%s type %T", ibcPallet, item, v)
+	}
+	return bz, nil
+}
+
+// queryIBCPacketStates returns the entries of the IBC pallet's item for the packets of channelID, ordered by sequence.
+// The item must be keyed by port ID, channel ID and sequence, with a concat hasher for the sequence,
+// so that the sequence of each entry can be read back from its key.
+func (c *PolkadotChain) queryIBCPacketStates(ctx context.Context, loc Location, item, portID, channelID string) ([]*chantypes.PacketState, error) {
+	api, meta, sd, err := c.ibcStorageAPI(ctx, loc)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := storageEntryType(meta, ibcPallet, item)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.IsMap || len(entry.AsMap.Hashers) != 3 {
+		return nil, fmt.Errorf("%s.%s storage is not a map of port, channel and sequence", ibcPallet, item)
+	}
+	seqHasher := entry.AsMap.Hashers[2]
+	if !seqHasher.IsBlake2_128Concat && !seqHasher.IsTwox64Concat && !seqHasher.IsIdentity {
+		return nil, fmt.Errorf("%s.%s storage does not store packet sequences in its keys", ibcPallet, item)
+	}
+
+	encodedKeys, err := encodeIBCStorageKeys(portID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := storageKeyPrefix(entry, ibcPallet, item, encodedKeys...)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := api.RPC.State.GetKeysLatest(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("getting %s %s.%s keys: %w", loc, ibcPallet, item, err)
+	}
+
+	states := make([]*chantypes.PacketState, 0, len(keys))
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		raw, err := api.RPC.State.GetStorageRawLatest(key)
+		if err != nil {
+			return nil, fmt.Errorf("getting %s %s.%s storage: %w", loc, ibcPallet, item, err)
+		}
+		if raw == nil || len(*raw) == 0 {
+			continue
+		}
+		v, err := sd.Decode(*raw, entry.AsMap.Value.Int64())
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s.%s: %w", ibcPallet, item, err)
+		}
+		data, ok := v.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %s.%s type %T", ibcPallet, item, v)
+		}
+		seq, err := keySequence(key)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, &chantypes.PacketState{PortId: portID, ChannelId: channelID, Sequence: seq, Data: data})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Sequence < states[j].Sequence })
+	return states, nil
+}
+
+// ibcStorageAPI returns the client, latest metadata, and decoder of the parachain at loc, which must have an IBC pallet.
+// The client does not take a context, so ctx is only checked before querying.
+func (c *PolkadotChain) ibcStorageAPI(ctx context.Context, loc Location) (*gsrpc.SubstrateAPI, *gstypes.Metadata, *scaleDecoder, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+	api, err := c.locationAPI(loc)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	meta, err := api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("getting %s metadata: %w", loc, err)
+	}
+	sd, err := newScaleDecoder(meta)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return api, meta, sd, nil
+}
+
+// encodeIBCStorageKeys SCALE encodes the identifiers and packet sequences keying the IBC pallet's storage:
+// strings as byte vectors, and sequences as u64.
+func encodeIBCStorageKeys(keys ...any) ([][]byte, error) {
+	encoded := make([][]byte, len(keys))
+	for i, key := range keys {
+		switch key := key.(type) {
+		case string:
+			var buf bytes.Buffer
+			if err := scale.NewEncoder(&buf).Encode([]byte(key)); err != nil {
+				return nil, fmt.Errorf("encoding storage key %q: %w", key, err)
+			}
+			encoded[i] = buf.Bytes()
+		case uint64:
+			encoded[i] = make([]byte, 8)
+			binary.LittleEndian.PutUint64(encoded[i], key)
+		default:
+			return nil, fmt.Errorf("unsupported IBC storage key type %T", key)
+		}
+	}
+	return encoded, nil
+}
+
+// storageKeyPrefix returns the key prefix of the entries of the map pallet.item whose first keys are the encoded keys,
+// for listing entries with a partial key, which gstypes.CreateStorageKey does not support.
+func storageKeyPrefix(entry gstypes.StorageEntryTypeV14, pallet, item string, keys ...[]byte) (gstypes.StorageKey, error) {
+	if len(keys) > len(entry.AsMap.Hashers) {
+		return nil, fmt.Errorf("%s.%s storage has %d keys, got %d", pallet, item, len(entry.AsMap.Hashers), len(keys))
+	}
+	prefix := append(xxhash.New128([]byte(pallet)).Sum(nil), xxhash.New128([]byte(item)).Sum(nil)...)
+	for i, key := range keys {
+		h, err := entry.AsMap.Hashers[i].HashFunc()
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s storage key %d: %w", pallet, item, i, err)
+		}
+		if _, err := h.Write(key); err != nil {
+			return nil, err
+		}
+		prefix = append(prefix, h.Sum(nil)...)
+	}
+	return prefix, nil
+}
+
+// keySequence returns the packet sequence ending the storage key, encoded as u64 after a concat hasher.
+func keySequence(key gstypes.StorageKey) (uint64, error) {
+	if len(key) < 8 {
+		return 0, fmt.Errorf("storage key 0x%x is too short to end with a packet sequence", []byte(key))
+	}
+	return binary.LittleEndian.Uint64(key[len(key)-8:]), nil
+}
@@ -0,0 +1,66 @@
+package polkadot
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	gstypes "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeIBCStorageKeys(t *testing.T) {
+	t.Parallel()
+
+	keys, err := encodeIBCStorageKeys("transfer", "channel-0", uint64(3))
+	require.NoError(t, err)
+	// Byte vectors are prefixed with their compact length.
+	require.Equal(t, append([]byte{8 << 2}, "transfer"...), keys[0])
+	require.Equal(t, append([]byte{9 << 2}, "channel-0"...), keys[1])
+	require.Equal(t, []byte{3, 0, 0, 0, 0, 0, 0, 0}, keys[2])
+
+	_, err = encodeIBCStorageKeys(3)
+	require.EqualError(t, err, "unsupported IBC storage key type int")
+}
+
+func TestStorageKeyPrefix(t *testing.T) {
+	t.Parallel()
+
+	concat := gstypes.StorageHasherV10{IsBlake2_128Concat: true}
+	entry := gstypes.StorageEntryTypeV14{
+		IsMap: true,
+		AsMap: gstypes.MapTypeV14{Hashers: []gstypes.StorageHasherV10{concat, concat, concat}},
+	}
+	keys, err := encodeIBCStorageKeys("transfer", "channel-0", uint64(3))
+	require.NoError(t, err)
+
+	full, err := storageKeyPrefix(entry, ibcPallet, ibcPacketCommitments, keys...)
+	require.NoError(t, err)
+	// Two 16 byte name hashes, then each key after its 16 byte hash.
+	require.Len(t, full, 32+(16+9)+(16+10)+(16+8))
+	require.True(t, bytes.HasSuffix(full[:32+16+9], keys[0]))
+
+	prefix, err := storageKeyPrefix(entry, ibcPallet, ibcPacketCommitments, keys[:2]...)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(full, prefix))
+
+	seq, err := keySequence(full)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), seq)
+
+	_, err = storageKeyPrefix(entry, ibcPallet, ibcPacketCommitments, append(keys, keys...)...)
+	require.ErrorContains(t, err, "has 3 keys, got 6")
+}
+
+func TestPolkadotChain_QueryIBCStateCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &PolkadotChain{}
+	_, err := c.QueryClientState(ctx, RelayChain, "10-grandpa-0")
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = c.QueryPacketCommitments(ctx, RelayChain, "transfer", "channel-0")
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,466 @@
+package polkadot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	gstypes "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// Event is a runtime event emitted in a block.
+type Event struct {
+	// Height of the block containing the event.
+	Height uint64
+
+	// Position of the event within the block's events.
+	Index int
+
+	// Pallet and event name, e.g. "Assets" and "Issued", or "Sudo" and "Sudid".
+	Pallet, Name string
+
+	// Decoded event fields; see ScaleVariant for the representation.
+	Fields any
+}
+
+// PacketEvent is an IBC packet lifecycle event, extracted from the IBC pallet's events.
+type PacketEvent struct {
+	Height uint64
+
+	// Type of the IBC event, e.g. "SendPacket", "AcknowledgePacket", or "TimeoutPacket".
+	Type string
+
+	Sequence      uint64
+	SourcePort    string
+	SourceChannel string
+	DestPort      string
+	DestChannel   string
+
+	// Packet data, if the event includes it.
+	Data []byte
+}
+
+func (e PacketEvent) packet() ibc.Packet {
+	return ibc.Packet{
+		Sequence:      e.Sequence,
+		SourcePort:    e.SourcePort,
+		SourceChannel: e.SourceChannel,
+		DestPort:      e.DestPort,
+		DestChannel:   e.DestChannel,
+		Data:          e.Data,
+	}
+}
+
+// Transfer decodes the event's packet data as an ICS-20 fungible token transfer.
+// It returns an error if the event has no packet data or the data is not a fungible token transfer.
+func (e PacketEvent) Transfer() (transfertypes.FungibleTokenPacketData, error) {
+	return ibc.DecodeTransferPacketData(e.Data)
+}
+
+// Well known IBC packet event types.
+const (
+	PacketEventSend                 = "SendPacket"
+	PacketEventReceive              = "ReceivePacket"
+	PacketEventWriteAcknowledgement = "WriteAcknowledgement"
+	PacketEventAcknowledge          = "AcknowledgePacket"
+	PacketEventTimeout              = "TimeoutPacket"
+	PacketEventTimeoutOnClose       = "TimeoutOnClosePacket"
+)
+
+var packetEventTypes = map[string]bool{
+	PacketEventSend:                 true,
+	PacketEventReceive:              true,
+	PacketEventWriteAcknowledgement: true,
+	PacketEventAcknowledge:          true,
+	PacketEventTimeout:              true,
+	PacketEventTimeoutOnClose:       true,
+}
+
+// Indexer records the decoded events of the recent blocks of a substrate chain,
+// so that tests can query them by height, pallet, and event name.
+//
+// Events are decoded using the chain's own metadata,
+// so the indexer does not need Go types for the runtime's events.
+// The indexer catches up to the queried block on each query,
+// and to every new block while following the chain; see Follow.
+// Only the most recent blocks are kept; older blocks are read from the chain again when queried.
+type Indexer struct {
+	api *gsrpc.SubstrateAPI
+
+	// fetch returns the events of the block at height; indexBlock, unless replaced in tests.
+	fetch func(height uint64) ([]Event, error)
+
+	// indexMu serializes indexing, and guards the decoder state used by indexBlock.
+	indexMu   sync.Mutex
+	decoder   *scaleDecoder
+	eventsKey gstypes.StorageKey
+	eventsTy  int64
+
+	// mu guards the indexed events. It is never held across RPC calls,
+	// so that queries of indexed blocks do not wait for indexing.
+	mu        sync.Mutex
+	maxBlocks uint64
+	indexed   uint64 // Highest indexed block height.
+	events    map[uint64][]Event
+}
+
+// DefaultIndexerMaxBlocks is the number of recent blocks an Indexer keeps by default.
+const DefaultIndexerMaxBlocks = 1000
+
+// IndexerOptions configure an Indexer.
+type IndexerOptions struct {
+	// Height of the first block to index. Earlier blocks are read from the chain when queried.
+	// Zero indexes from the first block after genesis.
+	StartHeight uint64
+
+	// Number of most recent indexed blocks to keep. Zero keeps DefaultIndexerMaxBlocks.
+	MaxBlocks uint64
+}
+
+// NewIndexer returns an Indexer that reads blocks through api.
+func NewIndexer(api *gsrpc.SubstrateAPI, opts IndexerOptions) *Indexer {
+	if opts.StartHeight == 0 {
+		opts.StartHeight = 1
+	}
+	if opts.MaxBlocks == 0 {
+		opts.MaxBlocks = DefaultIndexerMaxBlocks
+	}
+	idx := &Indexer{
+		api:       api,
+		maxBlocks: opts.MaxBlocks,
+		indexed:   opts.StartHeight - 1,
+		events:    make(map[uint64][]Event),
+	}
+	idx.fetch = idx.indexBlock
+	return idx
+}
+
+// Events returns the events in the block at height, optionally filtered by pallet and event name.
+// Empty pallet or name values match any pallet or name.
+func (idx *Indexer) Events(ctx context.Context, height uint64, pallet, name string) ([]Event, error) {
+	events, err := idx.blockEvents(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Event
+	for _, e := range events {
+		if (pallet == "" || e.Pallet == pallet) && (name == "" || e.Name == name) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// PacketEvents returns the IBC packet events of the given type in the block at height.
+// An empty eventType matches all packet events.
+func (idx *Indexer) PacketEvents(ctx context.Context, height uint64, eventType string) ([]PacketEvent, error) {
+	events, err := idx.Events(ctx, height, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []PacketEvent
+	for _, e := range events {
+		for _, pe := range collectPacketEvents(e.Height, e.Fields) {
+			if eventType == "" || pe.Type == eventType {
+				out = append(out, pe)
+			}
+		}
+	}
+	return out, nil
+}
+
+// Follow indexes every new block of the chain as it is produced, until ctx is done
+// or the subscription to new blocks ends, such as when the chain's node stops.
+// It returns once the subscription is established.
+func (idx *Indexer) Follow(ctx context.Context) error {
+	sub, err := idx.api.RPC.Chain.SubscribeNewHeads()
+	if err != nil {
+		return fmt.Errorf("subscribing to new heads: %w", err)
+	}
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.Err():
+				return
+			case head, ok := <-sub.Chan():
+				if !ok {
+					return
+				}
+				// A failure is retried by the next head, or by the next query.
+				_ = idx.catchUp(ctx, uint64(head.Number))
+			}
+		}
+	}()
+	return nil
+}
+
+// blockEvents returns all events of the block at height,
+// indexing the chain through height if necessary.
+func (idx *Indexer) blockEvents(ctx context.Context, height uint64) ([]Event, error) {
+	idx.mu.Lock()
+	events, ok := idx.events[height]
+	oldest := idx.oldest()
+	idx.mu.Unlock()
+	if ok {
+		return events, nil
+	}
+
+	if height < oldest {
+		// The block is not kept; read it without indexing it.
+		idx.indexMu.Lock()
+		defer idx.indexMu.Unlock()
+		events, err := idx.fetch(height)
+		if err != nil {
+			return nil, fmt.Errorf("indexing block %d: %w", height, err)
+		}
+		return events, nil
+	}
+
+	if err := idx.catchUp(ctx, height); err != nil {
+		return nil, err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return idx.events[height], nil
+}
+
+// oldest returns the height of the oldest kept block,
+// or of the next block to index if no block is kept.
+// The kept blocks are always the contiguous range through the last indexed block.
+// idx.mu must be held.
+func (idx *Indexer) oldest() uint64 {
+	return idx.indexed + 1 - uint64(len(idx.events))
+}
+
+// catchUp indexes every block after the last indexed block, through height.
+// Blocks that would not be kept once height is indexed are skipped.
+func (idx *Indexer) catchUp(ctx context.Context, height uint64) error {
+	idx.indexMu.Lock()
+	defer idx.indexMu.Unlock()
+
+	idx.mu.Lock()
+	next := idx.indexed + 1
+	idx.mu.Unlock()
+	if height >= idx.maxBlocks && next < height-idx.maxBlocks+1 {
+		next = height - idx.maxBlocks + 1
+	}
+
+	for h := next; h <= height; h++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		events, err := idx.fetch(h)
+		if err != nil {
+			return fmt.Errorf("indexing block %d: %w", h, err)
+		}
+		idx.store(h, events)
+	}
+	return nil
+}
+
+// store records the events of the block at height, the block after the last indexed block or later,
+// and forgets the blocks that are no longer kept.
+func (idx *Indexer) store(height uint64, events []Event) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.events[height] = events
+	idx.indexed = height
+	for h := range idx.events {
+		if h+idx.maxBlocks <= height {
+			delete(idx.events, h)
+		}
+	}
+}
+
+func (idx *Indexer) indexBlock(height uint64) ([]Event, error) {
+	hash, err := idx.api.RPC.Chain.GetBlockHash(height)
+	if err != nil {
+		return nil, fmt.Errorf("getting block hash: %w", err)
+	}
+
+	if idx.decoder == nil {
+		if err := idx.loadMetadata(hash); err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := idx.api.RPC.State.GetStorageRaw(idx.eventsKey, hash)
+	if err != nil {
+		return nil, fmt.Errorf("getting events: %w", err)
+	}
+
+	if raw == nil || len(*raw) == 0 {
+		return nil, nil
+	}
+
+	records, err := idx.decoder.Decode(*raw, idx.eventsTy)
+	if err != nil {
+		// The runtime may have been upgraded since the metadata was loaded.
+		if err := idx.loadMetadata(hash); err != nil {
+			return nil, err
+		}
+		if records, err = idx.decoder.Decode(*raw, idx.eventsTy); err != nil {
+			return nil, fmt.Errorf("decoding events: %w", err)
+		}
+	}
+
+	return eventsFromRecords(height, records)
+}
+
+func (idx *Indexer) loadMetadata(hash gstypes.Hash) error {
+	meta, err := idx.api.RPC.State.GetMetadata(hash)
+	if err != nil {
+		return fmt.Errorf("getting metadata: %w", err)
+	}
+	decoder, err := newScaleDecoder(meta)
+	if err != nil {
+		return err
+	}
+	key, err := gstypes.CreateStorageKey(meta, "System", "Events")
+	if err != nil {
+		return fmt.Errorf("creating events storage key: %w", err)
+	}
+	ty, err := eventsStorageType(meta)
+	if err != nil {
+		return err
+	}
+
+	idx.decoder = decoder
+	idx.eventsKey = key
+	idx.eventsTy = ty
+	return nil
+}
+
+// eventsStorageType returns the type id of the System.Events storage value.
+func eventsStorageType(meta *gstypes.Metadata) (int64, error) {
+	entry, err := storageEntryType(meta, "System", "Events")
+	if err != nil {
+		return 0, err
+	}
+	if !entry.IsPlainType {
+		return 0, fmt.Errorf("System.Events storage is not a plain value")
+	}
+	return entry.AsPlainType.Int64(), nil
+}
+
+// storageEntryType returns the type of the named storage item in pallet.
+func storageEntryType(meta *gstypes.Metadata, pallet, item string) (gstypes.StorageEntryTypeV14, error) {
+	for _, p := range meta.AsMetadataV14.Pallets {
+		if !p.HasStorage || string(p.Storage.Prefix) != pallet {
+			continue
+		}
+		for _, entry := range p.Storage.Items {
+			if string(entry.Name) == item {
+				return entry.Type, nil
+			}
+		}
+	}
+	return gstypes.StorageEntryTypeV14{}, fmt.Errorf("%s.%s storage not found in metadata", pallet, item)
+}
+
+// eventsFromRecords converts decoded System.Events storage into Events.
+// Each record is a composite with an "event" field,
+// which is an enum of pallets, each of which is an enum of that pallet's events.
+func eventsFromRecords(height uint64, records any) ([]Event, error) {
+	list, ok := records.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected events type %T", records)
+	}
+
+	events := make([]Event, 0, len(list))
+	for i, r := range list {
+		rec, ok := r.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("event record %d: unexpected type %T", i, r)
+		}
+		pallet, ok := rec["event"].(ScaleVariant)
+		if !ok {
+			return nil, fmt.Errorf("event record %d: unexpected event type %T", i, rec["event"])
+		}
+		ev, ok := pallet.Fields.(ScaleVariant)
+		if !ok {
+			return nil, fmt.Errorf("event record %d: unexpected %s event type %T", i, pallet.Name, pallet.Fields)
+		}
+		events = append(events, Event{
+			Height: height,
+			Index:  i,
+			Pallet: pallet.Name,
+			Name:   ev.Name,
+			Fields: ev.Fields,
+		})
+	}
+	return events, nil
+}
+
+// collectPacketEvents walks v, which is typically the fields of an IBC pallet event,
+// and returns every IBC packet event found within it.
+// Walking the value rather than matching an exact layout
+// tolerates the packet events being wrapped in lists or Results.
+func collectPacketEvents(height uint64, v any) []PacketEvent {
+	var out []PacketEvent
+	switch v := v.(type) {
+	case ScaleVariant:
+		if fields, ok := v.Fields.(map[string]any); ok && packetEventTypes[v.Name] {
+			out = append(out, PacketEvent{
+				Height:        height,
+				Type:          v.Name,
+				Sequence:      uintField(fields, "sequence"),
+				SourcePort:    stringField(fields, "port_id", "source_port"),
+				SourceChannel: stringField(fields, "channel_id", "source_channel"),
+				DestPort:      stringField(fields, "dest_port", "destination_port"),
+				DestChannel:   stringField(fields, "dest_channel", "destination_channel"),
+				Data:          bytesField(fields, "data"),
+			})
+			return out
+		}
+		out = append(out, collectPacketEvents(height, v.Fields)...)
+	case []any:
+		for _, e := range v {
+			out = append(out, collectPacketEvents(height, e)...)
+		}
+	case map[string]any:
+		// Sort keys for a deterministic result.
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			out = append(out, collectPacketEvents(height, v[k])...)
+		}
+	}
+	return out
+}
+
+func stringField(fields map[string]any, names ...string) string {
+	for _, n := range names {
+		switch v := fields[n].(type) {
+		case []byte:
+			return string(v)
+		case string:
+			return v
+		}
+	}
+	return ""
+}
+
+func bytesField(fields map[string]any, name string) []byte {
+	v, _ := fields[name].([]byte)
+	return v
+}
+
+func uintField(fields map[string]any, name string) uint64 {
+	v, _ := fields[name].(uint64)
+	return v
+}
@@ -0,0 +1,80 @@
+package polkadot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIndexer returns an Indexer whose blocks each have one event named after their height,
+// and the heights it fetched.
+func fakeIndexer(opts IndexerOptions) (*Indexer, *[]uint64) {
+	var fetched []uint64
+	idx := NewIndexer(nil, opts)
+	idx.fetch = func(height uint64) ([]Event, error) {
+		fetched = append(fetched, height)
+		return []Event{{Height: height, Pallet: "System", Name: "ExtrinsicSuccess"}}, nil
+	}
+	return idx, &fetched
+}
+
+func TestIndexer_StartHeight(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	idx, fetched := fakeIndexer(IndexerOptions{StartHeight: 10})
+
+	events, err := idx.Events(ctx, 12, "System", "")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, []uint64{10, 11, 12}, *fetched)
+
+	// Blocks before the start height are read but not kept.
+	events, err = idx.Events(ctx, 3, "", "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), events[0].Height)
+	_, err = idx.Events(ctx, 3, "", "")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{10, 11, 12, 3, 3}, *fetched)
+
+	// Kept blocks are not fetched again.
+	_, err = idx.Events(ctx, 11, "", "")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{10, 11, 12, 3, 3}, *fetched)
+}
+
+func TestIndexer_MaxBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	idx, fetched := fakeIndexer(IndexerOptions{MaxBlocks: 3})
+
+	_, err := idx.Events(ctx, 2, "", "")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2}, *fetched)
+
+	// Blocks that would not be kept are skipped.
+	_, err = idx.Events(ctx, 10, "", "")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 8, 9, 10}, *fetched)
+	require.Len(t, idx.events, 3)
+
+	// Older blocks are read again.
+	_, err = idx.Events(ctx, 2, "", "")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 8, 9, 10, 2}, *fetched)
+	require.Len(t, idx.events, 3)
+}
+
+func TestIndexer_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	idx, fetched := fakeIndexer(IndexerOptions{})
+	_, err := idx.Events(ctx, 5, "", "")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, *fetched)
+}
@@ -79,6 +79,26 @@ func DeriveSr25519FromName(path []string) (*schnorrkel.MiniSecretKey, error) {
 	return miniSecret, nil
 }
 
+// DeriveSr25519FromMnemonic returns the sr25519 key of a mnemonic, without derivation path or password,
+// as substrate tools and relayers restore accounts from mnemonics.
+func DeriveSr25519FromMnemonic(mnemonic string) (*schnorrkel.MiniSecretKey, error) {
+	miniSecret, err := schnorrkel.MiniSecretKeyFromMnemonic(mnemonic, "")
+	if err != nil {
+		return nil, fmt.Errorf("error getting mini secret from mnemonic: %w", err)
+	}
+	return miniSecret, nil
+}
+
+// AddressFromMnemonic returns the SS58 address of the sr25519 account of a mnemonic. See DeriveSr25519FromMnemonic.
+func AddressFromMnemonic(mnemonic string) (string, error) {
+	miniSecret, err := DeriveSr25519FromMnemonic(mnemonic)
+	if err != nil {
+		return "", err
+	}
+	pubKey := miniSecret.Public().Encode()
+	return EncodeAddressSS58(pubKey[:])
+}
+
 func DeriveSecp256k1FromName(name string) (*secp256k1.PrivateKey, error) {
 	chainCode := make([]byte, 32)
 	derivePath := []byte{byte(len(name) << 2)}
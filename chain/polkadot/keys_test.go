@@ -1,6 +1,7 @@
 package polkadot_test
 
 import (
+	"context"
 	"crypto/ed25519"
 	"encoding/hex"
 	"testing"
@@ -8,7 +9,9 @@ import (
 	p2pCrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/strangelove-ventures/ibctest/v6/chain/polkadot"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestNodeKeyPeerID(t *testing.T) {
@@ -83,3 +86,36 @@ func Test_DeriveSecp256k1FromName(t *testing.T) {
 
 	require.Equal(t, "KW39r9CJjAVzmkf9zQ4YDb2hqfAVGdRqn53eRqyruqpxAP5YL", pubKeyEncoded)
 }
+
+func TestAddressFromMnemonic(t *testing.T) {
+	// The root account of the well known substrate development mnemonic, as reported by subkey.
+	addr, err := polkadot.AddressFromMnemonic("bottom drive obey lake curtain smoke basket hold race lonely fit walk")
+	require.NoError(t, err)
+	require.Equal(t, "5DfhGyQdFobKM8NsWvEeAKk5EQQgYe9AydgJ7rMB6E1EqRzV", addr)
+
+	_, err = polkadot.AddressFromMnemonic("not a mnemonic")
+	require.Error(t, err)
+}
+
+func TestPolkadotChainKeyring(t *testing.T) {
+	c := polkadot.NewPolkadotChain(zap.NewNop(), t.Name(), ibc.ChainConfig{ChainID: "polkadot"}, 1, nil)
+	ctx := context.Background()
+
+	const mnemonic = "bottom drive obey lake curtain smoke basket hold race lonely fit walk"
+	require.NoError(t, c.RecoverKey(ctx, "dev", mnemonic))
+	addr, err := c.GetAddress(ctx, "dev")
+	require.NoError(t, err)
+	require.Equal(t, "5DfhGyQdFobKM8NsWvEeAKk5EQQgYe9AydgJ7rMB6E1EqRzV", string(addr))
+
+	require.Error(t, c.RecoverKey(ctx, "dev", mnemonic), "duplicate key name")
+
+	require.NoError(t, c.CreateKey(ctx, "user"))
+	userAddr, err := c.GetAddress(ctx, "user")
+	require.NoError(t, err)
+	_, err = polkadot.DecodeAddressSS58(string(userAddr))
+	require.NoError(t, err)
+	require.NotEqual(t, addr, userAddr)
+
+	_, err = c.GetAddress(ctx, "missing")
+	require.Error(t, err)
+}
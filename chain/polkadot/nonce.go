@@ -0,0 +1,67 @@
+package polkadot
+
+import (
+	"fmt"
+	"sync"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+)
+
+// NonceManager assigns the nonces of extrinsics signed by the accounts of a relay chain or parachain,
+// so that helpers submitting extrinsics concurrently from the same account,
+// such as XCM transfers, IBC transfers, and sudo calls from a dev account, do not collide on nonces.
+//
+// The first nonce of an account is fetched from the chain, accounting for extrinsics in the transaction pool,
+// and later nonces are assigned locally in order.
+type NonceManager struct {
+	fetch func(address string) (uint64, error)
+
+	mu   sync.Mutex
+	next map[string]uint64
+}
+
+func newNonceManager(fetch func(address string) (uint64, error)) *NonceManager {
+	return &NonceManager{
+		fetch: fetch,
+		next:  make(map[string]uint64),
+	}
+}
+
+// Next returns the nonce to sign the next extrinsic of the account with the SS58 address.
+// Each call returns a distinct nonce, so the caller must submit an extrinsic with it,
+// or call Reset if the extrinsic could not be submitted.
+func (m *NonceManager) Next(address string) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nonce, ok := m.next[address]
+	if !ok {
+		var err error
+		nonce, err = m.fetch(address)
+		if err != nil {
+			return 0, fmt.Errorf("fetch next nonce of %s: %w", address, err)
+		}
+	}
+	m.next[address] = nonce + 1
+	return nonce, nil
+}
+
+// Reset forgets the nonces assigned to the account with the SS58 address,
+// so that the next nonce is fetched from the chain again.
+// Call Reset when an extrinsic signed with a nonce from Next was not accepted into the transaction pool,
+// as later extrinsics of the account cannot be included until the nonce is used.
+func (m *NonceManager) Reset(address string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.next, address)
+}
+
+// fetchAccountNextIndex returns the next nonce of the account with the SS58 address,
+// including the extrinsics in the node's transaction pool.
+func fetchAccountNextIndex(api *gsrpc.SubstrateAPI, address string) (uint64, error) {
+	var next uint64
+	if err := api.Client.Call(&next, "system_accountNextIndex", address); err != nil {
+		return 0, err
+	}
+	return next, nil
+}
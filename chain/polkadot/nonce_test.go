@@ -0,0 +1,59 @@
+package polkadot
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceManager(t *testing.T) {
+	t.Parallel()
+
+	fetched := map[string]uint64{"alice": 5, "bob": 0}
+	fetches := 0
+	m := newNonceManager(func(address string) (uint64, error) {
+		fetches++
+		nonce, ok := fetched[address]
+		if !ok {
+			return 0, errors.New("unknown account")
+		}
+		return nonce, nil
+	})
+
+	// Concurrent callers get distinct, consecutive nonces.
+	var mu sync.Mutex
+	var nonces []uint64
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := m.Next("alice")
+			require.NoError(t, err)
+			mu.Lock()
+			nonces = append(nonces, nonce)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	require.ElementsMatch(t, []uint64{5, 6, 7, 8, 9, 10, 11, 12, 13, 14}, nonces)
+	require.Equal(t, 1, fetches)
+
+	// Accounts are independent.
+	nonce, err := m.Next("bob")
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), nonce)
+
+	// Reset fetches the nonce again.
+	fetched["alice"] = 7
+	m.Reset("alice")
+	nonce, err = m.Next("alice")
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), nonce)
+	require.Equal(t, 3, fetches)
+
+	_, err = m.Next("charlie")
+	require.EqualError(t, err, "fetch next nonce of charlie: unknown account")
+}
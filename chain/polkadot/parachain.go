@@ -0,0 +1,164 @@
+package polkadot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+	"github.com/strangelove-ventures/ibctest/v6/test"
+)
+
+// ParachainHead is the latest head of a parachain included in the relay chain.
+type ParachainHead struct {
+	// Block number of the parachain head.
+	Number uint64
+
+	// The SCALE encoded parachain header.
+	Data []byte
+}
+
+// CandidatePendingAvailability is a parachain block candidate that has been backed on the relay chain,
+// and is waiting for availability before it is included.
+type CandidatePendingAvailability struct {
+	// Relay chain block numbers of the candidate's relay parent and of the block it was backed in.
+	RelayParentNumber, BackedInNumber uint64
+}
+
+// ParachainHead returns the latest head of the parachain with ID paraID included in the relay chain,
+// from the relay chain's Paras.Heads storage.
+func (c *PolkadotChain) ParachainHead(ctx context.Context, paraID uint32) (ParachainHead, error) {
+	v, err := c.queryRelayParaStorage("Paras", "Heads", paraID)
+	if err != nil {
+		return ParachainHead{}, err
+	}
+	if v == nil {
+		return ParachainHead{}, fmt.Errorf("parachain %d has no head on the relay chain", paraID)
+	}
+	data, ok := v.([]byte)
+	if !ok {
+		return ParachainHead{}, fmt.Errorf("unexpected Paras.Heads type %T", v)
+	}
+
+	n, err := headNumber(data)
+	if err != nil {
+		return ParachainHead{}, fmt.Errorf("parachain %d head: %w", paraID, err)
+	}
+	return ParachainHead{Number: n, Data: data}, nil
+}
+
+// headNumber returns the block number of the SCALE encoded substrate header,
+// which starts with the 32 byte parent hash, followed by the compact block number.
+func headNumber(header []byte) (uint64, error) {
+	if len(header) < 33 {
+		return 0, fmt.Errorf("header is too short: %d bytes", len(header))
+	}
+	n, err := scale.NewDecoder(bytes.NewReader(header[32:])).DecodeUintCompact()
+	if err != nil {
+		return 0, fmt.Errorf("decoding header number: %w", err)
+	}
+	return n.Uint64(), nil
+}
+
+// PendingAvailability returns the candidate of the parachain with ID paraID that is backed and pending availability
+// on the relay chain, from the relay chain's ParaInclusion.PendingAvailability storage,
+// or nil if there is none.
+func (c *PolkadotChain) PendingAvailability(ctx context.Context, paraID uint32) (*CandidatePendingAvailability, error) {
+	v, err := c.queryRelayParaStorage("ParaInclusion", "PendingAvailability", paraID)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	fields, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected ParaInclusion.PendingAvailability type %T", v)
+	}
+	relayParent, _ := fields["relay_parent_number"].(uint64)
+	backedIn, _ := fields["backed_in_number"].(uint64)
+	return &CandidatePendingAvailability{RelayParentNumber: relayParent, BackedInNumber: backedIn}, nil
+}
+
+// AssertParachainProducingBlocks returns an error unless the relay chain includes a new head
+// of the parachain with ID paraID within window relay chain blocks.
+// It is intended for smoke tests of collator images.
+func (c *PolkadotChain) AssertParachainProducingBlocks(ctx context.Context, paraID uint32, window uint64) error {
+	before, err := c.ParachainHead(ctx, paraID)
+	if err != nil {
+		return err
+	}
+
+	if err := test.WaitForBlocks(ctx, int(window), relayChainHeighter{api: c.RelayChainNodes[0].api}); err != nil {
+		return fmt.Errorf("waiting for relay chain blocks: %w", err)
+	}
+
+	after, err := c.ParachainHead(ctx, paraID)
+	if err != nil {
+		return err
+	}
+	if after.Number > before.Number {
+		return nil
+	}
+
+	// Help distinguish collators that do not produce candidates from candidates that are never included.
+	pending, err := c.PendingAvailability(ctx, paraID)
+	if err != nil {
+		return fmt.Errorf("parachain %d included no blocks after %d in %d relay chain blocks; failed to query pending candidates: %w", paraID, before.Number, window, err)
+	}
+	if pending != nil {
+		return fmt.Errorf(
+			"parachain %d included no blocks after %d in %d relay chain blocks; a candidate backed in relay chain block %d is pending availability",
+			paraID, before.Number, window, pending.BackedInNumber,
+		)
+	}
+	return fmt.Errorf("parachain %d included no blocks after %d in %d relay chain blocks, and no candidate is pending availability", paraID, before.Number, window)
+}
+
+// queryRelayParaStorage returns the decoded value of the relay chain's pallet.item storage map entry for paraID,
+// or nil if the entry does not exist.
+func (c *PolkadotChain) queryRelayParaStorage(pallet, item string, paraID uint32) (any, error) {
+	api, err := c.locationAPI(RelayChain)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return nil, fmt.Errorf("getting relay chain metadata: %w", err)
+	}
+	sd, err := newScaleDecoder(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := storageEntryType(meta, pallet, item)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.IsMap {
+		return nil, fmt.Errorf("%s.%s storage is not a map", pallet, item)
+	}
+	key, err := sd.EncodeUint(entry.AsMap.Key.Int64(), strconv.FormatUint(uint64(paraID), 10))
+	if err != nil {
+		return nil, fmt.Errorf("encoding parachain ID: %w", err)
+	}
+
+	v, err := queryStorage(api, meta, sd, nil, pallet, item, key)
+	if err != nil {
+		return nil, fmt.Errorf("querying %s.%s for parachain %d: %w", pallet, item, paraID, err)
+	}
+	return v, nil
+}
+
+// relayChainHeighter reports the relay chain height,
+// whereas PolkadotChain.Height reports the parachain height when there is a parachain.
+type relayChainHeighter struct {
+	api *gsrpc.SubstrateAPI
+}
+
+func (h relayChainHeighter) Height(ctx context.Context) (uint64, error) {
+	header, err := h.api.RPC.Chain.GetHeaderLatest()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(header.Number), nil
+}
@@ -7,17 +7,21 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/avast/retry-go/v4"
 	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/strangelove-ventures/ibctest/v6/transcript"
 	"go.uber.org/zap"
 )
 
@@ -40,16 +44,27 @@ type ParachainNode struct {
 	Flags           []string
 	RelayChainFlags []string
 
-	api         *gsrpc.SubstrateAPI
-	hostWsPort  string
-	hostRpcPort string
+	api *gsrpc.SubstrateAPI
+
+	// Whether the node runs with the chain spec written by WriteChainSpec, instead of its built-in chain spec.
+	customChainSpec bool
+
+	// Host addresses of the node's ports, set once the container has started.
+	hostPortsMu        sync.RWMutex
+	hostWsPort         string
+	hostRpcPort        string
+	hostPrometheusPort string
+
+	logWatcher *dockerutil.LogWatcher
+	supervisor *dockerutil.Supervisor
 }
 
 type ParachainNodes []*ParachainNode
 
-// Name returns the name of the test node container.
+// Name returns the name of the test node container,
+// which includes the relay chain's ID so that relay chains in the same test may run the same parachain.
 func (pn *ParachainNode) Name() string {
-	return fmt.Sprintf("%s-%d-%s-%s", pn.Bin, pn.Index, pn.ChainID, dockerutil.SanitizeContainerName(pn.TestName))
+	return fmt.Sprintf("%s-%d-%s-%s-%s", pn.Bin, pn.Index, pn.ChainID, pn.Chain.Config().ChainID, dockerutil.SanitizeContainerName(pn.TestName))
 }
 
 // HostName returns the docker hostname of the test container.
@@ -80,6 +95,52 @@ func (pn *ParachainNode) RawChainSpecFilePathRelative() string {
 	return fmt.Sprintf("%s-raw.json", pn.Chain.Config().ChainID)
 }
 
+// ChainSpecFilePathFull returns the full path to the parachain's chain spec file
+// within the container.
+func (pn *ParachainNode) ChainSpecFilePathFull() string {
+	return filepath.Join(pn.NodeHome(), pn.ChainSpecFilePathRelative())
+}
+
+// ChainSpecFilePathRelative returns the relative path to the parachain's chain spec file
+// within the container.
+func (pn *ParachainNode) ChainSpecFilePathRelative() string {
+	return fmt.Sprintf("%s.json", pn.ChainID)
+}
+
+// GenerateChainSpec builds the chain spec for the configured chain ID.
+func (pn *ParachainNode) GenerateChainSpec(ctx context.Context) ([]byte, error) {
+	cmd := []string{
+		pn.Bin,
+		"build-spec",
+		fmt.Sprintf("--chain=%s", pn.ChainID),
+		"--disable-default-bootnode",
+	}
+	res := pn.Exec(ctx, cmd, nil)
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return res.Stdout, nil
+}
+
+// WriteChainSpec writes chainSpec to the node's volume, and makes the node use it
+// instead of the built-in chain spec of the configured chain ID.
+func (pn *ParachainNode) WriteChainSpec(ctx context.Context, chainSpec []byte) error {
+	fw := dockerutil.NewFileWriter(pn.logger(), pn.DockerClient, pn.TestName)
+	if err := fw.WriteFile(ctx, pn.VolumeName, pn.ChainSpecFilePathRelative(), chainSpec); err != nil {
+		return fmt.Errorf("error writing parachain chain spec: %w", err)
+	}
+	pn.customChainSpec = true
+	return nil
+}
+
+// chainFlag returns the flag selecting the parachain's chain spec.
+func (pn *ParachainNode) chainFlag() string {
+	if pn.customChainSpec {
+		return fmt.Sprintf("--chain=%s", pn.ChainSpecFilePathFull())
+	}
+	return fmt.Sprintf("--chain=%s", pn.ChainID)
+}
+
 // PeerID returns the public key of the node key for p2p.
 func (pn *ParachainNode) PeerID() (string, error) {
 	id, err := peer.IDFromPrivateKey(pn.NodeKey)
@@ -95,7 +156,7 @@ func (pn *ParachainNode) MultiAddress() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("/dns4/%s/tcp/%s/p2p/%s", pn.HostName(), strings.Split(rpcPort, "/")[0], peerId), nil
+	return fmt.Sprintf("/dns4/%s/tcp/%s/p2p/%s", pn.HostName(), strings.Split(p2pPort, "/")[0], peerId), nil
 }
 
 type GetParachainIDResponse struct {
@@ -107,7 +168,7 @@ func (pn *ParachainNode) ParachainID(ctx context.Context) (int, error) {
 	cmd := []string{
 		pn.Bin,
 		"build-spec",
-		fmt.Sprintf("--chain=%s", pn.ChainID),
+		pn.chainFlag(),
 	}
 	res := pn.Exec(ctx, cmd, nil)
 	if res.Err != nil {
@@ -125,7 +186,7 @@ func (pn *ParachainNode) ExportGenesisWasm(ctx context.Context) (string, error)
 	cmd := []string{
 		pn.Bin,
 		"export-genesis-wasm",
-		fmt.Sprintf("--chain=%s", pn.ChainID),
+		pn.chainFlag(),
 	}
 	res := pn.Exec(ctx, cmd, nil)
 	if res.Err != nil {
@@ -139,7 +200,7 @@ func (pn *ParachainNode) ExportGenesisState(ctx context.Context) (string, error)
 	cmd := []string{
 		pn.Bin,
 		"export-genesis-state",
-		fmt.Sprintf("--chain=%s", pn.ChainID),
+		pn.chainFlag(),
 	}
 	res := pn.Exec(ctx, cmd, nil)
 	if res.Err != nil {
@@ -168,6 +229,7 @@ func (pn *ParachainNode) CreateNodeContainer(ctx context.Context) error {
 	cmd := []string{
 		pn.Bin,
 		fmt.Sprintf("--ws-port=%s", strings.Split(wsPort, "/")[0]),
+		fmt.Sprintf("--rpc-port=%s", strings.Split(rpcPort, "/")[0]),
 		"--collator",
 		fmt.Sprintf("--node-key=%s", hex.EncodeToString(nodeKey[0:32])),
 		fmt.Sprintf("--%s", IndexedName[pn.Index]),
@@ -176,38 +238,56 @@ func (pn *ParachainNode) CreateNodeContainer(ctx context.Context) error {
 		"--prometheus-external",
 		"--rpc-cors=all",
 		fmt.Sprintf("--prometheus-port=%s", strings.Split(prometheusPort, "/")[0]),
-		fmt.Sprintf("--listen-addr=/ip4/0.0.0.0/tcp/%s", strings.Split(rpcPort, "/")[0]),
+		fmt.Sprintf("--listen-addr=/ip4/0.0.0.0/tcp/%s", strings.Split(p2pPort, "/")[0]),
 		fmt.Sprintf("--public-addr=%s", multiAddress),
 		"--base-path", pn.NodeHome(),
-		fmt.Sprintf("--chain=%s", pn.ChainID),
+		pn.chainFlag(),
+	}
+	chainCfg := pn.Chain.Config()
+	pruning, err := pruningFlags(chainCfg.Pruning, pn.Image.Version)
+	if err != nil {
+		return fmt.Errorf("node %s: %w", pn.Name(), err)
 	}
-	cmd = append(cmd, pn.Flags...)
+	cmd = append(cmd, MergeFlags(DefaultParachainFlags, append(append(logFlags(chainCfg), pruning...), pn.Flags...))...)
 	cmd = append(cmd, "--", fmt.Sprintf("--chain=%s", pn.RawChainSpecFilePathFull()))
-	cmd = append(cmd, pn.RelayChainFlags...)
+	cmd = append(cmd, MergeFlags(DefaultRelayChainFlags, pn.RelayChainFlags)...)
+	start := pn.Chain.Config().StartCommand(cmd)
 	pn.logger().
 		Info("Running command",
-			zap.String("command", strings.Join(cmd, " ")),
+			zap.String("command", strings.Join(start.Cmd, " ")),
+			zap.Strings("env", start.Env),
 			zap.String("container", pn.Name()),
 		)
+	testreporter.TrackNodeCommand(ctx, pn.Name(), start.Cmd, start.Env)
 
+	platform, err := dockerutil.ParsePlatform(pn.Image.Platform)
+	if err != nil {
+		return err
+	}
+	publishAll, portBindings, err := hostPortPublishing(pn.Chain.Config()).ReserveHostConfig(pn.Name(), exposedPorts)
+	if err != nil {
+		return err
+	}
 	cc, err := pn.DockerClient.ContainerCreate(
 		ctx,
 		&container.Config{
 			Image: pn.Image.Ref(),
 
 			Entrypoint: []string{},
-			Cmd:        cmd,
+			Cmd:        start.Cmd,
+			Env:        start.Env,
 
 			Hostname: pn.HostName(),
 			User:     dockerutil.GetRootUserString(),
 
-			Labels: map[string]string{dockerutil.CleanupLabel: pn.TestName},
+			Labels: dockerutil.Labels(pn.TestName),
 
 			ExposedPorts: exposedPorts,
 		},
 		&container.HostConfig{
 			Binds:           pn.Bind(),
-			PublishAllPorts: true,
+			PublishAllPorts: publishAll,
+			PortBindings:    portBindings,
 			AutoRemove:      false,
 			DNS:             []string{},
 		},
@@ -216,20 +296,44 @@ func (pn *ParachainNode) CreateNodeContainer(ctx context.Context) error {
 				pn.NetworkID: {},
 			},
 		},
-		nil,
+		platform,
 		pn.Name(),
 	)
 	if err != nil {
+		dockerutil.ReleaseHostPorts(pn.Name())
 		return err
 	}
 	pn.containerID = cc.ID
 	return nil
 }
 
-// StopContainer stops the relay chain node container, waiting at most 30 seconds.
+// StopContainer gracefully stops the parachain node container. See dockerutil.StopContainer.
 func (pn *ParachainNode) StopContainer(ctx context.Context) error {
-	timeout := 30 * time.Second
-	return pn.DockerClient.ContainerStop(ctx, pn.containerID, &timeout)
+	return dockerutil.StopContainer(ctx, pn.DockerClient, pn.containerID)
+}
+
+// RemoveContainer removes the parachain node container and releases its reserved host ports.
+func (pn *ParachainNode) RemoveContainer(ctx context.Context) error {
+	err := pn.DockerClient.ContainerRemove(ctx, pn.containerID, dockertypes.ContainerRemoveOptions{
+		Force:         true,
+		RemoveVolumes: true,
+	})
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("remove container %s: %w", pn.Name(), err)
+	}
+	dockerutil.ReleaseHostPorts(pn.Name())
+	return nil
+}
+
+// HostEndpoints returns the addresses of the node's RPC and websocket ports on the host,
+// or false if the node's container has not started yet.
+func (pn *ParachainNode) HostEndpoints() (HostEndpoints, bool) {
+	pn.hostPortsMu.RLock()
+	defer pn.hostPortsMu.RUnlock()
+	if pn.hostWsPort == "" {
+		return HostEndpoints{}, false
+	}
+	return HostEndpoints{RPC: pn.hostRpcPort, WS: pn.hostWsPort}, true
 }
 
 // StartContainer starts the container after it is built by CreateNodeContainer.
@@ -238,19 +342,29 @@ func (pn *ParachainNode) StartContainer(ctx context.Context) error {
 		return err
 	}
 
-	c, err := pn.DockerClient.ContainerInspect(ctx, pn.containerID)
+	// Set the host ports once since they will not change after the container has started.
+	portsCtx, cancel := context.WithTimeout(ctx, hostPortsTimeout)
+	defer cancel()
+	publishing := hostPortPublishing(pn.Chain.Config())
+	hostPorts, err := dockerutil.ResolveHostPorts(portsCtx, pn.DockerClient, pn.containerID, pn.HostName(), publishing, wsPort, rpcPort, prometheusPort)
 	if err != nil {
 		return err
 	}
+	pn.hostPortsMu.Lock()
+	pn.hostWsPort, pn.hostRpcPort, pn.hostPrometheusPort = hostPorts[0], hostPorts[1], hostPorts[2]
+	pn.hostPortsMu.Unlock()
 
-	// Set the host ports once since they will not change after the container has started.
-	pn.hostWsPort = dockerutil.GetHostPort(c, wsPort)
-	pn.hostRpcPort = dockerutil.GetHostPort(c, rpcPort)
+	if pn.logWatcher != nil {
+		pn.logWatcher.Watch(pn.containerID, pn.Name())
+	}
+	if pn.supervisor != nil {
+		pn.supervisor.Watch(pn.containerID, pn.Name())
+	}
 
 	var api *gsrpc.SubstrateAPI
 	if err = retry.Do(func() error {
 		var err error
-		api, err = gsrpc.NewSubstrateAPI("ws://" + pn.hostWsPort)
+		api, err = gsrpc.NewSubstrateAPI("ws://" + hostPorts[0])
 		return err
 	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr); err != nil {
 		return err
@@ -262,11 +376,13 @@ func (pn *ParachainNode) StartContainer(ctx context.Context) error {
 
 // Exec run a container for a specific job and block until the container exits.
 func (pn *ParachainNode) Exec(ctx context.Context, cmd []string, env []string) dockerutil.ContainerExecResult {
-	job := dockerutil.NewImage(pn.log, pn.DockerClient, pn.NetworkID, pn.TestName, pn.Image.Repository, pn.Image.Version)
+	transcript.RecordChainExec(ctx, pn.Chain.Config().ChainID, pn.Name(), cmd, env)
+	job := dockerutil.NewImage(pn.log, pn.DockerClient, pn.NetworkID, pn.TestName, pn.Image.Repository, pn.Image.Tag())
 	opts := dockerutil.ContainerOptions{
-		Binds: pn.Bind(),
-		Env:   env,
-		User:  dockerutil.GetRootUserString(),
+		Binds:    pn.Bind(),
+		Env:      env,
+		User:     dockerutil.GetRootUserString(),
+		Platform: pn.Image.Platform,
 	}
 	return job.Run(ctx, cmd, opts)
 }
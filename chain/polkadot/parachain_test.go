@@ -0,0 +1,250 @@
+package polkadot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadNumber(t *testing.T) {
+	t.Parallel()
+
+	parentHash := bytes.Repeat([]byte{0xab}, 32)
+
+	for _, tt := range []struct {
+		encoded []byte
+		want    uint64
+	}{
+		{[]byte{0x00}, 0},
+		{[]byte{0x1c}, 7},
+		{[]byte{0xa1, 0x0f}, 1000},
+	} {
+		// The state root and the rest of the header follow the number.
+		header := append(append(append([]byte{}, parentHash...), tt.encoded...), bytes.Repeat([]byte{0xcd}, 32)...)
+		n, err := headNumber(header)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, n)
+	}
+
+	_, err := headNumber(parentHash)
+	require.Error(t, err)
+}
+
+func TestHostEndpoints(t *testing.T) {
+	t.Parallel()
+
+	c := NewPolkadotChain(nil, t.Name(), ibc.ChainConfig{Denom: "uDOT"}, 1, []ParachainConfig{
+		{ChainID: "dali-dev", Denom: "PICA"},
+	})
+	relay := &RelayChainNode{}
+	c.RelayChainNodes = RelayChainNodes{relay}
+	c.ParachainNodes = []ParachainNodes{{&ParachainNode{}}}
+
+	require.Empty(t, c.GetHostRPCAddress())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.HostEndpoints(ctx, RelayChain)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = c.HostEndpoints(context.Background(), Parachain("unknown"))
+	require.Error(t, err)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		relay.hostPortsMu.Lock()
+		relay.hostWsPort, relay.hostRpcPort = "localhost:1000", "localhost:1001"
+		relay.hostPortsMu.Unlock()
+	}()
+	e, err := c.HostEndpoints(context.Background(), RelayChain)
+	require.NoError(t, err)
+	require.Equal(t, HostEndpoints{RPC: "localhost:1001", WS: "localhost:1000"}, e)
+
+	// The chain's default endpoints are the parachain's, which has not started.
+	require.Empty(t, c.GetHostRPCAddress())
+}
+
+func TestEndpoints(t *testing.T) {
+	t.Parallel()
+
+	c := NewPolkadotChain(nil, "TestEndpoints", ibc.ChainConfig{ChainID: "rococo-local", Denom: "uDOT"}, 1, []ParachainConfig{
+		{ChainID: "dali-dev", Denom: "PICA"},
+	})
+	relay := &RelayChainNode{Chain: c, TestName: "TestEndpoints"}
+	para := &ParachainNode{Chain: c, Bin: "parachain", ChainID: "dali-dev", TestName: "TestEndpoints"}
+	c.RelayChainNodes = RelayChainNodes{relay}
+	c.ParachainNodes = []ParachainNodes{{para}}
+
+	e, err := c.WSEndpoint(RelayChain)
+	require.NoError(t, err)
+	require.Equal(t, ibc.Endpoint{Transport: ibc.TransportWebSocket, Address: relay.HostName() + ":27451"}, e)
+	require.Equal(t, "ws://"+relay.HostName()+":27451", e.URL())
+
+	e, err = c.HTTPEndpoint(Parachain("dali-dev"))
+	require.NoError(t, err)
+	require.Equal(t, ibc.Endpoint{Transport: ibc.TransportHTTP, Address: para.HostName() + ":27454"}, e)
+
+	_, err = c.WSEndpoint(Parachain("unknown"))
+	require.Error(t, err)
+
+	// The chain's default endpoints are the parachain's.
+	e, err = c.Endpoint(ibc.TransportWebSocket, false)
+	require.NoError(t, err)
+	require.Equal(t, c.GetGRPCAddress(), e.Address)
+	require.Equal(t, para.HostName()+":27451", e.Address)
+
+	_, err = c.Endpoint(ibc.TransportWebSocket, true)
+	require.ErrorContains(t, err, "has not started")
+
+	para.hostPortsMu.Lock()
+	para.hostWsPort, para.hostRpcPort = "localhost:1000", "localhost:1001"
+	para.hostPortsMu.Unlock()
+	e, err = c.Endpoint(ibc.TransportHTTP, true)
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost:1001", e.URL())
+
+	_, err = c.Endpoint("grpc", false)
+	require.ErrorContains(t, err, `transport "grpc"`)
+}
+
+func TestNodeHostEndpoints(t *testing.T) {
+	t.Parallel()
+
+	c := NewPolkadotChain(nil, "TestNodeHostEndpoints", ibc.ChainConfig{ChainID: "rococo-local", Denom: "uDOT"}, 2, []ParachainConfig{
+		{ChainID: "dali-dev", Denom: "PICA"},
+	})
+	relay0 := &RelayChainNode{Chain: c, Index: 0, TestName: "TestNodeHostEndpoints"}
+	relay1 := &RelayChainNode{Chain: c, Index: 1, TestName: "TestNodeHostEndpoints"}
+	para := &ParachainNode{Chain: c, Bin: "parachain", ChainID: "dali-dev", TestName: "TestNodeHostEndpoints"}
+	c.RelayChainNodes = RelayChainNodes{relay0, relay1}
+	c.ParachainNodes = []ParachainNodes{{para}}
+
+	require.Empty(t, c.NodeHostEndpoints())
+
+	relay1.hostWsPort, relay1.hostRpcPort = "127.0.0.1:1000", "127.0.0.1:1001"
+	para.hostWsPort, para.hostRpcPort = "127.0.0.1:2000", "127.0.0.1:2001"
+
+	require.Equal(t, []NodeHostEndpoints{
+		{Location: RelayChain, Index: 1, Name: relay1.Name(), HostEndpoints: HostEndpoints{RPC: "127.0.0.1:1001", WS: "127.0.0.1:1000"}},
+		{Location: Parachain("dali-dev"), Index: 0, Name: para.Name(), HostEndpoints: HostEndpoints{RPC: "127.0.0.1:2001", WS: "127.0.0.1:2000"}},
+	}, c.NodeHostEndpoints())
+
+	e, err := c.HostEndpointsOfNode(context.Background(), RelayChain, 1)
+	require.NoError(t, err)
+	require.Equal(t, "https://polkadot.js.org/apps/?rpc=ws%3A%2F%2F127.0.0.1%3A1000#/explorer", e.PolkadotJSURL())
+
+	_, err = c.HostEndpointsOfNode(context.Background(), RelayChain, 2)
+	require.EqualError(t, err, "no node 2 for relay chain")
+	_, err = c.HostEndpointsOfNode(context.Background(), Parachain("dali-dev"), 1)
+	require.EqualError(t, err, "no node 1 for parachain dali-dev")
+
+	require.Empty(t, HostEndpoints{}.PolkadotJSURL())
+}
+
+func TestHostPortPublishing(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, hostPortPublishing(ibc.ChainConfig{}).Publish)
+
+	cfg := ibc.ChainConfig{HostPorts: ibc.HostPorts{Publish: []string{rpcPort}}}
+	require.Equal(t, []string{rpcPort, wsPort}, hostPortPublishing(cfg).Publish)
+	require.Equal(t, []string{rpcPort}, cfg.HostPorts.Publish, "config must not be modified")
+
+	cfg = ibc.ChainConfig{HostPorts: ibc.HostPorts{Publish: []string{wsPort}}}
+	require.Equal(t, []string{wsPort}, hostPortPublishing(cfg).Publish)
+
+	cfg = ibc.ChainConfig{HostPorts: ibc.HostPorts{InternalOnly: true}}
+	require.False(t, hostPortPublishing(cfg).Published(wsPort))
+}
+
+func TestModifyParachainChainSpec(t *testing.T) {
+	t.Parallel()
+
+	var chainSpec interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"para_id": 2000,
+		"genesis": {"runtime": {
+			"parachainInfo": {"parachainId": 2000},
+			"balances": {"balances": [["alice", 10]]}
+		}}
+	}`), &chainSpec))
+
+	require.NoError(t, modifyParachainChainSpec(chainSpec, 2087, [][]interface{}{{"bob", uint64(20)}}))
+	out, err := json.Marshal(chainSpec)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"para_id": 2087,
+		"genesis": {"runtime": {
+			"parachainInfo": {"parachainId": 2087},
+			"balances": {"balances": [["alice", 10], ["bob", 20]]}
+		}}
+	}`, string(out))
+
+	// Without an override or balances, the chain spec is kept.
+	require.NoError(t, json.Unmarshal([]byte(`{"para_id": 2000, "genesis": {"runtime": {}}}`), &chainSpec))
+	require.NoError(t, modifyParachainChainSpec(chainSpec, 0, nil))
+	out, err = json.Marshal(chainSpec)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"para_id": 2000, "genesis": {"runtime": {}}}`, string(out))
+}
+
+func TestValidateParachainConfigs(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateParachainConfigs([]ParachainConfig{
+		{ChainID: "dev", ParaID: 2000},
+		{ChainID: "local", ParaID: 2001},
+		{ChainID: "other"},
+	}))
+	require.EqualError(t, validateParachainConfigs([]ParachainConfig{
+		{ChainID: "dev", ParaID: 2000},
+		{ChainID: "local", ParaID: 2000},
+	}), "parachains dev and local have the same parachain ID 2000")
+	require.EqualError(t, validateParachainConfigs([]ParachainConfig{
+		{ChainID: "dev"},
+		{ChainID: "dev", ParaID: 2001},
+	}), "parachain chain ID dev is configured more than once")
+	require.EqualError(t, validateParachainConfigs([]ParachainConfig{
+		{ChainID: "dev", ParaID: -1},
+	}), "parachain dev: negative parachain ID -1")
+}
+
+func TestParachainConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := ParachainConfig{
+		ChainID:  "dali-dev",
+		Bin:      "composable-node",
+		Image:    ibc.DockerImage{Repository: "ghcr.io/misko9/composable", Version: "centauri"},
+		NumNodes: 2,
+	}
+	require.NoError(t, valid.Validate())
+
+	for _, tt := range []struct {
+		modify  func(*ParachainConfig)
+		wantErr string
+	}{
+		{func(pc *ParachainConfig) { pc.ChainID = "" }, "parachain chain ID must not be empty"},
+		{func(pc *ParachainConfig) { pc.Bin = "" }, "parachain dali-dev: binary must not be empty"},
+		{func(pc *ParachainConfig) { pc.Image.Repository = "" }, "parachain dali-dev: image repository must not be empty"},
+		{func(pc *ParachainConfig) { pc.Image.UidGid = "root" }, "must be of the form uid:gid"},
+		{func(pc *ParachainConfig) { pc.Image.Platform = "linux" }, "invalid platform"},
+		{func(pc *ParachainConfig) { pc.NumNodes = 0 }, "at least 1 node is required"},
+		{func(pc *ParachainConfig) { pc.NumNodes = 6 }, "6 nodes requested, but at most 5 are supported"},
+		{func(pc *ParachainConfig) { pc.ParaID = -1 }, "negative parachain ID -1"},
+	} {
+		pc := valid
+		tt.modify(&pc)
+		require.ErrorContains(t, pc.Validate(), tt.wantErr)
+	}
+
+	other := valid
+	other.ChainID = "other"
+	require.NoError(t, ValidateParachainConfigs([]ParachainConfig{valid, other}))
+	require.EqualError(t, ValidateParachainConfigs([]ParachainConfig{valid, valid}), "parachain chain ID dali-dev is configured more than once")
+}
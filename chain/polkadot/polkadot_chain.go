@@ -4,19 +4,27 @@ import (
 	"context"
 	crand "crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"math/rand"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
+	"cosmossdk.io/math"
 	"github.com/StirlingMarketingGroup/go-namecase"
-	"github.com/docker/docker/api/types"
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/signature"
+	"github.com/cosmos/go-bip39"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/icza/dyno"
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/strangelove-ventures/ibctest/v6/transcript"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -30,6 +38,22 @@ type PolkadotChain struct {
 	parachainConfig    []ParachainConfig
 	RelayChainNodes    RelayChainNodes
 	ParachainNodes     []ParachainNodes
+
+	// Set during Initialize; watches all node containers for fatal log lines.
+	logWatcher *dockerutil.LogWatcher
+
+	// Set during Initialize; restarts crashed node containers, such as OOM killed collators.
+	supervisor *dockerutil.Supervisor
+
+	indexerMu sync.Mutex
+	indexer   *Indexer
+
+	noncesMu sync.Mutex
+	nonces   map[Location]*NonceManager
+
+	// Accounts created or recovered by name, which sign the extrinsics of helpers such as SendFunds.
+	keysMu sync.Mutex
+	keys   map[string]signature.KeyringPair
 }
 
 // PolkadotAuthority is used when constructing the validator authorities in the substrate chain spec.
@@ -53,12 +77,29 @@ type PolkadotParachainSpec struct {
 
 // ParachainConfig is a shared type that allows callers of this module to configure a parachain.
 type ParachainConfig struct {
-	ChainID         string
-	Bin             string
-	Image           ibc.DockerImage
-	NumNodes        int
+	ChainID  string
+	Bin      string
+	Image    ibc.DockerImage
+	NumNodes int
+
+	// If set, overrides the parachain ID of the parachain's chain spec, which its collators run with
+	// and the relay chain's genesis registers the parachain under,
+	// e.g. to run two parachains from the same image. IDs must be unique across a relay chain's parachains.
+	ParaID int
+
+	// Flags for the parachain node, and for its embedded relay chain node.
+	// A flag, of the form --flag=value or --flag value, replaces the default flag of the same name
+	// in DefaultParachainFlags or DefaultRelayChainFlags, respectively.
 	Flags           []string
 	RelayChainFlags []string
+
+	// Denom of the parachain's native balance, if any.
+	// GetBalance queries the parachain for balances of this denom.
+	Denom string
+
+	// AssetIDs maps denoms to asset IDs in the parachain's Assets pallet.
+	// GetBalance queries the parachain's Assets pallet for balances of these denoms.
+	AssetIDs map[string]string
 }
 
 // IndexedName is a slice of the substrate dev key names used for key derivation.
@@ -81,31 +122,46 @@ func (c *PolkadotChain) Config() ibc.ChainConfig {
 	return c.cfg
 }
 
+// Decimals returns the number of decimals of the display unit of the chain's denom, e.g. 12 for DOT.
+// Implements ibc.DisplayUnits.
+func (c *PolkadotChain) Decimals() int64 {
+	return c.Config().Decimals()
+}
+
+// DisplayDenom returns the display unit of the chain's denom, from its CoinDisplayDenom or its Denom.
+// Implements ibc.DisplayUnits.
+func (c *PolkadotChain) DisplayDenom() string {
+	return c.Config().DisplayDenom()
+}
+
 // Initialize initializes node structs so that things like initializing keys can be done before starting the chain.
+// Common docker failures, such as images unavailable for the host's architecture, are returned with a remediation hint.
 // Implements Chain interface.
 func (c *PolkadotChain) Initialize(ctx context.Context, testName string, cli *client.Client, networkID string) error {
+	return dockerutil.Diagnose(c.initializeNodes(ctx, testName, cli, networkID))
+}
+
+// initializeNodes pulls the chain's images and creates the relay chain and parachain nodes.
+func (c *PolkadotChain) initializeNodes(ctx context.Context, testName string, cli *client.Client, networkID string) error {
+	if err := validateParachainConfigs(c.parachainConfig); err != nil {
+		return err
+	}
 	relayChainNodes := []*RelayChainNode{}
 	chainCfg := c.Config()
+	c.logWatcher = dockerutil.NewLogWatcher(c.log, cli)
+	supervisor, err := dockerutil.NewSupervisor(c.log, cli, c.logWatcher)
+	if err != nil {
+		return err
+	}
+	c.supervisor = supervisor
 	images := []ibc.DockerImage{}
 	images = append(images, chainCfg.Images...)
 	for _, parachain := range c.parachainConfig {
 		images = append(images, parachain.Image)
 	}
 	for _, image := range images {
-		rc, err := cli.ImagePull(
-			ctx,
-			image.Repository+":"+image.Version,
-			types.ImagePullOptions{},
-		)
-		if err != nil {
-			c.log.Error("Failed to pull image",
-				zap.Error(err),
-				zap.String("repository", image.Repository),
-				zap.String("tag", image.Version),
-			)
-		} else {
-			_, _ = io.Copy(io.Discard, rc)
-			_ = rc.Close()
+		if err := dockerutil.EnsureImage(ctx, c.log, cli, image.Ref(), image.Platform); err != nil {
+			return err
 		}
 	}
 	for i := 0; i < c.numRelayChainNodes; i++ {
@@ -117,26 +173,14 @@ func (c *PolkadotChain) Initialize(ctx context.Context, testName string, cli *cl
 			return fmt.Errorf("error generating node key: %w", err)
 		}
 
-		nameCased := namecase.New().NameCase(IndexedName[i])
-
-		ed25519PrivKey, err := DeriveEd25519FromName(nameCased)
-		if err != nil {
-			return err
-		}
-		accountKey, err := DeriveSr25519FromName([]string{nameCased})
+		keys, err := c.deriveRelayChainNodeKeys(ctx, IndexedName[i])
 		if err != nil {
 			return err
 		}
-		stashKey, err := DeriveSr25519FromName([]string{nameCased, "stash"})
-		if err != nil {
-			return err
-		}
-		ecdsaPrivKey, err := DeriveSecp256k1FromName(nameCased)
-		if err != nil {
-			return fmt.Errorf("error generating secp256k1 private key: %w", err)
-		}
 		pn := &RelayChainNode{
 			log:               c.log,
+			logWatcher:        c.logWatcher,
+			supervisor:        c.supervisor,
 			Index:             i,
 			Chain:             c,
 			DockerClient:      cli,
@@ -144,18 +188,14 @@ func (c *PolkadotChain) Initialize(ctx context.Context, testName string, cli *cl
 			TestName:          testName,
 			Image:             chainCfg.Images[0],
 			NodeKey:           nodeKey,
-			Ed25519PrivateKey: ed25519PrivKey,
-			AccountKey:        accountKey,
-			StashKey:          stashKey,
-			EcdsaPrivateKey:   *ecdsaPrivKey,
+			Ed25519PrivateKey: keys.Ed25519PrivateKey,
+			AccountKey:        keys.AccountKey,
+			StashKey:          keys.StashKey,
+			EcdsaPrivateKey:   keys.EcdsaPrivateKey,
 		}
 
 		v, err := cli.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
-			Labels: map[string]string{
-				dockerutil.CleanupLabel: testName,
-
-				dockerutil.NodeOwnerLabel: pn.Name(),
-			},
+			Labels: dockerutil.VolumeLabels(testName, pn.Name()),
 		})
 		if err != nil {
 			return fmt.Errorf("creating volume for chain node: %w", err)
@@ -185,6 +225,8 @@ func (c *PolkadotChain) Initialize(ctx context.Context, testName string, cli *cl
 			}
 			pn := &ParachainNode{
 				log:             c.log,
+				logWatcher:      c.logWatcher,
+				supervisor:      c.supervisor,
 				Index:           i,
 				Chain:           c,
 				DockerClient:    cli,
@@ -198,11 +240,7 @@ func (c *PolkadotChain) Initialize(ctx context.Context, testName string, cli *cl
 				RelayChainFlags: parachainConfig.RelayChainFlags,
 			}
 			v, err := cli.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
-				Labels: map[string]string{
-					dockerutil.CleanupLabel: testName,
-
-					dockerutil.NodeOwnerLabel: pn.Name(),
-				},
+				Labels: dockerutil.VolumeLabels(testName, pn.Name()),
 			})
 			if err != nil {
 				return fmt.Errorf("creating volume for chain node: %w", err)
@@ -233,107 +271,175 @@ func runtimeGenesisPath(path ...interface{}) []interface{} {
 	return fullPath
 }
 
-func (c *PolkadotChain) modifyGenesis(ctx context.Context, chainSpec interface{}) error {
-	bootNodes := []string{}
-	authorities := [][]interface{}{}
-	balances := [][]interface{}{}
-	var sudoAddress string
-	for i, n := range c.RelayChainNodes {
-		multiAddress, err := n.MultiAddress()
-		if err != nil {
-			return err
+// genesisBalances sorts wallets by denom into the genesis balances of the relay chain and of each parachain, keyed by chain ID.
+// Wallets of the relay chain denom are also funded on the parachains without a denom of their own,
+// whose native balances are otherwise not addressable by denom.
+func (c *PolkadotChain) genesisBalances(wallets []ibc.WalletAmount) ([][]interface{}, map[string][][]interface{}, error) {
+	relayChain := [][]interface{}{}
+	parachains := make(map[string][][]interface{})
+	for _, w := range wallets {
+		if _, err := DecodeAddressSS58(w.Address); err != nil {
+			return nil, nil, fmt.Errorf("genesis wallet: %w", err)
 		}
-		bootNodes = append(bootNodes, multiAddress)
-		stashAddress, err := n.StashAddress()
-		if err != nil {
-			return fmt.Errorf("error getting stash address")
+		if !w.Amount.IsUint64() {
+			return nil, nil, fmt.Errorf("genesis wallet %s: amount %s of %s does not fit in a u64", w.Address, w.Amount, w.Denom)
 		}
-		accountAddress, err := n.AccountAddress()
+		balance := []interface{}{w.Address, w.Amount.Uint64()}
+
+		loc, assetID, err := c.denomLocation(w.Denom)
 		if err != nil {
-			return fmt.Errorf("error getting account address")
+			return nil, nil, fmt.Errorf("genesis wallet %s: %w", w.Address, err)
 		}
-		grandpaAddress, err := n.GrandpaAddress()
-		if err != nil {
-			return fmt.Errorf("error getting grandpa address")
+		if assetID != "" {
+			return nil, nil, fmt.Errorf("genesis wallet %s: genesis balances of asset %s on %s are not supported", w.Address, assetID, loc)
 		}
-		beefyAddress, err := n.EcdsaAddress()
-		if err != nil {
-			return fmt.Errorf("error getting beefy address")
+		if loc.ParachainID != "" {
+			parachains[loc.ParachainID] = append(parachains[loc.ParachainID], balance)
+			continue
 		}
-		balances = append(balances,
-			[]interface{}{stashAddress, uint64(1000000000000000000)},
-			[]interface{}{accountAddress, uint64(1000000000000000000)},
-		)
-		if i == 0 {
-			sudoAddress = accountAddress
+		relayChain = append(relayChain, balance)
+		for _, pc := range c.parachainConfig {
+			if pc.Denom == "" {
+				parachains[pc.ChainID] = append(parachains[pc.ChainID], balance)
+			}
 		}
-		authority := []interface{}{stashAddress, stashAddress, PolkadotAuthority{
-			Grandpa:            grandpaAddress,
-			Babe:               accountAddress,
-			IMOnline:           accountAddress,
-			ParachainValidator: accountAddress,
-			AuthorityDiscovery: accountAddress,
-			ParaValidator:      accountAddress,
-			ParaAssignment:     accountAddress,
-			Beefy:              beefyAddress,
-		}}
-		authorities = append(authorities, authority)
 	}
+	return relayChain, parachains, nil
+}
 
-	if err := dyno.Set(chainSpec, bootNodes, "bootNodes"); err != nil {
-		return fmt.Errorf("error setting boot nodes: %w", err)
+// modifyParachainGenesis sets the parachain ID, if paraID is not zero, and adds balances to the genesis balances
+// of the parachain nodes, writing the modified chain spec to every node of the parachain.
+// The genesis state and wasm exported for the relay chain's genesis are then those of the modified chain spec.
+func (c *PolkadotChain) modifyParachainGenesis(ctx context.Context, nodes ParachainNodes, paraID int, balances [][]interface{}) error {
+	firstNode := nodes[0]
+	chainSpecBytes, err := firstNode.GenerateChainSpec(ctx)
+	if err != nil {
+		return fmt.Errorf("error generating chain spec: %w", err)
 	}
-	if err := dyno.Set(chainSpec, authorities, runtimeGenesisPath("session", "keys")...); err != nil {
-		return fmt.Errorf("error setting authorities: %w", err)
+	var chainSpec interface{}
+	if err := json.Unmarshal(chainSpecBytes, &chainSpec); err != nil {
+		return fmt.Errorf("error unmarshaling chain spec: %w", err)
 	}
-	if err := dyno.Set(chainSpec, balances, runtimeGenesisPath("balances", "balances")...); err != nil {
-		return fmt.Errorf("error setting balances: %w", err)
+
+	if err := modifyParachainChainSpec(chainSpec, paraID, balances); err != nil {
+		return err
 	}
-	if err := dyno.Set(chainSpec, sudoAddress, runtimeGenesisPath("sudo", "key")...); err != nil {
-		return fmt.Errorf("error setting sudo key: %w", err)
+
+	editedChainSpec, err := json.MarshalIndent(chainSpec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling modified chain spec: %w", err)
 	}
-	if err := dyno.Set(chainSpec, sudoAddress, runtimeGenesisPath("bridgeRococoGrandpa", "owner")...); err != nil {
-		return fmt.Errorf("error setting bridgeRococoGrandpa owner: %w", err)
+	for _, n := range nodes {
+		if err := n.WriteChainSpec(ctx, editedChainSpec); err != nil {
+			return err
+		}
 	}
-	if err := dyno.Set(chainSpec, sudoAddress, runtimeGenesisPath("bridgeWococoGrandpa", "owner")...); err != nil {
-		return fmt.Errorf("error setting bridgeWococoGrandpa owner: %w", err)
+	return nil
+}
+
+// modifyParachainChainSpec sets the parachain ID of chainSpec, a parachain's chain spec, if paraID is not zero,
+// both as the ID that its collators run with and in its runtime's parachain info, if any,
+// and adds balances to its genesis balances.
+func modifyParachainChainSpec(chainSpec interface{}, paraID int, balances [][]interface{}) error {
+	if paraID != 0 {
+		if err := dyno.Set(chainSpec, paraID, "para_id"); err != nil {
+			return fmt.Errorf("error setting parachain ID: %w", err)
+		}
+		infoPath := []interface{}{"genesis", "runtime", "parachainInfo"}
+		if _, err := dyno.Get(chainSpec, infoPath...); err == nil {
+			if err := dyno.Set(chainSpec, paraID, append(infoPath, "parachainId")...); err != nil {
+				return fmt.Errorf("error setting parachain info: %w", err)
+			}
+		}
 	}
-	if err := dyno.Set(chainSpec, sudoAddress, runtimeGenesisPath("bridgeRococoMessages", "owner")...); err != nil {
-		return fmt.Errorf("error setting bridgeRococoMessages owner: %w", err)
+
+	if len(balances) == 0 {
+		return nil
 	}
-	if err := dyno.Set(chainSpec, sudoAddress, runtimeGenesisPath("bridgeWococoMessages", "owner")...); err != nil {
-		return fmt.Errorf("error setting bridgeWococoMessages owner: %w", err)
+	path := []interface{}{"genesis", "runtime", "balances", "balances"}
+	existing, err := dyno.GetSlice(chainSpec, path...)
+	if err != nil {
+		return fmt.Errorf("error getting balances: %w", err)
 	}
-	if err := dyno.Set(chainSpec, 2, runtimeGenesisPath("configuration", "config", "validation_upgrade_delay")...); err != nil {
-		return fmt.Errorf("error setting validation upgrade delay: %w", err)
+	for _, b := range balances {
+		existing = append(existing, b)
+	}
+	if err := dyno.Set(chainSpec, existing, path...); err != nil {
+		return fmt.Errorf("error setting balances: %w", err)
 	}
-	parachains := [][]interface{}{}
+	return nil
+}
 
-	for _, parachainNodes := range c.ParachainNodes {
-		firstParachainNode := parachainNodes[0]
-		parachainID, err := firstParachainNode.ParachainID(ctx)
-		if err != nil {
-			return fmt.Errorf("error getting parachain ID: %w", err)
+// validateParachainConfigs returns an error if parachains share a chain ID, by which parachains are located,
+// or a parachain ID override.
+func validateParachainConfigs(parachains []ParachainConfig) error {
+	chainIDs := make(map[string]bool, len(parachains))
+	paraIDs := make(map[int]string, len(parachains))
+	for _, pc := range parachains {
+		if chainIDs[pc.ChainID] {
+			return fmt.Errorf("parachain chain ID %s is configured more than once", pc.ChainID)
 		}
-		genesisState, err := firstParachainNode.ExportGenesisState(ctx)
-		if err != nil {
-			return fmt.Errorf("error exporting genesis state: %w", err)
+		chainIDs[pc.ChainID] = true
+
+		if pc.ParaID < 0 {
+			return fmt.Errorf("parachain %s: negative parachain ID %d", pc.ChainID, pc.ParaID)
 		}
-		genesisWasm, err := firstParachainNode.ExportGenesisWasm(ctx)
-		if err != nil {
-			return fmt.Errorf("error exporting genesis wasm: %w", err)
+		if pc.ParaID == 0 {
+			continue
+		}
+		if other, ok := paraIDs[pc.ParaID]; ok {
+			return fmt.Errorf("parachains %s and %s have the same parachain ID %d", other, pc.ChainID, pc.ParaID)
 		}
+		paraIDs[pc.ParaID] = pc.ChainID
+	}
+	return nil
+}
 
-		composableParachain := []interface{}{parachainID, PolkadotParachainSpec{
-			GenesisHead:    genesisState,
-			ValidationCode: genesisWasm,
-			Parachain:      true,
-		}}
-		parachains = append(parachains, composableParachain)
+// ValidateParachainConfigs returns an error if any of parachains is invalid, see ParachainConfig.Validate,
+// or if parachains collide on their chain or parachain IDs.
+// Chain factories validate parachains before initializing the chain, so that misconfigurations fail fast.
+func ValidateParachainConfigs(parachains []ParachainConfig) error {
+	for _, pc := range parachains {
+		if err := pc.Validate(); err != nil {
+			return err
+		}
 	}
+	return validateParachainConfigs(parachains)
+}
 
-	if err := dyno.Set(chainSpec, parachains, runtimeGenesisPath("paras", "paras")...); err != nil {
-		return fmt.Errorf("error setting parachains: %w", err)
+// Validate returns an error if the parachain has no chain ID, binary, or image, if its image is invalid,
+// if its number of nodes is unsupported, see ValidateNumNodes, or if its parachain ID is negative.
+func (pc ParachainConfig) Validate() error {
+	if pc.ChainID == "" {
+		return errors.New("parachain chain ID must not be empty")
+	}
+	if pc.Bin == "" {
+		return fmt.Errorf("parachain %s: binary must not be empty", pc.ChainID)
+	}
+	if err := pc.Image.Validate(); err != nil {
+		return fmt.Errorf("parachain %s: %w", pc.ChainID, err)
+	}
+	if _, err := dockerutil.ParsePlatform(pc.Image.Platform); err != nil {
+		return fmt.Errorf("parachain %s: %w", pc.ChainID, err)
+	}
+	if err := ValidateNumNodes(pc.NumNodes); err != nil {
+		return fmt.Errorf("parachain %s: %w", pc.ChainID, err)
+	}
+	if pc.ParaID < 0 {
+		return fmt.Errorf("parachain %s: negative parachain ID %d", pc.ChainID, pc.ParaID)
+	}
+	return nil
+}
+
+// ValidateNumNodes returns an error unless n nodes of a relay chain or parachain
+// can each derive their keys from a distinct substrate dev account in IndexedName.
+func ValidateNumNodes(n int) error {
+	if n < 1 {
+		return fmt.Errorf("at least 1 node is required, got %d", n)
+	}
+	if n > len(IndexedName) {
+		return fmt.Errorf("%d nodes requested, but at most %d are supported, one per substrate dev account (%s)",
+			n, len(IndexedName), strings.Join(IndexedName, ", "))
 	}
 	return nil
 }
@@ -345,51 +451,49 @@ func (c *PolkadotChain) logger() *zap.Logger {
 	)
 }
 
-// Start sets up everything needed (validators, gentx, fullnodes, peering, additional accounts) for chain to start from genesis.
-// Implements Chain interface.
-func (c *PolkadotChain) Start(testName string, ctx context.Context, additionalGenesisWallets ...ibc.WalletAmount) error {
-	// generate chain spec
-	firstNode := c.RelayChainNodes[0]
-	if err := firstNode.GenerateChainSpec(ctx); err != nil {
-		return fmt.Errorf("error generating chain spec: %w", err)
-	}
-	fr := dockerutil.NewFileRetriever(c.logger(), firstNode.DockerClient, c.testName)
-	fw := dockerutil.NewFileWriter(c.logger(), firstNode.DockerClient, c.testName)
+// deriveRelayChainNodeKeys derives the keys of the relay chain node named name, e.g. alice,
+// returned in the key fields of an otherwise empty RelayChainNode.
+func (c *PolkadotChain) deriveRelayChainNodeKeys(ctx context.Context, name string) (*RelayChainNode, error) {
+	defer testreporter.StartPhase(ctx, testreporter.PhaseKeyDerivation, c.cfg.ChainID+"/"+name)()
 
-	chainSpecBytes, err := fr.SingleFileContent(ctx, firstNode.VolumeName, firstNode.ChainSpecFilePathContainer())
+	nameCased := namecase.New().NameCase(name)
+	ed25519PrivKey, err := DeriveEd25519FromName(nameCased)
 	if err != nil {
-		return fmt.Errorf("error reading chain spec: %w", err)
+		return nil, err
 	}
-
-	var chainSpec interface{}
-	if err := json.Unmarshal(chainSpecBytes, &chainSpec); err != nil {
-		return fmt.Errorf("error unmarshaling chain spec: %w", err)
-	}
-
-	if err := c.modifyGenesis(ctx, chainSpec); err != nil {
-		return fmt.Errorf("error modifying genesis: %w", err)
+	accountKey, err := DeriveSr25519FromName([]string{nameCased})
+	if err != nil {
+		return nil, err
 	}
-
-	editedChainSpec, err := json.MarshalIndent(chainSpec, "", "  ")
+	stashKey, err := DeriveSr25519FromName([]string{nameCased, "stash"})
 	if err != nil {
-		return fmt.Errorf("error marshaling modified chain spec: %w", err)
+		return nil, err
 	}
-
-	if err := fw.WriteFile(ctx, firstNode.VolumeName, firstNode.ChainSpecFilePathContainer(), editedChainSpec); err != nil {
-		return fmt.Errorf("error writing modified chain spec: %w", err)
+	ecdsaPrivKey, err := DeriveSecp256k1FromName(nameCased)
+	if err != nil {
+		return nil, fmt.Errorf("error generating secp256k1 private key: %w", err)
 	}
+	return &RelayChainNode{
+		Ed25519PrivateKey: ed25519PrivKey,
+		AccountKey:        accountKey,
+		StashKey:          stashKey,
+		EcdsaPrivateKey:   *ecdsaPrivKey,
+	}, nil
+}
 
-	c.logger().Info("Generating raw chain spec", zap.String("container", firstNode.Name()))
-
-	if err := firstNode.GenerateChainSpecRaw(ctx); err != nil {
-		return err
+// Start sets up everything needed (validators, gentx, fullnodes, peering, additional accounts) for chain to start from genesis.
+// Implements Chain interface.
+func (c *PolkadotChain) Start(testName string, ctx context.Context, additionalGenesisWallets ...ibc.WalletAmount) error {
+	if !c.cfg.GenesisTime.IsZero() || c.cfg.StartBeforeGenesis {
+		return fmt.Errorf("chain %s: genesis time is not supported for polkadot chains", c.cfg.ChainID)
 	}
 
-	rawChainSpecBytes, err := fr.SingleFileContent(ctx, firstNode.VolumeName, firstNode.RawChainSpecFilePathRelative())
+	rawChainSpecBytes, err := c.buildRawChainSpec(ctx, additionalGenesisWallets)
 	if err != nil {
-		return fmt.Errorf("error reading chain spec: %w", err)
+		return err
 	}
 
+	fw := dockerutil.NewFileWriter(c.logger(), c.RelayChainNodes[0].DockerClient, c.testName)
 	var eg errgroup.Group
 	for i, n := range c.RelayChainNodes {
 		n := n
@@ -434,9 +538,78 @@ func (c *PolkadotChain) Start(testName string, ctx context.Context, additionalGe
 		return err
 	}
 
+	for _, e := range c.NodeHostEndpoints() {
+		c.logger().Debug(
+			"Node websocket endpoint",
+			zap.String("name", e.Name),
+			zap.String("ws", e.WS),
+			zap.String("polkadot_js", e.PolkadotJSURL()),
+		)
+	}
+
+	if c.supervisor != nil {
+		c.supervisor.WatchHealth(c.supervisorHealthCheck)
+	}
 	return nil
 }
 
+// StopAllNodes stops and removes the containers of all relay chain and parachain nodes,
+// releasing their reserved host ports.
+func (c *PolkadotChain) StopAllNodes(ctx context.Context) error {
+	var eg errgroup.Group
+	for _, n := range c.RelayChainNodes {
+		n := n
+		eg.Go(func() error {
+			if err := n.StopContainer(ctx); err != nil {
+				return err
+			}
+			return n.RemoveContainer(ctx)
+		})
+	}
+	for _, nodes := range c.ParachainNodes {
+		for _, n := range nodes {
+			n := n
+			eg.Go(func() error {
+				if err := n.StopContainer(ctx); err != nil {
+					return err
+				}
+				return n.RemoveContainer(ctx)
+			})
+		}
+	}
+	return eg.Wait()
+}
+
+// buildRawChainSpec builds the chain spec with the additional genesis wallets,
+// converts it to a raw chain spec on the first relay chain node, and returns the raw chain spec.
+func (c *PolkadotChain) buildRawChainSpec(ctx context.Context, additionalGenesisWallets []ibc.WalletAmount) ([]byte, error) {
+	defer testreporter.StartPhase(ctx, testreporter.PhaseChainspec, c.cfg.ChainID)()
+
+	editedChainSpec, err := c.buildChainSpec(ctx, additionalGenesisWallets)
+	if err != nil {
+		return nil, err
+	}
+
+	firstNode := c.RelayChainNodes[0]
+	fr := dockerutil.NewFileRetriever(c.logger(), firstNode.DockerClient, c.testName)
+	fw := dockerutil.NewFileWriter(c.logger(), firstNode.DockerClient, c.testName)
+	if err := fw.WriteFile(ctx, firstNode.VolumeName, firstNode.ChainSpecFilePathContainer(), editedChainSpec); err != nil {
+		return nil, fmt.Errorf("error writing modified chain spec: %w", err)
+	}
+
+	c.logger().Info("Generating raw chain spec", zap.String("container", firstNode.Name()))
+
+	if err := firstNode.GenerateChainSpecRaw(ctx); err != nil {
+		return nil, err
+	}
+
+	rawChainSpecBytes, err := fr.SingleFileContent(ctx, firstNode.VolumeName, firstNode.RawChainSpecFilePathRelative())
+	if err != nil {
+		return nil, fmt.Errorf("error reading chain spec: %w", err)
+	}
+	return rawChainSpecBytes, nil
+}
+
 // Exec runs an arbitrary command using Chain's docker environment.
 // Implements Chain interface.
 func (c *PolkadotChain) Exec(ctx context.Context, cmd []string, env []string) ([]byte, []byte, error) {
@@ -444,42 +617,309 @@ func (c *PolkadotChain) Exec(ctx context.Context, cmd []string, env []string) ([
 	return res.Stdout, res.Stderr, res.Err
 }
 
-// GetRPCAddress retrieves the rpc address that can be reached by other containers in the docker network.
+// GetRPCAddress retrieves the HTTP RPC address that can be reached by other containers in the docker network,
+// of the first parachain node if there is a parachain, or of the first relay chain node otherwise.
 // Implements Chain interface.
 func (c *PolkadotChain) GetRPCAddress() string {
-	if len(c.ParachainNodes) > 0 && len(c.ParachainNodes[0]) > 0 {
-		return fmt.Sprintf("%s:%s", c.ParachainNodes[0][0].HostName(), strings.Split(rpcPort, "/")[0])
-	}
-	return fmt.Sprintf("%s:%s", c.RelayChainNodes[0].HostName(), strings.Split(rpcPort, "/")[0])
+	return fmt.Sprintf("%s:%s", c.defaultHostName(), strings.Split(rpcPort, "/")[0])
 }
 
-// GetGRPCAddress retrieves the grpc address that can be reached by other containers in the docker network.
+// GetGRPCAddress retrieves the websocket RPC address that can be reached by other containers in the docker network,
+// as substrate chains do not serve gRPC. Prefer WSEndpoint, which names the transport.
 // Implements Chain interface.
 func (c *PolkadotChain) GetGRPCAddress() string {
+	return fmt.Sprintf("%s:%s", c.defaultHostName(), strings.Split(wsPort, "/")[0])
+}
+
+// defaultHostName returns the docker hostname of the first parachain node if there is a parachain,
+// or of the first relay chain node otherwise.
+func (c *PolkadotChain) defaultHostName() string {
 	if len(c.ParachainNodes) > 0 && len(c.ParachainNodes[0]) > 0 {
-		return fmt.Sprintf("%s:%s", c.ParachainNodes[0][0].HostName(), strings.Split(wsPort, "/")[0])
+		return c.ParachainNodes[0][0].HostName()
+	}
+	return c.RelayChainNodes[0].HostName()
+}
+
+// HTTPEndpoint returns the HTTP RPC endpoint of the first node of the chain at loc,
+// either RelayChain or a Parachain, that can be reached by other containers in the docker network.
+func (c *PolkadotChain) HTTPEndpoint(loc Location) (ibc.Endpoint, error) {
+	hostName, err := c.locationHostName(loc)
+	if err != nil {
+		return ibc.Endpoint{}, err
 	}
-	return fmt.Sprintf("%s:%s", c.RelayChainNodes[0].HostName(), strings.Split(wsPort, "/")[0])
+	return ibc.Endpoint{
+		Transport: ibc.TransportHTTP,
+		Address:   fmt.Sprintf("%s:%s", hostName, strings.Split(rpcPort, "/")[0]),
+	}, nil
+}
+
+// WSEndpoint returns the websocket RPC endpoint of the first node of the chain at loc,
+// either RelayChain or a Parachain, that can be reached by other containers in the docker network.
+func (c *PolkadotChain) WSEndpoint(loc Location) (ibc.Endpoint, error) {
+	hostName, err := c.locationHostName(loc)
+	if err != nil {
+		return ibc.Endpoint{}, err
+	}
+	return ibc.Endpoint{
+		Transport: ibc.TransportWebSocket,
+		Address:   fmt.Sprintf("%s:%s", hostName, strings.Split(wsPort, "/")[0]),
+	}, nil
+}
+
+// Endpoint returns the RPC endpoint over transport of the first parachain node if there is a parachain,
+// or of the first relay chain node otherwise, like GetRPCAddress and GetGRPCAddress.
+// Host endpoints are only available once the chain has started; see HostEndpoints to wait for them.
+// Implements ibc.EndpointProvider.
+func (c *PolkadotChain) Endpoint(transport ibc.Transport, host bool) (ibc.Endpoint, error) {
+	var address string
+	if host {
+		e, ok := c.defaultHostEndpoints()
+		if !ok {
+			return ibc.Endpoint{}, fmt.Errorf("chain %s has not started", c.cfg.ChainID)
+		}
+		switch transport {
+		case ibc.TransportHTTP:
+			address = e.RPC
+		case ibc.TransportWebSocket:
+			address = e.WS
+		}
+	} else {
+		switch transport {
+		case ibc.TransportHTTP:
+			address = c.GetRPCAddress()
+		case ibc.TransportWebSocket:
+			address = c.GetGRPCAddress()
+		}
+	}
+	if address == "" {
+		return ibc.Endpoint{}, fmt.Errorf("chain %s does not serve rpc over transport %q", c.cfg.ChainID, transport)
+	}
+	return ibc.Endpoint{Transport: transport, Address: address}, nil
+}
+
+// locationHostName returns the docker hostname of the first node of the chain at loc.
+func (c *PolkadotChain) locationHostName(loc Location) (string, error) {
+	if loc.ParachainID == "" {
+		if len(c.RelayChainNodes) == 0 {
+			return "", fmt.Errorf("no nodes for %s", loc)
+		}
+		return c.RelayChainNodes[0].HostName(), nil
+	}
+	for i, pc := range c.parachainConfig {
+		if pc.ChainID == loc.ParachainID && i < len(c.ParachainNodes) && len(c.ParachainNodes[i]) > 0 {
+			return c.ParachainNodes[i][0].HostName(), nil
+		}
+	}
+	return "", fmt.Errorf("no nodes for %s", loc)
 }
 
 // GetHostRPCAddress returns the rpc address that can be reached by processes on the host machine.
-// Note that this will not return a valid value until after Start returns.
+// Note that this will return an empty string until after Start returns;
+// use HostEndpoints to wait for the address, or to get the relay chain's address when there is a parachain.
 // Implements Chain interface.
 func (c *PolkadotChain) GetHostRPCAddress() string {
-	if len(c.ParachainNodes) > 0 && len(c.ParachainNodes[0]) > 0 {
-		return c.ParachainNodes[0][0].hostRpcPort
-	}
-	return c.RelayChainNodes[0].hostRpcPort
+	e, _ := c.defaultHostEndpoints()
+	return e.RPC
 }
 
 // GetHostGRPCAddress returns the grpc address that can be reached by processes on the host machine.
-// Note that this will not return a valid value until after Start returns.
+// Note that this will return an empty string until after Start returns;
+// use HostEndpoints to wait for the address, or to get the relay chain's address when there is a parachain.
 // Implements Chain interface.
 func (c *PolkadotChain) GetHostGRPCAddress() string {
+	e, _ := c.defaultHostEndpoints()
+	return e.WS
+}
+
+// HostEndpoints are the addresses of a node's ports that can be reached by processes on the host machine.
+type HostEndpoints struct {
+	RPC string
+	WS  string
+}
+
+// polkadotJSAppsURL is the URL of the hosted polkadot.js apps UI.
+const polkadotJSAppsURL = "https://polkadot.js.org/apps/"
+
+// PolkadotJSURL returns the URL of the polkadot.js apps explorer connected to the node's websocket endpoint,
+// e.g. to inspect a running chain while a test is paused.
+func (e HostEndpoints) PolkadotJSURL() string {
+	if e.WS == "" {
+		return ""
+	}
+	return polkadotJSAppsURL + "?rpc=" + url.QueryEscape("ws://"+e.WS) + "#/explorer"
+}
+
+// defaultHostEndpoints returns the host endpoints of the first parachain node if there is a parachain,
+// or of the first relay chain node otherwise.
+func (c *PolkadotChain) defaultHostEndpoints() (HostEndpoints, bool) {
 	if len(c.ParachainNodes) > 0 && len(c.ParachainNodes[0]) > 0 {
-		return c.ParachainNodes[0][0].hostWsPort
+		return c.ParachainNodes[0][0].HostEndpoints()
 	}
-	return c.RelayChainNodes[0].hostWsPort
+	return c.RelayChainNodes[0].HostEndpoints()
+}
+
+// HostEndpoints returns the host endpoints of the first node of the chain at loc,
+// either RelayChain or a Parachain, blocking until the node's container has started and its ports are bound.
+// It returns an error if ctx is done first, e.g. because the chain was never started.
+func (c *PolkadotChain) HostEndpoints(ctx context.Context, loc Location) (HostEndpoints, error) {
+	return c.HostEndpointsOfNode(ctx, loc, 0)
+}
+
+// HostEndpointsOfNode is like HostEndpoints, for the node at index i of the chain at loc.
+func (c *PolkadotChain) HostEndpointsOfNode(ctx context.Context, loc Location, i int) (HostEndpoints, error) {
+	var endpoints func() (HostEndpoints, bool)
+	if loc.ParachainID == "" {
+		if i < 0 || i >= len(c.RelayChainNodes) {
+			return HostEndpoints{}, fmt.Errorf("no node %d for %s", i, loc)
+		}
+		endpoints = c.RelayChainNodes[i].HostEndpoints
+	} else {
+		for j, pc := range c.parachainConfig {
+			if pc.ChainID == loc.ParachainID && j < len(c.ParachainNodes) && i >= 0 && i < len(c.ParachainNodes[j]) {
+				endpoints = c.ParachainNodes[j][i].HostEndpoints
+				break
+			}
+		}
+		if endpoints == nil {
+			return HostEndpoints{}, fmt.Errorf("no node %d for %s", i, loc)
+		}
+	}
+
+	for {
+		if e, ok := endpoints(); ok {
+			return e, nil
+		}
+		select {
+		case <-ctx.Done():
+			return HostEndpoints{}, fmt.Errorf("waiting for host endpoints of %s, which has not started: %w", loc, ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// NodeHostEndpoints are the host endpoints of one node of a PolkadotChain.
+type NodeHostEndpoints struct {
+	// Chain of the node, and index of the node in that chain.
+	Location Location
+	Index    int
+
+	// Container name of the node.
+	Name string
+
+	HostEndpoints
+}
+
+// NodeHostEndpoints returns the host endpoints of every started node,
+// the relay chain nodes first, then the nodes of each parachain.
+// Nodes that have not started are omitted.
+func (c *PolkadotChain) NodeHostEndpoints() []NodeHostEndpoints {
+	var nodes []NodeHostEndpoints
+	for i, n := range c.RelayChainNodes {
+		if e, ok := n.HostEndpoints(); ok {
+			nodes = append(nodes, NodeHostEndpoints{Location: RelayChain, Index: i, Name: n.Name(), HostEndpoints: e})
+		}
+	}
+	for j, pc := range c.parachainConfig {
+		if j >= len(c.ParachainNodes) {
+			break
+		}
+		for i, n := range c.ParachainNodes[j] {
+			if e, ok := n.HostEndpoints(); ok {
+				nodes = append(nodes, NodeHostEndpoints{Location: Parachain(pc.ChainID), Index: i, Name: n.Name(), HostEndpoints: e})
+			}
+		}
+	}
+	return nodes
+}
+
+// fatalError returns an error if any relay chain or parachain node has logged a fatal error,
+// such as a panic.
+func (c *PolkadotChain) fatalError() error {
+	if c.logWatcher != nil {
+		if err := c.logWatcher.Err(); err != nil {
+			return err
+		}
+	}
+	if c.supervisor != nil {
+		return c.supervisor.Err()
+	}
+	return nil
+}
+
+// supervisorHealthCheck adapts HealthCheck to the chain's supervisor.
+func (c *PolkadotChain) supervisorHealthCheck(ctx context.Context) (bool, string, error) {
+	status, err := c.HealthCheck(ctx)
+	return status.Live, status.Reason, err
+}
+
+// maxFinalityLag is how many blocks the finalized block may lag behind the best block of a ready chain.
+// GRANDPA normally finalizes blocks within a few blocks.
+const maxFinalityLag = 20
+
+// HealthCheck reports the chain as not live once a node has failed, e.g. logged a panic,
+// and as ready once no relay chain or parachain node is syncing or, with more than one node, lacks peers,
+// and finality on each chain lags at most maxFinalityLag blocks behind its best block.
+// Implements ibc.HealthChecker.
+func (c *PolkadotChain) HealthCheck(ctx context.Context) (ibc.HealthStatus, error) {
+	if err := c.fatalError(); err != nil {
+		return ibc.HealthStatus{Reason: err.Error()}, nil
+	}
+
+	relayChainAPIs := make(map[string]*gsrpc.SubstrateAPI, len(c.RelayChainNodes))
+	for _, n := range c.RelayChainNodes {
+		relayChainAPIs[n.Name()] = n.api
+	}
+	chains := []map[string]*gsrpc.SubstrateAPI{relayChainAPIs}
+	for _, nodes := range c.ParachainNodes {
+		parachainAPIs := make(map[string]*gsrpc.SubstrateAPI, len(nodes))
+		for _, n := range nodes {
+			parachainAPIs[n.Name()] = n.api
+		}
+		chains = append(chains, parachainAPIs)
+	}
+
+	for _, apis := range chains {
+		for name, api := range apis {
+			if api == nil {
+				return ibc.HealthStatus{}, fmt.Errorf("node %s has not started", name)
+			}
+			if reason, err := substrateNodeReadiness(name, api, len(apis) > 1); err != nil || reason != "" {
+				return ibc.HealthStatus{Live: true, Reason: reason}, err
+			}
+		}
+	}
+	return ibc.HealthStatus{Live: true, Ready: true}, nil
+}
+
+// substrateNodeReadiness returns why the node is not ready, or an empty string if it is.
+func substrateNodeReadiness(name string, api *gsrpc.SubstrateAPI, needsPeers bool) (string, error) {
+	health, err := api.RPC.System.Health()
+	if err != nil {
+		return "", fmt.Errorf("failed to get health of node %s: %w", name, err)
+	}
+	if health.IsSyncing {
+		return fmt.Sprintf("node %s is syncing", name), nil
+	}
+	if needsPeers && health.Peers == 0 {
+		return fmt.Sprintf("node %s has no peers", name), nil
+	}
+
+	best, err := api.RPC.Chain.GetHeaderLatest()
+	if err != nil {
+		return "", fmt.Errorf("failed to get best header of node %s: %w", name, err)
+	}
+	finalizedHash, err := api.RPC.Chain.GetFinalizedHead()
+	if err != nil {
+		return "", fmt.Errorf("failed to get finalized head of node %s: %w", name, err)
+	}
+	finalized, err := api.RPC.Chain.GetHeader(finalizedHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get finalized header of node %s: %w", name, err)
+	}
+	if lag := int64(best.Number) - int64(finalized.Number); lag > maxFinalityLag {
+		return fmt.Sprintf("finality on node %s lags %d blocks behind block %d", name, lag, best.Number), nil
+	}
+	return "", nil
 }
 
 // Height returns the current block height or an error if unable to get current height.
@@ -512,56 +952,314 @@ func (c *PolkadotChain) HomeDir() string {
 	panic("not implemented yet")
 }
 
-// CreateKey creates a test key in the "user" node (either the first fullnode or the first validator if no fullnodes).
+// CreateKey creates an sr25519 account from a random mnemonic, named keyName in the chain's keyring.
+// The account is the same on the relay chain and on the parachains.
 // Implements Chain interface.
 func (c *PolkadotChain) CreateKey(ctx context.Context, keyName string) error {
-	panic("not implemented yet")
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		return fmt.Errorf("failed to generate entropy: %w", err)
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return fmt.Errorf("failed to generate mnemonic: %w", err)
+	}
+	return c.RecoverKey(ctx, keyName, mnemonic)
 }
 
-// RecoverKey recovers an existing user from a given mnemonic.
+// RecoverKey recovers the sr25519 account of a mnemonic, as substrate tools and relayers restore it,
+// named name in the chain's keyring.
 // Implements Chain interface.
 func (c *PolkadotChain) RecoverKey(ctx context.Context, name, mnemonic string) error {
-	panic("not implemented yet")
+	kp, err := signature.KeyringPairFromSecret(mnemonic, ss58Format)
+	if err != nil {
+		return fmt.Errorf("failed to recover key %s: %w", name, err)
+	}
+
+	c.keysMu.Lock()
+	defer c.keysMu.Unlock()
+	if _, ok := c.keys[name]; ok {
+		return fmt.Errorf("key %s already exists", name)
+	}
+	if c.keys == nil {
+		c.keys = make(map[string]signature.KeyringPair)
+	}
+	c.keys[name] = kp
+	return nil
 }
 
-// GetAddress fetches the bech32 address for a test key on the "user" node (either the first fullnode or the first validator if no fullnodes).
+// GetAddress returns the SS58 address, as bytes, of the account named keyName in the chain's keyring.
 // Implements Chain interface.
 func (c *PolkadotChain) GetAddress(ctx context.Context, keyName string) ([]byte, error) {
-	panic("not implemented yet")
+	kp, err := c.keyringPair(keyName)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(kp.Address), nil
 }
 
-// SendFunds sends funds to a wallet from a user account.
+// keyringPair returns the account named keyName in the chain's keyring.
+func (c *PolkadotChain) keyringPair(keyName string) (signature.KeyringPair, error) {
+	c.keysMu.Lock()
+	defer c.keysMu.Unlock()
+	kp, ok := c.keys[keyName]
+	if !ok {
+		return signature.KeyringPair{}, fmt.Errorf("key %s not found", keyName)
+	}
+	return kp, nil
+}
+
+// RelayerWalletAddress returns the SS58 address of the sr25519 account a relayer restores from mnemonic.
+// Implements ibc.RelayerWalletDeriver.
+func (c *PolkadotChain) RelayerWalletAddress(mnemonic string) (string, error) {
+	return AddressFromMnemonic(mnemonic)
+}
+
+// RelayerFeeDenoms returns the relay chain denom, which also funds the parachains without a denom of their own at genesis,
+// and the denoms of the other parachains, so that relayers can pay fees on the relay chain and on every parachain.
+// Implements ibc.RelayerWalletDeriver.
+func (c *PolkadotChain) RelayerFeeDenoms() []string {
+	denoms := []string{c.cfg.Denom}
+	for _, pc := range c.parachainConfig {
+		if pc.Denom != "" && pc.Denom != c.cfg.Denom {
+			denoms = append(denoms, pc.Denom)
+		}
+	}
+	return denoms
+}
+
+// SendFunds transfers funds to a wallet from the account named keyName in the chain's keyring,
+// returning once the transfer is included in a block.
+// The transfer is sent on the relay chain for the chain's configured denom,
+// or on the parachain whose Denom in its ParachainConfig matches; see SendFundsOn to send on a specific chain.
 // Implements Chain interface.
 func (c *PolkadotChain) SendFunds(ctx context.Context, keyName string, amount ibc.WalletAmount) error {
-	panic("not implemented yet")
+	ctx = transcript.RecordSendFunds(ctx, c.cfg.ChainID, transcript.Funds{KeyName: keyName, Amount: amount})
+
+	loc, assetID, err := c.denomLocation(amount.Denom)
+	if err != nil {
+		return err
+	}
+	if assetID != "" {
+		return fmt.Errorf("sending asset %s on %s is not supported", assetID, loc)
+	}
+	return c.SendFundsOn(ctx, loc, keyName, amount.Address, amount.Amount)
 }
 
 // SendIBCTransfer sends an IBC transfer returning a transaction or an error if the transfer failed.
 // Implements Chain interface.
 func (c *PolkadotChain) SendIBCTransfer(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (ibc.Tx, error) {
-	panic("not implemented yet")
+	transcript.RecordTransfer(ctx, c.cfg.ChainID, transcript.Transfer{
+		ChannelID: channelID,
+		KeyName:   keyName,
+		Amounts:   []ibc.WalletAmount{amount},
+		Timeout:   timeout,
+	})
+	return ibc.Tx{}, errors.New("sending IBC transfers from polkadot chains is not implemented yet")
+}
+
+// SendIBCTransferBatch is not supported on polkadot chains, since transfers are not yet sent
+// as utility.batch extrinsics; it always returns an error, and sends nothing.
+// Implements ibc.BatchIBCTransferer.
+func (c *PolkadotChain) SendIBCTransferBatch(ctx context.Context, channelID, keyName string, amounts []ibc.WalletAmount, timeout *ibc.IBCTimeout) ([]ibc.Tx, error) {
+	return nil, fmt.Errorf("chain %s: batched IBC transfers are not supported for polkadot chains", c.cfg.ChainID)
 }
 
 // GetBalance fetches the current balance for a specific account address and denom.
+// The relay chain is queried for the chain's configured denom;
+// otherwise the parachain with a matching Denom or AssetIDs entry in its ParachainConfig is queried.
+// See GetBalanceOn to query a specific chain directly.
 // Implements Chain interface.
-func (c *PolkadotChain) GetBalance(ctx context.Context, address string, denom string) (int64, error) {
-	panic("not implemented yet")
+func (c *PolkadotChain) GetBalance(ctx context.Context, address string, denom string) (math.Int, error) {
+	loc, assetID, err := c.denomLocation(denom)
+	if err != nil {
+		return math.Int{}, err
+	}
+	return c.GetBalanceOn(ctx, loc, address, assetID)
+}
+
+// BalanceAtHeight fetches the balance for a specific account address and denom
+// in the state of the block at height, on the relay chain or parachain that GetBalance queries for denom.
+// Implements ibc.BalanceAtHeightQuerier.
+func (c *PolkadotChain) BalanceAtHeight(ctx context.Context, address, denom string, height uint64) (math.Int, error) {
+	loc, assetID, err := c.denomLocation(denom)
+	if err != nil {
+		return math.Int{}, err
+	}
+	return c.GetBalanceOnAtHeight(ctx, loc, address, assetID, height)
+}
+
+// ResolvedConfig returns the effective configuration of the started chain,
+// including the digests of the images run by its relay chain and parachain nodes.
+// Implements ibc.ConfigResolver.
+func (c *PolkadotChain) ResolvedConfig(ctx context.Context) (ibc.ResolvedChainConfig, error) {
+	cfg := c.Config()
+	resolved := ibc.ResolvedChainConfig{
+		Type:            cfg.Type,
+		Name:            cfg.Name,
+		ChainID:         cfg.ChainID,
+		Denom:           cfg.Denom,
+		Bech32Prefix:    cfg.Bech32Prefix,
+		HostRPCAddress:  c.GetHostRPCAddress(),
+		HostGRPCAddress: c.GetHostGRPCAddress(),
+	}
+	seen := make(map[string]bool)
+	addImage := func(cli *client.Client, image ibc.DockerImage) error {
+		ref := image.Ref()
+		if seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+		digest, err := dockerutil.ImageDigest(ctx, cli, ref)
+		if err != nil {
+			return err
+		}
+		resolved.Images = append(resolved.Images, ibc.ResolvedImage{DockerImage: image, Digest: digest})
+		return nil
+	}
+	for _, n := range c.RelayChainNodes {
+		if err := addImage(n.DockerClient, n.Image); err != nil {
+			return ibc.ResolvedChainConfig{}, err
+		}
+	}
+	for _, parachainNodes := range c.ParachainNodes {
+		for _, n := range parachainNodes {
+			if err := addImage(n.DockerClient, n.Image); err != nil {
+				return ibc.ResolvedChainConfig{}, err
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// ProfileEndpoints returns the Prometheus metrics endpoints, including wasm execution metrics,
+// of the started relay chain and parachain nodes.
+// Implements ibc.Profiler.
+func (c *PolkadotChain) ProfileEndpoints(ctx context.Context) ([]ibc.ProfileEndpoint, error) {
+	var endpoints []ibc.ProfileEndpoint
+	addEndpoint := func(container string, mu *sync.RWMutex, hostPort *string) {
+		mu.RLock()
+		defer mu.RUnlock()
+		if *hostPort == "" {
+			return
+		}
+		endpoints = append(endpoints, ibc.ProfileEndpoint{
+			Container:   container,
+			Kind:        ibc.ProfileKindMetrics,
+			HostAddress: *hostPort,
+		})
+	}
+	for _, n := range c.RelayChainNodes {
+		addEndpoint(n.Name(), &n.hostPortsMu, &n.hostPrometheusPort)
+	}
+	for _, parachainNodes := range c.ParachainNodes {
+		for _, n := range parachainNodes {
+			addEndpoint(n.Name(), &n.hostPortsMu, &n.hostPrometheusPort)
+		}
+	}
+	return endpoints, nil
+}
+
+// SupportsFeature reports whether the chain supports f.
+// Polkadot chains do not support any feature yet, as IBC transfers are not implemented.
+// Implements ibc.ChainCapabilities.
+func (c *PolkadotChain) SupportsFeature(f ibc.ChainFeature) bool {
+	return false
 }
 
 // GetGasFeesInNativeDenom gets the fees in native denom for an amount of spent gas.
 // Implements Chain interface.
-func (c *PolkadotChain) GetGasFeesInNativeDenom(gasPaid int64) int64 {
+func (c *PolkadotChain) GetGasFeesInNativeDenom(gasPaid int64) math.Int {
 	panic("not implemented yet")
 }
 
+// Indexer returns the event indexer for the chain, which follows the chain's new blocks.
+// The parachain's events are indexed if there is a parachain; otherwise the relay chain's are.
+// It returns an error if the chain has not been started.
+func (c *PolkadotChain) Indexer() (*Indexer, error) {
+	c.indexerMu.Lock()
+	defer c.indexerMu.Unlock()
+	if c.indexer != nil {
+		return c.indexer, nil
+	}
+
+	var api *gsrpc.SubstrateAPI
+	if len(c.ParachainNodes) > 0 && len(c.ParachainNodes[0]) > 0 {
+		api = c.ParachainNodes[0][0].api
+	} else if len(c.RelayChainNodes) > 0 {
+		api = c.RelayChainNodes[0].api
+	}
+	if api == nil {
+		return nil, fmt.Errorf("chain %s: indexer requires a started chain", c.cfg.ChainID)
+	}
+
+	idx := NewIndexer(api, IndexerOptions{})
+	// Following ends when the node's connection closes as the chain is torn down.
+	if err := idx.Follow(context.Background()); err != nil {
+		return nil, err
+	}
+	c.indexer = idx
+	return idx, nil
+}
+
+// Nonces returns the nonce manager for the accounts of the chain at loc,
+// shared by every helper that submits extrinsics to it.
+// The chain must be started before the nonce manager is used.
+func (c *PolkadotChain) Nonces(loc Location) *NonceManager {
+	c.noncesMu.Lock()
+	defer c.noncesMu.Unlock()
+	if c.nonces == nil {
+		c.nonces = make(map[Location]*NonceManager)
+	}
+	m, ok := c.nonces[loc]
+	if !ok {
+		m = newNonceManager(func(address string) (uint64, error) {
+			api, err := c.locationAPI(loc)
+			if err != nil {
+				return 0, err
+			}
+			return fetchAccountNextIndex(api, address)
+		})
+		c.nonces[loc] = m
+	}
+	return m
+}
+
 // Acknowledgements returns all acknowledgements in a block at height.
+// The packet data and acknowledgement bytes are not included in the IBC pallet's events,
+// so only the packet's sequence, ports, and channels are populated.
 // Implements Chain interface.
 func (c *PolkadotChain) Acknowledgements(ctx context.Context, height uint64) ([]ibc.PacketAcknowledgement, error) {
-	panic("not implemented yet")
+	idx, err := c.Indexer()
+	if err != nil {
+		return nil, err
+	}
+	events, err := idx.PacketEvents(ctx, height, PacketEventAcknowledge)
+	if err != nil {
+		return nil, err
+	}
+	acks := make([]ibc.PacketAcknowledgement, len(events))
+	for i, e := range events {
+		acks[i] = ibc.PacketAcknowledgement{Packet: e.packet()}
+	}
+	return acks, nil
 }
 
 // Timeouts returns all timeouts in a block at height.
+// As with Acknowledgements, only the packet's sequence, ports, and channels are populated.
 // Implements Chain interface.
 func (c *PolkadotChain) Timeouts(ctx context.Context, height uint64) ([]ibc.PacketTimeout, error) {
-	panic("not implemented yet")
+	idx, err := c.Indexer()
+	if err != nil {
+		return nil, err
+	}
+	events, err := idx.PacketEvents(ctx, height, PacketEventTimeout)
+	if err != nil {
+		return nil, err
+	}
+	timeouts := make([]ibc.PacketTimeout, len(events))
+	for i, e := range events {
+		timeouts[i] = ibc.PacketTimeout{Packet: e.packet()}
+	}
+	return timeouts, nil
 }
@@ -0,0 +1,30 @@
+package polkadot_test
+
+import (
+	"context"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/strangelove-ventures/ibctest/v6/chain/polkadot"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPolkadotChain_SendIBCTransferBatch(t *testing.T) {
+	t.Parallel()
+
+	c := polkadot.NewPolkadotChain(zap.NewNop(), t.Name(), ibc.ChainConfig{ChainID: "rococo-local"}, 1, nil)
+	txs, err := c.SendIBCTransferBatch(context.Background(), "channel-0", "alice", []ibc.WalletAmount{{Denom: "DOT", Amount: math.OneInt()}}, nil)
+	require.ErrorContains(t, err, "batched IBC transfers are not supported for polkadot chains")
+	require.Nil(t, txs)
+}
+
+func TestPolkadotChain_DisplayUnits(t *testing.T) {
+	t.Parallel()
+
+	c := polkadot.NewPolkadotChain(zap.NewNop(), t.Name(), ibc.ChainConfig{Type: "polkadot", ChainID: "rococo-local", Denom: "uDOT"}, 1, nil)
+	var du ibc.DisplayUnits = c
+	require.EqualValues(t, 12, du.Decimals())
+	require.Equal(t, "DOT", du.DisplayDenom())
+}
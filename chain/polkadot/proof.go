@@ -0,0 +1,153 @@
+package polkadot
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	gstypes "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+// ReadProof is a proof of a storage entry of a substrate chain at a block, as returned by the state_getReadProof RPC.
+type ReadProof struct {
+	// Height and hash of the block whose state root the proof is verified against.
+	Height    uint64
+	BlockHash gstypes.Hash
+
+	// Storage key proven.
+	Key gstypes.StorageKey
+
+	// Raw storage value at Key, or nil if the entry does not exist,
+	// in which case the proof is a non-membership proof of Key.
+	Value []byte
+
+	// Trie nodes of the proof.
+	Proof [][]byte
+}
+
+// readProofResult is the result of the state_getReadProof RPC.
+type readProofResult struct {
+	At    string   `json:"at"`
+	Proof []string `json:"proof"`
+}
+
+// QueryReadProof proves the storage entry at key of the chain at loc, as of height.
+// A zero height queries the latest block.
+func (c *PolkadotChain) QueryReadProof(ctx context.Context, loc Location, key gstypes.StorageKey, height uint64) (ReadProof, error) {
+	api, err := c.locationAPI(loc)
+	if err != nil {
+		return ReadProof{}, err
+	}
+	return queryReadProof(api, key, height)
+}
+
+// The proof queries below prove the IBC state in the IBC pallet of the parachain at loc, with QueryReadProof.
+
+// QueryClientStateProof proves the client state of clientID as of height.
+func (c *PolkadotChain) QueryClientStateProof(ctx context.Context, loc Location, clientID string, height uint64) (ReadProof, error) {
+	return c.queryIBCReadProof(ctx, loc, height, ibcClientStates, clientID)
+}
+
+// QueryConnectionProof proves the connection end of connectionID as of height.
+func (c *PolkadotChain) QueryConnectionProof(ctx context.Context, loc Location, connectionID string, height uint64) (ReadProof, error) {
+	return c.queryIBCReadProof(ctx, loc, height, ibcConnections, connectionID)
+}
+
+// QueryChannelProof proves the channel end of channelID on portID as of height.
+func (c *PolkadotChain) QueryChannelProof(ctx context.Context, loc Location, portID, channelID string, height uint64) (ReadProof, error) {
+	return c.queryIBCReadProof(ctx, loc, height, ibcChannels, portID, channelID)
+}
+
+// QueryPacketCommitmentProof proves the commitment of the packet with sequence sent on channelID as of height.
+func (c *PolkadotChain) QueryPacketCommitmentProof(ctx context.Context, loc Location, portID, channelID string, sequence, height uint64) (ReadProof, error) {
+	return c.queryIBCReadProof(ctx, loc, height, ibcPacketCommitments, portID, channelID, sequence)
+}
+
+// QueryPacketReceiptProof proves the receipt of the packet with sequence received on channelID as of height.
+// A non-membership proof proves that the packet has not been received.
+func (c *PolkadotChain) QueryPacketReceiptProof(ctx context.Context, loc Location, portID, channelID string, sequence, height uint64) (ReadProof, error) {
+	return c.queryIBCReadProof(ctx, loc, height, ibcPacketReceipts, portID, channelID, sequence)
+}
+
+// QueryPacketAcknowledgementProof proves the acknowledgement written for the packet with sequence received on channelID as of height.
+func (c *PolkadotChain) QueryPacketAcknowledgementProof(ctx context.Context, loc Location, portID, channelID string, sequence, height uint64) (ReadProof, error) {
+	return c.queryIBCReadProof(ctx, loc, height, ibcAcknowledgements, portID, channelID, sequence)
+}
+
+// queryIBCReadProof proves the entry of the IBC pallet's item at keys, which are identifiers or packet sequences.
+func (c *PolkadotChain) queryIBCReadProof(ctx context.Context, loc Location, height uint64, item string, keys ...any) (ReadProof, error) {
+	api, meta, _, err := c.ibcStorageAPI(ctx, loc)
+	if err != nil {
+		return ReadProof{}, err
+	}
+	entry, err := storageEntryType(meta, ibcPallet, item)
+	if err != nil {
+		return ReadProof{}, err
+	}
+	if !entry.IsMap || len(entry.AsMap.Hashers) != len(keys) {
+		return ReadProof{}, fmt.Errorf("%s.%s storage is not a map of %d keys", ibcPallet, item, len(keys))
+	}
+	encodedKeys, err := encodeIBCStorageKeys(keys...)
+	if err != nil {
+		return ReadProof{}, err
+	}
+	key, err := storageKeyPrefix(entry, ibcPallet, item, encodedKeys...)
+	if err != nil {
+		return ReadProof{}, err
+	}
+	proof, err := queryReadProof(api, key, height)
+	if err != nil {
+		return ReadProof{}, fmt.Errorf("proving %s %s.%s: %w", loc, ibcPallet, item, err)
+	}
+	return proof, nil
+}
+
+// queryReadProof returns the value and read proof of key as of height, or of the latest block if height is zero.
+func queryReadProof(api *gsrpc.SubstrateAPI, key gstypes.StorageKey, height uint64) (ReadProof, error) {
+	if height == 0 {
+		header, err := api.RPC.Chain.GetHeaderLatest()
+		if err != nil {
+			return ReadProof{}, fmt.Errorf("getting latest header: %w", err)
+		}
+		height = uint64(header.Number)
+	}
+	hash, err := api.RPC.Chain.GetBlockHash(height)
+	if err != nil {
+		return ReadProof{}, fmt.Errorf("getting block hash at height %d: %w", height, err)
+	}
+
+	raw, err := api.RPC.State.GetStorageRaw(key, hash)
+	if err != nil {
+		return ReadProof{}, fmt.Errorf("getting storage at height %d: %w", height, err)
+	}
+
+	var res readProofResult
+	if err := api.Client.Call(&res, "state_getReadProof", []string{key.Hex()}, hash.Hex()); err != nil {
+		return ReadProof{}, fmt.Errorf("getting read proof at height %d: %w", height, err)
+	}
+
+	return newReadProof(height, hash, key, raw, res)
+}
+
+// newReadProof returns the read proof of key at the block with height and hash,
+// from the raw storage value and the hex encoded trie nodes of the state_getReadProof result.
+func newReadProof(height uint64, hash gstypes.Hash, key gstypes.StorageKey, raw *gstypes.StorageDataRaw, res readProofResult) (ReadProof, error) {
+	proof := ReadProof{
+		Height:    height,
+		BlockHash: hash,
+		Key:       key,
+		Proof:     make([][]byte, len(res.Proof)),
+	}
+	if raw != nil && len(*raw) > 0 {
+		proof.Value = *raw
+	}
+	for i, node := range res.Proof {
+		var err error
+		if proof.Proof[i], err = hex.DecodeString(strings.TrimPrefix(node, "0x")); err != nil {
+			return ReadProof{}, fmt.Errorf("decoding read proof node %d: %w", i, err)
+		}
+	}
+	return proof, nil
+}
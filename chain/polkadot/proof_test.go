@@ -0,0 +1,52 @@
+package polkadot
+
+import (
+	"encoding/json"
+	"testing"
+
+	gstypes "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/stretchr/testify/require"
+)
+
+// readProofFixture is a state_getReadProof result with a branch node and a leaf node.
+const readProofFixture = `{
+	"at": "0x6d1e1a2c6f5a8d8f5b2e8ab0a4b4fb8c6f5a0c0d6cfd8d5f5c9d1d63b4cbb1a2",
+	"proof": [
+		"0x80011080d2a6b3f7bd1b3c05c8e0c6f56e5d2e1a06b1d58a9f5c3f0e4e1cce2a3a3c1c",
+		"0x5e0a6e5f2f3cbd50a5b1c1d4a39b0b4b0c2d10636c69656e74207374617465"
+	]
+}`
+
+func TestNewReadProof(t *testing.T) {
+	t.Parallel()
+
+	var res readProofResult
+	require.NoError(t, json.Unmarshal([]byte(readProofFixture), &res))
+
+	hash, err := gstypes.NewHashFromHexString(res.At)
+	require.NoError(t, err)
+	key := gstypes.NewStorageKey([]byte("client state key"))
+	value := gstypes.NewStorageDataRaw([]byte("client state"))
+
+	proof, err := newReadProof(12, hash, key, &value, res)
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), proof.Height)
+	require.Equal(t, hash, proof.BlockHash)
+	require.Equal(t, key, proof.Key)
+	require.Equal(t, []byte("client state"), proof.Value)
+	require.Len(t, proof.Proof, 2)
+	require.Equal(t, byte(0x80), proof.Proof[0][0])
+	require.Equal(t, []byte("client state"), proof.Proof[1][len(proof.Proof[1])-len("client state"):])
+
+	// Absent entries have a nil value and a non-membership proof.
+	for _, raw := range []*gstypes.StorageDataRaw{nil, {}} {
+		proof, err = newReadProof(12, hash, key, raw, res)
+		require.NoError(t, err)
+		require.Nil(t, proof.Value)
+		require.Len(t, proof.Proof, 2)
+	}
+
+	res.Proof = append(res.Proof, "0xzz")
+	_, err = newReadProof(12, hash, key, &value, res)
+	require.EqualError(t, err, "decoding read proof node 2: encoding/hex: invalid byte: U+007A 'z'")
+}
@@ -0,0 +1,123 @@
+package polkadot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// blocksPruningVersion is the first polkadot version with the --blocks-pruning flag.
+// Older nodes keep all blocks.
+var blocksPruningVersion = [3]int{0, 9, 29}
+
+// pruningFlags returns the flags that prune the state and blocks of substrate nodes of the given image version
+// as configured by p. The state is pruned with --pruning, which every version supports,
+// and blocks are only pruned by versions supporting --blocks-pruning.
+// Versions that cannot be parsed, such as "latest", are assumed to support it.
+// Flags set after them, e.g. in ChainConfig.AdditionalStartArgs, take precedence.
+//
+// It returns an error if p keeps a number of blocks that the version cannot prune.
+func pruningFlags(p ibc.Pruning, version string) ([]string, error) {
+	blocksPruning := versionAtLeast(version, blocksPruningVersion)
+	if p.Archive {
+		if !blocksPruning {
+			return []string{"--pruning=archive"}, nil
+		}
+		return []string{"--pruning=archive", "--blocks-pruning=archive"}, nil
+	}
+	var flags []string
+	if p.KeepRecent > 0 {
+		flags = append(flags, fmt.Sprintf("--pruning=%d", p.KeepRecent))
+	}
+	if p.KeepBlocks > 0 {
+		if !blocksPruning {
+			return nil, fmt.Errorf(
+				"keeping %d blocks requires polkadot v%d.%d.%d or later, got %s",
+				p.KeepBlocks, blocksPruningVersion[0], blocksPruningVersion[1], blocksPruningVersion[2], version,
+			)
+		}
+		flags = append(flags, fmt.Sprintf("--blocks-pruning=%d", p.KeepBlocks))
+	}
+	return flags, nil
+}
+
+// versionAtLeast reports whether version, e.g. "v0.9.29" or "0.9.30-rc1", is at least want.
+// Versions that cannot be parsed are assumed to be recent.
+func versionAtLeast(version string, want [3]int) bool {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) > len(want) {
+		return true
+	}
+	var have [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return true
+		}
+		have[i] = n
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			return have[i] > want[i]
+		}
+	}
+	return true
+}
+
+// Height returns the latest block height seen by the node.
+func (p *RelayChainNode) Height(ctx context.Context) (uint64, error) {
+	return nodeHeight(p.api)
+}
+
+// QueryHistoricalState queries the runtime version of the relay chain as of height,
+// returning an error if the node has pruned the state at height.
+// Implements test.HistoricalStateQuerier.
+func (p *RelayChainNode) QueryHistoricalState(ctx context.Context, height uint64) error {
+	if err := queryHistoricalState(p.api, height); err != nil {
+		return fmt.Errorf("%s: %w", p.Name(), err)
+	}
+	return nil
+}
+
+// Height returns the latest block height seen by the node.
+func (pn *ParachainNode) Height(ctx context.Context) (uint64, error) {
+	return nodeHeight(pn.api)
+}
+
+// QueryHistoricalState queries the runtime version of the parachain as of height,
+// returning an error if the node has pruned the state at height.
+// Implements test.HistoricalStateQuerier.
+func (pn *ParachainNode) QueryHistoricalState(ctx context.Context, height uint64) error {
+	if err := queryHistoricalState(pn.api, height); err != nil {
+		return fmt.Errorf("%s: %w", pn.Name(), err)
+	}
+	return nil
+}
+
+func nodeHeight(api *gsrpc.SubstrateAPI) (uint64, error) {
+	header, err := api.RPC.Chain.GetHeaderLatest()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	return uint64(header.Number), nil
+}
+
+// queryHistoricalState queries the runtime version as of height, which needs the state at height.
+func queryHistoricalState(api *gsrpc.SubstrateAPI, height uint64) error {
+	hash, err := api.RPC.Chain.GetBlockHash(height)
+	if err != nil {
+		return fmt.Errorf("failed to get block hash at height %d: %w", height, err)
+	}
+	if _, err := api.RPC.State.GetRuntimeVersion(hash); err != nil {
+		return fmt.Errorf("failed to query state at height %d: %w", height, err)
+	}
+	return nil
+}
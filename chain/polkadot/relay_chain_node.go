@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/avast/retry-go/v4"
 	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-connections/nat"
 
 	schnorrkel "github.com/ChainSafe/go-schnorrkel/1"
@@ -23,6 +26,8 @@ import (
 	"github.com/decred/dcrd/dcrec/secp256k1/v2"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/strangelove-ventures/ibctest/v6/transcript"
 )
 
 // RelayChainNode defines the properties required for running a polkadot relay chain node.
@@ -44,19 +49,30 @@ type RelayChainNode struct {
 	Ed25519PrivateKey p2pCrypto.PrivKey
 	EcdsaPrivateKey   secp256k1.PrivateKey
 
-	api         *gsrpc.SubstrateAPI
-	hostWsPort  string
-	hostRpcPort string
+	api *gsrpc.SubstrateAPI
+
+	// Host addresses of the node's ports, set once the container has started.
+	hostPortsMu        sync.RWMutex
+	hostWsPort         string
+	hostRpcPort        string
+	hostPrometheusPort string
+
+	logWatcher *dockerutil.LogWatcher
+	supervisor *dockerutil.Supervisor
 }
 
 type RelayChainNodes []*RelayChainNode
 
 const (
 	wsPort         = "27451/tcp"
-	rpcPort        = "27452/tcp"
+	p2pPort        = "27452/tcp"
 	prometheusPort = "27453/tcp"
+	rpcPort        = "27454/tcp"
 )
 
+// How long to wait for docker to publish the ports of a started node container.
+const hostPortsTimeout = 10 * time.Second
+
 var (
 	RtyAtt = retry.Attempts(10)
 	RtyDel = retry.Delay(time.Second * 2)
@@ -65,8 +81,20 @@ var (
 
 var exposedPorts = map[nat.Port]struct{}{
 	nat.Port(wsPort):         {},
-	nat.Port(rpcPort):        {},
+	nat.Port(p2pPort):        {},
 	nat.Port(prometheusPort): {},
+	nat.Port(rpcPort):        {},
+}
+
+// hostPortPublishing returns the port publishing of the nodes of a chain configured with cfg.
+// Unless no ports are published, the websocket port always is,
+// so that the node can be reached from the host, e.g. by polkadot.js apps.
+func hostPortPublishing(cfg ibc.ChainConfig) dockerutil.PortPublishing {
+	p := dockerutil.PortPublishing(cfg.HostPorts)
+	if !p.InternalOnly && !p.Published(wsPort) {
+		p.Publish = append(append([]string(nil), p.Publish...), wsPort)
+	}
+	return p
 }
 
 // Name returns the name of the test node.
@@ -145,7 +173,7 @@ func (p *RelayChainNode) MultiAddress() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("/dns4/%s/tcp/%s/p2p/%s", p.HostName(), strings.Split(rpcPort, "/")[0], peerId), nil
+	return fmt.Sprintf("/dns4/%s/tcp/%s/p2p/%s", p.HostName(), strings.Split(p2pPort, "/")[0], peerId), nil
 }
 
 func (c *RelayChainNode) logger() *zap.Logger {
@@ -173,13 +201,13 @@ func (p *RelayChainNode) RawChainSpecFilePathRelative() string {
 	return fmt.Sprintf("%s-raw.json", p.Chain.Config().ChainID)
 }
 
-// GenerateChainSpec builds the chain spec for the configured chain ID.
+// GenerateChainSpec builds the chain spec from the configured built-in chain spec, which defaults to the chain ID.
 func (p *RelayChainNode) GenerateChainSpec(ctx context.Context) error {
 	chainCfg := p.Chain.Config()
 	cmd := []string{
 		chainCfg.Bin,
 		"build-spec",
-		fmt.Sprintf("--chain=%s", chainCfg.ChainID),
+		fmt.Sprintf("--chain=%s", builtinChainSpec(chainCfg)),
 		"--disable-default-bootnode",
 	}
 	res := p.Exec(ctx, cmd, nil)
@@ -223,6 +251,7 @@ func (p *RelayChainNode) CreateNodeContainer(ctx context.Context) error {
 		chainCfg.Bin,
 		fmt.Sprintf("--chain=%s", p.RawChainSpecFilePathFull()),
 		fmt.Sprintf("--ws-port=%s", strings.Split(wsPort, "/")[0]),
+		fmt.Sprintf("--rpc-port=%s", strings.Split(rpcPort, "/")[0]),
 		fmt.Sprintf("--%s", IndexedName[p.Index]),
 		fmt.Sprintf("--node-key=%s", hex.EncodeToString(nodeKey[0:32])),
 		"--beefy",
@@ -231,34 +260,52 @@ func (p *RelayChainNode) CreateNodeContainer(ctx context.Context) error {
 		"--unsafe-rpc-external",
 		"--prometheus-external",
 		fmt.Sprintf("--prometheus-port=%s", strings.Split(prometheusPort, "/")[0]),
-		fmt.Sprintf("--listen-addr=/ip4/0.0.0.0/tcp/%s", strings.Split(rpcPort, "/")[0]),
+		fmt.Sprintf("--listen-addr=/ip4/0.0.0.0/tcp/%s", strings.Split(p2pPort, "/")[0]),
 		fmt.Sprintf("--public-addr=%s", multiAddress),
 		"--base-path", p.NodeHome(),
 	}
+	pruning, err := pruningFlags(chainCfg.Pruning, p.Image.Version)
+	if err != nil {
+		return fmt.Errorf("node %s: %w", p.Name(), err)
+	}
+	cmd = append(cmd, MergeFlags(DefaultRelayChainFlags, append(append(logFlags(chainCfg), pruning...), chainCfg.AdditionalStartArgs...))...)
+	start := chainCfg.StartCommand(cmd)
 	p.logger().
 		Info("Running command",
-			zap.String("command", strings.Join(cmd, " ")),
+			zap.String("command", strings.Join(start.Cmd, " ")),
+			zap.Strings("env", start.Env),
 			zap.String("container", p.Name()),
 		)
+	testreporter.TrackNodeCommand(ctx, p.Name(), start.Cmd, start.Env)
 
+	platform, err := dockerutil.ParsePlatform(p.Image.Platform)
+	if err != nil {
+		return err
+	}
+	publishAll, portBindings, err := hostPortPublishing(p.Chain.Config()).ReserveHostConfig(p.Name(), exposedPorts)
+	if err != nil {
+		return err
+	}
 	cc, err := p.DockerClient.ContainerCreate(
 		ctx,
 		&container.Config{
 			Image: p.Image.Ref(),
 
 			Entrypoint: []string{},
-			Cmd:        cmd,
+			Cmd:        start.Cmd,
+			Env:        start.Env,
 
 			Hostname: p.HostName(),
 			User:     dockerutil.GetRootUserString(),
 
-			Labels: map[string]string{dockerutil.CleanupLabel: p.TestName},
+			Labels: dockerutil.Labels(p.TestName),
 
 			ExposedPorts: exposedPorts,
 		},
 		&container.HostConfig{
 			Binds:           p.Bind(),
-			PublishAllPorts: true,
+			PublishAllPorts: publishAll,
+			PortBindings:    portBindings,
 			AutoRemove:      false,
 			DNS:             []string{},
 		},
@@ -267,20 +314,44 @@ func (p *RelayChainNode) CreateNodeContainer(ctx context.Context) error {
 				p.NetworkID: {},
 			},
 		},
-		nil,
+		platform,
 		p.Name(),
 	)
 	if err != nil {
+		dockerutil.ReleaseHostPorts(p.Name())
 		return err
 	}
 	p.containerID = cc.ID
 	return nil
 }
 
-// StopContainer stops the relay chain node container, waiting at most 30 seconds.
+// StopContainer gracefully stops the relay chain node container. See dockerutil.StopContainer.
 func (p *RelayChainNode) StopContainer(ctx context.Context) error {
-	timeout := 30 * time.Second
-	return p.DockerClient.ContainerStop(ctx, p.containerID, &timeout)
+	return dockerutil.StopContainer(ctx, p.DockerClient, p.containerID)
+}
+
+// RemoveContainer removes the relay chain node container and releases its reserved host ports.
+func (p *RelayChainNode) RemoveContainer(ctx context.Context) error {
+	err := p.DockerClient.ContainerRemove(ctx, p.containerID, dockertypes.ContainerRemoveOptions{
+		Force:         true,
+		RemoveVolumes: true,
+	})
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("remove container %s: %w", p.Name(), err)
+	}
+	dockerutil.ReleaseHostPorts(p.Name())
+	return nil
+}
+
+// HostEndpoints returns the addresses of the node's RPC and websocket ports on the host,
+// or false if the node's container has not started yet.
+func (p *RelayChainNode) HostEndpoints() (HostEndpoints, bool) {
+	p.hostPortsMu.RLock()
+	defer p.hostPortsMu.RUnlock()
+	if p.hostWsPort == "" {
+		return HostEndpoints{}, false
+	}
+	return HostEndpoints{RPC: p.hostRpcPort, WS: p.hostWsPort}, true
 }
 
 // StartContainer starts the container after it is built by CreateNodeContainer.
@@ -289,19 +360,29 @@ func (p *RelayChainNode) StartContainer(ctx context.Context) error {
 		return err
 	}
 
-	c, err := p.DockerClient.ContainerInspect(ctx, p.containerID)
+	// Set the host ports once since they will not change after the container has started.
+	portsCtx, cancel := context.WithTimeout(ctx, hostPortsTimeout)
+	defer cancel()
+	publishing := hostPortPublishing(p.Chain.Config())
+	hostPorts, err := dockerutil.ResolveHostPorts(portsCtx, p.DockerClient, p.containerID, p.HostName(), publishing, wsPort, rpcPort, prometheusPort)
 	if err != nil {
 		return err
 	}
+	p.hostPortsMu.Lock()
+	p.hostWsPort, p.hostRpcPort, p.hostPrometheusPort = hostPorts[0], hostPorts[1], hostPorts[2]
+	p.hostPortsMu.Unlock()
 
-	// Set the host ports once since they will not change after the container has started.
-	p.hostWsPort = dockerutil.GetHostPort(c, wsPort)
-	p.hostRpcPort = dockerutil.GetHostPort(c, rpcPort)
+	if p.logWatcher != nil {
+		p.logWatcher.Watch(p.containerID, p.Name())
+	}
+	if p.supervisor != nil {
+		p.supervisor.Watch(p.containerID, p.Name())
+	}
 
 	var api *gsrpc.SubstrateAPI
 	if err = retry.Do(func() error {
 		var err error
-		api, err = gsrpc.NewSubstrateAPI("ws://" + p.hostWsPort)
+		api, err = gsrpc.NewSubstrateAPI("ws://" + hostPorts[0])
 		return err
 	}, retry.Context(ctx), RtyAtt, RtyDel, RtyErr); err != nil {
 		return err
@@ -314,11 +395,13 @@ func (p *RelayChainNode) StartContainer(ctx context.Context) error {
 
 // Exec runs a container for a specific job and blocks until the container exits.
 func (p *RelayChainNode) Exec(ctx context.Context, cmd []string, env []string) dockerutil.ContainerExecResult {
-	job := dockerutil.NewImage(p.log, p.DockerClient, p.NetworkID, p.TestName, p.Image.Repository, p.Image.Version)
+	transcript.RecordChainExec(ctx, p.Chain.Config().ChainID, p.Name(), cmd, env)
+	job := dockerutil.NewImage(p.log, p.DockerClient, p.NetworkID, p.TestName, p.Image.Repository, p.Image.Tag())
 	opts := dockerutil.ContainerOptions{
-		Binds: p.Bind(),
-		Env:   env,
-		User:  dockerutil.GetRootUserString(),
+		Binds:    p.Bind(),
+		Env:      env,
+		User:     dockerutil.GetRootUserString(),
+		Platform: p.Image.Platform,
 	}
 	return job.Run(ctx, cmd, opts)
 }
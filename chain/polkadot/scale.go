@@ -0,0 +1,296 @@
+package polkadot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/scale"
+	gstypes "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+)
+
+// ScaleVariant is a decoded value of a SCALE enum.
+type ScaleVariant struct {
+	// Name of the variant, e.g. "SendPacket".
+	Name string
+
+	// Fields of the variant, decoded the same way as a composite:
+	// a map[string]any for named fields, a []any for unnamed fields,
+	// the inner value for a single unnamed field, or nil for no fields.
+	Fields any
+}
+
+// scaleDecoder decodes SCALE-encoded values using the type registry in V14 metadata,
+// so that values can be decoded without Go types for every runtime type.
+//
+// Decoded values are represented as:
+//   - bool, string, uint64, int64, or *big.Int for primitives and compacts;
+//   - []byte for sequences and arrays of u8;
+//   - []any for other sequences, arrays, and tuples;
+//   - map[string]any for composites with named fields;
+//   - ScaleVariant for enums, except Option which decodes to nil or the inner value.
+type scaleDecoder struct {
+	lookup map[int64]*gstypes.Si1Type
+}
+
+func newScaleDecoder(meta *gstypes.Metadata) (*scaleDecoder, error) {
+	if meta.Version != 14 {
+		return nil, fmt.Errorf("unsupported metadata version %d: only V14 is supported", meta.Version)
+	}
+	return &scaleDecoder{lookup: meta.AsMetadataV14.EfficientLookup}, nil
+}
+
+// Decode decodes a single value of the type with the given id from b,
+// and returns an error if any bytes are left over.
+func (sd *scaleDecoder) Decode(b []byte, id int64) (any, error) {
+	r := bytes.NewReader(b)
+	v, err := sd.decode(scale.NewDecoder(r), id)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d bytes left over after decoding type %d", r.Len(), id)
+	}
+	return v, nil
+}
+
+func (sd *scaleDecoder) decode(d *scale.Decoder, id int64) (any, error) {
+	t, ok := sd.lookup[id]
+	if !ok {
+		return nil, fmt.Errorf("type %d not found in metadata", id)
+	}
+
+	def := t.Def
+	switch {
+	case def.IsComposite:
+		return sd.decodeFields(d, def.Composite.Fields)
+	case def.IsVariant:
+		return sd.decodeVariant(d, t)
+	case def.IsSequence:
+		n, err := d.DecodeUintCompact()
+		if err != nil {
+			return nil, err
+		}
+		return sd.decodeList(d, n.Uint64(), def.Sequence.Type.Int64())
+	case def.IsArray:
+		return sd.decodeList(d, uint64(def.Array.Len), def.Array.Type.Int64())
+	case def.IsTuple:
+		if len(def.Tuple) == 0 {
+			return nil, nil
+		}
+		vals := make([]any, len(def.Tuple))
+		for i, elemID := range def.Tuple {
+			v, err := sd.decode(d, elemID.Int64())
+			if err != nil {
+				return nil, err
+			}
+			vals[i] = v
+		}
+		return vals, nil
+	case def.IsPrimitive:
+		return decodePrimitive(d, def.Primitive.Si0TypeDefPrimitive)
+	case def.IsCompact:
+		n, err := d.DecodeUintCompact()
+		if err != nil {
+			return nil, err
+		}
+		if n.IsUint64() {
+			return n.Uint64(), nil
+		}
+		return n, nil
+	case def.IsBitSequence:
+		// Assumes the common u8 bit store; bit sequences do not appear in the events we inspect.
+		nBits, err := d.DecodeUintCompact()
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, (nBits.Uint64()+7)/8)
+		if err := d.Read(b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported definition for type %d", id)
+	}
+}
+
+func (sd *scaleDecoder) decodeFields(d *scale.Decoder, fields []gstypes.Si1Field) (any, error) {
+	switch {
+	case len(fields) == 0:
+		return nil, nil
+	case len(fields) == 1 && !fields[0].HasName:
+		// Newtype wrapper; unwrap it.
+		return sd.decode(d, fields[0].Type.Int64())
+	case fields[0].HasName:
+		m := make(map[string]any, len(fields))
+		for _, f := range fields {
+			v, err := sd.decode(d, f.Type.Int64())
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.Name, err)
+			}
+			m[string(f.Name)] = v
+		}
+		return m, nil
+	default:
+		vals := make([]any, len(fields))
+		for i, f := range fields {
+			v, err := sd.decode(d, f.Type.Int64())
+			if err != nil {
+				return nil, fmt.Errorf("field %d: %w", i, err)
+			}
+			vals[i] = v
+		}
+		return vals, nil
+	}
+}
+
+func (sd *scaleDecoder) decodeVariant(d *scale.Decoder, t *gstypes.Si1Type) (any, error) {
+	idx, err := d.ReadOneByte()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range t.Def.Variant.Variants {
+		if byte(v.Index) != idx {
+			continue
+		}
+		fields, err := sd.decodeFields(d, v.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("variant %s: %w", v.Name, err)
+		}
+		if isOptionType(t) {
+			// None has no fields and Some has exactly one, so fields is already nil or the inner value.
+			return fields, nil
+		}
+		return ScaleVariant{Name: string(v.Name), Fields: fields}, nil
+	}
+	return nil, fmt.Errorf("variant index %d not found in type %v", idx, t.Path)
+}
+
+func (sd *scaleDecoder) decodeList(d *scale.Decoder, n uint64, elemID int64) (any, error) {
+	if elem, ok := sd.lookup[elemID]; ok && elem.Def.IsPrimitive && elem.Def.Primitive.Si0TypeDefPrimitive == gstypes.IsU8 {
+		b := make([]byte, n)
+		if n > 0 {
+			if err := d.Read(b); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+
+	vals := make([]any, n)
+	for i := range vals {
+		v, err := sd.decode(d, elemID)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		vals[i] = v
+	}
+	return vals, nil
+}
+
+// EncodeUint SCALE encodes the decimal unsigned integer s as the type with the given id,
+// unwrapping single field composites, as is common for IDs such as asset IDs.
+func (sd *scaleDecoder) EncodeUint(id int64, s string) ([]byte, error) {
+	t, ok := sd.lookup[id]
+	if !ok {
+		return nil, fmt.Errorf("type %d not found in metadata", id)
+	}
+
+	def := t.Def
+	switch {
+	case def.IsComposite && len(def.Composite.Fields) == 1:
+		return sd.EncodeUint(def.Composite.Fields[0].Type.Int64(), s)
+	case def.IsPrimitive:
+		size, ok := map[gstypes.Si0TypeDefPrimitive]int{
+			gstypes.IsU8: 1, gstypes.IsU16: 2, gstypes.IsU32: 4, gstypes.IsU64: 8, gstypes.IsU128: 16, gstypes.IsU256: 32,
+		}[def.Primitive.Si0TypeDefPrimitive]
+		if !ok {
+			return nil, fmt.Errorf("type %d is not an unsigned integer", id)
+		}
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok || n.Sign() < 0 || n.BitLen() > 8*size {
+			return nil, fmt.Errorf("invalid %d byte unsigned integer %q", size, s)
+		}
+		b := n.FillBytes(make([]byte, size))
+		// Reverse big-endian bytes to little-endian.
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported definition for unsigned integer type %d", id)
+	}
+}
+
+func isOptionType(t *gstypes.Si1Type) bool {
+	return len(t.Path) == 1 && t.Path[0] == "Option"
+}
+
+func decodePrimitive(d *scale.Decoder, p gstypes.Si0TypeDefPrimitive) (any, error) {
+	readLE := func(n int) ([]byte, error) {
+		b := make([]byte, n)
+		if err := d.Read(b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	switch p {
+	case gstypes.IsBool:
+		b, err := d.ReadOneByte()
+		return b == 1, err
+	case gstypes.IsStr:
+		n, err := d.DecodeUintCompact()
+		if err != nil {
+			return nil, err
+		}
+		b, err := readLE(int(n.Uint64()))
+		return string(b), err
+	case gstypes.IsChar, gstypes.IsU8, gstypes.IsU16, gstypes.IsU32, gstypes.IsU64:
+		size := map[gstypes.Si0TypeDefPrimitive]int{
+			gstypes.IsChar: 4, gstypes.IsU8: 1, gstypes.IsU16: 2, gstypes.IsU32: 4, gstypes.IsU64: 8,
+		}[p]
+		b, err := readLE(size)
+		if err != nil {
+			return nil, err
+		}
+		var buf [8]byte
+		copy(buf[:], b)
+		return binary.LittleEndian.Uint64(buf[:]), nil
+	case gstypes.IsI8, gstypes.IsI16, gstypes.IsI32, gstypes.IsI64:
+		size := map[gstypes.Si0TypeDefPrimitive]int{
+			gstypes.IsI8: 1, gstypes.IsI16: 2, gstypes.IsI32: 4, gstypes.IsI64: 8,
+		}[p]
+		b, err := readLE(size)
+		if err != nil {
+			return nil, err
+		}
+		var buf [8]byte
+		copy(buf[:], b)
+		u := binary.LittleEndian.Uint64(buf[:])
+		// Sign-extend from the encoded width.
+		shift := 64 - 8*size
+		return int64(u<<shift) >> shift, nil
+	case gstypes.IsU128, gstypes.IsU256, gstypes.IsI128, gstypes.IsI256:
+		size := 16
+		if p == gstypes.IsU256 || p == gstypes.IsI256 {
+			size = 32
+		}
+		b, err := readLE(size)
+		if err != nil {
+			return nil, err
+		}
+		// Reverse little-endian bytes to big-endian for big.Int.
+		for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+		n := new(big.Int).SetBytes(b)
+		if (p == gstypes.IsI128 || p == gstypes.IsI256) && b[0]&0x80 != 0 {
+			n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(8*size)))
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unsupported primitive %d", p)
+	}
+}
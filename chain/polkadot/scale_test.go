@@ -0,0 +1,178 @@
+package polkadot
+
+import (
+	"encoding/binary"
+	"testing"
+
+	gstypes "github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+// Type ids in the test registry.
+const (
+	testTypeU8 int64 = iota
+	testTypeBytes
+	testTypeU64
+	testTypeOptionU64
+	testTypeIbcEvent
+	testTypeIbcEvents
+)
+
+func testTypeID(id int64) gstypes.Si1LookupTypeID {
+	return gstypes.NewSi1LookupTypeIDFromUInt(uint64(id))
+}
+
+func testNamedField(name string, id int64) gstypes.Si1Field {
+	return gstypes.Si1Field{HasName: true, Name: gstypes.Text(name), Type: testTypeID(id)}
+}
+
+func testRegistry() map[int64]*gstypes.Si1Type {
+	return map[int64]*gstypes.Si1Type{
+		testTypeU8: {Def: gstypes.Si1TypeDef{
+			IsPrimitive: true,
+			Primitive:   gstypes.Si1TypeDefPrimitive{Si0TypeDefPrimitive: gstypes.IsU8},
+		}},
+		testTypeBytes: {Def: gstypes.Si1TypeDef{
+			IsSequence: true,
+			Sequence:   gstypes.Si1TypeDefSequence{Type: testTypeID(testTypeU8)},
+		}},
+		testTypeU64: {Def: gstypes.Si1TypeDef{
+			IsPrimitive: true,
+			Primitive:   gstypes.Si1TypeDefPrimitive{Si0TypeDefPrimitive: gstypes.IsU64},
+		}},
+		testTypeOptionU64: {
+			Path: gstypes.Si1Path{"Option"},
+			Def: gstypes.Si1TypeDef{
+				IsVariant: true,
+				Variant: gstypes.Si1TypeDefVariant{Variants: []gstypes.Si1Variant{
+					{Name: "None", Index: 0},
+					{Name: "Some", Index: 1, Fields: []gstypes.Si1Field{{Type: testTypeID(testTypeU64)}}},
+				}},
+			},
+		},
+		testTypeIbcEvent: {Def: gstypes.Si1TypeDef{
+			IsVariant: true,
+			Variant: gstypes.Si1TypeDefVariant{Variants: []gstypes.Si1Variant{
+				{Name: "NewBlock", Index: 0, Fields: []gstypes.Si1Field{
+					testNamedField("revision_height", testTypeU64),
+				}},
+				{Name: "SendPacket", Index: 1, Fields: []gstypes.Si1Field{
+					testNamedField("port_id", testTypeBytes),
+					testNamedField("channel_id", testTypeBytes),
+					testNamedField("dest_port", testTypeBytes),
+					testNamedField("dest_channel", testTypeBytes),
+					testNamedField("sequence", testTypeU64),
+					testNamedField("timeout", testTypeOptionU64),
+				}},
+			}},
+		}},
+		testTypeIbcEvents: {Def: gstypes.Si1TypeDef{
+			IsSequence: true,
+			Sequence:   gstypes.Si1TypeDefSequence{Type: testTypeID(testTypeIbcEvent)},
+		}},
+	}
+}
+
+func encodeTestBytes(s string) []byte {
+	// Compact length prefix, valid for lengths under 64.
+	return append([]byte{byte(len(s) << 2)}, s...)
+}
+
+func encodeTestU64(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, n)
+	return b
+}
+
+func TestScaleDecoder(t *testing.T) {
+	t.Parallel()
+
+	sd := &scaleDecoder{lookup: testRegistry()}
+
+	var b []byte
+	b = append(b, 2<<2) // Two events.
+	b = append(b, 0)    // NewBlock.
+	b = append(b, encodeTestU64(10)...)
+	b = append(b, 1) // SendPacket.
+	b = append(b, encodeTestBytes("transfer")...)
+	b = append(b, encodeTestBytes("channel-0")...)
+	b = append(b, encodeTestBytes("transfer")...)
+	b = append(b, encodeTestBytes("channel-1")...)
+	b = append(b, encodeTestU64(7)...)
+	b = append(b, 0) // Timeout is None.
+
+	v, err := sd.Decode(b, testTypeIbcEvents)
+	require.NoError(t, err)
+	require.Equal(t, []any{
+		ScaleVariant{Name: "NewBlock", Fields: map[string]any{"revision_height": uint64(10)}},
+		ScaleVariant{Name: "SendPacket", Fields: map[string]any{
+			"port_id":      []byte("transfer"),
+			"channel_id":   []byte("channel-0"),
+			"dest_port":    []byte("transfer"),
+			"dest_channel": []byte("channel-1"),
+			"sequence":     uint64(7),
+			"timeout":      nil,
+		}},
+	}, v)
+
+	require.Equal(t, []PacketEvent{
+		{
+			Height:        3,
+			Type:          PacketEventSend,
+			Sequence:      7,
+			SourcePort:    "transfer",
+			SourceChannel: "channel-0",
+			DestPort:      "transfer",
+			DestChannel:   "channel-1",
+		},
+	}, collectPacketEvents(3, v))
+
+	_, err = sd.Decode(append(b, 0), testTypeIbcEvents)
+	require.ErrorContains(t, err, "left over")
+
+	_, err = sd.Decode([]byte{1 << 2, 9}, testTypeIbcEvents)
+	require.ErrorContains(t, err, "variant index 9 not found")
+}
+
+func TestEventsFromRecords(t *testing.T) {
+	t.Parallel()
+
+	records := []any{
+		map[string]any{
+			"phase":  ScaleVariant{Name: "ApplyExtrinsic", Fields: uint64(1)},
+			"event":  ScaleVariant{Name: "Sudo", Fields: ScaleVariant{Name: "Sudid", Fields: map[string]any{}}},
+			"topics": []any{},
+		},
+	}
+
+	events, err := eventsFromRecords(5, records)
+	require.NoError(t, err)
+	require.Equal(t, []Event{
+		{Height: 5, Index: 0, Pallet: "Sudo", Name: "Sudid", Fields: map[string]any{}},
+	}, events)
+
+	_, err = eventsFromRecords(5, []any{uint64(1)})
+	require.Error(t, err)
+}
+
+func TestPacketEventTransfer(t *testing.T) {
+	t.Parallel()
+
+	data := transfertypes.NewFungibleTokenPacketData("uatom", "100", "5Sender", "cosmos1receiver")
+	events := collectPacketEvents(3, ScaleVariant{Name: PacketEventReceive, Fields: map[string]any{
+		"port_id":  []byte("transfer"),
+		"sequence": uint64(1),
+		"data":     data.GetBytes(),
+	}})
+	require.Len(t, events, 1)
+
+	got, err := events[0].Transfer()
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+	require.NoError(t, ibc.ExpectedTransfer{Amount: "100", Receiver: "cosmos1receiver"}.Check(events[0].packet().Data))
+
+	_, err = PacketEvent{Type: PacketEventAcknowledge}.Transfer()
+	require.Error(t, err)
+}
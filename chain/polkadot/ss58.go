@@ -1,6 +1,9 @@
 package polkadot
 
 import (
+	"bytes"
+	"fmt"
+
 	"github.com/mr-tron/base58"
 	"golang.org/x/crypto/blake2b"
 )
@@ -29,6 +32,34 @@ func EncodeAddressSS58(key []byte) (string, error) {
 	return base58.Encode(final), nil
 }
 
+// DecodeAddressSS58 returns the 32 byte account ID encoded in an SS58 address of any address format.
+func DecodeAddressSS58(address string) ([]byte, error) {
+	b, err := base58.Decode(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ss58 address %q: %w", address, err)
+	}
+
+	// Formats below 64 are encoded in one byte; others in two, with the second bit of the first byte set.
+	prefixLen := 1
+	if len(b) > 0 && b[0]&0x40 != 0 {
+		prefixLen = 2
+	}
+	if len(b) != prefixLen+32+2 {
+		return nil, fmt.Errorf("invalid ss58 address %q: unexpected length %d", address, len(b))
+	}
+
+	data, sum := b[:prefixLen+32], b[prefixLen+32:]
+	checksum, err := ss58Checksum(data)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(sum, checksum[:2]) {
+		return nil, fmt.Errorf("invalid ss58 address %q: checksum mismatch", address)
+	}
+
+	return data[prefixLen:], nil
+}
+
 func ss58Checksum(data []byte) ([]byte, error) {
 	hasher, err := blake2b.New512(nil)
 	if err != nil {
@@ -8,7 +8,6 @@ import (
 	"sync"
 
 	"github.com/strangelove-ventures/ibctest/v6/chain/cosmos"
-	"github.com/strangelove-ventures/ibctest/v6/chain/penumbra"
 	"github.com/strangelove-ventures/ibctest/v6/chain/polkadot"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/label"
@@ -51,6 +50,13 @@ var embeddedConfiguredChains []byte
 
 var logConfiguredChainsSourceOnce sync.Once
 
+// builtinGenesisModifiers are the ModifyGenesis functions of the built-in chain configs that need them, by name.
+// A ChainSpec setting its own ModifyGenesis replaces the built-in one.
+var builtinGenesisModifiers = map[string]func(ibc.ChainConfig, []byte) ([]byte, error){
+	// Votes on proposals storing wasm light client code end well within a test.
+	"ibc-go-simd-wasm": cosmos.ModifyGenesisWasmClients("10s"),
+}
+
 // initBuiltinChainConfig returns an ibc.ChainConfig mapping all configured chains
 func initBuiltinChainConfig(log *zap.Logger) (map[string]ibc.ChainConfig, error) {
 	var dat []byte
@@ -76,6 +82,14 @@ func initBuiltinChainConfig(log *zap.Logger) (map[string]ibc.ChainConfig, error)
 		return nil, fmt.Errorf("error unmarshalling pre-configured chains: %w", err)
 	}
 
+	// Genesis modifications cannot be configured in YAML.
+	for name, modify := range builtinGenesisModifiers {
+		if cfg, ok := builtinChainConfigs[name]; ok && cfg.ModifyGenesis == nil {
+			cfg.ModifyGenesis = modify
+			builtinChainConfigs[name] = cfg
+		}
+	}
+
 	logConfiguredChainsSourceOnce.Do(func() {
 		if val != "" {
 			log.Info("Using user specified configured chains", zap.String("file", val))
@@ -99,7 +113,8 @@ func (f *BuiltinChainFactory) Count() int {
 func (f *BuiltinChainFactory) Chains(testName string) ([]ibc.Chain, error) {
 	chains := make([]ibc.Chain, len(f.specs))
 	for i, s := range f.specs {
-		cfg, err := s.Config(f.log)
+		// Validate the spec before any chain is built, so that a misconfigured spec fails without docker work.
+		cfg, err := s.validatedConfig(f.log)
 		if err != nil {
 			// Prefer to wrap the error with the chain name if possible.
 			if s.Name != "" {
@@ -109,7 +124,18 @@ func (f *BuiltinChainFactory) Chains(testName string) ([]ibc.Chain, error) {
 			return nil, fmt.Errorf("failed to build chain config at index %d: %w", i, err)
 		}
 
-		chain, err := buildChain(f.log, testName, *cfg, s.NumValidators, s.NumFullNodes)
+		// Clone before applying image overrides, so that the spec's images are not modified.
+		chainCfg := cfg.Clone()
+		if err := applyImageOverrides(&chainCfg); err != nil {
+			return nil, err
+		}
+
+		var chain ibc.Chain
+		if len(s.Parachains) > 0 {
+			chain, err = buildParachains(f.log, testName, chainCfg, s.NumValidators, s.Parachains)
+		} else {
+			chain, err = buildChain(f.log, testName, chainCfg, s.NumValidators, s.NumFullNodes)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -133,30 +159,31 @@ func buildChain(log *zap.Logger, testName string, cfg ibc.ChainConfig, numValida
 	if numFullNodes != nil {
 		nf = *numFullNodes
 	}
-
-	switch cfg.Type {
-	case "cosmos":
-		return cosmos.NewCosmosChain(testName, cfg, nv, nf, log), nil
-	case "penumbra":
-		return penumbra.NewPenumbraChain(log, testName, cfg, nv, nf), nil
-	case "polkadot":
-		switch {
-		case strings.Contains(cfg.Name, "composable"):
-			parachains := []polkadot.ParachainConfig{{
-				Bin:             "composable-node",
-				ChainID:         "dali-dev",
-				Image:           cfg.Images[1],
-				NumNodes:        nf,
-				Flags:           []string{"--execution=wasm", "--wasmtime-instantiation-strategy=recreate-instance-copy-on-write"},
-				RelayChainFlags: []string{"--execution=wasm"},
-			}}
-			return polkadot.NewPolkadotChain(log, testName, cfg, nv, parachains), nil
-		default:
-			return nil, fmt.Errorf("unexpected error, unknown polkadot parachain: %s", cfg.Name)
+	if n := cfg.SentriesPerValidator; n > 0 {
+		if cfg.P2P != nil {
+			return nil, fmt.Errorf("chain %s cannot set both sentries per validator and a p2p topology", cfg.Name)
 		}
-	default:
-		return nil, fmt.Errorf("unexpected error, unknown chain type: %s for chain: %s", cfg.Type, cfg.Name)
+		nf = nv * n
+		cfg.P2P = ibc.SentryTopology(nv, n)
+	}
+
+	newChain, ok := chainConstructor(cfg.Type)
+	if !ok {
+		return nil, fmt.Errorf("unexpected error, unknown chain type: %s for chain: %s (registered types are: %s)", cfg.Type, cfg.Name, strings.Join(registeredChainTypes(), ", "))
+	}
+	return newChain(log, testName, cfg, nv, nf)
+}
+
+// buildParachains returns a polkadot chain with the given parachains rather than those selected by the chain's name.
+func buildParachains(log *zap.Logger, testName string, cfg ibc.ChainConfig, numValidators *int, parachains []polkadot.ParachainConfig) (ibc.Chain, error) {
+	if cfg.Type != "polkadot" {
+		return nil, fmt.Errorf("chain %s of type %s cannot have parachains", cfg.Name, cfg.Type)
+	}
+	nv := defaultNumValidators
+	if numValidators != nil {
+		nv = *numValidators
 	}
+	return polkadot.NewPolkadotChain(log, testName, cfg, nv, append([]polkadot.ParachainConfig(nil), parachains...)), nil
 }
 
 func (f *BuiltinChainFactory) Name() string {
@@ -0,0 +1,99 @@
+package ibctest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/strangelove-ventures/ibctest/v6/chain/cosmos"
+	"github.com/strangelove-ventures/ibctest/v6/chain/penumbra"
+	"github.com/strangelove-ventures/ibctest/v6/chain/polkadot"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"go.uber.org/zap"
+)
+
+// ChainConstructor returns a chain of a registered chain type, configured by cfg,
+// with numValidators validators and numFullNodes full nodes.
+// The chain is not initialized or started yet.
+type ChainConstructor func(log *zap.Logger, testName string, cfg ibc.ChainConfig, numValidators, numFullNodes int) (ibc.Chain, error)
+
+var (
+	chainConstructorsMu sync.RWMutex
+	chainConstructors   = map[string]ChainConstructor{
+		"cosmos":   newCosmosChain,
+		"penumbra": newPenumbraChain,
+		"polkadot": newPolkadotChain,
+	}
+)
+
+// RegisterChain is available for external packages that may import ibctest,
+// to register any external chain implementations they may provide.
+// Chains whose ibc.ChainConfig.Type is typ are then built with constructor,
+// so that they can be used through ChainSpec and NewBuiltinChainFactory like the built-in chain types.
+//
+// Like label.RegisterChainLabel, RegisterChain is typically called inside init functions.
+// It panics if typ is empty or already registered.
+func RegisterChain(typ string, constructor ChainConstructor) {
+	if typ == "" {
+		panic(fmt.Errorf("chain type must not be empty"))
+	}
+	if constructor == nil {
+		panic(fmt.Errorf("chain type %q must not be registered with a nil constructor", typ))
+	}
+
+	chainConstructorsMu.Lock()
+	defer chainConstructorsMu.Unlock()
+
+	if _, exists := chainConstructors[typ]; exists {
+		panic(fmt.Errorf("chain type %q already exists and must not be double registered", typ))
+	}
+	chainConstructors[typ] = constructor
+}
+
+// chainConstructor returns the constructor registered for the chain type typ.
+func chainConstructor(typ string) (ChainConstructor, bool) {
+	chainConstructorsMu.RLock()
+	defer chainConstructorsMu.RUnlock()
+
+	c, ok := chainConstructors[typ]
+	return c, ok
+}
+
+// registeredChainTypes returns the sorted registered chain types.
+func registeredChainTypes() []string {
+	chainConstructorsMu.RLock()
+	defer chainConstructorsMu.RUnlock()
+
+	types := make([]string, 0, len(chainConstructors))
+	for typ := range chainConstructors {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func newCosmosChain(log *zap.Logger, testName string, cfg ibc.ChainConfig, numValidators, numFullNodes int) (ibc.Chain, error) {
+	return cosmos.NewCosmosChain(testName, cfg, numValidators, numFullNodes, log), nil
+}
+
+func newPenumbraChain(log *zap.Logger, testName string, cfg ibc.ChainConfig, numValidators, numFullNodes int) (ibc.Chain, error) {
+	return penumbra.NewPenumbraChain(log, testName, cfg, numValidators, numFullNodes), nil
+}
+
+func newPolkadotChain(log *zap.Logger, testName string, cfg ibc.ChainConfig, numValidators, numFullNodes int) (ibc.Chain, error) {
+	switch {
+	case strings.Contains(cfg.Name, "composable"):
+		parachains := []polkadot.ParachainConfig{{
+			Bin:             "composable-node",
+			ChainID:         "dali-dev",
+			Image:           cfg.Images[1],
+			NumNodes:        numFullNodes,
+			Flags:           []string{"--execution=wasm", "--wasmtime-instantiation-strategy=recreate-instance-copy-on-write"},
+			RelayChainFlags: []string{"--execution=wasm"},
+		}}
+		return polkadot.NewPolkadotChain(log, testName, cfg, numValidators, parachains), nil
+	default:
+		return nil, fmt.Errorf("unexpected error, unknown polkadot parachain: %s", cfg.Name)
+	}
+}
@@ -0,0 +1,90 @@
+package ibctest_test
+
+import (
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6"
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// registeredChain is a mock chain recording the node counts it was created with.
+type registeredChain struct {
+	*mock.Chain
+
+	numValidators, numFullNodes int
+}
+
+func TestRegisterChain(t *testing.T) {
+	t.Parallel()
+
+	ibctest.RegisterChain("registry-test", func(_ *zap.Logger, _ string, cfg ibc.ChainConfig, numValidators, numFullNodes int) (ibc.Chain, error) {
+		return &registeredChain{Chain: mock.NewChain(cfg), numValidators: numValidators, numFullNodes: numFullNodes}, nil
+	})
+
+	require.Panics(t, func() {
+		ibctest.RegisterChain("registry-test", func(*zap.Logger, string, ibc.ChainConfig, int, int) (ibc.Chain, error) {
+			return nil, nil
+		})
+	})
+	require.Panics(t, func() {
+		ibctest.RegisterChain("cosmos", func(*zap.Logger, string, ibc.ChainConfig, int, int) (ibc.Chain, error) {
+			return nil, nil
+		})
+	})
+
+	nv, nf := 1, 0
+	cf := ibctest.NewBuiltinChainFactory(zaptest.NewLogger(t), []*ibctest.ChainSpec{{
+		Name:          "myrollup",
+		Version:       "v1.2.3",
+		NumValidators: &nv,
+		NumFullNodes:  &nf,
+		ChainConfig: ibc.ChainConfig{
+			Type:           "registry-test",
+			ChainID:        "myrollup-1",
+			Images:         []ibc.DockerImage{{Repository: "docker.example.com/myrollup"}},
+			Bin:            "myrollupd",
+			Bech32Prefix:   "roll",
+			Denom:          "uroll",
+			GasPrices:      "0uroll",
+			GasAdjustment:  1.5,
+			TrustingPeriod: "24h",
+		},
+	}})
+
+	chains, err := cf.Chains(t.Name())
+	require.NoError(t, err)
+	require.Len(t, chains, 1)
+
+	c := chains[0].(*registeredChain)
+	require.Equal(t, "registry-test", c.Config().Type)
+	require.Equal(t, "v1.2.3", c.Config().Images[0].Version)
+	require.Equal(t, 1, c.numValidators)
+	require.Equal(t, 0, c.numFullNodes)
+}
+
+func TestBuiltinChainFactory_UnknownType(t *testing.T) {
+	t.Parallel()
+
+	cf := ibctest.NewBuiltinChainFactory(zaptest.NewLogger(t), []*ibctest.ChainSpec{{
+		Name:    "unknown-type",
+		Version: "v1",
+		ChainConfig: ibc.ChainConfig{
+			Type:           "not-registered",
+			ChainID:        "unknown-1",
+			Images:         []ibc.DockerImage{{Repository: "docker.example.com/unknown"}},
+			Bin:            "unknownd",
+			Bech32Prefix:   "unknown",
+			Denom:          "uunknown",
+			GasPrices:      "0uunknown",
+			GasAdjustment:  1.5,
+			TrustingPeriod: "24h",
+		},
+	}})
+
+	_, err := cf.Chains(t.Name())
+	require.ErrorContains(t, err, "unknown chain type: not-registered")
+}
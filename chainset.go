@@ -3,6 +3,7 @@ package ibctest
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -12,6 +13,7 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/internal/blockdb"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -26,6 +28,9 @@ type chainSet struct {
 
 	chains map[ibc.Chain]struct{}
 
+	// Map of chain to the chains that must have started before it starts.
+	dependencies map[ibc.Chain][]ibc.Chain
+
 	// The following fields are set during TrackBlocks, and used in Close.
 	trackerEg  *errgroup.Group
 	db         *sql.DB
@@ -92,9 +97,13 @@ func (cs *chainSet) CreateCommonAccount(ctx context.Context, keyName string) (be
 				return fmt.Errorf("failed to get account address for key %q on chain %s: %w", keyName, config.Name, err)
 			}
 
-			b32, err := types.Bech32ifyAddressBytes(config.Bech32Prefix, addrBytes)
-			if err != nil {
-				return fmt.Errorf("failed to Bech32ifyAddressBytes on chain %s: %w", config.Name, err)
+			// Chains without a bech32 prefix, such as polkadot chains, return their address as is.
+			b32 := string(addrBytes)
+			if config.Bech32Prefix != "" {
+				b32, err = types.Bech32ifyAddressBytes(config.Bech32Prefix, addrBytes)
+				if err != nil {
+					return fmt.Errorf("failed to Bech32ifyAddressBytes on chain %s: %w", config.Name, err)
+				}
 			}
 
 			mu.Lock()
@@ -112,24 +121,152 @@ func (cs *chainSet) CreateCommonAccount(ctx context.Context, keyName string) (be
 	return bech32, nil
 }
 
-// Start concurrently calls Start against each chain in the set.
-func (cs *chainSet) Start(ctx context.Context, testName string, additionalGenesisWallets map[ibc.Chain][]ibc.WalletAmount) error {
+// Start concurrently calls Start against each chain in the set,
+// with at most maxConcurrent chains starting at once, or without a limit if maxConcurrent is not positive.
+// A chain with dependencies starts once all of them have started; independent chains start concurrently.
+//
+// The first failure cancels the chains still starting.
+// The returned error combines the failure of every chain that did not fail only due to that cancellation,
+// each identified by chain name and ID.
+func (cs *chainSet) Start(ctx context.Context, testName string, additionalGenesisWallets map[ibc.Chain][]ibc.WalletAmount, maxConcurrent int) error {
 	eg, egCtx := errgroup.WithContext(ctx)
+	if maxConcurrent > 0 {
+		eg.SetLimit(maxConcurrent)
+	}
+
+	var (
+		mu   sync.Mutex
+		errs error
+	)
 
+	started := make(map[ibc.Chain]chan struct{}, len(cs.chains))
 	for c := range cs.chains {
+		started[c] = make(chan struct{})
+	}
+
+	// Chains are scheduled after their dependencies,
+	// so that chains waiting for their dependencies cannot take every slot of the concurrency limit.
+	for _, c := range cs.startOrder() {
 		c := c
 		eg.Go(func() error {
-			if err := c.Start(testName, egCtx, additionalGenesisWallets[c]...); err != nil {
-				return fmt.Errorf("failed to start chain %s: %w", c.Config().Name, err)
+			for _, dep := range cs.dependencies[c] {
+				depStarted, ok := started[dep]
+				if !ok {
+					continue
+				}
+				select {
+				case <-depStarted:
+				case <-egCtx.Done():
+					return egCtx.Err()
+				}
 			}
 
-			return nil
+			// Do not start remaining chains once another chain has failed.
+			if err := egCtx.Err(); err != nil {
+				return err
+			}
+
+			config := c.Config()
+			chainStartDone := testreporter.StartPhase(egCtx, testreporter.PhaseChainStart, config.ChainID)
+			err := c.Start(testName, egCtx, additionalGenesisWallets[c]...)
+			if err == nil {
+				chainStartDone()
+				close(started[c])
+				return nil
+			}
+
+			err = fmt.Errorf("failed to start chain %s (%s): %w", config.Name, config.ChainID, err)
+			if ctx.Err() != nil || !errors.Is(err, context.Canceled) {
+				mu.Lock()
+				errs = multierr.Append(errs, err)
+				mu.Unlock()
+			}
+			return err
 		})
 	}
 
+	if err := eg.Wait(); err != nil {
+		if errs == nil {
+			return err
+		}
+		return errs
+	}
+	return nil
+}
+
+// chainReadyPollInterval is how often Build polls the health of started chains.
+const chainReadyPollInterval = time.Second
+
+// WaitReady waits until every chain in the set that implements ibc.HealthChecker reports that it is ready,
+// polling each chain every pollInterval.
+// It fails as soon as a chain reports that it is not live, or once timeout elapses,
+// with the last reported health of each chain that is not ready.
+func (cs *chainSet) WaitReady(ctx context.Context, timeout, pollInterval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	for c := range cs.chains {
+		hc, ok := c.(ibc.HealthChecker)
+		if !ok {
+			continue
+		}
+		config := c.Config()
+		eg.Go(func() error {
+			var last string
+			for {
+				status, err := hc.HealthCheck(egCtx)
+				switch {
+				case err != nil:
+					// The chain may not respond yet; only report the error if it never does.
+					last = err.Error()
+				case !status.Live:
+					return fmt.Errorf("chain %s (%s) is %s", config.Name, config.ChainID, status)
+				case status.Ready:
+					return nil
+				default:
+					last = status.String()
+				}
+
+				select {
+				case <-egCtx.Done():
+					if ctx.Err() == nil {
+						// Another chain failed.
+						return egCtx.Err()
+					}
+					return fmt.Errorf("chain %s (%s) not ready after %s: %s", config.Name, config.ChainID, timeout, last)
+				case <-time.After(pollInterval):
+				}
+			}
+		})
+	}
 	return eg.Wait()
 }
 
+// startOrder returns the chains in the set ordered after their dependencies.
+// Dependencies outside of the set are ignored; dependencies must not be cyclic.
+func (cs *chainSet) startOrder() []ibc.Chain {
+	order := make([]ibc.Chain, 0, len(cs.chains))
+	visited := make(map[ibc.Chain]bool, len(cs.chains))
+	var visit func(c ibc.Chain)
+	visit = func(c ibc.Chain) {
+		if visited[c] {
+			return
+		}
+		visited[c] = true
+		for _, dep := range cs.dependencies[c] {
+			if _, ok := cs.chains[dep]; ok {
+				visit(dep)
+			}
+		}
+		order = append(order, c)
+	}
+	for c := range cs.chains {
+		visit(c)
+	}
+	return order
+}
+
 // TrackBlocks initializes database tables and polls for transactions to be saved in the database.
 // This method is a nop if dbPath is blank.
 // The gitSha is used to pin a git commit to a test invocation. Thus, when a user is looking at historical
@@ -0,0 +1,203 @@
+package ibctest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// startChain is a mock chain whose Start calls start, if set.
+type startChain struct {
+	*mock.Chain
+
+	start func(ctx context.Context) error
+}
+
+func newStartChain(cfg ibc.ChainConfig, start func(ctx context.Context) error) *startChain {
+	return &startChain{Chain: mock.NewChain(cfg), start: start}
+}
+
+func (c *startChain) Start(testName string, ctx context.Context, additionalGenesisWallets ...ibc.WalletAmount) error {
+	if c.start == nil {
+		return c.Chain.Start(testName, ctx, additionalGenesisWallets...)
+	}
+	return c.start(ctx)
+}
+
+func TestChainSet_Start(t *testing.T) {
+	t.Parallel()
+
+	t.Run("concurrency limit", func(t *testing.T) {
+		t.Parallel()
+
+		var active, maxActive int32
+		start := func(ctx context.Context) error {
+			n := atomic.AddInt32(&active, 1)
+			defer atomic.AddInt32(&active, -1)
+			for {
+				m := atomic.LoadInt32(&maxActive)
+				if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+
+		var chains []ibc.Chain
+		for _, id := range []string{"a", "b", "c", "d", "e"} {
+			chains = append(chains, newStartChain(ibc.ChainConfig{Name: id, ChainID: id}, start))
+		}
+
+		cs := newChainSet(zap.NewNop(), chains)
+		require.NoError(t, cs.Start(context.Background(), t.Name(), nil, 2))
+		require.EqualValues(t, 2, maxActive)
+	})
+
+	t.Run("failure attribution", func(t *testing.T) {
+		t.Parallel()
+
+		chains := []ibc.Chain{
+			newStartChain(ibc.ChainConfig{Name: "gaia", ChainID: "gaia-1"}, func(ctx context.Context) error {
+				return errors.New("boom")
+			}),
+			newStartChain(ibc.ChainConfig{Name: "osmosis", ChainID: "osmosis-1"}, func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}),
+		}
+
+		cs := newChainSet(zap.NewNop(), chains)
+		err := cs.Start(context.Background(), t.Name(), nil, 0)
+		require.ErrorContains(t, err, "failed to start chain gaia (gaia-1): boom")
+		require.NotContains(t, err.Error(), "osmosis")
+	})
+	t.Run("dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		var order []string
+		newChain := func(id string) *startChain {
+			return newStartChain(ibc.ChainConfig{Name: id, ChainID: id}, func(ctx context.Context) error {
+				time.Sleep(10 * time.Millisecond)
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, id)
+				return nil
+			})
+		}
+		relay, para, consumer, other := newChain("relay"), newChain("para"), newChain("consumer"), newChain("other")
+
+		cs := newChainSet(zap.NewNop(), []ibc.Chain{consumer, para, relay, other})
+		cs.dependencies = map[ibc.Chain][]ibc.Chain{
+			para:     {relay},
+			consumer: {para},
+		}
+		// A limit of one would deadlock if a waiting chain took the only slot.
+		require.NoError(t, cs.Start(context.Background(), t.Name(), nil, 1))
+
+		index := func(id string) int {
+			for i, o := range order {
+				if o == id {
+					return i
+				}
+			}
+			return -1
+		}
+		require.Len(t, order, 4)
+		require.Less(t, index("relay"), index("para"))
+		require.Less(t, index("para"), index("consumer"))
+	})
+
+	t.Run("failed dependency", func(t *testing.T) {
+		t.Parallel()
+
+		var dependentStarted int32
+		dep := newStartChain(ibc.ChainConfig{Name: "provider", ChainID: "provider-1"}, func(ctx context.Context) error {
+			return errors.New("boom")
+		})
+		dependent := newStartChain(ibc.ChainConfig{Name: "consumer", ChainID: "consumer-1"}, func(ctx context.Context) error {
+			atomic.StoreInt32(&dependentStarted, 1)
+			return nil
+		})
+
+		cs := newChainSet(zap.NewNop(), []ibc.Chain{dep, dependent})
+		cs.dependencies = map[ibc.Chain][]ibc.Chain{dependent: {dep}}
+		err := cs.Start(context.Background(), t.Name(), nil, 0)
+		require.ErrorContains(t, err, "failed to start chain provider (provider-1): boom")
+		require.NotContains(t, err.Error(), "consumer")
+		require.Zero(t, atomic.LoadInt32(&dependentStarted))
+	})
+}
+
+// healthChain is a mock chain whose HealthCheck calls health.
+type healthChain struct {
+	*mock.Chain
+
+	health func() (ibc.HealthStatus, error)
+}
+
+func newHealthChain(cfg ibc.ChainConfig, health func() (ibc.HealthStatus, error)) *healthChain {
+	return &healthChain{Chain: mock.NewChain(cfg), health: health}
+}
+
+func (c *healthChain) HealthCheck(ctx context.Context) (ibc.HealthStatus, error) {
+	return c.health()
+}
+
+func TestChainSet_WaitReady(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ready", func(t *testing.T) {
+		t.Parallel()
+
+		var checks int32
+		chain := newHealthChain(ibc.ChainConfig{Name: "gaia", ChainID: "gaia-1"}, func() (ibc.HealthStatus, error) {
+			switch atomic.AddInt32(&checks, 1) {
+			case 1:
+				return ibc.HealthStatus{}, errors.New("connection refused")
+			case 2:
+				return ibc.HealthStatus{Live: true, Reason: "catching up"}, nil
+			default:
+				return ibc.HealthStatus{Live: true, Ready: true}, nil
+			}
+		})
+
+		// Chains without health checks are not waited for.
+		cs := newChainSet(zap.NewNop(), []ibc.Chain{chain, mock.NewChain(ibc.ChainConfig{Name: "other"})})
+		require.NoError(t, cs.WaitReady(context.Background(), time.Minute, time.Millisecond))
+		require.EqualValues(t, 3, atomic.LoadInt32(&checks))
+	})
+
+	t.Run("not live", func(t *testing.T) {
+		t.Parallel()
+
+		chain := newHealthChain(ibc.ChainConfig{Name: "gaia", ChainID: "gaia-1"}, func() (ibc.HealthStatus, error) {
+			return ibc.HealthStatus{Reason: "consensus failure"}, nil
+		})
+
+		cs := newChainSet(zap.NewNop(), []ibc.Chain{chain})
+		err := cs.WaitReady(context.Background(), time.Minute, time.Millisecond)
+		require.EqualError(t, err, "chain gaia (gaia-1) is not live: consensus failure")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		chain := newHealthChain(ibc.ChainConfig{Name: "gaia", ChainID: "gaia-1"}, func() (ibc.HealthStatus, error) {
+			return ibc.HealthStatus{Live: true, Reason: "node val-0 has no peers"}, nil
+		})
+
+		cs := newChainSet(zap.NewNop(), []ibc.Chain{chain})
+		err := cs.WaitReady(context.Background(), 20*time.Millisecond, time.Millisecond)
+		require.EqualError(t, err, "chain gaia (gaia-1) not ready after 20ms: live but not ready: node val-0 has no peers")
+	})
+}
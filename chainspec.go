@@ -3,12 +3,15 @@ package ibctest
 import (
 	"errors"
 	"fmt"
+	"path"
 	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 
+	"github.com/strangelove-ventures/ibctest/v6/chain/polkadot"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
 	"github.com/strangelove-ventures/ibctest/v6/label"
 	"go.uber.org/zap"
 )
@@ -41,6 +44,11 @@ type ChainSpec struct {
 	// If unspecified, NumValidators defaults to 2 and NumFullNodes defaults to 1.
 	NumValidators, NumFullNodes *int
 
+	// Parachains of a polkadot chain, replacing the parachains that the built-in config's name selects.
+	// The version of a chain with parachains only sets the relay chain image;
+	// each parachain's image is set by its config.
+	Parachains []polkadot.ParachainConfig
+
 	// Generate the automatic suffix on demand when needed.
 	autoSuffixOnce sync.Once
 	autoSuffix     string
@@ -49,11 +57,9 @@ type ChainSpec struct {
 // Config returns the underlying ChainConfig,
 // with any overrides applied.
 func (s *ChainSpec) Config(log *zap.Logger) (*ibc.ChainConfig, error) {
-	if s.Version == "" {
-		// Version must be set at top-level if not set in inlined config.
-		if len(s.ChainConfig.Images) == 0 || s.ChainConfig.Images[0].Version == "" {
-			return nil, errors.New("ChainSpec.Version must not be empty")
-		}
+	// Version must be set at top-level if not set in inlined config or pinned by the built-in config.
+	if s.Version == "" && s.Name == "" && !imageVersionSet(s.ChainConfig) {
+		return nil, errors.New("ChainSpec.Version must not be empty")
 	}
 
 	// s.Name and chainConfig.Name are interchangeable
@@ -69,11 +75,17 @@ func (s *ChainSpec) Config(log *zap.Logger) (*ibc.ChainConfig, error) {
 		if s.ChainConfig.Name == "" && s.ChainName != "" {
 			s.ChainConfig.Name = s.ChainName
 		}
-		if !s.ChainConfig.IsFullyConfigured() {
-			return nil, errors.New("ChainSpec.Name required when not all config fields are set")
+		if s.ChainConfig.IsFullyConfigured() {
+			return s.applyConfigOverrides(s.ChainConfig)
 		}
 
-		return s.applyConfigOverrides(s.ChainConfig)
+		// A custom image alone is enough for a simd-like cosmos chain.
+		if len(s.ChainConfig.Images) > 0 && s.ChainConfig.Images[0].Repository != "" &&
+			(s.ChainConfig.Type == "" || s.ChainConfig.Type == "cosmos") {
+			return s.applyConfigOverrides(customImageDefaults(s.ChainConfig.Clone()))
+		}
+
+		return nil, errors.New("ChainSpec.Name required when not all config fields are set")
 	}
 
 	builtinChainConfigs, err := initBuiltinChainConfig(log)
@@ -105,23 +117,101 @@ func (s *ChainSpec) Config(log *zap.Logger) (*ibc.ChainConfig, error) {
 
 	// Apply any overrides from this ChainSpec.
 	cfg = cfg.MergeChainSpecConfig(s.ChainConfig)
+	if s.Version == "" && !imageVersionSet(cfg) {
+		return nil, errors.New("ChainSpec.Version must not be empty")
+	}
 
 	// Apply remaining top-level overrides.
 	return s.applyConfigOverrides(cfg)
 }
 
+// Validate returns an error describing the first problem found in the spec before any docker work starts,
+// such as a missing image, an unsupported version string, a uid:gid not of the form uid:gid,
+// or more polkadot nodes than there are substrate dev accounts to derive their keys from.
+// BuiltinChainFactory validates each spec when building its chains,
+// so that a misconfigured spec fails fast rather than deep inside Initialize.
+func (s *ChainSpec) Validate(log *zap.Logger) error {
+	_, err := s.validatedConfig(log)
+	return err
+}
+
+// validatedConfig returns the config of s, or an error if the config or the node counts of s are invalid.
+func (s *ChainSpec) validatedConfig(log *zap.Logger) (*ibc.ChainConfig, error) {
+	cfg, err := s.Config(log)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Images) == 0 {
+		return nil, fmt.Errorf("chain %s has no images: set ChainConfig.Images", cfg.Name)
+	}
+	for i, image := range cfg.Images {
+		if err := image.Validate(); err != nil {
+			return nil, fmt.Errorf("chain %s image %d: %w", cfg.Name, i, err)
+		}
+		if _, err := dockerutil.ParsePlatform(image.Platform); err != nil {
+			return nil, fmt.Errorf("chain %s image %d: %w", cfg.Name, i, err)
+		}
+	}
+
+	nv := defaultNumValidators
+	if s.NumValidators != nil {
+		nv = *s.NumValidators
+	}
+	nf := defaultNumFullNodes
+	if s.NumFullNodes != nil {
+		nf = *s.NumFullNodes
+	}
+	if nv < 1 {
+		return nil, fmt.Errorf("chain %s: NumValidators must be at least 1, got %d", cfg.Name, nv)
+	}
+	if nf < 0 {
+		return nil, fmt.Errorf("chain %s: NumFullNodes must not be negative, got %d", cfg.Name, nf)
+	}
+
+	if cfg.Type != "polkadot" {
+		if len(s.Parachains) > 0 {
+			return nil, fmt.Errorf("chain %s of type %s cannot have parachains", cfg.Name, cfg.Type)
+		}
+		return cfg, nil
+	}
+	if err := polkadot.ValidateNumNodes(nv); err != nil {
+		return nil, fmt.Errorf("chain %s relay chain (NumValidators): %w", cfg.Name, err)
+	}
+	if len(s.Parachains) > 0 {
+		if err := polkadot.ValidateParachainConfigs(s.Parachains); err != nil {
+			return nil, fmt.Errorf("chain %s: %w", cfg.Name, err)
+		}
+	} else if err := polkadot.ValidateNumNodes(nf); err != nil {
+		// The parachain of a built-in polkadot chain runs one node per full node.
+		return nil, fmt.Errorf("chain %s parachain (NumFullNodes): %w", cfg.Name, err)
+	}
+	return cfg, nil
+}
+
+// imageVersionSet reports whether the first image of cfg has a version.
+func imageVersionSet(cfg ibc.ChainConfig) bool {
+	return len(cfg.Images) > 0 && cfg.Images[0].Version != ""
+}
+
 func (s *ChainSpec) applyConfigOverrides(cfg ibc.ChainConfig) (*ibc.ChainConfig, error) {
+	// Without a spec name, only a custom image config may carry a name to base generated names on.
+	specName := s.Name
+	if specName == "" {
+		specName = cfg.Name
+	}
+
 	// If no ChainName provided, generate one based on the spec name.
 	cfg.Name = s.ChainName
 	if cfg.Name == "" {
-		cfg.Name = s.Name + s.suffix()
+		cfg.Name = specName + s.suffix()
 	}
 
 	// If no ChainID provided, generate one -- prefer chain name but fall back to spec name.
 	if cfg.ChainID == "" {
 		prefix := s.ChainName
 		if prefix == "" {
-			prefix = s.Name
+			prefix = specName
 		}
 		cfg.ChainID = prefix + s.suffix()
 	}
@@ -140,16 +230,22 @@ func (s *ChainSpec) applyConfigOverrides(cfg ibc.ChainConfig) (*ibc.ChainConfig,
 	switch cfg.Type {
 	case "cosmos":
 		if s.Version != "" && len(cfg.Images) > 0 {
-			cfg.Images[0].Version = s.Version
+			cfg.Images[0] = cfg.Images[0].WithVersion(s.Version)
 		}
 	case "penumbra":
+		if len(cfg.Images) < 2 {
+			return nil, errors.New("penumbra chains require two images, for tendermint and penumbra")
+		}
 		versionSplit := strings.Split(s.Version, ",")
 		if len(versionSplit) != 2 {
 			return nil, errors.New("penumbra version should be comma separated penumbra_version,tendermint_version")
 		}
-		cfg.Images[0].Version = versionSplit[1]
-		cfg.Images[1].Version = versionSplit[0]
+		cfg.Images[0] = cfg.Images[0].WithVersion(versionSplit[1])
+		cfg.Images[1] = cfg.Images[1].WithVersion(versionSplit[0])
 	case "polkadot":
+		if len(cfg.Images) == 0 {
+			return nil, errors.New("polkadot chains require a relay chain image")
+		}
 		versionSplit := strings.Split(s.Version, ",")
 		relayChainImageSplit := strings.Split(versionSplit[0], ":")
 		var relayChainVersion string
@@ -161,12 +257,17 @@ func (s *ChainSpec) applyConfigOverrides(cfg ibc.ChainConfig) (*ibc.ChainConfig,
 		} else {
 			relayChainVersion = relayChainImageSplit[0]
 		}
-		cfg.Images[0].Version = relayChainVersion
+		cfg.Images[0] = cfg.Images[0].WithVersion(relayChainVersion)
 		switch {
+		case len(s.Parachains) > 0:
+			// Parachain images are set by s.Parachains.
 		case strings.Contains(s.Name, "composable"):
 			if len(versionSplit) != 2 {
 				return nil, fmt.Errorf("unexpected composable version: %s. should be comma separated polkadot:version,composable:version", s.Version)
 			}
+			if len(cfg.Images) < 2 {
+				return nil, errors.New("composable chains require two images, for the relay chain and the parachain")
+			}
 			imageSplit := strings.Split(versionSplit[1], ":")
 			if len(imageSplit) != 2 {
 				return nil, fmt.Errorf("parachain versions should be in the format parachain_name:parachain_version, got: %s", versionSplit[1])
@@ -174,15 +275,49 @@ func (s *ChainSpec) applyConfigOverrides(cfg ibc.ChainConfig) (*ibc.ChainConfig,
 			if !strings.Contains(cfg.Images[1].Repository, imageSplit[0]) {
 				return nil, fmt.Errorf("unexpected parachain: %s", imageSplit[0])
 			}
-			cfg.Images[1].Version = imageSplit[1]
+			cfg.Images[1] = cfg.Images[1].WithVersion(imageSplit[1])
 		default:
 			return nil, fmt.Errorf("unexpected parachain: %s", s.Name)
 		}
+	default:
+		// Chain types registered through RegisterChain use a single image, like cosmos chains.
+		if s.Version != "" && len(cfg.Images) > 0 {
+			cfg.Images[0] = cfg.Images[0].WithVersion(s.Version)
+		}
 	}
 
 	return &cfg, nil
 }
 
+// customImageDefaults fills in the fields of a cosmos chain config that only sets a custom image
+// with the defaults of a simd-like chain.
+// The name defaults to the last element of the image repository.
+// Bin is left empty if unset, so that the chain detects the binary from the image when it is initialized.
+func customImageDefaults(cfg ibc.ChainConfig) ibc.ChainConfig {
+	if cfg.Type == "" {
+		cfg.Type = "cosmos"
+	}
+	if cfg.Name == "" {
+		cfg.Name = path.Base(cfg.Images[0].Repository)
+	}
+	if cfg.Bech32Prefix == "" {
+		cfg.Bech32Prefix = "cosmos"
+	}
+	if cfg.Denom == "" {
+		cfg.Denom = "stake"
+	}
+	if cfg.GasPrices == "" {
+		cfg.GasPrices = "0" + cfg.Denom
+	}
+	if cfg.GasAdjustment == 0 {
+		cfg.GasAdjustment = 1.3
+	}
+	if cfg.TrustingPeriod == "" {
+		cfg.TrustingPeriod = "504h"
+	}
+	return cfg
+}
+
 // suffix returns the automatically generated, concurrency-safe suffix for
 // generating a chain name or chain ID.
 func (s *ChainSpec) suffix() string {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/strangelove-ventures/ibctest/v6"
+	"github.com/strangelove-ventures/ibctest/v6/chain/polkadot"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
@@ -47,6 +48,58 @@ func TestChainSpec_Config(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("custom image defaults", func(t *testing.T) {
+		s := ibctest.ChainSpec{
+			Version: "v0.46.0",
+			ChainConfig: ibc.ChainConfig{
+				Images: []ibc.DockerImage{
+					{Repository: "ghcr.io/example/simd"},
+				},
+			},
+		}
+
+		cfg, err := s.Config(zaptest.NewLogger(t))
+		require.NoError(t, err)
+
+		require.Equal(t, "cosmos", cfg.Type)
+		require.Regexp(t, `^simd-\d+$`, cfg.Name)
+		require.Equal(t, cfg.Name, cfg.ChainID)
+		require.Equal(t, "v0.46.0", cfg.Images[0].Version)
+		require.Equal(t, "cosmos", cfg.Bech32Prefix)
+		require.Equal(t, "stake", cfg.Denom)
+		require.Equal(t, "0stake", cfg.GasPrices)
+		require.Equal(t, "504h", cfg.TrustingPeriod)
+
+		// Bin is detected from the image when the chain is initialized.
+		require.Empty(t, cfg.Bin)
+	})
+
+	t.Run("simd with wasm clients", func(t *testing.T) {
+		s := ibctest.ChainSpec{
+			Name:    "ibc-go-simd-wasm",
+			Version: "feat-wasm-clients",
+		}
+
+		cfg, err := s.Config(zaptest.NewLogger(t))
+		require.NoError(t, err)
+
+		require.Equal(t, "simd", cfg.Bin)
+		require.True(t, cfg.HasAdditionalFeature(ibc.FeatureWasmClients))
+		require.NotNil(t, cfg.ModifyGenesis)
+
+		// The image version is pinned, so the spec need not set one.
+		s = ibctest.ChainSpec{Name: "ibc-go-simd-wasm"}
+		cfg, err = s.Config(zaptest.NewLogger(t))
+		require.NoError(t, err)
+		require.Equal(t, "feat-wasm-clients", cfg.Images[0].Version)
+		require.NoError(t, cfg.Images[0].Validate())
+
+		s = ibctest.ChainSpec{Name: "ibc-go-simd-wasm", Version: "feat-wasm-clients-v2"}
+		cfg, err = s.Config(zaptest.NewLogger(t))
+		require.NoError(t, err)
+		require.Equal(t, "feat-wasm-clients-v2", cfg.Images[0].Tag())
+	})
+
 	t.Run("consistently generated config", func(t *testing.T) {
 		s := ibctest.ChainSpec{
 			Name: "gaia",
@@ -165,3 +218,94 @@ func TestChainSpec_Config(t *testing.T) {
 		})
 	})
 }
+
+func TestChainSpec_Validate(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+
+	t.Run("valid", func(t *testing.T) {
+		s := ibctest.ChainSpec{Name: "gaia", Version: "v7.0.1"}
+		require.NoError(t, s.Validate(zaptest.NewLogger(t)))
+
+		s = ibctest.ChainSpec{Name: "composable", Version: "polkadot:v0.9.19,composable:centauri", NumValidators: intPtr(5)}
+		require.NoError(t, s.Validate(zaptest.NewLogger(t)))
+	})
+
+	for _, tt := range []struct {
+		name    string
+		spec    *ibctest.ChainSpec
+		wantErr string
+	}{
+		{
+			name:    "missing version",
+			spec:    &ibctest.ChainSpec{Name: "gaia"},
+			wantErr: "ChainSpec.Version must not be empty",
+		},
+		{
+			name:    "unsupported version string",
+			spec:    &ibctest.ChainSpec{Name: "gaia", Version: "gaia:v7.0.1"},
+			wantErr: `version "gaia:v7.0.1" is not a valid docker tag`,
+		},
+		{
+			name:    "unsupported polkadot version string",
+			spec:    &ibctest.ChainSpec{Name: "composable", Version: "polkadot:v0.9.19"},
+			wantErr: "should be comma separated polkadot:version,composable:version",
+		},
+		{
+			name: "missing image repository",
+			spec: &ibctest.ChainSpec{Name: "gaia", Version: "v7.0.1", ChainConfig: ibc.ChainConfig{
+				Images: []ibc.DockerImage{{UidGid: "1025:1025"}},
+			}},
+			wantErr: "image repository must not be empty",
+		},
+		{
+			name: "bad uid:gid",
+			spec: &ibctest.ChainSpec{Name: "gaia", Version: "v7.0.1", ChainConfig: ibc.ChainConfig{
+				Images: []ibc.DockerImage{{Repository: "ghcr.io/strangelove-ventures/heighliner/gaia", UidGid: "heighliner"}},
+			}},
+			wantErr: `uid-gid "heighliner" must be of the form uid:gid`,
+		},
+		{
+			name:    "no validators",
+			spec:    &ibctest.ChainSpec{Name: "gaia", Version: "v7.0.1", NumValidators: intPtr(0)},
+			wantErr: "NumValidators must be at least 1",
+		},
+		{
+			name:    "more polkadot validators than dev accounts",
+			spec:    &ibctest.ChainSpec{Name: "composable", Version: "polkadot:v0.9.19,composable:centauri", NumValidators: intPtr(6)},
+			wantErr: "relay chain (NumValidators): 6 nodes requested, but at most 5 are supported",
+		},
+		{
+			name:    "more parachain nodes than dev accounts",
+			spec:    &ibctest.ChainSpec{Name: "composable", Version: "polkadot:v0.9.19,composable:centauri", NumFullNodes: intPtr(6)},
+			wantErr: "parachain (NumFullNodes): 6 nodes requested",
+		},
+		{
+			name: "invalid parachain",
+			spec: &ibctest.ChainSpec{Name: "composable", Version: "polkadot:v0.9.19", Parachains: []polkadot.ParachainConfig{
+				{ChainID: "dali-dev", Bin: "composable-node", NumNodes: 1},
+			}},
+			wantErr: "parachain dali-dev: image repository must not be empty",
+		},
+		{
+			name: "parachains of a cosmos chain",
+			spec: &ibctest.ChainSpec{Name: "gaia", Version: "v7.0.1", Parachains: []polkadot.ParachainConfig{
+				{ChainID: "dali-dev"},
+			}},
+			wantErr: "cannot have parachains",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.spec.Validate(zaptest.NewLogger(t)), tt.wantErr)
+		})
+	}
+
+	t.Run("factory validates specs", func(t *testing.T) {
+		cf := ibctest.NewBuiltinChainFactory(zaptest.NewLogger(t), []*ibctest.ChainSpec{
+			{Name: "gaia", Version: "v7.0.1", NumValidators: intPtr(0)},
+		})
+		_, err := cf.Chains(t.Name())
+		require.ErrorContains(t, err, "failed to build chain config gaia: chain")
+		require.ErrorContains(t, err, "NumValidators must be at least 1")
+	})
+}
@@ -1,12 +1,14 @@
 package ibctest
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"time"
 
 	"github.com/strangelove-ventures/ibctest/v6"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -17,8 +19,19 @@ type mainFlags struct {
 	LogFormat         string
 	LogLevel          string
 	MatrixFile        string
+	TopologyFile      string
 	ReportFile        string
 	BlockDatabaseFile string
+	Progress          bool
+}
+
+// ProgressContext returns ctx carrying a progress bar of chain and relayer setup on stderr,
+// if the progress flag is set, or else ctx itself.
+func (f mainFlags) ProgressContext(ctx context.Context) context.Context {
+	if !f.Progress {
+		return ctx
+	}
+	return testreporter.WithProgress(ctx, testreporter.ProgressBar(os.Stderr))
 }
 
 func (f mainFlags) Logger() (lc LoggerCloser, _ error) {
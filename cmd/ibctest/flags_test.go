@@ -3,7 +3,9 @@ package ibctest
 import (
 	"testing"
 
+	"github.com/strangelove-ventures/ibctest/v6/relayer"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestMainFlags_Logger(t *testing.T) {
@@ -22,3 +24,17 @@ func TestMainFlags_Logger(t *testing.T) {
 		require.NotEmpty(t, logger.FilePath)
 	}
 }
+
+func TestGetRelayerFactory_Version(t *testing.T) {
+	nop := zap.NewNop()
+
+	rf, err := getRelayerFactory("rly@v2.0.0", nop)
+	require.NoError(t, err)
+	// rly v2.0.0 predates extension options, so the pinned version is honored.
+	require.False(t, rf.Capabilities()[relayer.ExtensionOptions])
+
+	for _, name := range []string{"hermes@v1.0.0", "rly@", "hermes@"} {
+		_, err := getRelayerFactory(name, nop)
+		require.Errorf(t, err, "relayer %s", name)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,7 +21,9 @@ import (
 	blockdbtui "github.com/strangelove-ventures/ibctest/v6/internal/blockdb/tui"
 	"github.com/strangelove-ventures/ibctest/v6/internal/version"
 	"github.com/strangelove-ventures/ibctest/v6/relayer"
+	"github.com/strangelove-ventures/ibctest/v6/relayer/rly"
 	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -46,10 +49,26 @@ var testMatrix struct {
 	Relayers []string
 
 	ChainSets [][]*ibctest.ChainSpec
+
+	// Optional version skew profile, run by TestVersionSkew.
+	VersionSkew []versionSkewEntry
+}
+
+// versionSkewEntry is one documented relayer and chain set compatibility pairing.
+type versionSkewEntry struct {
+	// Relayer name, optionally pinned to a version, e.g. "rly@v2.0.0".
+	Relayer string
+
+	ChainSet []*ibctest.ChainSpec
+
+	Compatible bool
 }
 
 var debugFlagSet = flag.NewFlagSet("debug", flag.ExitOnError)
 
+// The topology loaded from the file referenced by the topology flag, run by TestTopology.
+var testTopology *ibctest.Topology
+
 func TestMain(m *testing.M) {
 	rand.Seed(time.Now().UnixNano())
 	addFlags()
@@ -79,6 +98,11 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 
+	if err := setUpTestTopology(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load topology: %v\n", err)
+		os.Exit(1)
+	}
+
 	if err := configureTestReporter(); err != nil {
 		fmt.Fprintf(os.Stderr, "Failure configuring test reporter: %v\n", err)
 		os.Exit(1)
@@ -128,6 +152,22 @@ func setUpTestMatrix() error {
 	return nil
 }
 
+// setUpTestTopology populates the testTopology singleton
+// with the topology file referenced by the topology flag, if any.
+func setUpTestTopology() error {
+	if extraFlags.TopologyFile == "" {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Loading topology file from %s\n", extraFlags.TopologyFile)
+	tp, err := ibctest.LoadTopology(extraFlags.TopologyFile)
+	if err != nil {
+		return err
+	}
+	testTopology = tp
+	return nil
+}
+
 func validateTestMatrix() error {
 	nop := zap.NewNop()
 	for _, r := range testMatrix.Relayers {
@@ -142,17 +182,26 @@ func validateTestMatrix() error {
 		}
 	}
 
+	for i, e := range testMatrix.VersionSkew {
+		if _, err := getRelayerFactory(e.Relayer, nop); err != nil {
+			return fmt.Errorf("version skew entry %d: %w", i, err)
+		}
+		if _, err := getChainFactory(nop, e.ChainSet); err != nil {
+			return fmt.Errorf("version skew entry %d: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
 var reporter *testreporter.Reporter
 
 func configureTestReporter() error {
-	home, err := os.UserHomeDir()
+	dir, err := ibctest.ArtifactDir()
 	if err != nil {
-		return fmt.Errorf("failed to get user home dir: %w", err)
+		return fmt.Errorf("failed to get artifact dir: %w", err)
 	}
-	fpath := filepath.Join(home, ".ibctest", "reports")
+	fpath := filepath.Join(dir, "reports")
 	err = os.MkdirAll(fpath, 0755)
 	if err != nil {
 		return fmt.Errorf("mkdirall: %w", err)
@@ -169,14 +218,36 @@ func configureTestReporter() error {
 	return nil
 }
 
+// getRelayerFactory returns the relayer factory for name,
+// which may be pinned to a specific version with an "@version" suffix, e.g. "rly@v2.0.0".
+// Only rly honors a version; a version for any other relayer is an error rather than silently ignored.
 func getRelayerFactory(name string, logger *zap.Logger) (ibctest.RelayerFactory, error) {
+	name, version, pinned := strings.Cut(name, "@")
+	if pinned && version == "" {
+		return nil, fmt.Errorf("relayer %q has an empty version after @", name)
+	}
 	switch name {
 	case "rly", "cosmos/relayer":
-		return ibctest.NewBuiltinRelayerFactory(ibc.CosmosRly, logger, relayer.StartupFlags("-b", "100")), nil
+		opts := []relayer.RelayerOption{relayer.StartupFlags("-b", "100")}
+		if version != "" {
+			opts = append(opts, relayer.CustomDockerImage(rly.DefaultContainerImage, version, rly.RlyDefaultUidGid))
+		}
+		return ibctest.NewBuiltinRelayerFactory(ibc.CosmosRly, logger, opts...), nil
 	case "hermes":
+		if version != "" {
+			return nil, fmt.Errorf("relayer %q cannot be pinned to a version", name)
+		}
 		return ibctest.NewBuiltinRelayerFactory(ibc.Hermes, logger), nil
 	default:
-		return nil, fmt.Errorf("unknown relayer type %q (valid types: rly, hermes)", name)
+		// Relayers registered through ibctest.RegisterRelayer.
+		impl, ok := ibctest.RelayerImplementationByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown relayer type %q (valid types: %s, hermes)", name, strings.Join(ibctest.RegisteredRelayerNames(), ", "))
+		}
+		if version != "" {
+			return nil, fmt.Errorf("relayer %q cannot be pinned to a version", name)
+		}
+		return ibctest.NewBuiltinRelayerFactory(impl, logger), nil
 	}
 }
 
@@ -194,7 +265,7 @@ func getChainFactory(log *zap.Logger, chainSpecs []*ibctest.ChainSpec) (ibctest.
 func TestConformance(t *testing.T) {
 	t.Parallel()
 
-	ctx := context.Background()
+	ctx := extraFlags.ProgressContext(context.Background())
 
 	logger, err := extraFlags.Logger()
 	if err != nil {
@@ -232,6 +303,82 @@ func TestConformance(t *testing.T) {
 	conformance.Test(t, ctx, chainFactories, relayerFactories, reporter)
 }
 
+// TestVersionSkew runs the optional version skew profile from the matrix file,
+// asserting that each documented pairing of relayer and chain versions is or is not compatible.
+func TestVersionSkew(t *testing.T) {
+	if len(testMatrix.VersionSkew) == 0 {
+		t.Skip("No version skew profile in matrix file")
+	}
+	t.Parallel()
+
+	ctx := extraFlags.ProgressContext(context.Background())
+
+	logger, err := extraFlags.Logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+	t.Logf("View chain and relayer logs at %s", logger.FilePath)
+
+	log := logger.Logger
+
+	cases := make([]conformance.VersionSkewCase, len(testMatrix.VersionSkew))
+	for i, e := range testMatrix.VersionSkew {
+		rf, err := getRelayerFactory(e.Relayer, log)
+		if err != nil {
+			// This error should have been validated before running tests.
+			panic(err)
+		}
+		cf, err := getChainFactory(log, e.ChainSet)
+		if err != nil {
+			// This error should have been validated before running tests.
+			panic(err)
+		}
+		cases[i] = conformance.VersionSkewCase{
+			ChainFactory:   cf,
+			RelayerFactory: rf,
+			Compatible:     e.Compatible,
+		}
+	}
+
+	conformance.TestVersionSkew(t, ctx, cases, reporter)
+}
+
+// TestTopology builds the chains, relayers, and links of the topology file
+// given with the topology flag, failing if any of them cannot be built or linked.
+func TestTopology(t *testing.T) {
+	if testTopology == nil {
+		t.Skip("No topology file provided")
+	}
+	t.Parallel()
+
+	ctx := extraFlags.ProgressContext(context.Background())
+
+	logger, err := extraFlags.Logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+	t.Logf("View chain and relayer logs at %s", logger.FilePath)
+
+	reporter.TrackTest(t)
+	req := require.New(reporter.TestifyT(t))
+
+	client, network := ibctest.DockerSetup(t)
+
+	bt, err := testTopology.Build(t, client, network, logger.Logger)
+	req.NoError(err, "failed to build topology")
+
+	ic := bt.Interchain.WithLog(logger.Logger)
+	t.Cleanup(func() { _ = ic.Close() })
+
+	req.NoError(ic.Build(ctx, reporter.RelayerExecReporter(t), ibctest.InterchainBuildOptions{
+		TestName:  t.Name(),
+		Client:    client,
+		NetworkID: network,
+	}), "failed to build interchain from topology")
+}
+
 // addFlags configures additional flags beyond the default testing flags.
 // Although pflag would have been slightly more developer friendly,
 // I ran out of time to spend on getting pflag to cooperate with the
@@ -239,10 +386,12 @@ func TestConformance(t *testing.T) {
 // We can revisit if necessary.
 func addFlags() {
 	flag.StringVar(&extraFlags.MatrixFile, "matrix", "", "Path to matrix file defining what configurations to test")
-	flag.StringVar(&extraFlags.LogFile, "log-file", "ibctest.log", "File to write chain and relayer logs. If a file name, logs written to $HOME/.ibctest/logs directory. Use 'stderr' or 'stdout' to print logs in line tests.")
+	flag.StringVar(&extraFlags.TopologyFile, "topology", "", "Path to YAML topology file of chains, relayers, and links to build, run by TestTopology")
+	flag.StringVar(&extraFlags.LogFile, "log-file", "ibctest.log", "File to write chain and relayer logs. If a file name, logs written to the logs directory of IBCTEST_ARTIFACT_DIR, $HOME/.ibctest/logs by default. Use 'stderr' or 'stdout' to print logs in line tests.")
 	flag.StringVar(&extraFlags.LogFormat, "log-format", "console", "Chain and relayer log format: console|json")
 	flag.StringVar(&extraFlags.LogLevel, "log-level", "info", "Chain and relayer log level: debug|info|error")
-	flag.StringVar(&extraFlags.ReportFile, "report-file", "", "Path where test report will be stored. Defaults to $HOME/.ibctest/reports/$TIMESTAMP.json")
+	flag.BoolVar(&extraFlags.Progress, "progress", false, "Render the progress of chain and relayer setup as a progress bar on stderr")
+	flag.StringVar(&extraFlags.ReportFile, "report-file", "", "Path where test report will be stored. Defaults to $IBCTEST_ARTIFACT_DIR/reports/$TIMESTAMP.json, with $HOME/.ibctest as the default artifact dir")
 
 	debugFlagSet.StringVar(&extraFlags.BlockDatabaseFile, "block-db", ibctest.DefaultBlockDatabaseFilepath(), "Path to database sqlite file that tracks blocks and transactions.")
 }
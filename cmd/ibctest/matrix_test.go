@@ -17,11 +17,18 @@ var (
 
 	//go:embed example_matrix_custom.json
 	exampleMatrixCustom string
+
+	//go:embed example_matrix_version_skew.json
+	exampleMatrixVersionSkew string
 )
 
 func TestMatrixValid(t *testing.T) {
 	type matrix struct {
 		ChainSets [][]*ibctest.ChainSpec
+
+		VersionSkew []struct {
+			ChainSet []*ibctest.ChainSpec
+		}
 	}
 
 	for _, tc := range []struct {
@@ -30,6 +37,7 @@ func TestMatrixValid(t *testing.T) {
 	}{
 		{name: "example_matrix.json", j: exampleMatrix},
 		{name: "example_matrix_custom.json", j: exampleMatrixCustom},
+		{name: "example_matrix_version_skew.json", j: exampleMatrixVersionSkew},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			var m matrix
@@ -41,6 +49,13 @@ func TestMatrixValid(t *testing.T) {
 					require.NoErrorf(t, err, "failed to generate config from chainset at index %d-%d", i, j)
 				}
 			}
+
+			for i, e := range m.VersionSkew {
+				for j, c := range e.ChainSet {
+					_, err := c.Config(zaptest.NewLogger(t))
+					require.NoErrorf(t, err, "failed to generate config from version skew entry at index %d-%d", i, j)
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,265 @@
+package ibctest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+)
+
+// Config is the global configuration of ibctest, shared by every test in the process.
+//
+// Every field can be initialized by setting an environment variable,
+// so that CI can tune ibctest without code changes.
+// Alternatively, importers of the ibctest package may call SetConfig, e.g. in TestMain,
+// or the setter of an individual field, such as SetImagePullMode.
+type Config struct {
+	// Directory under which log files, reports, and block databases are written.
+	// Initialized by IBCTEST_ARTIFACT_DIR; defaults to $HOME/.ibctest.
+	ArtifactDir string
+
+	// Whether docker volumes and temporary directories of failed tests are kept for inspection.
+	// Initialized by IBCTEST_SKIP_FAILURE_CLEANUP; see KeepDockerVolumesOnFailure and KeepTempDirOnFailure.
+	KeepOnFailure bool
+
+	// Image versions to use in place of the configured versions, keyed by image repository,
+	// for chains built by a BuiltinChainFactory.
+	// Initialized by IBCTEST_IMAGE_OVERRIDES, a comma-separated list of repository=version pairs,
+	// e.g. "ghcr.io/strangelove-ventures/heighliner/gaia=v8.0.0".
+	// If IBCTEST_IMAGE_OVERRIDES is invalid, BuiltinChainFactory.Chains returns an error
+	// and CurrentConfig reports no overrides, until SetConfig replaces them.
+	ImageOverrides map[string]string
+
+	// How images are pulled. Initialized by IBCTEST_IMAGE_PULL_MODE; see SetImagePullMode.
+	ImagePullMode ImagePullMode
+
+	// Log level of relayers built by the built-in relayer factory, e.g. "debug" or "info",
+	// unless set with relayer.Logging. Empty uses the relayer's default.
+	// Initialized by IBCTEST_LOG_LEVEL.
+	LogLevel string
+
+	// Maximum number of chains that Interchain.Build starts concurrently,
+	// unless set in InterchainBuildOptions. Zero starts all chains at once.
+	// Initialized by IBCTEST_MAX_CONCURRENT_CHAIN_STARTS.
+	// If IBCTEST_MAX_CONCURRENT_CHAIN_STARTS is invalid, Interchain.Build returns an error
+	// and CurrentConfig reports 0, until SetConfig replaces it.
+	MaxConcurrentChainStarts int
+
+	// Initialized by IBCTEST_MAX_CONTAINER_RESTARTS; see SetMaxContainerRestarts.
+	MaxContainerRestarts int
+
+	// Initialized by IBCTEST_STOP_GRACE_PERIOD; see SetStopGracePeriod.
+	// If IBCTEST_STOP_GRACE_PERIOD is invalid, CurrentConfig reports -1.
+	StopGracePeriod time.Duration
+
+	// In bytes. Initialized by IBCTEST_DISK_BUDGET_MB; see SetDiskBudget.
+	// If IBCTEST_DISK_BUDGET_MB is invalid, CurrentConfig reports -1.
+	DiskBudget int64
+
+	// Initialized by IBCTEST_PRUNE_FINISHED_VOLUMES; see PruneFinishedTestVolumes.
+	PruneFinishedTestVolumes bool
+
+	// Master seed that mnemonics of test users and relayer wallets are derived from, hex encoded,
+	// or empty for independently random mnemonics. SetConfig also accepts "random" to generate a new seed.
+	// Initialized by IBCTEST_MNEMONIC_SEED; see SetMnemonicSeed.
+	// If IBCTEST_MNEMONIC_SEED is invalid, DockerSetup fails the test and CurrentConfig reports an empty seed,
+	// until SetConfig or SetMnemonicSeed replaces it.
+	MnemonicSeed string
+
+	// Labels added to every docker resource created for tests.
+	// Initialized by IBCTEST_DOCKER_LABELS; see SetDockerLabels.
+	DockerLabels map[string]string
+
+	// Address of the debug server of built interchains, or empty for none.
+	// Initialized by IBCTEST_DEBUG_ADDR; see SetDebugServerAddr.
+	DebugServerAddr string
+}
+
+var (
+	configMu sync.RWMutex
+
+	// Settings of Config that are not held by the package they configure.
+	artifactDir                 = os.Getenv("IBCTEST_ARTIFACT_DIR")
+	imageOverridesEnv           = os.Getenv("IBCTEST_IMAGE_OVERRIDES")
+	relayerLogLevel             = os.Getenv("IBCTEST_LOG_LEVEL")
+	maxConcurrentChainStartsEnv = os.Getenv("IBCTEST_MAX_CONCURRENT_CHAIN_STARTS")
+
+	// Image overrides set by SetConfig, or nil to use imageOverridesEnv, which is parsed when used
+	// so that an invalid value fails the chains that would be built with it rather than the importing program.
+	imageOverrides map[string]string
+
+	// Maximum number of concurrent chain starts set by SetConfig, or nil to use maxConcurrentChainStartsEnv,
+	// which is parsed when used like imageOverridesEnv.
+	maxConcurrentChainStarts *int
+)
+
+// CurrentConfig returns the configuration in effect.
+func CurrentConfig() Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	current, _ := currentImageOverrides()
+	overrides := make(map[string]string, len(current))
+	for repo, version := range current {
+		overrides[repo] = version
+	}
+	maxStarts, _ := currentMaxConcurrentChainStarts()
+	labels := dockerutil.ResourceLabels()
+	return Config{
+		ArtifactDir:              artifactDir,
+		KeepOnFailure:            dockerutil.KeepVolumesOnFailure,
+		ImageOverrides:           overrides,
+		ImagePullMode:            dockerutil.ImagePullMode,
+		LogLevel:                 relayerLogLevel,
+		MaxConcurrentChainStarts: maxStarts,
+		MaxContainerRestarts:     dockerutil.MaxContainerRestarts,
+		StopGracePeriod:          dockerutil.StopGracePeriod,
+		DiskBudget:               dockerutil.DiskBudget,
+		PruneFinishedTestVolumes: dockerutil.PruneFinishedTestVolumes,
+		MnemonicSeed:             MnemonicSeed(),
+		DockerLabels:             labels,
+		DebugServerAddr:          debugServerAddr,
+	}
+}
+
+// SetConfig replaces the configuration in effect.
+// To change only some settings, modify the value returned by CurrentConfig.
+// Like the individual setters, SetConfig should be called before tests start.
+// It returns an error without changing the configuration if cfg has an invalid MnemonicSeed or DockerLabels,
+// or a negative MaxConcurrentChainStarts.
+func SetConfig(cfg Config) error {
+	seed, err := parseMnemonicSeed(cfg.MnemonicSeed)
+	if err != nil {
+		return err
+	}
+	if cfg.MaxConcurrentChainStarts < 0 {
+		return fmt.Errorf("invalid max concurrent chain starts %d: must not be negative", cfg.MaxConcurrentChainStarts)
+	}
+	for k := range cfg.DockerLabels {
+		if err := dockerutil.ValidateResourceLabel(k); err != nil {
+			return err
+		}
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	artifactDir = cfg.ArtifactDir
+	imageOverrides = make(map[string]string, len(cfg.ImageOverrides))
+	for repo, version := range cfg.ImageOverrides {
+		imageOverrides[repo] = version
+	}
+	relayerLogLevel = cfg.LogLevel
+	maxStarts := cfg.MaxConcurrentChainStarts
+	maxConcurrentChainStarts = &maxStarts
+
+	KeepDockerVolumesOnFailure(cfg.KeepOnFailure)
+	KeepTempDirOnFailure(cfg.KeepOnFailure)
+	SetImagePullMode(cfg.ImagePullMode)
+	SetMaxContainerRestarts(cfg.MaxContainerRestarts)
+	SetStopGracePeriod(cfg.StopGracePeriod)
+	SetDiskBudget(cfg.DiskBudget)
+	PruneFinishedTestVolumes(cfg.PruneFinishedTestVolumes)
+	if cfg.MnemonicSeed != MnemonicSeed() || mnemonicSeedError() != nil {
+		// Keep the derivation counts of an unchanged seed, so that mnemonics derived later stay distinct.
+		setMnemonicSeed(seed)
+	}
+	setDebugServerAddr(cfg.DebugServerAddr)
+	return SetDockerLabels(cfg.DockerLabels)
+}
+
+// ArtifactDir returns the directory under which log files, reports, and block databases are written,
+// set by Config.ArtifactDir or else $HOME/.ibctest.
+func ArtifactDir() (string, error) {
+	configMu.RLock()
+	dir := artifactDir
+	configMu.RUnlock()
+	if dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("user home dir: %w", err)
+	}
+	return filepath.Join(home, ".ibctest"), nil
+}
+
+// applyImageOverrides replaces the versions of cfg's images that have a configured override.
+func applyImageOverrides(cfg *ibc.ChainConfig) error {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	overrides, err := currentImageOverrides()
+	if err != nil {
+		return err
+	}
+	for i, image := range cfg.Images {
+		if version, ok := overrides[image.Repository]; ok {
+			cfg.Images[i] = image.WithVersion(version)
+		}
+	}
+	return nil
+}
+
+// currentImageOverrides returns the image overrides set by SetConfig, or else parsed from IBCTEST_IMAGE_OVERRIDES.
+// configMu must be held.
+func currentImageOverrides() (map[string]string, error) {
+	if imageOverrides != nil {
+		return imageOverrides, nil
+	}
+	overrides, err := parseImageOverrides(imageOverridesEnv)
+	if err != nil {
+		return nil, fmt.Errorf("IBCTEST_IMAGE_OVERRIDES: %w", err)
+	}
+	return overrides, nil
+}
+
+// configuredMaxConcurrentChainStarts returns the maximum number of chains that Interchain.Build starts concurrently,
+// or 0 for no limit.
+func configuredMaxConcurrentChainStarts() (int, error) {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return currentMaxConcurrentChainStarts()
+}
+
+// currentMaxConcurrentChainStarts returns the maximum number of concurrent chain starts set by SetConfig,
+// or else parsed from IBCTEST_MAX_CONCURRENT_CHAIN_STARTS.
+// configMu must be held.
+func currentMaxConcurrentChainStarts() (int, error) {
+	if maxConcurrentChainStarts != nil {
+		return *maxConcurrentChainStarts, nil
+	}
+	if maxConcurrentChainStartsEnv == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(maxConcurrentChainStartsEnv)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf(
+			"IBCTEST_MAX_CONCURRENT_CHAIN_STARTS: invalid value %q: must be a non-negative integer",
+			maxConcurrentChainStartsEnv,
+		)
+	}
+	return n, nil
+}
+
+func parseImageOverrides(s string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		repo, version, ok := strings.Cut(pair, "=")
+		if !ok || repo == "" || version == "" {
+			return nil, fmt.Errorf("invalid image override %q: must be of the form repository=version", pair)
+		}
+		overrides[repo] = version
+	}
+	return overrides, nil
+}
@@ -0,0 +1,134 @@
+package ibctest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImageOverrides(t *testing.T) {
+	t.Parallel()
+
+	overrides, err := parseImageOverrides("")
+	require.NoError(t, err)
+	require.Empty(t, overrides)
+
+	overrides, err = parseImageOverrides("ghcr.io/org/gaia=v8.0.0, ghcr.io/org/osmosis=v12.0.0")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"ghcr.io/org/gaia":    "v8.0.0",
+		"ghcr.io/org/osmosis": "v12.0.0",
+	}, overrides)
+
+	_, err = parseImageOverrides("ghcr.io/org/gaia")
+	require.Error(t, err)
+}
+
+// Not parallel, because the configuration is global.
+func TestSetConfig(t *testing.T) {
+	orig := CurrentConfig()
+	t.Cleanup(func() { require.NoError(t, SetConfig(orig)) })
+
+	cfg := orig
+	cfg.ArtifactDir = t.TempDir()
+	cfg.ImageOverrides = map[string]string{"ghcr.io/org/gaia": "v8.0.0"}
+	cfg.MaxConcurrentChainStarts = 2
+	cfg.StopGracePeriod = time.Minute
+	cfg.MnemonicSeed = "00ff"
+	cfg.DockerLabels = map[string]string{"team": "ibc"}
+	cfg.DebugServerAddr = "localhost:0"
+	require.NoError(t, SetConfig(cfg))
+
+	require.Equal(t, cfg, CurrentConfig())
+	require.Equal(t, "00ff", MnemonicSeed())
+
+	dir, err := ArtifactDir()
+	require.NoError(t, err)
+	require.Equal(t, cfg.ArtifactDir, dir)
+
+	chainCfg := ibc.ChainConfig{Images: []ibc.DockerImage{
+		{Repository: "ghcr.io/org/gaia", Version: "v7.0.0"},
+		{Repository: "ghcr.io/org/other", Version: "v1.0.0"},
+	}}
+	require.NoError(t, applyImageOverrides(&chainCfg))
+	require.Equal(t, "v8.0.0", chainCfg.Images[0].Version)
+	require.Equal(t, "v1.0.0", chainCfg.Images[1].Version)
+
+	// Invalid settings are rejected without changing the configuration.
+	bad := cfg
+	bad.ArtifactDir = t.TempDir()
+	bad.MnemonicSeed = "not hex"
+	require.ErrorContains(t, SetConfig(bad), "invalid mnemonic seed")
+	bad.MnemonicSeed = ""
+	bad.DockerLabels = map[string]string{"": "x"}
+	require.ErrorContains(t, SetConfig(bad), "empty docker label key")
+	require.Equal(t, cfg, CurrentConfig())
+
+	// A random seed is generated and reported as hex.
+	random := cfg
+	random.MnemonicSeed = "random"
+	require.NoError(t, SetConfig(random))
+	require.Len(t, CurrentConfig().MnemonicSeed, 64)
+}
+
+// Not parallel, because the configuration is global.
+func TestApplyImageOverrides_InvalidEnv(t *testing.T) {
+	configMu.Lock()
+	origOverrides, origEnv := imageOverrides, imageOverridesEnv
+	imageOverrides, imageOverridesEnv = nil, "ghcr.io/org/gaia"
+	configMu.Unlock()
+	t.Cleanup(func() {
+		configMu.Lock()
+		imageOverrides, imageOverridesEnv = origOverrides, origEnv
+		configMu.Unlock()
+	})
+
+	chainCfg := ibc.ChainConfig{Images: []ibc.DockerImage{{Repository: "ghcr.io/org/gaia", Version: "v7.0.0"}}}
+	require.EqualError(t, applyImageOverrides(&chainCfg),
+		`IBCTEST_IMAGE_OVERRIDES: invalid image override "ghcr.io/org/gaia": must be of the form repository=version`)
+	require.Empty(t, CurrentConfig().ImageOverrides)
+
+	// Overrides set by SetConfig replace the invalid environment variable.
+	cfg := CurrentConfig()
+	cfg.ImageOverrides = map[string]string{"ghcr.io/org/gaia": "v8.0.0"}
+	require.NoError(t, SetConfig(cfg))
+	require.NoError(t, applyImageOverrides(&chainCfg))
+	require.Equal(t, "v8.0.0", chainCfg.Images[0].Version)
+}
+
+// Not parallel, because the configuration is global.
+func TestConfiguredMaxConcurrentChainStarts_InvalidEnv(t *testing.T) {
+	configMu.Lock()
+	origStarts, origEnv := maxConcurrentChainStarts, maxConcurrentChainStartsEnv
+	maxConcurrentChainStarts = nil
+	configMu.Unlock()
+	t.Cleanup(func() {
+		configMu.Lock()
+		maxConcurrentChainStarts, maxConcurrentChainStartsEnv = origStarts, origEnv
+		configMu.Unlock()
+	})
+
+	for _, s := range []string{"two", "-1"} {
+		configMu.Lock()
+		maxConcurrentChainStartsEnv = s
+		configMu.Unlock()
+
+		_, err := configuredMaxConcurrentChainStarts()
+		require.EqualError(t, err,
+			`IBCTEST_MAX_CONCURRENT_CHAIN_STARTS: invalid value "`+s+`": must be a non-negative integer`)
+		require.Zero(t, CurrentConfig().MaxConcurrentChainStarts)
+	}
+
+	// A limit set by SetConfig replaces the invalid environment variable.
+	cfg := CurrentConfig()
+	cfg.MaxConcurrentChainStarts = 2
+	require.NoError(t, SetConfig(cfg))
+	n, err := configuredMaxConcurrentChainStarts()
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	cfg.MaxConcurrentChainStarts = -1
+	require.ErrorContains(t, SetConfig(cfg), "invalid max concurrent chain starts -1")
+}
@@ -0,0 +1,125 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types"
+	chantypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
+	"github.com/strangelove-ventures/ibctest/v6"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/relayer"
+	"github.com/strangelove-ventures/ibctest/v6/test"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelCloseReopen closes the transfer channel between two chains,
+// checks that transfers over the closed channel fail,
+// and then opens a new channel on the same connection and relays a transfer over it.
+func TestChannelCloseReopen(t *testing.T, ctx context.Context, cf ibctest.ChainFactory, rf ibctest.RelayerFactory, rep *testreporter.Reporter) {
+	rep.TrackTest(t)
+
+	requireCapabilities(t, rep, rf, relayer.ChannelClose, relayer.FlushPackets, relayer.FlushAcknowledgements)
+
+	client, network := ibctest.DockerSetup(t)
+
+	req := require.New(rep.TestifyT(t))
+	chains, err := cf.Chains(t.Name())
+	req.NoError(err, "failed to get chains")
+
+	if len(chains) != 2 {
+		panic(fmt.Errorf("expected 2 chains, got %d", len(chains)))
+	}
+
+	requireChainFeatures(t, rep, chains, ibc.FeatureTransfer, ibc.FeatureChannelClose)
+
+	c0, c1 := chains[0], chains[1]
+
+	r := rf.Build(t, client, network)
+	closer, ok := r.(ibc.ChannelCloser)
+	req.True(ok, "relayer with the channel close capability must implement ibc.ChannelCloser")
+
+	const pathName = "p"
+	ic := ibctest.NewInterchain().
+		AddChain(c0).
+		AddChain(c1).
+		AddRelayer(r, "r").
+		AddLink(ibctest.InterchainLink{
+			Chain1:  c0,
+			Chain2:  c1,
+			Relayer: r,
+
+			Path:              pathName,
+			CreateChannelOpts: ibc.DefaultChannelOpts(),
+		})
+
+	eRep := rep.RelayerExecReporter(t)
+
+	req.NoError(ic.Build(ctx, eRep, ibctest.InterchainBuildOptions{
+		TestName:  t.Name(),
+		Client:    client,
+		NetworkID: network,
+	}))
+	defer ic.Close()
+
+	c1FaucetAddrBytes, err := c1.GetAddress(ctx, ibctest.FaucetAccountKeyName)
+	req.NoError(err)
+	c1FaucetAddr, err := types.Bech32ifyAddressBytes(c1.Config().Bech32Prefix, c1FaucetAddrBytes)
+	req.NoError(err)
+
+	transfer := ibc.WalletAmount{
+		Address: c1FaucetAddr,
+		Denom:   c0.Config().Denom,
+		Amount:  testCoinAmount,
+	}
+
+	channels, err := r.GetChannels(ctx, eRep, c0.Config().ChainID)
+	req.NoError(err)
+	req.Len(channels, 1)
+	closed := channels[0]
+
+	req.NoError(closer.CloseChannel(ctx, eRep, pathName, closed.ChannelID, closed.PortID))
+
+	// Both ends of the channel must be closed.
+	for _, c := range chains {
+		channels, err := r.GetChannels(ctx, eRep, c.Config().ChainID)
+		req.NoError(err)
+		req.Len(channels, 1)
+		req.Equal(chantypes.CLOSED.String(), channels[0].State, "channel %s on chain %s", channels[0].ChannelID, c.Config().ChainID)
+	}
+
+	_, err = c0.SendIBCTransfer(ctx, closed.ChannelID, ibctest.FaucetAccountKeyName, transfer, nil)
+	// ibc-go rejects packets on a closed channel with "channel is CLOSED (got STATE_CLOSED): invalid channel state".
+	req.ErrorContains(err, chantypes.ErrInvalidChannelState.Error(), "transfer over closed channel %s", closed.ChannelID)
+
+	req.NoError(r.CreateChannel(ctx, eRep, pathName, ibc.DefaultChannelOpts()))
+
+	channels, err = r.GetChannels(ctx, eRep, c0.Config().ChainID)
+	req.NoError(err)
+	req.Len(channels, 2)
+	reopened := channels[1]
+	if reopened.ChannelID == closed.ChannelID {
+		reopened = channels[0]
+	}
+	req.Equal(chantypes.OPEN.String(), reopened.State)
+	req.Equal(closed.ConnectionHops, reopened.ConnectionHops, "new channel must be on the connection of the closed channel")
+
+	beforeTransferHeight, err := c0.Height(ctx)
+	req.NoError(err)
+
+	tx, err := c0.SendIBCTransfer(ctx, reopened.ChannelID, ibctest.FaucetAccountKeyName, transfer, nil)
+	req.NoError(err)
+	req.NoError(tx.Validate())
+
+	req.NoError(r.FlushPackets(ctx, eRep, pathName, reopened.ChannelID))
+	req.NoError(test.WaitForBlocks(ctx, 3, c0, c1))
+	req.NoError(r.FlushAcknowledgements(ctx, eRep, pathName, reopened.ChannelID))
+
+	afterFlushHeight, err := c0.Height(ctx)
+	req.NoError(err)
+
+	_, err = test.PollForAck(ctx, c0, beforeTransferHeight, afterFlushHeight+2, tx.Packet)
+	req.NoError(err)
+}
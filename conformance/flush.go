@@ -31,6 +31,8 @@ func TestRelayerFlushing(t *testing.T, ctx context.Context, cf ibctest.ChainFact
 		panic(fmt.Errorf("expected 2 chains, got %d", len(chains)))
 	}
 
+	requireChainFeatures(t, rep, chains, ibc.FeatureTransfer)
+
 	c0, c1 := chains[0], chains[1]
 
 	r := rf.Build(t, client, network)
@@ -74,7 +76,7 @@ func TestRelayerFlushing(t *testing.T, ctx context.Context, cf ibctest.ChainFact
 	beforeTransferHeight, err := c0.Height(ctx)
 	req.NoError(err)
 
-	const txAmount = 112233 // Arbitrary amount that is easy to find in logs.
+	txAmount := types.NewInt(112233) // Arbitrary amount that is easy to find in logs.
 	tx, err := c0.SendIBCTransfer(ctx, c0ChannelID, ibctest.FaucetAccountKeyName, ibc.WalletAmount{
 		Address: c1FaucetAddr,
 		Denom:   c0.Config().Denom,
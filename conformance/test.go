@@ -35,6 +35,7 @@ import (
 	"testing"
 	"time"
 
+	"cosmossdk.io/math"
 	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
 	"github.com/docker/docker/client"
 	"github.com/strangelove-ventures/ibctest/v6"
@@ -48,12 +49,13 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-const (
-	userFaucetFund = int64(10_000_000_000)
-	testCoinAmount = int64(1_000_000)
-	pollHeightMax  = uint64(50)
+var (
+	userFaucetFund = math.NewInt(10_000_000_000)
+	testCoinAmount = math.NewInt(1_000_000)
 )
 
+const pollHeightMax = uint64(50)
+
 type TxCache struct {
 	Src []ibc.Tx
 	Dst []ibc.Tx
@@ -71,6 +73,8 @@ type RelayerTestCaseConfig struct {
 	Name string
 	// which relayer capabilities are required to run this test
 	RequiredRelayerCapabilities []relayer.Capability
+	// which features both chains must support to run this test
+	RequiredChainFeatures []ibc.ChainFeature
 	// function to run after the chains are started but before the relayer is started
 	// e.g. send a transfer and wait for it to timeout so that the relayer will handle it once it is timed out
 	PreRelayerStart func(context.Context, *testing.T, *RelayerTestCase, ibc.Chain, ibc.Chain, []ibc.ChannelOutput)
@@ -83,19 +87,22 @@ type RelayerTestCaseConfig struct {
 
 var relayerTestCaseConfigs = [...]RelayerTestCaseConfig{
 	{
-		Name:            "relay packet",
-		PreRelayerStart: preRelayerStart_RelayPacket,
-		Test:            testPacketRelaySuccess,
+		Name:                  "relay packet",
+		RequiredChainFeatures: []ibc.ChainFeature{ibc.FeatureTransfer},
+		PreRelayerStart:       preRelayerStart_RelayPacket,
+		Test:                  testPacketRelaySuccess,
 	},
 	{
-		Name:            "no timeout",
-		PreRelayerStart: preRelayerStart_NoTimeout,
-		Test:            testPacketRelaySuccess,
-		TestLabels:      []label.Test{label.Timeout},
+		Name:                  "no timeout",
+		RequiredChainFeatures: []ibc.ChainFeature{ibc.FeatureTransfer},
+		PreRelayerStart:       preRelayerStart_NoTimeout,
+		Test:                  testPacketRelaySuccess,
+		TestLabels:            []label.Test{label.Timeout},
 	},
 	{
 		Name:                        "height timeout",
 		RequiredRelayerCapabilities: []relayer.Capability{relayer.HeightTimeout},
+		RequiredChainFeatures:       []ibc.ChainFeature{ibc.FeatureTransfer, ibc.FeatureHeightTimeout},
 		PreRelayerStart:             preRelayerStart_HeightTimeout,
 		Test:                        testPacketRelayFail,
 		TestLabels:                  []label.Test{label.Timeout, label.HeightTimeout},
@@ -103,6 +110,7 @@ var relayerTestCaseConfigs = [...]RelayerTestCaseConfig{
 	{
 		Name:                        "timestamp timeout",
 		RequiredRelayerCapabilities: []relayer.Capability{relayer.TimestampTimeout},
+		RequiredChainFeatures:       []ibc.ChainFeature{ibc.FeatureTransfer, ibc.FeatureTimestampTimeout},
 		PreRelayerStart:             preRelayerStart_TimestampTimeout,
 		Test:                        testPacketRelayFail,
 		TestLabels:                  []label.Test{label.Timeout, label.TimestampTimeout},
@@ -205,6 +213,27 @@ func sendIBCTransfersFromBothChainsWithTimeout(
 	}
 }
 
+// requireChainFeatures tracks skipping t, if any of the chains does not support the required features.
+func requireChainFeatures(t *testing.T, rep *testreporter.Reporter, chains []ibc.Chain, reqFeatures ...ibc.ChainFeature) {
+	t.Helper()
+
+	for _, c := range chains {
+		if missing := ibc.MissingFeatures(c, reqFeatures...); len(missing) > 0 {
+			rep.TrackSkip(t, "skipping due to chain %s missing features %v", c.Config().Name, missing)
+		}
+	}
+}
+
+// missingChainFeatures reports whether any of the chains does not support the required features.
+func missingChainFeatures(chains []ibc.Chain, reqFeatures ...ibc.ChainFeature) bool {
+	for _, c := range chains {
+		if len(ibc.MissingFeatures(c, reqFeatures...)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Test is the stable API exposed by the conformance package.
 // This is intended to be used by Go unit tests.
 //
@@ -268,6 +297,13 @@ func Test(t *testing.T, ctx context.Context, cfs []ibctest.ChainFactory, rfs []i
 
 								TestRelayerFlushing(t, ctx, cf, rf, rep)
 							})
+
+							t.Run("channel close", func(t *testing.T) {
+								rep.TrackTest(t)
+								rep.TrackParallel(t)
+
+								TestChannelCloseReopen(t, ctx, cf, rf, rep)
+							})
 						})
 					}
 				})
@@ -284,6 +320,7 @@ func Test(t *testing.T, ctx context.Context, cfs []ibctest.ChainFactory, rfs []i
 // 2. Proper handling of no timeout from A -> B and B -> A.
 // 3. Proper handling of height timeout from A -> B and B -> A.
 // 4. Proper handling of timestamp timeout from A -> B and B -> A.
+// Tests depending on features that either chain does not support, per ibc.ChainCapabilities, are skipped.
 // If a non-nil relayerImpl is passed, it is assumed that the chains are already started.
 func TestChainPair(
 	t *testing.T,
@@ -296,6 +333,10 @@ func TestChainPair(
 	relayerImpl ibc.Relayer,
 	pathNames ...string,
 ) {
+	// Every test case sends transfers, so avoid starting chains that cannot.
+	chains := []ibc.Chain{srcChain, dstChain}
+	requireChainFeatures(t, rep, chains, ibc.FeatureTransfer)
+
 	req := require.New(rep.TestifyT(t))
 
 	var (
@@ -312,8 +353,9 @@ func TestChainPair(
 		}
 		testCases = append(testCases, &testCase)
 
-		if len(missingCapabilities(rf, testCaseConfig.RequiredRelayerCapabilities...)) > 0 {
-			// Do not add preRelayerStartFunc if capability missing.
+		if len(missingCapabilities(rf, testCaseConfig.RequiredRelayerCapabilities...)) > 0 ||
+			missingChainFeatures(chains, testCaseConfig.RequiredChainFeatures...) {
+			// Do not add preRelayerStartFunc if capability or feature missing.
 			// Adding all preRelayerStartFuncs appears to cause test pollution which is why this step is necessary.
 			continue
 		}
@@ -354,6 +396,7 @@ func TestChainPair(
 			t.Run(testCase.Config.Name, func(t *testing.T) {
 				rep.TrackTest(t, testCase.Config.TestLabels...)
 				requireCapabilities(t, rep, rf, testCase.Config.RequiredRelayerCapabilities...)
+				requireChainFeatures(t, rep, chains, testCase.Config.RequiredChainFeatures...)
 				rep.TrackParallel(t)
 				testCase.Config.Test(ctx, t, testCase, rep, srcChain, dstChain, channels)
 			})
@@ -411,7 +454,7 @@ func testPacketRelaySuccess(
 		t.Logf("Asserting %s to %s transfer", srcChainCfg.ChainID, dstChainCfg.ChainID)
 		// Assuming these values since the ibc transfers were sent in PreRelayerStart, so balances may have already changed by now
 		srcInitialBalance := userFaucetFund
-		dstInitialBalance := int64(0)
+		dstInitialBalance := math.ZeroInt()
 
 		srcAck, err := test.PollForAck(ctx, srcChain, srcTx.Height, srcTx.Height+pollHeightMax, srcTx.Packet)
 		req.NoError(err, "failed to get acknowledgement on source chain")
@@ -428,10 +471,10 @@ func testPacketRelaySuccess(
 		req.NoError(err, "failed to get balance from dest chain")
 
 		totalFees := srcChain.GetGasFeesInNativeDenom(srcTx.GasSpent)
-		expectedDifference := testCoinAmount + totalFees
+		expectedDifference := testCoinAmount.Add(totalFees)
 
-		req.Equal(srcInitialBalance-expectedDifference, srcFinalBalance)
-		req.Equal(dstInitialBalance+testCoinAmount, dstFinalBalance)
+		requireIntEqual(req, srcInitialBalance.Sub(expectedDifference), srcFinalBalance)
+		requireIntEqual(req, dstInitialBalance.Add(testCoinAmount), dstFinalBalance)
 	}
 
 	// [END] assert on source to destination transfer
@@ -442,7 +485,7 @@ func testPacketRelaySuccess(
 		dstUser := testCase.Users[1]
 		dstDenom := dstChainCfg.Denom
 		// Assuming these values since the ibc transfers were sent in PreRelayerStart, so balances may have already changed by now
-		srcInitialBalance := int64(0)
+		srcInitialBalance := math.ZeroInt()
 		dstInitialBalance := userFaucetFund
 
 		dstAck, err := test.PollForAck(ctx, dstChain, dstTx.Height, dstTx.Height+pollHeightMax, dstTx.Packet)
@@ -460,10 +503,10 @@ func testPacketRelaySuccess(
 		req.NoError(err, "failed to get balance from dest chain")
 
 		totalFees := dstChain.GetGasFeesInNativeDenom(dstTx.GasSpent)
-		expectedDifference := testCoinAmount + totalFees
+		expectedDifference := testCoinAmount.Add(totalFees)
 
-		req.Equal(srcInitialBalance+testCoinAmount, srcFinalBalance)
-		req.Equal(dstInitialBalance-expectedDifference, dstFinalBalance)
+		requireIntEqual(req, srcInitialBalance.Add(testCoinAmount), srcFinalBalance)
+		requireIntEqual(req, dstInitialBalance.Sub(expectedDifference), dstFinalBalance)
 	}
 	//[END] assert on destination to source transfer
 }
@@ -492,7 +535,7 @@ func testPacketRelayFail(
 	for i, srcTx := range testCase.TxCache.Src {
 		// Assuming these values since the ibc transfers were sent in PreRelayerStart, so balances may have already changed by now
 		srcInitialBalance := userFaucetFund
-		dstInitialBalance := int64(0)
+		dstInitialBalance := math.ZeroInt()
 
 		timeout, err := test.PollForTimeout(ctx, srcChain, srcTx.Height, srcTx.Height+pollHeightMax, srcTx.Packet)
 		req.NoError(err, "failed to get timeout packet on source chain")
@@ -514,15 +557,15 @@ func testPacketRelayFail(
 
 		totalFees := srcChain.GetGasFeesInNativeDenom(srcTx.GasSpent)
 
-		req.Equal(srcInitialBalance-totalFees, srcFinalBalance)
-		req.Equal(dstInitialBalance, dstFinalBalance)
+		requireIntEqual(req, srcInitialBalance.Sub(totalFees), srcFinalBalance)
+		requireIntEqual(req, dstInitialBalance, dstFinalBalance)
 	}
 	// [END] assert on source to destination transfer
 
 	// [BEGIN] assert on destination to source transfer
 	for i, dstTx := range testCase.TxCache.Dst {
 		// Assuming these values since the ibc transfers were sent in PreRelayerStart, so balances may have already changed by now
-		srcInitialBalance := int64(0)
+		srcInitialBalance := math.ZeroInt()
 		dstInitialBalance := userFaucetFund
 
 		timeout, err := test.PollForTimeout(ctx, dstChain, dstTx.Height, dstTx.Height+pollHeightMax, dstTx.Packet)
@@ -541,8 +584,15 @@ func testPacketRelayFail(
 
 		totalFees := dstChain.GetGasFeesInNativeDenom(dstTx.GasSpent)
 
-		req.Equal(srcInitialBalance, srcFinalBalance)
-		req.Equal(dstInitialBalance-totalFees, dstFinalBalance)
+		requireIntEqual(req, srcInitialBalance, srcFinalBalance)
+		requireIntEqual(req, dstInitialBalance.Sub(totalFees), dstFinalBalance)
 	}
 	// [END] assert on destination to source transfer
 }
+
+// requireIntEqual asserts that expected and actual are numerically equal.
+// Comparing math.Int values with req.Equal is unreliable,
+// because equal values may have different internal representations.
+func requireIntEqual(req *require.Assertions, expected, actual math.Int) {
+	req.Truef(expected.Equal(actual), "expected %s, actual %s", expected, actual)
+}
@@ -0,0 +1,87 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/client"
+	chainmock "github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/label"
+	"github.com/strangelove-ventures/ibctest/v6/relayer"
+	relayermock "github.com/strangelove-ventures/ibctest/v6/relayer/mock"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRelayerFactory builds mock relayers with the given capabilities.
+type mockRelayerFactory struct {
+	caps map[relayer.Capability]bool
+}
+
+func (f mockRelayerFactory) Build(t testing.TB, cli *client.Client, networkID string) ibc.Relayer {
+	return relayermock.NewRelayer()
+}
+
+func (f mockRelayerFactory) Name() string                              { return "mock" }
+func (f mockRelayerFactory) Labels() []label.Relayer                   { return []label.Relayer{label.Rly} }
+func (f mockRelayerFactory) Capabilities() map[relayer.Capability]bool { return f.caps }
+
+// featureChain is a mock chain supporting only the given features.
+type featureChain struct {
+	*chainmock.Chain
+
+	features []ibc.ChainFeature
+}
+
+func (c featureChain) SupportsFeature(f ibc.ChainFeature) bool {
+	for _, s := range c.features {
+		if s == f {
+			return true
+		}
+	}
+	return false
+}
+
+func newFeatureChain(chainID string, features ...ibc.ChainFeature) featureChain {
+	return featureChain{Chain: chainmock.NewChain(ibc.ChainConfig{Name: chainID, ChainID: chainID}), features: features}
+}
+
+func TestMissingCapabilities(t *testing.T) {
+	t.Parallel()
+
+	caps := relayer.FullCapabilities()
+	caps[relayer.TimestampTimeout] = false
+	rf := mockRelayerFactory{caps: caps}
+
+	require.Empty(t, missingCapabilities(rf, relayer.HeightTimeout, relayer.FlushPackets))
+	require.Equal(t, []relayer.Capability{relayer.TimestampTimeout}, missingCapabilities(rf, relayer.HeightTimeout, relayer.TimestampTimeout))
+}
+
+func TestMissingChainFeatures(t *testing.T) {
+	t.Parallel()
+
+	// Chains that do not report their features support every feature.
+	full := chainmock.NewChain(ibc.ChainConfig{ChainID: "a-1"})
+	transferOnly := newFeatureChain("b-1", ibc.FeatureTransfer)
+
+	require.False(t, missingChainFeatures([]ibc.Chain{full, transferOnly}, ibc.FeatureTransfer))
+	require.True(t, missingChainFeatures([]ibc.Chain{full, transferOnly}, ibc.FeatureTransfer, ibc.FeatureHeightTimeout))
+	require.False(t, missingChainFeatures([]ibc.Chain{full}, ibc.FeatureHeightTimeout, ibc.FeatureMemo))
+}
+
+func TestChainPair_SkipsChainsWithoutTransfers(t *testing.T) {
+	t.Parallel()
+
+	rep := testreporter.NewNopReporter()
+	src, dst := newFeatureChain("a-1"), newFeatureChain("b-1", ibc.FeatureTransfer)
+	rf := mockRelayerFactory{caps: relayer.FullCapabilities()}
+
+	var skipped bool
+	t.Run("pair", func(t *testing.T) {
+		defer func() { skipped = t.Skipped() }()
+		// Neither docker nor the relayer is used when the chains cannot send transfers.
+		TestChainPair(t, context.Background(), nil, "", src, dst, rf, rep, nil)
+	})
+	require.True(t, skipped)
+}
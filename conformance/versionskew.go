@@ -0,0 +1,79 @@
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/stretchr/testify/require"
+)
+
+// VersionSkewCase is one entry in a relayer and chain version compatibility matrix.
+type VersionSkewCase struct {
+	// The chains under test, typically at a version newer or older than the relayer was released against.
+	ChainFactory ibctest.ChainFactory
+
+	// The relayer under test, typically pinned to a specific version.
+	RelayerFactory ibctest.RelayerFactory
+
+	// Whether the relayer is documented to be compatible with the chains.
+	Compatible bool
+}
+
+// TestVersionSkew asserts the documented compatibility of each case.
+//
+// Compatible cases must pass the full TestChainPair suite.
+// Incompatible cases must fail to link the chains;
+// if the relayer links the chains successfully, the documented matrix is out of date and the test fails.
+func TestVersionSkew(t *testing.T, ctx context.Context, cases []VersionSkewCase, rep *testreporter.Reporter) {
+	for _, c := range cases {
+		c := c
+		if n := c.ChainFactory.Count(); n != 2 {
+			panic(fmt.Errorf("cannot accept chain factory with count=%d", n))
+		}
+
+		t.Run(c.RelayerFactory.Name()+"/"+c.ChainFactory.Name(), func(t *testing.T) {
+			rep.TrackParameters(t, c.RelayerFactory.Labels(), c.ChainFactory.Labels())
+			rep.TrackTest(t)
+			rep.TrackParallel(t)
+
+			chains, err := c.ChainFactory.Chains(t.Name())
+			require.NoError(rep.TestifyT(t), err, "failed to get chains")
+
+			client, network := ibctest.DockerSetup(t)
+
+			if c.Compatible {
+				TestChainPair(t, ctx, client, network, chains[0], chains[1], c.RelayerFactory, rep, nil)
+				return
+			}
+
+			r := c.RelayerFactory.Build(t, client, network)
+			ic := ibctest.NewInterchain().
+				AddChain(chains[0]).
+				AddChain(chains[1]).
+				AddRelayer(r, "r").
+				AddLink(ibctest.InterchainLink{
+					Chain1:  chains[0],
+					Chain2:  chains[1],
+					Relayer: r,
+
+					Path: "p",
+				})
+
+			err = ic.Build(ctx, rep.RelayerExecReporter(t), ibctest.InterchainBuildOptions{
+				TestName:  t.Name(),
+				Client:    client,
+				NetworkID: network,
+			})
+			defer ic.Close()
+
+			require.Error(
+				rep.TestifyT(t), err,
+				"relayer %s linked chains %s, but is documented as incompatible",
+				c.RelayerFactory.Name(), c.ChainFactory.Name(),
+			)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package ibctest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// deadlineCleanupReserve is the time before a test's deadline that is not given to any phase,
+// so that a phase timing out still leaves time to clean up containers and write reports.
+const deadlineCleanupReserve = 30 * time.Second
+
+// DeadlineTestingT is a subset of *testing.T to implement NewPhaseBudget.
+type DeadlineTestingT interface {
+	Deadline() (deadline time.Time, ok bool)
+}
+
+// Names of the phases of Interchain.Build, which it runs through InterchainBuildOptions.PhaseBudget.
+const (
+	// Initializing and starting the chains, until they are ready.
+	PhaseChainStart = "chain start"
+
+	// Creating the clients, connections and channels of the relayer paths.
+	PhaseChannelHandshake = "channel handshake"
+)
+
+// Phase is a named phase of a test, such as starting chains, the channel handshake, or sending transfers.
+type Phase struct {
+	Name string
+
+	// Share of the test's time budget given to the phase, relative to the weights of the other phases.
+	Weight int
+}
+
+// PhaseBudget divides the time left before a test's deadline across the test's phases,
+// so that a hung phase fails with an error naming it, e.g. "channel handshake exceeded 4m",
+// rather than the whole test being killed by go test's -timeout.
+//
+// Phases are budgeted when they are run, so time left over by a phase that finishes early
+// is shared by the phases that have not yet run.
+type PhaseBudget struct {
+	deadline    time.Time
+	hasDeadline bool
+
+	mu      sync.Mutex
+	phases  []Phase
+	started map[string]bool
+}
+
+// NewPhaseBudget returns a PhaseBudget dividing the time left before t's deadline,
+// less 30 seconds reserved for cleanup, across phases in proportion to their weights.
+// If t has no deadline, e.g. with go test -timeout 0, phases have no time limit.
+//
+// NewPhaseBudget panics if phases is empty, or if a phase has a duplicate name or a non-positive weight.
+func NewPhaseBudget(t DeadlineTestingT, phases ...Phase) *PhaseBudget {
+	if len(phases) == 0 {
+		panic(errors.New("no phases to budget"))
+	}
+	seen := make(map[string]bool, len(phases))
+	for _, p := range phases {
+		if seen[p.Name] {
+			panic(fmt.Errorf("duplicate phase %q", p.Name))
+		}
+		if p.Weight <= 0 {
+			panic(fmt.Errorf("phase %q must have a positive weight", p.Name))
+		}
+		seen[p.Name] = true
+	}
+
+	deadline, ok := t.Deadline()
+	return &PhaseBudget{
+		deadline:    deadline.Add(-deadlineCleanupReserve),
+		hasDeadline: ok,
+		phases:      phases,
+		started:     make(map[string]bool, len(phases)),
+	}
+}
+
+// Run runs fn with a context that expires once the named phase exceeds its budget.
+// If fn fails after its budget expired, Run returns a *PhaseTimeoutError.
+//
+// Each phase may only be run once. Run panics if name is not a phase of the budget or was already run.
+func (b *PhaseBudget) Run(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	budget, limited := b.start(name)
+	if !limited {
+		return fn(ctx)
+	}
+
+	phaseCtx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	err := fn(phaseCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(phaseCtx.Err(), context.DeadlineExceeded) {
+		return &PhaseTimeoutError{Phase: name, Budget: budget, Err: err}
+	}
+	return err
+}
+
+// runIfBudgeted runs fn through b.Run if b has the named phase, or else runs fn without a time limit.
+// b may be nil.
+func (b *PhaseBudget) runIfBudgeted(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	if b == nil || !b.has(name) {
+		return fn(ctx)
+	}
+	return b.Run(ctx, name, fn)
+}
+
+func (b *PhaseBudget) has(name string) bool {
+	for _, p := range b.phases {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// start marks the named phase as started, and returns its share of the time left before the deadline,
+// or false if there is no deadline.
+func (b *PhaseBudget) start(name string) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	weight, remaining := 0, 0
+	for _, p := range b.phases {
+		if p.Name == name {
+			weight = p.Weight
+		}
+		if !b.started[p.Name] {
+			remaining += p.Weight
+		}
+	}
+	if weight == 0 {
+		panic(fmt.Errorf("unknown phase %q", name))
+	}
+	if b.started[name] {
+		panic(fmt.Errorf("phase %q already run", name))
+	}
+	b.started[name] = true
+
+	if !b.hasDeadline {
+		return 0, false
+	}
+	left := time.Until(b.deadline)
+	if left < 0 {
+		left = 0
+	}
+	return time.Duration(int64(left) * int64(weight) / int64(remaining)), true
+}
+
+// PhaseTimeoutError is returned by PhaseBudget.Run when a phase exceeds its budget.
+type PhaseTimeoutError struct {
+	Phase  string
+	Budget time.Duration
+
+	// Error returned by the phase.
+	Err error
+}
+
+func (e *PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("%s exceeded %s: %v", e.Phase, formatBudget(e.Budget), e.Err)
+}
+
+func (e *PhaseTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// formatBudget formats d to the second, dropping zero seconds from whole minutes, e.g. "4m" rather than "4m0s".
+func formatBudget(d time.Duration) string {
+	if d >= time.Second {
+		d = d.Round(time.Second)
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	return s
+}
@@ -0,0 +1,131 @@
+package ibctest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6"
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+type deadlineT struct {
+	deadline time.Time
+	ok       bool
+}
+
+func (t deadlineT) Deadline() (time.Time, bool) {
+	return t.deadline, t.ok
+}
+
+func TestPhaseBudget(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	waitForDeadline := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	t.Run("phase timeout", func(t *testing.T) {
+		t.Parallel()
+
+		// Leave 400ms after the cleanup reserve, for phases weighted 1:3.
+		b := ibctest.NewPhaseBudget(
+			deadlineT{deadline: time.Now().Add(30*time.Second + 400*time.Millisecond), ok: true},
+			ibctest.Phase{Name: "chain start", Weight: 1},
+			ibctest.Phase{Name: "channel handshake", Weight: 3},
+		)
+
+		start := time.Now()
+		err := b.Run(ctx, "chain start", waitForDeadline)
+		elapsed := time.Since(start)
+		require.Less(t, elapsed, 300*time.Millisecond)
+
+		var timeoutErr *ibctest.PhaseTimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.Equal(t, "chain start", timeoutErr.Phase)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Regexp(t, `^chain start exceeded [\d.]+(ms|s): context deadline exceeded$`, err.Error())
+
+		// The remaining time goes to the remaining phase.
+		start = time.Now()
+		require.NoError(t, b.Run(ctx, "channel handshake", func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			require.Greater(t, deadline.Sub(start), elapsed)
+			return nil
+		}))
+
+		require.Panics(t, func() { _ = b.Run(ctx, "channel handshake", waitForDeadline) })
+		require.Panics(t, func() { _ = b.Run(ctx, "transfers", waitForDeadline) })
+	})
+
+	t.Run("no deadline", func(t *testing.T) {
+		t.Parallel()
+
+		b := ibctest.NewPhaseBudget(deadlineT{}, ibctest.Phase{Name: "transfers", Weight: 1})
+		require.NoError(t, b.Run(ctx, "transfers", func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			require.False(t, ok)
+			return nil
+		}))
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		t.Parallel()
+
+		b := ibctest.NewPhaseBudget(
+			deadlineT{deadline: time.Now().Add(time.Hour), ok: true},
+			ibctest.Phase{Name: "transfers", Weight: 1},
+		)
+		failed := errors.New("insufficient funds")
+		require.Equal(t, failed, b.Run(ctx, "transfers", func(context.Context) error { return failed }))
+	})
+
+	t.Run("invalid phases", func(t *testing.T) {
+		t.Parallel()
+
+		require.Panics(t, func() { ibctest.NewPhaseBudget(deadlineT{}) })
+		require.Panics(t, func() {
+			ibctest.NewPhaseBudget(deadlineT{}, ibctest.Phase{Name: "a", Weight: 1}, ibctest.Phase{Name: "a", Weight: 1})
+		})
+		require.Panics(t, func() { ibctest.NewPhaseBudget(deadlineT{}, ibctest.Phase{Name: "a"}) })
+	})
+}
+
+// hangingChain is a chain whose start never completes.
+type hangingChain struct {
+	*mock.Chain
+}
+
+func (c hangingChain) Start(testName string, ctx context.Context, additionalGenesisWallets ...ibc.WalletAmount) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestInterchain_BuildPhaseBudget(t *testing.T) {
+	t.Parallel()
+
+	chain := hangingChain{mock.NewChain(ibc.ChainConfig{Name: "gaia", ChainID: "gaia-1", Denom: "uatom"})}
+	ic := ibctest.NewInterchain().AddChain(chain)
+
+	// Leave 400ms after the cleanup reserve, for both phases.
+	b := ibctest.NewPhaseBudget(
+		deadlineT{deadline: time.Now().Add(30*time.Second + 400*time.Millisecond), ok: true},
+		ibctest.Phase{Name: ibctest.PhaseChainStart, Weight: 1},
+		ibctest.Phase{Name: ibctest.PhaseChannelHandshake, Weight: 1},
+	)
+	err := ic.Build(context.Background(), nil, ibctest.InterchainBuildOptions{
+		TestName:    t.Name(),
+		PhaseBudget: b,
+	})
+
+	var timeoutErr *ibctest.PhaseTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	require.Equal(t, ibctest.PhaseChainStart, timeoutErr.Phase)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
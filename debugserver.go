@@ -0,0 +1,302 @@
+package ibctest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/chain/polkadot"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"go.uber.org/zap"
+)
+
+// debugServerAddr is the address Interchain.Build serves the debug server on, if not empty.
+// It is guarded by configMu.
+var debugServerAddr = os.Getenv("IBCTEST_DEBUG_ADDR")
+
+// debugHeightTimeout bounds how long the debug server waits for the height of each chain.
+const debugHeightTimeout = 2 * time.Second
+
+// SetDebugServerAddr sets the address, such as "localhost:8765", of a debug HTTP server
+// that Interchain.Build starts once the chains are running, and Interchain.Close stops.
+// The server shows the chains, their host-mapped RPC endpoints and heights, the relayer paths,
+// and the latest relayer commands, which makes it easier to inspect a test paused, e.g., in a debugger.
+// A port of 0 picks a free port; the server's URL is logged by the interchain's logger and returned by DebugServerURL.
+//
+// The address is empty by default, so that no server is started, but can be initialized by setting the
+// environment variable IBCTEST_DEBUG_ADDR. The address applies to interchains built after the call.
+func SetDebugServerAddr(addr string) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	setDebugServerAddr(addr)
+}
+
+// setDebugServerAddr sets debugServerAddr. configMu must be held.
+func setDebugServerAddr(addr string) {
+	debugServerAddr = addr
+}
+
+// DebugTopology is the state of an interchain shown by its debug server.
+type DebugTopology struct {
+	TestName string         `json:"test_name"`
+	Chains   []DebugChain   `json:"chains"`
+	Relayers []DebugRelayer `json:"relayers"`
+}
+
+// DebugChain is a chain of a DebugTopology.
+type DebugChain struct {
+	ChainID string `json:"chain_id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+
+	// Addresses reachable from the host, and from containers in the docker network.
+	HostRPCAddress  string `json:"host_rpc_address"`
+	HostGRPCAddress string `json:"host_grpc_address"`
+	RPCAddress      string `json:"rpc_address"`
+	GRPCAddress     string `json:"grpc_address"`
+
+	// Current height of the chain, or why it could not be queried.
+	Height      uint64 `json:"height,omitempty"`
+	HeightError string `json:"height_error,omitempty"`
+
+	// Nodes of chains that expose the host endpoints of each node, such as polkadot chains.
+	Nodes []DebugNode `json:"nodes,omitempty"`
+}
+
+// DebugNode is a node of a DebugChain.
+type DebugNode struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+
+	HostRPCAddress string `json:"host_rpc_address"`
+	HostWSAddress  string `json:"host_ws_address"`
+
+	// URL of the polkadot.js apps explorer connected to the node.
+	PolkadotJSURL string `json:"polkadot_js_url,omitempty"`
+}
+
+// debugNodes returns the nodes of c, if c exposes the host endpoints of each node.
+func debugNodes(c ibc.Chain) []DebugNode {
+	pc, ok := c.(*polkadot.PolkadotChain)
+	if !ok {
+		return nil
+	}
+	var nodes []DebugNode
+	for _, e := range pc.NodeHostEndpoints() {
+		nodes = append(nodes, DebugNode{
+			Name:           e.Name,
+			Location:       e.Location.String(),
+			HostRPCAddress: e.RPC,
+			HostWSAddress:  e.WS,
+			PolkadotJSURL:  e.PolkadotJSURL(),
+		})
+	}
+	return nodes
+}
+
+// DebugRelayer is a relayer of a DebugTopology.
+type DebugRelayer struct {
+	Name  string      `json:"name"`
+	Paths []DebugPath `json:"paths"`
+}
+
+// DebugPath is a relayer path between two chains.
+type DebugPath struct {
+	Name     string    `json:"name"`
+	ChainIDs [2]string `json:"chain_ids"`
+}
+
+// DebugTopology returns the chains and relayer paths of ic, querying the height of each chain.
+func (ic *Interchain) DebugTopology(ctx context.Context) DebugTopology {
+	topo := DebugTopology{TestName: ic.testName}
+	for c, chainID := range ic.chains {
+		cfg := c.Config()
+		dc := DebugChain{
+			ChainID: chainID,
+			Name:    cfg.Name,
+			Type:    cfg.Type,
+		}
+		if ic.started {
+			dc.HostRPCAddress = c.GetHostRPCAddress()
+			dc.HostGRPCAddress = c.GetHostGRPCAddress()
+			dc.RPCAddress = c.GetRPCAddress()
+			dc.GRPCAddress = c.GetGRPCAddress()
+
+			heightCtx, cancel := context.WithTimeout(ctx, debugHeightTimeout)
+			h, err := c.Height(heightCtx)
+			cancel()
+			if err != nil {
+				dc.HeightError = err.Error()
+			} else {
+				dc.Height = h
+			}
+			dc.Nodes = debugNodes(c)
+		}
+		topo.Chains = append(topo.Chains, dc)
+	}
+	sort.Slice(topo.Chains, func(i, j int) bool { return topo.Chains[i].ChainID < topo.Chains[j].ChainID })
+
+	paths := make(map[ibc.Relayer][]DebugPath)
+	for rp, link := range ic.links {
+		paths[rp.Relayer] = append(paths[rp.Relayer], DebugPath{
+			Name:     ic.RelayerPath(rp.Relayer, rp.Path),
+			ChainIDs: [2]string{ic.chains[link.chains[0]], ic.chains[link.chains[1]]},
+		})
+	}
+	for r, name := range ic.relayers {
+		rps := paths[r]
+		sort.Slice(rps, func(i, j int) bool { return rps[i].Name < rps[j].Name })
+		topo.Relayers = append(topo.Relayers, DebugRelayer{Name: name, Paths: rps})
+	}
+	sort.Slice(topo.Relayers, func(i, j int) bool { return topo.Relayers[i].Name < topo.Relayers[j].Name })
+	return topo
+}
+
+// DebugHandler returns the handler of the debug server of ic, see SetDebugServerAddr,
+// e.g. to serve it from a server of the test itself.
+// The latest relayer commands are those tracked by rep, which may be nil.
+//
+// It serves an HTML overview at /, the DebugTopology at /topology.json,
+// and the latest relayer commands at /relayer-commands.json.
+func (ic *Interchain) DebugHandler(rep *testreporter.RelayerExecReporter) http.Handler {
+	recent := func() []testreporter.RelayerExecMessage {
+		if rep == nil {
+			return nil
+		}
+		return rep.RecentRelayerExecs()
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/topology.json", func(w http.ResponseWriter, req *http.Request) {
+		writeDebugJSON(w, ic.DebugTopology(req.Context()))
+	})
+	mux.HandleFunc("/relayer-commands.json", func(w http.ResponseWriter, req *http.Request) {
+		writeDebugJSON(w, recent())
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/" {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		err := debugPage.Execute(w, struct {
+			DebugTopology
+			Commands []testreporter.RelayerExecMessage
+		}{ic.DebugTopology(req.Context()), recent()})
+		if err != nil {
+			ic.log.Warn("Failed to render debug page", zap.Error(err))
+		}
+	})
+	return mux
+}
+
+func writeDebugJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// DebugServerURL returns the URL of the debug server started by Build, or an empty string if none was started.
+func (ic *Interchain) DebugServerURL() string {
+	if ic.debugListener == nil {
+		return ""
+	}
+	return "http://" + ic.debugListener.Addr().String()
+}
+
+// startDebugServer starts the debug server on debugServerAddr, if set.
+// Failing to start it is only logged, as it does not affect the test.
+func (ic *Interchain) startDebugServer(rep *testreporter.RelayerExecReporter) {
+	configMu.RLock()
+	addr := debugServerAddr
+	configMu.RUnlock()
+	if addr == "" {
+		return
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		ic.log.Warn("Failed to start debug server", zap.String("addr", addr), zap.Error(err))
+		return
+	}
+	ic.debugListener = l
+	ic.debugServer = &http.Server{Handler: ic.DebugHandler(rep), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := ic.debugServer.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			ic.log.Warn("Debug server stopped", zap.Error(err))
+		}
+	}()
+	ic.log.Info("Serving interchain debug server", zap.String("url", ic.DebugServerURL()))
+
+	for c := range ic.chains {
+		for _, n := range debugNodes(c) {
+			ic.log.Info("Node polkadot.js apps", zap.String("name", n.Name), zap.String("url", n.PolkadotJSURL))
+		}
+	}
+}
+
+// stopDebugServer stops the debug server started by startDebugServer, if any.
+func (ic *Interchain) stopDebugServer() error {
+	if ic.debugServer == nil {
+		return nil
+	}
+	return ic.debugServer.Close()
+}
+
+// rpcLink returns a link to the status endpoint of a tendermint RPC address, or an empty string for other RPC addresses.
+func rpcLink(addr string) string {
+	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
+		return ""
+	}
+	return strings.TrimSuffix(addr, "/") + "/status"
+}
+
+var debugPage = template.Must(template.New("debug").Funcs(template.FuncMap{"rpcLink": rpcLink}).Parse(`<!DOCTYPE html>
+<html>
+<head><title>ibctest {{.TestName}}</title></head>
+<body>
+<h1>{{.TestName}}</h1>
+<p><a href="/topology.json">topology.json</a> · <a href="/relayer-commands.json">relayer-commands.json</a></p>
+
+<h2>Chains</h2>
+<table border="1" cellpadding="4">
+<tr><th>Chain ID</th><th>Type</th><th>Height</th><th>Host RPC</th><th>Host gRPC</th><th>Docker RPC</th><th>Docker gRPC</th></tr>
+{{range .Chains}}<tr>
+<td>{{.ChainID}}</td><td>{{.Type}}</td>
+<td>{{if .HeightError}}{{.HeightError}}{{else}}{{.Height}}{{end}}</td>
+<td>{{with $link := rpcLink .HostRPCAddress}}<a href="{{$link}}">{{$link}}</a>{{else}}{{.HostRPCAddress}}{{end}}</td>
+<td>{{.HostGRPCAddress}}</td><td>{{.RPCAddress}}</td><td>{{.GRPCAddress}}</td>
+</tr>{{end}}
+</table>
+{{range .Chains}}{{if .Nodes}}
+<h3>{{.ChainID}} nodes</h3>
+<table border="1" cellpadding="4">
+<tr><th>Node</th><th>Chain</th><th>Host RPC</th><th>Host WebSocket</th><th>polkadot.js</th></tr>
+{{range .Nodes}}<tr>
+<td>{{.Name}}</td><td>{{.Location}}</td><td>{{.HostRPCAddress}}</td><td>{{.HostWSAddress}}</td>
+<td>{{with .PolkadotJSURL}}<a href="{{.}}">explorer</a>{{end}}</td>
+</tr>{{end}}
+</table>
+{{end}}{{end}}
+
+<h2>Relayers</h2>
+{{range .Relayers}}<h3>{{.Name}}</h3>
+<ul>{{range .Paths}}<li>{{.Name}}: {{index .ChainIDs 0}} ↔ {{index .ChainIDs 1}}</li>{{end}}</ul>
+{{end}}
+
+<h2>Latest relayer commands</h2>
+{{range .Commands}}<details>
+<summary>{{.FinishedAt.Format "15:04:05"}} {{.ContainerName}}: {{range .Command}}{{.}} {{end}}(exit code {{.ExitCode}})</summary>
+<pre>{{.Stdout}}</pre><pre>{{.Stderr}}</pre>{{if .Error}}<pre>{{.Error}}</pre>{{end}}
+</details>{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,108 @@
+package ibctest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	relayermock "github.com/strangelove-ventures/ibctest/v6/relayer/mock"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/stretchr/testify/require"
+)
+
+// debugChain is a mock chain with fixed host addresses, at a fixed height or failing to report it.
+type debugChain struct {
+	*mock.Chain
+}
+
+func newDebugChain(cfg ibc.ChainConfig, height uint64, heightErr error) *debugChain {
+	c := &debugChain{Chain: mock.NewChain(cfg)}
+	c.HeightFunc = func(ctx context.Context) (uint64, error) { return height, heightErr }
+	return c
+}
+
+func (c *debugChain) GetHostRPCAddress() string  { return "http://127.0.0.1:26657" }
+func (c *debugChain) GetHostGRPCAddress() string { return "127.0.0.1:9090" }
+
+func TestInterchain_DebugHandler(t *testing.T) {
+	t.Parallel()
+
+	gaia := newDebugChain(ibc.ChainConfig{ChainID: "gaia-1", Name: "gaia", Type: "cosmos"}, 12, nil)
+	osmo := newDebugChain(ibc.ChainConfig{ChainID: "osmosis-1", Name: "osmosis", Type: "cosmos"}, 0, errors.New("connection refused"))
+	r := relayermock.NewRelayer()
+
+	ic := NewInterchain().
+		AddChain(gaia).
+		AddChain(osmo).
+		AddRelayer(r, "relayer").
+		AddLink(InterchainLink{Chain1: gaia, Chain2: osmo, Relayer: r, Path: "gaia-osmo"})
+	ic.testName = "TestDebug"
+	ic.started = true
+
+	rep := testreporter.NewNopReporter().RelayerExecReporter(t)
+	rep.TrackRelayerExec("relayer-1", []string{"rly", "tx", "link", "gaia-osmo"}, "linked", "", 0, time.Now(), time.Now(), nil)
+
+	srv := httptest.NewServer(ic.DebugHandler(rep))
+	defer srv.Close()
+
+	get := func(path string) string {
+		res, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		b, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	var topo DebugTopology
+	require.NoError(t, json.Unmarshal([]byte(get("/topology.json")), &topo))
+	require.Equal(t, "TestDebug", topo.TestName)
+	require.Equal(t, []DebugChain{
+		{
+			ChainID: "gaia-1", Name: "gaia", Type: "cosmos",
+			HostRPCAddress: "http://127.0.0.1:26657", HostGRPCAddress: "127.0.0.1:9090",
+			RPCAddress: "http://gaia-1:26657", GRPCAddress: "gaia-1:9090",
+			Height: 12,
+		},
+		{
+			ChainID: "osmosis-1", Name: "osmosis", Type: "cosmos",
+			HostRPCAddress: "http://127.0.0.1:26657", HostGRPCAddress: "127.0.0.1:9090",
+			RPCAddress: "http://osmosis-1:26657", GRPCAddress: "osmosis-1:9090",
+			HeightError: "connection refused",
+		},
+	}, topo.Chains)
+	require.Equal(t, []DebugRelayer{
+		{Name: "relayer", Paths: []DebugPath{{Name: "gaia-osmo", ChainIDs: [2]string{"gaia-1", "osmosis-1"}}}},
+	}, topo.Relayers)
+
+	var cmds []testreporter.RelayerExecMessage
+	require.NoError(t, json.Unmarshal([]byte(get("/relayer-commands.json")), &cmds))
+	require.Len(t, cmds, 1)
+	require.Equal(t, []string{"rly", "tx", "link", "gaia-osmo"}, cmds[0].Command)
+
+	page := get("/")
+	require.Contains(t, page, `<a href="http://127.0.0.1:26657/status">`)
+	require.Contains(t, page, "gaia-osmo: gaia-1 ↔ osmosis-1")
+	require.Contains(t, page, "rly tx link gaia-osmo")
+
+	res, err := http.Get(srv.URL + "/missing")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestInterchain_DebugServerURL(t *testing.T) {
+	t.Parallel()
+
+	ic := NewInterchain()
+	require.Empty(t, ic.DebugServerURL())
+	require.NoError(t, ic.stopDebugServer())
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"cosmossdk.io/math"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/strangelove-ventures/ibctest/v6"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
@@ -86,7 +87,7 @@ func TestInterchainAccounts(t *testing.T) {
 
 	// Fund a user account on chain1 and chain2
 	const userFunds = int64(10_000_000_000)
-	users := ibctest.GetAndFundTestUsers(t, ctx, t.Name(), userFunds, chain1, chain2)
+	users := ibctest.GetAndFundTestUsers(t, ctx, t.Name(), math.NewInt(userFunds), chain1, chain2)
 	chain1User := users[0]
 	chain2User := users[1]
 
@@ -173,7 +174,7 @@ func TestInterchainAccounts(t *testing.T) {
 	transfer := ibc.WalletAmount{
 		Address: icaAddr,
 		Denom:   chain2.Config().Denom,
-		Amount:  transferAmount,
+		Amount:  math.NewInt(transferAmount),
 	}
 	err = chain2.SendFunds(ctx, chain2User.KeyName, transfer)
 	require.NoError(t, err)
@@ -184,11 +185,11 @@ func TestInterchainAccounts(t *testing.T) {
 
 	chain2Bal, err := chain2.GetBalance(ctx, chain2Addr, chain2.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, chain2OrigBal-transferAmount, chain2Bal)
+	require.Equal(t, chain2OrigBal.Int64()-transferAmount, chain2Bal.Int64())
 
 	icaBal, err := chain2.GetBalance(ctx, icaAddr, chain2.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, icaOrigBal+transferAmount, icaBal)
+	require.Equal(t, icaOrigBal.Int64()+transferAmount, icaBal.Int64())
 
 	// Build bank transfer msg
 	rawMsg, err := json.Marshal(map[string]any{
@@ -225,12 +226,12 @@ func TestInterchainAccounts(t *testing.T) {
 	// Assert that the funds have been received by the user account on chain2
 	chain2Bal, err = chain2.GetBalance(ctx, chain2Addr, chain2.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, chain2OrigBal, chain2Bal)
+	require.Equal(t, chain2OrigBal.Int64(), chain2Bal.Int64())
 
 	// Assert that the funds have been removed from the ICA on chain2
 	icaBal, err = chain2.GetBalance(ctx, icaAddr, chain2.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, icaOrigBal, icaBal)
+	require.Equal(t, icaOrigBal.Int64(), icaBal.Int64())
 
 	// Stop the relayer and wait for the process to terminate
 	err = r.StopRelayer(ctx, eRep)
@@ -257,11 +258,11 @@ func TestInterchainAccounts(t *testing.T) {
 	// Assert that the packet timed out and that the acc balances are correct
 	chain2Bal, err = chain2.GetBalance(ctx, chain2Addr, chain2.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, chain2OrigBal, chain2Bal)
+	require.Equal(t, chain2OrigBal.Int64(), chain2Bal.Int64())
 
 	icaBal, err = chain2.GetBalance(ctx, icaAddr, chain2.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, icaOrigBal, icaBal)
+	require.Equal(t, icaOrigBal.Int64(), icaBal.Int64())
 
 	// Assert that the channel ends are both closed
 	chain1Chans, err := r.GetChannels(ctx, eRep, chain1.Config().ChainID)
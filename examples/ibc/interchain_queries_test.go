@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"testing"
 
+	"cosmossdk.io/math"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/icza/dyno"
 	"github.com/strangelove-ventures/ibctest/v6"
@@ -120,7 +121,7 @@ func TestInterchainQueries(t *testing.T) {
 
 	// Fund user accounts, so we can query balances and make assertions.
 	const userFunds = int64(10_000_000_000)
-	users := ibctest.GetAndFundTestUsers(t, ctx, t.Name(), userFunds, chain1, chain2)
+	users := ibctest.GetAndFundTestUsers(t, ctx, t.Name(), math.NewInt(userFunds), chain1, chain2)
 	chain1User := users[0]
 	chain2User := users[1]
 
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"cosmossdk.io/math"
 	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
 	"github.com/strangelove-ventures/ibctest/v6"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
@@ -69,13 +70,13 @@ func TestLearn(t *testing.T) {
 
 	// Create and Fund User Wallets
 	fundAmount := int64(10_000_000)
-	users := ibctest.GetAndFundTestUsers(t, ctx, "default", int64(fundAmount), gaia, osmosis)
+	users := ibctest.GetAndFundTestUsers(t, ctx, "default", math.NewInt(fundAmount), gaia, osmosis)
 	gaiaUser := users[0]
 	osmosisUser := users[1]
 
 	gaiaUserBalInitial, err := gaia.GetBalance(ctx, gaiaUser.Bech32Address(gaia.Config().Bech32Prefix), gaia.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, fundAmount, gaiaUserBalInitial)
+	require.Equal(t, fundAmount, gaiaUserBalInitial.Int64())
 
 	// Get Channel ID
 	gaiaChannelInfo, err := r.GetChannels(ctx, eRep, gaia.Config().ChainID)
@@ -92,7 +93,7 @@ func TestLearn(t *testing.T) {
 	tx, err := gaia.SendIBCTransfer(ctx, gaiaChannelID, gaiaUser.KeyName, ibc.WalletAmount{
 		Address: dstAddress,
 		Denom:   gaia.Config().Denom,
-		Amount:  amountToSend,
+		Amount:  math.NewInt(amountToSend),
 	},
 		nil,
 	)
@@ -104,10 +105,10 @@ func TestLearn(t *testing.T) {
 	require.NoError(t, r.FlushAcknowledgements(ctx, eRep, ibcPath, gaiaChannelID))
 
 	// test source wallet has decreased funds
-	expectedBal := gaiaUserBalInitial - amountToSend
+	expectedBal := gaiaUserBalInitial.Int64() - amountToSend
 	gaiaUserBalNew, err := gaia.GetBalance(ctx, gaiaUser.Bech32Address(gaia.Config().Bech32Prefix), gaia.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, expectedBal, gaiaUserBalNew)
+	require.Equal(t, expectedBal, gaiaUserBalNew.Int64())
 
 	// Trace IBC Denom
 	srcDenomTrace := transfertypes.ParseDenomTrace(transfertypes.GetPrefixedDenom("transfer", gaiaChannelID, gaia.Config().Denom))
@@ -116,6 +117,6 @@ func TestLearn(t *testing.T) {
 	// Test destination wallet has increased funds
 	osmosUserBalNew, err := osmosis.GetBalance(ctx, osmosisUser.Bech32Address(osmosis.Config().Bech32Prefix), dstIbcDenom)
 	require.NoError(t, err)
-	require.Equal(t, amountToSend, osmosUserBalNew)
+	require.Equal(t, amountToSend, osmosUserBalNew.Int64())
 
 }
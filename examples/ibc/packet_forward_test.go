@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"cosmossdk.io/math"
 	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
 	"github.com/strangelove-ventures/ibctest/v6"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
@@ -78,7 +79,7 @@ func TestPacketForwardMiddleware(t *testing.T) {
 	})
 
 	const userFunds = int64(10_000_000_000)
-	users := ibctest.GetAndFundTestUsers(t, ctx, t.Name(), userFunds, osmosis, gaia, juno)
+	users := ibctest.GetAndFundTestUsers(t, ctx, t.Name(), math.NewInt(userFunds), osmosis, gaia, juno)
 
 	osmoChannels, err := r.GetChannels(ctx, eRep, osmosis.Config().ChainID)
 	require.NoError(t, err)
@@ -113,7 +114,7 @@ func TestPacketForwardMiddleware(t *testing.T) {
 	transfer := ibc.WalletAmount{
 		Address: receiver,
 		Denom:   osmosis.Config().Denom,
-		Amount:  transferAmount,
+		Amount:  math.NewInt(transferAmount),
 	}
 
 	osmosisGaiaChan := osmoChannels[0]
@@ -127,7 +128,7 @@ func TestPacketForwardMiddleware(t *testing.T) {
 	// Check that the funds sent are gone from the acc on osmosis
 	osmosisBal, err := osmosis.GetBalance(ctx, osmosisUser.Bech32Address(osmosis.Config().Bech32Prefix), osmosis.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, osmosisBalOG-transferAmount, osmosisBal)
+	require.Equal(t, osmosisBalOG.Int64()-transferAmount, osmosisBal.Int64())
 
 	// Compose the prefixed denoms and ibc denom for asserting balances
 	gaiaOsmoChan := osmoChannels[0].Counterparty
@@ -139,14 +140,14 @@ func TestPacketForwardMiddleware(t *testing.T) {
 	// Check that the funds sent are present in the acc on juno
 	junoBal, err := juno.GetBalance(ctx, junoUser.Bech32Address(juno.Config().Bech32Prefix), dstIbcDenom.IBCDenom())
 	require.NoError(t, err)
-	require.Equal(t, transferAmount, junoBal)
+	require.Equal(t, transferAmount, junoBal.Int64())
 
 	// Send packet back from Juno->Hub->Osmosis
 	receiver = fmt.Sprintf("%s|%s/%s:%s", gaiaUser.Bech32Address(gaia.Config().Bech32Prefix), gaiaOsmoChan.PortID, gaiaOsmoChan.ChannelID, osmosisUser.Bech32Address(osmosis.Config().Bech32Prefix))
 	transfer = ibc.WalletAmount{
 		Address: receiver,
 		Denom:   dstIbcDenom.IBCDenom(),
-		Amount:  transferAmount,
+		Amount:  math.NewInt(transferAmount),
 	}
 
 	_, err = juno.SendIBCTransfer(ctx, junoGaiaChan.ChannelID, junoUser.KeyName, transfer, nil)
@@ -159,12 +160,12 @@ func TestPacketForwardMiddleware(t *testing.T) {
 	// Check that the funds sent are gone from the acc on juno
 	junoBal, err = juno.GetBalance(ctx, junoUser.Bech32Address(juno.Config().Bech32Prefix), dstIbcDenom.IBCDenom())
 	require.NoError(t, err)
-	require.Equal(t, int64(0), junoBal)
+	require.Equal(t, int64(0), junoBal.Int64())
 
 	// Check that the funds sent are present in the acc on osmosis
 	osmosisBal, err = osmosis.GetBalance(ctx, osmosisUser.Bech32Address(osmosis.Config().Bech32Prefix), osmosis.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, osmosisBalOG, osmosisBal)
+	require.Equal(t, osmosisBalOG.Int64(), osmosisBal.Int64())
 
 	// Send a malformed packet with invalid receiver address from Osmosis->Hub->Juno
 	// This should succeed in the first hop and fail to make the second hop; funds should end up in the intermediary account.
@@ -172,7 +173,7 @@ func TestPacketForwardMiddleware(t *testing.T) {
 	transfer = ibc.WalletAmount{
 		Address: receiver,
 		Denom:   osmosis.Config().Denom,
-		Amount:  transferAmount,
+		Amount:  math.NewInt(transferAmount),
 	}
 
 	_, err = osmosis.SendIBCTransfer(ctx, osmosisGaiaChan.ChannelID, osmosisUser.KeyName, transfer, nil)
@@ -185,11 +186,11 @@ func TestPacketForwardMiddleware(t *testing.T) {
 	// Check that the funds sent are gone from the acc on osmosis
 	osmosisBal, err = osmosis.GetBalance(ctx, osmosisUser.Bech32Address(osmosis.Config().Bech32Prefix), osmosis.Config().Denom)
 	require.NoError(t, err)
-	require.Equal(t, osmosisBalOG-transferAmount, osmosisBal)
+	require.Equal(t, osmosisBalOG.Int64()-transferAmount, osmosisBal.Int64())
 
 	// Check that the funds sent ended up in the acc on gaia
 	intermediaryIBCDenom := transfertypes.ParseDenomTrace(firstHopDenom)
 	gaiaBal, err := gaia.GetBalance(ctx, gaiaUser.Bech32Address(gaia.Config().Bech32Prefix), intermediaryIBCDenom.IBCDenom())
 	require.NoError(t, err)
-	require.Equal(t, transferAmount, gaiaBal)
+	require.Equal(t, transferAmount, gaiaBal.Int64())
 }
@@ -0,0 +1,303 @@
+package ibctest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultHandshakeStepTimeout is how long Build waits for each handshake step of a link, by default.
+	defaultHandshakeStepTimeout = 3 * time.Minute
+
+	// handshakePollInterval is how often Build polls the states of the connections and channels of linked chains.
+	handshakePollInterval = 2 * time.Second
+
+	// handshakeOutputTail is how much of the relayer's last output a HandshakeStuckError includes.
+	handshakeOutputTail = 4096
+)
+
+// handshakeSteps are the steps that creating a connection, then a channel, progresses through, in order.
+// A step is reached once either end of the link reaches it.
+var handshakeSteps = []string{
+	"connection INIT",
+	"connection TRYOPEN",
+	"connection OPEN on one end",
+	"connection OPEN on both ends",
+	"channel INIT",
+	"channel TRYOPEN",
+	"channel OPEN on one end",
+	"channel OPEN on both ends",
+}
+
+// HandshakeStuckError is returned by Interchain.Build when the handshake of a link makes no progress
+// within the step timeout set through InterchainBuildOptions.
+type HandshakeStuckError struct {
+	Path string
+
+	// Step the handshake was waiting for, e.g. "connection TRYOPEN", and the last step reached, if any,
+	// including the chain and ID, e.g. "connection INIT on gaia-1 (connection-0)".
+	Step, Reached string
+
+	// How long the handshake waited for Step.
+	Waited time.Duration
+
+	// The end of the output of the relayer's last command, which is typically the linking command.
+	RelayerOutput string
+}
+
+func (e *HandshakeStuckError) Error() string {
+	reached := e.Reached
+	if reached == "" {
+		reached = "nothing"
+	}
+	msg := fmt.Sprintf("handshake of path %s stuck waiting for %s for %s; last reached %s", e.Path, e.Step, e.Waited, reached)
+	if e.RelayerOutput == "" {
+		return msg + "; no relayer output captured"
+	}
+	return msg + "; relayer output:\n" + e.RelayerOutput
+}
+
+// handshakeStates is a snapshot of the connections and channels of one end of a link.
+type handshakeStates struct {
+	chainID     string
+	connections ibc.ConnectionOutputs
+	channels    []ibc.ChannelOutput
+}
+
+// handshakeStateFunc returns the connections and channels of a chain.
+type handshakeStateFunc func(ctx context.Context) (handshakeStates, error)
+
+// handshakeStateSource returns the function polling the connections and channels of c,
+// through the chain if it implements ibc.HandshakeStateQuerier, or else through the relayer r.
+func handshakeStateSource(r ibc.Relayer, c ibc.Chain) handshakeStateFunc {
+	chainID := c.Config().ChainID
+	return func(ctx context.Context) (handshakeStates, error) {
+		s := handshakeStates{chainID: chainID}
+		var err error
+		if q, ok := c.(ibc.HandshakeStateQuerier); ok {
+			if s.connections, err = q.QueryConnections(ctx); err != nil {
+				return s, err
+			}
+			s.channels, err = q.QueryChannels(ctx)
+			return s, err
+		}
+		// Polls are not reported, so that they do not bury the relayer's commands in reports.
+		if s.connections, err = r.GetConnections(ctx, nopRelayerExecReporter{}, chainID); err != nil {
+			return s, err
+		}
+		s.channels, err = r.GetChannels(ctx, nopRelayerExecReporter{}, chainID)
+		return s, err
+	}
+}
+
+// handshakeProgress returns the index in handshakeSteps of the furthest step reached by the connections and channels
+// created on either end of a link since the snapshots beforeA and beforeB, and a description of where it was reached.
+// It returns -1 if no step was reached.
+func handshakeProgress(beforeA, beforeB, a, b handshakeStates) (int, string) {
+	reached, where := -1, ""
+	advance := func(step int, chainID, id string) {
+		if step > reached {
+			reached, where = step, fmt.Sprintf("%s on %s (%s)", handshakeSteps[step], chainID, id)
+		}
+	}
+
+	for _, ends := range [][3]handshakeStates{{beforeA, a, b}, {beforeB, b, a}} {
+		before, end, counterparty := ends[0], ends[1], ends[2]
+		for _, conn := range end.connections {
+			if existingConnection(before, conn.ID) {
+				continue
+			}
+			switch handshakeState(conn.State) {
+			case "INIT":
+				advance(0, end.chainID, conn.ID)
+			case "TRYOPEN":
+				advance(1, end.chainID, conn.ID)
+			case "OPEN":
+				advance(2, end.chainID, conn.ID)
+				if conn.Counterparty != nil && counterpartyConnectionOpen(counterparty, conn.Counterparty.ConnectionId) {
+					advance(3, end.chainID, conn.ID)
+				}
+			}
+		}
+		for _, ch := range end.channels {
+			if existingChannel(before, ch.PortID, ch.ChannelID) {
+				continue
+			}
+			id := ch.PortID + "/" + ch.ChannelID
+			switch handshakeState(ch.State) {
+			case "INIT":
+				advance(4, end.chainID, id)
+			case "TRYOPEN":
+				advance(5, end.chainID, id)
+			case "OPEN":
+				advance(6, end.chainID, id)
+				if counterpartyChannelOpen(counterparty, ch.Counterparty) {
+					advance(7, end.chainID, id)
+				}
+			}
+		}
+	}
+	return reached, where
+}
+
+// handshakeState normalizes the state of a connection or channel as reported by chains and relayers,
+// e.g. "STATE_TRYOPEN" or "TryOpen", to e.g. "TRYOPEN".
+func handshakeState(state string) string {
+	return strings.TrimPrefix(strings.ToUpper(state), "STATE_")
+}
+
+func existingConnection(before handshakeStates, id string) bool {
+	for _, conn := range before.connections {
+		if conn.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func existingChannel(before handshakeStates, portID, channelID string) bool {
+	for _, ch := range before.channels {
+		if ch.PortID == portID && ch.ChannelID == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+func counterpartyConnectionOpen(counterparty handshakeStates, id string) bool {
+	for _, conn := range counterparty.connections {
+		if conn.ID == id && handshakeState(conn.State) == "OPEN" {
+			return true
+		}
+	}
+	return false
+}
+
+func counterpartyChannelOpen(counterparty handshakeStates, cp ibc.ChannelCounterparty) bool {
+	for _, ch := range counterparty.channels {
+		if ch.PortID == cp.PortID && ch.ChannelID == cp.ChannelID && handshakeState(ch.State) == "OPEN" {
+			return true
+		}
+	}
+	return false
+}
+
+// handshakeVerifier follows the handshake of a link while the relayer links its path,
+// and cancels the linking once a step takes longer than stepTimeout.
+type handshakeVerifier struct {
+	log          *zap.Logger
+	path         string
+	stepTimeout  time.Duration
+	pollInterval time.Duration
+
+	stateA, stateB handshakeStateFunc
+	rep            *handshakeExecRecorder
+}
+
+// run calls link with a context that is canceled if the handshake gets stuck,
+// in which case it returns a *HandshakeStuckError once link returns.
+func (v handshakeVerifier) run(ctx context.Context, link func(ctx context.Context) error) error {
+	beforeA, errA := v.stateA(ctx)
+	beforeB, errB := v.stateB(ctx)
+	if errA != nil || errB != nil {
+		// Without a snapshot, new connections and channels are indistinguishable from existing ones.
+		v.log.Info("Not verifying handshake progress: failed to query initial state",
+			zap.String("path", v.path), zap.NamedError("error_a", errA), zap.NamedError("error_b", errB),
+		)
+		return link(ctx)
+	}
+
+	linkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- link(linkCtx) }()
+
+	ticker := time.NewTicker(v.pollInterval)
+	defer ticker.Stop()
+
+	reached, where := -1, ""
+	progressedAt := time.Now()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+		}
+
+		a, errA := v.stateA(ctx)
+		b, errB := v.stateB(ctx)
+		if errA == nil && errB == nil {
+			step, stepWhere := handshakeProgress(beforeA, beforeB, a, b)
+			if step > reached {
+				reached, where = step, stepWhere
+				progressedAt = time.Now()
+				v.log.Info("Handshake progressed", zap.String("path", v.path), zap.String("reached", where))
+			}
+		}
+		if reached == len(handshakeSteps)-1 {
+			// Nothing left to wait for; the relayer may still be finishing up.
+			progressedAt = time.Now()
+		}
+
+		if waited := time.Since(progressedAt); waited > v.stepTimeout {
+			cancel()
+			<-done
+			return &HandshakeStuckError{
+				Path:          v.path,
+				Step:          handshakeSteps[reached+1],
+				Reached:       where,
+				Waited:        waited.Round(time.Second),
+				RelayerOutput: v.rep.lastOutput(),
+			}
+		}
+	}
+}
+
+// handshakeExecRecorder forwards relayer commands to a reporter, if any,
+// and keeps the output of the last one.
+type handshakeExecRecorder struct {
+	rep ibc.RelayerExecReporter
+
+	mu     sync.Mutex
+	output string
+}
+
+func (r *handshakeExecRecorder) TrackRelayerExec(
+	containerName string,
+	command []string,
+	stdout, stderr string,
+	exitCode int,
+	startedAt, finishedAt time.Time,
+	err error,
+) {
+	if r.rep != nil {
+		r.rep.TrackRelayerExec(containerName, command, stdout, stderr, exitCode, startedAt, finishedAt, err)
+	}
+
+	out := strings.TrimSpace(stderr + "\n" + stdout)
+	if len(out) > handshakeOutputTail {
+		out = "..." + out[len(out)-handshakeOutputTail:]
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.output = out
+}
+
+func (r *handshakeExecRecorder) lastOutput() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.output
+}
+
+// nopRelayerExecReporter does not report relayer commands.
+type nopRelayerExecReporter struct{}
+
+func (nopRelayerExecReporter) TrackRelayerExec(string, []string, string, string, int, time.Time, time.Time, error) {
+}
@@ -0,0 +1,141 @@
+package ibctest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	conntypes "github.com/cosmos/ibc-go/v6/modules/core/03-connection/types"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func handshakeConnection(id, state, counterpartyID string) *ibc.ConnectionOutput {
+	return &ibc.ConnectionOutput{ID: id, State: state, Counterparty: &conntypes.Counterparty{ConnectionId: counterpartyID}}
+}
+
+func TestHandshakeProgress(t *testing.T) {
+	t.Parallel()
+
+	// Each chain already has an open connection to another chain.
+	beforeA := handshakeStates{chainID: "gaia-1", connections: ibc.ConnectionOutputs{handshakeConnection("connection-0", "STATE_OPEN", "connection-9")}}
+	beforeB := handshakeStates{chainID: "osmosis-1", connections: ibc.ConnectionOutputs{handshakeConnection("connection-0", "STATE_OPEN", "connection-8")}}
+
+	step, where := handshakeProgress(beforeA, beforeB, beforeA, beforeB)
+	require.Equal(t, -1, step)
+	require.Empty(t, where)
+
+	a := beforeA
+	a.connections = append(a.connections, handshakeConnection("connection-1", "STATE_INIT", ""))
+	step, where = handshakeProgress(beforeA, beforeB, a, beforeB)
+	require.Equal(t, "connection INIT", handshakeSteps[step])
+	require.Equal(t, "connection INIT on gaia-1 (connection-1)", where)
+
+	b := beforeB
+	b.connections = append(b.connections, handshakeConnection("connection-1", "TryOpen", "connection-1"))
+	step, _ = handshakeProgress(beforeA, beforeB, a, b)
+	require.Equal(t, "connection TRYOPEN", handshakeSteps[step])
+
+	a.connections[1] = handshakeConnection("connection-1", "STATE_OPEN", "connection-1")
+	step, _ = handshakeProgress(beforeA, beforeB, a, b)
+	require.Equal(t, "connection OPEN on one end", handshakeSteps[step])
+
+	b.connections[1] = handshakeConnection("connection-1", "STATE_OPEN", "connection-1")
+	step, _ = handshakeProgress(beforeA, beforeB, a, b)
+	require.Equal(t, "connection OPEN on both ends", handshakeSteps[step])
+
+	a.channels = []ibc.ChannelOutput{{State: "STATE_OPEN", PortID: "transfer", ChannelID: "channel-0", Counterparty: ibc.ChannelCounterparty{PortID: "transfer", ChannelID: "channel-3"}}}
+	b.channels = []ibc.ChannelOutput{{State: "STATE_OPEN", PortID: "transfer", ChannelID: "channel-3", Counterparty: ibc.ChannelCounterparty{PortID: "transfer", ChannelID: "channel-0"}}}
+	step, where = handshakeProgress(beforeA, beforeB, a, b)
+	require.Equal(t, len(handshakeSteps)-1, step)
+	require.Equal(t, "channel OPEN on both ends on gaia-1 (transfer/channel-0)", where)
+}
+
+// handshakeSim serves the states of both ends of a link, which tests advance.
+type handshakeSim struct {
+	mu   sync.Mutex
+	a, b handshakeStates
+}
+
+func (s *handshakeSim) state(end *handshakeStates) handshakeStateFunc {
+	return func(ctx context.Context) (handshakeStates, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return *end, nil
+	}
+}
+
+func (s *handshakeSim) update(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn()
+}
+
+func TestHandshakeVerifier(t *testing.T) {
+	t.Parallel()
+
+	newVerifier := func(sim *handshakeSim) handshakeVerifier {
+		return handshakeVerifier{
+			log:          zap.NewNop(),
+			path:         "gaia-osmosis",
+			stepTimeout:  500 * time.Millisecond,
+			pollInterval: 10 * time.Millisecond,
+			stateA:       sim.state(&sim.a),
+			stateB:       sim.state(&sim.b),
+			rep:          &handshakeExecRecorder{},
+		}
+	}
+
+	t.Run("completes", func(t *testing.T) {
+		t.Parallel()
+
+		sim := &handshakeSim{a: handshakeStates{chainID: "gaia-1"}, b: handshakeStates{chainID: "osmosis-1"}}
+		err := newVerifier(sim).run(context.Background(), func(ctx context.Context) error {
+			// Each step takes less than the step timeout, though the whole handshake takes longer.
+			for _, state := range []string{"STATE_INIT", "STATE_TRYOPEN", "STATE_OPEN"} {
+				state := state
+				sim.update(func() {
+					sim.a.connections = ibc.ConnectionOutputs{handshakeConnection("connection-0", state, "connection-0")}
+				})
+				time.Sleep(100 * time.Millisecond)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("stuck", func(t *testing.T) {
+		t.Parallel()
+
+		sim := &handshakeSim{a: handshakeStates{chainID: "gaia-1"}, b: handshakeStates{chainID: "osmosis-1"}}
+		v := newVerifier(sim)
+		err := v.run(context.Background(), func(ctx context.Context) error {
+			sim.update(func() {
+				sim.a.connections = ibc.ConnectionOutputs{handshakeConnection("connection-0", "STATE_INIT", "")}
+			})
+			v.rep.TrackRelayerExec("rly", []string{"rly", "tx", "link"}, "", "Error: no counterparty\n", 1, time.Now(), time.Now(), nil)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		var stuck *HandshakeStuckError
+		require.True(t, errors.As(err, &stuck))
+		require.Equal(t, "connection TRYOPEN", stuck.Step)
+		require.Equal(t, "connection INIT on gaia-1 (connection-0)", stuck.Reached)
+		require.Equal(t, "Error: no counterparty", stuck.RelayerOutput)
+		require.Contains(t, err.Error(), "handshake of path gaia-osmosis stuck waiting for connection TRYOPEN")
+	})
+
+	t.Run("link error", func(t *testing.T) {
+		t.Parallel()
+
+		sim := &handshakeSim{a: handshakeStates{chainID: "gaia-1"}, b: handshakeStates{chainID: "osmosis-1"}}
+		err := newVerifier(sim).run(context.Background(), func(ctx context.Context) error {
+			return errors.New("bad config")
+		})
+		require.EqualError(t, err, "bad config")
+	})
+}
@@ -3,6 +3,7 @@ package ibc
 import (
 	"context"
 
+	"cosmossdk.io/math"
 	"github.com/docker/docker/client"
 )
 
@@ -63,10 +64,10 @@ type Chain interface {
 	Height(ctx context.Context) (uint64, error)
 
 	// GetBalance fetches the current balance for a specific account address and denom.
-	GetBalance(ctx context.Context, address string, denom string) (int64, error)
+	GetBalance(ctx context.Context, address string, denom string) (math.Int, error)
 
 	// GetGasFeesInNativeDenom gets the fees in native denom for an amount of spent gas.
-	GetGasFeesInNativeDenom(gasPaid int64) int64
+	GetGasFeesInNativeDenom(gasPaid int64) math.Int
 
 	// Acknowledgements returns all acknowledgements in a block at height.
 	Acknowledgements(ctx context.Context, height uint64) ([]PacketAcknowledgement, error)
@@ -74,3 +75,180 @@ type Chain interface {
 	// Timeouts returns all timeouts in a block at height.
 	Timeouts(ctx context.Context, height uint64) ([]PacketTimeout, error)
 }
+
+// MemoIBCTransferer is implemented by chains that can attach a memo to IBC transfers,
+// e.g. to instruct packet forward middleware on the receiving chain. See FeatureMemo.
+type MemoIBCTransferer interface {
+	// SendIBCTransferWithMemo is like SendIBCTransfer, setting the memo of the transfer's packet data.
+	SendIBCTransferWithMemo(ctx context.Context, channelID, keyName string, amount WalletAmount, timeout *IBCTimeout, memo string) (Tx, error)
+}
+
+// BatchIBCTransferer is implemented by chains that can send multiple IBC transfers in a single transaction.
+type BatchIBCTransferer interface {
+	// SendIBCTransferBatch sends one IBC transfer per amount in a single transaction.
+	// It returns one Tx per amount, in the same order, sharing the transaction's height, hash, and gas;
+	// each Tx's Packet holds that transfer's packet, including its sequence.
+	SendIBCTransferBatch(ctx context.Context, channelID, keyName string, amounts []WalletAmount, timeout *IBCTimeout) ([]Tx, error)
+}
+
+// BalanceAtHeightQuerier is implemented by chains that can query balances in the state of past blocks,
+// so that tests can verify the exact block in which a transfer settled.
+type BalanceAtHeightQuerier interface {
+	// BalanceAtHeight fetches the balance for a specific account address and denom
+	// in the state of the block at height.
+	BalanceAtHeight(ctx context.Context, address string, denom string, height uint64) (math.Int, error)
+}
+
+// ConfigResolver is implemented by chains that can report their effective configuration once started,
+// so that reports record exactly which images a failing run used.
+type ConfigResolver interface {
+	// ResolvedConfig returns the effective configuration of the started chain.
+	ResolvedConfig(ctx context.Context) (ResolvedChainConfig, error)
+}
+
+// HandshakeStateQuerier is implemented by chains that can list their IBC connections and channels without a relayer,
+// so that Interchain.Build can follow the progress of the handshakes of new links.
+type HandshakeStateQuerier interface {
+	// QueryConnections returns all connections of the chain, in any state.
+	QueryConnections(ctx context.Context) (ConnectionOutputs, error)
+
+	// QueryChannels returns all channels of the chain, in any state.
+	QueryChannels(ctx context.Context) ([]ChannelOutput, error)
+}
+
+// DisplayUnits is implemented by chains that report the display unit of their native token,
+// so that tests can express amounts in display units, e.g. "10 DOT", see ToBaseUnits and ToDisplayUnits.
+// The cosmos, polkadot, and penumbra chains implement it from their ChainConfig.
+// Chains that do not implement it use the display unit of their ChainConfig.
+type DisplayUnits interface {
+	// Decimals returns the number of decimals of the display unit of the native denom, e.g. 6 for atom.
+	Decimals() int64
+
+	// DisplayDenom returns the display unit of the native denom, e.g. atom for uatom.
+	DisplayDenom() string
+}
+
+// HealthChecker is implemented by chains that can report their readiness and liveness,
+// so that waiting on a chain can tell a slow chain from a broken one.
+type HealthChecker interface {
+	// HealthCheck returns the health of the chain.
+	// It returns an error if the health cannot be determined, e.g. while a node does not respond.
+	HealthCheck(ctx context.Context) (HealthStatus, error)
+}
+
+// HealthStatus is the health of a chain, as reported by a HealthChecker.
+type HealthStatus struct {
+	// Live is false once the chain cannot make progress without intervention,
+	// e.g. after a node crashed more often than it may be restarted, or logged a consensus failure.
+	Live bool
+
+	// Ready is true once every node of the chain is in sync and connected to its peers,
+	// so that the chain serves current state and makes blocks at its normal pace.
+	Ready bool
+
+	// Why the chain is not live or not ready, e.g. "node gaia-1-val-0 is catching up at height 12".
+	Reason string
+}
+
+func (s HealthStatus) String() string {
+	switch {
+	case !s.Live:
+		return "not live: " + s.Reason
+	case !s.Ready:
+		return "live but not ready: " + s.Reason
+	default:
+		return "ready"
+	}
+}
+
+// ValidatorCrasher is implemented by chains whose validator nodes can be killed and restarted one at a time,
+// so that tests can exercise the recovery of a node's database after a crash, see test.CrashValidator.
+type ValidatorCrasher interface {
+	// CrashableValidators returns the number of validator nodes, which are indexed from 0.
+	CrashableValidators() int
+
+	// KillValidator kills the container of validator i with SIGKILL, without letting its node shut down.
+	KillValidator(ctx context.Context, i int) error
+
+	// RestartValidator starts the killed container of validator i again, from the same volume,
+	// and waits for its node to respond.
+	RestartValidator(ctx context.Context, i int) error
+
+	// ValidatorHeight returns the latest block height of the node of validator i.
+	ValidatorHeight(ctx context.Context, i int) (uint64, error)
+}
+
+// RelayerWalletDeriver is implemented by chains whose accounts are not bech32 addresses of secp256k1 keys,
+// such as substrate chains, so that Interchain.Build can fund the wallets that relayers restore on them at genesis.
+type RelayerWalletDeriver interface {
+	// RelayerWalletAddress returns the address of the account a relayer restores from mnemonic.
+	RelayerWalletAddress(mnemonic string) (string, error)
+
+	// RelayerFeeDenoms returns the denoms that relayer wallets are funded in at genesis,
+	// e.g. the denoms of a relay chain and of its parachains.
+	RelayerFeeDenoms() []string
+}
+
+// ChainFeature is a feature of a chain that tests may depend on.
+type ChainFeature string
+
+// The list of chain features that ibctest understands.
+const (
+	// ICS-20 fungible token transfers, through SendIBCTransfer.
+	FeatureTransfer ChainFeature = "transfer"
+
+	// Transfers that time out after a destination chain height, or after a destination chain timestamp.
+	FeatureHeightTimeout    ChainFeature = "height-timeout"
+	FeatureTimestampTimeout ChainFeature = "timestamp-timeout"
+
+	// The memo field of ICS-20 transfers.
+	FeatureMemo ChainFeature = "memo"
+
+	// ICS-27 interchain accounts.
+	FeatureInterchainAccounts ChainFeature = "interchain-accounts"
+
+	// ICS-29 fee middleware.
+	FeatureFeeMiddleware ChainFeature = "fee-middleware"
+
+	// Light clients of other chains implemented as wasm contracts.
+	FeatureWasmClients ChainFeature = "wasm-clients"
+
+	// Closing ICS-20 transfer channels with MsgChannelCloseInit,
+	// which the ibc-go transfer module rejects unless the chain's transfer app allows it.
+	FeatureChannelClose ChainFeature = "channel-close"
+)
+
+// GenesisFeeGranter is implemented by chains that can create fee allowances at genesis,
+// so that Interchain.Build can grant relayer wallets the ChainConfig.RelayerFeeAllowance.
+type GenesisFeeGranter interface {
+	// AddGenesisFeeAllowances adds allowances to the genesis of the chain.
+	// It must be called before Start.
+	AddGenesisFeeAllowances(allowances ...GenesisFeeAllowance)
+}
+
+// ChainCapabilities is implemented by chains that report which features they support,
+// so that tests depending on unsupported features are skipped rather than failed.
+type ChainCapabilities interface {
+	// SupportsFeature reports whether the chain supports f.
+	SupportsFeature(f ChainFeature) bool
+}
+
+// SupportsFeature reports whether c supports f.
+// Chains that do not implement ChainCapabilities are assumed to support every feature.
+func SupportsFeature(c Chain, f ChainFeature) bool {
+	if cc, ok := c.(ChainCapabilities); ok {
+		return cc.SupportsFeature(f)
+	}
+	return true
+}
+
+// MissingFeatures returns the features in reqs that c does not support.
+func MissingFeatures(c Chain, reqs ...ChainFeature) []ChainFeature {
+	var missing []ChainFeature
+	for _, f := range reqs {
+		if !SupportsFeature(c, f) {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,90 @@
+package ibc
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/stretchr/testify/require"
+)
+
+type plainChain struct {
+	Chain
+}
+
+type transferOnlyChain struct {
+	Chain
+}
+
+func (transferOnlyChain) SupportsFeature(f ChainFeature) bool {
+	return f == FeatureTransfer
+}
+
+func TestSupportsFeature(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, SupportsFeature(plainChain{}, FeatureWasmClients))
+	require.Empty(t, MissingFeatures(plainChain{}, FeatureTransfer, FeatureMemo))
+
+	require.True(t, SupportsFeature(transferOnlyChain{}, FeatureTransfer))
+	require.False(t, SupportsFeature(transferOnlyChain{}, FeatureMemo))
+	require.Equal(t,
+		[]ChainFeature{FeatureMemo, FeatureInterchainAccounts},
+		MissingFeatures(transferOnlyChain{}, FeatureTransfer, FeatureMemo, FeatureInterchainAccounts),
+	)
+}
+
+type configChain struct {
+	Chain
+	cfg ChainConfig
+}
+
+func (c configChain) Config() ChainConfig {
+	return c.cfg
+}
+
+// unitsChain reports the display unit of its native token itself.
+type unitsChain struct {
+	configChain
+}
+
+func (unitsChain) Decimals() int64 {
+	return 10
+}
+
+func (unitsChain) DisplayDenom() string {
+	return "DOT"
+}
+
+func TestToBaseUnits(t *testing.T) {
+	t.Parallel()
+
+	gaia := configChain{cfg: ChainConfig{ChainID: "gaia-1", Type: "cosmos", Denom: "uatom"}}
+	rococo := configChain{cfg: ChainConfig{ChainID: "rococo-local", Type: "polkadot", Denom: "uDOT"}}
+	polkadot := unitsChain{configChain{cfg: ChainConfig{ChainID: "polkadot", Type: "polkadot", Denom: "planck"}}}
+
+	for _, tt := range []struct {
+		chain  Chain
+		amount string
+		want   string
+	}{
+		{gaia, "5", "5000000"},
+		{gaia, "5 ATOM", "5000000"},
+		{gaia, " 1.5 atom ", "1500000"},
+		{rococo, "10 DOT", "10000000000000"},
+		{polkadot, "10 DOT", "100000000000"},
+	} {
+		got, err := ToBaseUnits(tt.chain, tt.amount)
+		require.NoError(t, err, tt.amount)
+		require.Equal(t, tt.want, got.String(), tt.amount)
+	}
+
+	_, err := ToBaseUnits(gaia, "10 DOT")
+	require.EqualError(t, err, `amount "10 DOT" is not in atom, the display unit of chain gaia-1`)
+	_, err = ToBaseUnits(gaia, "1 atom extra")
+	require.EqualError(t, err, `invalid display amount "1 atom extra"`)
+	_, err = ToBaseUnits(gaia, "0.0000001 atom")
+	require.Error(t, err)
+
+	require.Equal(t, "1.5", ToDisplayUnits(gaia, math.NewInt(1500000)))
+	require.Equal(t, "10", ToDisplayUnits(polkadot, math.NewInt(100000000000)))
+}
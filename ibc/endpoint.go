@@ -0,0 +1,31 @@
+package ibc
+
+// Transport is a protocol over which a chain serves its RPC.
+type Transport string
+
+const (
+	TransportHTTP      Transport = "http"
+	TransportWebSocket Transport = "ws"
+)
+
+// Endpoint is the address of a chain's RPC served over a transport.
+type Endpoint struct {
+	Transport Transport
+
+	// Host and port of the endpoint, e.g. "relaychain-0-rococo-local-TestName:27451".
+	Address string
+}
+
+// URL returns the address of the endpoint with the scheme of its transport, e.g. "ws://localhost:49154".
+func (e Endpoint) URL() string {
+	return string(e.Transport) + "://" + e.Address
+}
+
+// EndpointProvider is implemented by chains serving their RPC over multiple transports,
+// such as substrate chains serving both HTTP and websocket RPC,
+// whose addresses do not map onto GetRPCAddress and GetGRPCAddress.
+type EndpointProvider interface {
+	// Endpoint returns the chain's RPC endpoint over transport,
+	// reachable from the docker network, or from the host machine if host is true.
+	Endpoint(transport Transport, host bool) (Endpoint, error)
+}
@@ -1,10 +1,12 @@
 package ibc
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"reflect"
 
+	chantypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
 	host "github.com/cosmos/ibc-go/v6/modules/core/24-host"
 	"go.uber.org/multierr"
 )
@@ -76,6 +78,32 @@ func (ack PacketAcknowledgement) Validate() error {
 	return multierr.Append(err, ack.Packet.Validate())
 }
 
+// ErrorResult decodes the acknowledgement as a standard ICS-4 acknowledgement,
+// and returns its error message, or an empty string if the acknowledgement is a success.
+// Like DecodeTransferPacketData, both the JSON encoding of ibc-go and protobuf are accepted.
+func (ack PacketAcknowledgement) ErrorResult() (string, error) {
+	var a chantypes.Acknowledgement
+	if trimmed := bytes.TrimSpace(ack.Acknowledgement); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := chantypes.SubModuleCdc.UnmarshalJSON(ack.Acknowledgement, &a); err != nil {
+			return "", fmt.Errorf("unmarshal json acknowledgement: %w", err)
+		}
+	} else if err := a.Unmarshal(ack.Acknowledgement); err != nil {
+		return "", fmt.Errorf("unmarshal proto acknowledgement: %w", err)
+	}
+
+	switch r := a.Response.(type) {
+	case *chantypes.Acknowledgement_Result:
+		return "", nil
+	case *chantypes.Acknowledgement_Error:
+		if r.Error == "" {
+			return "", errors.New("acknowledgement has an empty error")
+		}
+		return r.Error, nil
+	default:
+		return "", errors.New("acknowledgement has neither a result nor an error")
+	}
+}
+
 // PacketTimeout signals a packet was not processed by the counterparty chain.
 // Indicates the sending chain should undo or rollback state.
 // Timeout conditions are block height and timestamp.
@@ -3,6 +3,7 @@ package ibc
 import (
 	"testing"
 
+	chantypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/multierr"
 )
@@ -130,6 +131,38 @@ func TestPacketAcknowledgment_Validate(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPacketAcknowledgement_ErrorResult(t *testing.T) {
+	t.Parallel()
+
+	protoAck := func(a chantypes.Acknowledgement) []byte {
+		bz, err := a.Marshal()
+		require.NoError(t, err)
+		return bz
+	}
+
+	for _, tt := range []struct {
+		Name    string
+		Ack     []byte
+		Want    string
+		WantErr bool
+	}{
+		{Name: "json result", Ack: []byte(`{"result":"AQ=="}`)},
+		{Name: "json error", Ack: []byte(`{"error":"ABCI code: 1: error handling packet: see events for details"}`), Want: "ABCI code: 1: error handling packet: see events for details"},
+		{Name: "proto result", Ack: protoAck(chantypes.NewResultAcknowledgement([]byte{1}))},
+		{Name: "proto error", Ack: protoAck(chantypes.Acknowledgement{Response: &chantypes.Acknowledgement_Error{Error: "invalid receiver"}}), Want: "invalid receiver"},
+		{Name: "empty", Ack: nil, WantErr: true},
+		{Name: "invalid json", Ack: []byte(`{"result":`), WantErr: true},
+	} {
+		got, err := PacketAcknowledgement{Acknowledgement: tt.Ack}.ErrorResult()
+		if tt.WantErr {
+			require.Error(t, err, tt.Name)
+			continue
+		}
+		require.NoError(t, err, tt.Name)
+		require.Equal(t, tt.Want, got, tt.Name)
+	}
+}
+
 func TestPacketTimeout_Validate(t *testing.T) {
 	var timeout PacketTimeout
 	require.Error(t, timeout.Validate())
@@ -0,0 +1,33 @@
+package ibc
+
+import "context"
+
+// ProfileKind is the kind of profiling data served by a ProfileEndpoint.
+type ProfileKind string
+
+const (
+	// ProfileKindPprof endpoints serve the Go net/http/pprof handlers under /debug/pprof/.
+	ProfileKindPprof ProfileKind = "pprof"
+
+	// ProfileKindMetrics endpoints serve Prometheus metrics under /metrics,
+	// such as the wasm execution metrics of substrate nodes.
+	ProfileKindMetrics ProfileKind = "metrics"
+)
+
+// ProfileEndpoint is an HTTP endpoint of a chain node or relayer container serving profiling data.
+type ProfileEndpoint struct {
+	// Name of the container serving the endpoint.
+	Container string
+
+	Kind ProfileKind
+
+	// Address of the endpoint that can be reached by processes on the host machine, e.g. "localhost:49153".
+	HostAddress string
+}
+
+// Profiler is implemented by chains and relayers that serve profiling data while running,
+// so that benchmarks can collect profiles for regression analysis.
+type Profiler interface {
+	// ProfileEndpoints returns the endpoints of the running containers.
+	ProfileEndpoints(ctx context.Context) ([]ProfileEndpoint, error)
+}
@@ -2,7 +2,10 @@ package ibc
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	chantypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
@@ -41,8 +44,8 @@ type Relayer interface {
 	// setup channels, connections, and clients
 	LinkPath(ctx context.Context, rep RelayerExecReporter, pathName string, channelOpts CreateChannelOptions, clientOptions CreateClientOptions) error
 
-	// update path channel filter
-	UpdatePath(ctx context.Context, rep RelayerExecReporter, pathName string, filter ChannelFilter) error
+	// update the channel filter, clients, or connections of an existing path
+	UpdatePath(ctx context.Context, rep RelayerExecReporter, pathName string, opts PathUpdateOptions) error
 
 	// update clients, such as after new genesis
 	UpdateClients(ctx context.Context, rep RelayerExecReporter, pathName string) error
@@ -95,6 +98,115 @@ type Relayer interface {
 	Exec(ctx context.Context, rep RelayerExecReporter, cmd []string, env []string) RelayerExecResult
 }
 
+// RelayDirection is the direction that messages travel along a path,
+// relative to the path's source and destination chains.
+type RelayDirection int
+
+const (
+	// SrcToDst relays messages from the path's source chain to its destination chain.
+	SrcToDst RelayDirection = iota
+
+	// DstToSrc relays messages from the path's destination chain to its source chain.
+	DstToSrc
+)
+
+func (d RelayDirection) String() string {
+	switch d {
+	case SrcToDst:
+		return "src->dst"
+	case DstToSrc:
+		return "dst->src"
+	default:
+		return fmt.Sprintf("RelayDirection(%d)", int(d))
+	}
+}
+
+// DirectionalFlusher is implemented by relayers that can flush only one direction of a channel,
+// leaving the other direction deliberately unrelayed, as needed by timeout tests.
+type DirectionalFlusher interface {
+	// FlushPacketsDirection relays the outstanding packets sent on channelID,
+	// as MsgRecvPacket messages traveling in direction dir, and then returns.
+	// For example, SrcToDst relays packets sent from the source chain to the destination chain.
+	FlushPacketsDirection(ctx context.Context, rep RelayerExecReporter, pathName, channelID string, dir RelayDirection) error
+
+	// FlushAcknowledgementsDirection relays the outstanding acknowledgements on channelID,
+	// as MsgAcknowledgement messages traveling in direction dir, and then returns.
+	// For example, DstToSrc relays acknowledgements written on the destination chain
+	// for packets sent from the source chain.
+	FlushAcknowledgementsDirection(ctx context.Context, rep RelayerExecReporter, pathName, channelID string, dir RelayDirection) error
+}
+
+// PathRegistry is implemented by relayers that keep track of the paths they serve,
+// so that a single relayer process can relay every link of a multi-link topology.
+type PathRegistry interface {
+	// AddPath registers pathName as served by the relayer.
+	// Paths created through GeneratePath are registered automatically;
+	// AddPath is for paths configured by other means, such as a restored relayer configuration.
+	// Adding a registered path again has no effect.
+	AddPath(pathName string)
+
+	// ListPaths returns the registered paths, in the order they were added.
+	ListPaths() []string
+
+	// StartAllPaths starts a single relayer process relaying all registered paths,
+	// like calling StartRelayer with every path returned by ListPaths.
+	StartAllPaths(ctx context.Context, rep RelayerExecReporter) error
+}
+
+// ClientUpdatePolicy controls how a running relayer updates the light clients of a path.
+type ClientUpdatePolicy struct {
+	// ClientsOnly makes the relayer keep the path's clients up to date without relaying any packets on it,
+	// as needed by light client tests where packet relaying would interfere.
+	ClientsOnly bool
+
+	// UpdateInterval is the time after which the relayer updates the path's clients
+	// even if there are no packets to relay. Zero uses the relayer's default.
+	UpdateInterval time.Duration
+}
+
+// ClientUpdateController is implemented by relayers whose client updates can be controlled per path.
+// A single update of a path's clients can be triggered on demand with Relayer.UpdateClients.
+type ClientUpdateController interface {
+	// SetClientUpdatePolicy sets the client update policy of pathName,
+	// taking effect the next time the relayer is started.
+	SetClientUpdatePolicy(ctx context.Context, rep RelayerExecReporter, pathName string, policy ClientUpdatePolicy) error
+}
+
+// ChannelCloser is implemented by relayers that can close a channel.
+// A new channel can then be opened on the same connection with Relayer.CreateChannel.
+type ChannelCloser interface {
+	// CloseChannel closes channelID on portID of the path's source chain,
+	// by submitting MsgChannelCloseInit on the source chain
+	// and relaying MsgChannelCloseConfirm to the counterparty channel on the destination chain.
+	CloseChannel(ctx context.Context, rep RelayerExecReporter, pathName, channelID, portID string) error
+}
+
+// RelayerFeeGranter is implemented by relayers that can pay the fees of their transactions
+// from a fee allowance granted to their wallet, as configured by ChainConfig.RelayerFeeAllowance.
+type RelayerFeeGranter interface {
+	// SetFeeGranter makes the relayer's transactions on the chain chainID pay their fees
+	// from the allowance granted by the account granter.
+	// It must be called before the chain's configuration is added to the relayer,
+	// and returns an error if the relayer cannot pay fees from an allowance.
+	SetFeeGranter(chainID, granter string) error
+}
+
+// RelayerImageResolver is implemented by relayers that run in a docker image,
+// so that reports record exactly which relayer build a test used.
+type RelayerImageResolver interface {
+	// ResolvedImage returns the relayer's image and its digest.
+	ResolvedImage(ctx context.Context) (ResolvedImage, error)
+}
+
+// RPCTransportSelector is implemented by relayers that connect to some chain types over a specific RPC transport.
+// When configuring such a relayer for a chain implementing EndpointProvider,
+// the URL of the chain's endpoint over the selected transport is passed as the rpc address.
+type RPCTransportSelector interface {
+	// RPCTransport returns the transport the relayer uses for chains of chainType,
+	// or an empty Transport to use the chain's GetRPCAddress and GetGRPCAddress.
+	RPCTransport(chainType string) Transport
+}
+
 // RelyaerExecResult holds the details of a call to Relayer.Exec.
 type RelayerExecResult struct {
 	// This type is a redeclaration of dockerutil.ContainerExecResult.
@@ -184,6 +296,31 @@ func (o Order) Validate() error {
 // CreateClientOptions contains the configuration for creating a client.
 type CreateClientOptions struct {
 	TrustingPeriod string
+
+	// Optional fraction of the validator set, e.g. "1/3", that must sign a header for the client to trust it.
+	// Must be between 1/3 and 1. Empty uses the relayer's default.
+	TrustLevel string
+
+	// Optional client state parameters of a grandpa or beefy client tracking a substrate chain.
+	// If nil, the relayer derives them from the substrate chain.
+	Substrate *SubstrateClientState
+}
+
+// SubstrateClientState contains the parameters of the initial client state
+// of a grandpa or beefy client, created on a cosmos chain to track a relay chain or parachain.
+type SubstrateClientState struct {
+	// ID of the tracked parachain, or 0 if the client tracks the relay chain itself.
+	ParaID uint32
+
+	// Hex-encoded, 0x-prefixed genesis hash of the relay chain.
+	RelayChainGenesisHash string
+
+	// ID of the relay chain's current authority set.
+	AuthoritySetID uint64
+
+	// Hex-encoded, 0x-prefixed public keys of the current authority set.
+	// If empty, the relayer queries the authority set with AuthoritySetID.
+	Authorities []string
 }
 
 // DefaultClientOpts returns the default settings for creating clients.
@@ -199,6 +336,67 @@ func (opts CreateClientOptions) Validate() error {
 	if err != nil {
 		return err
 	}
+	if opts.TrustLevel != "" {
+		if err := validateTrustLevel(opts.TrustLevel); err != nil {
+			return err
+		}
+	}
+	if opts.Substrate != nil {
+		if err := opts.Substrate.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTrustLevel checks that trustLevel is a fraction between 1/3 and 1, as ibc-go light clients require.
+func validateTrustLevel(trustLevel string) error {
+	num, den, ok := strings.Cut(trustLevel, "/")
+	if !ok {
+		return fmt.Errorf("trust level %q is not a fraction", trustLevel)
+	}
+	n, err := strconv.ParseUint(num, 10, 64)
+	if err != nil {
+		return fmt.Errorf("trust level %q numerator: %w", trustLevel, err)
+	}
+	d, err := strconv.ParseUint(den, 10, 64)
+	if err != nil {
+		return fmt.Errorf("trust level %q denominator: %w", trustLevel, err)
+	}
+	if d == 0 || 3*n < d || n > d {
+		return fmt.Errorf("trust level %q must be between 1/3 and 1", trustLevel)
+	}
+	return nil
+}
+
+// Validate checks that the hashes and public keys are hex-encoded and of the expected lengths.
+func (s SubstrateClientState) Validate() error {
+	if err := validateHex(s.RelayChainGenesisHash, 32); err != nil {
+		return fmt.Errorf("relay chain genesis hash: %w", err)
+	}
+	for i, authority := range s.Authorities {
+		// grandpa authorities are ed25519 keys, and beefy authorities are compressed ecdsa keys.
+		if err := validateHex(authority, 32); err != nil {
+			if err := validateHex(authority, 33); err != nil {
+				return fmt.Errorf("authority %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateHex checks that s is a 0x-prefixed hex encoding of size bytes.
+func validateHex(s string, size int) error {
+	if !strings.HasPrefix(s, "0x") {
+		return fmt.Errorf("%q is missing the 0x prefix", s)
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return fmt.Errorf("%q: %w", s, err)
+	}
+	if len(b) != size {
+		return fmt.Errorf("%q is %d bytes, expected %d", s, len(b), size)
+	}
 	return nil
 }
 
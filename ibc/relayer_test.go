@@ -1,6 +1,7 @@
 package ibc
 
 import (
+	"strings"
 	"testing"
 
 	chantypes "github.com/cosmos/ibc-go/v6/modules/core/04-channel/types"
@@ -34,3 +35,37 @@ func TestChannelOptsConfigured(t *testing.T) {
 	}
 	require.Error(t, opts.Validate())
 }
+
+func TestClientOptsValidate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, DefaultClientOpts().Validate())
+	require.Error(t, CreateClientOptions{}.Validate())
+
+	opts := DefaultClientOpts()
+	for _, tl := range []string{"1/3", "2/3", "1/1"} {
+		opts.TrustLevel = tl
+		require.NoError(t, opts.Validate(), tl)
+	}
+	for _, tl := range []string{"1/4", "4/3", "1/0", "one/third", "0.33"} {
+		opts.TrustLevel = tl
+		require.Error(t, opts.Validate(), tl)
+	}
+
+	opts = DefaultClientOpts()
+	hash := "0x" + strings.Repeat("ab", 32)
+	opts.Substrate = &SubstrateClientState{
+		ParaID:                2000,
+		RelayChainGenesisHash: hash,
+		AuthoritySetID:        1,
+		Authorities:           []string{hash, "0x02" + strings.Repeat("cd", 32)},
+	}
+	require.NoError(t, opts.Validate())
+
+	opts.Substrate.Authorities = []string{"0xabcd"}
+	require.ErrorContains(t, opts.Validate(), "authority 0")
+
+	opts.Substrate.Authorities = nil
+	opts.Substrate.RelayChainGenesisHash = strings.Repeat("ab", 32)
+	require.ErrorContains(t, opts.Validate(), "missing the 0x prefix")
+}
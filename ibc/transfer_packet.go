@@ -0,0 +1,138 @@
+package ibc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"go.uber.org/multierr"
+)
+
+// DecodeTransferPacketData decodes packet data as an ICS-20 fungible token transfer.
+// ibc-go encodes the packet data as JSON, but other implementations, such as the substrate IBC pallet,
+// may encode it as protobuf, so both encodings are accepted.
+func DecodeTransferPacketData(data []byte) (transfertypes.FungibleTokenPacketData, error) {
+	var ftpd transfertypes.FungibleTokenPacketData
+	if len(data) == 0 {
+		return ftpd, errors.New("empty packet data")
+	}
+
+	if isJSON(data) {
+		// Packets of newer ibc-go versions may have fields, such as the memo, unknown to the pinned packet type,
+		// which encoding/json ignores.
+		if err := json.Unmarshal(data, &ftpd); err != nil {
+			return ftpd, fmt.Errorf("unmarshal json transfer packet data: %w", err)
+		}
+	} else if err := ftpd.Unmarshal(data); err != nil {
+		return ftpd, fmt.Errorf("unmarshal proto transfer packet data: %w", err)
+	}
+
+	if ftpd.Denom == "" {
+		return ftpd, errors.New("packet data is not a fungible token transfer: missing denom")
+	}
+	return ftpd, nil
+}
+
+// DecodeTransferPacketMemo returns the memo of ICS-20 transfer packet data, or an empty string if it has none.
+// The memo is not a field of the pinned ibc-go packet type, so it is read from the encoded packet data:
+// the "memo" field of JSON, or field 5 of protobuf.
+func DecodeTransferPacketMemo(data []byte) (string, error) {
+	if isJSON(data) {
+		var packet struct {
+			Memo string `json:"memo"`
+		}
+		if err := json.Unmarshal(data, &packet); err != nil {
+			return "", fmt.Errorf("unmarshal json transfer packet data: %w", err)
+		}
+		return packet.Memo, nil
+	}
+
+	const memoFieldNumber = 5
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return "", errors.New("invalid protobuf field key in transfer packet data")
+		}
+		data = data[n:]
+		fieldNumber, wireType := key>>3, key&7
+
+		var value []byte
+		switch wireType {
+		case 0: // Varint.
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return "", errors.New("invalid protobuf varint in transfer packet data")
+			}
+		case 1: // 64-bit.
+			n = 8
+		case 2: // Length-delimited.
+			length, m := binary.Uvarint(data)
+			if m <= 0 || uint64(len(data)-m) < length {
+				return "", errors.New("invalid protobuf length in transfer packet data")
+			}
+			value = data[m : m+int(length)]
+			n = m + int(length)
+		case 5: // 32-bit.
+			n = 4
+		default:
+			return "", fmt.Errorf("unsupported protobuf wire type %d in transfer packet data", wireType)
+		}
+		if len(data) < n {
+			return "", errors.New("truncated protobuf transfer packet data")
+		}
+		data = data[n:]
+
+		if fieldNumber == memoFieldNumber && wireType == 2 {
+			return string(value), nil
+		}
+	}
+	return "", nil
+}
+
+// isJSON reports whether packet data is JSON encoded, as ibc-go encodes it, rather than protobuf.
+func isJSON(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// ExpectedTransfer describes the expected contents of an ICS-20 transfer packet.
+// Empty fields are not checked.
+type ExpectedTransfer struct {
+	Denom    string
+	Amount   string
+	Sender   string
+	Receiver string
+	Memo     string
+}
+
+// Check decodes data with DecodeTransferPacketData and returns an error
+// describing every field that differs from the expected transfer.
+func (e ExpectedTransfer) Check(data []byte) error {
+	ftpd, err := DecodeTransferPacketData(data)
+	if err != nil {
+		return err
+	}
+
+	memo, err := DecodeTransferPacketMemo(data)
+	if err != nil {
+		return err
+	}
+
+	var merr error
+	for _, f := range []struct {
+		name, want, got string
+	}{
+		{"denom", e.Denom, ftpd.Denom},
+		{"amount", e.Amount, ftpd.Amount},
+		{"sender", e.Sender, ftpd.Sender},
+		{"receiver", e.Receiver, ftpd.Receiver},
+		{"memo", e.Memo, memo},
+	} {
+		if f.want != "" && f.want != f.got {
+			multierr.AppendInto(&merr, fmt.Errorf("transfer %s: expected %q, got %q", f.name, f.want, f.got))
+		}
+	}
+	return merr
+}
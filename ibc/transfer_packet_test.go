@@ -0,0 +1,49 @@
+package ibc
+
+import (
+	"testing"
+
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTransferPacketData(t *testing.T) {
+	t.Parallel()
+
+	want := transfertypes.NewFungibleTokenPacketData("transfer/channel-0/uatom", "100", "cosmos1sender", "5Receiver")
+
+	protoBz, err := want.Marshal()
+	require.NoError(t, err)
+	// The memo, field 5, is not a field of the pinned packet type, so it is appended as newer ibc-go versions encode it.
+	protoBz = append(protoBz, 5<<3|2, byte(len("hello")))
+	protoBz = append(protoBz, "hello"...)
+
+	jsonBz := []byte(`{"amount":"100","denom":"transfer/channel-0/uatom","memo":"hello","receiver":"5Receiver","sender":"cosmos1sender"}`)
+
+	for name, data := range map[string][]byte{
+		"json":  jsonBz,
+		"proto": protoBz,
+	} {
+		got, err := DecodeTransferPacketData(data)
+		require.NoError(t, err, name)
+		require.Equal(t, want, got, name)
+
+		require.NoError(t, ExpectedTransfer{Denom: want.Denom, Amount: "100", Memo: "hello"}.Check(data), name)
+
+		err = ExpectedTransfer{Amount: "99", Receiver: "5Other", Sender: want.Sender}.Check(data)
+		require.ErrorContains(t, err, `transfer amount: expected "99", got "100"`, name)
+		require.ErrorContains(t, err, `transfer receiver: expected "5Other", got "5Receiver"`, name)
+		require.NotContains(t, err.Error(), "sender", name)
+	}
+
+	memo, err := DecodeTransferPacketMemo(want.GetBytes())
+	require.NoError(t, err)
+	require.Empty(t, memo)
+	require.ErrorContains(t, ExpectedTransfer{Memo: "hello"}.Check(want.GetBytes()), `transfer memo: expected "hello", got ""`)
+
+	_, err = DecodeTransferPacketData(nil)
+	require.Error(t, err)
+
+	_, err = DecodeTransferPacketData([]byte(`{}`))
+	require.ErrorContains(t, err, "missing denom")
+}
@@ -1,6 +1,15 @@
 package ibc
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cosmossdk.io/math"
 	simappparams "github.com/cosmos/cosmos-sdk/simapp/params"
 	"github.com/cosmos/cosmos-sdk/types"
 	ibcexported "github.com/cosmos/ibc-go/v6/modules/core/03-connection/types"
@@ -20,8 +29,21 @@ type ChainConfig struct {
 	Bin string `yaml:"bin"`
 	// Bech32 prefix for chain addresses, e.g. cosmos.
 	Bech32Prefix string `yaml:"bech32-prefix"`
+	// SS58 address format of a substrate chain, e.g. 0 for polkadot, used for polkadot chains only.
+	// Defaults to 42, the generic substrate format.
+	SS58Format *uint16 `yaml:"ss58-format"`
 	// Denomination of native currency, e.g. uatom.
 	Denom string `yaml:"denom"`
+	// Number of decimals of the display unit of Denom, e.g. 6 for uatom, whose display unit is atom.
+	// Defaults to 12 for polkadot chains and to 6 for other chains, see Decimals.
+	CoinDecimals *int64 `yaml:"coin-decimals"`
+	// Display unit of Denom, e.g. atom for uatom, see DisplayDenom.
+	// Defaults to Denom without its "u" prefix, or its "a" prefix for 18 decimals, e.g. DOT for uDOT or evmos for aevmos.
+	CoinDisplayDenom string `yaml:"coin-display-denom"`
+	// Denomination of the staking token, if different from Denom, e.g. for chains that pay fees in another token.
+	BondDenom string `yaml:"bond-denom"`
+	// Denominations besides Denom and BondDenom that genesis validators and accounts, such as the faucet, hold.
+	AdditionalGenesisDenoms []string `yaml:"additional-genesis-denoms"`
 	// Minimum gas prices for sending transactions, in native currency denom.
 	GasPrices string `yaml:"gas-prices"`
 	// Adjustment multiplier for gas fees.
@@ -36,6 +58,336 @@ type ChainConfig struct {
 	ConfigFileOverrides map[string]any
 	// Non-nil will override the encoding config, used for cosmos chains only.
 	EncodingConfig *simappparams.EncodingConfig
+	// Additional arguments for the command that starts each chain node.
+	// For polkadot relay chain nodes, a flag, of the form --flag=value or --flag value,
+	// replaces the default flag of the same name.
+	AdditionalStartArgs []string `yaml:"additional-start-args"`
+	// Log level of the chain's nodes, e.g. debug or trace, passed to each node as its chain type's log flag:
+	// --log_level for cosmos and tendermint nodes, --log for substrate nodes, and RUST_LOG for penumbra nodes.
+	// A chain type specific filter, such as "consensus:debug,*:info" for cosmos or "runtime=trace" for substrate, is passed as is.
+	// Defaults to each chain type's default level.
+	LogLevel string `yaml:"log-level"`
+	// Path on the host to an exported genesis file, e.g. at a non-zero height with existing IBC state,
+	// for the chain to start from instead of a newly generated genesis.
+	// Validator gentxs are not collected for an imported genesis, so its validator set must be
+	// signed for by the chain's validators, see GenesisValidatorKeyFiles.
+	GenesisFile string `yaml:"genesis-file"`
+	// Paths on the host to the priv_validator_key.json files of the validators in GenesisFile,
+	// assigned to the chain's validators in order.
+	GenesisValidatorKeyFiles []string `yaml:"genesis-validator-key-files"`
+	// When provided, modifies the command that starts each chain node container,
+	// e.g. to run the node under strace or a debugger, or with profiling enabled.
+	// See RunUnder, RunInShell, and WithStartEnv.
+	ModifyStartCommand func(ChainConfig, StartCommand) StartCommand
+	// Which container ports of the chain's nodes are published on the host.
+	// By default, every port that a node exposes is published.
+	HostPorts HostPorts `yaml:"host-ports"`
+	// Features the chain supports in addition to those of its chain type,
+	// e.g. wasm-clients for a cosmos chain whose binary includes the 08-wasm light client module.
+	AdditionalFeatures []ChainFeature `yaml:"additional-features"`
+	// Version of ibc-go that the chain's binary is built with, e.g. "v6.1.0", used for cosmos chains only
+	// to derive which of the ibc-go features, such as interchain accounts, the chain supports.
+	IBCGoVersion string `yaml:"ibc-go-version"`
+	// Enable the REST API (LCD) server of each node on port 1317, used for cosmos chains only.
+	// See GetHostAPIAddress.
+	EnableAPI bool `yaml:"enable-api"`
+	// Serve the Go pprof handlers of each node on port 6060, for debugging and Interchain.CollectProfiles,
+	// used for cosmos chains only.
+	EnablePprof bool `yaml:"enable-pprof"`
+	// Minimum gas prices that each node accepts for transactions, e.g. "0.01uatom",
+	// set as minimum-gas-prices in app.toml, used for cosmos chains only.
+	// GasPrices are raised to match once the chain has started.
+	MinGasPrices string `yaml:"min-gas-prices"`
+	// Minimum gas prices of the chain's globalfee module, e.g. "0.01uatom", set in genesis.
+	// Used for cosmos chains with a globalfee module only, such as gaia.
+	GlobalFeeMinGasPrices string `yaml:"global-fee-min-gas-prices"`
+	// If set, the faucet grants each relayer wallet this fee allowance at genesis,
+	// so that relaying on a fee-enforcing chain draws from the faucet.
+	// Used for chains implementing GenesisFeeGranter, with relayers implementing RelayerFeeGranter, only;
+	// Interchain.Build fails otherwise. The cosmos relayer (rly) cannot pay fees from an allowance.
+	RelayerFeeAllowance *FeeAllowance `yaml:"relayer-fee-allowance"`
+	// Gas of the relayer's transactions on the chain, for chains with unusual gas regimes.
+	RelayerGas RelayerGas `yaml:"relayer-gas"`
+	// If set, each validator signs with its key held by a remote signer,
+	// run in sidecar containers connected to the validator's priv-validator socket.
+	// Used for cosmos chains only.
+	RemoteSigner *RemoteSigner `yaml:"remote-signer"`
+	// Pruning of the state and blocks kept by the chain's nodes. Zero values keep the nodes' defaults.
+	Pruning Pruning `yaml:"pruning"`
+	// If set, overrides Pruning for the chain's full nodes,
+	// e.g. to keep all state on full nodes while validators prune it.
+	// Used for cosmos chains only.
+	FullNodePruning *Pruning `yaml:"full-node-pruning"`
+	// Peer-to-peer topology of the chain's nodes. If nil, every node peers with every other node.
+	// Used for cosmos chains only.
+	P2P *P2PTopology `yaml:"p2p"`
+	// If set, each validator sits behind this many sentry full nodes, replacing the chain's full nodes,
+	// with the P2P topology returned by SentryTopology. Relayers then connect to the chain through a sentry.
+	// Used for cosmos chains only.
+	SentriesPerValidator int `yaml:"sentries-per-validator"`
+	// If set, the genesis time of the chain, before which its nodes produce no blocks.
+	// A genesis time in the future delays the chain's first block until then, and Start returns once blocks are produced,
+	// unless StartBeforeGenesis is set, so chains given the same genesis time start at the same moment.
+	// Used for cosmos chains only; other chains fail to start if it is set.
+	GenesisTime time.Time `yaml:"genesis-time"`
+	// If true, Start returns once the chain's nodes run, without waiting for the first blocks,
+	// e.g. to verify the behavior of relayers and clients before a future GenesisTime.
+	// Call the chain's WaitForGenesis method before relying on blocks being produced.
+	// Gas prices required by a feemarket module are not discovered, as they cannot be queried before the first blocks.
+	// Used for cosmos chains only; other chains fail to start if it is set.
+	StartBeforeGenesis bool `yaml:"start-before-genesis"`
+	// Name of the built-in chain spec that the chain's nodes generate the chain's genesis from, e.g. rococo-local.
+	// Defaults to ChainID. Setting it runs several independent chains of the same chain spec in one test under distinct chain IDs,
+	// e.g. two relay chains to bridge.
+	// Used for polkadot chains only.
+	BuiltinChainSpec string `yaml:"builtin-chain-spec"`
+}
+
+// P2PTopology defines which nodes of a chain connect to each other,
+// e.g. to partition a chain, or to hide validators behind sentry nodes.
+// Nodes are named as by TopologyNodeName, e.g. "val-0" for the first validator and "fn-1" for the second full node.
+type P2PTopology struct {
+	// Persistent peers of each node. A node without persistent peers connects only to seeds,
+	// and to the nodes that list it as a persistent peer or learn of it through peer exchange.
+	Peers map[string][]string `yaml:"peers"`
+	// Seeds of every other node, which the nodes learn more peers from through peer exchange.
+	Seeds []string `yaml:"seeds"`
+	// Nodes whose peer exchange is disabled, so that they connect to no peers beyond their persistent peers
+	// and the nodes that list them as persistent peers.
+	DisablePEX []string `yaml:"disable-pex"`
+	// Nodes whose addresses the other nodes never gossip, such as validators behind sentry nodes.
+	Private []string `yaml:"private"`
+}
+
+// SentryTopology returns the topology of a chain whose numValidators validators each sit behind sentriesPerValidator sentry full nodes,
+// so that the chain has numValidators*sentriesPerValidator full nodes.
+// Validator i peers only with its sentries, full nodes i*sentriesPerValidator to (i+1)*sentriesPerValidator-1,
+// which peer with every other sentry, and keep the validators' addresses private.
+func SentryTopology(numValidators, sentriesPerValidator int) *P2PTopology {
+	t := &P2PTopology{Peers: make(map[string][]string)}
+	var sentries []string
+	for i := 0; i < numValidators*sentriesPerValidator; i++ {
+		sentries = append(sentries, TopologyNodeName(false, i))
+	}
+	for i := 0; i < numValidators; i++ {
+		val := TopologyNodeName(true, i)
+		own := sentries[i*sentriesPerValidator : (i+1)*sentriesPerValidator]
+		t.Peers[val] = append([]string(nil), own...)
+		for _, sentry := range own {
+			peers := []string{val}
+			for _, other := range sentries {
+				if other != sentry {
+					peers = append(peers, other)
+				}
+			}
+			t.Peers[sentry] = peers
+		}
+		t.DisablePEX = append(t.DisablePEX, val)
+		t.Private = append(t.Private, val)
+	}
+	return t
+}
+
+// TopologyNodeName returns the name in a P2PTopology of the validator, or full node, at index.
+func TopologyNodeName(validator bool, index int) string {
+	if validator {
+		return fmt.Sprintf("val-%d", index)
+	}
+	return fmt.Sprintf("fn-%d", index)
+}
+
+// Validate returns an error if the topology names a node that a chain of numValidators and numFullNodes does not have,
+// or a node that is its own persistent peer.
+func (t P2PTopology) Validate(numValidators, numFullNodes int) error {
+	names := make(map[string]bool, numValidators+numFullNodes)
+	for i := 0; i < numValidators; i++ {
+		names[TopologyNodeName(true, i)] = true
+	}
+	for i := 0; i < numFullNodes; i++ {
+		names[TopologyNodeName(false, i)] = true
+	}
+	check := func(field, name string) error {
+		if !names[name] {
+			return fmt.Errorf("%s names unknown node %q", field, name)
+		}
+		return nil
+	}
+	// Check nodes in order, so that the same error is reported for the same topology.
+	nodes := make([]string, 0, len(t.Peers))
+	for name := range t.Peers {
+		nodes = append(nodes, name)
+	}
+	sort.Strings(nodes)
+	for _, name := range nodes {
+		if err := check("peers", name); err != nil {
+			return err
+		}
+		for _, peer := range t.Peers[name] {
+			if err := check("peers of "+name, peer); err != nil {
+				return err
+			}
+			if peer == name {
+				return fmt.Errorf("node %q is its own peer", name)
+			}
+		}
+	}
+	for _, name := range t.Seeds {
+		if err := check("seeds", name); err != nil {
+			return err
+		}
+	}
+	for _, name := range t.DisablePEX {
+		if err := check("disable-pex", name); err != nil {
+			return err
+		}
+	}
+	for _, name := range t.Private {
+		if err := check("private", name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the topology.
+func (t P2PTopology) Clone() *P2PTopology {
+	x := P2PTopology{
+		Seeds:      append([]string(nil), t.Seeds...),
+		DisablePEX: append([]string(nil), t.DisablePEX...),
+		Private:    append([]string(nil), t.Private...),
+	}
+	if t.Peers != nil {
+		x.Peers = make(map[string][]string, len(t.Peers))
+		for name, peers := range t.Peers {
+			x.Peers[name] = append([]string(nil), peers...)
+		}
+	}
+	return &x
+}
+
+// Pruning configures the state and blocks kept by a node.
+type Pruning struct {
+	// If true, the node keeps all state and blocks, ignoring KeepRecent and KeepBlocks.
+	Archive bool `yaml:"archive"`
+	// Number of recent heights whose state is kept,
+	// set as pruning-keep-recent for cosmos nodes, and --pruning for substrate nodes.
+	KeepRecent uint64 `yaml:"keep-recent"`
+	// Number of blocks between prunings of state, set as pruning-interval for cosmos nodes.
+	// Defaults to 10 when KeepRecent is set.
+	Interval uint64 `yaml:"interval"`
+	// Number of recent blocks kept,
+	// set as min-retain-blocks for cosmos nodes, and --blocks-pruning for substrate nodes that support it.
+	KeepBlocks uint64 `yaml:"keep-blocks"`
+}
+
+// NodePruning returns the pruning of a validator, or of a full node if validator is false.
+func (c ChainConfig) NodePruning(validator bool) Pruning {
+	if !validator && c.FullNodePruning != nil {
+		return *c.FullNodePruning
+	}
+	return c.Pruning
+}
+
+// Types of RemoteSigner.
+const (
+	// Horcrux signer, either a single signer or a threshold cluster of cosigners.
+	RemoteSignerHorcrux = "horcrux"
+	// Tendermint KMS with a softsign key.
+	RemoteSignerTMKMS = "tmkms"
+)
+
+// RemoteSigner configures the remote signer of each validator of a chain.
+type RemoteSigner struct {
+	// Type of signer, RemoteSignerHorcrux or RemoteSignerTMKMS.
+	Type string `yaml:"type"`
+	// Image of the signer. Optional for horcrux, which defaults to a release of horcrux.
+	Image DockerImage `yaml:"image"`
+	// Number of horcrux cosigners sharing the validator's key. If zero, horcrux runs as a single signer.
+	Shards int `yaml:"shards"`
+	// Number of horcrux cosigners that must sign each block, between 2 and Shards.
+	Threshold int `yaml:"threshold"`
+}
+
+// Validate returns an error if the remote signer is misconfigured.
+func (s RemoteSigner) Validate() error {
+	switch s.Type {
+	case RemoteSignerHorcrux:
+		if s.Shards == 0 && s.Threshold == 0 {
+			return nil
+		}
+		if s.Threshold < 2 || s.Threshold > s.Shards {
+			return fmt.Errorf("horcrux threshold %d must be between 2 and the %d shards", s.Threshold, s.Shards)
+		}
+	case RemoteSignerTMKMS:
+		if s.Image.Repository == "" {
+			return errors.New("tmkms remote signer needs an image")
+		}
+		if s.Shards > 0 || s.Threshold > 0 {
+			return errors.New("tmkms remote signer does not support shards")
+		}
+	default:
+		return fmt.Errorf("unknown remote signer type %q", s.Type)
+	}
+	return nil
+}
+
+// RelayerGas configures the gas of a relayer's transactions on a chain.
+// Zero values keep the relayer's defaults.
+// DefaultGas, MaxGas and ExtensionOptions require the relayer.ExtensionOptions capability;
+// relayers without it fail to add the chain when they are set.
+type RelayerGas struct {
+	// Gas of transactions that are not simulated, and minimum gas of simulated transactions.
+	DefaultGas uint64 `yaml:"default-gas"`
+	// Maximum gas of a transaction.
+	MaxGas uint64 `yaml:"max-gas"`
+	// Multiplier of the simulated gas of transactions. Defaults to GasAdjustment.
+	GasMultiplier float64 `yaml:"gas-multiplier"`
+	// Extension options of the relayer's transactions, such as the dynamic fee option of ethermint chains.
+	ExtensionOptions []ExtensionOption `yaml:"extension-options"`
+}
+
+// Multiplier returns the multiplier of the simulated gas of the relayer's transactions on a chain
+// configured with gasAdjustment.
+func (g RelayerGas) Multiplier(gasAdjustment float64) float64 {
+	if g.GasMultiplier > 0 {
+		return g.GasMultiplier
+	}
+	return gasAdjustment
+}
+
+// ExtensionOption is an extension option of transactions.
+type ExtensionOption struct {
+	// Type of the option, as named by the relayer, e.g. "ethermint_dynamic_fee".
+	Type string `yaml:"type"`
+	// Value of the option, e.g. the max priority price of a dynamic fee.
+	Value string `yaml:"value"`
+}
+
+// FeeAllowance is a basic feegrant allowance.
+type FeeAllowance struct {
+	// Maximum amount that the grantee may spend on fees, e.g. "1000000uatom". Unlimited if empty.
+	SpendLimit string `yaml:"spend-limit"`
+}
+
+// GenesisFeeAllowance is a FeeAllowance from Granter to Grantee created at genesis.
+type GenesisFeeAllowance struct {
+	Granter, Grantee string
+
+	FeeAllowance
+}
+
+// HostPorts selects which container ports of a chain's nodes are published on the host.
+//
+// A port that is not published is addressed through the node's hostname on the docker network,
+// including by GetHostRPCAddress and GetHostGRPCAddress,
+// so the test process must then run in the docker network, e.g. in a CI job container attached to it.
+type HostPorts struct {
+	// If true, no ports are published, and nodes are only reachable from the docker network.
+	InternalOnly bool `yaml:"internal-only"`
+
+	// If set, only these container ports, such as "26657/tcp", are published.
+	// The ports must be exposed by the node. Ignored if InternalOnly is true.
+	Publish []string `yaml:"publish"`
 }
 
 func (c ChainConfig) Clone() ChainConfig {
@@ -43,9 +395,159 @@ func (c ChainConfig) Clone() ChainConfig {
 	images := make([]DockerImage, len(c.Images))
 	copy(images, c.Images)
 	x.Images = images
+	x.AdditionalGenesisDenoms = append([]string(nil), c.AdditionalGenesisDenoms...)
+	x.GenesisValidatorKeyFiles = append([]string(nil), c.GenesisValidatorKeyFiles...)
+	x.HostPorts.Publish = append([]string(nil), c.HostPorts.Publish...)
+	x.AdditionalFeatures = append([]ChainFeature(nil), c.AdditionalFeatures...)
+	x.RelayerGas.ExtensionOptions = append([]ExtensionOption(nil), c.RelayerGas.ExtensionOptions...)
+	if c.CoinDecimals != nil {
+		decimals := *c.CoinDecimals
+		x.CoinDecimals = &decimals
+	}
+	if c.SS58Format != nil {
+		format := *c.SS58Format
+		x.SS58Format = &format
+	}
+	if c.RelayerFeeAllowance != nil {
+		allowance := *c.RelayerFeeAllowance
+		x.RelayerFeeAllowance = &allowance
+	}
+	if c.RemoteSigner != nil {
+		signer := *c.RemoteSigner
+		x.RemoteSigner = &signer
+	}
+	if c.FullNodePruning != nil {
+		pruning := *c.FullNodePruning
+		x.FullNodePruning = &pruning
+	}
+	if c.P2P != nil {
+		x.P2P = c.P2P.Clone()
+	}
 	return x
 }
 
+// Decimals returns the number of decimals of the display unit of Denom:
+// CoinDecimals if set, or else the default of the chain type.
+func (c ChainConfig) Decimals() int64 {
+	if c.CoinDecimals != nil {
+		return *c.CoinDecimals
+	}
+	if c.Type == "polkadot" {
+		return 12
+	}
+	return 6
+}
+
+// DisplayDenom returns the display unit of Denom: CoinDisplayDenom if set, or else Denom without its prefix,
+// "u" for micro units, or "a" for atto units of 18 decimals.
+func (c ChainConfig) DisplayDenom() string {
+	if c.CoinDisplayDenom != "" {
+		return c.CoinDisplayDenom
+	}
+	switch {
+	case len(c.Denom) > 1 && c.Denom[0] == 'u':
+		return c.Denom[1:]
+	case len(c.Denom) > 1 && c.Denom[0] == 'a' && c.Decimals() == 18:
+		return c.Denom[1:]
+	}
+	return c.Denom
+}
+
+// BaseAmount converts an amount of Denom in display units, e.g. "1.5" atom, to base units, e.g. 1500000 uatom,
+// according to the chain's Decimals.
+// The amount must be a non-negative decimal number with at most Decimals fractional digits.
+func (c ChainConfig) BaseAmount(display string) (math.Int, error) {
+	return baseAmount(display, c.Decimals(), c.Denom)
+}
+
+// DisplayAmount converts an amount of Denom in base units, e.g. 1500000 uatom, to display units, e.g. "1.5" atom,
+// according to the chain's Decimals.
+func (c ChainConfig) DisplayAmount(base math.Int) string {
+	return displayAmount(base, c.Decimals())
+}
+
+// baseAmount converts display, an amount in display units, to base units of denom with decimals.
+func baseAmount(display string, decimals int64, denom string) (math.Int, error) {
+	whole, frac := display, ""
+	if i := strings.IndexByte(display, '.'); i >= 0 {
+		whole, frac = display[:i], display[i+1:]
+	}
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return math.Int{}, fmt.Errorf("invalid display amount %q", display)
+	}
+	if int64(len(frac)) > decimals {
+		return math.Int{}, fmt.Errorf("display amount %q has more than %d decimals of %s", display, decimals, denom)
+	}
+	digits := strings.TrimLeft(whole+frac+strings.Repeat("0", int(decimals)-len(frac)), "0")
+	if digits == "" {
+		return math.ZeroInt(), nil
+	}
+	amount, ok := math.NewIntFromString(digits)
+	if !ok {
+		return math.Int{}, fmt.Errorf("invalid display amount %q", display)
+	}
+	return amount, nil
+}
+
+// displayAmount formats base, an amount in base units, in display units with decimals, without trailing zeros.
+func displayAmount(base math.Int, decimals int64) string {
+	digits, sign := base.String(), ""
+	if base.IsNegative() {
+		digits, sign = digits[1:], "-"
+	}
+	if pad := int(decimals) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	whole, frac := digits[:len(digits)-int(decimals)], strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// isDigits reports whether s consists only of decimal digits.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// StakingDenom returns the denomination of the staking token: BondDenom if set, or else Denom.
+func (c ChainConfig) StakingDenom() string {
+	if c.BondDenom != "" {
+		return c.BondDenom
+	}
+	return c.Denom
+}
+
+// GenesisDenoms returns the unique denominations held at genesis:
+// Denom, BondDenom, and AdditionalGenesisDenoms, in that order.
+func (c ChainConfig) GenesisDenoms() []string {
+	denoms := []string{c.Denom}
+	seen := map[string]bool{c.Denom: true}
+	for _, d := range append([]string{c.BondDenom}, c.AdditionalGenesisDenoms...) {
+		if d == "" || seen[d] {
+			continue
+		}
+		seen[d] = true
+		denoms = append(denoms, d)
+	}
+	return denoms
+}
+
+// HasAdditionalFeature reports whether f is one of the chain's AdditionalFeatures.
+func (c ChainConfig) HasAdditionalFeature(f ChainFeature) bool {
+	for _, af := range c.AdditionalFeatures {
+		if af == f {
+			return true
+		}
+	}
+	return false
+}
+
 func (c ChainConfig) MergeChainSpecConfig(other ChainConfig) ChainConfig {
 	// Make several in-place modifications to c,
 	// which is a value, not a reference,
@@ -73,10 +575,32 @@ func (c ChainConfig) MergeChainSpecConfig(other ChainConfig) ChainConfig {
 		c.Bech32Prefix = other.Bech32Prefix
 	}
 
+	if other.SS58Format != nil {
+		format := *other.SS58Format
+		c.SS58Format = &format
+	}
+
 	if other.Denom != "" {
 		c.Denom = other.Denom
 	}
 
+	if other.CoinDecimals != nil {
+		decimals := *other.CoinDecimals
+		c.CoinDecimals = &decimals
+	}
+
+	if other.CoinDisplayDenom != "" {
+		c.CoinDisplayDenom = other.CoinDisplayDenom
+	}
+
+	if other.BondDenom != "" {
+		c.BondDenom = other.BondDenom
+	}
+
+	if len(other.AdditionalGenesisDenoms) > 0 {
+		c.AdditionalGenesisDenoms = append([]string(nil), other.AdditionalGenesisDenoms...)
+	}
+
 	if other.GasPrices != "" {
 		c.GasPrices = other.GasPrices
 	}
@@ -103,9 +627,125 @@ func (c ChainConfig) MergeChainSpecConfig(other ChainConfig) ChainConfig {
 		c.EncodingConfig = other.EncodingConfig
 	}
 
+	if other.LogLevel != "" {
+		c.LogLevel = other.LogLevel
+	}
+
+	if len(other.AdditionalStartArgs) > 0 {
+		c.AdditionalStartArgs = append([]string(nil), other.AdditionalStartArgs...)
+	}
+
+	if other.GenesisFile != "" {
+		c.GenesisFile = other.GenesisFile
+	}
+
+	if other.ModifyStartCommand != nil {
+		c.ModifyStartCommand = other.ModifyStartCommand
+	}
+
+	if len(other.GenesisValidatorKeyFiles) > 0 {
+		c.GenesisValidatorKeyFiles = append([]string(nil), other.GenesisValidatorKeyFiles...)
+	}
+
+	if other.HostPorts.InternalOnly || len(other.HostPorts.Publish) > 0 {
+		c.HostPorts = HostPorts{
+			InternalOnly: other.HostPorts.InternalOnly,
+			Publish:      append([]string(nil), other.HostPorts.Publish...),
+		}
+	}
+
+	if len(other.AdditionalFeatures) > 0 {
+		c.AdditionalFeatures = append([]ChainFeature(nil), other.AdditionalFeatures...)
+	}
+
+	if other.IBCGoVersion != "" {
+		c.IBCGoVersion = other.IBCGoVersion
+	}
+
+	if other.EnableAPI {
+		c.EnableAPI = true
+	}
+
+	if other.EnablePprof {
+		c.EnablePprof = true
+	}
+
+	if other.MinGasPrices != "" {
+		c.MinGasPrices = other.MinGasPrices
+	}
+
+	if other.GlobalFeeMinGasPrices != "" {
+		c.GlobalFeeMinGasPrices = other.GlobalFeeMinGasPrices
+	}
+
+	if other.RelayerFeeAllowance != nil {
+		allowance := *other.RelayerFeeAllowance
+		c.RelayerFeeAllowance = &allowance
+	}
+
+	if other.RelayerGas.DefaultGas > 0 {
+		c.RelayerGas.DefaultGas = other.RelayerGas.DefaultGas
+	}
+
+	if other.RelayerGas.MaxGas > 0 {
+		c.RelayerGas.MaxGas = other.RelayerGas.MaxGas
+	}
+
+	if other.RelayerGas.GasMultiplier > 0 {
+		c.RelayerGas.GasMultiplier = other.RelayerGas.GasMultiplier
+	}
+
+	if len(other.RelayerGas.ExtensionOptions) > 0 {
+		c.RelayerGas.ExtensionOptions = append([]ExtensionOption(nil), other.RelayerGas.ExtensionOptions...)
+	}
+
+	if other.RemoteSigner != nil {
+		signer := *other.RemoteSigner
+		c.RemoteSigner = &signer
+	}
+
+	if other.Pruning != (Pruning{}) {
+		c.Pruning = other.Pruning
+	}
+
+	if other.FullNodePruning != nil {
+		pruning := *other.FullNodePruning
+		c.FullNodePruning = &pruning
+	}
+
+	if other.P2P != nil {
+		c.P2P = other.P2P.Clone()
+	}
+
+	if other.SentriesPerValidator > 0 {
+		c.SentriesPerValidator = other.SentriesPerValidator
+	}
+
+	if !other.GenesisTime.IsZero() {
+		c.GenesisTime = other.GenesisTime
+	}
+
+	if other.StartBeforeGenesis {
+		c.StartBeforeGenesis = true
+	}
+
+	if other.BuiltinChainSpec != "" {
+		c.BuiltinChainSpec = other.BuiltinChainSpec
+	}
+
 	return c
 }
 
+// StartCommand returns the command that starts a chain node container,
+// given the node's default command cmd and environment env, as modified by ModifyStartCommand.
+func (c ChainConfig) StartCommand(cmd []string, env ...string) StartCommand {
+	start := StartCommand{Cmd: cmd, Env: env}
+	if c.ModifyStartCommand != nil {
+		start = c.ModifyStartCommand(c, start)
+	}
+	return start
+}
+
 // IsFullyConfigured reports whether all required fields have been set on c.
 // It is possible for some fields, such as GasAdjustment and NoHostMount,
 // to be their respective zero values and for IsFullyConfigured to still report true.
@@ -121,25 +761,149 @@ func (c ChainConfig) IsFullyConfigured() bool {
 		c.TrustingPeriod != ""
 }
 
+// StartCommand is the command that starts a chain node container.
+type StartCommand struct {
+	Cmd []string
+
+	// Additional environment variables of the container, in the format "MY_ENV_VAR=value".
+	Env []string
+}
+
+// RunUnder returns a ModifyStartCommand function that runs the start command as the arguments of prefix,
+// e.g. RunUnder("strace", "-f", "-o", "/tmp/strace.log") in an image that contains strace.
+func RunUnder(prefix ...string) func(ChainConfig, StartCommand) StartCommand {
+	return func(_ ChainConfig, start StartCommand) StartCommand {
+		start.Cmd = append(append([]string(nil), prefix...), start.Cmd...)
+		return start
+	}
+}
+
+// RunInShell returns a ModifyStartCommand function that runs the start command with sh -c,
+// after the shell commands in prelude, e.g. "ulimit -c unlimited".
+func RunInShell(prelude string) func(ChainConfig, StartCommand) StartCommand {
+	return func(_ ChainConfig, start StartCommand) StartCommand {
+		quoted := make([]string, len(start.Cmd))
+		for i, arg := range start.Cmd {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		start.Cmd = []string{"sh", "-c", prelude + " && exec " + strings.Join(quoted, " ")}
+		return start
+	}
+}
+
+// WithStartEnv returns a ModifyStartCommand function that adds env to the start command's environment,
+// e.g. WithStartEnv("GODEBUG=gctrace=1").
+func WithStartEnv(env ...string) func(ChainConfig, StartCommand) StartCommand {
+	return func(_ ChainConfig, start StartCommand) StartCommand {
+		start.Env = append(append([]string(nil), start.Env...), env...)
+		return start
+	}
+}
+
+// ResolvedChainConfig is the effective configuration of a started chain,
+// after chain factory resolution, config merging, and image pulls.
+type ResolvedChainConfig struct {
+	Type         string
+	Name         string
+	ChainID      string
+	Denom        string
+	Bech32Prefix string
+
+	// Images run by the chain's nodes.
+	Images []ResolvedImage
+
+	// Addresses of the chain's RPC and gRPC ports that can be reached by processes on the host machine.
+	HostRPCAddress  string
+	HostGRPCAddress string
+}
+
+// ResolvedImage is a docker image with the digest identifying its exact content.
+type ResolvedImage struct {
+	DockerImage
+
+	// Repository digest of the image, e.g. "ghcr.io/strangelove-ventures/heighliner/gaia@sha256:...",
+	// or the image ID of a locally built image.
+	Digest string
+}
+
 type DockerImage struct {
 	Repository string `yaml:"repository"`
 	Version    string `yaml:"version"`
 	UidGid     string `yaml:"uid-gid"`
+
+	// Platform to pull and run the image for, e.g. "linux/amd64" or "linux/arm64".
+	// If empty, the Docker daemon's default platform is used.
+	Platform string `yaml:"platform"`
+
+	// Content digest that pins the image of Version, e.g. "sha256:...",
+	// so that the image is the same however the tag moves.
+	Digest string `yaml:"digest"`
 }
 
 // Ref returns the reference to use when e.g. creating a container.
 func (i DockerImage) Ref() string {
-	if i.Version == "" {
-		return i.Repository + ":latest"
+	return i.Repository + ":" + i.Tag()
+}
+
+// Tag returns the part of Ref after the repository, for APIs taking a repository and tag separately.
+// The tag of an image pinned by Digest includes the digest, through which docker resolves the image.
+func (i DockerImage) Tag() string {
+	tag := i.Version
+	if tag == "" {
+		tag = "latest"
+	}
+	if i.Digest != "" {
+		tag += "@" + i.Digest
+	}
+	return tag
+}
+
+// WithVersion returns the image with version, unpinned from its Digest if version is another version.
+func (i DockerImage) WithVersion(version string) DockerImage {
+	if version != i.Version {
+		i.Digest = ""
+	}
+	i.Version = version
+	return i
+}
+
+// dockerTagPattern matches the tags that docker accepts for images.
+var dockerTagPattern = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
+// dockerDigestPattern matches the sha256 content digests of images.
+var dockerDigestPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// Validate returns an error if the image has no repository, if its version is not a valid docker tag,
+// if its digest is not a sha256 digest, or if its UidGid is not of the form uid:gid, e.g. "1025:1025".
+func (i DockerImage) Validate() error {
+	if i.Repository == "" {
+		return errors.New("image repository must not be empty")
+	}
+	if i.Version != "" && !dockerTagPattern.MatchString(i.Version) {
+		return fmt.Errorf("image %s: version %q is not a valid docker tag", i.Repository, i.Version)
 	}
+	if i.Digest != "" && !dockerDigestPattern.MatchString(i.Digest) {
+		return fmt.Errorf("image %s: digest %q is not of the form sha256:<64 hex digits>", i.Repository, i.Digest)
+	}
+	if i.UidGid != "" {
+		uid, gid, ok := strings.Cut(i.UidGid, ":")
+		if !ok || !isUint(uid) || !isUint(gid) {
+			return fmt.Errorf("image %s: uid-gid %q must be of the form uid:gid, e.g. \"1025:1025\"", i.Repository, i.UidGid)
+		}
+	}
+	return nil
+}
 
-	return i.Repository + ":" + i.Version
+// isUint reports whether s is a decimal unsigned integer.
+func isUint(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 32)
+	return err == nil
 }
 
 type WalletAmount struct {
 	Address string
 	Denom   string
-	Amount  int64
+	Amount  math.Int
 }
 
 type IBCTimeout struct {
@@ -188,6 +952,16 @@ func (w *Wallet) Bech32Address(bech32Prefix string) string {
 	return types.MustBech32ifyAddressBytes(bech32Prefix, []byte(w.Address))
 }
 
+// ChainAddress returns the address of w in the address format of the chain with cfg:
+// bech32 with the chain's Bech32Prefix, or Address as is for chains without one,
+// such as substrate chains, whose addresses are SS58 encoded.
+func (w *Wallet) ChainAddress(cfg ChainConfig) string {
+	if cfg.Bech32Prefix == "" {
+		return w.Address
+	}
+	return w.Bech32Address(cfg.Bech32Prefix)
+}
+
 type RelayerImplementation int64
 
 const (
@@ -201,3 +975,14 @@ type ChannelFilter struct {
 	Rule        string
 	ChannelList []string
 }
+
+// PathUpdateOptions contains the settings to change on an existing relayer path.
+// Unset fields are left unchanged.
+type PathUpdateOptions struct {
+	ChannelFilter *ChannelFilter
+
+	// Explicit clients and connections for the path to use on either chain,
+	// e.g. to bind the relayer to clients created manually rather than through LinkPath.
+	SrcClientID, SrcConnectionID string
+	DstClientID, DstConnectionID string
+}
@@ -0,0 +1,360 @@
+package ibc
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainConfig_GenesisDenoms(t *testing.T) {
+	t.Parallel()
+
+	cfg := ChainConfig{Denom: "uatom"}
+	require.Equal(t, "uatom", cfg.StakingDenom())
+	require.Equal(t, []string{"uatom"}, cfg.GenesisDenoms())
+
+	cfg.BondDenom = "ustake"
+	cfg.AdditionalGenesisDenoms = []string{"ufee", "uatom", "ustake", "ufee"}
+	require.Equal(t, "ustake", cfg.StakingDenom())
+	require.Equal(t, []string{"uatom", "ustake", "ufee"}, cfg.GenesisDenoms())
+
+	merged := ChainConfig{Denom: "uosmo"}.MergeChainSpecConfig(cfg)
+	require.Equal(t, "ustake", merged.BondDenom)
+	require.Equal(t, cfg.AdditionalGenesisDenoms, merged.AdditionalGenesisDenoms)
+
+	// Clones do not share the additional denoms.
+	clone := cfg.Clone()
+	clone.AdditionalGenesisDenoms[0] = "uother"
+	require.Equal(t, "ufee", cfg.AdditionalGenesisDenoms[0])
+}
+
+func TestChainConfig_GenesisFile(t *testing.T) {
+	t.Parallel()
+
+	cfg := ChainConfig{
+		GenesisFile:              "/tmp/exported-genesis.json",
+		GenesisValidatorKeyFiles: []string{"/tmp/val0.json"},
+	}
+
+	merged := ChainConfig{GenesisFile: "/tmp/other.json"}.MergeChainSpecConfig(cfg)
+	require.Equal(t, cfg.GenesisFile, merged.GenesisFile)
+	require.Equal(t, cfg.GenesisValidatorKeyFiles, merged.GenesisValidatorKeyFiles)
+
+	// Unset fields do not clear the imported genesis.
+	merged = cfg.MergeChainSpecConfig(ChainConfig{})
+	require.Equal(t, cfg.GenesisFile, merged.GenesisFile)
+
+	clone := cfg.Clone()
+	clone.GenesisValidatorKeyFiles[0] = "/tmp/other.json"
+	require.Equal(t, "/tmp/val0.json", cfg.GenesisValidatorKeyFiles[0])
+}
+
+func TestChainConfig_StartCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := []string{"gaiad", "start", "--home", "/var/cosmos-chain/gaia"}
+
+	t.Run("unmodified", func(t *testing.T) {
+		require.Equal(t, StartCommand{Cmd: cmd}, ChainConfig{}.StartCommand(cmd))
+	})
+
+	t.Run("run under", func(t *testing.T) {
+		cfg := ChainConfig{ModifyStartCommand: RunUnder("strace", "-f")}
+		got := cfg.StartCommand(cmd)
+		require.Equal(t, []string{"strace", "-f", "gaiad", "start", "--home", "/var/cosmos-chain/gaia"}, got.Cmd)
+		require.Empty(t, got.Env)
+	})
+
+	t.Run("run in shell", func(t *testing.T) {
+		cfg := ChainConfig{ModifyStartCommand: RunInShell("ulimit -c unlimited")}
+		got := cfg.StartCommand([]string{"gaiad", "start", "--moniker", "it's"})
+		require.Equal(t, []string{"sh", "-c", `ulimit -c unlimited && exec 'gaiad' 'start' '--moniker' 'it'\''s'`}, got.Cmd)
+	})
+
+	t.Run("with env", func(t *testing.T) {
+		cfg := ChainConfig{ModifyStartCommand: WithStartEnv("GODEBUG=gctrace=1")}
+		got := cfg.StartCommand(cmd)
+		require.Equal(t, cmd, got.Cmd)
+		require.Equal(t, []string{"GODEBUG=gctrace=1"}, got.Env)
+	})
+
+	t.Run("default env", func(t *testing.T) {
+		cfg := ChainConfig{ModifyStartCommand: WithStartEnv("GODEBUG=gctrace=1")}
+		got := cfg.StartCommand(cmd, "RUST_LOG=debug")
+		require.Equal(t, []string{"RUST_LOG=debug", "GODEBUG=gctrace=1"}, got.Env)
+	})
+
+	t.Run("log level", func(t *testing.T) {
+		cfg := ChainConfig{}.MergeChainSpecConfig(ChainConfig{LogLevel: "trace"})
+		require.Equal(t, "trace", cfg.LogLevel)
+	})
+}
+
+func TestChainConfig_HostPorts(t *testing.T) {
+	t.Parallel()
+
+	cfg := ChainConfig{HostPorts: HostPorts{Publish: []string{"26657/tcp"}}}
+
+	merged := ChainConfig{HostPorts: HostPorts{InternalOnly: true}}.MergeChainSpecConfig(cfg)
+	require.Equal(t, cfg.HostPorts, merged.HostPorts)
+
+	// Unset host ports keep the chain's publishing.
+	merged = cfg.MergeChainSpecConfig(ChainConfig{})
+	require.Equal(t, cfg.HostPorts, merged.HostPorts)
+
+	clone := cfg.Clone()
+	clone.HostPorts.Publish[0] = "9090/tcp"
+	require.Equal(t, "26657/tcp", cfg.HostPorts.Publish[0])
+}
+
+func TestChainConfig_EnableAPI(t *testing.T) {
+	t.Parallel()
+
+	merged := ChainConfig{}.MergeChainSpecConfig(ChainConfig{EnableAPI: true})
+	require.True(t, merged.EnableAPI)
+
+	// An unset override keeps the API enabled.
+	merged = merged.MergeChainSpecConfig(ChainConfig{})
+	require.True(t, merged.EnableAPI)
+}
+
+func TestChainConfig_Fees(t *testing.T) {
+	t.Parallel()
+
+	cfg := ChainConfig{MinGasPrices: "0.01uatom", RelayerFeeAllowance: &FeeAllowance{SpendLimit: "1000uatom"}}
+	merged := ChainConfig{MinGasPrices: "0uatom"}.MergeChainSpecConfig(cfg)
+	require.Equal(t, "0.01uatom", merged.MinGasPrices)
+	require.Equal(t, cfg.RelayerFeeAllowance, merged.RelayerFeeAllowance)
+
+	clone := cfg.Clone()
+	clone.RelayerFeeAllowance.SpendLimit = ""
+	require.Equal(t, "1000uatom", cfg.RelayerFeeAllowance.SpendLimit)
+}
+
+func TestChainConfig_RelayerGas(t *testing.T) {
+	t.Parallel()
+
+	cfg := ChainConfig{GasAdjustment: 1.3, RelayerGas: RelayerGas{MaxGas: 5_000_000}}
+	require.Equal(t, 1.3, cfg.RelayerGas.Multiplier(cfg.GasAdjustment))
+
+	merged := cfg.MergeChainSpecConfig(ChainConfig{RelayerGas: RelayerGas{
+		GasMultiplier:    1.5,
+		ExtensionOptions: []ExtensionOption{{Type: "ethermint_dynamic_fee"}},
+	}})
+	require.Equal(t, RelayerGas{
+		MaxGas:           5_000_000,
+		GasMultiplier:    1.5,
+		ExtensionOptions: []ExtensionOption{{Type: "ethermint_dynamic_fee"}},
+	}, merged.RelayerGas)
+	require.Equal(t, 1.5, merged.RelayerGas.Multiplier(merged.GasAdjustment))
+}
+
+func TestChainConfig_BaseAmount(t *testing.T) {
+	t.Parallel()
+
+	cosmos := ChainConfig{Type: "cosmos", Denom: "uatom"}
+	polkadot := ChainConfig{Type: "polkadot", Denom: "uDOT"}
+	eighteen := int64(18)
+	evm := ChainConfig{Type: "cosmos", Denom: "aevmos", CoinDecimals: &eighteen}
+
+	require.EqualValues(t, 6, cosmos.Decimals())
+	require.EqualValues(t, 12, polkadot.Decimals())
+	require.EqualValues(t, 18, evm.Decimals())
+
+	for _, tt := range []struct {
+		cfg     ChainConfig
+		display string
+		want    string
+	}{
+		{cosmos, "1.5", "1500000"},
+		{cosmos, "10", "10000000"},
+		{cosmos, ".000001", "1"},
+		{cosmos, "0", "0"},
+		{polkadot, "1.5", "1500000000000"},
+		{evm, "2", "2000000000000000000"},
+	} {
+		got, err := tt.cfg.BaseAmount(tt.display)
+		require.NoError(t, err, tt.display)
+		require.Equal(t, tt.want, got.String(), tt.display)
+	}
+
+	for _, display := range []string{"", ".", "-1", "1e6", "1.2.3", "0.0000001"} {
+		_, err := cosmos.BaseAmount(display)
+		require.Error(t, err, display)
+	}
+}
+
+func TestChainConfig_DisplayAmount(t *testing.T) {
+	t.Parallel()
+
+	eighteen := int64(18)
+	require.Equal(t, "atom", ChainConfig{Denom: "uatom"}.DisplayDenom())
+	require.Equal(t, "DOT", ChainConfig{Type: "polkadot", Denom: "uDOT"}.DisplayDenom())
+	require.Equal(t, "evmos", ChainConfig{Denom: "aevmos", CoinDecimals: &eighteen}.DisplayDenom())
+	require.Equal(t, "acre", ChainConfig{Denom: "acre"}.DisplayDenom())
+	require.Equal(t, "photon", ChainConfig{Denom: "photon"}.DisplayDenom())
+	require.Equal(t, "ATOM", ChainConfig{Denom: "uatom", CoinDisplayDenom: "ATOM"}.DisplayDenom())
+
+	cosmos := ChainConfig{Type: "cosmos", Denom: "uatom"}
+	polkadot := ChainConfig{Type: "polkadot", Denom: "uDOT"}
+	zero := int64(0)
+	whole := ChainConfig{Denom: "token", CoinDecimals: &zero}
+	for _, tt := range []struct {
+		cfg  ChainConfig
+		base int64
+		want string
+	}{
+		{cosmos, 1500000, "1.5"},
+		{cosmos, 10000000, "10"},
+		{cosmos, 1, "0.000001"},
+		{cosmos, 0, "0"},
+		{cosmos, -2500000, "-2.5"},
+		{polkadot, 1500000000000, "1.5"},
+		{whole, 42, "42"},
+	} {
+		got := tt.cfg.DisplayAmount(math.NewInt(tt.base))
+		require.Equal(t, tt.want, got, tt.base)
+
+		if tt.base >= 0 {
+			back, err := tt.cfg.BaseAmount(got)
+			require.NoError(t, err)
+			require.Equal(t, tt.base, back.Int64())
+		}
+	}
+}
+
+func TestChainConfig_CoinDecimalsClone(t *testing.T) {
+	t.Parallel()
+
+	decimals := int64(8)
+	cfg := ChainConfig{CoinDecimals: &decimals}
+	clone := cfg.Clone()
+	*clone.CoinDecimals = 10
+	require.EqualValues(t, 8, cfg.Decimals())
+
+	merged := ChainConfig{}.MergeChainSpecConfig(cfg)
+	require.EqualValues(t, 8, merged.Decimals())
+}
+
+func TestChainConfig_RemoteSigner(t *testing.T) {
+	t.Parallel()
+
+	cfg := ChainConfig{RemoteSigner: &RemoteSigner{Type: RemoteSignerHorcrux, Shards: 3, Threshold: 2}}
+	merged := ChainConfig{}.MergeChainSpecConfig(cfg)
+	merged.RemoteSigner.Threshold = 3
+	require.Equal(t, 2, cfg.RemoteSigner.Threshold)
+	clone := cfg.Clone()
+	clone.RemoteSigner.Shards = 5
+	require.Equal(t, 3, cfg.RemoteSigner.Shards)
+
+	require.NoError(t, RemoteSigner{Type: RemoteSignerHorcrux}.Validate())
+	require.NoError(t, cfg.RemoteSigner.Validate())
+	require.NoError(t, RemoteSigner{Type: RemoteSignerTMKMS, Image: DockerImage{Repository: "tmkms"}}.Validate())
+
+	require.EqualError(t, RemoteSigner{Type: RemoteSignerHorcrux, Shards: 3, Threshold: 4}.Validate(), "horcrux threshold 4 must be between 2 and the 3 shards")
+	require.EqualError(t, RemoteSigner{Type: RemoteSignerHorcrux, Shards: 1, Threshold: 1}.Validate(), "horcrux threshold 1 must be between 2 and the 1 shards")
+	require.EqualError(t, RemoteSigner{Type: RemoteSignerTMKMS}.Validate(), "tmkms remote signer needs an image")
+	require.EqualError(t, RemoteSigner{Type: "yubihsm"}.Validate(), `unknown remote signer type "yubihsm"`)
+}
+
+func TestP2PTopology(t *testing.T) {
+	t.Parallel()
+
+	topology := P2PTopology{
+		Peers:      map[string][]string{"val-0": {"fn-0"}, "val-1": {"fn-0"}},
+		Seeds:      []string{"fn-0"},
+		DisablePEX: []string{"val-0", "val-1"},
+	}
+	require.NoError(t, topology.Validate(2, 1))
+	require.EqualError(t, topology.Validate(1, 1), `peers names unknown node "val-1"`)
+	require.EqualError(t, topology.Validate(2, 0), `peers of val-0 names unknown node "fn-0"`)
+	require.EqualError(t, P2PTopology{Peers: map[string][]string{"fn-0": {"fn-0"}}}.Validate(0, 1), `node "fn-0" is its own peer`)
+	require.EqualError(t, P2PTopology{DisablePEX: []string{"validator-0"}}.Validate(1, 0), `disable-pex names unknown node "validator-0"`)
+
+	cfg := ChainConfig{}.MergeChainSpecConfig(ChainConfig{P2P: &topology})
+	cfg.P2P.Peers["val-0"][0] = "fn-1"
+	cfg.Clone().P2P.Seeds[0] = "fn-1"
+	require.Equal(t, "fn-0", topology.Peers["val-0"][0])
+	require.Equal(t, "fn-0", topology.Seeds[0])
+}
+
+func TestSentryTopology(t *testing.T) {
+	t.Parallel()
+
+	topology := SentryTopology(2, 2)
+	require.NoError(t, topology.Validate(2, 4))
+	require.Equal(t, map[string][]string{
+		"val-0": {"fn-0", "fn-1"},
+		"val-1": {"fn-2", "fn-3"},
+		"fn-0":  {"val-0", "fn-1", "fn-2", "fn-3"},
+		"fn-1":  {"val-0", "fn-0", "fn-2", "fn-3"},
+		"fn-2":  {"val-1", "fn-0", "fn-1", "fn-3"},
+		"fn-3":  {"val-1", "fn-0", "fn-1", "fn-2"},
+	}, topology.Peers)
+	require.Equal(t, []string{"val-0", "val-1"}, topology.DisablePEX)
+	require.Equal(t, []string{"val-0", "val-1"}, topology.Private)
+	require.Empty(t, topology.Seeds)
+}
+
+func TestWallet_ChainAddress(t *testing.T) {
+	t.Parallel()
+
+	w := &Wallet{Address: string([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20})}
+	require.Equal(t, w.Bech32Address("cosmos"), w.ChainAddress(ChainConfig{Bech32Prefix: "cosmos"}))
+
+	ss58 := &Wallet{Address: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY"}
+	require.Equal(t, ss58.Address, ss58.ChainAddress(ChainConfig{Type: "polkadot"}))
+}
+
+func TestDockerImage_Validate(t *testing.T) {
+	t.Parallel()
+
+	for _, img := range []DockerImage{
+		{Repository: "ghcr.io/strangelove-ventures/heighliner/gaia"},
+		{Repository: "ghcr.io/strangelove-ventures/heighliner/gaia", Version: "v7.0.2", UidGid: "1025:1025"},
+		{Repository: "parity/polkadot", Version: "v0.9.19_rc-1"},
+		{Repository: "ghcr.io/strangelove-ventures/heighliner/ibc-go-simd", Version: "feat-wasm-clients", Digest: pinnedDigest},
+	} {
+		require.NoError(t, img.Validate(), img)
+	}
+
+	for _, tt := range []struct {
+		img     DockerImage
+		wantErr string
+	}{
+		{DockerImage{Version: "v7.0.2"}, "image repository must not be empty"},
+		{DockerImage{Repository: "gaia", Version: "polkadot:v0.9.19"}, `version "polkadot:v0.9.19" is not a valid docker tag`},
+		{DockerImage{Repository: "gaia", Version: "v7.0.2 "}, "is not a valid docker tag"},
+		{DockerImage{Repository: "gaia", UidGid: "1025"}, `uid-gid "1025" must be of the form uid:gid`},
+		{DockerImage{Repository: "gaia", UidGid: "heighliner:1025"}, "must be of the form uid:gid"},
+		{DockerImage{Repository: "gaia", UidGid: "1025:"}, "must be of the form uid:gid"},
+		{DockerImage{Repository: "gaia", Digest: "ab" + pinnedDigest[7:]}, `is not of the form sha256:<64 hex digits>`},
+		{DockerImage{Repository: "gaia", Digest: pinnedDigest[:70]}, `is not of the form sha256:<64 hex digits>`},
+	} {
+		require.ErrorContains(t, tt.img.Validate(), tt.wantErr, tt.img)
+	}
+}
+
+// pinnedDigest is a well-formed image digest.
+const pinnedDigest = "sha256:4b2d0f76a8f3c0d1e9b7a6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5"
+
+func TestDockerImage_Ref(t *testing.T) {
+	t.Parallel()
+
+	img := DockerImage{Repository: "ghcr.io/strangelove-ventures/heighliner/ibc-go-simd"}
+	require.Equal(t, "ghcr.io/strangelove-ventures/heighliner/ibc-go-simd:latest", img.Ref())
+
+	img.Version = "feat-wasm-clients"
+	require.Equal(t, "ghcr.io/strangelove-ventures/heighliner/ibc-go-simd:feat-wasm-clients", img.Ref())
+
+	img.Digest = pinnedDigest
+	require.Equal(t, "feat-wasm-clients@"+pinnedDigest, img.Tag())
+	require.Equal(t, "ghcr.io/strangelove-ventures/heighliner/ibc-go-simd:feat-wasm-clients@"+pinnedDigest, img.Ref())
+
+	// Another version is not pinned by the digest of the pinned version.
+	require.Equal(t, img, img.WithVersion("feat-wasm-clients"))
+	other := img.WithVersion("v7.0.0")
+	require.Equal(t, "v7.0.0", other.Tag())
+	require.Equal(t, pinnedDigest, img.Digest)
+}
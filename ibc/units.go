@@ -0,0 +1,43 @@
+package ibc
+
+import (
+	"fmt"
+	"strings"
+
+	"cosmossdk.io/math"
+)
+
+// chainDisplayUnits returns the decimals and display denom of the native denom of chain,
+// as reported by chain if it implements DisplayUnits, or else by its ChainConfig.
+func chainDisplayUnits(chain Chain) (int64, string) {
+	if du, ok := chain.(DisplayUnits); ok {
+		return du.Decimals(), du.DisplayDenom()
+	}
+	cfg := chain.Config()
+	return cfg.Decimals(), cfg.DisplayDenom()
+}
+
+// ToBaseUnits converts amount, in display units of the native denom of chain, e.g. "1.5" or "10 DOT", to base units.
+// If amount names its display unit, it must be the chain's display unit, compared case-insensitively,
+// which catches amounts meant for another chain.
+func ToBaseUnits(chain Chain, amount string) (math.Int, error) {
+	decimals, displayDenom := chainDisplayUnits(chain)
+	fields := strings.Fields(amount)
+	switch len(fields) {
+	case 1:
+	case 2:
+		if !strings.EqualFold(fields[1], displayDenom) {
+			return math.Int{}, fmt.Errorf("amount %q is not in %s, the display unit of chain %s", amount, displayDenom, chain.Config().ChainID)
+		}
+	default:
+		return math.Int{}, fmt.Errorf("invalid display amount %q", amount)
+	}
+	return baseAmount(fields[0], decimals, chain.Config().Denom)
+}
+
+// ToDisplayUnits converts base, an amount in base units of the native denom of chain, to display units,
+// e.g. "1.5" for 1500000 uatom.
+func ToDisplayUnits(chain Chain, base math.Int) string {
+	decimals, _ := chainDisplayUnits(chain)
+	return displayAmount(base, decimals)
+}
@@ -6,13 +6,13 @@ import (
 	"path/filepath"
 )
 
-// CreateLogFile creates a file with name in dir $HOME/.ibctest/logs/
+// CreateLogFile creates a file with name in the logs directory of ArtifactDir, $HOME/.ibctest/logs/ by default.
 func CreateLogFile(name string) (*os.File, error) {
-	home, err := os.UserHomeDir()
+	dir, err := ArtifactDir()
 	if err != nil {
-		return nil, fmt.Errorf("user home dir: %w", err)
+		return nil, err
 	}
-	fpath := filepath.Join(home, ".ibctest", "logs")
+	fpath := filepath.Join(dir, "logs")
 	err = os.MkdirAll(fpath, 0755)
 	if err != nil {
 		return nil, fmt.Errorf("mkdirall: %w", err)
@@ -20,11 +20,12 @@ func CreateLogFile(name string) (*os.File, error) {
 	return os.Create(filepath.Join(fpath, name))
 }
 
-// DefaultBlockDatabaseFilepath is the default filepath to the sqlite database for tracking blocks and transactions.
+// DefaultBlockDatabaseFilepath is the default filepath to the sqlite database for tracking blocks and transactions,
+// in the databases directory of ArtifactDir.
 func DefaultBlockDatabaseFilepath() string {
-	home, err := os.UserHomeDir()
+	dir, err := ArtifactDir()
 	if err != nil {
 		panic(err)
 	}
-	return filepath.Join(home, ".ibctest", "databases", "block.db")
+	return filepath.Join(dir, "databases", "block.db")
 }
@@ -3,7 +3,11 @@ package ibctest
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"time"
 
+	"cosmossdk.io/math"
 	"github.com/cosmos/cosmos-sdk/codec"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
@@ -12,7 +16,9 @@ import (
 	"github.com/cosmos/cosmos-sdk/types"
 	"github.com/docker/docker/client"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
 	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -28,6 +34,9 @@ type Interchain struct {
 	// Map of relayer reference to user-supplied instance name.
 	relayers map[ibc.Relayer]string
 
+	// Map of externally managed relayer reference to the namespace for its path names.
+	pathNamespaces map[ibc.Relayer]string
+
 	// Key: relayer and path name; Value: the two chains being linked.
 	links map[relayerPath]interchainLink
 
@@ -41,8 +50,19 @@ type Interchain struct {
 	// Map of chain to additional genesis wallets to include at chain start.
 	additionalGenesisWallets map[ibc.Chain][]ibc.WalletAmount
 
+	// Map of chain to the chains that must have started before it starts.
+	dependencies map[ibc.Chain][]ibc.Chain
+
 	// Set during Build and cleaned up in the Close method.
 	cs *chainSet
+
+	// Name of the test, and whether the chains have started, set during Build for the debug server.
+	testName string
+	started  bool
+
+	// Debug server started during Build if a debug server address is set, and stopped in the Close method.
+	debugServer   *http.Server
+	debugListener net.Listener
 }
 
 type interchainLink struct {
@@ -117,6 +137,47 @@ func (ic *Interchain) AddChain(chain ibc.Chain, additionalGenesisWallets ...ibc.
 	return ic
 }
 
+// AddChainDependency declares that chain must only start once each chain in dependsOn has started,
+// e.g. for a consumer chain that needs its provider chain running.
+// Build still starts chains that do not depend on each other concurrently.
+//
+// The chains must already be added. If a dependency is declared twice,
+// or would make chains depend on each other, AddChainDependency panics.
+func (ic *Interchain) AddChainDependency(chain ibc.Chain, dependsOn ...ibc.Chain) *Interchain {
+	if _, ok := ic.chains[chain]; !ok {
+		panic(fmt.Errorf("chain %v must be added before declaring its dependencies", chain))
+	}
+	for _, dep := range dependsOn {
+		if _, ok := ic.chains[dep]; !ok {
+			panic(fmt.Errorf("chain %v must be added before %s can depend on it", dep, ic.chains[chain]))
+		}
+		if dep == chain || ic.dependsOn(dep, chain) {
+			panic(fmt.Errorf("chain %s cannot depend on %s, which depends on it", ic.chains[chain], ic.chains[dep]))
+		}
+		for _, existing := range ic.dependencies[chain] {
+			if existing == dep {
+				panic(fmt.Errorf("chain %s already depends on %s", ic.chains[chain], ic.chains[dep]))
+			}
+		}
+
+		if ic.dependencies == nil {
+			ic.dependencies = make(map[ibc.Chain][]ibc.Chain)
+		}
+		ic.dependencies[chain] = append(ic.dependencies[chain], dep)
+	}
+	return ic
+}
+
+// dependsOn reports whether chain depends on dep, directly or through other chains.
+func (ic *Interchain) dependsOn(chain, dep ibc.Chain) bool {
+	for _, d := range ic.dependencies[chain] {
+		if d == dep || ic.dependsOn(d, dep) {
+			return true
+		}
+	}
+	return false
+}
+
 // AddRelayer adds the given relayer with the given name to the Interchain.
 func (ic *Interchain) AddRelayer(relayer ibc.Relayer, name string) *Interchain {
 	if relayer == nil {
@@ -136,6 +197,39 @@ func (ic *Interchain) AddRelayer(relayer ibc.Relayer, name string) *Interchain {
 	return ic
 }
 
+// AddExternalRelayer adds a relayer that is managed outside of the Interchain,
+// so that a single relayer can be shared across many Interchains, typically one per subtest.
+//
+// The relayer must already be built, and the caller is responsible for starting and stopping it.
+// Build still adds the Interchain's chains and keys to the relayer,
+// so each Interchain sharing the relayer must use distinct chain IDs.
+// Every path created on the relayer is prefixed with namespace to avoid conflicts between Interchains;
+// use RelayerPath to get the name of a path as known to the relayer.
+func (ic *Interchain) AddExternalRelayer(relayer ibc.Relayer, name, namespace string) *Interchain {
+	if namespace == "" {
+		panic(fmt.Errorf("external relayer %s requires a path namespace", name))
+	}
+
+	ic.AddRelayer(relayer, name)
+
+	if ic.pathNamespaces == nil {
+		ic.pathNamespaces = make(map[ibc.Relayer]string)
+	}
+	ic.pathNamespaces[relayer] = namespace
+	return ic
+}
+
+// RelayerPath returns the name that relayer knows the given path by.
+// For relayers added with AddExternalRelayer, this is the path prefixed with the relayer's namespace;
+// otherwise it is path unchanged.
+func (ic *Interchain) RelayerPath(relayer ibc.Relayer, path string) string {
+	namespace, ok := ic.pathNamespaces[relayer]
+	if !ok {
+		return path
+	}
+	return dockerutil.SanitizeContainerName(namespace) + "-" + path
+}
+
 // InterchainLink describes a link between two chains,
 // by specifying the chain names, the relayer name,
 // and the name of the path to create.
@@ -214,6 +308,28 @@ type InterchainBuildOptions struct {
 
 	// If set, saves block history to a sqlite3 database to aid debugging.
 	BlockDatabaseFile string
+
+	// Optional. Maximum number of chains to start concurrently.
+	// If zero, Config.MaxConcurrentChainStarts applies.
+	// If negative, or zero without a configured limit, all chains are started at once.
+	MaxConcurrentChainStarts int
+
+	// Optional. How long each step of the handshake of a link, such as a connection reaching TRYOPEN,
+	// may take before Build fails with a *HandshakeStuckError. Defaults to 3 minutes.
+	// If negative, the progress of handshakes is not verified.
+	HandshakeStepTimeout time.Duration
+
+	// Optional. If positive, how long Build waits, after starting the chains, for each chain that implements
+	// ibc.HealthChecker to report that it is ready, e.g. that its nodes have peers and are not catching up.
+	// Build fails at once if a chain reports that it is not live.
+	// By default, Build does not wait for chains to be ready.
+	ChainReadyTimeout time.Duration
+
+	// Optional. If set, Build runs starting the chains as the PhaseChainStart phase of the budget,
+	// and linking the relayer paths as its PhaseChannelHandshake phase,
+	// so that a hung phase fails with a *PhaseTimeoutError naming it.
+	// Phases that the budget does not have are not limited.
+	PhaseBudget *PhaseBudget
 }
 
 // Build starts all the chains and configures the relayers associated with the Interchain.
@@ -225,29 +341,70 @@ func (ic *Interchain) Build(ctx context.Context, rep *testreporter.RelayerExecRe
 		panic(fmt.Errorf("Interchain.Build called more than once"))
 	}
 	ic.built = true
+	ic.testName = opts.TestName
+
+	if rep != nil {
+		// Let chains and docker helpers report how long each setup phase takes.
+		ctx = testreporter.WithTimingTracker(ctx, rep)
+	}
 
 	chains := make([]ibc.Chain, 0, len(ic.chains))
 	for chain := range ic.chains {
 		chains = append(chains, chain)
 	}
 	ic.cs = newChainSet(ic.log, chains)
+	ic.cs.dependencies = ic.dependencies
 
-	// Initialize the chains (pull docker images, etc.).
-	if err := ic.cs.Initialize(ctx, opts.TestName, opts.Client, opts.NetworkID); err != nil {
-		return fmt.Errorf("failed to initialize chains: %w", err)
+	maxConcurrentStarts := opts.MaxConcurrentChainStarts
+	if maxConcurrentStarts == 0 {
+		n, err := configuredMaxConcurrentChainStarts()
+		if err != nil {
+			return err
+		}
+		maxConcurrentStarts = n
 	}
 
-	ic.generateRelayerWallets() // Build the relayer wallet mapping.
-	walletAmounts, err := ic.genesisWalletAmounts(ctx)
+	err := opts.PhaseBudget.runIfBudgeted(ctx, PhaseChainStart, func(ctx context.Context) error {
+		// Initialize the chains (pull docker images, etc.).
+		if err := ic.cs.Initialize(ctx, opts.TestName, opts.Client, opts.NetworkID); err != nil {
+			return fmt.Errorf("failed to initialize chains: %w", err)
+		}
+
+		// Build the relayer wallet mapping.
+		if err := ic.generateRelayerWallets(opts.TestName); err != nil {
+			return err
+		}
+		walletAmounts, err := ic.genesisWalletAmounts(ctx)
+		if err != nil {
+			// Error already wrapped with appropriate detail.
+			return err
+		}
+
+		if err := ic.cs.Start(ctx, opts.TestName, walletAmounts, maxConcurrentStarts); err != nil {
+			return fmt.Errorf("failed to start chains: %w", err)
+		}
+		ic.started = true
+		ic.startDebugServer(rep)
+
+		if opts.ChainReadyTimeout > 0 {
+			if err := ic.cs.WaitReady(ctx, opts.ChainReadyTimeout, chainReadyPollInterval); err != nil {
+				return fmt.Errorf("failed waiting for chains to be ready: %w", err)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		// Error already wrapped with appropriate detail.
 		return err
 	}
 
-	if err := ic.cs.Start(ctx, opts.TestName, walletAmounts); err != nil {
-		return fmt.Errorf("failed to start chains: %w", err)
+	// Fail early if the started chains already fill more disk than the test may use.
+	if err := checkDiskBudget(ctx, opts, "starting chains"); err != nil {
+		return err
 	}
 
+	ic.reportResolvedConfigs(ctx, rep)
+	ic.reportRelayerImages(ctx, rep)
+
 	if err := ic.cs.TrackBlocks(ctx, opts.TestName, opts.BlockDatabaseFile, opts.GitSha); err != nil {
 		return fmt.Errorf("failed to track blocks: %w", err)
 	}
@@ -256,6 +413,9 @@ func (ic *Interchain) Build(ctx context.Context, rep *testreporter.RelayerExecRe
 		// Error already wrapped with appropriate detail.
 		return err
 	}
+	if err := checkDiskBudget(ctx, opts, "configuring relayer keys"); err != nil {
+		return err
+	}
 
 	// Some tests may want to configure the relayer from a lower level,
 	// but still have wallets configured.
@@ -263,63 +423,184 @@ func (ic *Interchain) Build(ctx context.Context, rep *testreporter.RelayerExecRe
 		return nil
 	}
 
-	// For every relayer link, teach the relayer about the link and create the link.
-	for rp, link := range ic.links {
-		rp := rp
-		link := link
-		c0 := link.chains[0]
-		c1 := link.chains[1]
-
-		if err := rp.Relayer.GeneratePath(ctx, rep, c0.Config().ChainID, c1.Config().ChainID, rp.Path); err != nil {
-			return fmt.Errorf(
-				"failed to generate path %s on relayer %s between chains %s and %s: %w",
-				rp.Path, rp.Relayer, ic.chains[c0], ic.chains[c1], err,
-			)
+	err = opts.PhaseBudget.runIfBudgeted(ctx, PhaseChannelHandshake, func(ctx context.Context) error {
+		// For every relayer link, teach the relayer about the link and create the link.
+		for rp, link := range ic.links {
+			rp := rp
+			link := link
+			c0 := link.chains[0]
+			c1 := link.chains[1]
+
+			pathName := ic.RelayerPath(rp.Relayer, rp.Path)
+			if err := rp.Relayer.GeneratePath(ctx, rep, c0.Config().ChainID, c1.Config().ChainID, pathName); err != nil {
+				return fmt.Errorf(
+					"failed to generate path %s on relayer %s between chains %s and %s: %w",
+					pathName, rp.Relayer, ic.chains[c0], ic.chains[c1], err,
+				)
+			}
+		}
+
+		// Now link the paths in parallel
+		// Creates clients, connections, and channels for each link/path.
+		var eg errgroup.Group
+		for rp, link := range ic.links {
+			rp := rp
+			link := link
+			c0 := link.chains[0]
+			c1 := link.chains[1]
+			eg.Go(func() error {
+				// If the user specifies a zero value CreateClientOptions struct then we fall back to the default
+				// client options. The trusting period also defaults when only e.g. the trust level is set.
+				if link.createClientOpts == (ibc.CreateClientOptions{}) {
+					link.createClientOpts = ibc.DefaultClientOpts()
+				} else if link.createClientOpts.TrustingPeriod == "" {
+					link.createClientOpts.TrustingPeriod = ibc.DefaultClientOpts().TrustingPeriod
+				}
+
+				// Check that the client creation options are valid and fully specified.
+				if err := link.createClientOpts.Validate(); err != nil {
+					return err
+				}
+
+				// If the user specifies a zero value CreateChannelOptions struct then we fall back to the default
+				// channel options for an ics20 fungible token transfer channel.
+				if link.createChannelOpts == (ibc.CreateChannelOptions{}) {
+					link.createChannelOpts = ibc.DefaultChannelOpts()
+				}
+
+				// Check that the channel creation options are valid and fully specified.
+				if err := link.createChannelOpts.Validate(); err != nil {
+					return err
+				}
+
+				pathName := ic.RelayerPath(rp.Relayer, rp.Path)
+				defer testreporter.StartPhase(ctx, testreporter.PhaseRelayerHandshake, pathName)()
+				if err := ic.linkPath(ctx, rep, rp.Relayer, pathName, link, opts.HandshakeStepTimeout); err != nil {
+					return fmt.Errorf(
+						"failed to link path %s on relayer %s between chains %s and %s: %w",
+						pathName, rp.Relayer, ic.chains[c0], ic.chains[c1], err,
+					)
+				}
+				return nil
+			})
 		}
+
+		return eg.Wait()
+	})
+	if err != nil {
+		return err
 	}
+	return checkDiskBudget(ctx, opts, "linking paths")
+}
 
-	// Now link the paths in parallel
-	// Creates clients, connections, and channels for each link/path.
-	var eg errgroup.Group
-	for rp, link := range ic.links {
-		rp := rp
-		link := link
-		c0 := link.chains[0]
-		c1 := link.chains[1]
-		eg.Go(func() error {
-			// If the user specifies a zero value CreateClientOptions struct then we fall back to the default
-			// client options.
-			if link.createClientOpts == (ibc.CreateClientOptions{}) {
-				link.createClientOpts = ibc.DefaultClientOpts()
-			}
+// checkDiskBudget returns an error if the docker volumes of the test being built
+// exceed the disk budget after the named build phase.
+// The budget is checked after every phase, as chains keep writing blocks while relayers link paths.
+func checkDiskBudget(ctx context.Context, opts InterchainBuildOptions, phase string) error {
+	if opts.Client == nil {
+		return nil
+	}
+	if err := dockerutil.CheckDiskBudget(ctx, opts.Client, opts.TestName); err != nil {
+		return fmt.Errorf("after %s: %w", phase, err)
+	}
+	return nil
+}
 
-			// Check that the client creation options are valid and fully specified.
-			if err := link.createClientOpts.Validate(); err != nil {
-				return err
-			}
+// linkPath links pathName through r, verifying that the handshake progresses by at least one step every stepTimeout.
+func (ic *Interchain) linkPath(ctx context.Context, rep *testreporter.RelayerExecReporter, r ibc.Relayer, pathName string, link interchainLink, stepTimeout time.Duration) error {
+	if stepTimeout < 0 {
+		return r.LinkPath(ctx, rep, pathName, link.createChannelOpts, link.createClientOpts)
+	}
+	if stepTimeout == 0 {
+		stepTimeout = defaultHandshakeStepTimeout
+	}
 
-			// If the user specifies a zero value CreateChannelOptions struct then we fall back to the default
-			// channel options for an ics20 fungible token transfer channel.
-			if link.createChannelOpts == (ibc.CreateChannelOptions{}) {
-				link.createChannelOpts = ibc.DefaultChannelOpts()
-			}
+	recorder := &handshakeExecRecorder{}
+	if rep != nil {
+		recorder.rep = rep
+	}
+	v := handshakeVerifier{
+		log:          ic.log,
+		path:         pathName,
+		stepTimeout:  stepTimeout,
+		pollInterval: handshakePollInterval,
+		stateA:       handshakeStateSource(r, link.chains[0]),
+		stateB:       handshakeStateSource(r, link.chains[1]),
+		rep:          recorder,
+	}
+	return v.run(ctx, func(ctx context.Context) error {
+		return r.LinkPath(ctx, recorder, pathName, link.createChannelOpts, link.createClientOpts)
+	})
+}
 
-			// Check that the channel creation options are valid and fully specified.
-			if err := link.createChannelOpts.Validate(); err != nil {
-				return err
-			}
+// reportResolvedConfigs logs the effective configuration of every started chain that implements ibc.ConfigResolver,
+// including the digests of its images, and tracks it through rep if rep is not nil.
+// Failing to resolve a configuration is only logged, as it does not affect the test.
+func (ic *Interchain) reportResolvedConfigs(ctx context.Context, rep *testreporter.RelayerExecReporter) {
+	for c, chainID := range ic.chains {
+		r, ok := c.(ibc.ConfigResolver)
+		if !ok {
+			continue
+		}
+		cfg, err := r.ResolvedConfig(ctx)
+		if err != nil {
+			ic.log.Warn("Failed to resolve chain config", zap.String("chain_id", chainID), zap.Error(err))
+			continue
+		}
 
-			if err := rp.Relayer.LinkPath(ctx, rep, rp.Path, link.createChannelOpts, link.createClientOpts); err != nil {
-				return fmt.Errorf(
-					"failed to link path %s on relayer %s between chains %s and %s: %w",
-					rp.Path, rp.Relayer, ic.chains[c0], ic.chains[c1], err,
-				)
-			}
-			return nil
-		})
+		images := make([]testreporter.Image, len(cfg.Images))
+		for i, image := range cfg.Images {
+			images[i] = testreporter.Image{Ref: image.Ref(), Digest: image.Digest}
+			ic.log.Info(
+				"Resolved chain image",
+				zap.String("chain_id", chainID),
+				zap.String("image", image.Ref()),
+				zap.String("digest", image.Digest),
+			)
+		}
+		if rep != nil {
+			rep.TrackChainConfig(testreporter.ChainConfigMessage{
+				ChainType:       cfg.Type,
+				ChainName:       cfg.Name,
+				ChainID:         cfg.ChainID,
+				Denom:           cfg.Denom,
+				Bech32Prefix:    cfg.Bech32Prefix,
+				Images:          images,
+				HostRPCAddress:  cfg.HostRPCAddress,
+				HostGRPCAddress: cfg.HostGRPCAddress,
+			})
+		}
 	}
+}
 
-	return eg.Wait()
+// reportRelayerImages logs the image of every relayer that implements ibc.RelayerImageResolver,
+// including its digest, and tracks it through rep if rep is not nil.
+// Failing to resolve an image is only logged, as it does not affect the test.
+func (ic *Interchain) reportRelayerImages(ctx context.Context, rep *testreporter.RelayerExecReporter) {
+	for r, name := range ic.relayers {
+		ir, ok := r.(ibc.RelayerImageResolver)
+		if !ok {
+			continue
+		}
+		image, err := ir.ResolvedImage(ctx)
+		if err != nil {
+			ic.log.Warn("Failed to resolve relayer image", zap.String("relayer", name), zap.Error(err))
+			continue
+		}
+
+		ic.log.Info(
+			"Resolved relayer image",
+			zap.String("relayer", name),
+			zap.String("image", image.Ref()),
+			zap.String("digest", image.Digest),
+		)
+		if rep != nil {
+			rep.TrackRelayerConfig(testreporter.RelayerConfigMessage{
+				RelayerName: name,
+				Image:       testreporter.Image{Ref: image.Ref(), Digest: image.Digest},
+			})
+		}
+	}
 }
 
 // WithLog sets the logger on the interchain object.
@@ -333,7 +614,11 @@ func (ic *Interchain) WithLog(log *zap.Logger) *Interchain {
 // Close cleans up any resources created during Build,
 // and returns any relevant errors.
 func (ic *Interchain) Close() error {
-	return ic.cs.Close()
+	err := ic.stopDebugServer()
+	if ic.cs != nil {
+		multierr.AppendInto(&err, ic.cs.Close())
+	}
+	return err
 }
 
 func (ic *Interchain) genesisWalletAmounts(ctx context.Context) (map[ibc.Chain][]ibc.WalletAmount, error) {
@@ -348,13 +633,13 @@ func (ic *Interchain) genesisWalletAmounts(ctx context.Context) (map[ibc.Chain][
 
 	// Add faucet for each chain first.
 	for c := range ic.chains {
-		// The values are nil at this point, so it is safe to directly assign the slice.
-		walletAmounts[c] = []ibc.WalletAmount{
-			{
+		// The faucet holds every genesis denom, so that users can be funded in any of them.
+		for _, denom := range c.Config().GenesisDenoms() {
+			walletAmounts[c] = append(walletAmounts[c], ibc.WalletAmount{
 				Address: faucetAddresses[c],
-				Denom:   c.Config().Denom,
-				Amount:  100_000_000_000_000, // Faucet wallet gets 100T units of denom.
-			},
+				Denom:   denom,
+				Amount:  math.NewInt(100_000_000_000_000), // Faucet wallet gets 100T units of denom.
+			})
 		}
 
 		if ic.additionalGenesisWallets != nil {
@@ -365,18 +650,45 @@ func (ic *Interchain) genesisWalletAmounts(ctx context.Context) (map[ibc.Chain][
 	// Then add all defined relayer wallets.
 	for rc, wallet := range ic.relayerWallets {
 		c := rc.C
-		walletAmounts[c] = append(walletAmounts[c], ibc.WalletAmount{
-			Address: wallet.Address,
-			Denom:   c.Config().Denom,
-			Amount:  1_000_000_000_000, // Every wallet gets 1t units of denom.
-		})
+		denoms := []string{c.Config().Denom}
+		if d, ok := c.(ibc.RelayerWalletDeriver); ok {
+			denoms = d.RelayerFeeDenoms()
+		}
+		for _, denom := range denoms {
+			walletAmounts[c] = append(walletAmounts[c], ibc.WalletAmount{
+				Address: wallet.Address,
+				Denom:   denom,
+				Amount:  math.NewInt(1_000_000_000_000), // Every wallet gets 1t units of denom.
+			})
+		}
+
+		// The faucet pays the relayer's fees on chains configured to grant it an allowance.
+		if allowance := c.Config().RelayerFeeAllowance; allowance != nil {
+			g, ok := c.(ibc.GenesisFeeGranter)
+			if !ok {
+				return nil, fmt.Errorf("chain %s cannot grant the relayer fee allowance at genesis", c.Config().ChainID)
+			}
+			fg, ok := rc.R.(ibc.RelayerFeeGranter)
+			if !ok {
+				return nil, fmt.Errorf("relayer %s cannot pay fees from the relayer fee allowance of chain %s", ic.relayers[rc.R], c.Config().ChainID)
+			}
+			if err := fg.SetFeeGranter(c.Config().ChainID, faucetAddresses[c]); err != nil {
+				return nil, fmt.Errorf("relayer %s on chain %s: %w", ic.relayers[rc.R], c.Config().ChainID, err)
+			}
+			g.AddGenesisFeeAllowances(ibc.GenesisFeeAllowance{
+				Granter:      faucetAddresses[c],
+				Grantee:      wallet.Address,
+				FeeAllowance: *allowance,
+			})
+		}
 	}
 
 	return walletAmounts, nil
 }
 
 // generateRelayerWallets populates ic.relayerWallets.
-func (ic *Interchain) generateRelayerWallets() {
+// If a mnemonic seed is set, the wallets' mnemonics are derived from it.
+func (ic *Interchain) generateRelayerWallets(testName string) error {
 	if ic.relayerWallets != nil {
 		panic(fmt.Errorf("cannot call generateRelayerWallets more than once"))
 	}
@@ -393,9 +705,26 @@ func (ic *Interchain) generateRelayerWallets() {
 			// Just an ephemeral unique name, only for the local use of the keyring.
 			accountName := ic.relayers[r] + "-" + ic.chains[c]
 
-			ic.relayerWallets[relayerChain{R: r, C: c}] = BuildWallet(kr, accountName, c.Config())
+			mnemonic, err := deriveMnemonic(testName, "relayer", ic.relayers[r], ic.chains[c])
+			if err != nil {
+				return fmt.Errorf("failed to derive mnemonic for relayer %s on chain %s: %w", ic.relayers[r], ic.chains[c], err)
+			}
+
+			wallet := buildWallet(kr, accountName, mnemonic, c.Config())
+			if d, ok := c.(ibc.RelayerWalletDeriver); ok {
+				// The relayer restores a different account from the mnemonic than the cosmos one.
+				if wallet.Address, err = d.RelayerWalletAddress(mnemonic); err != nil {
+					return fmt.Errorf("failed to derive wallet address of relayer %s on chain %s: %w", ic.relayers[r], ic.chains[c], err)
+				}
+			}
+			ic.relayerWallets[relayerChain{R: r, C: c}] = wallet
 		}
 	}
+
+	if seed := MnemonicSeed(); seed != "" {
+		ic.log.Info("Derived relayer wallet mnemonics from master seed", zap.String("test", testName), zap.String("seed", seed))
+	}
+	return nil
 }
 
 // configureRelayerKeys adds the chain configuration for each relayer
@@ -406,12 +735,12 @@ func (ic *Interchain) configureRelayerKeys(ctx context.Context, rep *testreporte
 
 	for r, chains := range ic.relayerChains() {
 		for _, c := range chains {
-			rpcAddr, grpcAddr := c.GetRPCAddress(), c.GetGRPCAddress()
-			if !r.UseDockerNetwork() {
-				rpcAddr, grpcAddr = c.GetHostRPCAddress(), c.GetHostGRPCAddress()
+			chainName := ic.chains[c]
+			rpcAddr, grpcAddr, err := relayerChainAddrs(r, c)
+			if err != nil {
+				return fmt.Errorf("failed to get addresses of chain %s for relayer %s: %w", chainName, ic.relayers[r], err)
 			}
 
-			chainName := ic.chains[c]
 			if err := r.AddChainConfiguration(ctx,
 				rep,
 				c.Config(), chainName,
@@ -433,6 +762,36 @@ func (ic *Interchain) configureRelayerKeys(ctx context.Context, rep *testreporte
 	return nil
 }
 
+// relayerChainAddrs returns the rpc and grpc addresses of c to configure r with,
+// reachable from the docker network if r uses it, or from the host otherwise.
+// If r selects an RPC transport for c's chain type and c provides endpoints by transport,
+// the rpc address is the URL of c's endpoint over that transport.
+func relayerChainAddrs(r ibc.Relayer, c ibc.Chain) (rpcAddr, grpcAddr string, err error) {
+	host := !r.UseDockerNetwork()
+	rpcAddr, grpcAddr = c.GetRPCAddress(), c.GetGRPCAddress()
+	if host {
+		rpcAddr, grpcAddr = c.GetHostRPCAddress(), c.GetHostGRPCAddress()
+	}
+
+	selector, ok := r.(ibc.RPCTransportSelector)
+	if !ok {
+		return rpcAddr, grpcAddr, nil
+	}
+	provider, ok := c.(ibc.EndpointProvider)
+	if !ok {
+		return rpcAddr, grpcAddr, nil
+	}
+	transport := selector.RPCTransport(c.Config().Type)
+	if transport == "" {
+		return rpcAddr, grpcAddr, nil
+	}
+	e, err := provider.Endpoint(transport, host)
+	if err != nil {
+		return "", "", err
+	}
+	return e.URL(), grpcAddr, nil
+}
+
 // relayerChain is a tuple of a Relayer and a Chain.
 type relayerChain struct {
 	R ibc.Relayer
@@ -442,17 +801,29 @@ type relayerChain struct {
 // BuildWallet will generate a random key for the key name in the provided keyring.
 // Returns the mnemonic and address in the bech32 format of the provided ChainConfig.
 func BuildWallet(kr keyring.Keyring, keyName string, config ibc.ChainConfig) ibc.Wallet {
+	return buildWallet(kr, keyName, "", config)
+}
+
+// buildWallet is like BuildWallet, but uses mnemonic instead of a random mnemonic if mnemonic is not empty.
+func buildWallet(kr keyring.Keyring, keyName, mnemonic string, config ibc.ChainConfig) ibc.Wallet {
 	// NOTE: this is hardcoded to the cosmos coin type.
 	// In the future, we may need to get the coin type from the chain config.
 	const coinType = types.CoinType
-
-	info, mnemonic, err := kr.NewMnemonic(
-		keyName,
-		keyring.English,
-		hd.CreateHDPath(coinType, 0, 0).String(),
-		"", // Empty passphrase.
-		hd.Secp256k1,
-	)
+	hdPath := hd.CreateHDPath(coinType, 0, 0).String()
+
+	var info *keyring.Record
+	var err error
+	if mnemonic == "" {
+		info, mnemonic, err = kr.NewMnemonic(
+			keyName,
+			keyring.English,
+			hdPath,
+			"", // Empty passphrase.
+			hd.Secp256k1,
+		)
+	} else {
+		info, err = kr.NewAccount(keyName, mnemonic, "", hdPath, hd.Secp256k1)
+	}
 	if err != nil {
 		panic(fmt.Errorf("failed to create mnemonic: %w", err))
 	}
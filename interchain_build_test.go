@@ -0,0 +1,110 @@
+package ibctest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/strangelove-ventures/ibctest/v6"
+	chainmock "github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	relayermock "github.com/strangelove-ventures/ibctest/v6/relayer/mock"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// newMockInterchain returns an interchain of two mock chains linked on path p by a mock relayer.
+func newMockInterchain(t *testing.T) (*ibctest.Interchain, *relayermock.Relayer, []*chainmock.Chain) {
+	chains := []*chainmock.Chain{
+		chainmock.NewChain(ibc.ChainConfig{Type: "cosmos", Name: "gaia", ChainID: "gaia-1", Bech32Prefix: "cosmos", Denom: "uatom"}),
+		chainmock.NewChain(ibc.ChainConfig{Type: "cosmos", Name: "osmosis", ChainID: "osmosis-1", Bech32Prefix: "osmo", Denom: "uosmo"}),
+	}
+	r := relayermock.NewRelayer()
+	ic := ibctest.NewInterchain().
+		WithLog(zaptest.NewLogger(t)).
+		AddChain(chains[0]).
+		AddChain(chains[1]).
+		AddRelayer(r, "r").
+		AddLink(ibctest.InterchainLink{
+			Chain1:  chains[0],
+			Chain2:  chains[1],
+			Relayer: r,
+			Path:    "p",
+		})
+	t.Cleanup(func() { _ = ic.Close() })
+	return ic, r, chains
+}
+
+func buildMockInterchain(t *testing.T, ic *ibctest.Interchain) error {
+	return ic.Build(context.Background(), testreporter.NewNopReporter().RelayerExecReporter(t), ibctest.InterchainBuildOptions{
+		TestName: t.Name(),
+	})
+}
+
+func TestInterchain_Build_Mock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ic, r, chains := newMockInterchain(t)
+	require.NoError(t, buildMockInterchain(t, ic))
+
+	// The relayer is configured with a key on each chain before the path is generated and linked.
+	// Queries polling the progress of the handshake are left out.
+	var methods []string
+	for _, c := range r.Calls() {
+		if c.Method != "GetChannels" && c.Method != "GetConnections" {
+			methods = append(methods, c.Method)
+		}
+	}
+	require.Equal(t, []string{"AddChainConfiguration", "RestoreKey", "AddChainConfiguration", "RestoreKey", "GeneratePath", "LinkPath"}, methods)
+	require.Equal(t, []any{"gaia-1", "osmosis-1", "p"}, r.CallsTo("GeneratePath")[0].Args)
+
+	for _, c := range chains {
+		channels, err := r.GetChannels(ctx, nil, c.Config().ChainID)
+		require.NoError(t, err)
+		require.Len(t, channels, 1)
+
+		// The chain was started with a funded faucet and relayer wallet.
+		addr, err := c.GetAddress(ctx, ibctest.FaucetAccountKeyName)
+		require.NoError(t, err)
+		faucet, err := sdk.Bech32ifyAddressBytes(c.Config().Bech32Prefix, addr)
+		require.NoError(t, err)
+		bal, err := c.GetBalance(ctx, faucet, c.Config().Denom)
+		require.NoError(t, err)
+		require.Equal(t, "100000000000000", bal.String())
+
+		wallet, ok := r.GetWallet(c.Config().ChainID)
+		require.True(t, ok)
+		require.NotEmpty(t, wallet.Mnemonic)
+	}
+}
+
+func TestInterchain_Build_MockFailures(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		method, wantErr string
+	}{
+		{"AddChainConfiguration", "failed to configure relayer r for chain"},
+		{"RestoreKey", "failed to restore key to relayer r for chain"},
+		{"GeneratePath", "failed to generate path p on relayer"},
+		{"LinkPath", "failed to link path p on relayer"},
+	} {
+		tc := tc
+		t.Run(tc.method, func(t *testing.T) {
+			t.Parallel()
+
+			ic, r, _ := newMockInterchain(t)
+			r.FailOn(tc.method, errors.New("relayer failure"))
+
+			err := buildMockInterchain(t, ic)
+			require.ErrorContains(t, err, tc.wantErr)
+			require.ErrorContains(t, err, "relayer failure")
+			if tc.method != "LinkPath" {
+				require.Empty(t, r.CallsTo("LinkPath"))
+			}
+		})
+	}
+}
@@ -204,7 +204,7 @@ func TestInterchain_CreateUser(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, mnemonic)
 
-		user, err := ibctest.GetAndFundTestUserWithMnemonic(ctx, keyName, mnemonic, 10000, gaia0)
+		user, err := ibctest.GetAndFundTestUserWithMnemonic(ctx, keyName, mnemonic, types.NewInt(10000), gaia0)
 		require.NoError(t, err)
 		require.NoError(t, test.WaitForBlocks(ctx, 2, gaia0))
 		require.NotEmpty(t, user.Address)
@@ -212,13 +212,13 @@ func TestInterchain_CreateUser(t *testing.T) {
 
 		actualBalance, err := gaia0.GetBalance(ctx, user.Bech32Address(gaia0.Config().Bech32Prefix), gaia0.Config().Denom)
 		require.NoError(t, err)
-		require.Equal(t, int64(10000), actualBalance)
+		require.Equal(t, int64(10000), actualBalance.Int64())
 
 	})
 
 	t.Run("without mnemonic", func(t *testing.T) {
 		keyName := "regular-user-name"
-		users := ibctest.GetAndFundTestUsers(t, ctx, keyName, 10000, gaia0)
+		users := ibctest.GetAndFundTestUsers(t, ctx, keyName, types.NewInt(10000), gaia0)
 		require.NoError(t, test.WaitForBlocks(ctx, 2, gaia0))
 		require.Len(t, users, 1)
 		require.NotEmpty(t, users[0].Address)
@@ -226,7 +226,7 @@ func TestInterchain_CreateUser(t *testing.T) {
 
 		actualBalance, err := gaia0.GetBalance(ctx, users[0].Bech32Address(gaia0.Config().Bech32Prefix), gaia0.Config().Denom)
 		require.NoError(t, err)
-		require.Equal(t, int64(10000), actualBalance)
+		require.Equal(t, int64(10000), actualBalance.Int64())
 	})
 }
 
@@ -276,7 +276,7 @@ func TestCosmosChain_BroadcastTx(t *testing.T) {
 		NetworkID: network,
 	}))
 
-	testUser := ibctest.GetAndFundTestUsers(t, ctx, "gaia-user-1", 10_000_000, gaia0)[0]
+	testUser := ibctest.GetAndFundTestUsers(t, ctx, "gaia-user-1", types.NewInt(10_000_000), gaia0)[0]
 
 	sendAmount := int64(10000)
 
@@ -302,7 +302,7 @@ func TestCosmosChain_BroadcastTx(t *testing.T) {
 
 		dstFinalBalance, err := gaia1.GetBalance(ctx, testUser.Bech32Address(gaia1.Config().Bech32Prefix), dstIbcDenom)
 		require.NoError(t, err, "failed to get balance from dest chain")
-		require.Equal(t, sendAmount, dstFinalBalance)
+		require.Equal(t, sendAmount, dstFinalBalance.Int64())
 	})
 }
 
@@ -389,6 +389,24 @@ func TestInterchain_ConflictRejection(t *testing.T) {
 		})
 	})
 
+	t.Run("cyclic chain dependency", func(t *testing.T) {
+		cf := ibctest.NewBuiltinChainFactory(zap.NewNop(), []*ibctest.ChainSpec{
+			{Name: "gaia", ChainName: "g1", Version: "v7.0.1", ChainConfig: ibc.ChainConfig{ChainID: "cosmoshub-0"}},
+			{Name: "gaia", ChainName: "g2", Version: "v7.0.1", ChainConfig: ibc.ChainConfig{ChainID: "cosmoshub-1"}},
+		})
+
+		chains, err := cf.Chains(t.Name())
+		require.NoError(t, err)
+
+		ic := ibctest.NewInterchain().AddChain(chains[0]).AddChain(chains[1]).AddChainDependency(chains[1], chains[0])
+		require.PanicsWithError(t, "chain cosmoshub-0 cannot depend on cosmoshub-1, which depends on it", func() {
+			_ = ic.AddChainDependency(chains[0], chains[1])
+		})
+		require.PanicsWithError(t, "chain cosmoshub-1 already depends on cosmoshub-0", func() {
+			_ = ic.AddChainDependency(chains[1], chains[0])
+		})
+	})
+
 	t.Run("duplicate relayer", func(t *testing.T) {
 		var r rly.CosmosRelayer
 
@@ -417,6 +435,22 @@ func TestInterchain_AddNil(t *testing.T) {
 	})
 }
 
+func TestInterchain_ExternalRelayer(t *testing.T) {
+	var r1, r2 rly.CosmosRelayer
+
+	ic := ibctest.NewInterchain().
+		AddRelayer(&r1, "r1").
+		AddExternalRelayer(&r2, "r2", "TestSuite/sub")
+
+	require.Equal(t, "p", ic.RelayerPath(&r1, "p"))
+	require.Equal(t, "TestSuite_sub-p", ic.RelayerPath(&r2, "p"))
+
+	require.PanicsWithError(t, "external relayer r3 requires a path namespace", func() {
+		var r3 rly.CosmosRelayer
+		_ = ibctest.NewInterchain().AddExternalRelayer(&r3, "r3", "")
+	})
+}
+
 func assertTransactionIsValid(t *testing.T, resp sdk.TxResponse) {
 	require.NotNil(t, resp)
 	require.NotEqual(t, 0, resp.GasUsed)
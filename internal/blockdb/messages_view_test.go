@@ -258,7 +258,7 @@ WHERE type = "/ibc.core.channel.v1.MsgChannelOpenConfirm" AND chain_id = ?
 		tx, err := gaia0.SendIBCTransfer(ctx, gaia0ChannelID, ibctest.FaucetAccountKeyName, ibc.WalletAmount{
 			Address: gaia1FaucetAddr,
 			Denom:   gaia0.Config().Denom,
-			Amount:  txAmount,
+			Amount:  types.NewInt(txAmount),
 		}, nil)
 		require.NoError(t, err)
 		require.NoError(t, tx.Validate())
@@ -0,0 +1,87 @@
+package dockerutil
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"go.uber.org/zap"
+)
+
+// Entrypoint and command programs that wrap the image's real binary, so they are never detected as the binary.
+var wrapperPrograms = map[string]bool{
+	"sh": true, "bash": true, "ash": true, "env": true,
+	"tini": true, "dumb-init": true, "entrypoint.sh": true, "docker-entrypoint.sh": true,
+}
+
+// DetectBinary returns the name of the chain binary in the image repository:tag,
+// for images whose binary name is not configured.
+//
+// Candidates are, in order, the programs in the image's entrypoint and command,
+// the last element of the repository, and that element with a "d" suffix, e.g. "gaiad" for ".../gaia".
+// The first candidate that exits successfully with "--help" in a one-off container is returned.
+// The image must already be present locally.
+func DetectBinary(ctx context.Context, log *zap.Logger, cli *client.Client, networkID, testName, repository, tag string) (string, error) {
+	ref := repository + ":" + tag
+	inspect, _, err := cli.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("inspecting image %s: %w", ref, err)
+	}
+
+	var entrypoint, cmd []string
+	if inspect.Config != nil {
+		entrypoint, cmd = inspect.Config.Entrypoint, inspect.Config.Cmd
+	}
+
+	image := NewImage(log, cli, networkID, testName, repository, tag)
+	candidates := binaryCandidates(entrypoint, cmd, repository)
+	for _, bin := range candidates {
+		res := image.Run(ctx, []string{bin, "--help"}, ContainerOptions{})
+		if res.Err == nil && res.ExitCode == 0 {
+			log.Info("Detected chain binary", zap.String("image", ref), zap.String("bin", bin))
+			return bin, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not detect the chain binary of image %s (tried %s); set the binary name in the chain config", ref, strings.Join(candidates, ", "))
+}
+
+// binaryCandidates returns the distinct binary names that DetectBinary tries, in order.
+func binaryCandidates(entrypoint, cmd []string, repository string) []string {
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(bin string) {
+		if bin == "" || seen[bin] || wrapperPrograms[bin] || strings.HasPrefix(bin, "-") {
+			return
+		}
+		seen[bin] = true
+		candidates = append(candidates, bin)
+	}
+
+	for _, args := range [][]string{entrypoint, cmd} {
+		// Skip past wrappers, e.g. "tini --" or "/usr/bin/env", to the program they run.
+		// A shell command such as "gaiad start" runs its first word.
+		for _, arg := range args {
+			fields := strings.Fields(arg)
+			if len(fields) == 0 || fields[0] == "--" || strings.HasPrefix(fields[0], "-") {
+				continue
+			}
+			bin := path.Base(fields[0])
+			if wrapperPrograms[bin] {
+				continue
+			}
+			add(bin)
+			break
+		}
+	}
+
+	name := path.Base(repository)
+	add(name)
+	if !strings.HasSuffix(name, "d") {
+		add(name + "d")
+	}
+
+	return candidates
+}
@@ -0,0 +1,45 @@
+package dockerutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryCandidates(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name            string
+		entrypoint, cmd []string
+		repository      string
+		want            []string
+	}{
+		{
+			name:       "entrypoint",
+			entrypoint: []string{"/usr/bin/simd"},
+			repository: "ghcr.io/example/simapp",
+			want:       []string{"simd", "simapp", "simappd"},
+		},
+		{
+			name:       "wrapped entrypoint and command",
+			entrypoint: []string{"/sbin/tini", "--"},
+			cmd:        []string{"/bin/junod", "start"},
+			repository: "ghcr.io/example/juno",
+			want:       []string{"junod", "juno"},
+		},
+		{
+			name:       "shell command",
+			cmd:        []string{"/bin/sh", "-c", "gaiad start"},
+			repository: "ghcr.io/strangelove-ventures/heighliner/gaia",
+			want:       []string{"gaiad", "gaia"},
+		},
+		{
+			name:       "repository only",
+			repository: "simd",
+			want:       []string{"simd"},
+		},
+	} {
+		require.Equal(t, tt.want, binaryCandidates(tt.entrypoint, tt.cmd, tt.repository), tt.name)
+	}
+}
@@ -43,6 +43,10 @@ func ensureBusybox(ctx context.Context, cli *client.Client) error {
 		return nil
 	}
 
+	if ImagePullMode == PullModeOffline {
+		return fmt.Errorf("image %s is not present locally and pulls are disabled in %s pull mode", busyboxRef, PullModeOffline)
+	}
+
 	rc, err := cli.ImagePull(ctx, busyboxRef, types.ImagePullOptions{})
 	if err != nil {
 		return err
@@ -0,0 +1,101 @@
+package dockerutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// FailureKind is a common cause of docker failures.
+type FailureKind string
+
+const (
+	// FailureDaemonUnreachable means the Docker daemon is not running or DOCKER_HOST points elsewhere.
+	FailureDaemonUnreachable FailureKind = "daemon-unreachable"
+
+	// FailurePermissionDenied means the user may not access the Docker daemon socket.
+	FailurePermissionDenied FailureKind = "permission-denied"
+
+	// FailureOutOfDisk means the disk holding Docker's images and volumes is full.
+	FailureOutOfDisk FailureKind = "out-of-disk"
+
+	// FailurePortConflict means a port to publish is already in use on the host.
+	FailurePortConflict FailureKind = "port-conflict"
+
+	// FailurePlatformMismatch means an image is not available for, or cannot run on, the requested platform.
+	FailurePlatformMismatch FailureKind = "platform-mismatch"
+)
+
+// hints are the remediation hints for each kind of failure.
+var hints = map[FailureKind]string{
+	FailureDaemonUnreachable: "start the Docker daemon (or Docker Desktop), " +
+		"and check that DOCKER_HOST, if set, points to it",
+	FailurePermissionDenied: "add your user to the docker group with `sudo usermod -aG docker $USER` and log in again, " +
+		"or set DOCKER_HOST to a socket you can access",
+	FailureOutOfDisk: "free disk space used by Docker, e.g. with `docker system prune --volumes`; " +
+		"volumes of previous test runs can be pruned automatically with IBCTEST_PRUNE_FINISHED_VOLUMES",
+	FailurePortConflict: "stop the process using the port; containers left over from an interrupted test run " +
+		"can be listed with `docker ps --filter label=" + CleanupLabel + "`",
+	FailurePlatformMismatch: "the image is not built for this machine's architecture; set the image's Platform, " +
+		"e.g. to linux/amd64 with emulation enabled in Docker, or use an image built for this architecture",
+}
+
+// DiagnosedError is a docker failure with a known cause and a hint for fixing it.
+type DiagnosedError struct {
+	Kind FailureKind
+	Hint string
+
+	Err error
+}
+
+func (e *DiagnosedError) Error() string {
+	return fmt.Sprintf("%v (%s; hint: %s)", e.Err, e.Kind, e.Hint)
+}
+
+func (e *DiagnosedError) Unwrap() error {
+	return e.Err
+}
+
+// Diagnose wraps err in a *DiagnosedError if err is a common docker failure.
+// Otherwise, or if err has already been diagnosed, err is returned unchanged.
+func Diagnose(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var diagnosed *DiagnosedError
+	if errors.As(err, &diagnosed) {
+		return err
+	}
+
+	kind, ok := failureKind(err)
+	if !ok {
+		return err
+	}
+	return &DiagnosedError{Kind: kind, Hint: hints[kind], Err: err}
+}
+
+// failureKind reports the kind of docker failure that err is, if known.
+func failureKind(err error) (FailureKind, bool) {
+	if errors.Is(err, ErrNoMatchingManifest) {
+		return FailurePlatformMismatch, true
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "permission denied") && strings.Contains(msg, "docker"):
+		// E.g. "Got permission denied while trying to connect to the Docker daemon socket at unix:///var/run/docker.sock".
+		return FailurePermissionDenied, true
+	case client.IsErrConnectionFailed(err), strings.Contains(msg, "Cannot connect to the Docker daemon"):
+		return FailureDaemonUnreachable, true
+	case strings.Contains(msg, "no space left on device"):
+		return FailureOutOfDisk, true
+	case strings.Contains(msg, "port is already allocated"), strings.Contains(msg, "address already in use"):
+		return FailurePortConflict, true
+	case strings.Contains(msg, "no matching manifest"), strings.Contains(msg, "exec format error"):
+		return FailurePlatformMismatch, true
+	}
+	return "", false
+}
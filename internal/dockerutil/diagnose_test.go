@@ -0,0 +1,56 @@
+package dockerutil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		err  error
+		kind FailureKind
+	}{
+		{
+			err:  errors.New("Got permission denied while trying to connect to the Docker daemon socket at unix:///var/run/docker.sock"),
+			kind: FailurePermissionDenied,
+		},
+		{
+			err:  errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?"),
+			kind: FailureDaemonUnreachable,
+		},
+		{
+			err:  errors.New("write /var/lib/docker/volumes/abc/_data/config.toml: no space left on device"),
+			kind: FailureOutOfDisk,
+		},
+		{
+			err:  errors.New("driver failed programming external connectivity: Bind for 0.0.0.0:26657 failed: port is already allocated"),
+			kind: FailurePortConflict,
+		},
+		{
+			err:  fmt.Errorf("pull image parity/polkadot:v0.9.19 (platform linux/arm64): %w", ErrNoMatchingManifest),
+			kind: FailurePlatformMismatch,
+		},
+	} {
+		diagnosed := Diagnose(tt.err)
+
+		var de *DiagnosedError
+		require.ErrorAs(t, diagnosed, &de, tt.err.Error())
+		require.Equal(t, tt.kind, de.Kind)
+		require.NotEmpty(t, de.Hint)
+		require.ErrorIs(t, diagnosed, tt.err)
+
+		// Diagnosing again does not wrap twice.
+		wrapped := fmt.Errorf("wrapped: %w", diagnosed)
+		require.Equal(t, wrapped, Diagnose(wrapped))
+	}
+
+	require.NoError(t, Diagnose(nil))
+
+	other := errors.New("container exited")
+	require.Equal(t, other, Diagnose(other))
+}
@@ -0,0 +1,62 @@
+package dockerutil
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// PublishedPortsHost is the host through which the ports that containers publish are reached.
+//
+// It is the host of the DOCKER_HOST environment variable for a remote Docker daemon,
+// e.g. "build-box" for "tcp://build-box:2376" or "ssh://user@build-box",
+// or else localhost.
+var PublishedPortsHost = publishedPortsHost(os.Getenv("DOCKER_HOST"))
+
+func publishedPortsHost(dockerHost string) string {
+	u, err := url.Parse(dockerHost)
+	if err != nil {
+		return "localhost"
+	}
+	switch u.Scheme {
+	case "tcp", "http", "https", "ssh":
+		if h := u.Hostname(); h != "" {
+			return h
+		}
+	}
+	return "localhost"
+}
+
+// GetHostPorts returns the host addresses of the container's portIDs, in order, like GetHostPort.
+// Docker may publish ports shortly after a container starts,
+// so the container is inspected again until all ports are bound, or ctx is done.
+func GetHostPorts(ctx context.Context, cli *client.Client, id string, portIDs ...string) ([]string, error) {
+	for {
+		c, err := cli.ContainerInspect(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("inspect container %s: %w", id, err)
+		}
+
+		addrs := make([]string, len(portIDs))
+		var unbound []string
+		for i, portID := range portIDs {
+			addrs[i] = GetHostPort(c, portID)
+			if addrs[i] == "" {
+				unbound = append(unbound, portID)
+			}
+		}
+		if len(unbound) == 0 {
+			return addrs, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("ports %v of container %s are not bound to the host: %w", unbound, c.Name, ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
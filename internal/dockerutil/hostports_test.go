@@ -0,0 +1,56 @@
+package dockerutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishedPortsHost(t *testing.T) {
+	t.Parallel()
+
+	for dockerHost, want := range map[string]string{
+		"":                            "localhost",
+		"unix:///var/run/docker.sock": "localhost",
+		"npipe:////./pipe/docker":     "localhost",
+		"tcp://build-box:2376":        "build-box",
+		"tcp://10.0.0.5:2375":         "10.0.0.5",
+		"ssh://ci@build-box":          "build-box",
+	} {
+		require.Equal(t, want, publishedPortsHost(dockerHost), dockerHost)
+	}
+}
+
+func TestPortPublishing(t *testing.T) {
+	t.Parallel()
+
+	publishAll, bindings := PortPublishing{}.HostConfig()
+	require.True(t, publishAll)
+	require.Nil(t, bindings)
+	require.True(t, PortPublishing{}.Published("26657/tcp"))
+
+	internal := PortPublishing{InternalOnly: true, Publish: []string{"26657/tcp"}}
+	publishAll, bindings = internal.HostConfig()
+	require.False(t, publishAll)
+	require.Nil(t, bindings)
+	require.False(t, internal.Published("26657/tcp"))
+
+	targeted := PortPublishing{Publish: []string{"26657/tcp"}}
+	publishAll, bindings = targeted.HostConfig()
+	require.False(t, publishAll)
+	require.Len(t, bindings, 1)
+	require.Contains(t, bindings, nat.Port("26657/tcp"))
+	require.True(t, targeted.Published("26657/tcp"))
+	require.False(t, targeted.Published("9090/tcp"))
+}
+
+func TestResolveHostPorts_Internal(t *testing.T) {
+	t.Parallel()
+
+	// Unpublished ports resolve without inspecting the container.
+	addrs, err := ResolveHostPorts(context.Background(), nil, "id", "node-0", PortPublishing{InternalOnly: true}, "26657/tcp", "9090/tcp")
+	require.NoError(t, err)
+	require.Equal(t, []string{"node-0:26657", "node-0:9090"}, addrs)
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -89,6 +88,15 @@ type ContainerOptions struct {
 
 	// If non-zero, will limit the amount of log lines returned.
 	LogTail uint64
+
+	// Platform to pull and run the image for, e.g. "linux/arm64".
+	// If blank, defaults to the Docker daemon's platform.
+	Platform string
+
+	// Name of the container, whose hostname is derived from it,
+	// so that other containers can address it before it starts.
+	// If blank, defaults to a random name.
+	Name string
 }
 
 // ContainerExecResult is a wrapper type that wraps an exit code and associated output from stderr & stdout, along with
@@ -121,21 +129,28 @@ func (image *Image) imageRef() string {
 }
 
 // ensurePulled can only pull public images.
-func (image *Image) ensurePulled(ctx context.Context) error {
+// Unlike EnsureImage, an image already present locally is never pulled again.
+func (image *Image) ensurePulled(ctx context.Context, platform string) error {
 	ref := image.imageRef()
-	_, _, err := image.client.ImageInspectWithRaw(ctx, ref)
+	present, err := imagePresent(ctx, image.client, ref)
 	if err != nil {
-		rc, err := image.client.ImagePull(ctx, ref, types.ImagePullOptions{})
-		if err != nil {
-			return fmt.Errorf("pull image %s: %w", ref, err)
-		}
-		_, _ = io.Copy(io.Discard, rc)
-		_ = rc.Close()
+		return err
 	}
-	return nil
+	if present {
+		return nil
+	}
+	if ImagePullMode == PullModeOffline {
+		return fmt.Errorf("image %s is not present locally and pulls are disabled in %s pull mode", ref, PullModeOffline)
+	}
+	return PullImage(ctx, image.client, ref, platform)
 }
 
 func (image *Image) createContainer(ctx context.Context, containerName, hostName string, cmd []string, opts ContainerOptions) (string, error) {
+	platform, err := ParsePlatform(opts.Platform)
+	if err != nil {
+		return "", err
+	}
+
 	// Although this shouldn't happen because the name includes randomness, in reality there seems to intermittent
 	// chances of collisions.
 
@@ -169,7 +184,7 @@ func (image *Image) createContainer(ctx context.Context, containerName, hostName
 			Hostname: hostName,
 			User:     opts.User,
 
-			Labels: map[string]string{CleanupLabel: image.testName},
+			Labels: Labels(image.testName),
 		},
 		&container.HostConfig{
 			Binds:           opts.Binds,
@@ -181,7 +196,7 @@ func (image *Image) createContainer(ctx context.Context, containerName, hostName
 				image.networkID: {},
 			},
 		},
-		nil,
+		platform,
 		containerName,
 	)
 	if err != nil {
@@ -196,14 +211,17 @@ func (image *Image) Start(ctx context.Context, cmd []string, opts ContainerOptio
 		panic(errors.New("cmd cannot be empty"))
 	}
 
-	if err := image.ensurePulled(ctx); err != nil {
+	if err := image.ensurePulled(ctx, opts.Platform); err != nil {
 		return nil, image.wrapErr(err)
 	}
 
-	var (
+	containerName := opts.Name
+	if containerName == "" {
 		containerName = SanitizeContainerName(image.testName + "-" + RandLowerCaseLetterString(6))
-		hostName      = CondenseHostName(containerName)
-		logger        = image.log.With(
+	}
+	var (
+		hostName = CondenseHostName(containerName)
+		logger   = image.log.With(
 			zap.String("command", strings.Join(cmd, " ")),
 			zap.String("hostname", hostName),
 			zap.String("container", containerName),
@@ -0,0 +1,153 @@
+package dockerutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+var (
+	resourceLabelsMu sync.RWMutex
+
+	// Labels added to every container, volume, and network created for a test; see ResourceLabels.
+	resourceLabels, resourceLabelsErr = parseResourceLabels(os.Getenv("IBCTEST_DOCKER_LABELS"))
+)
+
+// ResourceLabels returns the labels added to every container, volume, and network created for a test,
+// to attribute docker resources on shared hosts, e.g. to a team, a ticket, or a CI run.
+//
+// The labels are empty by default, but can be initialized by setting the environment variable
+// IBCTEST_DOCKER_LABELS to comma separated key=value pairs, such as "team=ibc,run-id=1234".
+// If the variable is invalid, DockerSetup fails until the labels are set with SetResourceLabels.
+// Because dockerutil is an internal package, the public API for setting the labels
+// is ibctest.SetDockerLabels(map[string]string).
+func ResourceLabels() map[string]string {
+	resourceLabelsMu.RLock()
+	defer resourceLabelsMu.RUnlock()
+	labels := make(map[string]string, len(resourceLabels))
+	for k, v := range resourceLabels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// SetResourceLabels replaces the labels returned by ResourceLabels.
+// It returns an error without changing the labels if a key is invalid; see ValidateResourceLabel.
+func SetResourceLabels(labels map[string]string) error {
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if err := ValidateResourceLabel(k); err != nil {
+			return err
+		}
+		copied[k] = v
+	}
+
+	resourceLabelsMu.Lock()
+	defer resourceLabelsMu.Unlock()
+	resourceLabels, resourceLabelsErr = copied, nil
+	return nil
+}
+
+// resourceLabelsError returns the error of an invalid IBCTEST_DOCKER_LABELS, unless the labels were set since.
+func resourceLabelsError() error {
+	resourceLabelsMu.RLock()
+	defer resourceLabelsMu.RUnlock()
+	return resourceLabelsErr
+}
+
+// parseResourceLabels parses comma separated key=value pairs.
+// A pair without a value, such as "nightly", is a label with an empty value.
+// It returns an error if a pair has no key or a key reserved by ibctest.
+func parseResourceLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(pair, "=")
+		if err := ValidateResourceLabel(k); err != nil {
+			return nil, fmt.Errorf("IBCTEST_DOCKER_LABELS: invalid label %q: %w", pair, err)
+		}
+		labels[k] = v
+	}
+	return labels, nil
+}
+
+// ValidateResourceLabel returns an error if key cannot be used as a key of ResourceLabels,
+// because it is empty or is one of the labels that ibctest sets itself.
+func ValidateResourceLabel(key string) error {
+	switch {
+	case key == "":
+		return errors.New("empty docker label key")
+	case key == CleanupLabel || strings.HasPrefix(key, LabelPrefix):
+		return fmt.Errorf("docker label %q is reserved by ibctest", key)
+	}
+	return nil
+}
+
+// Labels returns the labels of a docker resource created for the test testName:
+// CleanupLabel, which DockerSetup cleans up by, and ResourceLabels.
+func Labels(testName string) map[string]string {
+	labels := ResourceLabels()
+	labels[CleanupLabel] = testName
+	return labels
+}
+
+// VolumeLabels returns the Labels of a volume created for the test testName,
+// with NodeOwnerLabel set to owner, the name of the node using the volume.
+func VolumeLabels(testName, owner string) map[string]string {
+	labels := Labels(testName)
+	labels[NodeOwnerLabel] = owner
+	return labels
+}
+
+// Resources are the docker resources with a label, as listed by ListResources.
+type Resources struct {
+	// Names of the resources, including stopped containers.
+	Containers, Volumes, Networks []string
+}
+
+// ListResources lists the containers, volumes, and networks with label,
+// either a key such as "team", or a key and value such as "run-id=1234",
+// e.g. to attribute resources on a shared docker host, or to reap the resources of a crashed CI run.
+func ListResources(ctx context.Context, cli *client.Client, label string) (Resources, error) {
+	var res Resources
+	f := filters.NewArgs(filters.Arg("label", label))
+
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: f})
+	if err != nil {
+		return Resources{}, fmt.Errorf("list containers: %w", err)
+	}
+	for _, c := range containers {
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		res.Containers = append(res.Containers, name)
+	}
+
+	volumes, err := cli.VolumeList(ctx, f)
+	if err != nil {
+		return Resources{}, fmt.Errorf("list volumes: %w", err)
+	}
+	for _, v := range volumes.Volumes {
+		res.Volumes = append(res.Volumes, v.Name)
+	}
+
+	networks, err := cli.NetworkList(ctx, types.NetworkListOptions{Filters: f})
+	if err != nil {
+		return Resources{}, fmt.Errorf("list networks: %w", err)
+	}
+	for _, n := range networks {
+		res.Networks = append(res.Networks, n.Name)
+	}
+	return res, nil
+}
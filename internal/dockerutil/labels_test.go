@@ -0,0 +1,50 @@
+package dockerutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResourceLabels(t *testing.T) {
+	t.Parallel()
+
+	labels, err := parseResourceLabels("")
+	require.NoError(t, err)
+	require.Empty(t, labels)
+
+	labels, err = parseResourceLabels("team=ibc, run-id=1234,nightly,")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"team": "ibc", "run-id": "1234", "nightly": ""}, labels)
+
+	_, err = parseResourceLabels("team=ibc,=orphan")
+	require.EqualError(t, err, `IBCTEST_DOCKER_LABELS: invalid label "=orphan": empty docker label key`)
+	_, err = parseResourceLabels("ibc-test=other")
+	require.EqualError(t, err, `IBCTEST_DOCKER_LABELS: invalid label "ibc-test=other": docker label "ibc-test" is reserved by ibctest`)
+	_, err = parseResourceLabels(LabelPrefix + "node-owner=x")
+	require.Error(t, err)
+}
+
+func TestValidateResourceLabel(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateResourceLabel("team"))
+	require.EqualError(t, ValidateResourceLabel(""), "empty docker label key")
+	require.EqualError(t, ValidateResourceLabel(CleanupLabel), `docker label "ibc-test" is reserved by ibctest`)
+	require.Error(t, ValidateResourceLabel(NodeOwnerLabel))
+}
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+
+	labels := Labels("TestFoo")
+	require.Equal(t, "TestFoo", labels[CleanupLabel])
+	for k, v := range ResourceLabels() {
+		require.Equal(t, v, labels[k])
+	}
+
+	volume := VolumeLabels("TestFoo", "gaia-1-val-0")
+	require.Equal(t, "TestFoo", volume[CleanupLabel])
+	require.Equal(t, "gaia-1-val-0", volume[NodeOwnerLabel])
+	require.NotContains(t, Labels("TestFoo"), NodeOwnerLabel)
+}
@@ -0,0 +1,199 @@
+package dockerutil
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"go.uber.org/zap"
+)
+
+// DefaultFatalLogPatterns match log lines that indicate a chain node has failed in a way it cannot recover from.
+// Once one of these lines is logged, the chain will typically stop producing blocks,
+// so there is no point in waiting for a timeout.
+var DefaultFatalLogPatterns = []*regexp.Regexp{
+	// Tendermint consensus failures, including app hash mismatches.
+	regexp.MustCompile(`CONSENSUS FAILURE!!!`),
+	regexp.MustCompile(`wrong Block\.Header\.AppHash`),
+	regexp.MustCompile(`^panic: `), // Unrecovered Go panic.
+
+	// Substrate node panics and essential task failures.
+	regexp.MustCompile(`Thread '.*' panicked at`),
+	regexp.MustCompile(`Essential task .* failed`),
+}
+
+// plannedHaltRE matches the consensus failure logged when a cosmos chain halts for a scheduled software upgrade.
+// That halt is expected by upgrade tests, so it must not be reported as fatal.
+// Tendermint logs the error as a quoted string, so the quotes around the upgrade name may be escaped.
+var plannedHaltRE = regexp.MustCompile(`UPGRADE \\?".*\\?" NEEDED at height`)
+
+// fatalLogExcerptLines is the number of log lines leading up to and including the fatal line
+// that are included in a FatalLogError.
+const fatalLogExcerptLines = 20
+
+// FatalLogError is returned when a container logs a line matching a fatal pattern.
+type FatalLogError struct {
+	// Name of the container that produced the log line.
+	Container string
+
+	// The pattern that matched.
+	Pattern string
+
+	// The matching line, preceded by the lines logged immediately before it.
+	Excerpt []string
+}
+
+func (e *FatalLogError) Error() string {
+	return fmt.Sprintf("container %s logged fatal error matching %q:\n%s", e.Container, e.Pattern, strings.Join(e.Excerpt, "\n"))
+}
+
+// LogWatcher follows the logs of one or more containers,
+// and records the first log line matching any of its fatal patterns.
+type LogWatcher struct {
+	log *zap.Logger
+	cli *client.Client
+
+	patterns []*regexp.Regexp
+
+	mu  sync.Mutex
+	err *FatalLogError
+}
+
+// NewLogWatcher returns a LogWatcher that matches log lines against patterns.
+// If patterns is empty, DefaultFatalLogPatterns is used.
+func NewLogWatcher(log *zap.Logger, cli *client.Client, patterns ...*regexp.Regexp) *LogWatcher {
+	if len(patterns) == 0 {
+		patterns = DefaultFatalLogPatterns
+	}
+	return &LogWatcher{
+		log:      log,
+		cli:      cli,
+		patterns: patterns,
+	}
+}
+
+// Watch begins following the logs of the container in the background.
+// Watching stops when the container stops or is removed.
+func (w *LogWatcher) Watch(containerID, containerName string) {
+	// The log stream must outlive the context used to start the container,
+	// which is frequently an errgroup context that is canceled once the container has started.
+	ctx := context.Background()
+
+	rc, err := w.cli.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		w.log.Info("Failed to follow container logs", zap.String("container", containerName), zap.Error(err))
+		return
+	}
+
+	// Logs are multiplexed into one stream; see docs for ContainerLogs.
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, rc)
+		_ = rc.Close()
+		_ = pw.CloseWithError(err)
+	}()
+
+	go func() {
+		defer pr.Close()
+		fatal, err := scanFatalLog(pr, w.patterns)
+		if err != nil {
+			w.log.Info("Stopped watching container logs", zap.String("container", containerName), zap.Error(err))
+		}
+		if fatal != nil {
+			fatal.Container = containerName
+			w.setErr(fatal)
+		}
+	}()
+}
+
+// Err returns the first fatal log line detected across all watched containers,
+// or nil if none have been detected.
+func (w *LogWatcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		return nil
+	}
+	return w.err
+}
+
+func (w *LogWatcher) setErr(err *FatalLogError) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.log.Error("Detected fatal container log", zap.String("container", err.Container), zap.String("pattern", err.Pattern))
+		w.err = err
+	}
+}
+
+// maxFatalLogLineLength is how many bytes of each log line are matched against fatal patterns.
+// Some nodes log very long lines, e.g. full genesis files; the rest of such a line is skipped.
+const maxFatalLogLineLength = 1024 * 1024
+
+// scanFatalLog reads r line by line until EOF or until a line matches one of patterns.
+// It returns a nil *FatalLogError if no line matched, and the error that ended reading r, other than io.EOF.
+func scanFatalLog(r io.Reader, patterns []*regexp.Regexp) (*FatalLogError, error) {
+	var excerpt []string
+
+	br := bufio.NewReaderSize(r, 64*1024)
+	for {
+		line, err := readLogLine(br, maxFatalLogLineLength)
+		if err != nil && line == "" {
+			if err == io.EOF {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		excerpt = append(excerpt, line)
+		if len(excerpt) > fatalLogExcerptLines {
+			excerpt = excerpt[1:]
+		}
+
+		if plannedHaltRE.MatchString(line) {
+			continue
+		}
+
+		for _, p := range patterns {
+			if p.MatchString(line) {
+				return &FatalLogError{
+					Pattern: p.String(),
+					Excerpt: excerpt,
+				}, nil
+			}
+		}
+	}
+}
+
+// readLogLine reads the next line from br without its line ending,
+// keeping at most maxLen bytes of it and discarding the rest.
+// The error is non-nil only if the line is incomplete, e.g. at EOF.
+func readLogLine(br *bufio.Reader, maxLen int) (string, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := br.ReadLine()
+		if err != nil {
+			return string(line), err
+		}
+		if room := maxLen - len(line); room > 0 {
+			if len(chunk) > room {
+				chunk = chunk[:room]
+			}
+			line = append(line, chunk...)
+		}
+		if !isPrefix {
+			return string(line), nil
+		}
+	}
+}
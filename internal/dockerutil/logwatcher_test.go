@@ -0,0 +1,94 @@
+package dockerutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanFatalLog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no match", func(t *testing.T) {
+		logs := "I[2022-10-01] executed block height=5\nI[2022-10-01] committed state height=5\n"
+		fatal, err := scanFatalLog(strings.NewReader(logs), DefaultFatalLogPatterns)
+		require.NoError(t, err)
+		require.Nil(t, fatal)
+	})
+
+	t.Run("consensus failure", func(t *testing.T) {
+		logs := strings.Join([]string{
+			"I[2022-10-01] executed block height=5",
+			"E[2022-10-01] CONSENSUS FAILURE!!! err=\"oops\"",
+			"I[2022-10-01] this line is never read",
+		}, "\n")
+
+		fatal, err := scanFatalLog(strings.NewReader(logs), DefaultFatalLogPatterns)
+		require.NoError(t, err)
+		require.NotNil(t, fatal)
+		require.Equal(t, "CONSENSUS FAILURE!!!", fatal.Pattern)
+		require.Equal(t, []string{
+			"I[2022-10-01] executed block height=5",
+			"E[2022-10-01] CONSENSUS FAILURE!!! err=\"oops\"",
+		}, fatal.Excerpt)
+	})
+
+	t.Run("planned upgrade halt", func(t *testing.T) {
+		logs := `E[2022-10-01] CONSENSUS FAILURE!!! err="UPGRADE \"v2\" NEEDED at height: 40: "` + "\n"
+		fatal, err := scanFatalLog(strings.NewReader(logs), DefaultFatalLogPatterns)
+		require.NoError(t, err)
+		require.Nil(t, fatal)
+	})
+
+	t.Run("substrate panic", func(t *testing.T) {
+		logs := "2022-10-01 Thread 'tokio-runtime-worker' panicked at 'boom', src/lib.rs:1\n"
+
+		fatal, err := scanFatalLog(strings.NewReader(logs), DefaultFatalLogPatterns)
+		require.NoError(t, err)
+		require.NotNil(t, fatal)
+		require.Len(t, fatal.Excerpt, 1)
+	})
+
+	t.Run("excerpt is truncated", func(t *testing.T) {
+		var lines []string
+		for i := 0; i < 50; i++ {
+			lines = append(lines, fmt.Sprintf("line %d", i))
+		}
+		lines = append(lines, "wrong Block.Header.AppHash.  Expected ABCD, got 1234")
+
+		fatal, err := scanFatalLog(strings.NewReader(strings.Join(lines, "\n")), DefaultFatalLogPatterns)
+		require.NoError(t, err)
+		require.NotNil(t, fatal)
+		require.Len(t, fatal.Excerpt, fatalLogExcerptLines)
+		require.Equal(t, lines[len(lines)-1], fatal.Excerpt[len(fatal.Excerpt)-1])
+
+		fatal.Container = "val-0"
+		require.Contains(t, fatal.Error(), "container val-0 logged fatal error")
+	})
+
+	t.Run("very long line", func(t *testing.T) {
+		logs := strings.Repeat("x", 2*maxFatalLogLineLength) + "\n" + "panic: runtime error\n"
+
+		fatal, err := scanFatalLog(strings.NewReader(logs), DefaultFatalLogPatterns)
+		require.NoError(t, err)
+		require.NotNil(t, fatal, "a long line must not stop the scan")
+		require.Equal(t, "^panic: ", fatal.Pattern)
+		require.Len(t, fatal.Excerpt[0], maxFatalLogLineLength)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		go func() {
+			_, _ = pw.Write([]byte("I[2022-10-01] executed block height=5\n"))
+			_ = pw.CloseWithError(errors.New("boom"))
+		}()
+
+		fatal, err := scanFatalLog(pr, DefaultFatalLogPatterns)
+		require.EqualError(t, err, "boom")
+		require.Nil(t, fatal)
+	})
+}
@@ -0,0 +1,192 @@
+package dockerutil
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+)
+
+// hostPortReservationTTL is how long a reservation of another process is honored,
+// so that the reservations of a process that exited without releasing them eventually expire.
+const hostPortReservationTTL = time.Hour
+
+// DefaultHostPortAllocator reserves the host ports of every container port that ibctest publishes,
+// through lock files shared by every ibctest process of the user, e.g. of packages tested in parallel by go test ./...
+var DefaultHostPortAllocator = NewHostPortAllocator(filepath.Join(os.TempDir(), "ibctest-host-ports"))
+
+// HostPortAllocator reserves free host ports for container port bindings,
+// so that containers started concurrently, within a process or by processes sharing its directory,
+// are never bound to the same host port.
+//
+// Ports are picked by the operating system from its ephemeral range, and reserved with a lock file per port in the directory.
+// Reservations are held by an owner, such as a container name, until released.
+type HostPortAllocator struct {
+	dir string
+
+	mu       sync.Mutex
+	reserved map[string][]int
+}
+
+// NewHostPortAllocator returns an allocator whose reservations are lock files in dir.
+func NewHostPortAllocator(dir string) *HostPortAllocator {
+	return &HostPortAllocator{dir: dir, reserved: make(map[string][]int)}
+}
+
+// Reserve reserves n free host ports for owner, in addition to any ports owner already holds.
+func (a *HostPortAllocator) Reserve(owner string, n int) ([]int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := os.MkdirAll(a.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create host port reservation directory: %w", err)
+	}
+
+	// Hold every candidate port open until done, so that the operating system does not pick a port twice.
+	var listeners []net.Listener
+	defer func() {
+		for _, l := range listeners {
+			_ = l.Close()
+		}
+	}()
+
+	ports := make([]int, 0, n)
+	for attempts := 0; len(ports) < n; attempts++ {
+		if attempts >= 20*n {
+			a.unlock(ports)
+			return nil, fmt.Errorf("failed to reserve %d host ports after %d attempts", n, attempts)
+		}
+		l, err := net.Listen("tcp", ":0")
+		if err != nil {
+			a.unlock(ports)
+			return nil, fmt.Errorf("failed to find a free host port: %w", err)
+		}
+		listeners = append(listeners, l)
+
+		port := l.Addr().(*net.TCPAddr).Port
+		ok, err := a.lock(port)
+		if err != nil {
+			a.unlock(ports)
+			return nil, err
+		}
+		if ok {
+			ports = append(ports, port)
+		}
+	}
+
+	a.reserved[owner] = append(a.reserved[owner], ports...)
+	return ports, nil
+}
+
+// Release releases the ports reserved for owner.
+func (a *HostPortAllocator) Release(owner string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.unlock(a.reserved[owner])
+	delete(a.reserved, owner)
+}
+
+// lock creates the lock file of port, replacing an expired one of another process,
+// and reports whether port is now reserved.
+func (a *HostPortAllocator) lock(port int) (bool, error) {
+	path := a.lockPath(port)
+	for i := 0; i < 2; i++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, err = f.WriteString(strconv.Itoa(os.Getpid()))
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(path)
+				return false, fmt.Errorf("failed to write host port reservation %s: %w", path, err)
+			}
+			return true, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return false, fmt.Errorf("failed to create host port reservation %s: %w", path, err)
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil || time.Since(fi.ModTime()) < hostPortReservationTTL {
+			// Reserved, or released and possibly reserved again, by another process.
+			return false, nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+func (a *HostPortAllocator) unlock(ports []int) {
+	for _, port := range ports {
+		_ = os.Remove(a.lockPath(port))
+	}
+}
+
+func (a *HostPortAllocator) lockPath(port int) string {
+	return filepath.Join(a.dir, strconv.Itoa(port)+".lock")
+}
+
+// ReserveHostConfig is like HostConfig, but binds each published port of the container containerName to a host port
+// reserved with DefaultHostPortAllocator, rather than to a random host port picked by Docker,
+// which may collide with the binding of another container starting at the same time.
+// exposed are the container ports, which are all published unless p selects some of them.
+//
+// The reservation of a previous container of the same name is released first;
+// call ReleaseHostPorts once the container is removed.
+// Ports of a remote Docker daemon cannot be reserved on this host, so they are left to Docker.
+func (p PortPublishing) ReserveHostConfig(containerName string, exposed nat.PortSet) (publishAll bool, bindings nat.PortMap, err error) {
+	if PublishedPortsHost != "localhost" {
+		publishAll, bindings := p.HostConfig()
+		return publishAll, bindings, nil
+	}
+	return p.reserveHostConfig(DefaultHostPortAllocator, containerName, exposed)
+}
+
+func (p PortPublishing) reserveHostConfig(a *HostPortAllocator, containerName string, exposed nat.PortSet) (publishAll bool, bindings nat.PortMap, err error) {
+	a.Release(containerName)
+	if p.InternalOnly {
+		return false, nil, nil
+	}
+
+	var portIDs []nat.Port
+	if len(p.Publish) > 0 {
+		for _, portID := range p.Publish {
+			portIDs = append(portIDs, nat.Port(portID))
+		}
+	} else {
+		for portID := range exposed {
+			portIDs = append(portIDs, portID)
+		}
+		sort.Slice(portIDs, func(i, j int) bool { return portIDs[i] < portIDs[j] })
+	}
+	if len(portIDs) == 0 {
+		return false, nil, nil
+	}
+
+	hostPorts, err := a.Reserve(containerName, len(portIDs))
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to reserve host ports of container %s: %w", containerName, err)
+	}
+	bindings = make(nat.PortMap, len(portIDs))
+	for i, portID := range portIDs {
+		bindings[portID] = []nat.PortBinding{{HostPort: strconv.Itoa(hostPorts[i])}}
+	}
+	return false, bindings, nil
+}
+
+// ReleaseHostPorts releases the host ports reserved for the container containerName by ReserveHostConfig.
+func ReleaseHostPorts(containerName string) {
+	DefaultHostPortAllocator.Release(containerName)
+}
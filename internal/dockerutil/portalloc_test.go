@@ -0,0 +1,104 @@
+package dockerutil
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostPortAllocator(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// Allocators sharing a directory stand in for concurrent test processes.
+	allocators := []*HostPortAllocator{NewHostPortAllocator(dir), NewHostPortAllocator(dir)}
+	reserved := make([][]int, 8)
+	errs := make([]error, 8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reserved[i], errs[i] = allocators[i%2].Reserve("node-"+strconv.Itoa(i), 5)
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool)
+	for i, ports := range reserved {
+		require.NoError(t, errs[i])
+		require.Len(t, ports, 5)
+		for _, port := range ports {
+			require.False(t, seen[port], "port %d reserved twice", port)
+			seen[port] = true
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 40)
+
+	for i := 0; i < 8; i++ {
+		allocators[i%2].Release("node-" + strconv.Itoa(i))
+	}
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestHostPortAllocator_Lock(t *testing.T) {
+	t.Parallel()
+
+	a := NewHostPortAllocator(t.TempDir())
+	require.NoError(t, os.MkdirAll(a.dir, 0o755))
+
+	ok, err := a.lock(40000)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = a.lock(40000)
+	require.NoError(t, err)
+	require.False(t, ok, "reserved port locked again")
+
+	expired := time.Now().Add(-2 * hostPortReservationTTL)
+	require.NoError(t, os.Chtimes(a.lockPath(40000), expired, expired))
+	ok, err = a.lock(40000)
+	require.NoError(t, err)
+	require.True(t, ok, "expired reservation not replaced")
+}
+
+func TestPortPublishing_ReserveHostConfig(t *testing.T) {
+	t.Parallel()
+
+	a := NewHostPortAllocator(t.TempDir())
+	exposed := nat.PortSet{"26657/tcp": {}, "9090/tcp": {}, "26656/tcp": {}}
+
+	publishAll, bindings, err := PortPublishing{}.reserveHostConfig(a, "node", exposed)
+	require.NoError(t, err)
+	require.False(t, publishAll)
+	require.Len(t, bindings, 3)
+	for portID := range exposed {
+		require.Len(t, bindings[portID], 1)
+		require.NotEmpty(t, bindings[portID][0].HostPort)
+	}
+	require.Len(t, a.reserved["node"], 3)
+
+	// Recreating the container releases its previous reservation.
+	_, bindings, err = PortPublishing{Publish: []string{"26657/tcp"}}.reserveHostConfig(a, "node", exposed)
+	require.NoError(t, err)
+	require.Len(t, bindings, 1)
+	require.Len(t, a.reserved["node"], 1)
+	require.Equal(t, strconv.Itoa(a.reserved["node"][0]), bindings["26657/tcp"][0].HostPort)
+
+	_, bindings, err = PortPublishing{InternalOnly: true}.reserveHostConfig(a, "node", exposed)
+	require.NoError(t, err)
+	require.Nil(t, bindings)
+	require.Empty(t, a.reserved["node"])
+}
@@ -0,0 +1,88 @@
+package dockerutil
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+// PortPublishing selects which container ports are published on the host.
+// Its fields match ibc.HostPorts, so that one converts to the other.
+type PortPublishing struct {
+	// If true, no ports are published.
+	InternalOnly bool
+
+	// If set, only these ports, such as "26657/tcp", are published.
+	// Otherwise, every exposed port is published.
+	Publish []string
+}
+
+// HostConfig returns the values of container.HostConfig's PublishAllPorts and PortBindings fields
+// that publish the selected ports, each on a random host port.
+func (p PortPublishing) HostConfig() (publishAll bool, bindings nat.PortMap) {
+	if p.InternalOnly {
+		return false, nil
+	}
+	if len(p.Publish) == 0 {
+		return true, nil
+	}
+	bindings = make(nat.PortMap, len(p.Publish))
+	for _, portID := range p.Publish {
+		bindings[nat.Port(portID)] = []nat.PortBinding{{}}
+	}
+	return false, bindings
+}
+
+// Published reports whether the container port portID is published on the host.
+func (p PortPublishing) Published(portID string) bool {
+	if p.InternalOnly {
+		return false
+	}
+	if len(p.Publish) == 0 {
+		return true
+	}
+	for _, published := range p.Publish {
+		if published == portID {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveHostPorts returns the addresses of the container's portIDs, in order, like GetHostPorts.
+// Ports that are not published are instead addressed through hostName, the container's hostname on the docker network.
+func ResolveHostPorts(ctx context.Context, cli *client.Client, id, hostName string, p PortPublishing, portIDs ...string) ([]string, error) {
+	addrs := make([]string, len(portIDs))
+	var published []string
+	var publishedIdx []int
+	for i, portID := range portIDs {
+		if p.Published(portID) {
+			published = append(published, portID)
+			publishedIdx = append(publishedIdx, i)
+			continue
+		}
+		addrs[i] = InternalAddress(hostName, portID)
+	}
+	if len(published) == 0 {
+		return addrs, nil
+	}
+
+	hostAddrs, err := GetHostPorts(ctx, cli, id, published...)
+	if err != nil {
+		return nil, err
+	}
+	for i, addr := range hostAddrs {
+		addrs[publishedIdx[i]] = addr
+	}
+	return addrs, nil
+}
+
+// InternalAddress returns the address of the container port portID, such as "26657/tcp",
+// through the container's hostname on the docker network.
+func InternalAddress(hostName, portID string) string {
+	port, _, _ := strings.Cut(portID, "/")
+	return net.JoinHostPort(hostName, port)
+}
@@ -0,0 +1,195 @@
+package dockerutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"go.uber.org/zap"
+)
+
+// PullMode controls how images are pulled before chains and relayers are started.
+type PullMode string
+
+const (
+	// PullModeDefault pulls every image.
+	// If a pull fails but the image is already present locally,
+	// a warning is logged and the local image is used; otherwise the pull error is returned.
+	PullModeDefault PullMode = "default"
+
+	// PullModeStrict pulls every image and returns any pull error,
+	// even if the image is already present locally.
+	PullModeStrict PullMode = "strict"
+
+	// PullModeOffline never pulls images,
+	// and returns an error if an image is not already present locally.
+	PullModeOffline PullMode = "offline"
+)
+
+// ImagePullMode determines how images are pulled.
+//
+// The value is PullModeDefault by default, but can be initialized by setting the
+// environment variable IBCTEST_IMAGE_PULL_MODE to "default", "strict", or "offline".
+// Because dockerutil is an internal package, the public API for setting this value
+// is ibctest.SetImagePullMode(ibctest.ImagePullMode).
+var ImagePullMode = PullMode(os.Getenv("IBCTEST_IMAGE_PULL_MODE"))
+
+// EnsureImage makes the image ref available locally according to ImagePullMode.
+// If platform is empty, the Docker daemon's default platform is used.
+// Each image is pulled at most once per process, as long as the local image keeps the digest it was pulled with,
+// so that parallel tests using the same image share a single pull.
+// Common docker failures are returned as a *DiagnosedError.
+func EnsureImage(ctx context.Context, log *zap.Logger, cli *client.Client, ref, platform string) error {
+	defer testreporter.StartPhase(ctx, testreporter.PhaseImagePull, ref)()
+
+	return Diagnose(ensureImage(ctx, log, cli, ref, platform))
+}
+
+func ensureImage(ctx context.Context, log *zap.Logger, cli *client.Client, ref, platform string) error {
+	switch ImagePullMode {
+	case PullModeOffline:
+		present, err := imagePresent(ctx, cli, ref)
+		if err != nil {
+			return err
+		}
+		if !present {
+			return fmt.Errorf("image %s is not present locally and pulls are disabled in %s pull mode", ref, PullModeOffline)
+		}
+		return nil
+	case PullModeStrict:
+		return pullImageOnce(ctx, cli, ref, platform)
+	case PullModeDefault, "":
+		pullErr := pullImageOnce(ctx, cli, ref, platform)
+		if pullErr == nil || errors.Is(pullErr, ErrNoMatchingManifest) {
+			return pullErr
+		}
+		present, err := imagePresent(ctx, cli, ref)
+		if err != nil || !present {
+			return pullErr
+		}
+		log.Warn("Failed to pull image; using local copy", zap.String("image", ref), zap.Error(pullErr))
+		return nil
+	default:
+		return fmt.Errorf("invalid image pull mode %q: must be one of %q, %q, or %q",
+			ImagePullMode, PullModeDefault, PullModeStrict, PullModeOffline)
+	}
+}
+
+// imagePresent reports whether the image ref exists locally.
+func imagePresent(ctx context.Context, cli *client.Client, ref string) (bool, error) {
+	if _, _, err := cli.ImageInspectWithRaw(ctx, ref); err != nil {
+		if client.IsErrNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("inspecting image %s: %w", ref, err)
+	}
+	return true, nil
+}
+
+// ImageDigest returns the repository digest of the local image ref, e.g. "ghcr.io/org/gaia@sha256:...",
+// identifying the exact image content regardless of later pushes to the tag.
+// The image ID is returned for images without a repository digest, i.e. images that were built locally.
+func ImageDigest(ctx context.Context, cli *client.Client, ref string) (string, error) {
+	inspect, _, err := cli.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("inspecting image %s: %w", ref, err)
+	}
+	return imageDigest(ref, inspect.RepoDigests, inspect.ID), nil
+}
+
+// imageDigest returns the digest of repoDigests in the repository of ref, or else the first one, or else id.
+func imageDigest(ref string, repoDigests []string, id string) string {
+	repo := ref
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		repo = ref[:i]
+	}
+	for _, d := range repoDigests {
+		if strings.HasPrefix(d, repo+"@") {
+			return d
+		}
+	}
+	if len(repoDigests) > 0 {
+		return repoDigests[0]
+	}
+	return id
+}
+
+// ErrNoMatchingManifest is wrapped by errors returned from PullImage
+// when the image does not have a manifest for the requested platform.
+var ErrNoMatchingManifest = errors.New("no matching manifest for platform")
+
+// ParsePlatform parses a platform string of the form os/arch or os/arch/variant,
+// such as "linux/amd64" or "linux/arm64/v8".
+// An empty string returns a nil platform, which tells the Docker daemon to use its own default.
+func ParsePlatform(platform string) (*ocispec.Platform, error) {
+	if platform == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(platform, "/")
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("invalid platform %q: empty component", platform)
+		}
+	}
+
+	switch len(parts) {
+	case 2:
+		return &ocispec.Platform{OS: parts[0], Architecture: parts[1]}, nil
+	case 3:
+		return &ocispec.Platform{OS: parts[0], Architecture: parts[1], Variant: parts[2]}, nil
+	default:
+		return nil, fmt.Errorf("invalid platform %q: must be of the form os/arch or os/arch/variant", platform)
+	}
+}
+
+// PullImage pulls the image ref for the given platform.
+// If platform is empty, the Docker daemon's default platform is used.
+//
+// Unlike a bare ImagePull, PullImage reads the entire progress stream,
+// so that errors reported partway through the pull are returned.
+func PullImage(ctx context.Context, cli *client.Client, ref, platform string) error {
+	if _, err := ParsePlatform(platform); err != nil {
+		return err
+	}
+
+	rc, err := cli.ImagePull(ctx, ref, types.ImagePullOptions{Platform: platform})
+	if err != nil {
+		return wrapPullErr(ref, platform, err.Error())
+	}
+	defer rc.Close()
+
+	dec := json.NewDecoder(rc)
+	for {
+		var msg struct {
+			Error string `json:"error"`
+		}
+		if err := dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("reading pull progress for image %s: %w", ref, err)
+		}
+		if msg.Error != "" {
+			return wrapPullErr(ref, platform, msg.Error)
+		}
+	}
+}
+
+func wrapPullErr(ref, platform, msg string) error {
+	if strings.Contains(msg, "no matching manifest") {
+		if platform == "" {
+			platform = "daemon default"
+		}
+		return fmt.Errorf("pull image %s (platform %s): %w: %s", ref, platform, ErrNoMatchingManifest, msg)
+	}
+	return fmt.Errorf("pull image %s: %s", ref, msg)
+}
@@ -0,0 +1,185 @@
+package dockerutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestParsePlatform(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		In   string
+		Want *ocispec.Platform
+	}{
+		{"", nil},
+		{"linux/amd64", &ocispec.Platform{OS: "linux", Architecture: "amd64"}},
+		{"linux/arm64/v8", &ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}},
+	} {
+		got, err := ParsePlatform(tt.In)
+		require.NoError(t, err, tt.In)
+		require.Equal(t, tt.Want, got, tt.In)
+	}
+
+	for _, in := range []string{"linux", "linux/", "/amd64", "linux/arm64/v8/extra"} {
+		_, err := ParsePlatform(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestWrapPullErr(t *testing.T) {
+	t.Parallel()
+
+	err := wrapPullErr("foo:v1", "linux/arm64", "no matching manifest for linux/arm64/v8 in the manifest list entries")
+	require.True(t, errors.Is(err, ErrNoMatchingManifest))
+	require.Contains(t, err.Error(), "foo:v1")
+	require.Contains(t, err.Error(), "linux/arm64")
+
+	err = wrapPullErr("foo:v1", "", "manifest unknown")
+	require.False(t, errors.Is(err, ErrNoMatchingManifest))
+}
+
+func TestImageDigest(t *testing.T) {
+	t.Parallel()
+
+	const id = "sha256:0123"
+	require.Equal(t, "ghcr.io/org/gaia@sha256:abcd", imageDigest(
+		"ghcr.io/org/gaia:v7.0.0",
+		[]string{"mirror.example.com/gaia@sha256:abcd", "ghcr.io/org/gaia@sha256:abcd"},
+		id,
+	))
+	require.Equal(t, "localhost:5000/gaia@sha256:abcd", imageDigest(
+		"localhost:5000/gaia:v7.0.0",
+		[]string{"localhost:5000/gaia@sha256:abcd"},
+		id,
+	))
+	require.Equal(t, "mirror.example.com/gaia@sha256:abcd", imageDigest(
+		"ghcr.io/org/gaia:v7.0.0",
+		[]string{"mirror.example.com/gaia@sha256:abcd"},
+		id,
+	))
+	require.Equal(t, id, imageDigest("gaia:local", nil, id))
+}
+
+func TestPullCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := newPullCache(time.Minute)
+
+	var (
+		mu     sync.Mutex
+		pulls  int
+		digest = "repo@sha256:aaa"
+	)
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	pullFn := func(context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		pulls++
+		return nil
+	}
+	localDigest := func(context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return digest, nil
+	}
+	pullCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return pulls
+	}
+
+	// Concurrent pulls of the same image share one pull.
+	// Callers arriving after the pull completes find the image already pulled.
+	var eg errgroup.Group
+	for i := 0; i < 5; i++ {
+		eg.Go(func() error {
+			return c.pull(ctx, "img", pullFn, localDigest)
+		})
+	}
+	<-started
+	close(release)
+	require.NoError(t, eg.Wait())
+	require.Equal(t, 1, pullCount())
+
+	// The image is not pulled again while its local digest is unchanged.
+	require.NoError(t, c.pull(ctx, "img", pullFn, localDigest))
+	require.Equal(t, 1, pullCount())
+
+	// A changed local image is pulled again.
+	mu.Lock()
+	digest = "repo@sha256:bbb"
+	mu.Unlock()
+	require.NoError(t, c.pull(ctx, "img", pullFn, localDigest))
+	require.Equal(t, 2, pullCount())
+
+	// Failed pulls are not remembered.
+	failErr := errors.New("rate limited")
+	failing := func(context.Context) error { return failErr }
+	require.ErrorIs(t, c.pull(ctx, "other", failing, localDigest), failErr)
+	require.NoError(t, c.pull(ctx, "other", pullFn, localDigest))
+	require.Equal(t, 3, pullCount())
+}
+
+func TestPullCache_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	c := newPullCache(time.Minute)
+	noDigest := func(context.Context) (string, error) { return "", nil }
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	pullErr := make(chan error, 1)
+	pullFn := func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-release:
+			return ctx.Err()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// The caller starting the pull gives up.
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() { firstErr <- c.pull(firstCtx, "img", pullFn, noDigest) }()
+	<-started
+	cancelFirst()
+	require.ErrorIs(t, <-firstErr, context.Canceled)
+
+	// A waiting caller whose context is done returns early.
+	doneCtx, cancelDone := context.WithCancel(context.Background())
+	cancelDone()
+	require.ErrorIs(t, c.pull(doneCtx, "img", pullFn, noDigest), context.Canceled)
+
+	// The shared pull is unaffected, and completes for the callers still waiting on it.
+	go func() { pullErr <- c.pull(context.Background(), "img", pullFn, noDigest) }()
+	close(release)
+	require.NoError(t, <-pullErr)
+}
+
+func TestPullCache_Timeout(t *testing.T) {
+	t.Parallel()
+
+	c := newPullCache(time.Millisecond)
+	err := c.pull(context.Background(), "img",
+		func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() },
+		func(context.Context) (string, error) { return "", nil },
+	)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,88 @@
+package dockerutil
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+	"golang.org/x/sync/singleflight"
+)
+
+// imagePulls deduplicates the image pulls of every chain and relayer in the process,
+// so that parallel tests using the same images pull each image at most once per run,
+// instead of each test pulling it concurrently and hitting registry rate limits.
+var imagePulls = newPullCache(imagePullTimeout)
+
+// imagePullTimeout bounds each shared image pull,
+// which runs independently of the contexts of the callers waiting on it.
+const imagePullTimeout = 15 * time.Minute
+
+// pullCache pulls images at most once, sharing a pull in progress with concurrent callers for the same image,
+// and skipping later pulls while the local image still has the digest it was pulled with.
+type pullCache struct {
+	group   singleflight.Group
+	timeout time.Duration // Timeout of each shared pull.
+
+	mu     sync.Mutex
+	pulled map[string]string // Digest of each image pulled, by pull key.
+}
+
+func newPullCache(timeout time.Duration) *pullCache {
+	return &pullCache{timeout: timeout, pulled: make(map[string]string)}
+}
+
+// pullImageOnce pulls the image ref for platform through cli, unless it was already pulled in this process
+// and the local image still has the digest it was pulled with.
+// A failed pull is not remembered, so that a later call pulls again.
+func pullImageOnce(ctx context.Context, cli *client.Client, ref, platform string) error {
+	key := cli.DaemonHost() + " " + platform + " " + ref
+	return imagePulls.pull(ctx, key,
+		func(ctx context.Context) error { return PullImage(ctx, cli, ref, platform) },
+		func(ctx context.Context) (string, error) { return ImageDigest(ctx, cli, ref) },
+	)
+}
+
+// pull calls pullFn unless the image identified by key was already pulled and localDigest returns
+// the digest that it had after that pull.
+// Concurrent calls for the same key share a single call to pullFn.
+// The shared call runs on its own context with the cache's timeout, rather than on the context of
+// the caller that started it, so that a caller giving up does not fail the pull for the others.
+// Each caller returns early if its ctx is done.
+func (c *pullCache) pull(
+	ctx context.Context,
+	key string,
+	pullFn func(context.Context) error,
+	localDigest func(context.Context) (string, error),
+) error {
+	c.mu.Lock()
+	digest, ok := c.pulled[key]
+	c.mu.Unlock()
+	if ok {
+		if local, err := localDigest(ctx); err == nil && local == digest {
+			return nil
+		}
+	}
+
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+
+		if err := pullFn(ctx); err != nil {
+			return nil, err
+		}
+		// Remembering the digest is best effort; without it, the next call pulls again.
+		if digest, err := localDigest(ctx); err == nil {
+			c.mu.Lock()
+			c.pulled[key] = digest
+			c.mu.Unlock()
+		}
+		return nil, nil
+	})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-ch:
+		return res.Err
+	}
+}
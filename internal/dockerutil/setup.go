@@ -26,6 +26,7 @@ type DockerSetupTestingT interface {
 	Cleanup(func())
 
 	Logf(format string, args ...any)
+	Fatalf(format string, args ...any)
 }
 
 // CleanupLabel is a docker label key targeted by DockerSetup when it cleans up docker resources.
@@ -59,15 +60,28 @@ var KeepVolumesOnFailure = os.Getenv("IBCTEST_SKIP_FAILURE_CLEANUP") != ""
 
 // DockerSetup returns a new Docker Client and the ID of a configured network, associated with t.
 //
-// If any part of the setup fails, DockerSetup panics because the test cannot continue.
+// If any part of the setup fails, t.Fatalf is called with the diagnosis of the failure.
 func DockerSetup(t DockerSetupTestingT) (*client.Client, string) {
 	t.Helper()
 
+	if err := resourceLabelsError(); err != nil {
+		t.Fatalf("Invalid docker labels: %v", err)
+	}
+	if err := stopGracePeriodError(); err != nil {
+		t.Fatalf("Invalid stop grace period: %v", err)
+	}
+	if err := diskBudgetError(); err != nil {
+		t.Fatalf("Invalid disk budget: %v", err)
+	}
+
 	cli, err := client.NewClientWithOpts(client.FromEnv)
 	if err != nil {
-		panic(fmt.Errorf("failed to create docker client: %v", err))
+		t.Fatalf("Failed to create docker client: %v", Diagnose(err))
 	}
 
+	markTestActive(t.Name())
+	t.Cleanup(func() { markTestFinished(t.Name()) })
+
 	// Clean up docker resources at end of test.
 	t.Cleanup(dockerCleanup(t, cli))
 
@@ -75,14 +89,18 @@ func DockerSetup(t DockerSetupTestingT) (*client.Client, string) {
 	// e.g. if the test was interrupted.
 	dockerCleanup(t, cli)()
 
+	if PruneFinishedTestVolumes {
+		pruneFinishedTestVolumes(context.TODO(), t, cli)
+	}
+
 	name := fmt.Sprintf("ibctest-%s", RandLowerCaseLetterString(8))
 	network, err := cli.NetworkCreate(context.TODO(), name, types.NetworkCreate{
 		CheckDuplicate: true,
 
-		Labels: map[string]string{CleanupLabel: t.Name()},
+		Labels: Labels(t.Name()),
 	})
 	if err != nil {
-		panic(fmt.Errorf("failed to create docker network: %v", err))
+		t.Fatalf("Failed to create docker network: %v", Diagnose(err))
 	}
 
 	return cli, network.ID
@@ -101,12 +119,13 @@ func dockerCleanup(t DockerSetupTestingT, cli *client.Client) func() {
 			),
 		})
 		if err != nil {
-			t.Logf("Failed to list containers during docker cleanup: %v", err)
+			t.Logf("Failed to list containers during docker cleanup: %v", Diagnose(err))
 			return
 		}
 
 		for _, c := range cs {
-			stopTimeout := 10 * time.Second
+			// Give nodes the same grace period as StopContainer, so that volumes kept for inspection are not corrupted.
+			stopTimeout := StopGracePeriod
 			deadline := time.Now().Add(stopTimeout)
 			if err := cli.ContainerStop(ctx, c.ID, &stopTimeout); isLoggableStopError(err) {
 				t.Logf("Failed to stop container %s during docker cleanup: %v", c.ID, err)
@@ -152,6 +171,9 @@ func dockerCleanup(t DockerSetupTestingT, cli *client.Client) func() {
 			}); err != nil {
 				t.Logf("Failed to remove container %s during docker cleanup: %v", c.ID, err)
 			}
+			for _, name := range c.Names {
+				ReleaseHostPorts(strings.TrimPrefix(name, "/"))
+			}
 		}
 
 		pruneVolumesWithRetry(ctx, t, cli)
@@ -8,12 +8,15 @@ import (
 	"github.com/avast/retry-go/v4"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
 )
 
 // StartContainer attempts to start the container with the given ID.
 // If the request times out, it retries a certain number of times before failing.
 // Any other failure modes stop immediately.
 func StartContainer(ctx context.Context, cli *client.Client, id string) error {
+	defer testreporter.StartPhase(ctx, testreporter.PhaseContainerStart, id)()
+
 	return retry.Do(
 		func() error {
 			retryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -27,7 +30,7 @@ func StartContainer(ctx context.Context, cli *client.Client, id string) error {
 				}
 
 				// Otherwise, assume the error cannot be retried.
-				return retry.Unrecoverable(err)
+				return retry.Unrecoverable(Diagnose(err))
 			}
 
 			return nil
@@ -0,0 +1,121 @@
+package dockerutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// StopGracePeriod is how long StopContainer waits for a container to exit after sending it SIGTERM,
+// before killing it.
+//
+// The value is 30 seconds by default, but can be initialized by setting the
+// environment variable IBCTEST_STOP_GRACE_PERIOD to a duration such as "1m".
+// If the environment variable is invalid, the value is -1 and DockerSetup fails with the parse error.
+// Because dockerutil is an internal package, the public API for setting this value
+// is ibctest.SetStopGracePeriod(time.Duration).
+var StopGracePeriod, stopGracePeriodErr = parseStopGracePeriod(os.Getenv("IBCTEST_STOP_GRACE_PERIOD"))
+
+// DefaultStopGracePeriod is the default value of StopGracePeriod.
+const DefaultStopGracePeriod = 30 * time.Second
+
+// parseStopGracePeriod parses the value of IBCTEST_STOP_GRACE_PERIOD, which may be empty for the default.
+func parseStopGracePeriod(s string) (time.Duration, error) {
+	if s == "" {
+		return DefaultStopGracePeriod, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return -1, fmt.Errorf("IBCTEST_STOP_GRACE_PERIOD: invalid value %q: must be a positive duration such as \"1m\"", s)
+	}
+	return d, nil
+}
+
+// stopGracePeriodError returns an error if StopGracePeriod is not positive,
+// e.g. because IBCTEST_STOP_GRACE_PERIOD is invalid.
+func stopGracePeriodError() error {
+	if StopGracePeriod > 0 {
+		return nil
+	}
+	if stopGracePeriodErr != nil {
+		return stopGracePeriodErr
+	}
+	return fmt.Errorf("invalid stop grace period %s: must be positive", StopGracePeriod)
+}
+
+// Exit codes of a process that exited cleanly, or by the default action of SIGINT or SIGTERM,
+// or by the SIGKILL that docker sends once the grace period is over, which are all expected when stopping a container.
+var cleanExitCodes = map[int]bool{0: true, 128 + 2: true, 128 + 9: true, 128 + 15: true}
+
+// StopContainer gracefully stops the container with the given ID,
+// sending it SIGTERM and waiting up to StopGracePeriod for it to exit before it is killed.
+//
+// Once the container has stopped, StopContainer verifies that it exited as expected, so that its volumes
+// are safe to use in later phases, such as exporting state or restarting the node.
+// Exits by SIGTERM, or by SIGKILL once the grace period is over, are expected.
+// It returns an *UncleanStopError if the container ran out of memory or exited with an error code.
+// A container that was not running is left as is.
+func StopContainer(ctx context.Context, cli *client.Client, id string) error {
+	c, err := cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return fmt.Errorf("inspect container %s: %w", id, err)
+	}
+	if !c.State.Running {
+		return nil
+	}
+
+	timeout := StopGracePeriod
+	if err := cli.ContainerStop(ctx, id, &timeout); err != nil {
+		return fmt.Errorf("stop container %s: %w", c.Name, err)
+	}
+
+	c, err = cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return fmt.Errorf("inspect stopped container %s: %w", id, err)
+	}
+	return stoppedCleanly(c.Name, c.State.ExitCode, c.State.OOMKilled)
+}
+
+// KillContainer kills the container with the given ID with SIGKILL, without letting its process shut down,
+// e.g. to test that a node recovers its database after a crash, and waits for the container to stop.
+// The Supervisor does not restart a container killed this way.
+func KillContainer(ctx context.Context, cli *client.Client, id string) error {
+	waitCh, errCh := cli.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	if err := cli.ContainerKill(ctx, id, "SIGKILL"); err != nil {
+		return fmt.Errorf("kill container %s: %w", id, err)
+	}
+	select {
+	case <-waitCh:
+		return nil
+	case err := <-errCh:
+		return fmt.Errorf("wait for killed container %s: %w", id, err)
+	}
+}
+
+// UncleanStopError is returned by StopContainer when a container did not exit cleanly.
+type UncleanStopError struct {
+	// Name of the container that was stopped.
+	Container string
+
+	ExitCode  int
+	OOMKilled bool
+}
+
+func (e *UncleanStopError) Error() string {
+	if e.OOMKilled {
+		return fmt.Sprintf("container %s ran out of memory while stopping", e.Container)
+	}
+	return fmt.Sprintf("container %s exited with code %d while stopping", e.Container, e.ExitCode)
+}
+
+// stoppedCleanly returns an *UncleanStopError if a container that stopped with exitCode did not stop cleanly.
+func stoppedCleanly(name string, exitCode int, oomKilled bool) error {
+	if oomKilled || !cleanExitCodes[exitCode] {
+		return &UncleanStopError{Container: name, ExitCode: exitCode, OOMKilled: oomKilled}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package dockerutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoppedCleanly(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []int{0, 130, 137, 143} {
+		require.NoError(t, stoppedCleanly("node", code, false), code)
+	}
+
+	for _, tt := range []struct {
+		exitCode  int
+		oomKilled bool
+		want      string
+	}{
+		{1, false, "container node exited with code 1 while stopping"},
+		{137, true, "container node ran out of memory while stopping"},
+	} {
+		err := stoppedCleanly("node", tt.exitCode, tt.oomKilled)
+		var uncleanErr *UncleanStopError
+		require.True(t, errors.As(err, &uncleanErr))
+		require.Equal(t, tt.exitCode, uncleanErr.ExitCode)
+		require.EqualError(t, err, tt.want)
+	}
+}
+
+func TestParseStopGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	d, err := parseStopGracePeriod("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultStopGracePeriod, d)
+
+	d, err = parseStopGracePeriod("1m")
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, d)
+
+	for _, s := range []string{"30", "0s", "-1m"} {
+		d, err = parseStopGracePeriod(s)
+		require.EqualError(t, err, `IBCTEST_STOP_GRACE_PERIOD: invalid value "`+s+`": must be a positive duration such as "1m"`)
+		require.Equal(t, time.Duration(-1), d)
+	}
+}
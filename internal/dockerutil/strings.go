@@ -14,6 +14,7 @@ import (
 )
 
 // GetHostPort returns a resource's published port with an address.
+// Ports published on all interfaces are addressed through PublishedPortsHost.
 // cont is the type returned by the Docker client's ContainerInspect method.
 func GetHostPort(cont types.ContainerJSON, portID string) string {
 	if cont.NetworkSettings == nil {
@@ -27,7 +28,7 @@ func GetHostPort(cont types.ContainerJSON, portID string) string {
 
 	ip := m[0].HostIP
 	if ip == "0.0.0.0" {
-		ip = "localhost"
+		ip = PublishedPortsHost
 	}
 	return net.JoinHostPort(ip, m[0].HostPort)
 }
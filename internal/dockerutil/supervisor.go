@@ -0,0 +1,311 @@
+package dockerutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"go.uber.org/zap"
+)
+
+// MaxContainerRestarts is the number of times a Supervisor restarts a crashed container
+// before reporting the crash as a fatal error.
+//
+// The value is 0 by default, so that any crash is fatal, but can be initialized by setting the
+// environment variable IBCTEST_MAX_CONTAINER_RESTARTS.
+// If the variable is invalid, the value is -1, so that NewSupervisor returns an error until the value is set.
+// Because dockerutil is an internal package, the public API for setting this value
+// is ibctest.SetMaxContainerRestarts(int).
+var MaxContainerRestarts, maxContainerRestartsErr = parseMaxContainerRestarts(os.Getenv("IBCTEST_MAX_CONTAINER_RESTARTS"))
+
+// parseMaxContainerRestarts parses the value of IBCTEST_MAX_CONTAINER_RESTARTS, which may be empty for 0.
+func parseMaxContainerRestarts(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return -1, fmt.Errorf("IBCTEST_MAX_CONTAINER_RESTARTS: invalid value %q: must be a non-negative integer", s)
+	}
+	return n, nil
+}
+
+// ContainerExitError is returned when a supervised container exits unexpectedly
+// more often than it may be restarted.
+type ContainerExitError struct {
+	// Name of the container that exited.
+	Container string
+
+	ExitCode  string
+	OOMKilled bool
+
+	// Number of times the container had been restarted before the final exit.
+	Restarts int
+}
+
+func (e *ContainerExitError) Error() string {
+	msg := fmt.Sprintf("container %s exited unexpectedly with code %s", e.Container, e.ExitCode)
+	if e.OOMKilled {
+		msg += " after running out of memory"
+	}
+	if e.Restarts > 0 {
+		msg += fmt.Sprintf(" (restarted %d times)", e.Restarts)
+	}
+	return msg
+}
+
+// UnhealthyError is returned when the health check of a Supervisor reports that its containers
+// cannot make progress, or fails for longer than UnresponsiveTimeout, while they are running.
+type UnhealthyError struct {
+	Reason string
+}
+
+func (e *UnhealthyError) Error() string {
+	return "supervised containers are unhealthy: " + e.Reason
+}
+
+// HealthCheck reports whether the chain run by supervised containers is live, i.e. can still make progress,
+// and why it is not. It returns an error if the health cannot be determined, e.g. while a node does not respond.
+type HealthCheck func(ctx context.Context) (live bool, reason string, err error)
+
+const (
+	// HealthCheckInterval is how often a Supervisor runs its health check.
+	HealthCheckInterval = 5 * time.Second
+
+	// UnresponsiveTimeout is how long the health check of a Supervisor may keep failing
+	// before the supervised containers are reported as unhealthy.
+	UnresponsiveTimeout = time.Minute
+)
+
+// Supervisor watches containers for unexpected exits, such as crashes or OOM kills,
+// and restarts them with the same volumes up to MaxContainerRestarts times.
+//
+// Exits caused by stopping or killing a container through the Docker API,
+// e.g. during a chain upgrade or test cleanup, are expected and ignored.
+type Supervisor struct {
+	log *zap.Logger
+	cli *client.Client
+
+	// Optional. Follows the logs of restarted containers again.
+	logWatcher *LogWatcher
+
+	maxRestarts int
+
+	mu             sync.Mutex
+	watching       map[string]bool
+	watchingHealth bool
+	err            error
+}
+
+// NewSupervisor returns a Supervisor that restarts crashed containers up to MaxContainerRestarts times.
+// If logWatcher is not nil, restarted containers are watched by it again.
+// NewSupervisor returns an error if MaxContainerRestarts is negative.
+func NewSupervisor(log *zap.Logger, cli *client.Client, logWatcher *LogWatcher) (*Supervisor, error) {
+	if MaxContainerRestarts < 0 {
+		if maxContainerRestartsErr != nil {
+			return nil, maxContainerRestartsErr
+		}
+		return nil, fmt.Errorf("invalid max container restarts %d: must not be negative", MaxContainerRestarts)
+	}
+	return &Supervisor{
+		log:         log,
+		cli:         cli,
+		logWatcher:  logWatcher,
+		maxRestarts: MaxContainerRestarts,
+		watching:    make(map[string]bool),
+	}, nil
+}
+
+// Watch begins supervising the container in the background, if it is not already supervised.
+// Supervision stops when the container is removed.
+func (s *Supervisor) Watch(containerID, containerName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watching[containerID] {
+		return
+	}
+	s.watching[containerID] = true
+
+	// Like the LogWatcher, supervision must outlive the context used to start the container.
+	ctx, cancel := context.WithCancel(context.Background())
+	msgs, errs := s.cli.Events(ctx, types.EventsOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("type", events.ContainerEventType),
+			filters.Arg("container", containerID),
+		),
+	})
+
+	go func() {
+		defer cancel()
+		defer func() {
+			s.mu.Lock()
+			delete(s.watching, containerID)
+			s.mu.Unlock()
+		}()
+
+		st := containerState{name: containerName}
+		for {
+			select {
+			case msg := <-msgs:
+				switch s.handleEvent(&st, msg) {
+				case supervisorRestart:
+					s.restart(containerID, containerName)
+				case supervisorStop:
+					return
+				}
+			case err := <-errs:
+				s.log.Info("Stopped supervising container", zap.String("container", containerName), zap.Error(err))
+				return
+			}
+		}
+	}()
+}
+
+// WatchHealth runs check every HealthCheckInterval in the background, if it is not already running,
+// until the Supervisor fails or no longer supervises any container.
+// The Supervisor fails with an *UnhealthyError once check reports that the chain is not live,
+// or keeps returning errors for UnresponsiveTimeout, e.g. because a running node hangs.
+// Chains call WatchHealth after starting their supervised containers.
+func (s *Supervisor) WatchHealth(check HealthCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watchingHealth {
+		return
+	}
+	s.watchingHealth = true
+
+	go s.watchHealth(check, HealthCheckInterval, UnresponsiveTimeout)
+}
+
+func (s *Supervisor) watchHealth(check HealthCheck, interval, unresponsiveTimeout time.Duration) {
+	defer func() {
+		s.mu.Lock()
+		s.watchingHealth = false
+		s.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var failingSince time.Time
+	for range ticker.C {
+		s.mu.Lock()
+		done := s.err != nil || len(s.watching) == 0
+		s.mu.Unlock()
+		if done {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		live, reason, err := check(ctx)
+		cancel()
+		switch {
+		case err != nil:
+			if failingSince.IsZero() {
+				failingSince = time.Now()
+			}
+			if time.Since(failingSince) >= unresponsiveTimeout {
+				s.setErr(&UnhealthyError{Reason: fmt.Sprintf("health check failing for %s: %v", unresponsiveTimeout, err)})
+				return
+			}
+		case !live:
+			s.setErr(&UnhealthyError{Reason: reason})
+			return
+		default:
+			failingSince = time.Time{}
+		}
+	}
+}
+
+// Err returns the first unexpected exit that exceeded the restart limit across all supervised containers,
+// or the first *UnhealthyError reported through WatchHealth, or nil if there has been none.
+func (s *Supervisor) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+type supervisorAction int
+
+const (
+	supervisorContinue supervisorAction = iota
+	supervisorRestart
+	supervisorStop
+)
+
+// containerState tracks the events of one supervised container since it last started.
+type containerState struct {
+	name string
+
+	killed, oomKilled bool
+	restarts          int
+}
+
+// handleEvent updates st with the container event msg and returns what to do next.
+func (s *Supervisor) handleEvent(st *containerState, msg events.Message) supervisorAction {
+	switch msg.Action {
+	case "start":
+		st.killed, st.oomKilled = false, false
+	case "kill":
+		// Docker API stops and kills, including test cleanup, send a kill event before the container dies.
+		st.killed = true
+	case "oom":
+		st.oomKilled = true
+	case "destroy":
+		return supervisorStop
+	case "die":
+		exitCode := msg.Actor.Attributes["exitCode"]
+		if st.killed && !st.oomKilled {
+			return supervisorContinue
+		}
+
+		s.log.Warn(
+			"Container exited unexpectedly",
+			zap.String("container", st.name),
+			zap.String("exit_code", exitCode),
+			zap.Bool("oom_killed", st.oomKilled),
+			zap.Int("restarts", st.restarts),
+		)
+		if st.restarts < s.maxRestarts {
+			st.restarts++
+			return supervisorRestart
+		}
+
+		s.setErr(&ContainerExitError{
+			Container: st.name,
+			ExitCode:  exitCode,
+			OOMKilled: st.oomKilled,
+			Restarts:  st.restarts,
+		})
+		return supervisorStop
+	}
+	return supervisorContinue
+}
+
+func (s *Supervisor) restart(containerID, containerName string) {
+	if err := StartContainer(context.Background(), s.cli, containerID); err != nil {
+		s.log.Error("Failed to restart container", zap.String("container", containerName), zap.Error(err))
+		s.setErr(&ContainerExitError{Container: containerName, ExitCode: "unknown"})
+		return
+	}
+	s.log.Info("Restarted container", zap.String("container", containerName))
+	if s.logWatcher != nil {
+		s.logWatcher.Watch(containerID, containerName)
+	}
+}
+
+func (s *Supervisor) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.log.Error("Supervised containers failed", zap.Error(err))
+		s.err = err
+	}
+}
@@ -0,0 +1,128 @@
+package dockerutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func dieEvent(exitCode string) events.Message {
+	return events.Message{
+		Action: "die",
+		Actor:  events.Actor{Attributes: map[string]string{"exitCode": exitCode}},
+	}
+}
+
+func TestSupervisor_HandleEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stopped through the API", func(t *testing.T) {
+		s := &Supervisor{log: zap.NewNop(), maxRestarts: 0}
+		st := containerState{name: "node"}
+
+		require.Equal(t, supervisorContinue, s.handleEvent(&st, events.Message{Action: "kill"}))
+		require.Equal(t, supervisorContinue, s.handleEvent(&st, dieEvent("143")))
+		require.NoError(t, s.Err())
+
+		require.Equal(t, supervisorStop, s.handleEvent(&st, events.Message{Action: "destroy"}))
+	})
+
+	t.Run("restarts until limit", func(t *testing.T) {
+		s := &Supervisor{log: zap.NewNop(), maxRestarts: 1}
+		st := containerState{name: "node"}
+
+		require.Equal(t, supervisorRestart, s.handleEvent(&st, dieEvent("1")))
+		require.NoError(t, s.Err())
+
+		require.Equal(t, supervisorContinue, s.handleEvent(&st, events.Message{Action: "start"}))
+		require.Equal(t, supervisorStop, s.handleEvent(&st, dieEvent("2")))
+
+		var exitErr *ContainerExitError
+		require.ErrorAs(t, s.Err(), &exitErr)
+		require.Equal(t, ContainerExitError{Container: "node", ExitCode: "2", Restarts: 1}, *exitErr)
+	})
+
+	t.Run("oom kill", func(t *testing.T) {
+		s := &Supervisor{log: zap.NewNop(), maxRestarts: 0}
+		st := containerState{name: "collator"}
+
+		require.Equal(t, supervisorContinue, s.handleEvent(&st, events.Message{Action: "oom"}))
+		require.Equal(t, supervisorContinue, s.handleEvent(&st, events.Message{Action: "kill"}))
+		require.Equal(t, supervisorStop, s.handleEvent(&st, dieEvent("137")))
+		require.EqualError(t, s.Err(), "container collator exited unexpectedly with code 137 after running out of memory")
+	})
+}
+
+func TestSupervisor_WatchHealth(t *testing.T) {
+	t.Parallel()
+
+	newSupervisor := func() *Supervisor {
+		return &Supervisor{log: zap.NewNop(), watching: map[string]bool{"node": true}}
+	}
+
+	t.Run("not live", func(t *testing.T) {
+		s := newSupervisor()
+		s.watchHealth(func(context.Context) (bool, string, error) {
+			return false, "consensus failure", nil
+		}, time.Millisecond, time.Hour)
+
+		var unhealthy *UnhealthyError
+		require.ErrorAs(t, s.Err(), &unhealthy)
+		require.Equal(t, "consensus failure", unhealthy.Reason)
+	})
+
+	t.Run("unresponsive", func(t *testing.T) {
+		s := newSupervisor()
+		checks := 0
+		s.watchHealth(func(context.Context) (bool, string, error) {
+			checks++
+			if checks == 1 {
+				return true, "", nil
+			}
+			return false, "", errors.New("connection refused")
+		}, time.Millisecond, 20*time.Millisecond)
+
+		require.ErrorContains(t, s.Err(), "connection refused")
+		require.Greater(t, checks, 2)
+	})
+
+	t.Run("stops without containers", func(t *testing.T) {
+		s := newSupervisor()
+		checks := 0
+		s.watchHealth(func(context.Context) (bool, string, error) {
+			checks++
+			if checks == 3 {
+				s.mu.Lock()
+				delete(s.watching, "node")
+				s.mu.Unlock()
+			}
+			return true, "", nil
+		}, time.Millisecond, time.Hour)
+
+		require.NoError(t, s.Err())
+		require.Equal(t, 3, checks)
+	})
+}
+
+func TestParseMaxContainerRestarts(t *testing.T) {
+	t.Parallel()
+
+	n, err := parseMaxContainerRestarts("")
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	n, err = parseMaxContainerRestarts("3")
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	for _, s := range []string{"three", "-1"} {
+		n, err = parseMaxContainerRestarts(s)
+		require.EqualError(t, err, `IBCTEST_MAX_CONTAINER_RESTARTS: invalid value "`+s+`": must be a non-negative integer`)
+		require.Equal(t, -1, n)
+	}
+}
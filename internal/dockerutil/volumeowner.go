@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
 	"go.uber.org/zap"
 )
 
@@ -25,6 +26,8 @@ type VolumeOwnerOptions struct {
 
 // SetVolumeOwner configures the owner of a volume to match the default user in the supplied image reference.
 func SetVolumeOwner(ctx context.Context, opts VolumeOwnerOptions) error {
+	defer testreporter.StartPhase(ctx, testreporter.PhaseVolumeSetup, opts.VolumeName)()
+
 	owner := opts.UidGid
 	if owner == "" {
 		owner = GetRootUserString()
@@ -55,7 +58,7 @@ func SetVolumeOwner(ctx context.Context, opts VolumeOwnerOptions) error {
 			// Root user so we have permissions to set ownership and mode.
 			User: GetRootUserString(),
 
-			Labels: map[string]string{CleanupLabel: opts.TestName},
+			Labels: Labels(opts.TestName),
 		},
 		&container.HostConfig{
 			Binds:      []string{opts.VolumeName + ":" + mountPath},
@@ -0,0 +1,218 @@
+package dockerutil
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+)
+
+// DiskBudget is the maximum number of bytes that the volumes of a single test may use,
+// as checked by CheckDiskBudget. A value of 0 disables the check.
+//
+// The value is 0 by default, but can be initialized by setting the
+// environment variable IBCTEST_DISK_BUDGET_MB to a number of megabytes.
+// If the environment variable is invalid, the value is -1 and DockerSetup fails with the parse error.
+// Because dockerutil is an internal package, the public API for setting this value
+// is ibctest.SetDiskBudget(int64).
+var DiskBudget, diskBudgetErr = parseDiskBudget(os.Getenv("IBCTEST_DISK_BUDGET_MB"))
+
+// maxDiskBudgetMB is the largest number of megabytes whose size in bytes fits an int64.
+const maxDiskBudgetMB = math.MaxInt64 >> 20
+
+// parseDiskBudget parses the value of IBCTEST_DISK_BUDGET_MB, which may be empty for 0, into bytes.
+func parseDiskBudget(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	mb, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || mb < 0 || mb > maxDiskBudgetMB {
+		return -1, fmt.Errorf("IBCTEST_DISK_BUDGET_MB: invalid value %q: must be a number of megabytes from 0 to %d", s, int64(maxDiskBudgetMB))
+	}
+	return mb * 1024 * 1024, nil
+}
+
+// diskBudgetError returns an error if DiskBudget is negative, e.g. because IBCTEST_DISK_BUDGET_MB is invalid.
+func diskBudgetError() error {
+	if DiskBudget >= 0 {
+		return nil
+	}
+	if diskBudgetErr != nil {
+		return diskBudgetErr
+	}
+	return fmt.Errorf("invalid disk budget %d: must not be negative", DiskBudget)
+}
+
+// PruneFinishedTestVolumes determines whether DockerSetup removes unused volumes
+// left behind by tests that are no longer running, such as volumes kept after a failure,
+// or volumes of an interrupted test run.
+//
+// The value is false by default, but can be initialized to true by setting the
+// environment variable IBCTEST_PRUNE_FINISHED_VOLUMES to a non-empty value.
+// Because dockerutil is an internal package, the public API for setting this value
+// is ibctest.PruneFinishedTestVolumes(bool).
+var PruneFinishedTestVolumes = os.Getenv("IBCTEST_PRUNE_FINISHED_VOLUMES") != ""
+
+// VolumeUsage is the disk usage of a docker volume created for a test.
+type VolumeUsage struct {
+	Name string
+
+	// Logical node owning the volume, if any.
+	Owner string
+
+	// Size in bytes, or -1 if the volume driver does not report it.
+	Size int64
+}
+
+// TestVolumeUsage returns the disk usage of the volumes created for the test testName, largest first,
+// and the total size of the volumes that report their size.
+//
+// Docker computes volume sizes by walking the volumes, so this may be slow with large volumes.
+func TestVolumeUsage(ctx context.Context, cli *client.Client, testName string) ([]VolumeUsage, int64, error) {
+	du, err := cli.DiskUsage(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get docker disk usage: %w", err)
+	}
+
+	var (
+		usages []VolumeUsage
+		total  int64
+	)
+	for _, v := range du.Volumes {
+		if v.Labels[CleanupLabel] != testName {
+			continue
+		}
+		size := int64(-1)
+		if v.UsageData != nil {
+			size = v.UsageData.Size
+		}
+		if size > 0 {
+			total += size
+		}
+		usages = append(usages, VolumeUsage{Name: v.Name, Owner: v.Labels[NodeOwnerLabel], Size: size})
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool { return usages[i].Size > usages[j].Size })
+	return usages, total, nil
+}
+
+// DiskBudgetExceededError is returned by CheckDiskBudget when a test's volumes use more than DiskBudget.
+type DiskBudgetExceededError struct {
+	TestName string
+
+	// Bytes used by the test's volumes, and the budget.
+	Used, Budget int64
+
+	// The test's volumes, largest first.
+	Volumes []VolumeUsage
+}
+
+func (e *DiskBudgetExceededError) Error() string {
+	const maxListed = 5
+
+	var largest []string
+	for i, v := range e.Volumes {
+		if i == maxListed {
+			largest = append(largest, fmt.Sprintf("and %d more", len(e.Volumes)-maxListed))
+			break
+		}
+		name := v.Name
+		if v.Owner != "" {
+			name = v.Owner
+		}
+		largest = append(largest, fmt.Sprintf("%s: %.1f MB", name, megabytes(v.Size)))
+	}
+
+	return fmt.Sprintf(
+		"volumes of test %s use %.1f MB, exceeding the disk budget of %.1f MB (%s)",
+		e.TestName, megabytes(e.Used), megabytes(e.Budget), strings.Join(largest, ", "),
+	)
+}
+
+func megabytes(b int64) float64 {
+	return float64(b) / (1024 * 1024)
+}
+
+// CheckDiskBudget returns a *DiskBudgetExceededError if the volumes of the test testName
+// use more than DiskBudget bytes.
+// It returns nil without inspecting volumes if DiskBudget is not set.
+func CheckDiskBudget(ctx context.Context, cli *client.Client, testName string) error {
+	budget := DiskBudget
+	if budget <= 0 {
+		return nil
+	}
+
+	usages, total, err := TestVolumeUsage(ctx, cli, testName)
+	if err != nil {
+		return err
+	}
+	if total > budget {
+		return &DiskBudgetExceededError{TestName: testName, Used: total, Budget: budget, Volumes: usages}
+	}
+	return nil
+}
+
+// activeTests holds the names of the tests in this process that called DockerSetup and have not finished,
+// whose volumes must not be pruned even while no container uses them.
+var activeTests = struct {
+	mu    sync.Mutex
+	names map[string]int
+}{names: make(map[string]int)}
+
+func markTestActive(testName string) {
+	activeTests.mu.Lock()
+	defer activeTests.mu.Unlock()
+	activeTests.names[testName]++
+}
+
+func markTestFinished(testName string) {
+	activeTests.mu.Lock()
+	defer activeTests.mu.Unlock()
+	if activeTests.names[testName]--; activeTests.names[testName] <= 0 {
+		delete(activeTests.names, testName)
+	}
+}
+
+func isTestActive(testName string) bool {
+	activeTests.mu.Lock()
+	defer activeTests.mu.Unlock()
+	return activeTests.names[testName] > 0
+}
+
+// pruneFinishedTestVolumes removes the volumes created for tests that are not active in this process,
+// and that are not used by any container.
+// Tests running concurrently in other processes are not known,
+// so this should only be enabled when a single test process uses the docker host.
+func pruneFinishedTestVolumes(ctx context.Context, t DockerSetupTestingT, cli *client.Client) {
+	res, err := cli.VolumeList(ctx, filters.NewArgs(filters.Arg("label", CleanupLabel), filters.Arg("dangling", "true")))
+	if err != nil {
+		t.Logf("Failed to list volumes of finished tests: %v", err)
+		return
+	}
+
+	var pruned int
+	for _, v := range res.Volumes {
+		if isTestActive(v.Labels[CleanupLabel]) {
+			continue
+		}
+		if err := cli.VolumeRemove(ctx, v.Name, false); err != nil {
+			if !errdefs.IsConflict(err) && !errdefs.IsNotFound(err) {
+				t.Logf("Failed to remove volume %s of finished test %s: %v", v.Name, v.Labels[CleanupLabel], err)
+			}
+			continue
+		}
+		pruned++
+	}
+
+	if pruned > 0 {
+		t.Logf("Pruned %d volumes of finished tests", pruned)
+	}
+}
@@ -0,0 +1,68 @@
+package dockerutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskBudgetExceededError(t *testing.T) {
+	t.Parallel()
+
+	const mb = 1024 * 1024
+
+	err := &DiskBudgetExceededError{
+		TestName: "TestFoo",
+		Used:     3 * mb,
+		Budget:   2 * mb,
+		Volumes: []VolumeUsage{
+			{Name: "vol-a", Owner: "gaia-val-0-TestFoo", Size: 2 * mb},
+			{Name: "vol-b", Size: mb},
+		},
+	}
+	require.EqualError(t, err, "volumes of test TestFoo use 3.0 MB, exceeding the disk budget of 2.0 MB (gaia-val-0-TestFoo: 2.0 MB, vol-b: 1.0 MB)")
+
+	err.Volumes = nil
+	for i := 0; i < 7; i++ {
+		err.Volumes = append(err.Volumes, VolumeUsage{Name: fmt.Sprintf("vol-%d", i), Size: mb})
+	}
+	require.Contains(t, err.Error(), "vol-4: 1.0 MB, and 2 more)")
+}
+
+func TestActiveTests(t *testing.T) {
+	t.Parallel()
+
+	name := t.Name()
+	require.False(t, isTestActive(name))
+
+	markTestActive(name)
+	markTestActive(name)
+	markTestFinished(name)
+	require.True(t, isTestActive(name))
+
+	markTestFinished(name)
+	require.False(t, isTestActive(name))
+}
+
+func TestParseDiskBudget(t *testing.T) {
+	t.Parallel()
+
+	b, err := parseDiskBudget("")
+	require.NoError(t, err)
+	require.Zero(t, b)
+
+	b, err = parseDiskBudget("512")
+	require.NoError(t, err)
+	require.Equal(t, int64(512*1024*1024), b)
+
+	b, err = parseDiskBudget(fmt.Sprint(maxDiskBudgetMB))
+	require.NoError(t, err)
+	require.Positive(t, b)
+
+	for _, s := range []string{"10GB", "-1", fmt.Sprint(maxDiskBudgetMB + 1)} {
+		b, err = parseDiskBudget(s)
+		require.ErrorContains(t, err, `IBCTEST_DISK_BUDGET_MB: invalid value "`+s+`"`)
+		require.Equal(t, int64(-1), b)
+	}
+}
@@ -119,6 +119,13 @@ func (t *T) FailNow() {
 	runtime.Goexit()
 }
 
+// Fatalf appends the formatted error message to t.Errors and then calls t.FailNow.
+// Fatalf panics if called outside the context of RunTest.
+func (t *T) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	t.FailNow()
+}
+
 // Parallel blocks for the configured t.ParallelDelay and then returns.
 func (t *T) Parallel() {
 	time.Sleep(t.ParallelDelay)
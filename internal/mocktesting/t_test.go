@@ -146,6 +146,20 @@ func TestT_FailNow(t *testing.T) {
 	})
 }
 
+func TestT_Fatalf(t *testing.T) {
+	continuedAfterFatalf := false
+	mt := mocktesting.NewT("x")
+
+	mt.Simulate(func() {
+		mt.Fatalf("failed %d", 1)
+		continuedAfterFatalf = true
+	})
+
+	require.False(t, continuedAfterFatalf, "control flow continued after t.Fatalf")
+	require.True(t, mt.Failed())
+	require.Equal(t, []string{"failed 1"}, mt.Errors)
+}
+
 func TestT_Parallel(t *testing.T) {
 	const delay = 10 * time.Millisecond
 
@@ -0,0 +1,123 @@
+package ibctest
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/cosmos/go-bip39"
+)
+
+// mnemonicSeedRandom is the SetMnemonicSeed value that generates a new random master seed.
+const mnemonicSeedRandom = "random"
+
+var (
+	mnemonicMu sync.Mutex
+
+	// Master seed that generated mnemonics are derived from, or nil for independently random mnemonics.
+	// If IBCTEST_MNEMONIC_SEED is invalid, mnemonicSeedErr holds the error, which deriveMnemonic returns
+	// rather than the importing program panicking, until the seed is replaced.
+	mnemonicSeed, mnemonicSeedErr = parseMnemonicSeedEnv(os.Getenv("IBCTEST_MNEMONIC_SEED"))
+
+	// Number of mnemonics derived per scope, so that repeated derivations in a scope differ.
+	mnemonicCounts = make(map[string]int)
+)
+
+// SetMnemonicSeed sets the master seed that mnemonics generated for test users and relayer wallets are derived from.
+// Each derived mnemonic depends only on the master seed, the test name, and the chain,
+// so rerunning a test with the logged seed reproduces its addresses and keys exactly.
+//
+// The seed may be a hex string to reproduce an earlier run, "random" to generate and log a new seed,
+// or empty to generate independently random mnemonics, which is the default.
+// The seed can also be initialized by setting the environment variable IBCTEST_MNEMONIC_SEED.
+func SetMnemonicSeed(seed string) error {
+	s, err := parseMnemonicSeed(seed)
+	if err != nil {
+		return err
+	}
+	setMnemonicSeed(s)
+	return nil
+}
+
+// setMnemonicSeed sets the parsed master seed, restarting the derivations of every scope.
+func setMnemonicSeed(seed []byte) {
+	mnemonicMu.Lock()
+	defer mnemonicMu.Unlock()
+	mnemonicSeed = seed
+	mnemonicSeedErr = nil
+	mnemonicCounts = make(map[string]int)
+}
+
+// MnemonicSeed returns the hex encoded master seed set by SetMnemonicSeed,
+// or an empty string if mnemonics are independently random.
+func MnemonicSeed() string {
+	mnemonicMu.Lock()
+	defer mnemonicMu.Unlock()
+	return hex.EncodeToString(mnemonicSeed)
+}
+
+func parseMnemonicSeed(seed string) ([]byte, error) {
+	switch seed {
+	case "":
+		return nil, nil
+	case mnemonicSeedRandom:
+		s := make([]byte, 32)
+		if _, err := rand.Read(s); err != nil {
+			return nil, fmt.Errorf("failed to generate mnemonic seed: %w", err)
+		}
+		return s, nil
+	default:
+		s, err := hex.DecodeString(seed)
+		if err != nil || len(s) == 0 {
+			return nil, fmt.Errorf("invalid mnemonic seed %q: must be hex, %q, or empty", seed, mnemonicSeedRandom)
+		}
+		return s, nil
+	}
+}
+
+// parseMnemonicSeedEnv parses the value of IBCTEST_MNEMONIC_SEED.
+func parseMnemonicSeedEnv(seed string) ([]byte, error) {
+	s, err := parseMnemonicSeed(seed)
+	if err != nil {
+		return nil, fmt.Errorf("IBCTEST_MNEMONIC_SEED: %w", err)
+	}
+	return s, nil
+}
+
+// mnemonicSeedError returns the error of an invalid IBCTEST_MNEMONIC_SEED, unless the seed has since been replaced.
+func mnemonicSeedError() error {
+	mnemonicMu.Lock()
+	defer mnemonicMu.Unlock()
+	return mnemonicSeedErr
+}
+
+// deriveMnemonic returns the next mnemonic derived from the master seed for the given scope,
+// or an empty string if there is no master seed.
+// It returns an error if IBCTEST_MNEMONIC_SEED is invalid and the seed has not been replaced.
+func deriveMnemonic(scope ...string) (string, error) {
+	mnemonicMu.Lock()
+	defer mnemonicMu.Unlock()
+
+	if mnemonicSeedErr != nil {
+		return "", mnemonicSeedErr
+	}
+	if mnemonicSeed == nil {
+		return "", nil
+	}
+
+	h := sha256.New()
+	h.Write(mnemonicSeed)
+	var key string
+	for _, s := range scope {
+		key += s + "\x00"
+	}
+	h.Write([]byte(key))
+	h.Write([]byte(strconv.Itoa(mnemonicCounts[key])))
+	mnemonicCounts[key]++
+
+	return bip39.NewMnemonic(h.Sum(nil))
+}
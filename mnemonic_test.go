@@ -0,0 +1,73 @@
+package ibctest
+
+import (
+	"testing"
+
+	"github.com/cosmos/go-bip39"
+	"github.com/stretchr/testify/require"
+)
+
+// Not parallel, because the mnemonic seed is global.
+func TestDeriveMnemonic(t *testing.T) {
+	orig := MnemonicSeed()
+	t.Cleanup(func() {
+		require.NoError(t, SetMnemonicSeed(orig))
+	})
+
+	require.NoError(t, SetMnemonicSeed(""))
+	m, err := deriveMnemonic("test", "chain-1")
+	require.NoError(t, err)
+	require.Empty(t, m, "mnemonics must not be derived without a seed")
+
+	const seed = "00112233445566778899aabbccddeeff"
+	require.NoError(t, SetMnemonicSeed(seed))
+	require.Equal(t, seed, MnemonicSeed())
+
+	first, err := deriveMnemonic("test", "chain-1")
+	require.NoError(t, err)
+	require.True(t, bip39.IsMnemonicValid(first))
+
+	second, err := deriveMnemonic("test", "chain-1")
+	require.NoError(t, err)
+	require.NotEqual(t, first, second, "repeated derivations in a scope must differ")
+
+	other, err := deriveMnemonic("test", "chain-2")
+	require.NoError(t, err)
+	require.NotEqual(t, first, other)
+
+	// Resetting the seed reproduces the same sequence.
+	require.NoError(t, SetMnemonicSeed(seed))
+	again, err := deriveMnemonic("test", "chain-1")
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+
+	require.NoError(t, SetMnemonicSeed("random"))
+	require.Len(t, MnemonicSeed(), 64)
+
+	require.Error(t, SetMnemonicSeed("not hex"))
+}
+
+// Not parallel, because the mnemonic seed is global.
+func TestDeriveMnemonic_InvalidEnv(t *testing.T) {
+	mnemonicMu.Lock()
+	origSeed, origErr := mnemonicSeed, mnemonicSeedErr
+	mnemonicSeed, mnemonicSeedErr = parseMnemonicSeedEnv("not hex")
+	mnemonicMu.Unlock()
+	t.Cleanup(func() {
+		mnemonicMu.Lock()
+		mnemonicSeed, mnemonicSeedErr = origSeed, origErr
+		mnemonicMu.Unlock()
+	})
+
+	_, err := deriveMnemonic("test", "chain-1")
+	require.ErrorContains(t, err, "IBCTEST_MNEMONIC_SEED: invalid mnemonic seed")
+	require.Equal(t, err, mnemonicSeedError())
+	require.Empty(t, CurrentConfig().MnemonicSeed)
+
+	// Replacing the seed clears the error.
+	require.NoError(t, SetConfig(CurrentConfig()))
+	require.NoError(t, mnemonicSeedError())
+	m, err := deriveMnemonic("test", "chain-1")
+	require.NoError(t, err)
+	require.Empty(t, m)
+}
@@ -0,0 +1,132 @@
+package ibctest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+	"golang.org/x/sync/errgroup"
+)
+
+// ProfileOptions configures CollectProfiles.
+type ProfileOptions struct {
+	// How long CPU profiles of pprof endpoints are sampled for.
+	// Zero skips CPU profiles, collecting only heap and goroutine profiles.
+	CPUDuration time.Duration
+}
+
+// CollectProfiles saves the profiling data served by the chains and relayers of ic that implement ibc.Profiler,
+// e.g. at the end of a benchmark, so that runs can be compared for regressions.
+//
+// The profiles are written to a new directory under the profiles directory of ArtifactDir,
+// named after testName and the current time, which is returned.
+// pprof endpoints are saved as <container>.<profile>.pb.gz, readable with go tool pprof,
+// and metrics endpoints are saved as <container>.metrics.txt.
+func (ic *Interchain) CollectProfiles(ctx context.Context, testName string, opts ProfileOptions) (string, error) {
+	var endpoints []ibc.ProfileEndpoint
+	addEndpoints := func(x any) error {
+		p, ok := x.(ibc.Profiler)
+		if !ok {
+			return nil
+		}
+		e, err := p.ProfileEndpoints(ctx)
+		if err != nil {
+			return err
+		}
+		endpoints = append(endpoints, e...)
+		return nil
+	}
+	for c, chainID := range ic.chains {
+		if err := addEndpoints(c); err != nil {
+			return "", fmt.Errorf("profile endpoints of chain %s: %w", chainID, err)
+		}
+	}
+	for r, name := range ic.relayers {
+		if err := addEndpoints(r); err != nil {
+			return "", fmt.Errorf("profile endpoints of relayer %s: %w", name, err)
+		}
+	}
+
+	artifactDir, err := ArtifactDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(
+		artifactDir, "profiles",
+		dockerutil.SanitizeContainerName(testName)+"-"+time.Now().UTC().Format("20060102T150405Z"),
+	)
+	if err := collectProfiles(ctx, dir, endpoints, opts); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// collectProfiles fetches the profiles of every endpoint concurrently into dir.
+func collectProfiles(ctx context.Context, dir string, endpoints []ibc.ProfileEndpoint, opts ProfileOptions) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("mkdirall: %w", err)
+	}
+
+	var eg errgroup.Group
+	for _, e := range endpoints {
+		e := e
+
+		// Profile name to path of the profile on the endpoint.
+		var paths map[string]string
+		switch e.Kind {
+		case ibc.ProfileKindPprof:
+			paths = map[string]string{
+				"heap.pb.gz":      "/debug/pprof/heap",
+				"goroutine.pb.gz": "/debug/pprof/goroutine",
+			}
+			if opts.CPUDuration > 0 {
+				paths["cpu.pb.gz"] = fmt.Sprintf("/debug/pprof/profile?seconds=%d", int(opts.CPUDuration.Seconds()))
+			}
+		case ibc.ProfileKindMetrics:
+			paths = map[string]string{"metrics.txt": "/metrics"}
+		default:
+			return fmt.Errorf("unknown kind %q of profile endpoint of container %s", e.Kind, e.Container)
+		}
+
+		for name, path := range paths {
+			fpath := filepath.Join(dir, e.Container+"."+name)
+			url := "http://" + e.HostAddress + path
+			eg.Go(func() error {
+				return saveProfile(ctx, url, fpath)
+			})
+		}
+	}
+	return eg.Wait()
+}
+
+// saveProfile writes the response body of a GET request to url into the file at fpath.
+func saveProfile(ctx context.Context, url, fpath string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("get profile: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("get profile %s: unexpected status %s", url, res.Status)
+	}
+
+	f, err := os.Create(fpath)
+	if err != nil {
+		return fmt.Errorf("create profile file: %w", err)
+	}
+	if _, err := io.Copy(f, res.Body); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write profile %s: %w", fpath, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,69 @@
+package ibctest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectProfiles(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.String()))
+	}))
+	defer srv.Close()
+	addr := strings.TrimPrefix(srv.URL, "http://")
+
+	dir := filepath.Join(t.TempDir(), "profiles")
+	endpoints := []ibc.ProfileEndpoint{
+		{Container: "gaia-val-0", Kind: ibc.ProfileKindPprof, HostAddress: addr},
+		{Container: "rococo-alice", Kind: ibc.ProfileKindMetrics, HostAddress: addr},
+	}
+	require.NoError(t, collectProfiles(context.Background(), dir, endpoints, ProfileOptions{}))
+
+	for file, want := range map[string]string{
+		"gaia-val-0.heap.pb.gz":      "/debug/pprof/heap",
+		"gaia-val-0.goroutine.pb.gz": "/debug/pprof/goroutine",
+		"rococo-alice.metrics.txt":   "/metrics",
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+		require.Equal(t, want, string(got))
+	}
+
+	// CPU profiles are only collected with a duration.
+	_, err := os.Stat(filepath.Join(dir, "gaia-val-0.cpu.pb.gz"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	err = collectProfiles(context.Background(), dir, endpoints[:1], ProfileOptions{CPUDuration: 3 * time.Second})
+	require.NoError(t, err)
+	got, err := os.ReadFile(filepath.Join(dir, "gaia-val-0.cpu.pb.gz"))
+	require.NoError(t, err)
+	require.Equal(t, "/debug/pprof/profile?seconds=3", string(got))
+}
+
+func TestCollectProfiles_Errors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	endpoints := []ibc.ProfileEndpoint{
+		{Container: "gaia-val-0", Kind: ibc.ProfileKindPprof, HostAddress: strings.TrimPrefix(srv.URL, "http://")},
+	}
+	err := collectProfiles(context.Background(), t.TempDir(), endpoints, ProfileOptions{})
+	require.ErrorContains(t, err, "404")
+
+	endpoints[0].Kind = "flamegraph"
+	err = collectProfiles(context.Background(), t.TempDir(), endpoints, ProfileOptions{})
+	require.ErrorContains(t, err, `unknown kind "flamegraph"`)
+}
@@ -18,10 +18,31 @@ const (
 	// Whether the relayer supports a one-off flush packets or flush acknowledgements command.
 	FlushPackets
 	FlushAcknowledgements
+
+	// Whether the relayer can flush packets or acknowledgements in only one direction of a channel,
+	// through ibc.DirectionalFlusher.
+	DirectionalFlush
+
+	// Whether the relayer can close a channel, through ibc.ChannelCloser.
+	ChannelClose
+
+	// Whether the relayer signs its transactions with the extension options of ibc.RelayerGas,
+	// as required by e.g. ethermint chains, and honors its default and max gas.
+	ExtensionOptions
+
+	// Whether the relayer can create clients with the trust level of ibc.CreateClientOptions.
+	ClientTrustLevel
+
+	// Whether the relayer can create clients with the substrate client state of ibc.CreateClientOptions.
+	SubstrateClientState
+
+	// Whether the relayer can pay its fees from a fee allowance, through ibc.RelayerFeeGranter.
+	FeeGranter
 )
 
 // FullCapabilities returns a mapping of all known relayer features to true,
-// indicating that all features are supported.
+// indicating that all features are supported,
+// except DirectionalFlush, which relayers implementing ibc.DirectionalFlusher must set explicitly.
 // FullCapabilities returns a new map every time it is called,
 // so callers are free to set one value to false if they support everything but one or two features.
 func FullCapabilities() map[Capability]bool {
@@ -31,5 +52,14 @@ func FullCapabilities() map[Capability]bool {
 
 		FlushPackets:          true,
 		FlushAcknowledgements: true,
+		DirectionalFlush:      false,
+
+		ChannelClose:     true,
+		ExtensionOptions: true,
+
+		ClientTrustLevel:     true,
+		SubstrateClientState: true,
+
+		FeeGranter: true,
 	}
 }
@@ -12,11 +12,17 @@ func _() {
 	_ = x[HeightTimeout-1]
 	_ = x[FlushPackets-2]
 	_ = x[FlushAcknowledgements-3]
+	_ = x[DirectionalFlush-4]
+	_ = x[ChannelClose-5]
+	_ = x[ExtensionOptions-6]
+	_ = x[ClientTrustLevel-7]
+	_ = x[SubstrateClientState-8]
+	_ = x[FeeGranter-9]
 }
 
-const _Capability_name = "TimestampTimeoutHeightTimeoutFlushPacketsFlushAcknowledgements"
+const _Capability_name = "TimestampTimeoutHeightTimeoutFlushPacketsFlushAcknowledgementsDirectionalFlushChannelCloseExtensionOptionsClientTrustLevelSubstrateClientStateFeeGranter"
 
-var _Capability_index = [...]uint8{0, 16, 29, 41, 62}
+var _Capability_index = [...]uint8{0, 16, 29, 41, 62, 78, 90, 106, 122, 142, 152}
 
 func (i Capability) String() string {
 	if i < 0 || i >= Capability(len(_Capability_index)-1) {
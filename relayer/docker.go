@@ -4,10 +4,12 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -16,8 +18,10 @@ import (
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+	"github.com/strangelove-ventures/ibctest/v6/transcript"
 	"go.uber.org/zap"
 )
 
@@ -41,11 +45,49 @@ type DockerRelayer struct {
 	// The ID of the container created by StartRelayer.
 	containerID string
 
+	// Whether the relayer logs JSON that is parsed into r.log while it runs,
+	// and how to stop following the logs of the running container.
+	parseJSONLogs     bool
+	stopFollowingLogs func()
+
 	// wallets contains a mapping of chainID to relayer wallet
 	wallets map[string]ibc.Wallet
+
+	// Paths registered through GeneratePath or AddPath, in order.
+	pathsMu sync.Mutex
+	paths   []string
+
+	// Fee granters set through SetFeeGranter, keyed by chain ID.
+	feeGranters map[string]string
+
+	// Client update intervals set through SetClientUpdatePolicy, keyed by path name.
+	clientUpdateIntervals map[string]time.Duration
+
+	// Channel filters set through UpdatePath, keyed by path name,
+	// which SetClientUpdatePolicy restores when relaying packets again.
+	channelFilters map[string]ibc.ChannelFilter
+
+	// Whether the relayer serves pprof handlers while running,
+	// and the endpoint of the running relayer's debug server, guarded by debugMu
+	// as the relayer may be profiled while it is started or stopped.
+	profiling     bool
+	debugMu       sync.Mutex
+	debugEndpoint *ibc.ProfileEndpoint
 }
 
-var _ ibc.Relayer = (*DockerRelayer)(nil)
+var (
+	_ ibc.Relayer                = (*DockerRelayer)(nil)
+	_ ibc.DirectionalFlusher     = (*DockerRelayer)(nil)
+	_ ibc.PathRegistry           = (*DockerRelayer)(nil)
+	_ ibc.ClientUpdateController = (*DockerRelayer)(nil)
+	_ ibc.Profiler               = (*DockerRelayer)(nil)
+	_ ibc.RelayerImageResolver   = (*DockerRelayer)(nil)
+	_ ibc.RelayerFeeGranter      = (*DockerRelayer)(nil)
+	_ ibc.RPCTransportSelector   = (*DockerRelayer)(nil)
+)
+
+// debugPort is the container port of the relayer's debug server, when profiling.
+const debugPort = "7597/tcp"
 
 // NewDockerRelayer returns a new DockerRelayer.
 func NewDockerRelayer(ctx context.Context, log *zap.Logger, testName string, cli *client.Client, networkID string, c RelayerCommander, options ...RelayerOption) (*DockerRelayer, error) {
@@ -71,6 +113,10 @@ func NewDockerRelayer(ctx context.Context, log *zap.Logger, testName string, cli
 			r.customImage = &o.DockerImage
 		case RelayerOptionImagePull:
 			r.pullImage = o.Pull
+		case RelayerOptionLogging:
+			r.parseJSONLogs = o.Format == "json"
+		case RelayerOptionProfiling:
+			r.profiling = true
 		}
 	}
 
@@ -82,7 +128,7 @@ func NewDockerRelayer(ctx context.Context, log *zap.Logger, testName string, cli
 	v, err := cli.VolumeCreate(ctx, volumetypes.VolumeCreateBody{
 		// Have to leave Driver unspecified for Docker Desktop compatibility.
 
-		Labels: map[string]string{dockerutil.CleanupLabel: testName},
+		Labels: dockerutil.Labels(testName),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("creating volume: %w", err)
@@ -134,6 +180,13 @@ func (r *DockerRelayer) AddChainConfiguration(ctx context.Context, rep ibc.Relay
 	if err != nil {
 		return fmt.Errorf("failed to generate config content: %w", err)
 	}
+	if granter, ok := r.feeGranters[chainConfig.ChainID]; ok {
+		// SetFeeGranter only records granters for commanders implementing FeeGranterCommander.
+		configContent, err = r.c.(FeeGranterCommander).WithFeeGranter(configContent, granter)
+		if err != nil {
+			return fmt.Errorf("failed to set fee granter in config content: %w", err)
+		}
+	}
 
 	tar, err := r.generateConfigTar(chainConfigFile, configContent)
 	if err != nil {
@@ -221,7 +274,11 @@ func (r *DockerRelayer) CreateChannel(ctx context.Context, rep ibc.RelayerExecRe
 	return res.Err
 }
 
+// CreateClients returns an error if opts require a capability the relayer lacks.
 func (r *DockerRelayer) CreateClients(ctx context.Context, rep ibc.RelayerExecReporter, pathName string, opts ibc.CreateClientOptions) error {
+	if err := r.checkClientOptions(opts); err != nil {
+		return err
+	}
 	cmd := r.c.CreateClients(pathName, opts, r.HomeDir())
 	res := r.Exec(ctx, rep, cmd, nil)
 	return res.Err
@@ -245,18 +302,157 @@ func (r *DockerRelayer) FlushPackets(ctx context.Context, rep ibc.RelayerExecRep
 	return res.Err
 }
 
-func (r *DockerRelayer) GeneratePath(ctx context.Context, rep ibc.RelayerExecReporter, srcChainID, dstChainID, pathName string) error {
-	cmd := r.c.GeneratePath(srcChainID, dstChainID, pathName, r.HomeDir())
+// FlushPacketsDirection implements ibc.DirectionalFlusher.
+// It returns an error if the relayer's commander does not implement DirectionalFlushCommander.
+func (r *DockerRelayer) FlushPacketsDirection(ctx context.Context, rep ibc.RelayerExecReporter, pathName, channelID string, dir ibc.RelayDirection) error {
+	c, ok := r.c.(DirectionalFlushCommander)
+	if !ok {
+		return fmt.Errorf("relayer %s does not support flushing packets in one direction", r.c.Name())
+	}
+	cmd := c.FlushPacketsDirection(pathName, channelID, dir, r.HomeDir())
+	res := r.Exec(ctx, rep, cmd, nil)
+	return res.Err
+}
+
+// FlushAcknowledgementsDirection implements ibc.DirectionalFlusher.
+// It returns an error if the relayer's commander does not implement DirectionalFlushCommander.
+func (r *DockerRelayer) FlushAcknowledgementsDirection(ctx context.Context, rep ibc.RelayerExecReporter, pathName, channelID string, dir ibc.RelayDirection) error {
+	c, ok := r.c.(DirectionalFlushCommander)
+	if !ok {
+		return fmt.Errorf("relayer %s does not support flushing acknowledgements in one direction", r.c.Name())
+	}
+	cmd := c.FlushAcknowledgementsDirection(pathName, channelID, dir, r.HomeDir())
 	res := r.Exec(ctx, rep, cmd, nil)
 	return res.Err
 }
 
-func (r *DockerRelayer) UpdatePath(ctx context.Context, rep ibc.RelayerExecReporter, pathName string, filter ibc.ChannelFilter) error {
-	cmd := r.c.UpdatePath(pathName, r.HomeDir(), filter)
+// CloseChannel implements ibc.ChannelCloser.
+// It returns an error if the relayer's commander does not implement ChannelCloseCommander.
+func (r *DockerRelayer) CloseChannel(ctx context.Context, rep ibc.RelayerExecReporter, pathName, channelID, portID string) error {
+	c, ok := r.c.(ChannelCloseCommander)
+	if !ok {
+		return fmt.Errorf("relayer %s does not support closing channels", r.c.Name())
+	}
+	cmd := c.CloseChannel(pathName, channelID, portID, r.HomeDir())
 	res := r.Exec(ctx, rep, cmd, nil)
 	return res.Err
 }
 
+// SetClientUpdatePolicy implements ibc.ClientUpdateController.
+// It returns an error if the relayer's commander does not implement ClientUpdateCommander.
+//
+// Relaying packets again after ClientsOnly restores the channel filter last set on the path through UpdatePath,
+// or removes the path's channel filter if none was set.
+// If the relayer is started with multiple paths in one process,
+// the shortest update interval of those paths applies to all of them.
+func (r *DockerRelayer) SetClientUpdatePolicy(ctx context.Context, rep ibc.RelayerExecReporter, pathName string, policy ibc.ClientUpdatePolicy) error {
+	c, ok := r.c.(ClientUpdateCommander)
+	if !ok {
+		return fmt.Errorf("relayer %s does not support client update policies", r.c.Name())
+	}
+
+	cmd := r.clientsOnlyCmd(c, pathName, policy.ClientsOnly)
+	if res := r.Exec(ctx, rep, cmd, nil); res.Err != nil {
+		return res.Err
+	}
+
+	r.pathsMu.Lock()
+	defer r.pathsMu.Unlock()
+	if r.clientUpdateIntervals == nil {
+		r.clientUpdateIntervals = make(map[string]time.Duration)
+	}
+	r.clientUpdateIntervals[pathName] = policy.UpdateInterval
+	return nil
+}
+
+// clientsOnlyCmd returns the command to relay only the client updates of pathName,
+// or to relay its packets again with the channel filter it had before.
+func (r *DockerRelayer) clientsOnlyCmd(c ClientUpdateCommander, pathName string, clientsOnly bool) []string {
+	if !clientsOnly {
+		r.pathsMu.Lock()
+		filter, ok := r.channelFilters[pathName]
+		r.pathsMu.Unlock()
+		if ok {
+			return r.c.UpdatePath(pathName, r.HomeDir(), ibc.PathUpdateOptions{ChannelFilter: &filter})
+		}
+	}
+	return c.ClientsOnly(pathName, clientsOnly, r.HomeDir())
+}
+
+// clientUpdateInterval returns the shortest client update interval set for pathNames,
+// or zero if none was set.
+func (r *DockerRelayer) clientUpdateInterval(pathNames []string) time.Duration {
+	r.pathsMu.Lock()
+	defer r.pathsMu.Unlock()
+	var interval time.Duration
+	for _, p := range pathNames {
+		if i := r.clientUpdateIntervals[p]; i > 0 && (interval == 0 || i < interval) {
+			interval = i
+		}
+	}
+	return interval
+}
+
+func (r *DockerRelayer) GeneratePath(ctx context.Context, rep ibc.RelayerExecReporter, srcChainID, dstChainID, pathName string) error {
+	cmd := r.c.GeneratePath(srcChainID, dstChainID, pathName, r.HomeDir())
+	res := r.Exec(ctx, rep, cmd, nil)
+	if res.Err != nil {
+		return res.Err
+	}
+	r.AddPath(pathName)
+	return nil
+}
+
+// AddPath registers pathName as served by the relayer, if it is not registered yet.
+// Implements ibc.PathRegistry.
+func (r *DockerRelayer) AddPath(pathName string) {
+	r.pathsMu.Lock()
+	defer r.pathsMu.Unlock()
+
+	for _, p := range r.paths {
+		if p == pathName {
+			return
+		}
+	}
+	r.paths = append(r.paths, pathName)
+}
+
+// ListPaths returns the registered paths, in the order they were added.
+// Implements ibc.PathRegistry.
+func (r *DockerRelayer) ListPaths() []string {
+	r.pathsMu.Lock()
+	defer r.pathsMu.Unlock()
+
+	return append([]string(nil), r.paths...)
+}
+
+// StartAllPaths starts a single relayer container relaying all registered paths.
+// Implements ibc.PathRegistry.
+func (r *DockerRelayer) StartAllPaths(ctx context.Context, rep ibc.RelayerExecReporter) error {
+	paths := r.ListPaths()
+	if len(paths) == 0 {
+		return fmt.Errorf("relayer %s has no registered paths to start", r.c.Name())
+	}
+	return r.StartRelayer(ctx, rep, paths...)
+}
+
+func (r *DockerRelayer) UpdatePath(ctx context.Context, rep ibc.RelayerExecReporter, pathName string, opts ibc.PathUpdateOptions) error {
+	cmd := r.c.UpdatePath(pathName, r.HomeDir(), opts)
+	if res := r.Exec(ctx, rep, cmd, nil); res.Err != nil {
+		return res.Err
+	}
+
+	if opts.ChannelFilter != nil {
+		r.pathsMu.Lock()
+		defer r.pathsMu.Unlock()
+		if r.channelFilters == nil {
+			r.channelFilters = make(map[string]ibc.ChannelFilter)
+		}
+		r.channelFilters[pathName] = *opts.ChannelFilter
+	}
+	return nil
+}
+
 func (r *DockerRelayer) GetChannels(ctx context.Context, rep ibc.RelayerExecReporter, chainID string) ([]ibc.ChannelOutput, error) {
 	cmd := r.c.GetChannels(chainID, r.HomeDir())
 
@@ -282,17 +478,64 @@ func (r *DockerRelayer) GetConnections(ctx context.Context, rep ibc.RelayerExecR
 	return r.c.ParseGetConnectionsOutput(string(res.Stdout), string(res.Stderr))
 }
 
+// LinkPath returns an error if clientOpts require a capability the relayer lacks.
 func (r *DockerRelayer) LinkPath(ctx context.Context, rep ibc.RelayerExecReporter, pathName string, channelOpts ibc.CreateChannelOptions, clientOpts ibc.CreateClientOptions) error {
+	if err := r.checkClientOptions(clientOpts); err != nil {
+		return err
+	}
 	cmd := r.c.LinkPath(pathName, r.HomeDir(), channelOpts, clientOpts)
 	res := r.Exec(ctx, rep, cmd, nil)
 	return res.Err
 }
 
+// Capabilities returns the capabilities reported by the relayer's commander,
+// or FullCapabilities if the commander does not implement CapabilityCommander.
+// DirectionalFlush is only reported for commanders implementing DirectionalFlushCommander.
+func (r *DockerRelayer) Capabilities() map[Capability]bool {
+	caps := FullCapabilities()
+	if c, ok := r.c.(CapabilityCommander); ok {
+		caps = c.Capabilities()
+	}
+	if _, ok := r.c.(DirectionalFlushCommander); !ok {
+		caps[DirectionalFlush] = false
+	}
+	return caps
+}
+
+// SetFeeGranter implements ibc.RelayerFeeGranter.
+// It returns an error if the relayer's commander lacks the FeeGranter capability
+// or does not implement FeeGranterCommander.
+func (r *DockerRelayer) SetFeeGranter(chainID, granter string) error {
+	if _, ok := r.c.(FeeGranterCommander); !ok || !r.Capabilities()[FeeGranter] {
+		return fmt.Errorf("relayer %s does not support paying fees from a fee allowance", r.c.Name())
+	}
+	if r.feeGranters == nil {
+		r.feeGranters = make(map[string]string)
+	}
+	r.feeGranters[chainID] = granter
+	return nil
+}
+
+// checkClientOptions returns an error if creating clients with opts requires a capability the relayer lacks.
+func (r *DockerRelayer) checkClientOptions(opts ibc.CreateClientOptions) error {
+	caps := r.Capabilities()
+	if opts.TrustLevel != "" && !caps[ClientTrustLevel] {
+		return fmt.Errorf("relayer %s does not support creating clients with a custom trust level", r.c.Name())
+	}
+	if opts.Substrate != nil && !caps[SubstrateClientState] {
+		return fmt.Errorf("relayer %s does not support creating clients with an explicit substrate client state", r.c.Name())
+	}
+	return nil
+}
+
 func (r *DockerRelayer) Exec(ctx context.Context, rep ibc.RelayerExecReporter, cmd []string, env []string) ibc.RelayerExecResult {
-	job := dockerutil.NewImage(r.log, r.client, r.networkID, r.testName, r.containerImage().Repository, r.containerImage().Version)
+	transcript.RecordRelayerExec(ctx, r.c.Name(), r.Name(), cmd, env)
+
+	job := dockerutil.NewImage(r.log, r.client, r.networkID, r.testName, r.containerImage().Repository, r.containerImage().Tag())
 	opts := dockerutil.ContainerOptions{
-		Env:   env,
-		Binds: r.Bind(),
+		Env:      env,
+		Binds:    r.Bind(),
+		Platform: r.containerImage().Platform,
 	}
 
 	startedAt := time.Now()
@@ -344,12 +587,19 @@ func (r *DockerRelayer) UpdateClients(ctx context.Context, rep ibc.RelayerExecRe
 }
 
 func (r *DockerRelayer) StartRelayer(ctx context.Context, rep ibc.RelayerExecReporter, pathNames ...string) error {
+	transcript.RecordRelayerStart(ctx, r.c.Name(), r.Name(), pathNames)
 	return r.createNodeContainer(ctx, pathNames...)
 }
 
 func (r *DockerRelayer) StopRelayer(ctx context.Context, rep ibc.RelayerExecReporter) error {
-	if err := r.stopContainer(ctx); err != nil {
-		return err
+	transcript.RecordRelayerStop(ctx, r.c.Name(), r.Name())
+
+	// A relayer that did not stop cleanly is still reported and removed,
+	// so that it can be started again, before the unclean stop is returned.
+	stopErr := r.stopContainer(ctx)
+	var uncleanErr *dockerutil.UncleanStopError
+	if stopErr != nil && !errors.As(stopErr, &uncleanErr) {
+		return stopErr
 	}
 
 	stdoutBuf := new(bytes.Buffer)
@@ -407,10 +657,35 @@ func (r *DockerRelayer) StopRelayer(ctx context.Context, rep ibc.RelayerExecRepo
 		zap.String("container", c.Name),
 	)
 
-	return r.client.ContainerRemove(ctx, r.containerID, types.ContainerRemoveOptions{
+	if err := r.client.ContainerRemove(ctx, r.containerID, types.ContainerRemoveOptions{
 		RemoveVolumes: true,
 		// TODO: should this set Force=true?
-	})
+	}); err != nil {
+		return err
+	}
+	dockerutil.ReleaseHostPorts(strings.TrimPrefix(c.Name, "/"))
+	return stopErr
+}
+
+// ResolvedImage returns the relayer's image, including custom images, and its digest.
+// Implements ibc.RelayerImageResolver.
+func (r *DockerRelayer) ResolvedImage(ctx context.Context) (ibc.ResolvedImage, error) {
+	image := r.containerImage()
+	digest, err := dockerutil.ImageDigest(ctx, r.client, image.Ref())
+	if err != nil {
+		return ibc.ResolvedImage{}, err
+	}
+	return ibc.ResolvedImage{DockerImage: image, Digest: digest}, nil
+}
+
+// RPCTransport returns the transport the relayer uses for chains of chainType,
+// or an empty Transport if the relayer's commander does not implement RPCTransportCommander.
+// Implements ibc.RPCTransportSelector.
+func (r *DockerRelayer) RPCTransport(chainType string) ibc.Transport {
+	if c, ok := r.c.(RPCTransportCommander); ok {
+		return c.RPCTransport(chainType)
+	}
+	return ""
 }
 
 func (r *DockerRelayer) containerImage() ibc.DockerImage {
@@ -429,14 +704,7 @@ func (r *DockerRelayer) pullContainerImageIfNecessary(containerImage ibc.DockerI
 		return nil
 	}
 
-	rc, err := r.client.ImagePull(context.TODO(), containerImage.Ref(), types.ImagePullOptions{})
-	if err != nil {
-		return err
-	}
-
-	_, _ = io.Copy(io.Discard, rc)
-	_ = rc.Close()
-	return nil
+	return dockerutil.EnsureImage(context.TODO(), r.log, r.client, containerImage.Ref(), containerImage.Platform)
 }
 
 func (r *DockerRelayer) createNodeContainer(ctx context.Context, pathNames ...string) error {
@@ -444,11 +712,34 @@ func (r *DockerRelayer) createNodeContainer(ctx context.Context, pathNames ...st
 	joinedPaths := strings.Join(pathNames, ".")
 	containerName := fmt.Sprintf("%s-%s", r.c.Name(), joinedPaths)
 	cmd := r.c.StartRelayer(r.HomeDir(), pathNames...)
+	if interval := r.clientUpdateInterval(pathNames); interval > 0 {
+		// SetClientUpdatePolicy only sets an interval if the commander implements ClientUpdateCommander.
+		cmd = append(cmd, r.c.(ClientUpdateCommander).ClientUpdateIntervalFlags(interval)...)
+	}
+	var exposedPorts nat.PortSet
+	pc, profiling := r.c.(ProfilingCommander)
+	profiling = profiling && r.profiling
+	if profiling {
+		cmd = append(cmd, pc.DebugServerFlags("0.0.0.0:"+strings.Split(debugPort, "/")[0])...)
+		exposedPorts = nat.PortSet{nat.Port(debugPort): {}}
+	}
 	r.log.Info(
 		"Running command",
 		zap.String("command", strings.Join(cmd, " ")),
 		zap.String("container", containerName),
 	)
+	platform, err := dockerutil.ParsePlatform(containerImage.Platform)
+	if err != nil {
+		return err
+	}
+	var publishAll bool
+	var portBindings nat.PortMap
+	if profiling {
+		publishAll, portBindings, err = dockerutil.PortPublishing{}.ReserveHostConfig(containerName, exposedPorts)
+		if err != nil {
+			return err
+		}
+	}
 	cc, err := r.client.ContainerCreate(
 		ctx,
 		&container.Config{
@@ -460,31 +751,103 @@ func (r *DockerRelayer) createNodeContainer(ctx context.Context, pathNames ...st
 			Hostname: r.HostName(joinedPaths),
 			User:     r.c.DockerUser(),
 
-			Labels: map[string]string{dockerutil.CleanupLabel: r.testName},
+			Labels: dockerutil.Labels(r.testName),
+
+			ExposedPorts: exposedPorts,
 		},
 		&container.HostConfig{
-			Binds:      r.Bind(),
-			AutoRemove: false,
+			Binds:           r.Bind(),
+			PublishAllPorts: publishAll,
+			PortBindings:    portBindings,
+			AutoRemove:      false,
 		},
 		&network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
 				r.networkID: {},
 			},
 		},
-		nil,
+		platform,
 		containerName,
 	)
 	if err != nil {
+		dockerutil.ReleaseHostPorts(containerName)
 		return err
 	}
 
 	r.containerID = cc.ID
-	return dockerutil.StartContainer(ctx, r.client, r.containerID)
+	if err := dockerutil.StartContainer(ctx, r.client, r.containerID); err != nil {
+		return err
+	}
+
+	if profiling {
+		hostPorts, err := dockerutil.GetHostPorts(ctx, r.client, r.containerID, debugPort)
+		if err != nil {
+			return err
+		}
+		r.debugMu.Lock()
+		r.debugEndpoint = &ibc.ProfileEndpoint{
+			Container:   containerName,
+			Kind:        ibc.ProfileKindPprof,
+			HostAddress: hostPorts[0],
+		}
+		r.debugMu.Unlock()
+	}
+
+	if r.parseJSONLogs {
+		r.followLogs(containerName)
+	}
+	return nil
+}
+
+// followLogs logs the JSON logs of the started relayer container through r.log, until the container stops.
+func (r *DockerRelayer) followLogs(containerName string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rc, err := r.client.ContainerLogs(ctx, r.containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		cancel()
+		r.log.Info("Failed to follow relayer logs", zap.String("container", containerName), zap.Error(err))
+		return
+	}
+
+	// Logs are multiplexed into one stream; see docs for ContainerLogs.
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, rc)
+		_ = rc.Close()
+		_ = pw.CloseWithError(err)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = LogJSONLines(r.log.With(zap.String("container", containerName)), pr)
+		_ = pr.Close()
+	}()
+
+	r.stopFollowingLogs = func() {
+		// The stream ends once the container stops; give it a moment to flush the final lines.
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+		}
+		cancel()
+	}
 }
 
 func (r *DockerRelayer) stopContainer(ctx context.Context) error {
-	timeout := 30 * time.Second
-	return r.client.ContainerStop(ctx, r.containerID, &timeout)
+	err := dockerutil.StopContainer(ctx, r.client, r.containerID)
+	r.debugMu.Lock()
+	r.debugEndpoint = nil
+	r.debugMu.Unlock()
+	if r.stopFollowingLogs != nil {
+		r.stopFollowingLogs()
+		r.stopFollowingLogs = nil
+	}
+	return err
 }
 
 func (r *DockerRelayer) Name() string {
@@ -503,6 +866,29 @@ func (r *DockerRelayer) HomeDir() string {
 	return "/var/relayer-" + r.c.Name()
 }
 
+// ProfileEndpoints returns the pprof endpoint of the running relayer,
+// if it was built with the Profiling option and its commander implements ProfilingCommander.
+// Implements ibc.Profiler.
+func (r *DockerRelayer) ProfileEndpoints(ctx context.Context) ([]ibc.ProfileEndpoint, error) {
+	r.debugMu.Lock()
+	defer r.debugMu.Unlock()
+	if r.debugEndpoint == nil {
+		return nil, nil
+	}
+	return []ibc.ProfileEndpoint{*r.debugEndpoint}, nil
+}
+
+// ReadFileFromHomeDir returns the content of the file at relPath in the relayer's home directory,
+// e.g. its configuration or keys, read from the relayer's volume.
+func (r *DockerRelayer) ReadFileFromHomeDir(ctx context.Context, relPath string) ([]byte, error) {
+	fr := dockerutil.NewFileRetriever(r.log, r.client, r.testName)
+	content, err := fr.SingleFileContent(ctx, r.volumeName, relPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s from relayer home: %w", relPath, err)
+	}
+	return content, nil
+}
+
 func (r *DockerRelayer) HostName(pathName string) string {
 	return dockerutil.CondenseHostName(fmt.Sprintf("%s-%s", r.c.Name(), pathName))
 }
@@ -539,6 +925,10 @@ type oneOffOptions struct {
 
 func (r *DockerRelayer) runOneOff(ctx context.Context, opts oneOffOptions) error {
 	containerName := r.Name() + "-" + opts.ContainerNameDetail + "-" + dockerutil.RandLowerCaseLetterString(5)
+	platform, err := dockerutil.ParsePlatform(r.containerImage().Platform)
+	if err != nil {
+		return err
+	}
 	cc, err := r.client.ContainerCreate(
 		ctx,
 		&container.Config{
@@ -552,14 +942,14 @@ func (r *DockerRelayer) runOneOff(ctx context.Context, opts oneOffOptions) error
 			Hostname: r.HostName(containerName),
 			User:     opts.User,
 
-			Labels: map[string]string{dockerutil.CleanupLabel: r.testName},
+			Labels: dockerutil.Labels(r.testName),
 		},
 		&container.HostConfig{
 			Binds:      r.Bind(),
 			AutoRemove: false,
 		},
 		nil, // No network config in the one-off jobs for now. Could move to an option if we need it.
-		nil,
+		platform,
 		containerName,
 	)
 	if err != nil {
@@ -651,7 +1041,7 @@ type RelayerCommander interface {
 	FlushAcknowledgements(pathName, channelID, homeDir string) []string
 	FlushPackets(pathName, channelID, homeDir string) []string
 	GeneratePath(srcChainID, dstChainID, pathName, homeDir string) []string
-	UpdatePath(pathName, homeDir string, filter ibc.ChannelFilter) []string
+	UpdatePath(pathName, homeDir string, opts ibc.PathUpdateOptions) []string
 	GetChannels(chainID, homeDir string) []string
 	GetConnections(chainID, homeDir string) []string
 	LinkPath(pathName, homeDir string, channelOpts ibc.CreateChannelOptions, clientOpts ibc.CreateClientOptions) []string
@@ -659,3 +1049,69 @@ type RelayerCommander interface {
 	StartRelayer(homeDir string, pathNames ...string) []string
 	UpdateClients(pathName, homeDir string) []string
 }
+
+// CapabilityCommander is an optional extension of RelayerCommander
+// for relayers that do not support every Capability.
+// A DockerRelayer returns an error instead of running a command that needs a capability its commander lacks.
+type CapabilityCommander interface {
+	// Capabilities returns the features supported by the relayer.
+	Capabilities() map[Capability]bool
+}
+
+// FeeGranterCommander is an optional extension of RelayerCommander
+// for relayers that can pay the fees of their transactions from a fee allowance.
+// A DockerRelayer whose commander implements it, and has the FeeGranter capability, supports ibc.RelayerFeeGranter.
+type FeeGranterCommander interface {
+	// WithFeeGranter returns the chain config content produced by ConfigContent,
+	// changed so that the relayer's transactions pay fees from the allowance granted by granter.
+	WithFeeGranter(configContent []byte, granter string) ([]byte, error)
+}
+
+// DirectionalFlushCommander is an optional extension of RelayerCommander
+// for relayers that can flush one direction of a channel.
+// A DockerRelayer whose commander implements it supports ibc.DirectionalFlusher.
+type DirectionalFlushCommander interface {
+	FlushAcknowledgementsDirection(pathName, channelID string, dir ibc.RelayDirection, homeDir string) []string
+	FlushPacketsDirection(pathName, channelID string, dir ibc.RelayDirection, homeDir string) []string
+}
+
+// ChannelCloseCommander is an optional extension of RelayerCommander
+// for relayers that can close a channel.
+// A DockerRelayer whose commander implements it supports ibc.ChannelCloser.
+type ChannelCloseCommander interface {
+	// CloseChannel is the command to close channelID on portID of the path's source chain,
+	// completing the close handshake on the destination chain.
+	CloseChannel(pathName, channelID, portID, homeDir string) []string
+}
+
+// ProfilingCommander is an optional extension of RelayerCommander
+// for relayers that can serve Go pprof handlers while running.
+// A DockerRelayer whose commander implements it serves profiles when built with the Profiling option.
+type ProfilingCommander interface {
+	// DebugServerFlags are the flags to append to StartRelayer
+	// to serve pprof handlers under /debug/pprof/ on the listen address addr.
+	DebugServerFlags(addr string) []string
+}
+
+// RPCTransportCommander is an optional extension of RelayerCommander
+// for relayers that connect to some chain types over a specific RPC transport.
+// A DockerRelayer whose commander implements it supports ibc.RPCTransportSelector.
+type RPCTransportCommander interface {
+	// RPCTransport returns the transport the relayer uses for chains of chainType,
+	// or an empty Transport to use the chain's GetRPCAddress and GetGRPCAddress.
+	RPCTransport(chainType string) ibc.Transport
+}
+
+// ClientUpdateCommander is an optional extension of RelayerCommander
+// for relayers whose client updates can be controlled per path.
+// A DockerRelayer whose commander implements it supports ibc.ClientUpdateController.
+type ClientUpdateCommander interface {
+	// ClientsOnly is the command to stop relaying packets on pathName while still updating its clients,
+	// or to relay packets again without a channel filter if clientsOnly is false.
+	// DockerRelayer restores a channel filter set through UpdatePath with UpdatePath instead.
+	ClientsOnly(pathName string, clientsOnly bool, homeDir string) []string
+
+	// ClientUpdateIntervalFlags are the flags to append to StartRelayer
+	// so that clients are updated at least every interval.
+	ClientUpdateIntervalFlags(interval time.Duration) []string
+}
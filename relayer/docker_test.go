@@ -0,0 +1,105 @@
+package relayer
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerRelayer_Paths(t *testing.T) {
+	t.Parallel()
+
+	var r DockerRelayer
+	require.Empty(t, r.ListPaths())
+
+	r.AddPath("gaia-osmosis")
+	r.AddPath("gaia-juno")
+	r.AddPath("gaia-osmosis")
+	require.Equal(t, []string{"gaia-osmosis", "gaia-juno"}, r.ListPaths())
+
+	// The returned slice is a copy.
+	paths := r.ListPaths()
+	paths[0] = "changed"
+	require.Equal(t, []string{"gaia-osmosis", "gaia-juno"}, r.ListPaths())
+}
+
+// capabilityCommander reports caps. Only its Capabilities method may be called.
+type capabilityCommander struct {
+	RelayerCommander
+	caps map[Capability]bool
+}
+
+func (c capabilityCommander) Capabilities() map[Capability]bool {
+	return c.caps
+}
+
+// directionalFlushCommander is a capabilityCommander that implements DirectionalFlushCommander.
+type directionalFlushCommander struct {
+	capabilityCommander
+}
+
+func (directionalFlushCommander) FlushAcknowledgementsDirection(string, string, ibc.RelayDirection, string) []string {
+	return nil
+}
+
+func (directionalFlushCommander) FlushPacketsDirection(string, string, ibc.RelayDirection, string) []string {
+	return nil
+}
+
+func TestDockerRelayer_Capabilities(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, FullCapabilities()[DirectionalFlush])
+
+	caps := FullCapabilities()
+	caps[DirectionalFlush] = true
+
+	r := DockerRelayer{c: capabilityCommander{caps: caps}}
+	require.False(t, r.Capabilities()[DirectionalFlush])
+	require.True(t, r.Capabilities()[FlushPackets])
+
+	caps = FullCapabilities()
+	caps[DirectionalFlush] = true
+	r = DockerRelayer{c: directionalFlushCommander{capabilityCommander{caps: caps}}}
+	require.True(t, r.Capabilities()[DirectionalFlush])
+}
+
+// clientsOnlyCommander is a capabilityCommander that implements ClientUpdateCommander,
+// with commands naming their arguments.
+type clientsOnlyCommander struct {
+	capabilityCommander
+}
+
+func (clientsOnlyCommander) Name() string { return "fake" }
+
+func (clientsOnlyCommander) ClientsOnly(pathName string, clientsOnly bool, homeDir string) []string {
+	return []string{"clients-only", pathName, strconv.FormatBool(clientsOnly)}
+}
+
+func (clientsOnlyCommander) ClientUpdateIntervalFlags(interval time.Duration) []string {
+	return nil
+}
+
+func (clientsOnlyCommander) UpdatePath(pathName, homeDir string, opts ibc.PathUpdateOptions) []string {
+	return append([]string{"update", pathName, opts.ChannelFilter.Rule}, opts.ChannelFilter.ChannelList...)
+}
+
+func TestDockerRelayer_ClientsOnlyCmd(t *testing.T) {
+	t.Parallel()
+
+	c := clientsOnlyCommander{}
+	r := DockerRelayer{c: c}
+	require.Equal(t, []string{"clients-only", "p", "true"}, r.clientsOnlyCmd(c, "p", true))
+	require.Equal(t, []string{"clients-only", "p", "false"}, r.clientsOnlyCmd(c, "p", false))
+
+	// Relaying packets again restores the path's channel filter.
+	r.channelFilters = map[string]ibc.ChannelFilter{
+		"p": {Rule: "allowlist", ChannelList: []string{"channel-0", "channel-1"}},
+	}
+	require.Equal(t, []string{"clients-only", "p", "true"}, r.clientsOnlyCmd(c, "p", true))
+	require.Equal(t, []string{"update", "p", "allowlist", "channel-0", "channel-1"}, r.clientsOnlyCmd(c, "p", false))
+	require.Equal(t, []string{"clients-only", "q", "false"}, r.clientsOnlyCmd(c, "q", false))
+}
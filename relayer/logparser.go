@@ -0,0 +1,68 @@
+package relayer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogJSONLines reads newline-delimited JSON log entries from r, as written by a zap logger with a JSON encoder,
+// and logs each entry to log with the entry's level, message, and remaining keys as fields.
+// Lines that are not JSON objects are logged at debug level as is.
+// LogJSONLines returns when r is exhausted.
+func LogJSONLines(log *zap.Logger, r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	// Relayer log lines may include large transaction dumps.
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		logJSONLine(log, sc.Bytes())
+	}
+	return sc.Err()
+}
+
+func logJSONLine(log *zap.Logger, line []byte) {
+	if len(line) == 0 {
+		return
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(line, &entry); err != nil {
+		log.Debug(string(line))
+		return
+	}
+
+	level := zapcore.DebugLevel
+	if l, ok := entry["level"].(string); ok {
+		if err := level.UnmarshalText([]byte(l)); err != nil {
+			level = zapcore.InfoLevel
+		}
+	}
+	// Never panic or exit the test process because the relayer logged at those levels.
+	if level > zapcore.ErrorLevel {
+		level = zapcore.ErrorLevel
+	}
+
+	msg, _ := entry["msg"].(string)
+	delete(entry, "level")
+	delete(entry, "msg")
+	// The entry's timestamp is superseded by the time it is logged again.
+	delete(entry, "ts")
+
+	keys := make([]string, 0, len(entry))
+	for k := range entry {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]zap.Field, len(keys))
+	for i, k := range keys {
+		fields[i] = zap.Any(k, entry[k])
+	}
+
+	if ce := log.Check(level, msg); ce != nil {
+		ce.Write(fields...)
+	}
+}
@@ -0,0 +1,42 @@
+package relayer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogJSONLines(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	in := strings.Join([]string{
+		`{"level":"info","ts":"2022-10-01T00:00:00Z","msg":"Successfully created client","chain_id":"gaia-1","client_id":"07-tendermint-0"}`,
+		`{"level":"error","ts":"2022-10-01T00:00:01Z","msg":"Failed to relay packet","error":"out of gas","sequence":3}`,
+		`{"level":"fatal","msg":"Exiting"}`,
+		`not json`,
+		``,
+	}, "\n")
+	require.NoError(t, LogJSONLines(zap.New(core), strings.NewReader(in)))
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 4)
+
+	require.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	require.Equal(t, "Successfully created client", entries[0].Message)
+	require.Equal(t, map[string]any{"chain_id": "gaia-1", "client_id": "07-tendermint-0"}, entries[0].ContextMap())
+
+	require.Equal(t, zapcore.ErrorLevel, entries[1].Level)
+	require.Equal(t, map[string]any{"error": "out of gas", "sequence": float64(3)}, entries[1].ContextMap())
+
+	// Levels that would panic or exit are logged as errors.
+	require.Equal(t, zapcore.ErrorLevel, entries[2].Level)
+
+	require.Equal(t, zapcore.DebugLevel, entries[3].Level)
+	require.Equal(t, "not json", entries[3].Message)
+}
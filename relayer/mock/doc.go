@@ -0,0 +1,4 @@
+// Package mock provides an in-memory implementation of ibc.Relayer that does not require docker.
+// It records the calls made to it and can be configured to fail specific operations,
+// for unit testing code that drives a relayer, such as Interchain.Build and the conformance tests.
+package mock
@@ -0,0 +1,445 @@
+package mock
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+var (
+	_ ibc.Relayer           = (*Relayer)(nil)
+	_ ibc.PathRegistry      = (*Relayer)(nil)
+	_ ibc.RelayerFeeGranter = (*Relayer)(nil)
+)
+
+// Call is a call made to a Relayer.
+type Call struct {
+	// Method is the name of the called method, e.g. "LinkPath".
+	Method string
+
+	// Args are the method's arguments, excluding the context and reporter.
+	Args []any
+}
+
+// Relayer is an in-memory ibc.Relayer.
+//
+// Relayer records every call made to it, see Calls.
+// Paths, clients, connections, and channels are tracked in memory,
+// so that e.g. GetChannels reports the channels created through LinkPath.
+// Any method can be made to fail with FailOn.
+type Relayer struct {
+	mu sync.Mutex
+
+	calls    []Call
+	failures map[string]error
+
+	wallets     map[string]ibc.Wallet
+	paths       map[string]path
+	pathOrder   []string
+	channels    map[string][]ibc.ChannelOutput
+	connections map[string]ibc.ConnectionOutputs
+	running     bool
+}
+
+type path struct {
+	srcChainID, dstChainID string
+	srcConnID, dstConnID   string
+}
+
+// NewRelayer returns a new mock relayer.
+func NewRelayer() *Relayer {
+	return &Relayer{
+		failures:    make(map[string]error),
+		wallets:     make(map[string]ibc.Wallet),
+		paths:       make(map[string]path),
+		channels:    make(map[string][]ibc.ChannelOutput),
+		connections: make(map[string]ibc.ConnectionOutputs),
+	}
+}
+
+// FailOn makes every subsequent call to method return err, without other effects.
+// A nil err makes method succeed again.
+func (r *Relayer) FailOn(method string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		delete(r.failures, method)
+		return
+	}
+	r.failures[method] = err
+}
+
+// Calls returns the calls made to the relayer, in order.
+func (r *Relayer) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the calls made to method, in order.
+func (r *Relayer) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Running reports whether the relayer has been started and not stopped since.
+func (r *Relayer) Running() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.running
+}
+
+// record records a call to method and returns its configured failure, if any.
+// r.mu must be held.
+func (r *Relayer) record(method string, args ...any) error {
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+	return r.failures[method]
+}
+
+// SetFeeGranter implements ibc.RelayerFeeGranter. It only records the call.
+func (r *Relayer) SetFeeGranter(chainID, granter string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.record("SetFeeGranter", chainID, granter)
+}
+
+func (r *Relayer) RestoreKey(ctx context.Context, rep ibc.RelayerExecReporter, chainID, keyName, mnemonic string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("RestoreKey", chainID, keyName, mnemonic); err != nil {
+		return err
+	}
+	r.wallets[chainID] = ibc.Wallet{
+		Mnemonic: mnemonic,
+		Address:  fakeAddress(mnemonic),
+		KeyName:  keyName,
+	}
+	return nil
+}
+
+func (r *Relayer) AddKey(ctx context.Context, rep ibc.RelayerExecReporter, chainID, keyName string) (ibc.Wallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("AddKey", chainID, keyName); err != nil {
+		return ibc.Wallet{}, err
+	}
+	w := ibc.Wallet{
+		Address: fakeAddress(chainID + "/" + keyName),
+		KeyName: keyName,
+	}
+	r.wallets[chainID] = w
+	return w, nil
+}
+
+func (r *Relayer) GetWallet(chainID string) (ibc.Wallet, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w, ok := r.wallets[chainID]
+	return w, ok
+}
+
+func (r *Relayer) AddChainConfiguration(ctx context.Context, rep ibc.RelayerExecReporter, chainConfig ibc.ChainConfig, keyName, rpcAddr, grpcAddr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.record("AddChainConfiguration", chainConfig.ChainID, keyName, rpcAddr, grpcAddr)
+}
+
+func (r *Relayer) GeneratePath(ctx context.Context, rep ibc.RelayerExecReporter, srcChainID, dstChainID, pathName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("GeneratePath", srcChainID, dstChainID, pathName); err != nil {
+		return err
+	}
+	if _, ok := r.paths[pathName]; ok {
+		return fmt.Errorf("path %q already exists", pathName)
+	}
+	r.paths[pathName] = path{srcChainID: srcChainID, dstChainID: dstChainID}
+	r.pathOrder = append(r.pathOrder, pathName)
+	return nil
+}
+
+func (r *Relayer) LinkPath(ctx context.Context, rep ibc.RelayerExecReporter, pathName string, channelOpts ibc.CreateChannelOptions, clientOpts ibc.CreateClientOptions) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("LinkPath", pathName, channelOpts, clientOpts); err != nil {
+		return err
+	}
+	if err := r.createConnections(pathName); err != nil {
+		return err
+	}
+	return r.createChannel(pathName, channelOpts)
+}
+
+func (r *Relayer) UpdatePath(ctx context.Context, rep ibc.RelayerExecReporter, pathName string, opts ibc.PathUpdateOptions) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("UpdatePath", pathName, opts); err != nil {
+		return err
+	}
+	p, ok := r.paths[pathName]
+	if !ok {
+		return fmt.Errorf("path %q not found", pathName)
+	}
+	if opts.SrcConnectionID != "" {
+		p.srcConnID = opts.SrcConnectionID
+	}
+	if opts.DstConnectionID != "" {
+		p.dstConnID = opts.DstConnectionID
+	}
+	r.paths[pathName] = p
+	return nil
+}
+
+func (r *Relayer) UpdateClients(ctx context.Context, rep ibc.RelayerExecReporter, pathName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.record("UpdateClients", pathName)
+}
+
+func (r *Relayer) GetChannels(ctx context.Context, rep ibc.RelayerExecReporter, chainID string) ([]ibc.ChannelOutput, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("GetChannels", chainID); err != nil {
+		return nil, err
+	}
+	return append([]ibc.ChannelOutput(nil), r.channels[chainID]...), nil
+}
+
+func (r *Relayer) GetConnections(ctx context.Context, rep ibc.RelayerExecReporter, chainID string) (ibc.ConnectionOutputs, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("GetConnections", chainID); err != nil {
+		return nil, err
+	}
+	return append(ibc.ConnectionOutputs(nil), r.connections[chainID]...), nil
+}
+
+func (r *Relayer) StartRelayer(ctx context.Context, rep ibc.RelayerExecReporter, pathNames ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	args := make([]any, len(pathNames))
+	for i, p := range pathNames {
+		args[i] = p
+	}
+	if err := r.record("StartRelayer", args...); err != nil {
+		return err
+	}
+	if r.running {
+		return fmt.Errorf("relayer already started")
+	}
+	r.running = true
+	return nil
+}
+
+func (r *Relayer) StopRelayer(ctx context.Context, rep ibc.RelayerExecReporter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("StopRelayer"); err != nil {
+		return err
+	}
+	r.running = false
+	return nil
+}
+
+func (r *Relayer) FlushPackets(ctx context.Context, rep ibc.RelayerExecReporter, pathName, channelID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.record("FlushPackets", pathName, channelID)
+}
+
+func (r *Relayer) FlushAcknowledgements(ctx context.Context, rep ibc.RelayerExecReporter, pathName, channelID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.record("FlushAcknowledgements", pathName, channelID)
+}
+
+func (r *Relayer) CreateClients(ctx context.Context, rep ibc.RelayerExecReporter, pathName string, opts ibc.CreateClientOptions) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.record("CreateClients", pathName, opts)
+}
+
+func (r *Relayer) CreateConnections(ctx context.Context, rep ibc.RelayerExecReporter, pathName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("CreateConnections", pathName); err != nil {
+		return err
+	}
+	return r.createConnections(pathName)
+}
+
+func (r *Relayer) CreateChannel(ctx context.Context, rep ibc.RelayerExecReporter, pathName string, opts ibc.CreateChannelOptions) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("CreateChannel", pathName, opts); err != nil {
+		return err
+	}
+	return r.createChannel(pathName, opts)
+}
+
+// CloseChannel implements ibc.ChannelCloser.
+func (r *Relayer) CloseChannel(ctx context.Context, rep ibc.RelayerExecReporter, pathName, channelID, portID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("CloseChannel", pathName, channelID, portID); err != nil {
+		return err
+	}
+	return r.closeChannel(pathName, channelID, portID)
+}
+
+func (r *Relayer) UseDockerNetwork() bool {
+	return true
+}
+
+func (r *Relayer) Exec(ctx context.Context, rep ibc.RelayerExecReporter, cmd []string, env []string) ibc.RelayerExecResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.record("Exec", cmd, env); err != nil {
+		return ibc.RelayerExecResult{Err: err}
+	}
+	return ibc.RelayerExecResult{}
+}
+
+// AddPath implements ibc.PathRegistry.
+// A path added this way has no chains, so it cannot be linked.
+func (r *Relayer) AddPath(pathName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_ = r.record("AddPath", pathName)
+	if _, ok := r.paths[pathName]; ok {
+		return
+	}
+	r.paths[pathName] = path{}
+	r.pathOrder = append(r.pathOrder, pathName)
+}
+
+// ListPaths implements ibc.PathRegistry.
+func (r *Relayer) ListPaths() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.pathOrder...)
+}
+
+// StartAllPaths implements ibc.PathRegistry.
+func (r *Relayer) StartAllPaths(ctx context.Context, rep ibc.RelayerExecReporter) error {
+	return r.StartRelayer(ctx, rep, r.ListPaths()...)
+}
+
+// createConnections creates a client and connection on each chain of the path,
+// unless the path already has connections. r.mu must be held.
+func (r *Relayer) createConnections(pathName string) error {
+	p, ok := r.paths[pathName]
+	if !ok {
+		return fmt.Errorf("path %q not found", pathName)
+	}
+	if p.srcChainID == "" || p.dstChainID == "" {
+		return fmt.Errorf("path %q has no chains", pathName)
+	}
+	if p.srcConnID != "" && p.dstConnID != "" {
+		return nil
+	}
+
+	srcN, dstN := len(r.connections[p.srcChainID]), len(r.connections[p.dstChainID])
+	p.srcConnID, p.dstConnID = fmt.Sprintf("connection-%d", srcN), fmt.Sprintf("connection-%d", dstN)
+	r.connections[p.srcChainID] = append(r.connections[p.srcChainID], &ibc.ConnectionOutput{
+		ID:       p.srcConnID,
+		ClientID: fmt.Sprintf("07-tendermint-%d", srcN),
+		State:    "STATE_OPEN",
+	})
+	r.connections[p.dstChainID] = append(r.connections[p.dstChainID], &ibc.ConnectionOutput{
+		ID:       p.dstConnID,
+		ClientID: fmt.Sprintf("07-tendermint-%d", dstN),
+		State:    "STATE_OPEN",
+	})
+	r.paths[pathName] = p
+	return nil
+}
+
+// createChannel creates an open channel on the path's connections. r.mu must be held.
+func (r *Relayer) createChannel(pathName string, opts ibc.CreateChannelOptions) error {
+	p, ok := r.paths[pathName]
+	if !ok {
+		return fmt.Errorf("path %q not found", pathName)
+	}
+	if p.srcConnID == "" || p.dstConnID == "" {
+		return fmt.Errorf("path %q has no connections", pathName)
+	}
+
+	srcChanID := fmt.Sprintf("channel-%d", len(r.channels[p.srcChainID]))
+	dstChanID := fmt.Sprintf("channel-%d", len(r.channels[p.dstChainID]))
+	ordering := "ORDER_" + strings.ToUpper(opts.Order.String())
+	r.channels[p.srcChainID] = append(r.channels[p.srcChainID], ibc.ChannelOutput{
+		State:          "STATE_OPEN",
+		Ordering:       ordering,
+		Counterparty:   ibc.ChannelCounterparty{PortID: opts.DestPortName, ChannelID: dstChanID},
+		ConnectionHops: []string{p.srcConnID},
+		Version:        opts.Version,
+		PortID:         opts.SourcePortName,
+		ChannelID:      srcChanID,
+	})
+	r.channels[p.dstChainID] = append(r.channels[p.dstChainID], ibc.ChannelOutput{
+		State:          "STATE_OPEN",
+		Ordering:       ordering,
+		Counterparty:   ibc.ChannelCounterparty{PortID: opts.SourcePortName, ChannelID: srcChanID},
+		ConnectionHops: []string{p.dstConnID},
+		Version:        opts.Version,
+		PortID:         opts.DestPortName,
+		ChannelID:      dstChanID,
+	})
+	return nil
+}
+
+// closeChannel closes an open channel on the path's source chain and its counterparty. r.mu must be held.
+func (r *Relayer) closeChannel(pathName, channelID, portID string) error {
+	p, ok := r.paths[pathName]
+	if !ok {
+		return fmt.Errorf("path %q not found", pathName)
+	}
+
+	src := r.channels[p.srcChainID]
+	i := findChannel(src, channelID, portID)
+	if i < 0 {
+		return fmt.Errorf("channel %s on port %s not found on chain %s", channelID, portID, p.srcChainID)
+	}
+	if src[i].State != "STATE_OPEN" {
+		return fmt.Errorf("channel %s on port %s is not open: %s", channelID, portID, src[i].State)
+	}
+
+	dst := r.channels[p.dstChainID]
+	j := findChannel(dst, src[i].Counterparty.ChannelID, src[i].Counterparty.PortID)
+	if j < 0 {
+		return fmt.Errorf("counterparty of channel %s not found on chain %s", channelID, p.dstChainID)
+	}
+
+	src[i].State = "STATE_CLOSED"
+	dst[j].State = "STATE_CLOSED"
+	return nil
+}
+
+// findChannel returns the index of the channel with channelID and portID in channels, or -1.
+func findChannel(channels []ibc.ChannelOutput, channelID, portID string) int {
+	for i, c := range channels {
+		if c.ChannelID == channelID && c.PortID == portID {
+			return i
+		}
+	}
+	return -1
+}
+
+// fakeAddress returns a deterministic hex address derived from seed.
+func fakeAddress(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:20])
+}
@@ -0,0 +1,91 @@
+package mock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/relayer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayer_LinkPath(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rep := ibc.NopRelayerExecReporter{}
+	r := mock.NewRelayer()
+
+	require.NoError(t, r.GeneratePath(ctx, rep, "a-1", "b-1", "ab"))
+	require.NoError(t, r.LinkPath(ctx, rep, "ab", ibc.DefaultChannelOpts(), ibc.DefaultClientOpts()))
+	require.NoError(t, r.CreateChannel(ctx, rep, "ab", ibc.DefaultChannelOpts()))
+
+	channels, err := r.GetChannels(ctx, rep, "a-1")
+	require.NoError(t, err)
+	require.Len(t, channels, 2)
+	require.Equal(t, "channel-1", channels[1].ChannelID)
+	require.Equal(t, "channel-1", channels[1].Counterparty.ChannelID)
+	require.Equal(t, []string{"connection-0"}, channels[1].ConnectionHops)
+
+	conns, err := r.GetConnections(ctx, rep, "b-1")
+	require.NoError(t, err)
+	require.Len(t, conns, 1)
+
+	require.Len(t, r.CallsTo("GetChannels"), 1)
+	require.Equal(t, mock.Call{Method: "GeneratePath", Args: []any{"a-1", "b-1", "ab"}}, r.Calls()[0])
+}
+
+func TestRelayer_CloseChannel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rep := ibc.NopRelayerExecReporter{}
+	r := mock.NewRelayer()
+
+	require.NoError(t, r.GeneratePath(ctx, rep, "a-1", "b-1", "ab"))
+	require.NoError(t, r.LinkPath(ctx, rep, "ab", ibc.DefaultChannelOpts(), ibc.DefaultClientOpts()))
+	require.NoError(t, r.CloseChannel(ctx, rep, "ab", "channel-0", "transfer"))
+	require.Error(t, r.CloseChannel(ctx, rep, "ab", "channel-0", "transfer"), "closing a closed channel")
+	require.Error(t, r.CloseChannel(ctx, rep, "ab", "channel-9", "transfer"), "closing an unknown channel")
+
+	// A new channel can be opened on the same connection.
+	require.NoError(t, r.CreateChannel(ctx, rep, "ab", ibc.DefaultChannelOpts()))
+
+	for _, chainID := range []string{"a-1", "b-1"} {
+		channels, err := r.GetChannels(ctx, rep, chainID)
+		require.NoError(t, err)
+		require.Len(t, channels, 2)
+		require.Equal(t, "STATE_CLOSED", channels[0].State)
+		require.Equal(t, "STATE_OPEN", channels[1].State)
+		require.Equal(t, channels[0].ConnectionHops, channels[1].ConnectionHops)
+	}
+}
+
+func TestRelayer_FailOn(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rep := ibc.NopRelayerExecReporter{}
+	r := mock.NewRelayer()
+	require.NoError(t, r.GeneratePath(ctx, rep, "a-1", "b-1", "ab"))
+
+	linkErr := errors.New("handshake failed")
+	r.FailOn("LinkPath", linkErr)
+	require.ErrorIs(t, r.LinkPath(ctx, rep, "ab", ibc.DefaultChannelOpts(), ibc.DefaultClientOpts()), linkErr)
+
+	// A failed call has no effect but is still recorded.
+	channels, err := r.GetChannels(ctx, rep, "a-1")
+	require.NoError(t, err)
+	require.Empty(t, channels)
+	require.Len(t, r.CallsTo("LinkPath"), 1)
+
+	r.FailOn("LinkPath", nil)
+	require.NoError(t, r.LinkPath(ctx, rep, "ab", ibc.DefaultChannelOpts(), ibc.DefaultClientOpts()))
+
+	require.NoError(t, r.StartAllPaths(ctx, rep))
+	require.True(t, r.Running())
+	require.Error(t, r.StartRelayer(ctx, rep, "ab"))
+	require.NoError(t, r.StopRelayer(ctx, rep))
+	require.False(t, r.Running())
+}
@@ -55,3 +55,48 @@ func StartupFlags(flags ...string) RelayerOption {
 }
 
 func (opt RelayerOptionExtraStartFlags) relayerOption() {}
+
+type RelayerOptionChainTypes struct {
+	Types []string
+}
+
+// SupportedChainTypes overrides the chain types, e.g. "cosmos" or "polkadot",
+// that the relayer image can be configured for.
+// This is useful for relayer images built with support for more chain types than the default image.
+func SupportedChainTypes(types ...string) RelayerOption {
+	return RelayerOptionChainTypes{
+		Types: types,
+	}
+}
+
+func (opt RelayerOptionChainTypes) relayerOption() {}
+
+type RelayerOptionLogging struct {
+	Level, Format string
+}
+
+// Logging sets the relayer's log level, e.g. "debug" or "info", and log format, e.g. "json" or "console".
+// An empty value leaves the relayer's default unchanged.
+//
+// With the "json" format, the logs of a started relayer are parsed and emitted
+// through the test's logger with their original levels and fields,
+// so that relayer errors appear inline in the test log.
+func Logging(level, format string) RelayerOption {
+	return RelayerOptionLogging{
+		Level:  level,
+		Format: format,
+	}
+}
+
+func (opt RelayerOptionLogging) relayerOption() {}
+
+type RelayerOptionProfiling struct{}
+
+// Profiling makes a started relayer serve Go pprof handlers,
+// so that its profiles can be collected through ibc.Profiler.
+// The relayer's commander must support serving profiles; see ProfilingCommander.
+func Profiling() RelayerOption {
+	return RelayerOptionProfiling{}
+}
+
+func (opt RelayerOptionProfiling) relayerOption() {}
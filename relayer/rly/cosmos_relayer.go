@@ -4,9 +4,13 @@ package rly
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/docker/docker/client"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/relayer"
@@ -20,11 +24,17 @@ type CosmosRelayer struct {
 }
 
 func NewCosmosRelayer(log *zap.Logger, testName string, cli *client.Client, networkID string, options ...relayer.RelayerOption) *CosmosRelayer {
-	c := commander{log: log}
+	c := commander{log: log, chainTypes: DefaultChainTypes, version: DefaultContainerVersion}
 	for _, opt := range options {
 		switch o := opt.(type) {
+		case relayer.RelayerOptionDockerImage:
+			c.version = o.DockerImage.Version
 		case relayer.RelayerOptionExtraStartFlags:
 			c.extraStartFlags = o.Flags
+		case relayer.RelayerOptionChainTypes:
+			c.chainTypes = o.Types
+		case relayer.RelayerOptionLogging:
+			c.logLevel, c.logFormat = o.Level, o.Format
 		}
 	}
 	dr, err := relayer.NewDockerRelayer(context.TODO(), log, testName, cli, networkID, c, options...)
@@ -40,18 +50,27 @@ func NewCosmosRelayer(log *zap.Logger, testName string, cli *client.Client, netw
 }
 
 type CosmosRelayerChainConfigValue struct {
-	AccountPrefix  string  `json:"account-prefix"`
-	ChainID        string  `json:"chain-id"`
-	Debug          bool    `json:"debug"`
-	GRPCAddr       string  `json:"grpc-addr"`
-	GasAdjustment  float64 `json:"gas-adjustment"`
-	GasPrices      string  `json:"gas-prices"`
-	Key            string  `json:"key"`
-	KeyringBackend string  `json:"keyring-backend"`
-	OutputFormat   string  `json:"output-format"`
-	RPCAddr        string  `json:"rpc-addr"`
-	SignMode       string  `json:"sign-mode"`
-	Timeout        string  `json:"timeout"`
+	AccountPrefix    string                         `json:"account-prefix"`
+	ChainID          string                         `json:"chain-id"`
+	Debug            bool                           `json:"debug"`
+	GRPCAddr         string                         `json:"grpc-addr"`
+	GasAdjustment    float64                        `json:"gas-adjustment"`
+	GasPrices        string                         `json:"gas-prices"`
+	MinGasAmount     uint64                         `json:"min-gas-amount,omitempty"`
+	MaxGasAmount     uint64                         `json:"max-gas-amount,omitempty"`
+	ExtensionOptions []CosmosRelayerExtensionOption `json:"extension-options,omitempty"`
+	Key              string                         `json:"key"`
+	KeyringBackend   string                         `json:"keyring-backend"`
+	OutputFormat     string                         `json:"output-format"`
+	RPCAddr          string                         `json:"rpc-addr"`
+	SignMode         string                         `json:"sign-mode"`
+	Timeout          string                         `json:"timeout"`
+}
+
+// CosmosRelayerExtensionOption is an extension option of the relayer's transactions on a cosmos chain.
+type CosmosRelayerExtensionOption struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
 }
 
 type CosmosRelayerChainConfig struct {
@@ -59,52 +78,225 @@ type CosmosRelayerChainConfig struct {
 	Value CosmosRelayerChainConfigValue `json:"value"`
 }
 
+type SubstrateRelayerChainConfigValue struct {
+	ChainID        string `json:"chain-id"`
+	Debug          bool   `json:"debug"`
+	Key            string `json:"key"`
+	KeyringBackend string `json:"keyring-backend"`
+	Network        uint16 `json:"network"`
+	OutputFormat   string `json:"output-format"`
+	RPCAddr        string `json:"rpc-addr"`
+	Timeout        string `json:"timeout"`
+}
+
+type SubstrateRelayerChainConfig struct {
+	Type  string                           `json:"type"`
+	Value SubstrateRelayerChainConfigValue `json:"value"`
+}
+
 const (
 	DefaultContainerImage   = "ghcr.io/cosmos/relayer"
 	DefaultContainerVersion = "v2.1.2"
+
+	// RlyDefaultUidGid is the uid:gid of the default user in the relayer image.
+	RlyDefaultUidGid = "100:1000" // docker run -it --rm --entrypoint echo ghcr.io/cosmos/relayer "$(id -u):$(id -g)"
+
+	// Generic substrate SS58 address format, used when a substrate chain does not configure its own.
+	defaultSS58Format = 42
+	// SS58 address formats are encoded in at most 14 bits.
+	maxSS58Format = 1<<14 - 1
 )
 
-// Capabilities returns the set of capabilities of the Cosmos relayer.
-//
-// Note, this API may change if the rly package eventually needs
-// to distinguish between multiple rly versions.
+// DefaultChainTypes are the chain types supported by the default relayer image.
+// Use relayer.SupportedChainTypes for images that support other chain types.
+var DefaultChainTypes = []string{"cosmos"}
+
+// extensionOptionsVersion is the first rly version supporting the gas limits
+// and extension options of its transactions.
+var extensionOptionsVersion = [3]int{2, 2, 0}
+
+// Capabilities returns the set of capabilities of the default version of the Cosmos relayer.
 func Capabilities() map[relayer.Capability]bool {
-	// RC1 matches the full set of capabilities as of writing.
-	return relayer.FullCapabilities()
+	return CapabilitiesForVersion(DefaultContainerVersion)
+}
+
+// CapabilitiesForVersion returns the set of capabilities of the given version of the Cosmos relayer.
+// Versions that are not semantic versions, such as "main" or "latest", are assumed to be recent.
+func CapabilitiesForVersion(version string) map[relayer.Capability]bool {
+	caps := relayer.FullCapabilities()
+
+	caps[relayer.ExtensionOptions] = versionAtLeast(version, extensionOptionsVersion)
+
+	// rly always pays the fees of its transactions from its own wallet.
+	caps[relayer.FeeGranter] = false
+
+	// rly derives the trust level and the substrate client state of the clients it creates.
+	caps[relayer.ClientTrustLevel] = false
+	caps[relayer.SubstrateClientState] = false
+
+	return caps
+}
+
+// versionAtLeast reports whether version is at least want.
+// Versions that cannot be parsed are assumed to be recent.
+func versionAtLeast(version string, want [3]int) bool {
+	have, ok := parseVersion(version)
+	if !ok {
+		return true
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			return have[i] > want[i]
+		}
+	}
+	return true
+}
+
+// parseVersion parses the major, minor and patch numbers of version, e.g. "v2.1.2" or "2.2.0-rc1".
+// Missing minor and patch numbers are zero. Pre-release and build suffixes are ignored.
+func parseVersion(version string) ([3]int, bool) {
+	var v [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if version == "" || len(parts) > len(v) {
+		return v, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
 }
 
+// formatVersion formats v as a version tag, e.g. "v2.2.0".
+func formatVersion(v [3]int) string {
+	return fmt.Sprintf("v%d.%d.%d", v[0], v[1], v[2])
+}
+
+// ChainConfigToCosmosRelayerChainConfig converts a cosmos chain config to a relayer chain config.
+// The gas settings of chainConfig.RelayerGas other than the multiplier require the relayer.ExtensionOptions
+// capability, see CapabilitiesForVersion.
 func ChainConfigToCosmosRelayerChainConfig(chainConfig ibc.ChainConfig, keyName, rpcAddr, gprcAddr string) CosmosRelayerChainConfig {
+	var extensionOptions []CosmosRelayerExtensionOption
+	for _, o := range chainConfig.RelayerGas.ExtensionOptions {
+		extensionOptions = append(extensionOptions, CosmosRelayerExtensionOption{Type: o.Type, Value: o.Value})
+	}
 	return CosmosRelayerChainConfig{
 		Type: chainConfig.Type,
 		Value: CosmosRelayerChainConfigValue{
+			Key:              keyName,
+			ChainID:          chainConfig.ChainID,
+			RPCAddr:          rpcAddr,
+			GRPCAddr:         gprcAddr,
+			AccountPrefix:    chainConfig.Bech32Prefix,
+			KeyringBackend:   keyring.BackendTest,
+			GasAdjustment:    chainConfig.RelayerGas.Multiplier(chainConfig.GasAdjustment),
+			GasPrices:        chainConfig.GasPrices,
+			MinGasAmount:     chainConfig.RelayerGas.DefaultGas,
+			MaxGasAmount:     chainConfig.RelayerGas.MaxGas,
+			ExtensionOptions: extensionOptions,
+			Debug:            true,
+			Timeout:          "10s",
+			OutputFormat:     "json",
+			SignMode:         "direct",
+		},
+	}
+}
+
+// ChainConfigToSubstrateRelayerChainConfig converts a substrate chain config to a relayer chain config.
+// The chain's SS58Format is used for its addresses, or the generic substrate format if it is not set.
+// wsAddr is the chain's websocket URL, or its host and port.
+func ChainConfigToSubstrateRelayerChainConfig(chainConfig ibc.ChainConfig, keyName, wsAddr string) (SubstrateRelayerChainConfig, error) {
+	network := uint16(defaultSS58Format)
+	if chainConfig.SS58Format != nil {
+		network = *chainConfig.SS58Format
+	}
+	if network > maxSS58Format {
+		return SubstrateRelayerChainConfig{}, fmt.Errorf("chain %s: invalid SS58 address format %d: must be at most %d", chainConfig.ChainID, network, maxSS58Format)
+	}
+	return SubstrateRelayerChainConfig{
+		Type: chainConfig.Type,
+		Value: SubstrateRelayerChainConfigValue{
 			Key:            keyName,
 			ChainID:        chainConfig.ChainID,
-			RPCAddr:        rpcAddr,
-			GRPCAddr:       gprcAddr,
-			AccountPrefix:  chainConfig.Bech32Prefix,
+			RPCAddr:        wsURL(wsAddr),
+			Network:        network,
 			KeyringBackend: keyring.BackendTest,
-			GasAdjustment:  chainConfig.GasAdjustment,
-			GasPrices:      chainConfig.GasPrices,
 			Debug:          true,
 			Timeout:        "10s",
 			OutputFormat:   "json",
-			SignMode:       "direct",
 		},
+	}, nil
+}
+
+// validateCosmosChainConfig returns an error if chainConfig cannot produce a valid cosmos relayer chain config.
+func validateCosmosChainConfig(chainConfig ibc.ChainConfig) error {
+	if chainConfig.ChainID == "" {
+		return fmt.Errorf("chain %s: missing chain ID", chainConfig.Name)
 	}
+	if chainConfig.Bech32Prefix == "" {
+		return fmt.Errorf("chain %s: missing bech32 prefix", chainConfig.ChainID)
+	}
+	if strings.ToLower(chainConfig.Bech32Prefix) != chainConfig.Bech32Prefix {
+		return fmt.Errorf("chain %s: bech32 prefix %q must be lowercase", chainConfig.ChainID, chainConfig.Bech32Prefix)
+	}
+	if _, err := sdk.ParseDecCoins(chainConfig.GasPrices); err != nil {
+		return fmt.Errorf("chain %s: invalid gas prices %q: %w", chainConfig.ChainID, chainConfig.GasPrices, err)
+	}
+	if gas := chainConfig.RelayerGas; gas.MaxGas > 0 && gas.DefaultGas > gas.MaxGas {
+		return fmt.Errorf("chain %s: relayer default gas %d exceeds max gas %d", chainConfig.ChainID, gas.DefaultGas, gas.MaxGas)
+	}
+	for _, o := range chainConfig.RelayerGas.ExtensionOptions {
+		if o.Type == "" {
+			return fmt.Errorf("chain %s: relayer extension option without type", chainConfig.ChainID)
+		}
+	}
+	return nil
 }
 
-// commander satisfies relayer.RelayerCommander.
+// clientsOnlyChannel is a channel ID that no chain assigns,
+// allowlisted on a path to relay only its client updates.
+const clientsOnlyChannel = "clients-only"
+
+// commander satisfies relayer.RelayerCommander, relayer.CapabilityCommander, relayer.ClientUpdateCommander,
+// relayer.ProfilingCommander, and relayer.RPCTransportCommander.
 type commander struct {
 	log             *zap.Logger
 	extraStartFlags []string
+	chainTypes      []string
+
+	// Version of the relayer image, which determines its capabilities.
+	version string
+
+	// Optional log level and format of the started relayer.
+	logLevel, logFormat string
 }
 
 func (commander) Name() string {
 	return "rly"
 }
 
+// Capabilities implements relayer.CapabilityCommander.
+func (c commander) Capabilities() map[relayer.Capability]bool {
+	return CapabilitiesForVersion(c.imageVersion())
+}
+
+// imageVersion returns the version of the relayer image, or the default version if it is not set.
+func (c commander) imageVersion() string {
+	if c.version == "" {
+		return DefaultContainerVersion
+	}
+	return c.version
+}
+
 func (commander) DockerUser() string {
-	return "100:1000" // docker run -it --rm --entrypoint echo ghcr.io/cosmos/relayer "$(id -u):$(id -g)"
+	return RlyDefaultUidGid
 }
 
 func (commander) AddChainConfiguration(containerFilePath, homeDir string) []string {
@@ -133,13 +325,27 @@ func (commander) CreateChannel(pathName string, opts ibc.CreateChannelOptions, h
 	}
 }
 
-func (commander) CreateClients(pathName string, opts ibc.CreateClientOptions, homeDir string) []string {
+// CloseChannel implements relayer.ChannelCloseCommander.
+func (commander) CloseChannel(pathName, channelID, portID, homeDir string) []string {
 	return []string{
-		"rly", "tx", "clients", pathName, "--client-tp", opts.TrustingPeriod,
+		"rly", "tx", "channel-close", pathName, channelID, portID,
 		"--home", homeDir,
 	}
 }
 
+func (commander) CreateClients(pathName string, opts ibc.CreateClientOptions, homeDir string) []string {
+	cmd := []string{"rly", "tx", "clients", pathName}
+	cmd = append(cmd, createClientFlags(opts)...)
+	return append(cmd, "--home", homeDir)
+}
+
+// createClientFlags are the flags of the client options that the relayer would otherwise derive,
+// shared by the clients and link commands.
+// rly cannot set a client's trust level or substrate client state; see Capabilities.
+func createClientFlags(opts ibc.CreateClientOptions) []string {
+	return []string{"--client-tp", opts.TrustingPeriod}
+}
+
 // passing a value of 0 for customeClientTrustingPeriod will use default
 func (commander) CreateClient(pathName, homeDir, customeClientTrustingPeriod string) []string {
 	return []string{
@@ -176,13 +382,28 @@ func (commander) GeneratePath(srcChainID, dstChainID, pathName, homeDir string)
 	}
 }
 
-func (commander) UpdatePath(pathName, homeDir string, filter ibc.ChannelFilter) []string {
-	return []string{
+func (commander) UpdatePath(pathName, homeDir string, opts ibc.PathUpdateOptions) []string {
+	cmd := []string{
 		"rly", "paths", "update", pathName,
 		"--home", homeDir,
-		"--filter-rule", filter.Rule,
-		"--filter-channels", strings.Join(filter.ChannelList, ","),
 	}
+	if opts.ChannelFilter != nil {
+		cmd = append(cmd,
+			"--filter-rule", opts.ChannelFilter.Rule,
+			"--filter-channels", strings.Join(opts.ChannelFilter.ChannelList, ","),
+		)
+	}
+	for _, f := range []struct{ flag, value string }{
+		{"--src-client-id", opts.SrcClientID},
+		{"--src-connection-id", opts.SrcConnectionID},
+		{"--dst-client-id", opts.DstClientID},
+		{"--dst-connection-id", opts.DstConnectionID},
+	} {
+		if f.value != "" {
+			cmd = append(cmd, f.flag, f.value)
+		}
+	}
+	return cmd
 }
 
 func (commander) GetChannels(chainID, homeDir string) []string {
@@ -200,16 +421,15 @@ func (commander) GetConnections(chainID, homeDir string) []string {
 }
 
 func (commander) LinkPath(pathName, homeDir string, channelOpts ibc.CreateChannelOptions, clientOpt ibc.CreateClientOptions) []string {
-	return []string{
+	cmd := []string{
 		"rly", "tx", "link", pathName,
 		"--src-port", channelOpts.SourcePortName,
 		"--dst-port", channelOpts.DestPortName,
 		"--order", channelOpts.Order.String(),
 		"--version", channelOpts.Version,
-		"--client-tp", clientOpt.TrustingPeriod,
-
-		"--home", homeDir,
 	}
+	cmd = append(cmd, createClientFlags(clientOpt)...)
+	return append(cmd, "--home", homeDir)
 }
 
 func (commander) RestoreKey(chainID, keyName, mnemonic, homeDir string) []string {
@@ -220,15 +440,47 @@ func (commander) RestoreKey(chainID, keyName, mnemonic, homeDir string) []string
 }
 
 func (c commander) StartRelayer(homeDir string, pathNames ...string) []string {
-	cmd := []string{
-		"rly", "start", "--debug",
-		"--home", homeDir,
+	cmd := []string{"rly", "start"}
+	if c.logLevel != "" {
+		cmd = append(cmd, "--log-level", c.logLevel)
+	} else {
+		cmd = append(cmd, "--debug")
+	}
+	if c.logFormat != "" {
+		cmd = append(cmd, "--log-format", c.logFormat)
 	}
+	cmd = append(cmd, "--home", homeDir)
 	cmd = append(cmd, c.extraStartFlags...)
 	cmd = append(cmd, pathNames...)
 	return cmd
 }
 
+// ClientsOnly allowlists a channel that does not exist on the path,
+// so that rly relays no packets on it but still updates its clients.
+// Relaying packets again removes the path's channel filter,
+// which relayer.DockerRelayer restores with UpdatePath if it was set.
+func (commander) ClientsOnly(pathName string, clientsOnly bool, homeDir string) []string {
+	rule, channels := "", ""
+	if clientsOnly {
+		rule, channels = "allowlist", clientsOnlyChannel
+	}
+	return []string{
+		"rly", "paths", "update", pathName,
+		"--filter-rule", rule,
+		"--filter-channels", channels,
+		"--home", homeDir,
+	}
+}
+
+func (commander) ClientUpdateIntervalFlags(interval time.Duration) []string {
+	return []string{"--time-threshold", interval.String()}
+}
+
+// DebugServerFlags serves pprof handlers from rly's debug server, available since rly v2.1.0.
+func (commander) DebugServerFlags(addr string) []string {
+	return []string{"--debug-addr", addr}
+}
+
 func (commander) UpdateClients(pathName, homeDir string) []string {
 	return []string{
 		"rly", "tx", "update-clients", pathName,
@@ -236,15 +488,72 @@ func (commander) UpdateClients(pathName, homeDir string) []string {
 	}
 }
 
-func (commander) ConfigContent(ctx context.Context, cfg ibc.ChainConfig, keyName, rpcAddr, grpcAddr string) ([]byte, error) {
-	cosmosRelayerChainConfig := ChainConfigToCosmosRelayerChainConfig(cfg, keyName, rpcAddr, grpcAddr)
-	jsonBytes, err := json.Marshal(cosmosRelayerChainConfig)
+func (c commander) ConfigContent(ctx context.Context, cfg ibc.ChainConfig, keyName, rpcAddr, grpcAddr string) ([]byte, error) {
+	supported := false
+	for _, t := range c.chainTypes {
+		supported = supported || t == cfg.Type
+	}
+	if !supported {
+		return nil, fmt.Errorf(
+			"chain %s has type %q, but the relayer image only supports chain types %v",
+			cfg.ChainID, cfg.Type, c.chainTypes,
+		)
+	}
+
+	var relayerChainConfig any
+	switch cfg.Type {
+	case "cosmos":
+		if err := validateCosmosChainConfig(cfg); err != nil {
+			return nil, err
+		}
+		if gas := cfg.RelayerGas; (gas.DefaultGas > 0 || gas.MaxGas > 0 || len(gas.ExtensionOptions) > 0) &&
+			!c.Capabilities()[relayer.ExtensionOptions] {
+			return nil, fmt.Errorf(
+				"chain %s: relayer gas limits and extension options require rly %s or later, got %s",
+				cfg.ChainID, formatVersion(extensionOptionsVersion), c.imageVersion(),
+			)
+		}
+		relayerChainConfig = ChainConfigToCosmosRelayerChainConfig(cfg, keyName, rpcAddr, grpcAddr)
+	case "polkadot":
+		// rpcAddr is the websocket URL of chains providing typed endpoints, see RPCTransport.
+		// Otherwise, substrate chains report their websocket address as the gRPC address.
+		wsAddr := grpcAddr
+		if strings.HasPrefix(rpcAddr, "ws://") || strings.HasPrefix(rpcAddr, "wss://") {
+			wsAddr = rpcAddr
+		}
+		substrateConfig, err := ChainConfigToSubstrateRelayerChainConfig(cfg, keyName, wsAddr)
+		if err != nil {
+			return nil, err
+		}
+		relayerChainConfig = substrateConfig
+	default:
+		return nil, fmt.Errorf("chain %s: no relayer chain config schema for chain type %q", cfg.ChainID, cfg.Type)
+	}
+
+	jsonBytes, err := json.Marshal(relayerChainConfig)
 	if err != nil {
 		return nil, err
 	}
 	return jsonBytes, nil
 }
 
+// RPCTransport implements relayer.RPCTransportCommander.
+// The relayer connects to substrate chains over websocket.
+func (commander) RPCTransport(chainType string) ibc.Transport {
+	if chainType == "polkadot" {
+		return ibc.TransportWebSocket
+	}
+	return ""
+}
+
+// wsURL returns addr with the websocket scheme, unless it already has a scheme.
+func wsURL(addr string) string {
+	if strings.Contains(addr, "://") {
+		return addr
+	}
+	return "ws://" + addr
+}
+
 func (commander) DefaultContainerImage() string {
 	return DefaultContainerImage
 }
@@ -0,0 +1,213 @@
+package rly
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/relayer"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConfigContent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cosmosCfg := ibc.ChainConfig{
+		Type:         "cosmos",
+		ChainID:      "gaia-1",
+		Bech32Prefix: "cosmos",
+		GasPrices:    "0.01uatom",
+	}
+	polkadotCfg := ibc.ChainConfig{
+		Type:    "polkadot",
+		ChainID: "rococo-local",
+	}
+
+	c := commander{log: zap.NewNop(), chainTypes: DefaultChainTypes}
+
+	content, err := c.ConfigContent(ctx, cosmosCfg, "key", "rpc:26657", "grpc:9090")
+	require.NoError(t, err)
+	var cosmos CosmosRelayerChainConfig
+	require.NoError(t, json.Unmarshal(content, &cosmos))
+	require.Equal(t, "cosmos", cosmos.Value.AccountPrefix)
+
+	_, err = c.ConfigContent(ctx, polkadotCfg, "key", "rpc:27451", "ws:27451")
+	require.ErrorContains(t, err, "only supports chain types")
+
+	noPrefix := cosmosCfg
+	noPrefix.Bech32Prefix = ""
+	_, err = c.ConfigContent(ctx, noPrefix, "key", "rpc:26657", "grpc:9090")
+	require.ErrorContains(t, err, "missing bech32 prefix")
+
+	badGas := cosmosCfg
+	badGas.GasPrices = "cheap"
+	_, err = c.ConfigContent(ctx, badGas, "key", "rpc:26657", "grpc:9090")
+	require.ErrorContains(t, err, "invalid gas prices")
+
+	gas := cosmosCfg
+	gas.GasAdjustment = 1.3
+	gas.RelayerGas = ibc.RelayerGas{
+		DefaultGas:       100_000,
+		MaxGas:           5_000_000,
+		ExtensionOptions: []ibc.ExtensionOption{{Type: "ethermint_dynamic_fee", Value: "1000000"}},
+	}
+	_, err = c.ConfigContent(ctx, gas, "key", "rpc:26657", "grpc:9090")
+	require.ErrorContains(t, err, "require rly v2.2.0 or later, got v2.1.2")
+
+	c.version = "v2.2.0"
+	content, err = c.ConfigContent(ctx, gas, "key", "rpc:26657", "grpc:9090")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &cosmos))
+	require.Equal(t, 1.3, cosmos.Value.GasAdjustment)
+	require.Equal(t, uint64(100_000), cosmos.Value.MinGasAmount)
+	require.Equal(t, uint64(5_000_000), cosmos.Value.MaxGasAmount)
+	require.Equal(t, []CosmosRelayerExtensionOption{{Type: "ethermint_dynamic_fee", Value: "1000000"}}, cosmos.Value.ExtensionOptions)
+
+	gas.RelayerGas.GasMultiplier = 1.5
+	content, err = c.ConfigContent(ctx, gas, "key", "rpc:26657", "grpc:9090")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &cosmos))
+	require.Equal(t, 1.5, cosmos.Value.GasAdjustment)
+
+	gas.RelayerGas.MaxGas = 50_000
+	_, err = c.ConfigContent(ctx, gas, "key", "rpc:26657", "grpc:9090")
+	require.ErrorContains(t, err, "relayer default gas 100000 exceeds max gas 50000")
+
+	c.chainTypes = []string{"cosmos", "polkadot"}
+	content, err = c.ConfigContent(ctx, polkadotCfg, "key", "rpc:27451", "ws:27451")
+	require.NoError(t, err)
+	var substrate SubstrateRelayerChainConfig
+	require.NoError(t, json.Unmarshal(content, &substrate))
+	require.Equal(t, uint16(42), substrate.Value.Network)
+	require.Equal(t, "ws://ws:27451", substrate.Value.RPCAddr)
+
+	// Chains with typed endpoints pass the websocket URL as the rpc address.
+	require.Equal(t, ibc.TransportWebSocket, c.RPCTransport("polkadot"))
+	require.Empty(t, c.RPCTransport("cosmos"))
+	content, err = c.ConfigContent(ctx, polkadotCfg, "key", "ws://para:27451", "para:27451")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &substrate))
+	require.Equal(t, "ws://para:27451", substrate.Value.RPCAddr)
+
+	polkadotFormat := uint16(0)
+	withFormat := polkadotCfg
+	withFormat.SS58Format = &polkadotFormat
+	content, err = c.ConfigContent(ctx, withFormat, "key", "rpc:27451", "ws:27451")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &substrate))
+	require.Equal(t, uint16(0), substrate.Value.Network)
+
+	badFormat := polkadotCfg
+	tooLarge := uint16(1 << 14)
+	badFormat.SS58Format = &tooLarge
+	_, err = c.ConfigContent(ctx, badFormat, "key", "rpc:27451", "ws:27451")
+	require.ErrorContains(t, err, "invalid SS58 address format")
+}
+
+func TestUpdatePath(t *testing.T) {
+	t.Parallel()
+
+	var c commander
+
+	require.Equal(t, []string{
+		"rly", "paths", "update", "p", "--home", "/home/relayer",
+		"--src-client-id", "07-tendermint-3",
+		"--dst-client-id", "10-grandpa-0",
+		"--dst-connection-id", "connection-1",
+	}, c.UpdatePath("p", "/home/relayer", ibc.PathUpdateOptions{
+		SrcClientID:     "07-tendermint-3",
+		DstClientID:     "10-grandpa-0",
+		DstConnectionID: "connection-1",
+	}))
+
+	require.Equal(t, []string{
+		"rly", "paths", "update", "p", "--home", "/home/relayer",
+		"--filter-rule", "allowlist",
+		"--filter-channels", "channel-0,channel-1",
+	}, c.UpdatePath("p", "/home/relayer", ibc.PathUpdateOptions{
+		ChannelFilter: &ibc.ChannelFilter{Rule: "allowlist", ChannelList: []string{"channel-0", "channel-1"}},
+	}))
+}
+
+func TestStartRelayer_Logging(t *testing.T) {
+	t.Parallel()
+
+	c := commander{extraStartFlags: []string{"-b", "100"}}
+	require.Equal(t,
+		[]string{"rly", "start", "--debug", "--home", "/home/relayer", "-b", "100", "p"},
+		c.StartRelayer("/home/relayer", "p"),
+	)
+
+	c.logLevel, c.logFormat = "info", "json"
+	require.Equal(t,
+		[]string{"rly", "start", "--log-level", "info", "--log-format", "json", "--home", "/home/relayer", "-b", "100", "p"},
+		c.StartRelayer("/home/relayer", "p"),
+	)
+}
+
+func TestClientUpdateCommander(t *testing.T) {
+	t.Parallel()
+
+	var c relayer.ClientUpdateCommander = commander{}
+	require.Equal(t, []string{
+		"rly", "paths", "update", "p",
+		"--filter-rule", "allowlist",
+		"--filter-channels", "clients-only",
+		"--home", "/home/relayer",
+	}, c.ClientsOnly("p", true, "/home/relayer"))
+	require.Equal(t, []string{
+		"rly", "paths", "update", "p",
+		"--filter-rule", "",
+		"--filter-channels", "",
+		"--home", "/home/relayer",
+	}, c.ClientsOnly("p", false, "/home/relayer"))
+
+	require.Equal(t, []string{"--time-threshold", "1m30s"}, c.ClientUpdateIntervalFlags(90*time.Second))
+}
+
+func TestChannelCloseCommander(t *testing.T) {
+	t.Parallel()
+
+	var c relayer.ChannelCloseCommander = commander{}
+	require.Equal(t, []string{
+		"rly", "tx", "channel-close", "p", "channel-0", "transfer",
+		"--home", "/home/relayer",
+	}, c.CloseChannel("p", "channel-0", "transfer", "/home/relayer"))
+}
+
+func TestCreateClientFlags(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"--client-tp", "0"}, createClientFlags(ibc.DefaultClientOpts()))
+	require.Equal(t, []string{"--client-tp", "336h"}, createClientFlags(ibc.CreateClientOptions{TrustingPeriod: "336h"}))
+
+	caps := commander{}.Capabilities()
+	require.False(t, caps[relayer.ClientTrustLevel])
+	require.False(t, caps[relayer.SubstrateClientState])
+	require.False(t, caps[relayer.FeeGranter])
+}
+
+func TestCapabilitiesForVersion(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, CapabilitiesForVersion(DefaultContainerVersion), Capabilities())
+
+	for version, want := range map[string]bool{
+		"v2.1.2":     false,
+		"2.1":        false,
+		"v2.2.0-rc1": true,
+		"v2.2.0":     true,
+		"v3":         true,
+		"main":       true,
+		"latest":     true,
+	} {
+		require.Equal(t, want, CapabilitiesForVersion(version)[relayer.ExtensionOptions], version)
+	}
+
+	require.False(t, commander{}.Capabilities()[relayer.ExtensionOptions])
+	require.True(t, commander{version: "v2.2.0"}.Capabilities()[relayer.ExtensionOptions])
+}
@@ -0,0 +1,171 @@
+package relayer
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
+	"go.uber.org/zap"
+)
+
+// HomeSnapshot is a point-in-time copy of a DockerRelayer's home directory,
+// which includes the relayer's configuration, keys, and any other state it persists to disk.
+//
+// A snapshot taken from one DockerRelayer may be restored into another,
+// possibly running a different relayer version,
+// in order to test relayer upgrade and downgrade migrations.
+type HomeSnapshot struct {
+	// Tar archive of the home directory.
+	// Entry names are relative to the home directory.
+	Tar []byte
+
+	// The wallets known to the relayer at the time of the snapshot, keyed by chain ID.
+	Wallets map[string]ibc.Wallet
+}
+
+// SnapshotHome returns a copy of the relayer's home directory.
+// The relayer should be stopped before taking a snapshot,
+// so that the snapshot is not taken while the relayer is writing its state.
+func (r *DockerRelayer) SnapshotHome(ctx context.Context) (HomeSnapshot, error) {
+	containerName := r.Name() + "-snapshot-" + dockerutil.RandLowerCaseLetterString(5)
+	platform, err := dockerutil.ParsePlatform(r.containerImage().Platform)
+	if err != nil {
+		return HomeSnapshot{}, err
+	}
+	cc, err := r.client.ContainerCreate(
+		ctx,
+		&container.Config{
+			Image: r.containerImage().Ref(),
+
+			// The container is never started; it only exists to access the volume.
+			Entrypoint: []string{},
+			Cmd:        []string{"true"},
+
+			Labels: dockerutil.Labels(r.testName),
+		},
+		&container.HostConfig{
+			Binds:      r.Bind(),
+			AutoRemove: false,
+		},
+		nil,
+		platform,
+		containerName,
+	)
+	if err != nil {
+		return HomeSnapshot{}, fmt.Errorf("creating container for snapshot: %w", err)
+	}
+	defer func() {
+		if err := r.client.ContainerRemove(ctx, cc.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			r.log.Info("Failed to remove snapshot container", zap.String("container_id", cc.ID), zap.Error(err))
+		}
+	}()
+
+	rc, _, err := r.client.CopyFromContainer(ctx, cc.ID, r.HomeDir())
+	if err != nil {
+		return HomeSnapshot{}, fmt.Errorf("copying relayer home from container: %w", err)
+	}
+	defer rc.Close()
+
+	// The archive's entries are rooted at the base name of the home directory.
+	// Strip that prefix so the snapshot can be restored into a relayer with a different home directory.
+	tarBytes, err := rerootTar(rc, path.Base(r.HomeDir()))
+	if err != nil {
+		return HomeSnapshot{}, fmt.Errorf("rewriting relayer home archive: %w", err)
+	}
+
+	wallets := make(map[string]ibc.Wallet, len(r.wallets))
+	for chainID, w := range r.wallets {
+		wallets[chainID] = w
+	}
+
+	return HomeSnapshot{
+		Tar:     tarBytes,
+		Wallets: wallets,
+	}, nil
+}
+
+// RestoreHome replaces the contents of the relayer's home directory with the given snapshot,
+// and replaces the relayer's known wallets with those in the snapshot.
+// The relayer must be stopped before its home is restored.
+func (r *DockerRelayer) RestoreHome(ctx context.Context, snap HomeSnapshot) error {
+	if err := r.runOneOff(ctx, oneOffOptions{
+		ContainerNameDetail: "restore-clear",
+		Entrypoint:          []string{"sh", "-c"},
+		// Remove all entries, including hidden ones, but leave the mounted directory itself in place.
+		Cmd:  []string{`find "$1" -mindepth 1 -delete`, "_", r.HomeDir()},
+		User: dockerutil.GetRootUserString(),
+	}); err != nil {
+		return fmt.Errorf("clearing relayer home: %w", err)
+	}
+
+	if err := r.untarIntoNodeHome(ctx, bytes.NewReader(snap.Tar)); err != nil {
+		return fmt.Errorf("restoring relayer home: %w", err)
+	}
+
+	r.wallets = make(map[string]ibc.Wallet, len(snap.Wallets))
+	for chainID, w := range snap.Wallets {
+		r.wallets[chainID] = w
+	}
+
+	return nil
+}
+
+// rerootTar returns a copy of the tar archive read from r,
+// with the leading root directory removed from the names of the entries within it,
+// and from the targets of hard links within it.
+// The root directory entry itself is dropped, and entries outside the root are kept as is.
+func rerootTar(r io.Reader, root string) ([]byte, error) {
+	var buf bytes.Buffer
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name, inRoot := rerootPath(hdr.Name, root)
+		if inRoot && name == "" {
+			// The root directory itself.
+			continue
+		}
+		hdr.Name = name
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname, _ = rerootPath(hdr.Linkname, root)
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// rerootPath returns the archive path p relative to the root directory, and whether p is within root.
+// Paths outside root, including those of siblings sharing its name as a prefix, are returned unchanged.
+func rerootPath(p, root string) (string, bool) {
+	if p == root {
+		return "", true
+	}
+	if rel := strings.TrimPrefix(p, root+"/"); rel != p {
+		return rel, true
+	}
+	return p, false
+}
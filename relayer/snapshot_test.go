@@ -0,0 +1,134 @@
+package relayer
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRerootTar(t *testing.T) {
+	t.Parallel()
+
+	type entry struct {
+		name, linkname, body string
+	}
+
+	build := func(t *testing.T, entries []entry) []byte {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, e := range entries {
+			hdr := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.body))}
+			switch {
+			case e.linkname != "":
+				hdr.Typeflag = tar.TypeLink
+			case e.name[len(e.name)-1] == '/':
+				hdr.Typeflag = tar.TypeDir
+				hdr.Mode = 0o755
+			default:
+				hdr.Typeflag = tar.TypeReg
+			}
+			hdr.Linkname = e.linkname
+			require.NoError(t, tw.WriteHeader(hdr))
+			_, err := tw.Write([]byte(e.body))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		return buf.Bytes()
+	}
+
+	read := func(t *testing.T, b []byte) []entry {
+		var entries []entry
+		tr := tar.NewReader(bytes.NewReader(b))
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return entries
+			}
+			require.NoError(t, err)
+			body, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			entries = append(entries, entry{name: hdr.Name, linkname: hdr.Linkname, body: string(body)})
+		}
+	}
+
+	for _, tt := range []struct {
+		name string
+		in   []entry
+		want []entry
+	}{
+		{
+			name: "entries within the root",
+			in: []entry{
+				{name: "relayer/"},
+				{name: "relayer/config/"},
+				{name: "relayer/config/config.yaml", body: "global: {}"},
+				{name: "relayer/keys/gaia-1/keyring-test/a.info", body: "key"},
+			},
+			want: []entry{
+				{name: "config/"},
+				{name: "config/config.yaml", body: "global: {}"},
+				{name: "keys/gaia-1/keyring-test/a.info", body: "key"},
+			},
+		},
+		{
+			name: "root entry without trailing slash",
+			in: []entry{
+				{name: "relayer"},
+				{name: "relayer/config.yaml", body: "x"},
+			},
+			want: []entry{
+				{name: "config.yaml", body: "x"},
+			},
+		},
+		{
+			name: "entries outside the root",
+			in: []entry{
+				{name: "relayer/config.yaml", body: "in"},
+				{name: "other/config.yaml", body: "out"},
+				{name: "relayer2/config.yaml", body: "sibling"},
+				{name: "relayer.yaml", body: "file"},
+			},
+			want: []entry{
+				{name: "config.yaml", body: "in"},
+				{name: "other/config.yaml", body: "out"},
+				{name: "relayer2/config.yaml", body: "sibling"},
+				{name: "relayer.yaml", body: "file"},
+			},
+		},
+		{
+			name: "hard links",
+			in: []entry{
+				{name: "relayer/keys/a.info", body: "key"},
+				{name: "relayer/keys/b.info", linkname: "relayer/keys/a.info"},
+				{name: "relayer/keys/c.info", linkname: "other/a.info"},
+			},
+			want: []entry{
+				{name: "keys/a.info", body: "key"},
+				{name: "keys/b.info", linkname: "keys/a.info"},
+				{name: "keys/c.info", linkname: "other/a.info"},
+			},
+		},
+		{
+			name: "empty archive",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := rerootTar(bytes.NewReader(build(t, tt.in)), "relayer")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, read(t, out))
+		})
+	}
+
+	t.Run("invalid archive", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := rerootTar(bytes.NewReader([]byte("not a tar archive, but long enough to fill a header block")), "relayer")
+		require.Error(t, err)
+	})
+}
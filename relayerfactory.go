@@ -1,14 +1,12 @@
 package ibctest
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/docker/docker/client"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/label"
 	"github.com/strangelove-ventures/ibctest/v6/relayer"
-	"github.com/strangelove-ventures/ibctest/v6/relayer/rly"
 	"go.uber.org/zap"
 )
 
@@ -16,7 +14,7 @@ import (
 type RelayerFactory interface {
 	// Build returns a Relayer associated with the given arguments.
 	Build(
-		t *testing.T,
+		t testing.TB,
 		cli *client.Client,
 		networkID string,
 	) ibc.Relayer
@@ -39,7 +37,7 @@ type RelayerFactory interface {
 }
 
 // builtinRelayerFactory is the built-in relayer factory that understands
-// how to start the cosmos relayer in a docker container.
+// how to start the cosmos relayer, or any relayer registered through RegisterRelayer, in a docker container.
 type builtinRelayerFactory struct {
 	impl    ibc.RelayerImplementation
 	log     *zap.Logger
@@ -51,59 +49,64 @@ func NewBuiltinRelayerFactory(impl ibc.RelayerImplementation, logger *zap.Logger
 }
 
 // Build returns a relayer chosen depending on f.impl.
+// Unless f's options set the relayer's logging, the relayer logs at Config.LogLevel, if set.
 func (f builtinRelayerFactory) Build(
-	t *testing.T,
+	t testing.TB,
 	cli *client.Client,
 	networkID string,
 ) ibc.Relayer {
-	switch f.impl {
-	case ibc.CosmosRly:
-		return rly.NewCosmosRelayer(
-			f.log,
-			t.Name(),
-			cli,
-			networkID,
-			f.options...,
-		)
-	default:
-		panic(fmt.Errorf("RelayerImplementation %v unknown", f.impl))
+	return relayerBuilder(f.impl).New(
+		f.log,
+		t.Name(),
+		cli,
+		networkID,
+		f.optionsWithConfig()...,
+	)
+}
+
+// optionsWithConfig returns f's options, with the configured log level if the options do not set logging.
+func (f builtinRelayerFactory) optionsWithConfig() relayer.RelayerOptions {
+	level := CurrentConfig().LogLevel
+	if level == "" {
+		return f.options
+	}
+	for _, opt := range f.options {
+		if _, ok := opt.(relayer.RelayerOptionLogging); ok {
+			return f.options
+		}
 	}
+	return append(append(relayer.RelayerOptions(nil), f.options...), relayer.Logging(level, ""))
 }
 
 func (f builtinRelayerFactory) Name() string {
-	switch f.impl {
-	case ibc.CosmosRly:
-		// This is using the string "rly" instead of rly.ContainerImage
-		// so that the slashes in the image repository don't add ambiguity
-		// to subtest paths, when the factory name is used in calls to t.Run.
-		for _, opt := range f.options {
-			switch o := opt.(type) {
-			case relayer.RelayerOptionDockerImage:
-				return "rly@" + o.DockerImage.Version
-			}
+	return relayerBuilder(f.impl).Name + "@" + f.version()
+}
+
+// version returns the version of the relayer's custom docker image, or the relayer's default version.
+func (f builtinRelayerFactory) version() string {
+	for _, opt := range f.options {
+		switch o := opt.(type) {
+		case relayer.RelayerOptionDockerImage:
+			return o.DockerImage.Version
 		}
-		return "rly@" + rly.DefaultContainerVersion
-	default:
-		panic(fmt.Errorf("RelayerImplementation %v unknown", f.impl))
 	}
+	return relayerBuilder(f.impl).DefaultVersion
 }
 
 func (f builtinRelayerFactory) Labels() []label.Relayer {
-	switch f.impl {
-	case ibc.CosmosRly:
-		return []label.Relayer{label.Rly}
-	default:
-		panic(fmt.Errorf("RelayerImplementation %v unknown", f.impl))
-	}
+	return relayerBuilder(f.impl).Labels
 }
 
 // Capabilities returns the set of capabilities for the
 // relayer implementation backing this factory.
 func (f builtinRelayerFactory) Capabilities() map[relayer.Capability]bool {
-	switch f.impl {
-	case ibc.CosmosRly:
-		return rly.Capabilities()
+	b := relayerBuilder(f.impl)
+	switch {
+	case b.VersionCapabilities != nil:
+		return b.VersionCapabilities(f.version())
+	case b.Capabilities != nil:
+		return b.Capabilities()
 	default:
-		panic(fmt.Errorf("RelayerImplementation %v unknown", f.impl))
+		return relayer.FullCapabilities()
 	}
 }
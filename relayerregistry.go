@@ -0,0 +1,130 @@
+package ibctest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/docker/docker/client"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/label"
+	"github.com/strangelove-ventures/ibctest/v6/relayer"
+	"github.com/strangelove-ventures/ibctest/v6/relayer/rly"
+	"go.uber.org/zap"
+)
+
+// RelayerBuilder describes how NewBuiltinRelayerFactory builds a relayer implementation
+// registered through RegisterRelayer.
+type RelayerBuilder struct {
+	// Name of the relayer implementation, e.g. "rly".
+	// It is used in the factory name, and to select the relayer by name, e.g. in conformance test matrices.
+	// It should not contain slashes, so that it does not add ambiguity to subtest paths.
+	Name string
+
+	// Version reported in the factory name when the relayer is not given a custom docker image.
+	DefaultVersion string
+
+	// Labels reported by the factory.
+	// They must be registered through label.RegisterRelayerLabel.
+	Labels []label.Relayer
+
+	// Capabilities returns the features supported by the relayer.
+	// If nil, the relayer is assumed to support every feature.
+	Capabilities func() map[relayer.Capability]bool
+
+	// VersionCapabilities returns the features supported by the given version of the relayer,
+	// i.e. the version of its custom docker image, or DefaultVersion.
+	// If set, it takes precedence over Capabilities.
+	VersionCapabilities func(version string) map[relayer.Capability]bool
+
+	// New returns a new relayer for the test testName, on the docker network networkID.
+	New func(log *zap.Logger, testName string, cli *client.Client, networkID string, options ...relayer.RelayerOption) ibc.Relayer
+}
+
+var (
+	relayerBuildersMu sync.RWMutex
+	relayerBuilders   = map[ibc.RelayerImplementation]RelayerBuilder{
+		ibc.CosmosRly: {
+			// This is using the string "rly" instead of rly.ContainerImage
+			// so that the slashes in the image repository don't add ambiguity
+			// to subtest paths, when the factory name is used in calls to t.Run.
+			Name:                "rly",
+			DefaultVersion:      rly.DefaultContainerVersion,
+			Labels:              []label.Relayer{label.Rly},
+			Capabilities:        rly.Capabilities,
+			VersionCapabilities: rly.CapabilitiesForVersion,
+			New: func(log *zap.Logger, testName string, cli *client.Client, networkID string, options ...relayer.RelayerOption) ibc.Relayer {
+				return rly.NewCosmosRelayer(log, testName, cli, networkID, options...)
+			},
+		},
+	}
+)
+
+// RegisterRelayer is available for external packages that may import ibctest,
+// to register any external relayer implementations they may provide,
+// so that NewBuiltinRelayerFactory builds them for impl,
+// and they can participate in conformance tests without changes to ibctest.
+// External packages should choose an impl value well outside the range of the ibc package's constants.
+//
+// Like label.RegisterRelayerLabel, RegisterRelayer is typically called inside init functions.
+// It panics if impl or the builder's name is already registered, or if the builder is incomplete.
+func RegisterRelayer(impl ibc.RelayerImplementation, builder RelayerBuilder) {
+	if builder.Name == "" {
+		panic(fmt.Errorf("RelayerImplementation %v must not be registered without a name", impl))
+	}
+	if builder.New == nil {
+		panic(fmt.Errorf("relayer %q must not be registered without a New function", builder.Name))
+	}
+
+	relayerBuildersMu.Lock()
+	defer relayerBuildersMu.Unlock()
+
+	if _, exists := relayerBuilders[impl]; exists {
+		panic(fmt.Errorf("RelayerImplementation %v already exists and must not be double registered", impl))
+	}
+	for _, b := range relayerBuilders {
+		if b.Name == builder.Name {
+			panic(fmt.Errorf("relayer %q already exists and must not be double registered", builder.Name))
+		}
+	}
+	relayerBuilders[impl] = builder
+}
+
+// RelayerImplementationByName returns the relayer implementation registered with name.
+func RelayerImplementationByName(name string) (ibc.RelayerImplementation, bool) {
+	relayerBuildersMu.RLock()
+	defer relayerBuildersMu.RUnlock()
+
+	for impl, b := range relayerBuilders {
+		if b.Name == name {
+			return impl, true
+		}
+	}
+	return 0, false
+}
+
+// RegisteredRelayerNames returns the sorted names of the registered relayer implementations.
+func RegisteredRelayerNames() []string {
+	relayerBuildersMu.RLock()
+	defer relayerBuildersMu.RUnlock()
+
+	names := make([]string, 0, len(relayerBuilders))
+	for _, b := range relayerBuilders {
+		names = append(names, b.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// relayerBuilder returns the builder registered for impl.
+// It panics if impl is not registered.
+func relayerBuilder(impl ibc.RelayerImplementation) RelayerBuilder {
+	relayerBuildersMu.RLock()
+	defer relayerBuildersMu.RUnlock()
+
+	b, ok := relayerBuilders[impl]
+	if !ok {
+		panic(fmt.Errorf("RelayerImplementation %v unknown", impl))
+	}
+	return b
+}
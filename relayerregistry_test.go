@@ -0,0 +1,66 @@
+package ibctest_test
+
+import (
+	"testing"
+
+	"github.com/docker/docker/client"
+	"github.com/strangelove-ventures/ibctest/v6"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/label"
+	"github.com/strangelove-ventures/ibctest/v6/relayer"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestRegisterRelayer(t *testing.T) {
+	t.Parallel()
+
+	const impl ibc.RelayerImplementation = 1000
+	label.RegisterRelayerLabel("registry-test-relayer")
+
+	ibctest.RegisterRelayer(impl, ibctest.RelayerBuilder{
+		Name:           "registry-test-relayer",
+		DefaultVersion: "v0.1.0",
+		Labels:         []label.Relayer{"registry-test-relayer"},
+		New: func(*zap.Logger, string, *client.Client, string, ...relayer.RelayerOption) ibc.Relayer {
+			return nil
+		},
+	})
+
+	newRelayer := func(*zap.Logger, string, *client.Client, string, ...relayer.RelayerOption) ibc.Relayer { return nil }
+	require.Panics(t, func() {
+		ibctest.RegisterRelayer(impl, ibctest.RelayerBuilder{Name: "other", New: newRelayer})
+	})
+	require.Panics(t, func() {
+		ibctest.RegisterRelayer(impl+1, ibctest.RelayerBuilder{Name: "rly", New: newRelayer})
+	})
+
+	got, ok := ibctest.RelayerImplementationByName("registry-test-relayer")
+	require.True(t, ok)
+	require.Equal(t, impl, got)
+	require.Contains(t, ibctest.RegisteredRelayerNames(), "registry-test-relayer")
+
+	f := ibctest.NewBuiltinRelayerFactory(impl, zaptest.NewLogger(t))
+	require.Equal(t, "registry-test-relayer@v0.1.0", f.Name())
+	require.Equal(t, []label.Relayer{"registry-test-relayer"}, f.Labels())
+	require.Equal(t, relayer.FullCapabilities(), f.Capabilities())
+
+	f = ibctest.NewBuiltinRelayerFactory(impl, zaptest.NewLogger(t), relayer.CustomDockerImage("example.com/relayer", "v0.2.0", "1000:1000"))
+	require.Equal(t, "registry-test-relayer@v0.2.0", f.Name())
+}
+
+func TestBuiltinRelayerFactory_Rly(t *testing.T) {
+	t.Parallel()
+
+	impl, ok := ibctest.RelayerImplementationByName("rly")
+	require.True(t, ok)
+	require.Equal(t, ibc.CosmosRly, impl)
+
+	f := ibctest.NewBuiltinRelayerFactory(ibc.CosmosRly, zaptest.NewLogger(t))
+	require.Equal(t, []label.Relayer{label.Rly}, f.Labels())
+	require.False(t, f.Capabilities()[relayer.ExtensionOptions])
+
+	f = ibctest.NewBuiltinRelayerFactory(ibc.CosmosRly, zaptest.NewLogger(t), relayer.CustomDockerImage("ghcr.io/cosmos/relayer", "v2.2.0", "100:1000"))
+	require.True(t, f.Capabilities()[relayer.ExtensionOptions])
+}
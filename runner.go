@@ -0,0 +1,348 @@
+package ibctest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"go.uber.org/zap"
+)
+
+// Runner provides the test name, logging, reporting and cleanup of a test
+// to programs running outside of go test, such as the ibctest CLI, long-lived devnets,
+// or other orchestration tools.
+//
+// Runner satisfies testing.TB, so it can be passed to DockerSetup, a RelayerFactory,
+// GetAndFundTestUsers and testify's require package:
+//
+//	r := ibctest.NewRunner("devnet", logger, nil)
+//	err := r.Run(func(r *ibctest.Runner) {
+//		client, network := ibctest.DockerSetup(r)
+//		// ...
+//	})
+//
+// Like in a test, FailNow, Fatal and the Skip methods stop the function passed to Run,
+// so they must be called from the goroutine running it.
+type Runner struct {
+	// testing.TB has an unexported method to prevent implementations outside of the testing package.
+	// Embedding the nil interface satisfies it. Runner implements every exported method of testing.TB,
+	// up to the methods added in Go 1.26, so that the nil interface is never called.
+	testing.TB
+
+	name string
+	log  *zap.Logger
+	rep  *testreporter.Reporter
+
+	// ctx is canceled just before the cleanup functions are called.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	cleanups []func()
+	errs     []string
+	failed   bool
+	skipped  bool
+	ran      bool
+}
+
+var _ testing.TB = (*Runner)(nil)
+
+// NewRunner returns a Runner for the named run, logging to log.
+// If rep is nil, the run is not reported.
+func NewRunner(name string, log *zap.Logger, rep *testreporter.Reporter) *Runner {
+	if name == "" {
+		panic(fmt.Errorf("NewRunner: name must not be empty"))
+	}
+	if log == nil {
+		log = zap.NewNop()
+	}
+	if rep == nil {
+		rep = testreporter.NewNopReporter()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Runner{name: name, log: log, rep: rep, ctx: ctx, cancel: cancel}
+}
+
+// Run calls fn, then the functions registered with Cleanup, in last added, first called order.
+// It returns an error if fn failed or panicked, unless fn was skipped.
+// Run may only be called once.
+func (r *Runner) Run(fn func(r *Runner)) error {
+	r.mu.Lock()
+	if r.ran {
+		r.mu.Unlock()
+		panic(fmt.Errorf("Runner %s: Run called more than once", r.name))
+	}
+	r.ran = true
+	r.mu.Unlock()
+
+	r.rep.TrackTest(r)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		finished := false
+		defer func() {
+			if v := recover(); v != nil {
+				r.Errorf("panic: %v\n%s", v, debug.Stack())
+			} else if !finished && !r.Skipped() {
+				// fn called runtime.Goexit directly, which fails a test too.
+				r.Fail()
+			}
+		}()
+
+		fn(r)
+		finished = true
+	}()
+	<-done
+
+	r.cancel()
+	r.runCleanups()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.failed || r.skipped {
+		return nil
+	}
+	if len(r.errs) == 0 {
+		return fmt.Errorf("%s failed", r.name)
+	}
+	return errors.New(r.name + " failed: " + strings.Join(r.errs, "; "))
+}
+
+// runCleanups calls the registered cleanup functions, including any added by cleanup functions.
+func (r *Runner) runCleanups() {
+	for {
+		r.mu.Lock()
+		n := len(r.cleanups)
+		if n == 0 {
+			r.mu.Unlock()
+			return
+		}
+		fn := r.cleanups[n-1]
+		r.cleanups = r.cleanups[:n-1]
+		r.mu.Unlock()
+
+		fn()
+	}
+}
+
+// Logger returns the logger passed to NewRunner.
+func (r *Runner) Logger() *zap.Logger {
+	return r.log
+}
+
+// Reporter returns the reporter passed to NewRunner.
+func (r *Runner) Reporter() *testreporter.Reporter {
+	return r.rep
+}
+
+// RelayerExecReporter returns a RelayerExecReporter for r, to pass to Interchain.Build.
+func (r *Runner) RelayerExecReporter() *testreporter.RelayerExecReporter {
+	return r.rep.RelayerExecReporter(r)
+}
+
+// Name returns the name passed to NewRunner.
+func (r *Runner) Name() string {
+	return r.name
+}
+
+// Helper is a no-op, as logs do not report the caller.
+func (r *Runner) Helper() {}
+
+// Parallel is a no-op, as a Runner does not run alongside tests.
+func (r *Runner) Parallel() {}
+
+// Cleanup registers fn to be called after the function passed to Run returns.
+func (r *Runner) Cleanup(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cleanups = append(r.cleanups, fn)
+}
+
+// Log logs args at info level, formatted like fmt.Sprintln.
+func (r *Runner) Log(args ...any) {
+	r.log.Info(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+// Logf logs a message at info level, formatted like fmt.Sprintf.
+func (r *Runner) Logf(format string, args ...any) {
+	r.log.Info(fmt.Sprintf(format, args...))
+}
+
+// Error logs args at error level and marks r as failed.
+func (r *Runner) Error(args ...any) {
+	r.logError(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+// Errorf logs a message at error level and marks r as failed.
+func (r *Runner) Errorf(format string, args ...any) {
+	r.logError(fmt.Sprintf(format, args...))
+}
+
+func (r *Runner) logError(msg string) {
+	r.log.Error(msg, zap.String("run", r.name))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, msg)
+	r.failed = true
+}
+
+// Fail marks r as failed and continues.
+func (r *Runner) Fail() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = true
+}
+
+// FailNow marks r as failed and stops the function passed to Run.
+func (r *Runner) FailNow() {
+	r.Fail()
+	runtime.Goexit()
+}
+
+// Failed reports whether r has failed.
+func (r *Runner) Failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.failed
+}
+
+// Fatal is Error followed by FailNow.
+func (r *Runner) Fatal(args ...any) {
+	r.Error(args...)
+	r.FailNow()
+}
+
+// Fatalf is Errorf followed by FailNow.
+func (r *Runner) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	r.FailNow()
+}
+
+// Skip is Log followed by SkipNow.
+func (r *Runner) Skip(args ...any) {
+	r.Log(args...)
+	r.SkipNow()
+}
+
+// Skipf is Logf followed by SkipNow.
+func (r *Runner) Skipf(format string, args ...any) {
+	r.Logf(format, args...)
+	r.SkipNow()
+}
+
+// SkipNow marks r as skipped and stops the function passed to Run.
+func (r *Runner) SkipNow() {
+	r.mu.Lock()
+	r.skipped = true
+	r.mu.Unlock()
+	runtime.Goexit()
+}
+
+// Skipped reports whether r was skipped.
+func (r *Runner) Skipped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.skipped
+}
+
+// Setenv sets the environment variable key to value, and restores it during cleanup.
+func (r *Runner) Setenv(key, value string) {
+	prev, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		r.Fatalf("Setenv %s: %v", key, err)
+	}
+	r.Cleanup(func() {
+		if ok {
+			_ = os.Setenv(key, prev)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	})
+}
+
+// TempDir returns a new temporary directory, removed during cleanup. See the package-level TempDir.
+func (r *Runner) TempDir() string {
+	return TempDir(r)
+}
+
+// Chdir changes the working directory to dir, and restores it during cleanup.
+func (r *Runner) Chdir(dir string) {
+	prev, err := os.Getwd()
+	if err != nil {
+		r.Fatalf("Chdir %s: %v", dir, err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		r.Fatalf("Chdir %s: %v", dir, err)
+	}
+	r.Cleanup(func() {
+		_ = os.Chdir(prev)
+	})
+}
+
+// Context returns a context that is canceled after the function passed to Run returns,
+// just before the functions registered with Cleanup are called.
+func (r *Runner) Context() context.Context {
+	return r.ctx
+}
+
+// Attr logs the attribute key with value at info level.
+func (r *Runner) Attr(key, value string) {
+	r.log.Info("Attribute", zap.String("run", r.name), zap.String(key, value))
+}
+
+// Output returns a writer whose lines are logged at info level, like Log.
+func (r *Runner) Output() io.Writer {
+	return &runnerOutput{r: r}
+}
+
+// ArtifactDir returns a directory for the run's artifacts, named after the run,
+// in the artifact directory of the package-level ArtifactDir. Unlike TempDir, it is not removed.
+func (r *Runner) ArtifactDir() string {
+	base, err := ArtifactDir()
+	if err != nil {
+		r.Fatalf("ArtifactDir: %v", err)
+	}
+	dir := filepath.Join(base, "runs", strings.ReplaceAll(r.name, string(filepath.Separator), "_"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		r.Fatalf("ArtifactDir: %v", err)
+	}
+	return dir
+}
+
+// runnerOutput is the writer returned by Runner.Output.
+type runnerOutput struct {
+	r *Runner
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write logs each complete line of p, buffering any incomplete last line.
+func (o *runnerOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.buf.Write(p)
+	for {
+		line, err := o.buf.ReadString('\n')
+		if err != nil {
+			// Keep the incomplete line for the next write.
+			o.buf.WriteString(line)
+			return len(p), nil
+		}
+		o.r.Log(strings.TrimSuffix(line, "\n"))
+	}
+}
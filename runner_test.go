@@ -0,0 +1,94 @@
+package ibctest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRunner(t *testing.T) {
+	t.Parallel()
+
+	t.Run("passed", func(t *testing.T) {
+		t.Parallel()
+
+		var order []int
+		var dir string
+		r := ibctest.NewRunner("devnet", zap.NewNop(), nil)
+		err := r.Run(func(r *ibctest.Runner) {
+			r.Cleanup(func() { order = append(order, 1) })
+			r.Cleanup(func() {
+				order = append(order, 2)
+				r.Cleanup(func() { order = append(order, 3) })
+			})
+			dir = r.TempDir()
+			require.DirExists(r, dir)
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 3, 1}, order)
+		require.NoDirExists(t, dir)
+		require.Equal(t, "devnet", r.Name())
+
+		require.Panics(t, func() { _ = r.Run(func(*ibctest.Runner) {}) })
+	})
+
+	t.Run("require failure", func(t *testing.T) {
+		t.Parallel()
+
+		cleanedUp := false
+		reached := false
+		r := ibctest.NewRunner("devnet", zap.NewNop(), nil)
+		err := r.Run(func(r *ibctest.Runner) {
+			r.Cleanup(func() { cleanedUp = true })
+			require.NoError(r, errors.New("chain halted"))
+			reached = true
+		})
+		require.ErrorContains(t, err, "chain halted")
+		require.True(t, r.Failed())
+		require.True(t, cleanedUp)
+		require.False(t, reached)
+	})
+
+	t.Run("skipped", func(t *testing.T) {
+		t.Parallel()
+
+		r := ibctest.NewRunner("devnet", zap.NewNop(), nil)
+		err := r.Run(func(r *ibctest.Runner) {
+			r.Skip("docker unavailable")
+		})
+		require.NoError(t, err)
+		require.True(t, r.Skipped())
+	})
+
+	t.Run("panicked", func(t *testing.T) {
+		t.Parallel()
+
+		r := ibctest.NewRunner("devnet", zap.NewNop(), nil)
+		err := r.Run(func(r *ibctest.Runner) {
+			panic("boom")
+		})
+		require.ErrorContains(t, err, "panic: boom")
+	})
+
+	t.Run("context and output", func(t *testing.T) {
+		t.Parallel()
+
+		var ctxErrInCleanup error
+		r := ibctest.NewRunner("devnet", zap.NewNop(), nil)
+		err := r.Run(func(r *ibctest.Runner) {
+			require.NoError(r, r.Context().Err())
+			r.Cleanup(func() { ctxErrInCleanup = r.Context().Err() })
+
+			r.Attr("chain", "gaia")
+			_, err := fmt.Fprint(r.Output(), "first line\nsecond ")
+			require.NoError(r, err)
+		})
+		require.NoError(t, err)
+		require.ErrorIs(t, ctxErrInCleanup, context.Canceled)
+	})
+}
@@ -0,0 +1,191 @@
+package ibctest
+
+import (
+	"context"
+	"fmt"
+
+	"cosmossdk.io/math"
+	"github.com/docker/docker/client"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/test"
+	"github.com/strangelove-ventures/ibctest/v6/testreporter"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap/zaptest"
+)
+
+// defaultSuiteUserFunds is how much of its native denom each test user is funded with by default.
+var defaultSuiteUserFunds = math.NewInt(10_000_000_000)
+
+// Suite is a testify suite whose tests share one interchain, built once before the first test of the suite,
+// with freshly funded users for each test.
+//
+// Embed Suite in a suite type, set the chains to build, and run it with suite.Run:
+//
+//	type TransferSuite struct {
+//		ibctest.Suite
+//	}
+//
+//	func TestTransfers(t *testing.T) {
+//		suite.Run(t, &TransferSuite{Suite: ibctest.Suite{
+//			ChainSpecs:     []*ibctest.ChainSpec{{Name: "gaia", Version: "v7.0.0"}, {Name: "osmosis", Version: "v11.0.0"}},
+//			RelayerFactory: ibctest.NewBuiltinRelayerFactory(ibc.CosmosRly, zap.NewNop()),
+//			StartRelayer:   true,
+//		}})
+//	}
+//
+//	func (s *TransferSuite) TestTransfer() {
+//		gaia, osmosis := s.Chains[0], s.Chains[1]
+//		// s.Users[0] and s.Users[1] are funded users on gaia and osmosis.
+//	}
+//
+// Suite implements SetupSuite, TearDownSuite and SetupTest;
+// a suite type overriding any of them must call Suite's method.
+// Per-test cleanup can be registered with s.T().Cleanup.
+type Suite struct {
+	suite.Suite
+
+	// Chains to build, in order.
+	ChainSpecs []*ChainSpec
+
+	// Optional. Relayer linking each consecutive pair of chains, e.g. the first chain to the second.
+	// Without a relayer, the chains are built without links.
+	RelayerFactory RelayerFactory
+
+	// Optional. Whether to start the relayer on every path after building the interchain.
+	StartRelayer bool
+
+	// Optional. Creates the links between the chains instead of linking each consecutive pair.
+	// The returned links must use relayer.
+	Links func(chains []ibc.Chain, relayer ibc.Relayer) []InterchainLink
+
+	// Optional. Options of Interchain.Build; the test name, docker client and network are set by the suite.
+	BuildOptions InterchainBuildOptions
+
+	// Optional. How much of its native denom each test user is funded with. Defaults to 10,000,000,000.
+	UserFunds math.Int
+
+	// Optional. Whether to skip funding users for each test, e.g. for chains without faucets.
+	SkipUserFunding bool
+
+	// Optional. Reporter of the suite. Defaults to a reporter that discards reports.
+	Reporter *testreporter.Reporter
+
+	// The fields below are set by SetupSuite.
+
+	Ctx          context.Context
+	Client       *client.Client
+	Network      string
+	Chains       []ibc.Chain
+	Relayer      ibc.Relayer
+	Interchain   *Interchain
+	ExecReporter *testreporter.RelayerExecReporter
+
+	// Relayer paths of the links, in order.
+	Paths []string
+
+	// Set by SetupTest: a funded user on each chain, in the order of Chains.
+	Users []*ibc.Wallet
+
+	cancel context.CancelFunc
+}
+
+// SetupSuite builds the chains, the relayer and the interchain.
+func (s *Suite) SetupSuite() {
+	t := s.T()
+	req := s.Require()
+	req.NotEmpty(s.ChainSpecs, "suite has no chains")
+
+	s.Ctx, s.cancel = context.WithCancel(context.Background())
+	if s.Reporter == nil {
+		s.Reporter = testreporter.NewNopReporter()
+	}
+	s.ExecReporter = s.Reporter.RelayerExecReporter(t)
+	s.Client, s.Network = DockerSetup(t)
+
+	var err error
+	s.Chains, err = NewBuiltinChainFactory(zaptest.NewLogger(t), s.ChainSpecs).Chains(t.Name())
+	req.NoError(err, "failed to get chains")
+
+	s.Interchain = NewInterchain()
+	for _, c := range s.Chains {
+		s.Interchain.AddChain(c)
+	}
+	if s.RelayerFactory != nil {
+		s.Relayer = s.RelayerFactory.Build(t, s.Client, s.Network)
+		s.Interchain.AddRelayer(s.Relayer, "relayer")
+
+		links := consecutiveLinks
+		if s.Links != nil {
+			links = s.Links
+		}
+		for _, link := range links(s.Chains, s.Relayer) {
+			s.Interchain.AddLink(link)
+			s.Paths = append(s.Paths, link.Path)
+		}
+	}
+
+	opts := s.BuildOptions
+	opts.TestName = t.Name()
+	opts.Client = s.Client
+	opts.NetworkID = s.Network
+	req.NoError(s.Interchain.Build(s.Ctx, s.ExecReporter, opts), "failed to build interchain")
+
+	if s.StartRelayer && s.Relayer != nil && len(s.Paths) > 0 {
+		req.NoError(s.Relayer.StartRelayer(s.Ctx, s.ExecReporter, s.Paths...), "failed to start relayer")
+	}
+}
+
+// TearDownSuite stops the relayer, if the suite started it, and closes the interchain.
+func (s *Suite) TearDownSuite() {
+	if s.StartRelayer && s.Relayer != nil && len(s.Paths) > 0 {
+		if err := s.Relayer.StopRelayer(s.Ctx, s.ExecReporter); err != nil {
+			s.T().Logf("failed to stop relayer: %v", err)
+		}
+	}
+	if s.Interchain != nil {
+		if err := s.Interchain.Close(); err != nil {
+			s.T().Logf("failed to close interchain: %v", err)
+		}
+	}
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// SetupTest funds a new user on each chain for the test, unless SkipUserFunding is set,
+// and waits for the funds to be available.
+func (s *Suite) SetupTest() {
+	s.Users = nil
+	if s.SkipUserFunding {
+		return
+	}
+
+	funds := s.UserFunds
+	if funds.IsNil() {
+		funds = defaultSuiteUserFunds
+	}
+	s.Users = GetAndFundTestUsers(s.T(), s.Ctx, "user", funds, s.Chains...)
+
+	heighters := make([]test.ChainHeighter, len(s.Chains))
+	for i, c := range s.Chains {
+		heighters[i] = c
+	}
+	s.Require().NoError(test.WaitForBlocks(s.Ctx, 2, heighters...), "failed to wait for user funds")
+}
+
+// consecutiveLinks links each consecutive pair of chains with the default transfer channel,
+// naming each path after its chains.
+func consecutiveLinks(chains []ibc.Chain, relayer ibc.Relayer) []InterchainLink {
+	var links []InterchainLink
+	for i := 1; i < len(chains); i++ {
+		c1, c2 := chains[i-1], chains[i]
+		links = append(links, InterchainLink{
+			Chain1:            c1,
+			Chain2:            c2,
+			Relayer:           relayer,
+			Path:              fmt.Sprintf("%s-%s", c1.Config().ChainID, c2.Config().ChainID),
+			CreateChannelOpts: ibc.DefaultChannelOpts(),
+		})
+	}
+	return links
+}
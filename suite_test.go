@@ -0,0 +1,34 @@
+package ibctest
+
+import (
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	relayermock "github.com/strangelove-ventures/ibctest/v6/relayer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsecutiveLinks(t *testing.T) {
+	t.Parallel()
+
+	chains := []ibc.Chain{
+		mock.NewChain(ibc.ChainConfig{ChainID: "a-1"}),
+		mock.NewChain(ibc.ChainConfig{ChainID: "b-1"}),
+		mock.NewChain(ibc.ChainConfig{ChainID: "c-1"}),
+	}
+	r := relayermock.NewRelayer()
+
+	links := consecutiveLinks(chains, r)
+	require.Len(t, links, 2)
+	require.Equal(t, "a-1-b-1", links[0].Path)
+	require.Equal(t, "b-1-c-1", links[1].Path)
+	for i, link := range links {
+		require.Same(t, chains[i], link.Chain1)
+		require.Same(t, chains[i+1], link.Chain2)
+		require.Equal(t, ibc.Relayer(r), link.Relayer)
+		require.Equal(t, ibc.DefaultChannelOpts(), link.CreateChannelOpts)
+	}
+
+	require.Empty(t, consecutiveLinks(chains[:1], r))
+}
@@ -0,0 +1,230 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+const (
+	defaultMaxKillDelay   = 5 * time.Second
+	defaultRecoveryBlocks = 3
+	defaultCrashAckBlocks = 20
+
+	// How often CrashValidator polls validator heights.
+	crashPollInterval = 500 * time.Millisecond
+)
+
+// CrashValidatorConfig describes the validator crash of CrashValidator.
+type CrashValidatorConfig struct {
+	// Chain whose validator is crashed. It must implement ibc.ValidatorCrasher.
+	Chain ibc.Chain
+
+	// Seed of the random choice of the validator and of the point in a block it is killed at.
+	// Errors include the seed, so that a failing crash can be reproduced.
+	Seed int64
+
+	// Optional. The validator is killed at a random point of up to MaxKillDelay after a new block.
+	// Defaults to 5 seconds.
+	MaxKillDelay time.Duration
+
+	// Optional. How long the validator stays down before it is restarted.
+	Downtime time.Duration
+
+	// Optional. Blocks the restarted validator must reach past the height of the other validators when it restarted.
+	// Defaults to 3.
+	RecoveryBlocks uint64
+
+	// Optional. Transfer sent once the validator recovered, whose acknowledgement a running relayer must relay,
+	// to check that the relayer continues after the crash.
+	Transfer *CrashTransfer
+}
+
+// CrashTransfer is a transfer sent by CrashValidator once the crashed validator recovered.
+type CrashTransfer struct {
+	// Chain the transfer is sent from, either the crashed chain or its counterparty.
+	Src ibc.Chain
+
+	// Channel on Src that the transfer is sent on, relayed by a running relayer.
+	ChannelID string
+
+	// Key name of the sender on Src.
+	SenderKeyName string
+
+	// Transfer to send; Address is the receiver on the counterparty.
+	Amount ibc.WalletAmount
+
+	// Optional. Blocks of Src to wait for the acknowledgement. Defaults to 20.
+	AckBlocks uint64
+}
+
+// CrashValidatorResult describes the crash of a validator that recovered.
+type CrashValidatorResult struct {
+	Seed int64
+
+	// Index of the crashed validator.
+	Validator int
+
+	// How long after a new block the validator was killed.
+	KillDelay time.Duration
+
+	// Height of the validator when it was killed, and once it recovered.
+	KilledAt, RecoveredAt uint64
+
+	// Acknowledgement of the Transfer, if any.
+	Ack *ibc.PacketAcknowledgement
+}
+
+// CrashValidator kills a random validator of the chain with SIGKILL at a random point during a block,
+// covering the recovery paths of the node's database from an unclean shutdown,
+// restarts it from the same volume, and checks that it rejoins consensus:
+// its height must reach RecoveryBlocks past the height of the other validators when it restarted.
+// If Transfer is set, CrashValidator then checks that a transfer is relayed and acknowledged successfully.
+func CrashValidator(ctx context.Context, cfg CrashValidatorConfig) (CrashValidatorResult, error) {
+	res := CrashValidatorResult{Seed: cfg.Seed}
+	vc, ok := cfg.Chain.(ibc.ValidatorCrasher)
+	if !ok {
+		return res, fmt.Errorf("chain %s cannot crash validators", cfg.Chain.Config().ChainID)
+	}
+	n := vc.CrashableValidators()
+	if n == 0 {
+		return res, fmt.Errorf("chain %s has no validators", cfg.Chain.Config().ChainID)
+	}
+
+	maxDelay := cfg.MaxKillDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxKillDelay
+	}
+	recoveryBlocks := cfg.RecoveryBlocks
+	if recoveryBlocks == 0 {
+		recoveryBlocks = defaultRecoveryBlocks
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	res.Validator = rng.Intn(n)
+	res.KillDelay = time.Duration(rng.Int63n(int64(maxDelay)))
+
+	if err := crashValidator(ctx, vc, &res, cfg.Downtime, recoveryBlocks); err != nil {
+		return res, fmt.Errorf("seed %d, validator %d: %w", cfg.Seed, res.Validator, err)
+	}
+
+	if cfg.Transfer != nil {
+		ack, err := cfg.Transfer.relay(ctx)
+		if err != nil {
+			return res, fmt.Errorf("seed %d, validator %d: transfer after recovery: %w", cfg.Seed, res.Validator, err)
+		}
+		res.Ack = &ack
+	}
+	return res, nil
+}
+
+// crashValidator kills res.Validator after res.KillDelay into a new block, restarts it after downtime,
+// and waits for it to reach recoveryBlocks past the other validators.
+func crashValidator(ctx context.Context, vc ibc.ValidatorCrasher, res *CrashValidatorResult, downtime time.Duration, recoveryBlocks uint64) error {
+	i := res.Validator
+	start, err := vc.ValidatorHeight(ctx, i)
+	if err != nil {
+		return err
+	}
+	if _, err := pollValidatorHeight(ctx, vc, i, start+1); err != nil {
+		return fmt.Errorf("waiting for a new block: %w", err)
+	}
+	if err := sleep(ctx, res.KillDelay); err != nil {
+		return err
+	}
+
+	if res.KilledAt, err = vc.ValidatorHeight(ctx, i); err != nil {
+		return err
+	}
+	if err := vc.KillValidator(ctx, i); err != nil {
+		return err
+	}
+	if err := sleep(ctx, downtime); err != nil {
+		return err
+	}
+	if err := vc.RestartValidator(ctx, i); err != nil {
+		return err
+	}
+
+	target := res.KilledAt
+	for j := 0; j < vc.CrashableValidators(); j++ {
+		if j == i {
+			continue
+		}
+		h, err := vc.ValidatorHeight(ctx, j)
+		if err != nil {
+			return fmt.Errorf("height of validator %d: %w", j, err)
+		}
+		if h > target {
+			target = h
+		}
+	}
+	if res.RecoveredAt, err = pollValidatorHeight(ctx, vc, i, target+recoveryBlocks); err != nil {
+		return fmt.Errorf("validator killed at height %d did not rejoin consensus: %w", res.KilledAt, err)
+	}
+	return nil
+}
+
+// pollValidatorHeight waits for validator i to reach height, and returns its height then.
+// Errors getting the height are retried, as the node may still be starting.
+func pollValidatorHeight(ctx context.Context, vc ibc.ValidatorCrasher, i int, height uint64) (uint64, error) {
+	var last uint64
+	var lastErr error
+	for {
+		h, err := vc.ValidatorHeight(ctx, i)
+		if err == nil && h >= height {
+			return h, nil
+		}
+		if err == nil {
+			last = h
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return last, fmt.Errorf("height %d not reached: %w (last error: %v)", height, ctx.Err(), lastErr)
+			}
+			return last, fmt.Errorf("height %d not reached, at %d: %w", height, last, ctx.Err())
+		case <-time.After(crashPollInterval):
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// relay sends the transfer and waits for its successful acknowledgement on Src.
+func (t CrashTransfer) relay(ctx context.Context) (ibc.PacketAcknowledgement, error) {
+	tx, err := t.Src.SendIBCTransfer(ctx, t.ChannelID, t.SenderKeyName, t.Amount, nil)
+	if err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("failed to send transfer: %w", err)
+	}
+	ackBlocks := t.AckBlocks
+	if ackBlocks == 0 {
+		ackBlocks = defaultCrashAckBlocks
+	}
+	ack, err := PollForAck(ctx, t.Src, tx.Height, tx.Height+ackBlocks, tx.Packet)
+	if err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("transfer was not acknowledged: %w", err)
+	}
+	ackErr, err := ack.ErrorResult()
+	if err != nil {
+		return ibc.PacketAcknowledgement{}, err
+	}
+	if ackErr != "" {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("transfer failed: %s", ackErr)
+	}
+	return ack, nil
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+// crashChain is a mock chain whose validators make a block each time a height is queried,
+// and whose killed validators do not respond until restarted.
+type crashChain struct {
+	*mock.Chain
+
+	mu        sync.Mutex
+	down      []bool
+	killed    []int
+	restarted []int
+}
+
+func newCrashChain(validators int) *crashChain {
+	return &crashChain{
+		Chain: mock.NewChain(ibc.ChainConfig{ChainID: "gaia-1"}),
+		down:  make([]bool, validators),
+	}
+}
+
+func (c *crashChain) CrashableValidators() int { return len(c.down) }
+
+func (c *crashChain) KillValidator(ctx context.Context, i int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.down[i] = true
+	c.killed = append(c.killed, i)
+	return nil
+}
+
+func (c *crashChain) RestartValidator(ctx context.Context, i int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.down[i] = false
+	c.restarted = append(c.restarted, i)
+	return nil
+}
+
+func (c *crashChain) ValidatorHeight(ctx context.Context, i int) (uint64, error) {
+	c.mu.Lock()
+	down := c.down[i]
+	c.mu.Unlock()
+	if down {
+		return 0, errors.New("connection refused")
+	}
+	return c.Height(ctx)
+}
+
+func TestCrashValidator(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cfg := CrashValidatorConfig{Seed: 42, MaxKillDelay: 10 * time.Millisecond, RecoveryBlocks: 1}
+	var validators []int
+	for run := 0; run < 2; run++ {
+		chain := newCrashChain(4)
+		cfg.Chain = chain
+		res, err := CrashValidator(ctx, cfg)
+		require.NoError(t, err)
+
+		require.EqualValues(t, 42, res.Seed)
+		require.Less(t, res.KillDelay, 10*time.Millisecond)
+		require.Equal(t, []int{res.Validator}, chain.killed)
+		require.Equal(t, []int{res.Validator}, chain.restarted)
+		require.Greater(t, res.RecoveredAt, res.KilledAt+1)
+		require.Nil(t, res.Ack)
+		validators = append(validators, res.Validator)
+	}
+	require.Equal(t, validators[0], validators[1], "validator not reproducible from seed")
+}
+
+func TestCrashValidator_NotCrasher(t *testing.T) {
+	t.Parallel()
+
+	_, err := CrashValidator(context.Background(), CrashValidatorConfig{Chain: mock.NewChain(ibc.ChainConfig{ChainID: "gaia-1"})})
+	require.EqualError(t, err, "chain gaia-1 cannot crash validators")
+}
+
+func TestCrashValidator_NoRecovery(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	chain := &stuckChain{newCrashChain(1)}
+	_, err := CrashValidator(ctx, CrashValidatorConfig{Chain: chain, Seed: 7, MaxKillDelay: time.Millisecond})
+	require.ErrorContains(t, err, "seed 7, validator 0: validator killed at height")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// stuckChain is a crashChain whose validators do not respond after a restart.
+type stuckChain struct {
+	*crashChain
+}
+
+func (c *stuckChain) RestartValidator(ctx context.Context, i int) error { return nil }
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"context"
+	"fmt"
+
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// DenomTraceQuerier queries the denom traces of a chain's IBC vouchers.
+// *cosmos.CosmosChain satisfies DenomTraceQuerier.
+type DenomTraceQuerier interface {
+	QueryDenomTrace(ctx context.Context, ibcDenom string) (transfertypes.DenomTrace, error)
+}
+
+// DenomMetadataQuerier queries the bank metadata of a chain's denoms.
+// *cosmos.CosmosChain satisfies DenomMetadataQuerier.
+type DenomMetadataQuerier interface {
+	QueryDenomMetadata(ctx context.Context, denom string) (banktypes.Metadata, error)
+}
+
+// VoucherDenom returns the denom and full denom trace that the counterparty of channel,
+// as returned by GetChannels on the sending chain, receives for a transfer of denomTrace,
+// e.g. "uatom", or "transfer/channel-0/uatom" for an ibc/ denom of the sending chain.
+// The denom is an ibc/ denom, unless the transfer returns a voucher to its source chain.
+func VoucherDenom(channel ibc.ChannelOutput, denomTrace string) (denom, fullTrace string) {
+	fullTrace = receivedDenomTrace(channel, denomTrace)
+	return transfertypes.ParseDenomTrace(fullTrace).IBCDenom(), fullTrace
+}
+
+// VerifyVoucherDenom returns the denom that chain, the counterparty of channel, received for transfers of denomTrace,
+// see VoucherDenom, and returns an error unless chain resolves the denom to the expected denom trace.
+// Call it once a transfer has been received, as chains only store the traces of received vouchers.
+func VerifyVoucherDenom(ctx context.Context, chain DenomTraceQuerier, channel ibc.ChannelOutput, denomTrace string) (string, error) {
+	denom, fullTrace := VoucherDenom(channel, denomTrace)
+	want := transfertypes.ParseDenomTrace(fullTrace)
+	if want.Path != "" {
+		got, err := chain.QueryDenomTrace(ctx, denom)
+		if err != nil {
+			return "", err
+		}
+		if got.GetFullDenomPath() != want.GetFullDenomPath() {
+			return "", fmt.Errorf("denom trace of %s is %s, want %s", denom, got.GetFullDenomPath(), want.GetFullDenomPath())
+		}
+	}
+	return denom, nil
+}
+
+// VerifyDisplayDenom returns an error unless the bank metadata of denom on chain displays it as display,
+// with exponent decimals, as registered e.g. by cosmos.ModifyGenesisDenomMetadata.
+func VerifyDisplayDenom(ctx context.Context, chain DenomMetadataQuerier, denom, display string, exponent uint32) error {
+	m, err := chain.QueryDenomMetadata(ctx, denom)
+	if err != nil {
+		return err
+	}
+	if m.Display != display {
+		return fmt.Errorf("display denom of %s is %q, want %q", denom, m.Display, display)
+	}
+	for _, u := range m.DenomUnits {
+		if u.Denom != display {
+			continue
+		}
+		if u.Exponent != exponent {
+			return fmt.Errorf("exponent of display denom %s of %s is %d, want %d", display, denom, u.Exponent, exponent)
+		}
+		return nil
+	}
+	return fmt.Errorf("metadata of %s has no unit for display denom %s", denom, display)
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+// denomChain resolves the denom traces and metadata it holds.
+type denomChain struct {
+	traces   map[string]transfertypes.DenomTrace
+	metadata map[string]banktypes.Metadata
+}
+
+func (c denomChain) QueryDenomTrace(ctx context.Context, ibcDenom string) (transfertypes.DenomTrace, error) {
+	trace, ok := c.traces[ibcDenom]
+	if !ok {
+		return transfertypes.DenomTrace{}, fmt.Errorf("no denom trace for %s", ibcDenom)
+	}
+	return trace, nil
+}
+
+func (c denomChain) QueryDenomMetadata(ctx context.Context, denom string) (banktypes.Metadata, error) {
+	m, ok := c.metadata[denom]
+	if !ok {
+		return banktypes.Metadata{}, fmt.Errorf("no metadata for %s", denom)
+	}
+	return m, nil
+}
+
+func TestVerifyVoucherDenom(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	channel := ibc.ChannelOutput{
+		PortID: "transfer", ChannelID: "channel-0",
+		Counterparty: ibc.ChannelCounterparty{PortID: "transfer", ChannelID: "channel-1"},
+	}
+	trace := transfertypes.ParseDenomTrace("transfer/channel-1/uatom")
+	voucher := trace.IBCDenom()
+
+	denom, fullTrace := VoucherDenom(channel, "uatom")
+	require.Equal(t, voucher, denom)
+	require.Equal(t, "transfer/channel-1/uatom", fullTrace)
+
+	chain := denomChain{traces: map[string]transfertypes.DenomTrace{voucher: trace}}
+	denom, err := VerifyVoucherDenom(ctx, chain, channel, "uatom")
+	require.NoError(t, err)
+	require.Equal(t, voucher, denom)
+
+	chain.traces[voucher] = transfertypes.ParseDenomTrace("transfer/channel-7/uatom")
+	_, err = VerifyVoucherDenom(ctx, chain, channel, "uatom")
+	require.ErrorContains(t, err, "is transfer/channel-7/uatom, want transfer/channel-1/uatom")
+
+	// A voucher returning to its source chain is received as the native denom.
+	denom, err = VerifyVoucherDenom(ctx, denomChain{}, channel, "transfer/channel-0/uosmo")
+	require.NoError(t, err)
+	require.Equal(t, "uosmo", denom)
+}
+
+func TestVerifyDisplayDenom(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	chain := denomChain{metadata: map[string]banktypes.Metadata{
+		"uatom": {
+			Base:       "uatom",
+			Display:    "atom",
+			DenomUnits: []*banktypes.DenomUnit{{Denom: "uatom"}, {Denom: "atom", Exponent: 6}},
+		},
+	}}
+
+	require.NoError(t, VerifyDisplayDenom(ctx, chain, "uatom", "atom", 6))
+	require.EqualError(t, VerifyDisplayDenom(ctx, chain, "uatom", "atom", 18), "exponent of display denom atom of uatom is 6, want 18")
+	require.EqualError(t, VerifyDisplayDenom(ctx, chain, "uatom", "matom", 3), `display denom of uatom is "atom", want "matom"`)
+	require.ErrorContains(t, VerifyDisplayDenom(ctx, chain, "uosmo", "osmo", 6), "no metadata for uosmo")
+}
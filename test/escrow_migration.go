@@ -0,0 +1,205 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cosmossdk.io/math"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// defaultMigrationTransfers is how many transfers VerifyEscrowMigration sends before and after the upgrade.
+const defaultMigrationTransfers = 3
+
+// EscrowMigrationConfig describes the link that VerifyEscrowMigration transfers across while a chain is upgraded.
+type EscrowMigrationConfig struct {
+	Relayer  ibc.Relayer
+	Reporter ibc.RelayerExecReporter
+
+	// Name of the relayer path between Src and Dst.
+	PathName string
+
+	// Src escrows the transfers of its denom, and Dst holds the vouchers.
+	Src, Dst ibc.Chain
+
+	// Transfer channel from Src to Dst, as returned by GetChannels on Src.
+	SrcChannel ibc.ChannelOutput
+
+	// Key name and address of the sender on Src.
+	SenderKeyName, SenderAddress string
+
+	// Key name and address of the receiver of the vouchers on Dst.
+	// If ReceiverKeyName is set, the receiver returns vouchers to Src after the upgrade,
+	// so that the upgraded chain also releases escrowed funds or burns vouchers.
+	ReceiverKeyName, ReceiverAddress string
+
+	// Amount of Src's denom sent by each transfer.
+	Amount math.Int
+
+	// Optional. Number of transfers sent before and after the upgrade. Defaults to 3.
+	Transfers int
+
+	// Upgrades Src or Dst, e.g. to a new major version of ibc-go, such as with cosmos.CosmosChain.Upgrade.
+	// The relayer must keep relaying the path after the upgrade.
+	Upgrade func(ctx context.Context) error
+
+	// Optional. Address of the escrow account of SrcChannel on Src.
+	// Defaults to the ibc-go escrow address for cosmos chains; escrow is not checked for other chains unless set.
+	EscrowAddress string
+}
+
+// EscrowMigrationResult is the result of a successful VerifyEscrowMigration.
+type EscrowMigrationResult struct {
+	// Voucher denom of the transfers on Dst.
+	VoucherDenom string
+
+	// Escrow balance on Src, and voucher balance of the receiver on Dst, before and after the upgrade,
+	// and after the transfers following it.
+	EscrowBeforeUpgrade, EscrowAfterUpgrade, EscrowAfter       math.Int
+	VouchersBeforeUpgrade, VouchersAfterUpgrade, VouchersAfter math.Int
+}
+
+// VerifyEscrowMigration checks that the escrow of ICS-20 transfers and the vouchers backed by it survive the upgrade of a chain,
+// such as to a new major version of ibc-go that migrates the transfer module's state.
+// It sends transfers from Src to Dst, upgrades a chain through Upgrade, and sends more transfers,
+// flushing each transfer and its acknowledgement through the relayer, and checks these invariants:
+//   - every transfer is acknowledged with a success, before and after the upgrade;
+//   - the upgrade changes neither the escrow balance nor the receiver's voucher balance;
+//   - the escrow balance grows by exactly the amounts transferred, and the voucher balance along with it;
+//   - vouchers returned to Src after the upgrade are released from escrow;
+//   - Dst still resolves the voucher denom to its trace after the upgrade, if it is a DenomTraceQuerier.
+func VerifyEscrowMigration(ctx context.Context, cfg EscrowMigrationConfig) (EscrowMigrationResult, error) {
+	var result EscrowMigrationResult
+	if cfg.Upgrade == nil {
+		return result, errors.New("no upgrade")
+	}
+	if cfg.Amount.IsNil() || !cfg.Amount.IsPositive() {
+		return result, fmt.Errorf("invalid transfer amount %v", cfg.Amount)
+	}
+	if cfg.Transfers < 0 {
+		return result, fmt.Errorf("invalid number of transfers %d: must not be negative", cfg.Transfers)
+	}
+	n := cfg.Transfers
+	if n == 0 {
+		n = defaultMigrationTransfers
+	}
+
+	escrow, err := escrowAddress(cfg.Src, cfg.SrcChannel, cfg.EscrowAddress)
+	if err != nil {
+		return result, err
+	}
+	denom := cfg.Src.Config().Denom
+	result.VoucherDenom, _ = VoucherDenom(cfg.SrcChannel, denom)
+
+	escrowStart, vouchersStart, err := cfg.balances(ctx, escrow, result.VoucherDenom)
+	if err != nil {
+		return result, err
+	}
+	if err := cfg.sendTransfers(ctx, n, "before upgrade"); err != nil {
+		return result, err
+	}
+	total := cfg.Amount.MulRaw(int64(n))
+	if result.EscrowBeforeUpgrade, result.VouchersBeforeUpgrade, err = cfg.checkBalances(ctx, escrow, result.VoucherDenom, escrowStart.Add(total), vouchersStart.Add(total)); err != nil {
+		return result, fmt.Errorf("before upgrade: %w", err)
+	}
+
+	if err := cfg.Upgrade(ctx); err != nil {
+		return result, fmt.Errorf("failed to upgrade: %w", err)
+	}
+
+	if result.EscrowAfterUpgrade, result.VouchersAfterUpgrade, err = cfg.checkBalances(ctx, escrow, result.VoucherDenom, result.EscrowBeforeUpgrade, result.VouchersBeforeUpgrade); err != nil {
+		return result, fmt.Errorf("after upgrade: %w", err)
+	}
+	if q, ok := cfg.Dst.(DenomTraceQuerier); ok {
+		if _, err := VerifyVoucherDenom(ctx, q, cfg.SrcChannel, denom); err != nil {
+			return result, fmt.Errorf("after upgrade: %w", err)
+		}
+	}
+
+	if err := cfg.sendTransfers(ctx, n, "after upgrade"); err != nil {
+		return result, err
+	}
+	wantEscrow, wantVouchers := result.EscrowAfterUpgrade.Add(total), result.VouchersAfterUpgrade.Add(total)
+	if cfg.ReceiverKeyName != "" {
+		// Return the vouchers of one transfer.
+		if err := cfg.returnVouchers(ctx, result.VoucherDenom); err != nil {
+			return result, err
+		}
+		wantEscrow, wantVouchers = wantEscrow.Sub(cfg.Amount), wantVouchers.Sub(cfg.Amount)
+	}
+	if result.EscrowAfter, result.VouchersAfter, err = cfg.checkBalances(ctx, escrow, result.VoucherDenom, wantEscrow, wantVouchers); err != nil {
+		return result, fmt.Errorf("after transfers following upgrade: %w", err)
+	}
+	return result, nil
+}
+
+// sendTransfers sends n transfers of Amount from Src to the receiver on Dst, relaying each one.
+func (cfg EscrowMigrationConfig) sendTransfers(ctx context.Context, n int, phase string) error {
+	amount := ibc.WalletAmount{Address: cfg.ReceiverAddress, Denom: cfg.Src.Config().Denom, Amount: cfg.Amount}
+	for i := 0; i < n; i++ {
+		tx, err := cfg.Src.SendIBCTransfer(ctx, cfg.SrcChannel.ChannelID, cfg.SenderKeyName, amount, nil)
+		if err != nil {
+			return fmt.Errorf("%s, transfer %d: failed to send transfer: %w", phase, i, err)
+		}
+		if err := cfg.checkAck(ctx, cfg.SrcChannel.ChannelID, cfg.Src, cfg.Dst, tx); err != nil {
+			return fmt.Errorf("%s, transfer %d: %w", phase, i, err)
+		}
+	}
+	return nil
+}
+
+// returnVouchers sends Amount of voucherDenom from the receiver on Dst back to the sender on Src.
+func (cfg EscrowMigrationConfig) returnVouchers(ctx context.Context, voucherDenom string) error {
+	amount := ibc.WalletAmount{Address: cfg.SenderAddress, Denom: voucherDenom, Amount: cfg.Amount}
+	channelID := cfg.SrcChannel.Counterparty.ChannelID
+	tx, err := cfg.Dst.SendIBCTransfer(ctx, channelID, cfg.ReceiverKeyName, amount, nil)
+	if err != nil {
+		return fmt.Errorf("failed to return vouchers: %w", err)
+	}
+	if err := cfg.checkAck(ctx, channelID, cfg.Dst, cfg.Src, tx); err != nil {
+		return fmt.Errorf("returning vouchers: %w", err)
+	}
+	return nil
+}
+
+// checkAck relays tx, sent on channelID of src, and checks that it is acknowledged with a success.
+func (cfg EscrowMigrationConfig) checkAck(ctx context.Context, channelID string, src, dst ibc.Chain, tx ibc.Tx) error {
+	if err := tx.Validate(); err != nil {
+		return fmt.Errorf("invalid transfer tx: %w", err)
+	}
+	ack, err := flushTransfer(ctx, cfg.Relayer, cfg.Reporter, cfg.PathName, channelID, src, dst, tx)
+	if err != nil {
+		return err
+	}
+	return checkSuccessAck(ack)
+}
+
+// balances returns the escrow balance of Src's denom, or zero if escrow is empty,
+// and the receiver's balance of voucherDenom.
+func (cfg EscrowMigrationConfig) balances(ctx context.Context, escrow, voucherDenom string) (escrowed, vouchers math.Int, err error) {
+	escrowed = math.ZeroInt()
+	if escrow != "" {
+		if escrowed, err = cfg.Src.GetBalance(ctx, escrow, cfg.Src.Config().Denom); err != nil {
+			return escrowed, vouchers, fmt.Errorf("failed to get escrow balance: %w", err)
+		}
+	}
+	if vouchers, err = cfg.Dst.GetBalance(ctx, cfg.ReceiverAddress, voucherDenom); err != nil {
+		return escrowed, vouchers, fmt.Errorf("failed to get voucher balance: %w", err)
+	}
+	return escrowed, vouchers, nil
+}
+
+// checkBalances returns the balances, and an error unless they are wantEscrow, if escrow is set, and wantVouchers.
+func (cfg EscrowMigrationConfig) checkBalances(ctx context.Context, escrow, voucherDenom string, wantEscrow, wantVouchers math.Int) (escrowed, vouchers math.Int, err error) {
+	if escrowed, vouchers, err = cfg.balances(ctx, escrow, voucherDenom); err != nil {
+		return escrowed, vouchers, err
+	}
+	if escrow != "" && !escrowed.Equal(wantEscrow) {
+		return escrowed, vouchers, fmt.Errorf("escrow balance %s%s, want %s%s", escrowed, cfg.Src.Config().Denom, wantEscrow, cfg.Src.Config().Denom)
+	}
+	if !vouchers.Equal(wantVouchers) {
+		return escrowed, vouchers, fmt.Errorf("voucher balance %s%s, want %s%s", vouchers, voucherDenom, wantVouchers, voucherDenom)
+	}
+	return escrowed, vouchers, nil
+}
@@ -0,0 +1,212 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	relayermock "github.com/strangelove-ventures/ibctest/v6/relayer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// ics20Chain is a mock chain that keeps the transfers it sends, for an ics20Relayer to deliver.
+type ics20Chain struct {
+	*mock.Chain
+
+	pending []sentTransfer
+}
+
+type sentTransfer struct {
+	tx     ibc.Tx
+	amount ibc.WalletAmount
+}
+
+func (c *ics20Chain) SendIBCTransfer(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (ibc.Tx, error) {
+	tx, err := c.Chain.SendIBCTransfer(ctx, channelID, keyName, amount, timeout)
+	if err == nil {
+		c.pending = append(c.pending, sentTransfer{tx: tx, amount: amount})
+	}
+	return tx, err
+}
+
+func (c *ics20Chain) credit(address, denom string, amount math.Int) {
+	bal, _ := c.GetBalance(context.Background(), address, denom)
+	c.SetBalance(address, denom, bal.Add(amount))
+}
+
+// ics20Relayer is a mock relayer that delivers the ICS-20 transfers of its chains when flushing packets:
+// transfers from src escrow src's denom and mint vouchers on dst,
+// and vouchers returned from dst, already burned when sent, release src's escrow.
+// Each delivered transfer is acknowledged with a success on its sending chain.
+type ics20Relayer struct {
+	*relayermock.Relayer
+
+	src, dst *ics20Chain
+	channel  ibc.ChannelOutput
+	escrow   string
+}
+
+func (r *ics20Relayer) FlushPackets(ctx context.Context, rep ibc.RelayerExecReporter, pathName, channelID string) error {
+	if err := r.Relayer.FlushPackets(ctx, rep, pathName, channelID); err != nil {
+		return err
+	}
+	denom := r.src.Config().Denom
+	voucherDenom, _ := VoucherDenom(r.channel, denom)
+
+	sender := r.src
+	if channelID == r.channel.Counterparty.ChannelID {
+		sender = r.dst
+	}
+	for _, s := range sender.pending {
+		if sender == r.src {
+			r.src.credit(r.escrow, denom, s.amount.Amount)
+			r.dst.credit(s.amount.Address, voucherDenom, s.amount.Amount)
+		} else {
+			r.src.credit(r.escrow, denom, s.amount.Amount.Neg())
+			r.src.credit(s.amount.Address, denom, s.amount.Amount)
+		}
+		sender.AddAcknowledgement(s.tx.Height, ibc.PacketAcknowledgement{Packet: s.tx.Packet, Acknowledgement: []byte(`{"result":"AQ=="}`)})
+	}
+	sender.pending = nil
+	return nil
+}
+
+// newEscrowMigrationConfig returns a config transferring 100uatom at a time between two mock chains
+// through an ics20Relayer, with an upgrade that does nothing, and the sender's starting balance.
+func newEscrowMigrationConfig(t *testing.T) EscrowMigrationConfig {
+	ctx := context.Background()
+	src := &ics20Chain{Chain: mock.NewChain(ibc.ChainConfig{Type: "cosmos", ChainID: "gaia-1", Bech32Prefix: "cosmos", Denom: "uatom"})}
+	dst := &ics20Chain{Chain: mock.NewChain(ibc.ChainConfig{Type: "cosmos", ChainID: "osmosis-1", Bech32Prefix: "osmo", Denom: "uosmo"})}
+
+	address := func(c *ics20Chain, keyName string) string {
+		require.NoError(t, c.CreateKey(ctx, keyName))
+		addr, err := c.GetAddress(ctx, keyName)
+		require.NoError(t, err)
+		bech32, err := sdk.Bech32ifyAddressBytes(c.Config().Bech32Prefix, addr)
+		require.NoError(t, err)
+		return bech32
+	}
+	sender, receiver := address(src, "sender"), address(dst, "receiver")
+	src.SetBalance(sender, "uatom", math.NewInt(10_000))
+
+	channel := ibc.ChannelOutput{
+		PortID:       "transfer",
+		ChannelID:    "channel-0",
+		Counterparty: ibc.ChannelCounterparty{PortID: "transfer", ChannelID: "channel-1"},
+	}
+	escrow, err := escrowAddress(src, channel, "")
+	require.NoError(t, err)
+
+	return EscrowMigrationConfig{
+		Relayer:         &ics20Relayer{Relayer: relayermock.NewRelayer(), src: src, dst: dst, channel: channel, escrow: escrow},
+		PathName:        "p",
+		Src:             src,
+		Dst:             dst,
+		SrcChannel:      channel,
+		SenderKeyName:   "sender",
+		SenderAddress:   sender,
+		ReceiverKeyName: "receiver",
+		ReceiverAddress: receiver,
+		Amount:          math.NewInt(100),
+		Upgrade:         func(context.Context) error { return nil },
+	}
+}
+
+func TestVerifyEscrowMigration(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("invariants hold", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := newEscrowMigrationConfig(t)
+		res, err := VerifyEscrowMigration(ctx, cfg)
+		require.NoError(t, err)
+
+		wantDenom, _ := VoucherDenom(cfg.SrcChannel, "uatom")
+		require.Equal(t, wantDenom, res.VoucherDenom)
+		for _, got := range []math.Int{res.EscrowBeforeUpgrade, res.EscrowAfterUpgrade, res.VouchersBeforeUpgrade, res.VouchersAfterUpgrade} {
+			require.Equal(t, "300", got.String())
+		}
+		// 3 more transfers, less the returned vouchers of one.
+		require.Equal(t, "500", res.EscrowAfter.String())
+		require.Equal(t, "500", res.VouchersAfter.String())
+
+		bal, err := cfg.Src.GetBalance(ctx, cfg.SenderAddress, "uatom")
+		require.NoError(t, err)
+		require.Equal(t, "9500", bal.String())
+	})
+
+	t.Run("upgrade loses escrow", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := newEscrowMigrationConfig(t)
+		r := cfg.Relayer.(*ics20Relayer)
+		cfg.Upgrade = func(context.Context) error {
+			r.src.SetBalance(r.escrow, "uatom", math.ZeroInt())
+			return nil
+		}
+		_, err := VerifyEscrowMigration(ctx, cfg)
+		require.EqualError(t, err, "after upgrade: escrow balance 0uatom, want 300uatom")
+	})
+
+	t.Run("upgrade mints vouchers", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := newEscrowMigrationConfig(t)
+		r := cfg.Relayer.(*ics20Relayer)
+		cfg.Upgrade = func(context.Context) error {
+			voucherDenom, _ := VoucherDenom(cfg.SrcChannel, "uatom")
+			r.dst.credit(cfg.ReceiverAddress, voucherDenom, math.NewInt(1))
+			return nil
+		}
+		_, err := VerifyEscrowMigration(ctx, cfg)
+		require.ErrorContains(t, err, "after upgrade: voucher balance 301")
+	})
+
+	t.Run("upgrade fails", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := newEscrowMigrationConfig(t)
+		cfg.Upgrade = func(context.Context) error { return errors.New("chain halted") }
+		_, err := VerifyEscrowMigration(ctx, cfg)
+		require.EqualError(t, err, "failed to upgrade: chain halted")
+	})
+
+	t.Run("relayer stops relaying after upgrade", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := newEscrowMigrationConfig(t)
+		r := cfg.Relayer.(*ics20Relayer)
+		cfg.Upgrade = func(context.Context) error {
+			r.FailOn("FlushPackets", errors.New("client expired"))
+			return nil
+		}
+		_, err := VerifyEscrowMigration(ctx, cfg)
+		require.EqualError(t, err, "after upgrade, transfer 0: failed to flush packets: client expired")
+	})
+}
+
+func TestVerifyEscrowMigration_Invalid(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	upgrade := func(context.Context) error { return nil }
+
+	_, err := VerifyEscrowMigration(ctx, EscrowMigrationConfig{Amount: math.NewInt(1)})
+	require.EqualError(t, err, "no upgrade")
+
+	_, err = VerifyEscrowMigration(ctx, EscrowMigrationConfig{Upgrade: upgrade})
+	require.ErrorContains(t, err, "invalid transfer amount")
+
+	_, err = VerifyEscrowMigration(ctx, EscrowMigrationConfig{Upgrade: upgrade, Amount: math.ZeroInt()})
+	require.EqualError(t, err, "invalid transfer amount 0")
+
+	_, err = VerifyEscrowMigration(ctx, EscrowMigrationConfig{Upgrade: upgrade, Amount: math.NewInt(1), Transfers: -1})
+	require.EqualError(t, err, "invalid number of transfers -1: must not be negative")
+}
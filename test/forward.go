@@ -0,0 +1,198 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cosmossdk.io/math"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// defaultForwardBlocks is how many blocks of Dst ForwardTransfer waits for the forwarded transfer to arrive.
+const defaultForwardBlocks = 30
+
+// ForwardTransferConfig describes a transfer from Src to Dst, forwarded through Hop,
+// e.g. cosmos to parachain to cosmos, or parachain to cosmos to parachain.
+type ForwardTransferConfig struct {
+	Src, Hop, Dst ibc.Chain
+
+	// Transfer channel from Src to Hop, as returned by GetChannels on Src.
+	SrcChannel ibc.ChannelOutput
+
+	// Transfer channel from Hop to Dst, as returned by GetChannels on Hop.
+	HopChannel ibc.ChannelOutput
+
+	// Key name and address of the sender on Src.
+	SenderKeyName, SenderAddress string
+
+	// Address of the intermediate receiver on Hop, required by the forwarding middleware.
+	HopReceiver string
+
+	// Transfer to send; Address is the receiver on Dst.
+	Amount ibc.WalletAmount
+
+	// Optional. Full denom trace of Amount.Denom on Src, e.g. "transfer/channel-0/uatom"
+	// when Amount.Denom is the corresponding ibc/ denom. Defaults to Amount.Denom.
+	DenomTrace string
+
+	// Optional. Registers the denom that Hop or Dst will receive, before the transfer is sent,
+	// for chains such as parachains that only accept transfers of registered assets.
+	// denomTrace is the full denom trace received by chain, e.g. "transfer/channel-1/uatom".
+	RegisterAsset func(ctx context.Context, chain ibc.Chain, denomTrace string) error
+
+	// Optional timeout of the transfer from Src.
+	Timeout *ibc.IBCTimeout
+
+	// Optional. How many blocks of Dst to wait for the forwarded transfer to arrive. Defaults to 30 if not positive.
+	MaxBlocks int
+}
+
+// ForwardTransferResult is the result of a successful ForwardTransfer.
+type ForwardTransferResult struct {
+	// Transfer sent on Src.
+	Tx ibc.Tx
+
+	// Denom traces received by Hop and Dst.
+	HopDenomTrace, DstDenomTrace string
+
+	// Balance of the receiver on Dst after the transfer arrived.
+	DstBalance math.Int
+}
+
+// forwardMemo is the memo instructing the forwarding middleware of the receiving chain
+// to forward the transfer on Port and Channel to Receiver.
+type forwardMemo struct {
+	Forward struct {
+		Receiver string `json:"receiver"`
+		Port     string `json:"port"`
+		Channel  string `json:"channel"`
+	} `json:"forward"`
+}
+
+// ForwardTransfer sends a transfer from Src with a memo instructing Hop to forward it to Dst,
+// and waits for it to arrive.
+//
+// Before sending, the denoms received by Hop and Dst are registered through RegisterAsset, if set.
+// The relayer must be relaying both channels.
+// Once the transfer arrives, ForwardTransfer verifies the final balances:
+// the sender paid the amount, aside from gas fees paid in the transferred denom,
+// the intermediate receiver on Hop kept nothing, and the receiver on Dst received the amount.
+func ForwardTransfer(ctx context.Context, cfg ForwardTransferConfig) (ForwardTransferResult, error) {
+	var result ForwardTransferResult
+
+	memoSender, ok := cfg.Src.(ibc.MemoIBCTransferer)
+	if !ok || !ibc.SupportsFeature(cfg.Src, ibc.FeatureMemo) {
+		return result, fmt.Errorf("chain %s does not support transfer memos", cfg.Src.Config().ChainID)
+	}
+	if cfg.HopReceiver == "" {
+		return result, fmt.Errorf("missing intermediate receiver on %s", cfg.Hop.Config().ChainID)
+	}
+
+	trace := cfg.DenomTrace
+	if trace == "" {
+		trace = cfg.Amount.Denom
+	}
+	result.HopDenomTrace = receivedDenomTrace(cfg.SrcChannel, trace)
+	result.DstDenomTrace = receivedDenomTrace(cfg.HopChannel, result.HopDenomTrace)
+	hopDenom := transfertypes.ParseDenomTrace(result.HopDenomTrace).IBCDenom()
+	dstDenom := transfertypes.ParseDenomTrace(result.DstDenomTrace).IBCDenom()
+
+	if cfg.RegisterAsset != nil {
+		if err := cfg.RegisterAsset(ctx, cfg.Hop, result.HopDenomTrace); err != nil {
+			return result, fmt.Errorf("failed to register %s on %s: %w", result.HopDenomTrace, cfg.Hop.Config().ChainID, err)
+		}
+		if err := cfg.RegisterAsset(ctx, cfg.Dst, result.DstDenomTrace); err != nil {
+			return result, fmt.Errorf("failed to register %s on %s: %w", result.DstDenomTrace, cfg.Dst.Config().ChainID, err)
+		}
+	}
+
+	senderBefore, err := cfg.Src.GetBalance(ctx, cfg.SenderAddress, cfg.Amount.Denom)
+	if err != nil {
+		return result, fmt.Errorf("failed to get sender balance before transfer: %w", err)
+	}
+	hopBefore, err := cfg.Hop.GetBalance(ctx, cfg.HopReceiver, hopDenom)
+	if err != nil {
+		return result, fmt.Errorf("failed to get intermediate receiver balance before transfer: %w", err)
+	}
+	dstBefore, err := cfg.Dst.GetBalance(ctx, cfg.Amount.Address, dstDenom)
+	if err != nil {
+		return result, fmt.Errorf("failed to get receiver balance before transfer: %w", err)
+	}
+
+	var memo forwardMemo
+	memo.Forward.Receiver = cfg.Amount.Address
+	memo.Forward.Port = cfg.HopChannel.PortID
+	memo.Forward.Channel = cfg.HopChannel.ChannelID
+	memoBz, err := json.Marshal(memo)
+	if err != nil {
+		return result, err
+	}
+
+	amount := cfg.Amount
+	amount.Address = cfg.HopReceiver
+	result.Tx, err = memoSender.SendIBCTransferWithMemo(ctx, cfg.SrcChannel.ChannelID, cfg.SenderKeyName, amount, cfg.Timeout, string(memoBz))
+	if err != nil {
+		return result, fmt.Errorf("failed to send transfer: %w", err)
+	}
+	if err := result.Tx.Validate(); err != nil {
+		return result, fmt.Errorf("invalid transfer tx: %w", err)
+	}
+
+	maxBlocks := cfg.MaxBlocks
+	if maxBlocks <= 0 {
+		maxBlocks = defaultForwardBlocks
+	}
+	want := dstBefore.Add(cfg.Amount.Amount)
+	for i := 0; ; i++ {
+		result.DstBalance, err = cfg.Dst.GetBalance(ctx, cfg.Amount.Address, dstDenom)
+		if err != nil {
+			return result, fmt.Errorf("failed to get receiver balance: %w", err)
+		}
+		if result.DstBalance.GTE(want) {
+			break
+		}
+		if i >= maxBlocks {
+			return result, fmt.Errorf("receiver balance %s%s did not reach %s%s within %d blocks", result.DstBalance, dstDenom, want, dstDenom, maxBlocks)
+		}
+		if err := WaitForBlocks(ctx, 1, cfg.Dst); err != nil {
+			return result, err
+		}
+	}
+	if !result.DstBalance.Equal(want) {
+		return result, fmt.Errorf("receiver balance %s%s, want %s%s", result.DstBalance, dstDenom, want, dstDenom)
+	}
+
+	hopAfter, err := cfg.Hop.GetBalance(ctx, cfg.HopReceiver, hopDenom)
+	if err != nil {
+		return result, fmt.Errorf("failed to get intermediate receiver balance after transfer: %w", err)
+	}
+	if !hopAfter.Equal(hopBefore) {
+		return result, fmt.Errorf("intermediate receiver balance changed from %s%s to %s%s", hopBefore, hopDenom, hopAfter, hopDenom)
+	}
+
+	senderAfter, err := cfg.Src.GetBalance(ctx, cfg.SenderAddress, cfg.Amount.Denom)
+	if err != nil {
+		return result, fmt.Errorf("failed to get sender balance after transfer: %w", err)
+	}
+	wantSender := senderBefore.Sub(cfg.Amount.Amount)
+	if cfg.Amount.Denom == cfg.Src.Config().Denom {
+		wantSender = wantSender.Sub(cfg.Src.GetGasFeesInNativeDenom(result.Tx.GasSpent))
+	}
+	if !senderAfter.Equal(wantSender) {
+		return result, fmt.Errorf("sender balance %s%s, want %s%s", senderAfter, cfg.Amount.Denom, wantSender, cfg.Amount.Denom)
+	}
+
+	return result, nil
+}
+
+// receivedDenomTrace returns the denom trace received by the counterparty of channel,
+// for a transfer of the denom with the given trace sent on channel.
+// The denom is unwound if it returns to the chain it came from, or prefixed with the counterparty's port and channel otherwise.
+func receivedDenomTrace(channel ibc.ChannelOutput, trace string) string {
+	if transfertypes.ReceiverChainIsSource(channel.PortID, channel.ChannelID, trace) {
+		return trace[len(transfertypes.GetDenomPrefix(channel.PortID, channel.ChannelID)):]
+	}
+	return transfertypes.GetPrefixedDenom(channel.Counterparty.PortID, channel.Counterparty.ChannelID, trace)
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+// forwardChain is a mock chain whose transfers sent with a forward memo
+// are credited directly to the forward receiver on dst, in dstDenom.
+type forwardChain struct {
+	*mock.Chain
+
+	dst        *forwardChain
+	dstDenom   string
+	registered []string
+}
+
+func newForwardChain(chainID string) *forwardChain {
+	return &forwardChain{Chain: mock.NewChain(ibc.ChainConfig{ChainID: chainID, Denom: "uatom", Bech32Prefix: "cosmos"})}
+}
+
+func (c *forwardChain) SendIBCTransferWithMemo(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout, memo string) (ibc.Tx, error) {
+	var m forwardMemo
+	if err := json.Unmarshal([]byte(memo), &m); err != nil {
+		return ibc.Tx{}, err
+	}
+	tx, err := c.SendIBCTransfer(ctx, channelID, keyName, amount, timeout)
+	if err != nil {
+		return ibc.Tx{}, err
+	}
+
+	received, err := c.dst.GetBalance(ctx, m.Forward.Receiver, c.dstDenom)
+	if err != nil {
+		return ibc.Tx{}, err
+	}
+	c.dst.SetBalance(m.Forward.Receiver, c.dstDenom, received.Add(amount.Amount))
+	return tx, nil
+}
+
+func TestForwardTransfer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	src, hop, dst := newForwardChain("gaia-1"), newForwardChain("rococo-1"), newForwardChain("gaia-2")
+	src.dst = dst
+	require.NoError(t, src.CreateKey(ctx, "sender"))
+	addr, err := src.GetAddress(ctx, "sender")
+	require.NoError(t, err)
+	sender := types.MustBech32ifyAddressBytes("cosmos", addr)
+	src.SetBalance(sender, "uatom", math.NewInt(1000))
+
+	srcChannel := ibc.ChannelOutput{PortID: "transfer", ChannelID: "channel-0", Counterparty: ibc.ChannelCounterparty{PortID: "transfer", ChannelID: "channel-7"}}
+	hopChannel := ibc.ChannelOutput{PortID: "transfer", ChannelID: "channel-8", Counterparty: ibc.ChannelCounterparty{PortID: "transfer", ChannelID: "channel-3"}}
+	src.dstDenom = transfertypes.ParseDenomTrace("transfer/channel-3/transfer/channel-7/uatom").IBCDenom()
+
+	cfg := ForwardTransferConfig{
+		Src:           src,
+		Hop:           hop,
+		Dst:           dst,
+		SrcChannel:    srcChannel,
+		HopChannel:    hopChannel,
+		SenderKeyName: "sender",
+		SenderAddress: sender,
+		HopReceiver:   "hop-receiver",
+		Amount:        ibc.WalletAmount{Address: "receiver", Denom: "uatom", Amount: math.NewInt(100)},
+		RegisterAsset: func(ctx context.Context, chain ibc.Chain, denomTrace string) error {
+			c := chain.(*forwardChain)
+			c.registered = append(c.registered, denomTrace)
+			return nil
+		},
+	}
+
+	result, err := ForwardTransfer(ctx, cfg)
+	require.NoError(t, err)
+	require.Equal(t, "transfer/channel-7/uatom", result.HopDenomTrace)
+	require.Equal(t, "transfer/channel-3/transfer/channel-7/uatom", result.DstDenomTrace)
+	require.Equal(t, []string{result.HopDenomTrace}, hop.registered)
+	require.Equal(t, []string{result.DstDenomTrace}, dst.registered)
+	require.Equal(t, int64(100), result.DstBalance.Int64())
+	senderBalance, err := src.GetBalance(ctx, sender, "uatom")
+	require.NoError(t, err)
+	require.Equal(t, int64(900), senderBalance.Int64())
+
+	// Nothing arrives if the transfer is not forwarded.
+	src.dstDenom = "ibc/other"
+	cfg.MaxBlocks = 2
+	_, err = ForwardTransfer(ctx, cfg)
+	require.ErrorContains(t, err, "did not reach")
+
+	// A negative MaxBlocks waits for the default number of blocks, rather than until ctx is done.
+	cfg.MaxBlocks = -1
+	_, err = ForwardTransfer(ctx, cfg)
+	require.ErrorContains(t, err, "within 30 blocks")
+
+	cfg.HopReceiver = ""
+	_, err = ForwardTransfer(ctx, cfg)
+	require.ErrorContains(t, err, "missing intermediate receiver")
+
+	// Chains that cannot send memos, such as polkadot chains, cannot start a forwarded transfer.
+	cfg.Src = src.Chain
+	_, err = ForwardTransfer(ctx, cfg)
+	require.EqualError(t, err, "chain gaia-1 does not support transfer memos")
+}
+
+func TestReceivedDenomTrace(t *testing.T) {
+	t.Parallel()
+
+	channel := ibc.ChannelOutput{PortID: "transfer", ChannelID: "channel-0", Counterparty: ibc.ChannelCounterparty{PortID: "transfer", ChannelID: "channel-5"}}
+	require.Equal(t, "transfer/channel-5/uatom", receivedDenomTrace(channel, "uatom"))
+
+	// Tokens returning through the channel they arrived on are unwound.
+	require.Equal(t, "uosmo", receivedDenomTrace(channel, "transfer/channel-0/uosmo"))
+	require.Equal(t, "transfer/channel-5/transfer/channel-1/uosmo", receivedDenomTrace(channel, "transfer/channel-1/uosmo"))
+}
@@ -0,0 +1,500 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v6/modules/apps/transfer/types"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+const (
+	defaultFuzzCases = 20
+
+	// Blocks to search for the acknowledgement on the source chain after flushing.
+	fuzzAckPollBlocks = 10
+)
+
+// maxUint256 is the largest amount an ICS-20 transfer can carry.
+var maxUint256 = math.NewIntFromBigInt(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)))
+
+// TransferCaseKind is the kind of input a TransferCase was generated as,
+// which determines the outcome FuzzTransfers expects.
+type TransferCaseKind int
+
+const (
+	// ValidTransfer is received on the destination chain with a success acknowledgement.
+	ValidTransfer TransferCaseKind = iota
+
+	// ZeroAmount is rejected by the source chain.
+	ZeroAmount
+
+	// ExcessAmount, above the sender's balance up to the maximum uint256, is rejected by the source chain.
+	ExcessAmount
+
+	// EmptyReceiver is rejected by the source chain.
+	EmptyReceiver
+
+	// InvalidReceiver is accepted by the source chain, which only checks that the receiver is not empty,
+	// and acknowledged with an error by the destination chain, refunding the sender.
+	InvalidReceiver
+)
+
+func (k TransferCaseKind) String() string {
+	switch k {
+	case ValidTransfer:
+		return "valid"
+	case ZeroAmount:
+		return "zero amount"
+	case ExcessAmount:
+		return "excess amount"
+	case EmptyReceiver:
+		return "empty receiver"
+	case InvalidReceiver:
+		return "invalid receiver"
+	default:
+		return fmt.Sprintf("TransferCaseKind(%d)", int(k))
+	}
+}
+
+// rejected reports whether transfers of kind k are rejected by the source chain.
+func (k TransferCaseKind) rejected() bool {
+	return k == ZeroAmount || k == ExcessAmount || k == EmptyReceiver
+}
+
+// TransferCase is a randomized transfer generated by GenerateTransferCases.
+type TransferCase struct {
+	Kind     TransferCaseKind
+	Denom    string
+	Amount   math.Int
+	Receiver string
+	Memo     string
+}
+
+func (c TransferCase) String() string {
+	return fmt.Sprintf("%s: %s%s to %q with %d byte memo", c.Kind, c.Amount, c.Denom, c.Receiver, len(c.Memo))
+}
+
+// TransferCaseParams bounds the transfers generated by GenerateTransferCases.
+type TransferCaseParams struct {
+	// Denoms to transfer, each with an entry in MaxAmounts and Balances.
+	Denoms []string
+
+	// Valid receiver on the destination chain, from which invalid receivers are derived.
+	Receiver string
+
+	// Largest amount of each denom sent by a valid transfer.
+	MaxAmounts map[string]math.Int
+
+	// Sender balance of each denom, which excess amounts exceed.
+	Balances map[string]math.Int
+
+	// Whether to generate memos.
+	Memos bool
+}
+
+// GenerateTransferCases deterministically generates n transfers from seed,
+// about half of them valid and the rest spread over the invalid kinds.
+// Amounts include the boundary values of each kind: 1 and the maximum amount for valid transfers,
+// and the balance plus one and the maximum uint256 for excess amounts.
+func GenerateTransferCases(seed int64, n int, p TransferCaseParams) []TransferCase {
+	if len(p.Denoms) == 0 {
+		panic(errors.New("no denoms to generate transfers of"))
+	}
+	if p.Receiver == "" {
+		panic(errors.New("missing valid receiver"))
+	}
+	for _, denom := range p.Denoms {
+		if !p.MaxAmounts[denom].IsPositive() {
+			panic(fmt.Errorf("max amount of %s must be positive", denom))
+		}
+		if p.Balances[denom].IsNil() {
+			panic(fmt.Errorf("missing balance of %s", denom))
+		}
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	cases := make([]TransferCase, n)
+	for i := range cases {
+		c := TransferCase{
+			Denom:    p.Denoms[rng.Intn(len(p.Denoms))],
+			Receiver: p.Receiver,
+		}
+		maxAmount := p.MaxAmounts[c.Denom]
+
+		switch k := rng.Intn(8); {
+		case k < 4:
+			c.Kind = ValidTransfer
+			c.Amount = randomAmount(rng, maxAmount)
+		case k == 4:
+			c.Kind = ZeroAmount
+			c.Amount = math.ZeroInt()
+		case k == 5:
+			c.Kind = ExcessAmount
+			if rng.Intn(2) == 0 {
+				c.Amount = maxUint256
+			} else {
+				c.Amount = p.Balances[c.Denom].Add(randomAmount(rng, maxAmount))
+			}
+		case k == 6:
+			c.Kind = EmptyReceiver
+			c.Amount = randomAmount(rng, maxAmount)
+			c.Receiver = ""
+		default:
+			c.Kind = InvalidReceiver
+			c.Amount = randomAmount(rng, maxAmount)
+			c.Receiver = invalidReceiver(rng, p.Receiver)
+		}
+
+		if p.Memos {
+			c.Memo = randomMemo(rng)
+		}
+		cases[i] = c
+	}
+	return cases
+}
+
+// randomAmount returns 1, maxAmount, or a uniformly random amount in between.
+func randomAmount(rng *rand.Rand, maxAmount math.Int) math.Int {
+	switch rng.Intn(4) {
+	case 0:
+		return math.OneInt()
+	case 1:
+		return maxAmount
+	default:
+		return math.NewIntFromBigInt(new(big.Int).Rand(rng, maxAmount.BigInt())).AddRaw(1)
+	}
+}
+
+// invalidReceiver returns a non-empty receiver derived from the valid receiver that no chain accepts.
+func invalidReceiver(rng *rand.Rand, receiver string) string {
+	switch rng.Intn(4) {
+	case 0:
+		// Invalid checksum.
+		return receiver[:len(receiver)-1]
+	case 1:
+		// Unknown bech32 prefix.
+		if i := strings.LastIndexByte(receiver, '1'); i > 0 {
+			return "invalid" + receiver[i:]
+		}
+		return "invalid" + receiver
+	case 2:
+		return "not-an-address"
+	default:
+		return strings.Repeat("x", 128)
+	}
+}
+
+// randomMemo returns an empty, short, unicode, or long memo.
+func randomMemo(rng *rand.Rand) string {
+	switch rng.Intn(4) {
+	case 0:
+		return ""
+	case 1:
+		const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 -_.,:;"
+		b := make([]byte, 1+rng.Intn(64))
+		for i := range b {
+			b[i] = letters[rng.Intn(len(letters))]
+		}
+		return string(b)
+	case 2:
+		return "memo ✓ 🚀 ¿über?"
+	default:
+		return strings.Repeat("m", 1024)
+	}
+}
+
+// TransferFuzzConfig describes the link that FuzzTransfers sends randomized transfers across.
+type TransferFuzzConfig struct {
+	Relayer  ibc.Relayer
+	Reporter ibc.RelayerExecReporter
+
+	// Name of the relayer path between Src and Dst.
+	PathName string
+
+	Src, Dst ibc.Chain
+
+	// Transfer channel from Src to Dst, as returned by GetChannels on Src.
+	SrcChannel ibc.ChannelOutput
+
+	// Key name and address of the sender on Src.
+	SenderKeyName, SenderAddress string
+
+	// Valid receiver on Dst.
+	Receiver string
+
+	// Optional. Denoms to transfer, which must be native to Src so that they are escrowed.
+	// Defaults to the denom of Src.
+	Denoms []string
+
+	// Optional. Seed of the generated transfers.
+	// Zero uses a time-based seed; the seed is reported with any failure, so that it can be set to replay the same transfers.
+	Seed int64
+
+	// Optional. Number of transfers to send. Defaults to 20.
+	Cases int
+
+	// Optional. Largest amount sent by a valid transfer.
+	// Defaults to the sender's balance of each denom divided by twice the number of transfers.
+	MaxAmount math.Int
+
+	// Optional. Address of the escrow account of SrcChannel on Src.
+	// Defaults to the ibc-go escrow address for cosmos chains; escrow is not checked for other chains unless set.
+	EscrowAddress string
+}
+
+// TransferFuzzResult is the result of FuzzTransfers.
+type TransferFuzzResult struct {
+	// Seed the transfers were generated from.
+	Seed int64
+
+	// Transfers that were generated.
+	Cases []TransferCase
+}
+
+// FuzzTransfers is a property test of ICS-20 transfers from Src to Dst.
+// It generates randomized transfers with GenerateTransferCases and sends them one at a time,
+// flushing each accepted transfer and its acknowledgement through the relayer,
+// and checks these invariants after each transfer:
+//   - transfers with a zero amount, an amount above the sender's balance, or no receiver are rejected by Src;
+//   - transfers to an invalid receiver are acknowledged with an error and refunded;
+//   - valid transfers are acknowledged with a success and credited to the receiver;
+//   - the escrow account holds exactly the amounts of successful transfers,
+//     and the sender paid exactly those amounts, aside from gas fees paid in the transferred denom.
+//
+// Memos are generated if Src supports them.
+// The first broken invariant is returned as an error naming the seed and the transfer.
+func FuzzTransfers(ctx context.Context, cfg TransferFuzzConfig) (TransferFuzzResult, error) {
+	result := TransferFuzzResult{Seed: cfg.Seed}
+	if result.Seed == 0 {
+		result.Seed = time.Now().UnixNano()
+	}
+
+	n := cfg.Cases
+	if n == 0 {
+		n = defaultFuzzCases
+	}
+	denoms := cfg.Denoms
+	if len(denoms) == 0 {
+		denoms = []string{cfg.Src.Config().Denom}
+	}
+
+	escrow, err := escrowAddress(cfg.Src, cfg.SrcChannel, cfg.EscrowAddress)
+	if err != nil {
+		return result, err
+	}
+
+	memoSender, memos := cfg.Src.(ibc.MemoIBCTransferer)
+	memos = memos && ibc.SupportsFeature(cfg.Src, ibc.FeatureMemo)
+
+	params := TransferCaseParams{
+		Denoms:     denoms,
+		Receiver:   cfg.Receiver,
+		MaxAmounts: make(map[string]math.Int, len(denoms)),
+		Balances:   make(map[string]math.Int, len(denoms)),
+		Memos:      memos,
+	}
+	for _, denom := range denoms {
+		balance, err := cfg.Src.GetBalance(ctx, cfg.SenderAddress, denom)
+		if err != nil {
+			return result, fmt.Errorf("failed to get sender balance of %s: %w", denom, err)
+		}
+		maxAmount := cfg.MaxAmount
+		if maxAmount.IsNil() {
+			maxAmount = balance.QuoRaw(int64(2 * n))
+		}
+		if !maxAmount.IsPositive() {
+			return result, fmt.Errorf("sender balance %s%s is too low for %d transfers", balance, denom, n)
+		}
+		params.Balances[denom] = balance
+		params.MaxAmounts[denom] = maxAmount
+	}
+
+	result.Cases = GenerateTransferCases(result.Seed, n, params)
+	for i, c := range result.Cases {
+		if err := cfg.checkTransfer(ctx, c, escrow, memoSender); err != nil {
+			return result, fmt.Errorf("seed %d, transfer %d (%s): %w", result.Seed, i, c, err)
+		}
+	}
+	return result, nil
+}
+
+// checkTransfer sends c and checks that its outcome matches its kind.
+func (cfg TransferFuzzConfig) checkTransfer(ctx context.Context, c TransferCase, escrow string, memoSender ibc.MemoIBCTransferer) error {
+	srcChainID := cfg.Src.Config().ChainID
+	dstDenom := transfertypes.ParseDenomTrace(receivedDenomTrace(cfg.SrcChannel, c.Denom)).IBCDenom()
+
+	senderBefore, err := cfg.Src.GetBalance(ctx, cfg.SenderAddress, c.Denom)
+	if err != nil {
+		return fmt.Errorf("failed to get sender balance before transfer: %w", err)
+	}
+	var escrowBefore math.Int
+	if escrow != "" {
+		if escrowBefore, err = cfg.Src.GetBalance(ctx, escrow, c.Denom); err != nil {
+			return fmt.Errorf("failed to get escrow balance before transfer: %w", err)
+		}
+	}
+	var receiverBefore math.Int
+	if c.Kind == ValidTransfer {
+		if receiverBefore, err = cfg.Dst.GetBalance(ctx, c.Receiver, dstDenom); err != nil {
+			return fmt.Errorf("failed to get receiver balance before transfer: %w", err)
+		}
+	}
+
+	amount := ibc.WalletAmount{Address: c.Receiver, Denom: c.Denom, Amount: c.Amount}
+	var tx ibc.Tx
+	if c.Memo != "" {
+		tx, err = memoSender.SendIBCTransferWithMemo(ctx, cfg.SrcChannel.ChannelID, cfg.SenderKeyName, amount, nil, c.Memo)
+	} else {
+		tx, err = cfg.Src.SendIBCTransfer(ctx, cfg.SrcChannel.ChannelID, cfg.SenderKeyName, amount, nil)
+	}
+
+	if c.Kind.rejected() {
+		if err == nil {
+			return fmt.Errorf("transfer was accepted by %s in tx %s, expected it to be rejected", srcChainID, tx.TxHash)
+		}
+		return cfg.checkEscrow(ctx, escrow, c.Denom, escrowBefore, math.ZeroInt())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send transfer: %w", err)
+	}
+	if err := tx.Validate(); err != nil {
+		return fmt.Errorf("invalid transfer tx: %w", err)
+	}
+
+	ack, err := cfg.relay(ctx, tx)
+	if err != nil {
+		return err
+	}
+	ackErr, err := ack.ErrorResult()
+	if err != nil {
+		return fmt.Errorf("invalid acknowledgement: %w", err)
+	}
+
+	sent := math.ZeroInt()
+	switch c.Kind {
+	case ValidTransfer:
+		if ackErr != "" {
+			return fmt.Errorf("transfer was acknowledged with an error: %s", ackErr)
+		}
+		sent = c.Amount
+	case InvalidReceiver:
+		if ackErr == "" {
+			return errors.New("transfer was acknowledged with a success, expected an error")
+		}
+	}
+
+	senderAfter, err := cfg.Src.GetBalance(ctx, cfg.SenderAddress, c.Denom)
+	if err != nil {
+		return fmt.Errorf("failed to get sender balance after transfer: %w", err)
+	}
+	wantSender := senderBefore.Sub(sent)
+	if c.Denom == cfg.Src.Config().Denom {
+		wantSender = wantSender.Sub(cfg.Src.GetGasFeesInNativeDenom(tx.GasSpent))
+	}
+	if !senderAfter.Equal(wantSender) {
+		return fmt.Errorf("sender balance %s%s, want %s%s", senderAfter, c.Denom, wantSender, c.Denom)
+	}
+
+	if err := cfg.checkEscrow(ctx, escrow, c.Denom, escrowBefore, sent); err != nil {
+		return err
+	}
+
+	if c.Kind == ValidTransfer {
+		receiverAfter, err := cfg.Dst.GetBalance(ctx, c.Receiver, dstDenom)
+		if err != nil {
+			return fmt.Errorf("failed to get receiver balance after transfer: %w", err)
+		}
+		if want := receiverBefore.Add(c.Amount); !receiverAfter.Equal(want) {
+			return fmt.Errorf("receiver balance %s%s, want %s%s", receiverAfter, dstDenom, want, dstDenom)
+		}
+	}
+	return nil
+}
+
+// relay flushes the packet of tx and its acknowledgement, and returns the acknowledgement found on Src.
+func (cfg TransferFuzzConfig) relay(ctx context.Context, tx ibc.Tx) (ibc.PacketAcknowledgement, error) {
+	return flushTransfer(ctx, cfg.Relayer, cfg.Reporter, cfg.PathName, cfg.SrcChannel.ChannelID, cfg.Src, cfg.Dst, tx)
+}
+
+// flushTransfer flushes the packet of tx, sent on channelID of src, and its acknowledgement,
+// and returns the acknowledgement found on src.
+func flushTransfer(
+	ctx context.Context,
+	r ibc.Relayer,
+	rep ibc.RelayerExecReporter,
+	pathName, channelID string,
+	src, dst ibc.Chain,
+	tx ibc.Tx,
+) (ibc.PacketAcknowledgement, error) {
+	if err := r.FlushPackets(ctx, rep, pathName, channelID); err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("failed to flush packets: %w", err)
+	}
+	if err := WaitForBlocks(ctx, 2, src, dst); err != nil {
+		return ibc.PacketAcknowledgement{}, err
+	}
+	if err := r.FlushAcknowledgements(ctx, rep, pathName, channelID); err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("failed to flush acknowledgements: %w", err)
+	}
+
+	srcHeight, err := src.Height(ctx)
+	if err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("failed to get source height: %w", err)
+	}
+	ack, err := PollForAck(ctx, src, tx.Height, srcHeight+fuzzAckPollBlocks, tx.Packet)
+	if err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("failed to find acknowledgement on source chain: %w", err)
+	}
+	if err := ack.Validate(); err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("invalid acknowledgement on source chain: %w", err)
+	}
+	return ack, nil
+}
+
+// checkSuccessAck returns an error unless ack acknowledges its transfer with a success.
+func checkSuccessAck(ack ibc.PacketAcknowledgement) error {
+	ackErr, err := ack.ErrorResult()
+	if err != nil {
+		return fmt.Errorf("invalid acknowledgement: %w", err)
+	}
+	if ackErr != "" {
+		return fmt.Errorf("transfer was acknowledged with an error: %s", ackErr)
+	}
+	return nil
+}
+
+// escrowAddress returns override if set, or else the ibc-go escrow address of channel for cosmos chains,
+// or else the empty string, meaning that escrow is not checked.
+func escrowAddress(src ibc.Chain, channel ibc.ChannelOutput, override string) (string, error) {
+	if override != "" || src.Config().Type != "cosmos" {
+		return override, nil
+	}
+	escrow, err := sdk.Bech32ifyAddressBytes(src.Config().Bech32Prefix, transfertypes.GetEscrowAddress(channel.PortID, channel.ChannelID))
+	if err != nil {
+		return "", fmt.Errorf("failed to derive escrow address: %w", err)
+	}
+	return escrow, nil
+}
+
+// checkEscrow checks that the escrow balance of denom grew from before by sent, unless escrow is empty.
+func (cfg TransferFuzzConfig) checkEscrow(ctx context.Context, escrow, denom string, before, sent math.Int) error {
+	if escrow == "" {
+		return nil
+	}
+	want := before.Add(sent)
+	got, err := cfg.Src.GetBalance(ctx, escrow, denom)
+	if err != nil {
+		return fmt.Errorf("failed to get escrow balance: %w", err)
+	}
+	if !got.Equal(want) {
+		return fmt.Errorf("escrow balance %s%s, want %s%s", got, denom, want, denom)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTransferCases(t *testing.T) {
+	t.Parallel()
+
+	const receiver = "cosmos1hsk6jryyqjfhp5dhc55tc9jtckygx0eph6dd02"
+	p := TransferCaseParams{
+		Denoms:     []string{"uatom", "stake"},
+		Receiver:   receiver,
+		MaxAmounts: map[string]math.Int{"uatom": math.NewInt(1000), "stake": math.NewInt(5)},
+		Balances:   map[string]math.Int{"uatom": math.NewInt(100_000), "stake": math.NewInt(10)},
+		Memos:      true,
+	}
+
+	cases := GenerateTransferCases(42, 500, p)
+	require.Len(t, cases, 500)
+	require.Equal(t, cases, GenerateTransferCases(42, 500, p), "cases must be deterministic for a seed")
+	require.NotEqual(t, cases, GenerateTransferCases(43, 500, p))
+
+	kinds := make(map[TransferCaseKind]int)
+	var memos, maxUint bool
+	for _, c := range cases {
+		kinds[c.Kind]++
+		memos = memos || c.Memo != ""
+		require.Contains(t, p.Denoms, c.Denom)
+
+		switch c.Kind {
+		case ValidTransfer, InvalidReceiver, EmptyReceiver:
+			require.True(t, c.Amount.IsPositive(), c)
+			require.True(t, c.Amount.LTE(p.MaxAmounts[c.Denom]), c)
+		case ZeroAmount:
+			require.True(t, c.Amount.IsZero(), c)
+		case ExcessAmount:
+			require.True(t, c.Amount.GT(p.Balances[c.Denom]), c)
+			maxUint = maxUint || c.Amount.Equal(maxUint256)
+		}
+
+		switch c.Kind {
+		case EmptyReceiver:
+			require.Empty(t, c.Receiver, c)
+		case InvalidReceiver:
+			require.NotEmpty(t, c.Receiver, c)
+			require.NotEqual(t, receiver, c.Receiver, c)
+		default:
+			require.Equal(t, receiver, c.Receiver, c)
+		}
+	}
+	for _, k := range []TransferCaseKind{ValidTransfer, ZeroAmount, ExcessAmount, EmptyReceiver, InvalidReceiver} {
+		require.Positive(t, kinds[k], "no %s cases", k)
+	}
+	require.Greater(t, kinds[ValidTransfer], 500/3, "about half of the cases must be valid")
+	require.True(t, memos)
+	require.True(t, maxUint)
+
+	p.Memos = false
+	for _, c := range GenerateTransferCases(42, 50, p) {
+		require.Empty(t, c.Memo)
+	}
+
+	require.Panics(t, func() {
+		GenerateTransferCases(1, 1, TransferCaseParams{Denoms: []string{"uatom"}, Receiver: receiver})
+	}, "missing max amount")
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// HistoricalStateQuerier is a node that queries the state of its chain as of a past height.
+// *cosmos.ChainNode, *polkadot.RelayChainNode and *polkadot.ParachainNode satisfy HistoricalStateQuerier.
+type HistoricalStateQuerier interface {
+	ChainHeighter
+	QueryHistoricalState(ctx context.Context, height uint64) error
+}
+
+// VerifyHistoricalQueries returns an error unless the state as of height can be queried from every archive node,
+// and queries of it fail gracefully on every pruned node, which must go on to serve queries of its latest state.
+// Height must be old enough for the pruned nodes to have pruned it, as configured by ibc.ChainConfig.Pruning,
+// e.g. by waiting for more blocks than they keep, and then for a pruning interval.
+// This matters to relayers, whose proofs of packets sent near the pruning boundary may no longer be queryable.
+func VerifyHistoricalQueries(ctx context.Context, height uint64, archive, pruned []HistoricalStateQuerier) error {
+	if len(archive) == 0 && len(pruned) == 0 {
+		return errors.New("no nodes")
+	}
+	for i, n := range archive {
+		if err := n.QueryHistoricalState(ctx, height); err != nil {
+			return fmt.Errorf("archive node %d: %w", i, err)
+		}
+	}
+	for i, n := range pruned {
+		if err := n.QueryHistoricalState(ctx, height); err == nil {
+			return fmt.Errorf("pruned node %d: state at height %d was not pruned", i, height)
+		}
+		latest, err := n.Height(ctx)
+		if err != nil {
+			return fmt.Errorf("pruned node %d: failed to get height after query of pruned state: %w", i, err)
+		}
+		if err := n.QueryHistoricalState(ctx, latest); err != nil {
+			return fmt.Errorf("pruned node %d: after query of pruned state: %w", i, err)
+		}
+	}
+	return nil
+}
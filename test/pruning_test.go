@@ -0,0 +1,49 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pruningNode keeps the state of heights from oldest to height.
+type pruningNode struct {
+	oldest, height uint64
+}
+
+func (n pruningNode) Height(ctx context.Context) (uint64, error) {
+	return n.height, nil
+}
+
+func (n pruningNode) QueryHistoricalState(ctx context.Context, height uint64) error {
+	if height < n.oldest || height > n.height {
+		return fmt.Errorf("version %d does not exist", height)
+	}
+	return nil
+}
+
+func TestVerifyHistoricalQueries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	archive := pruningNode{oldest: 1, height: 100}
+	pruned := pruningNode{oldest: 90, height: 100}
+
+	require.NoError(t, VerifyHistoricalQueries(ctx, 10, []HistoricalStateQuerier{archive}, []HistoricalStateQuerier{pruned}))
+
+	require.EqualError(t,
+		VerifyHistoricalQueries(ctx, 95, []HistoricalStateQuerier{archive}, []HistoricalStateQuerier{pruned}),
+		"pruned node 0: state at height 95 was not pruned",
+	)
+	require.EqualError(t,
+		VerifyHistoricalQueries(ctx, 10, []HistoricalStateQuerier{archive, pruned}, nil),
+		"archive node 1: version 10 does not exist",
+	)
+	require.EqualError(t,
+		VerifyHistoricalQueries(ctx, 10, nil, []HistoricalStateQuerier{pruningNode{oldest: 200, height: 100}}),
+		"pruned node 0: after query of pruned state: version 100 does not exist",
+	)
+	require.EqualError(t, VerifyHistoricalQueries(ctx, 10, nil, nil), "no nodes")
+}
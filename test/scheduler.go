@@ -0,0 +1,139 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// HeightSubscriber streams the heights of the blocks that a chain commits.
+// *cosmos.CosmosChain satisfies HeightSubscriber.
+type HeightSubscriber interface {
+	// SubscribeHeights returns the heights of the blocks committed by the chain until ctx is done.
+	SubscribeHeights(ctx context.Context) (<-chan uint64, error)
+}
+
+// Scheduler runs actions, such as upgrades, network partitions, or bursts of transfers,
+// once chains reach given heights, so that multi-phase scenarios can be declared up front.
+// Register actions with RegisterAtHeight, then call Run.
+// The zero value is ready to use.
+type Scheduler struct {
+	mu      sync.Mutex
+	running bool
+	actions map[HeightSubscriber][]scheduledAction
+}
+
+// scheduledAction is an action registered to run at a height of a chain.
+type scheduledAction struct {
+	height uint64
+	fn     func(ctx context.Context) error
+}
+
+// RegisterAtHeight registers fn to run once chain commits a block at height or above.
+// Actions of a chain run one after the other, in order of height, or of registration for the same height;
+// the actions of different chains run concurrently.
+// RegisterAtHeight panics if called while the scheduler is running.
+func (s *Scheduler) RegisterAtHeight(chain HeightSubscriber, height uint64, fn func(ctx context.Context) error) {
+	if chain == nil {
+		panic("missing chain")
+	}
+	if fn == nil {
+		panic("missing action")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		panic("RegisterAtHeight called while the scheduler is running")
+	}
+	if s.actions == nil {
+		s.actions = make(map[HeightSubscriber][]scheduledAction)
+	}
+	s.actions[chain] = append(s.actions[chain], scheduledAction{height: height, fn: fn})
+}
+
+// Run subscribes to the blocks of every chain with registered actions, and runs each action
+// once its chain commits a block at the action's height or above.
+// Actions at heights that a chain has already passed run on its next block.
+// Run returns once every action has run, or the first error of an action or subscription,
+// in which case the context passed to the remaining actions is canceled.
+// Run unregisters the actions it runs, so it may be called again after registering more actions.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		panic("Run called while the scheduler is running")
+	}
+	s.running = true
+	actions := s.actions
+	s.actions = nil
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	for chain, chainActions := range actions {
+		chain, chainActions := chain, chainActions
+		sort.SliceStable(chainActions, func(i, j int) bool {
+			return chainActions[i].height < chainActions[j].height
+		})
+		eg.Go(func() error {
+			return runAtHeights(egCtx, chain, chainActions)
+		})
+	}
+	return eg.Wait()
+}
+
+// runAtHeights runs actions, sorted by height, as chain reaches their heights.
+func runAtHeights(ctx context.Context, chain HeightSubscriber, actions []scheduledAction) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	heights, err := chain.SubscribeHeights(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to heights: %w", err)
+	}
+
+	// Keep receiving heights while actions run, so that slow actions do not stall the subscription.
+	latest := make(chan uint64, 1)
+	go func() {
+		defer close(latest)
+		for h := range heights {
+			select {
+			case <-latest:
+			default:
+			}
+			latest <- h
+		}
+	}()
+
+	var height uint64
+	for _, a := range actions {
+		for height < a.height {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case h, ok := <-latest:
+				if !ok {
+					if err := ctx.Err(); err != nil {
+						return err
+					}
+					return fmt.Errorf("height subscription ended at height %d, before height %d", height, a.height)
+				}
+				if h > height {
+					height = h
+				}
+			}
+		}
+		if err := a.fn(ctx); err != nil {
+			return fmt.Errorf("action at height %d: %w", a.height, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockHeightSubscriber streams Heights, then closes its stream.
+type mockHeightSubscriber struct {
+	Heights []uint64
+	Err     error
+}
+
+func (m *mockHeightSubscriber) SubscribeHeights(ctx context.Context) (<-chan uint64, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	ch := make(chan uint64)
+	go func() {
+		defer close(ch)
+		for _, h := range m.Heights {
+			select {
+			case ch <- h:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("runs actions in order of height", func(t *testing.T) {
+		chainA := &mockHeightSubscriber{Heights: []uint64{3, 4, 5, 6, 7}}
+		chainB := &mockHeightSubscriber{Heights: []uint64{10, 11}}
+
+		var (
+			mu  sync.Mutex
+			ran []string
+		)
+		record := func(name string) func(context.Context) error {
+			return func(context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				ran = append(ran, name)
+				return nil
+			}
+		}
+
+		var s Scheduler
+		s.RegisterAtHeight(chainA, 6, record("a6"))
+		s.RegisterAtHeight(chainA, 2, record("a2"))
+		s.RegisterAtHeight(chainA, 6, record("a6-second"))
+		s.RegisterAtHeight(chainB, 11, record("b11"))
+
+		require.NoError(t, s.Run(ctx))
+		require.ElementsMatch(t, []string{"a2", "a6", "a6-second", "b11"}, ran)
+
+		var chainARan []string
+		for _, name := range ran {
+			if name != "b11" {
+				chainARan = append(chainARan, name)
+			}
+		}
+		require.Equal(t, []string{"a2", "a6", "a6-second"}, chainARan)
+
+		// Actions that ran are not run again.
+		ran = nil
+		require.NoError(t, s.Run(ctx))
+		require.Empty(t, ran)
+	})
+
+	t.Run("action error", func(t *testing.T) {
+		chain := &mockHeightSubscriber{Heights: []uint64{1, 2, 3}}
+
+		var s Scheduler
+		s.RegisterAtHeight(chain, 2, func(context.Context) error { return errors.New("boom") })
+		s.RegisterAtHeight(chain, 3, func(context.Context) error {
+			panic("action after a failed action must not run")
+		})
+
+		require.EqualError(t, s.Run(ctx), "action at height 2: boom")
+	})
+
+	t.Run("height never reached", func(t *testing.T) {
+		chain := &mockHeightSubscriber{Heights: []uint64{1, 2}}
+
+		var s Scheduler
+		s.RegisterAtHeight(chain, 5, func(context.Context) error { return nil })
+
+		require.EqualError(t, s.Run(ctx), "height subscription ended at height 2, before height 5")
+	})
+
+	t.Run("subscription error", func(t *testing.T) {
+		chain := &mockHeightSubscriber{Err: errors.New("no websocket")}
+
+		var s Scheduler
+		s.RegisterAtHeight(chain, 1, func(context.Context) error { return nil })
+
+		require.EqualError(t, s.Run(ctx), "failed to subscribe to heights: no websocket")
+	})
+}
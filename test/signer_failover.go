@@ -0,0 +1,109 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultFailoverBlocksBefore = 2
+	defaultFailoverBlocksAfter  = 10
+)
+
+// CosignerStopper stops the threshold signer cosigners of a chain's validators.
+// *cosmos.CosmosChain satisfies CosignerStopper.
+type CosignerStopper interface {
+	StopCosigner(ctx context.Context, validator, shard int) error
+}
+
+// SigningVerifier verifies that a chain's validator signed blocks without double-signing.
+// *cosmos.CosmosChain satisfies SigningVerifier.
+type SigningVerifier interface {
+	VerifySigning(ctx context.Context, validator int, startHeight, endHeight uint64, maxMissed int) error
+}
+
+// SignerFailoverChain is a chain whose validators sign through threshold signers, see VerifySignerFailover.
+type SignerFailoverChain interface {
+	ChainHeighter
+	CosignerStopper
+	SigningVerifier
+}
+
+// SignerFailoverConfig describes the failover of a validator's threshold signer checked by VerifySignerFailover.
+type SignerFailoverConfig struct {
+	Chain SignerFailoverChain
+
+	// Index of the validator, and shard, numbered from 1, of the cosigner stopped.
+	Validator, Shard int
+
+	// Optional. Sends IBC traffic, such as relayed transfers from or to Chain, during the failover.
+	// It must return once its traffic is done; its error fails the scenario.
+	Traffic func(ctx context.Context) error
+
+	// Optional. Number of blocks before the cosigner is stopped. Defaults to 2.
+	BlocksBefore int
+
+	// Optional. Number of blocks after the cosigner is stopped. Defaults to 10.
+	BlocksAfter int
+
+	// Number of blocks that the validator may miss signing, e.g. while its cosigners elect a new leader.
+	MaxMissedBlocks int
+}
+
+// VerifySignerFailover stops a cosigner of a validator's threshold signer while Traffic flows,
+// and returns an error unless the validator keeps signing blocks, missing at most MaxMissedBlocks, without double-signing.
+func VerifySignerFailover(ctx context.Context, cfg SignerFailoverConfig) error {
+	if cfg.Chain == nil {
+		return errors.New("no chain")
+	}
+	if cfg.BlocksBefore < 0 || cfg.BlocksAfter < 0 {
+		return fmt.Errorf("invalid blocks before %d and after %d the failover: must not be negative", cfg.BlocksBefore, cfg.BlocksAfter)
+	}
+	before, after := cfg.BlocksBefore, cfg.BlocksAfter
+	if before == 0 {
+		before = defaultFailoverBlocksBefore
+	}
+	if after == 0 {
+		after = defaultFailoverBlocksAfter
+	}
+
+	start, err := cfg.Chain.Height(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get height before failover: %w", err)
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	if cfg.Traffic != nil {
+		eg.Go(func() error {
+			if err := cfg.Traffic(egCtx); err != nil {
+				return fmt.Errorf("traffic during failover: %w", err)
+			}
+			return nil
+		})
+	}
+	eg.Go(func() error {
+		if err := WaitForBlocks(egCtx, before, cfg.Chain); err != nil {
+			return fmt.Errorf("before stopping cosigner: %w", err)
+		}
+		if err := cfg.Chain.StopCosigner(egCtx, cfg.Validator, cfg.Shard); err != nil {
+			return fmt.Errorf("failed to stop cosigner: %w", err)
+		}
+		if err := WaitForBlocks(egCtx, after, cfg.Chain); err != nil {
+			return fmt.Errorf("after stopping cosigner: %w", err)
+		}
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	end, err := cfg.Chain.Height(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get height after failover: %w", err)
+	}
+	// The first block after start is the first whose commit the validator may have signed during the scenario.
+	return cfg.Chain.VerifySigning(ctx, cfg.Validator, start+1, end, cfg.MaxMissedBlocks)
+}
@@ -0,0 +1,160 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// TimeoutKind selects how the packet sent by TransferTimeout times out.
+type TimeoutKind int
+
+const (
+	// HeightTimeout times out the packet after a number of blocks on the destination chain.
+	HeightTimeout TimeoutKind = iota
+
+	// TimestampTimeout times out the packet after a duration of destination chain block time.
+	TimestampTimeout
+)
+
+const (
+	defaultTimeoutBlocks = 5
+	defaultTimeoutPeriod = 10 * time.Second
+
+	// Blocks to search for the timeout on the source chain after flushing.
+	timeoutPollBlocks = 10
+)
+
+// TransferTimeoutConfig describes the transfer that TransferTimeout times out.
+type TransferTimeoutConfig struct {
+	Relayer  ibc.Relayer
+	Reporter ibc.RelayerExecReporter
+
+	// Name of the relayer path between Src and Dst.
+	PathName string
+
+	// If set, the relayer is stopped before the transfer,
+	// and restarted on PathName when TransferTimeout returns, whether or not it succeeded.
+	StopRelayer bool
+
+	Src, Dst ibc.Chain
+
+	// Channel on Src that the transfer is sent on.
+	SrcChannelID string
+
+	// Key name and address of the sender on Src.
+	SenderKeyName, SenderAddress string
+
+	// Transfer to send; Address is the receiver on Dst.
+	Amount ibc.WalletAmount
+
+	Kind TimeoutKind
+
+	// Optional. Relative timeout of the packet: blocks on Dst for HeightTimeout,
+	// or Dst block time for TimestampTimeout.
+	// Defaults to 5 blocks or 10 seconds.
+	TimeoutBlocks uint64
+	TimeoutPeriod time.Duration
+}
+
+// TransferTimeout performs a full timeout scenario across any pair of chains.
+// It sends the transfer with a short timeout while nothing relays it, waits past the timeout on Dst,
+// flushes only the transfer's path and channel so the relayer submits the timeout on Src,
+// and verifies that the sender was refunded, aside from gas fees paid in the transferred denom.
+//
+// It returns the timeout found on Src, or an error if cfg.Kind is unknown, any step fails, or the sender was not refunded.
+func TransferTimeout(ctx context.Context, cfg TransferTimeoutConfig) (_ ibc.PacketTimeout, err error) {
+	var timeout ibc.IBCTimeout
+	switch cfg.Kind {
+	case HeightTimeout:
+		timeout.Height = cfg.TimeoutBlocks
+		if timeout.Height == 0 {
+			timeout.Height = defaultTimeoutBlocks
+		}
+	case TimestampTimeout:
+		period := cfg.TimeoutPeriod
+		if period == 0 {
+			period = defaultTimeoutPeriod
+		}
+		timeout.NanoSeconds = uint64(period.Nanoseconds())
+	default:
+		return ibc.PacketTimeout{}, fmt.Errorf("unknown timeout kind %d", cfg.Kind)
+	}
+
+	if cfg.StopRelayer {
+		if err := cfg.Relayer.StopRelayer(ctx, cfg.Reporter); err != nil {
+			return ibc.PacketTimeout{}, fmt.Errorf("failed to stop relayer: %w", err)
+		}
+		// Restart the relayer even if a later step fails, so the rest of the test still relays.
+		defer func() {
+			if startErr := cfg.Relayer.StartRelayer(ctx, cfg.Reporter, cfg.PathName); startErr != nil && err == nil {
+				err = fmt.Errorf("failed to restart relayer: %w", startErr)
+			}
+		}()
+	}
+
+	before, err := cfg.Src.GetBalance(ctx, cfg.SenderAddress, cfg.Amount.Denom)
+	if err != nil {
+		return ibc.PacketTimeout{}, fmt.Errorf("failed to get sender balance before transfer: %w", err)
+	}
+
+	tx, err := cfg.Src.SendIBCTransfer(ctx, cfg.SrcChannelID, cfg.SenderKeyName, cfg.Amount, &timeout)
+	if err != nil {
+		return ibc.PacketTimeout{}, fmt.Errorf("failed to send transfer: %w", err)
+	}
+	if err := tx.Validate(); err != nil {
+		return ibc.PacketTimeout{}, fmt.Errorf("invalid transfer tx: %w", err)
+	}
+
+	// Wait until Dst has produced a block past the timeout.
+	if timeout.NanoSeconds > 0 {
+		select {
+		case <-ctx.Done():
+			return ibc.PacketTimeout{}, ctx.Err()
+		case <-time.After(time.Duration(timeout.NanoSeconds)):
+		}
+		err = WaitForBlocks(ctx, 1, cfg.Dst)
+	} else {
+		err = WaitForBlocks(ctx, int(timeout.Height)+1, cfg.Dst)
+	}
+	if err != nil {
+		return ibc.PacketTimeout{}, fmt.Errorf("failed to wait for timeout: %w", err)
+	}
+
+	if err := cfg.Relayer.FlushPackets(ctx, cfg.Reporter, cfg.PathName, cfg.SrcChannelID); err != nil {
+		return ibc.PacketTimeout{}, fmt.Errorf("failed to flush packets: %w", err)
+	}
+
+	srcHeight, err := cfg.Src.Height(ctx)
+	if err != nil {
+		return ibc.PacketTimeout{}, fmt.Errorf("failed to get source height: %w", err)
+	}
+	packetTimeout, err := PollForTimeout(ctx, cfg.Src, tx.Height, srcHeight+timeoutPollBlocks, tx.Packet)
+	if err != nil {
+		return ibc.PacketTimeout{}, fmt.Errorf("failed to find timeout on source chain: %w", err)
+	}
+	if err := packetTimeout.Validate(); err != nil {
+		return ibc.PacketTimeout{}, fmt.Errorf("invalid timeout on source chain: %w", err)
+	}
+
+	// Give the refund a block to settle before checking balances.
+	if err := WaitForBlocks(ctx, 1, cfg.Src); err != nil {
+		return ibc.PacketTimeout{}, err
+	}
+
+	after, err := cfg.Src.GetBalance(ctx, cfg.SenderAddress, cfg.Amount.Denom)
+	if err != nil {
+		return ibc.PacketTimeout{}, fmt.Errorf("failed to get sender balance after timeout: %w", err)
+	}
+	want := before
+	if cfg.Amount.Denom == cfg.Src.Config().Denom {
+		want = want.Sub(cfg.Src.GetGasFeesInNativeDenom(tx.GasSpent))
+	}
+	if !after.Equal(want) {
+		return ibc.PacketTimeout{}, fmt.Errorf("sender was not refunded: balance %s%s, want %s%s", after, cfg.Amount.Denom, want, cfg.Amount.Denom)
+	}
+
+	return packetTimeout, nil
+}
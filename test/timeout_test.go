@@ -0,0 +1,132 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	relayermock "github.com/strangelove-ventures/ibctest/v6/relayer/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// timeoutChain is a mock chain that records the last transfer it sent.
+type timeoutChain struct {
+	*mock.Chain
+
+	sent ibc.Packet
+}
+
+func newTimeoutChain(chainID string) *timeoutChain {
+	return &timeoutChain{Chain: mock.NewChain(ibc.ChainConfig{ChainID: chainID, Denom: "uatom", Bech32Prefix: "cosmos"})}
+}
+
+func (c *timeoutChain) SendIBCTransfer(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (ibc.Tx, error) {
+	tx, err := c.Chain.SendIBCTransfer(ctx, channelID, keyName, amount, timeout)
+	c.sent = tx.Packet
+	return tx, err
+}
+
+// flushRelayer is a mock relayer whose flushes time out the last transfer sent by chain,
+// refunding amount to sender if refund is set.
+type flushRelayer struct {
+	*relayermock.Relayer
+
+	chain  *timeoutChain
+	sender string
+	amount math.Int
+	refund bool
+}
+
+func (r *flushRelayer) FlushPackets(ctx context.Context, rep ibc.RelayerExecReporter, pathName, channelID string) error {
+	if err := r.Relayer.FlushPackets(ctx, rep, pathName, channelID); err != nil {
+		return err
+	}
+	height, err := r.chain.Height(ctx)
+	if err != nil {
+		return err
+	}
+	r.chain.AddTimeout(height, ibc.PacketTimeout{Packet: r.chain.sent})
+	if r.refund {
+		balance, err := r.chain.GetBalance(ctx, r.sender, "uatom")
+		if err != nil {
+			return err
+		}
+		r.chain.SetBalance(r.sender, "uatom", balance.Add(r.amount))
+	}
+	return nil
+}
+
+// newFlushRelayer returns a running flushRelayer.
+func newFlushRelayer(t *testing.T, chain *timeoutChain, sender string, amount math.Int, refund bool) *flushRelayer {
+	r := &flushRelayer{Relayer: relayermock.NewRelayer(), chain: chain, sender: sender, amount: amount, refund: refund}
+	require.NoError(t, r.StartRelayer(context.Background(), nil, "p"))
+	return r
+}
+
+func TestTransferTimeout(t *testing.T) {
+	ctx := context.Background()
+	amount := math.NewInt(100)
+
+	for _, tc := range []struct {
+		name   string
+		kind   TimeoutKind
+		refund bool
+	}{
+		{"height refund", HeightTimeout, true},
+		{"timestamp refund", TimestampTimeout, true},
+		{"no refund", HeightTimeout, false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			src, dst := newTimeoutChain("gaia-1"), newTimeoutChain("gaia-2")
+			require.NoError(t, src.CreateKey(ctx, "sender"))
+			addr, err := src.GetAddress(ctx, "sender")
+			require.NoError(t, err)
+			sender := types.MustBech32ifyAddressBytes("cosmos", addr)
+			src.SetBalance(sender, "uatom", math.NewInt(1000))
+			r := newFlushRelayer(t, src, sender, amount, tc.refund)
+
+			timeout, err := TransferTimeout(ctx, TransferTimeoutConfig{
+				Relayer:       r,
+				PathName:      "p",
+				StopRelayer:   true,
+				Src:           src,
+				Dst:           dst,
+				SrcChannelID:  "channel-0",
+				SenderKeyName: "sender",
+				SenderAddress: sender,
+				Amount:        ibc.WalletAmount{Address: "cosmos1receiver", Denom: "uatom", Amount: amount},
+				Kind:          tc.kind,
+				TimeoutPeriod: 1, // Timestamp timeouts are not enforced by the fake chains.
+			})
+			// The relayer is restarted whether or not the timeout succeeded.
+			require.True(t, r.Running())
+			require.Len(t, r.CallsTo("StopRelayer"), 1)
+			if !tc.refund {
+				require.ErrorContains(t, err, "sender was not refunded")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, src.sent, timeout.Packet)
+			balance, err := src.GetBalance(ctx, sender, "uatom")
+			require.NoError(t, err)
+			require.Equal(t, int64(1000), balance.Int64())
+		})
+	}
+}
+
+func TestTransferTimeout_UnknownKind(t *testing.T) {
+	r := newFlushRelayer(t, nil, "", math.ZeroInt(), false)
+	_, err := TransferTimeout(context.Background(), TransferTimeoutConfig{
+		Relayer:     r,
+		StopRelayer: true,
+		Kind:        TimeoutKind(-1),
+	})
+	require.ErrorContains(t, err, "unknown timeout kind -1")
+	// The relayer is not stopped for an invalid config.
+	require.True(t, r.Running())
+	require.Empty(t, r.CallsTo("StopRelayer"))
+}
@@ -0,0 +1,154 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultSequenceRetries is how many times a transfer rejected for an account sequence mismatch is retried.
+const defaultSequenceRetries = 3
+
+// ChainTransferer sends IBC transfers. The ibc.Chain interface satisfies ChainTransferer.
+type ChainTransferer interface {
+	ChainHeighter
+	SendIBCTransfer(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (ibc.Tx, error)
+}
+
+// KeySequencer is implemented by chains that serialize the transactions of each key,
+// and retry those rejected for an account sequence mismatch, such as cosmos.CosmosChain.
+type KeySequencer interface {
+	WithKeySequence(ctx context.Context, keyName string, tx func() error) error
+}
+
+// IBCTransfersConfig describes the transfers sent by SendIBCTransfers.
+type IBCTransfersConfig struct {
+	// Channel that the transfers are sent on.
+	ChannelID string
+
+	// Key names of the sending accounts. Transfer i is sent from KeyNames[i % len(KeyNames)].
+	KeyNames []string
+
+	// Transfers to send; Address is the receiver on the counterparty chain.
+	Amounts []ibc.WalletAmount
+
+	// Optional timeout of every transfer.
+	Timeout *ibc.IBCTimeout
+
+	// Optional. Target number of transfers submitted per second across all accounts.
+	// The target is an upper bound, as an account does not submit its next transfer until the previous one returned.
+	// Zero, or a rate above one transfer per nanosecond, submits transfers as fast as the accounts allow.
+	TPS float64
+
+	// Optional. How many times a transfer rejected for an account sequence mismatch is retried,
+	// after waiting for a block. Defaults to 3.
+	// Ignored for chains implementing KeySequencer, which retry their transfers themselves.
+	MaxSequenceRetries int
+}
+
+// SendIBCTransfers sends many IBC transfers from multiple accounts, paced at the configured rate.
+//
+// Each account submits its transfers one at a time, so that its transactions are never sent
+// with the same sequence, and the accounts submit concurrently to reach the target rate.
+// A transfer rejected for an account sequence mismatch, e.g. because the node had not yet seen
+// the account's previous transaction, is retried after the next block,
+// unless the chain implements KeySequencer and already retries it.
+//
+// It returns one Tx per amount, in the same order, or the first error encountered.
+func SendIBCTransfers(ctx context.Context, chain ChainTransferer, cfg IBCTransfersConfig) ([]ibc.Tx, error) {
+	if len(cfg.KeyNames) == 0 {
+		return nil, errors.New("no sending accounts")
+	}
+	retries := cfg.MaxSequenceRetries
+	if retries == 0 {
+		retries = defaultSequenceRetries
+	}
+	if _, ok := chain.(KeySequencer); ok {
+		retries = 0
+	}
+
+	// Indices of the transfers to send, per account.
+	queues := make([]chan int, len(cfg.KeyNames))
+	for i := range queues {
+		queues[i] = make(chan int)
+	}
+
+	txs := make([]ibc.Tx, len(cfg.Amounts))
+	eg, egCtx := errgroup.WithContext(ctx)
+	for i, keyName := range cfg.KeyNames {
+		queue, keyName := queues[i], keyName
+		eg.Go(func() error {
+			for j := range queue {
+				tx, err := sendIBCTransfer(egCtx, chain, cfg, keyName, cfg.Amounts[j], retries)
+				if err != nil {
+					return fmt.Errorf("transfer %d from %s: %w", j, keyName, err)
+				}
+				txs[j] = tx
+			}
+			return nil
+		})
+	}
+
+	eg.Go(func() error {
+		defer func() {
+			for _, queue := range queues {
+				close(queue)
+			}
+		}()
+
+		var tick <-chan time.Time
+		if cfg.TPS > 0 {
+			// NewTicker panics on intervals that round to zero, so such rates are not paced.
+			if interval := time.Duration(float64(time.Second) / cfg.TPS); interval > 0 {
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				tick = ticker.C
+			}
+		}
+		for j := range cfg.Amounts {
+			if j > 0 && tick != nil {
+				select {
+				case <-egCtx.Done():
+					return egCtx.Err()
+				case <-tick:
+				}
+			}
+			select {
+			case <-egCtx.Done():
+				return egCtx.Err()
+			case queues[j%len(queues)] <- j:
+			}
+		}
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// sendIBCTransfer sends a single transfer, retrying after the next block on account sequence mismatches.
+func sendIBCTransfer(ctx context.Context, chain ChainTransferer, cfg IBCTransfersConfig, keyName string, amount ibc.WalletAmount, retries int) (ibc.Tx, error) {
+	for attempt := 0; ; attempt++ {
+		tx, err := chain.SendIBCTransfer(ctx, cfg.ChannelID, keyName, amount, cfg.Timeout)
+		if err == nil || attempt == retries || !IsSequenceMismatch(err) {
+			return tx, err
+		}
+		if err := WaitForBlocks(ctx, 1, chain); err != nil {
+			return ibc.Tx{}, err
+		}
+	}
+}
+
+// IsSequenceMismatch reports whether err is, or reports through a transaction log,
+// the cosmos-sdk's account sequence mismatch error.
+func IsSequenceMismatch(err error) bool {
+	return errors.Is(err, sdkerrors.ErrWrongSequence) || strings.Contains(err.Error(), sdkerrors.ErrWrongSequence.Error())
+}
@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+// transferChain records the transfers it sends,
+// failing with an account sequence mismatch if an account sends concurrently or as configured.
+type transferChain struct {
+	mu       sync.Mutex
+	height   uint64
+	sending  map[string]bool
+	sent     map[string][]int64
+	mismatch map[string]int
+}
+
+func newTransferChain() *transferChain {
+	return &transferChain{
+		sending:  make(map[string]bool),
+		sent:     make(map[string][]int64),
+		mismatch: make(map[string]int),
+	}
+}
+
+func (c *transferChain) Height(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.height++
+	return c.height, nil
+}
+
+func (c *transferChain) SendIBCTransfer(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (ibc.Tx, error) {
+	c.mu.Lock()
+	if c.sending[keyName] || c.mismatch[keyName] > 0 {
+		c.mismatch[keyName]--
+		c.mu.Unlock()
+		return ibc.Tx{}, errors.New("transaction failed with code 32: account sequence mismatch, expected 2, got 1: incorrect account sequence")
+	}
+	c.sending[keyName] = true
+	c.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sending[keyName] = false
+	c.sent[keyName] = append(c.sent[keyName], amount.Amount.Int64())
+	return ibc.Tx{TxHash: fmt.Sprint(amount.Amount)}, nil
+}
+
+// sequencedTransferChain is a transferChain implementing KeySequencer.
+type sequencedTransferChain struct {
+	*transferChain
+}
+
+func (sequencedTransferChain) WithKeySequence(ctx context.Context, keyName string, tx func() error) error {
+	return tx()
+}
+
+func TestIsSequenceMismatch(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsSequenceMismatch(fmt.Errorf("%w: expected 2, got 1", sdkerrors.ErrWrongSequence)))
+	require.True(t, IsSequenceMismatch(errors.New("transaction failed with code 32: account sequence mismatch, expected 2, got 1: incorrect account sequence")))
+	require.False(t, IsSequenceMismatch(errors.New("insufficient funds")))
+}
+
+func TestSendIBCTransfers(t *testing.T) {
+	t.Parallel()
+
+	amounts := make([]ibc.WalletAmount, 6)
+	for i := range amounts {
+		amounts[i] = ibc.WalletAmount{Address: "cosmos1receiver", Denom: "uatom", Amount: math.NewInt(int64(i))}
+	}
+
+	t.Run("paced across accounts", func(t *testing.T) {
+		chain := newTransferChain()
+		chain.mismatch["b"] = 1
+
+		start := time.Now()
+		txs, err := SendIBCTransfers(context.Background(), chain, IBCTransfersConfig{
+			ChannelID: "channel-0",
+			KeyNames:  []string{"a", "b"},
+			Amounts:   amounts,
+			TPS:       100,
+		})
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+		require.Len(t, txs, len(amounts))
+		for i, tx := range txs {
+			require.Equal(t, fmt.Sprint(i), tx.TxHash)
+		}
+		require.Equal(t, []int64{0, 2, 4}, chain.sent["a"])
+		require.Equal(t, []int64{1, 3, 5}, chain.sent["b"])
+	})
+
+	t.Run("rate too high to pace", func(t *testing.T) {
+		txs, err := SendIBCTransfers(context.Background(), newTransferChain(), IBCTransfersConfig{
+			ChannelID: "channel-0",
+			KeyNames:  []string{"a"},
+			Amounts:   amounts,
+			TPS:       1e12,
+		})
+		require.NoError(t, err)
+		require.Len(t, txs, len(amounts))
+	})
+
+	t.Run("sequence retries exhausted", func(t *testing.T) {
+		chain := newTransferChain()
+		chain.mismatch["a"] = 10
+
+		_, err := SendIBCTransfers(context.Background(), chain, IBCTransfersConfig{
+			ChannelID:          "channel-0",
+			KeyNames:           []string{"a"},
+			Amounts:            amounts,
+			MaxSequenceRetries: 2,
+		})
+		require.ErrorContains(t, err, "transfer 0 from a: transaction failed with code 32")
+		require.Equal(t, 7, chain.mismatch["a"])
+	})
+
+	t.Run("chain retries sequence mismatches", func(t *testing.T) {
+		chain := sequencedTransferChain{newTransferChain()}
+		chain.mismatch["a"] = 10
+
+		_, err := SendIBCTransfers(context.Background(), chain, IBCTransfersConfig{
+			ChannelID: "channel-0",
+			KeyNames:  []string{"a"},
+			Amounts:   amounts,
+		})
+		require.ErrorContains(t, err, "transfer 0 from a: transaction failed with code 32")
+		require.Equal(t, 9, chain.mismatch["a"])
+	})
+
+	t.Run("no accounts", func(t *testing.T) {
+		_, err := SendIBCTransfers(context.Background(), newTransferChain(), IBCTransfersConfig{Amounts: amounts})
+		require.Error(t, err)
+	})
+}
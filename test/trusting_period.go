@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+const (
+	defaultTrustingPeriodHeadroom = 20 * time.Second
+	defaultTrustingPeriodRounds   = 3
+)
+
+// TrustingPeriodEdgeConfig describes the transfers that RelayAtTrustingPeriodEdge relays.
+type TrustingPeriodEdgeConfig struct {
+	Relayer  ibc.Relayer
+	Reporter ibc.RelayerExecReporter
+
+	// Name of the relayer path between Src and Dst.
+	// The relayer must not be relaying the path, so that only the scenario updates its clients.
+	PathName string
+
+	Src, Dst ibc.Chain
+
+	// Channel on Src that the transfers are sent on.
+	SrcChannelID string
+
+	// Key name of the sender on Src.
+	SenderKeyName string
+
+	// Transfer sent in each round; Address is the receiver on Dst.
+	Amount ibc.WalletAmount
+
+	// Trusting period of the path's clients, as created with ClientOptions.
+	// It should be just above the chains' block time plus Headroom, so that clients expire soon after their last update.
+	TrustingPeriod time.Duration
+
+	// Optional. Time left in the trusting period when the relayer relays each transfer,
+	// which must cover relaying the packet and its acknowledgement. Defaults to 20 seconds.
+	Headroom time.Duration
+
+	// Optional. Number of transfers relayed at the edge of the trusting period, one after the other. Defaults to 3.
+	Rounds int
+}
+
+// ClientOptions returns the options to create the path's clients with, e.g. as ibctest.InterchainLink.CreateClientOpts.
+func (cfg TrustingPeriodEdgeConfig) ClientOptions() ibc.CreateClientOptions {
+	return ibc.CreateClientOptions{TrustingPeriod: cfg.TrustingPeriod.String()}
+}
+
+// RelayAtTrustingPeriodEdge checks that packets still relay when the path's clients are about to expire,
+// guarding against off-by-one errors in the trusting period checks of relayers and light clients.
+// In each round, it updates the path's clients, sends a transfer from Src to Dst,
+// holds back further client updates until only Headroom is left of the trusting period,
+// and then relays the transfer and its acknowledgement, which requires updating the clients from their last trusted state.
+//
+// It returns the acknowledgement of each round's transfer, or an error unless every transfer was acknowledged with a success.
+func RelayAtTrustingPeriodEdge(ctx context.Context, cfg TrustingPeriodEdgeConfig) ([]ibc.PacketAcknowledgement, error) {
+	headroom := cfg.Headroom
+	if headroom == 0 {
+		headroom = defaultTrustingPeriodHeadroom
+	}
+	rounds := cfg.Rounds
+	if rounds == 0 {
+		rounds = defaultTrustingPeriodRounds
+	}
+	if cfg.TrustingPeriod <= headroom {
+		return nil, fmt.Errorf("trusting period %s must exceed the headroom %s", cfg.TrustingPeriod, headroom)
+	}
+
+	acks := make([]ibc.PacketAcknowledgement, 0, rounds)
+	for i := 0; i < rounds; i++ {
+		ack, err := cfg.relayAtEdge(ctx, cfg.TrustingPeriod-headroom)
+		if err != nil {
+			return acks, fmt.Errorf("round %d: %w", i, err)
+		}
+		acks = append(acks, ack)
+	}
+	return acks, nil
+}
+
+// relayAtEdge updates the path's clients, sends a transfer, and relays it once delay has passed since the update.
+func (cfg TrustingPeriodEdgeConfig) relayAtEdge(ctx context.Context, delay time.Duration) (ibc.PacketAcknowledgement, error) {
+	if err := cfg.Relayer.UpdateClients(ctx, cfg.Reporter, cfg.PathName); err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("failed to update clients: %w", err)
+	}
+	// The clients' latest consensus states are no newer than the update, so they expire no later than TrustingPeriod from now.
+	edge := time.Now().Add(delay)
+
+	tx, err := cfg.Src.SendIBCTransfer(ctx, cfg.SrcChannelID, cfg.SenderKeyName, cfg.Amount, nil)
+	if err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("failed to send transfer: %w", err)
+	}
+	if err := tx.Validate(); err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("invalid transfer tx: %w", err)
+	}
+
+	wait := time.Until(edge)
+	if wait <= 0 {
+		return ibc.PacketAcknowledgement{}, errors.New("transfer was sent after the edge of the trusting period; increase the trusting period")
+	}
+	select {
+	case <-ctx.Done():
+		return ibc.PacketAcknowledgement{}, ctx.Err()
+	case <-time.After(wait):
+	}
+
+	ack, err := flushTransfer(ctx, cfg.Relayer, cfg.Reporter, cfg.PathName, cfg.SrcChannelID, cfg.Src, cfg.Dst, tx)
+	if err != nil {
+		return ibc.PacketAcknowledgement{}, fmt.Errorf("relaying at the edge of the trusting period: %w", err)
+	}
+	if err := checkSuccessAck(ack); err != nil {
+		return ibc.PacketAcknowledgement{}, err
+	}
+	return ack, nil
+}
@@ -0,0 +1,24 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustingPeriodEdgeConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := TrustingPeriodEdgeConfig{TrustingPeriod: 30 * time.Second}
+	opts := cfg.ClientOptions()
+	require.Equal(t, "30s", opts.TrustingPeriod)
+	require.NoError(t, opts.Validate())
+
+	_, err := RelayAtTrustingPeriodEdge(context.Background(), TrustingPeriodEdgeConfig{TrustingPeriod: 20 * time.Second})
+	require.EqualError(t, err, "trusting period 20s must exceed the headroom 20s")
+
+	_, err = RelayAtTrustingPeriodEdge(context.Background(), TrustingPeriodEdgeConfig{TrustingPeriod: 5 * time.Second, Headroom: 10 * time.Second})
+	require.EqualError(t, err, "trusting period 5s must exceed the headroom 10s")
+}
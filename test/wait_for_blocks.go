@@ -3,7 +3,9 @@ package test
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -12,8 +14,13 @@ type ChainHeighter interface {
 	Height(ctx context.Context) (uint64, error)
 }
 
+// healthCheckInterval is how often WaitForBlocks checks the health of chains implementing ibc.HealthChecker.
+const healthCheckInterval = time.Second
+
 // WaitForBlocks blocks until all chains reach a block height delta equal to or greater than the delta argument.
 // If a ChainHeighter does not monotonically increase the height, this function may block program execution indefinitely.
+// If a ChainHeighter also implements ibc.HealthChecker, WaitForBlocks returns early once that chain reports it is not live,
+// and an error caused by ctx ending includes whether the chain was merely slow, i.e. live but not ready, or broken.
 func WaitForBlocks(ctx context.Context, delta int, chains ...ChainHeighter) error {
 	if len(chains) == 0 {
 		panic("missing chains")
@@ -84,9 +91,20 @@ type height struct {
 }
 
 func (h *height) WaitForDelta(ctx context.Context, delta int) error {
+	hc, checksHealth := h.Chain.(ibc.HealthChecker)
+	var lastHealthCheck time.Time
 	for h.delta() < delta {
+		if checksHealth && time.Since(lastHealthCheck) >= healthCheckInterval {
+			lastHealthCheck = time.Now()
+			if status, err := hc.HealthCheck(ctx); err == nil && !status.Live {
+				return fmt.Errorf("chain is %s", status)
+			}
+		}
 		cur, err := h.Chain.Height(ctx)
 		if err != nil {
+			if checksHealth && ctx.Err() != nil {
+				return fmt.Errorf("%w; chain was %s", err, lastHealth(hc))
+			}
 			return err
 		}
 		// We assume the chain will eventually return a non-zero height, otherwise
@@ -99,6 +117,17 @@ func (h *height) WaitForDelta(ctx context.Context, delta int) error {
 	return nil
 }
 
+// lastHealth describes the health of a chain after the context of a wait ended.
+func lastHealth(hc ibc.HealthChecker) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := hc.HealthCheck(ctx)
+	if err != nil {
+		return "of unknown health: " + err.Error()
+	}
+	return status.String()
+}
+
 func (h *height) delta() int {
 	if h.starting == 0 {
 		return 0
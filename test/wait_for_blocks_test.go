@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,6 +36,22 @@ func (m *mockChainHeighterFixed) Height(ctx context.Context) (uint64, error) {
 	return uint64(m.CurHeight), m.Err
 }
 
+// mockHealthChainHeighter is stuck at a fixed height, reporting Status as its health.
+type mockHealthChainHeighter struct {
+	Status ibc.HealthStatus
+}
+
+func (m *mockHealthChainHeighter) Height(ctx context.Context) (uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return 10, nil
+}
+
+func (m *mockHealthChainHeighter) HealthCheck(ctx context.Context) (ibc.HealthStatus, error) {
+	return m.Status, nil
+}
+
 func TestWaitForBlocks(t *testing.T) {
 	t.Parallel()
 
@@ -80,6 +97,23 @@ func TestWaitForBlocks(t *testing.T) {
 		// Because 0 is always invalid height, we do not start testing for the delta until height > 0.
 		require.EqualValues(t, 2, chain.CurHeight)
 	})
+
+	t.Run("not live", func(t *testing.T) {
+		chain := &mockHealthChainHeighter{Status: ibc.HealthStatus{Reason: "container exited"}}
+		err := WaitForBlocks(context.Background(), 1, chain)
+
+		require.EqualError(t, err, "chain is not live: container exited")
+	})
+
+	t.Run("slow", func(t *testing.T) {
+		chain := &mockHealthChainHeighter{Status: ibc.HealthStatus{Live: true, Reason: "node val-0 is catching up at height 10"}}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := WaitForBlocks(ctx, 1, chain)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "chain was live but not ready: node val-0 is catching up at height 10")
+	})
 }
 
 func TestWaitForInSync(t *testing.T) {
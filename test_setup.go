@@ -30,11 +30,145 @@ func KeepDockerVolumesOnFailure(b bool) {
 	dockerutil.KeepVolumesOnFailure = b
 }
 
+// ImagePullMode controls how chain and relayer images are pulled.
+type ImagePullMode = dockerutil.PullMode
+
+const (
+	// ImagePullModeDefault pulls every image, falling back to a local copy if the pull fails.
+	ImagePullModeDefault = dockerutil.PullModeDefault
+
+	// ImagePullModeStrict pulls every image and fails immediately on any pull error.
+	ImagePullModeStrict = dockerutil.PullModeStrict
+
+	// ImagePullModeOffline never pulls images and requires them to be present locally.
+	ImagePullModeOffline = dockerutil.PullModeOffline
+)
+
+// SetImagePullMode sets how images are pulled when chains and relayers are initialized.
+//
+// The value is ImagePullModeDefault by default, but can be initialized by setting the
+// environment variable IBCTEST_IMAGE_PULL_MODE to "default", "strict", or "offline".
+// Alternatively, importers of the ibctest package may call SetImagePullMode.
+func SetImagePullMode(mode ImagePullMode) {
+	dockerutil.ImagePullMode = mode
+}
+
+// SetMaxContainerRestarts sets how many times a chain node container that exits unexpectedly,
+// e.g. after running out of memory, is restarted with the same volume
+// before the chain reports the exit as a fatal error, failing waits for blocks.
+//
+// The value is 0 by default, so that any unexpected exit is fatal, but can be initialized by setting the
+// environment variable IBCTEST_MAX_CONTAINER_RESTARTS.
+// Alternatively, importers of the ibctest package may call SetMaxContainerRestarts.
+// The value applies to chains initialized after the call.
+// While the value is negative, or the environment variable is invalid, chains fail to initialize.
+func SetMaxContainerRestarts(n int) {
+	dockerutil.MaxContainerRestarts = n
+}
+
+// SetStopGracePeriod sets how long stopping a chain node or relayer container waits for it to exit
+// after sending it SIGTERM, before killing it.
+// Stopping a container that runs out of memory, or that exits with an error code, fails,
+// as its database may be corrupted for later phases such as exporting state.
+//
+// The value is 30 seconds by default, but can be initialized by setting the
+// environment variable IBCTEST_STOP_GRACE_PERIOD to a duration such as "1m".
+// Alternatively, importers of the ibctest package may call SetStopGracePeriod.
+// A zero or negative d restores the default.
+// While the environment variable is invalid, and SetStopGracePeriod has not been called, DockerSetup fails the test.
+func SetStopGracePeriod(d time.Duration) {
+	if d <= 0 {
+		d = dockerutil.DefaultStopGracePeriod
+	}
+	dockerutil.StopGracePeriod = d
+}
+
+// SetDiskBudget sets the maximum number of bytes that the docker volumes of a single test may use.
+// Interchain.Build fails if the volumes exceed the budget after starting the chains,
+// configuring relayer keys, or linking paths,
+// and tests may call CheckDiskBudget between phases to fail early with a clear error.
+//
+// The value is 0 by default, disabling the check, but can be initialized by setting the
+// environment variable IBCTEST_DISK_BUDGET_MB to a number of megabytes.
+// Alternatively, importers of the ibctest package may call SetDiskBudget.
+// While the budget is negative, or the environment variable is invalid, DockerSetup fails the test.
+func SetDiskBudget(bytes int64) {
+	dockerutil.DiskBudget = bytes
+}
+
+// PruneFinishedTestVolumes sets whether DockerSetup removes unused docker volumes
+// left behind by tests that are no longer running, such as volumes kept by KeepDockerVolumesOnFailure.
+// Only enable this when a single test process uses the docker host,
+// as volumes of tests running in other processes may not be in use by a container yet.
+//
+// The value is false by default, but can be initialized to true by setting the
+// environment variable IBCTEST_PRUNE_FINISHED_VOLUMES to a non-empty value.
+// Alternatively, importers of the ibctest package may call PruneFinishedTestVolumes(true).
+func PruneFinishedTestVolumes(b bool) {
+	dockerutil.PruneFinishedTestVolumes = b
+}
+
+// VolumeUsage is the disk usage of a docker volume created for a test.
+type VolumeUsage = dockerutil.VolumeUsage
+
+// TestVolumeUsage returns the disk usage of the docker volumes created for the test t, largest first,
+// and their total size in bytes.
+func TestVolumeUsage(ctx context.Context, t testing.TB, cli *client.Client) ([]VolumeUsage, int64, error) {
+	return dockerutil.TestVolumeUsage(ctx, cli, t.Name())
+}
+
+// CheckDiskBudget returns an error describing the largest volumes
+// if the docker volumes created for the test t use more than the budget set by SetDiskBudget.
+func CheckDiskBudget(ctx context.Context, t testing.TB, cli *client.Client) error {
+	return dockerutil.CheckDiskBudget(ctx, cli, t.Name())
+}
+
+// SetDockerLabels sets labels, such as {"team": "ibc", "run-id": "1234"}, that are added to every container, volume,
+// and network created for tests, to attribute docker resources on shared hosts. See ListDockerResources.
+// It returns an error without changing the labels if a key is empty or is a label that ibctest sets itself.
+//
+// The labels are empty by default, but can be initialized by setting the environment variable
+// IBCTEST_DOCKER_LABELS to comma separated key=value pairs, such as "team=ibc,run-id=1234".
+// If the variable is invalid, DockerSetup fails until SetDockerLabels is called.
+// The labels apply to resources created after the call.
+func SetDockerLabels(labels map[string]string) error {
+	return dockerutil.SetResourceLabels(labels)
+}
+
+// DockerResources are the names of the docker resources with a label, as listed by ListDockerResources.
+type DockerResources = dockerutil.Resources
+
+// ListDockerResources lists the containers, volumes, and networks with label,
+// either a key such as "team", or a key and value such as "run-id=1234",
+// e.g. to attribute resources on a shared docker host, or to reap the resources of a crashed CI run.
+func ListDockerResources(ctx context.Context, cli *client.Client, label string) (DockerResources, error) {
+	return dockerutil.ListResources(ctx, cli, label)
+}
+
+// DockerError is a common docker failure, such as a full disk, with a hint for fixing it.
+// It is returned, possibly wrapped, by the Initialize method of the built-in chains,
+// and its message, including the hint, is reported by DockerSetup when it fails the test.
+type DockerError = dockerutil.DiagnosedError
+
+// DockerFailureKind is the cause of a DockerError.
+type DockerFailureKind = dockerutil.FailureKind
+
+const (
+	DockerFailureDaemonUnreachable = dockerutil.FailureDaemonUnreachable
+	DockerFailurePermissionDenied  = dockerutil.FailurePermissionDenied
+	DockerFailureOutOfDisk         = dockerutil.FailureOutOfDisk
+	DockerFailurePortConflict      = dockerutil.FailurePortConflict
+	DockerFailurePlatformMismatch  = dockerutil.FailurePlatformMismatch
+)
+
 // DockerSetup returns a new Docker Client and the ID of a configured network, associated with t.
 //
 // If any part of the setup fails, t.Fatal is called.
-func DockerSetup(t *testing.T) (*client.Client, string) {
+func DockerSetup(t testing.TB) (*client.Client, string) {
 	t.Helper()
+	if err := mnemonicSeedError(); err != nil {
+		t.Fatalf("Invalid mnemonic seed: %v", err)
+	}
 	return dockerutil.DockerSetup(t)
 }
 
@@ -44,7 +178,7 @@ func DockerSetup(t *testing.T) (*client.Client, string) {
 // creates a faucet account on the both chains (separate fullnode)
 // funds faucet accounts in genesis
 func StartChainPair(
-	t *testing.T,
+	t testing.TB,
 	ctx context.Context,
 	rep *testreporter.Reporter,
 	cli *client.Client,
@@ -90,7 +224,7 @@ func StartChainPair(
 // then execute the preRelayerStartFuncs and wait for all to complete before starting
 // the relayer.
 func StopStartRelayerWithPreStartFuncs(
-	t *testing.T,
+	t testing.TB,
 	ctx context.Context,
 	srcChainID string,
 	relayerImpl ibc.Relayer,
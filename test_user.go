@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"testing"
 
+	"cosmossdk.io/math"
 	"github.com/strangelove-ventures/ibctest/v6/ibc"
 	"github.com/strangelove-ventures/ibctest/v6/internal/dockerutil"
-	"github.com/strangelove-ventures/ibctest/v6/test"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
 )
@@ -41,7 +41,7 @@ func generateUserWallet(ctx context.Context, keyName, mnemonic string, chain ibc
 func GetAndFundTestUserWithMnemonic(
 	ctx context.Context,
 	keyNamePrefix, mnemonic string,
-	amount int64,
+	amount math.Int,
 	chain ibc.Chain,
 ) (*ibc.Wallet, error) {
 	chainCfg := chain.Config()
@@ -52,7 +52,7 @@ func GetAndFundTestUserWithMnemonic(
 	}
 
 	err = chain.SendFunds(ctx, FaucetAccountKeyName, ibc.WalletAmount{
-		Address: user.Bech32Address(chainCfg.Bech32Prefix),
+		Address: user.ChainAddress(chainCfg),
 		Amount:  amount,
 		Denom:   chainCfg.Denom,
 	})
@@ -63,21 +63,93 @@ func GetAndFundTestUserWithMnemonic(
 }
 
 // GetAndFundTestUsers generates and funds chain users with the native chain denom.
+// If a mnemonic seed is set with SetMnemonicSeed, the users' mnemonics are derived from it.
 // The caller should wait for some blocks to complete before the funds will be accessible.
 func GetAndFundTestUsers(
-	t *testing.T,
+	t testing.TB,
 	ctx context.Context,
 	keyNamePrefix string,
-	amount int64,
+	amount math.Int,
 	chains ...ibc.Chain,
 ) []*ibc.Wallet {
+	amounts := make([]math.Int, len(chains))
+	for i := range chains {
+		amounts[i] = amount
+	}
+	return getAndFundTestUsers(t, ctx, keyNamePrefix, amounts, chains)
+}
+
+// TestUser is a funded user of a chain.
+type TestUser struct {
+	*ibc.Wallet
+
+	// Chain the user was funded on.
+	Chain ibc.Chain
+
+	// Amount of the chain's native denom the user was funded with, in base units.
+	Funds math.Int
+}
+
+// FormattedAddress returns the address of the user in the address format of its chain,
+// e.g. bech32 for cosmos chains and SS58 for substrate chains.
+func (u TestUser) FormattedAddress() string {
+	return u.ChainAddress(u.Chain.Config())
+}
+
+// GetAndFundTestUsersDisplay generates and funds users on chains of any type,
+// e.g. cosmos and polkadot chains in a single call.
+// displayAmount is an amount of each chain's native denom in display units, e.g. "1.5",
+// which is converted to the base units of each chain according to its decimals, see ibc.ToBaseUnits.
+// If a mnemonic seed is set with SetMnemonicSeed, the users' mnemonics are derived from it.
+// The caller should wait for some blocks to complete before the funds will be accessible.
+func GetAndFundTestUsersDisplay(
+	t testing.TB,
+	ctx context.Context,
+	keyNamePrefix string,
+	displayAmount string,
+	chains ...ibc.Chain,
+) []TestUser {
+	amounts := make([]math.Int, len(chains))
+	for i, chain := range chains {
+		amount, err := ibc.ToBaseUnits(chain, displayAmount)
+		require.NoError(t, err, "chain %s", chain.Config().ChainID)
+		amounts[i] = amount
+	}
+
+	wallets := getAndFundTestUsers(t, ctx, keyNamePrefix, amounts, chains)
+	users := make([]TestUser, len(chains))
+	for i, chain := range chains {
+		users[i] = TestUser{Wallet: wallets[i], Chain: chain, Funds: amounts[i]}
+	}
+	return users
+}
+
+// getAndFundTestUsers generates a user on each chain and funds it with the amount of the same index.
+func getAndFundTestUsers(
+	t testing.TB,
+	ctx context.Context,
+	keyNamePrefix string,
+	amounts []math.Int,
+	chains []ibc.Chain,
+) []*ibc.Wallet {
+	// Derive mnemonics before starting goroutines, so the derivation order is deterministic.
+	mnemonics := make([]string, len(chains))
+	for i, chain := range chains {
+		mnemonic, err := deriveMnemonic(t.Name(), "user", chain.Config().ChainID)
+		require.NoError(t, err)
+		mnemonics[i] = mnemonic
+	}
+	if seed := MnemonicSeed(); seed != "" {
+		t.Logf("Derived test user mnemonics from master seed; reproduce with IBCTEST_MNEMONIC_SEED=%s", seed)
+	}
+
 	users := make([]*ibc.Wallet, len(chains))
 	var eg errgroup.Group
 	for i, chain := range chains {
 		i := i
 		chain := chain
 		eg.Go(func() error {
-			user, err := GetAndFundTestUserWithMnemonic(ctx, keyNamePrefix, "", amount, chain)
+			user, err := GetAndFundTestUserWithMnemonic(ctx, keyNamePrefix, mnemonics[i], amounts[i], chain)
 			if err != nil {
 				return err
 			}
@@ -86,11 +158,27 @@ func GetAndFundTestUsers(
 		})
 	}
 	require.NoError(t, eg.Wait())
+	return users
+}
 
-	// TODO(nix 05-17-2022): Map with generics once using go 1.18
-	chainHeights := make([]test.ChainHeighter, len(chains))
-	for i := range chains {
-		chainHeights[i] = chains[i]
+// FundFromFaucet sends each amount from the chain's faucet account to the amount's address.
+// The faucet holds every genesis denom of the chain, i.e. its Denom, BondDenom, and AdditionalGenesisDenoms,
+// so an amount in any other denom is an error.
+// The caller should wait for some blocks to complete before the funds will be accessible.
+func FundFromFaucet(ctx context.Context, chain ibc.Chain, amounts ...ibc.WalletAmount) error {
+	chainCfg := chain.Config()
+	genesisDenoms := chainCfg.GenesisDenoms()
+	held := make(map[string]bool, len(genesisDenoms))
+	for _, d := range genesisDenoms {
+		held[d] = true
 	}
-	return users
+	for _, amount := range amounts {
+		if !held[amount.Denom] {
+			return fmt.Errorf("faucet of chain %s does not hold denom %s (genesis denoms: %v)", chainCfg.ChainID, amount.Denom, genesisDenoms)
+		}
+		if err := chain.SendFunds(ctx, FaucetAccountKeyName, amount); err != nil {
+			return fmt.Errorf("failed to send %s%s from faucet to %s: %w", amount.Amount, amount.Denom, amount.Address, err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,70 @@
+package ibctest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/stretchr/testify/require"
+)
+
+// fundChain is a mock chain whose keys all have address,
+// and which records the funds sent from its faucet.
+type fundChain struct {
+	*mock.Chain
+
+	address string
+
+	mu   sync.Mutex
+	sent []ibc.WalletAmount
+}
+
+func newFundChain(cfg ibc.ChainConfig, address string) *fundChain {
+	return &fundChain{Chain: mock.NewChain(cfg), address: address}
+}
+
+func (c *fundChain) GetAddress(ctx context.Context, keyName string) ([]byte, error) {
+	if _, err := c.Chain.GetAddress(ctx, keyName); err != nil {
+		return nil, err
+	}
+	return []byte(c.address), nil
+}
+
+func (c *fundChain) SendFunds(ctx context.Context, keyName string, amount ibc.WalletAmount) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, amount)
+	return nil
+}
+
+func TestGetAndFundTestUsersDisplay(t *testing.T) {
+	t.Parallel()
+
+	gaia := newFundChain(
+		ibc.ChainConfig{Type: "cosmos", ChainID: "gaia-1", Bech32Prefix: "cosmos", Denom: "uatom"},
+		string(make([]byte, 20)),
+	)
+	relay := newFundChain(
+		ibc.ChainConfig{Type: "polkadot", ChainID: "rococo-local", Denom: "uDOT"},
+		"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+	)
+
+	users := GetAndFundTestUsersDisplay(t, context.Background(), "user", "2.5", gaia, relay)
+	require.Len(t, users, 2)
+
+	require.Equal(t, gaia, users[0].Chain)
+	require.Equal(t, "2500000", users[0].Funds.String())
+	require.Equal(t, "cosmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqnrql8a", users[0].FormattedAddress())
+	require.Equal(t, []ibc.WalletAmount{
+		{Address: users[0].FormattedAddress(), Denom: "uatom", Amount: users[0].Funds},
+	}, gaia.sent)
+
+	require.Equal(t, relay, users[1].Chain)
+	require.Equal(t, "2500000000000", users[1].Funds.String())
+	require.Equal(t, relay.address, users[1].FormattedAddress())
+	require.Equal(t, []ibc.WalletAmount{
+		{Address: relay.address, Denom: "uDOT", Amount: users[1].Funds},
+	}, relay.sent)
+}
@@ -72,4 +72,9 @@
 //
 // If you use a plain require.NoError(t, err) call,
 // the report will note that the test failed, but the report will not include the error line.
+//
+// Finally, (*ibctest.Interchain).Build passes its RelayerExecReporter to chains and docker helpers
+// through the context, so the report includes a "TimingMessage" for each setup phase,
+// such as image pulls, container starts, and relayer handshakes,
+// and a "TimingSummaryMessage" with the total duration per phase just before the test finishes.
 package testreporter
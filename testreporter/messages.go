@@ -19,9 +19,9 @@ type Message interface {
 type BeginSuiteMessage struct {
 	StartedAt time.Time
 
-	// TODO: it would be nice to embed the ibctest commit in this message,
-	// but while https://github.com/golang/go/issues/33976 is outstanding,
-	// we'll have to fall back to ldflags to embed it.
+	// Version of ibctest running the suite: the git commit embedded through ldflags by the Makefile,
+	// or else the module version or VCS revision from the executable's build info.
+	FrameworkVersion string `json:",omitempty"`
 }
 
 func (m BeginSuiteMessage) typ() string {
@@ -144,6 +144,113 @@ func (m RelayerExecMessage) typ() string {
 	return "RelayerExec"
 }
 
+// ChainConfigMessage records the effective configuration of a started chain,
+// so that a failing run can be reproduced with the exact same images.
+type ChainConfigMessage struct {
+	Name string // Test name, but "Name" for consistency.
+
+	ChainType    string
+	ChainName    string
+	ChainID      string
+	Denom        string
+	Bech32Prefix string
+
+	Images []Image
+
+	HostRPCAddress  string `json:",omitempty"`
+	HostGRPCAddress string `json:",omitempty"`
+}
+
+// Image is a docker image run by a test, as reported in a ChainConfigMessage or RelayerConfigMessage.
+type Image struct {
+	// Image reference, e.g. "ghcr.io/strangelove-ventures/heighliner/gaia:v7.0.0".
+	Ref string
+
+	// Repository digest of the image, or the image ID of a locally built image.
+	Digest string
+}
+
+func (m ChainConfigMessage) typ() string {
+	return "ChainConfig"
+}
+
+// RelayerConfigMessage records the relayer image used by a test,
+// so that results are traceable to the exact relayer build, including custom images.
+type RelayerConfigMessage struct {
+	Name string // Test name, but "Name" for consistency.
+
+	// Name of the relayer instance in the test's Interchain.
+	RelayerName string
+
+	Image Image
+}
+
+func (m RelayerConfigMessage) typ() string {
+	return "RelayerConfig"
+}
+
+// NodeCommandMessage records the command that started a chain node container,
+// including any modifications through ibc.ChainConfig.ModifyStartCommand.
+// Messages are tracked through TrackNodeCommand with a context from WithTimingTracker.
+type NodeCommandMessage struct {
+	Name string // Test name, but "Name" for consistency.
+
+	ContainerName string
+
+	Command []string
+	Env     []string `json:",omitempty"`
+}
+
+func (m NodeCommandMessage) typ() string {
+	return "NodeCommand"
+}
+
+// TimingMessage records how long one phase of test setup took,
+// such as pulling an image or completing a relayer handshake.
+// Messages are tracked through TrackTiming with a context from WithTimingTracker.
+type TimingMessage struct {
+	Name string // Test name, but "Name" for consistency.
+
+	Phase   string
+	Subject string `json:",omitempty"`
+
+	StartedAt, FinishedAt time.Time
+}
+
+func (m TimingMessage) typ() string {
+	return "Timing"
+}
+
+// PhaseStartMessage records that a phase of test setup started, to follow the progress of setup;
+// its TimingMessage follows once the phase completes.
+// Messages are tracked through StartPhase with a context from WithTimingTracker.
+type PhaseStartMessage struct {
+	Name string // Test name, but "Name" for consistency.
+
+	Phase   string
+	Subject string `json:",omitempty"`
+
+	StartedAt time.Time
+}
+
+func (m PhaseStartMessage) typ() string {
+	return "PhaseStart"
+}
+
+// TimingSummaryMessage is tracked just before the FinishTestMessage of a test that tracked any timings.
+// Phases holds the total duration of each phase.
+// Phases for different subjects may run concurrently,
+// so the totals may exceed the test's wall time.
+type TimingSummaryMessage struct {
+	Name string
+
+	Phases map[string]time.Duration
+}
+
+func (m TimingSummaryMessage) typ() string {
+	return "TimingSummary"
+}
+
 // WrappedMessage wraps a Message with an outer Type field
 // so that decoders can determine the underlying message's type.
 type WrappedMessage struct {
@@ -210,6 +317,30 @@ func (m *WrappedMessage) UnmarshalJSON(b []byte) error {
 		x := RelayerExecMessage{}
 		err = json.Unmarshal(raw, &x)
 		msg = x
+	case "ChainConfig":
+		x := ChainConfigMessage{}
+		err = json.Unmarshal(raw, &x)
+		msg = x
+	case "RelayerConfig":
+		x := RelayerConfigMessage{}
+		err = json.Unmarshal(raw, &x)
+		msg = x
+	case "NodeCommand":
+		x := NodeCommandMessage{}
+		err = json.Unmarshal(raw, &x)
+		msg = x
+	case "Timing":
+		x := TimingMessage{}
+		err = json.Unmarshal(raw, &x)
+		msg = x
+	case "PhaseStart":
+		x := PhaseStartMessage{}
+		err = json.Unmarshal(raw, &x)
+		msg = x
+	case "TimingSummary":
+		x := TimingSummaryMessage{}
+		err = json.Unmarshal(raw, &x)
+		msg = x
 	default:
 		return fmt.Errorf("unknown message type %q", outer.Type)
 	}
@@ -45,6 +45,58 @@ func TestWrappedMessage_RoundTrip(t *testing.T) {
 				Error:         "",
 			},
 		},
+		{
+			Message: testreporter.ChainConfigMessage{
+				Name:         "foo",
+				ChainType:    "cosmos",
+				ChainName:    "gaia",
+				ChainID:      "gaia-1",
+				Denom:        "uatom",
+				Bech32Prefix: "cosmos",
+				Images: []testreporter.Image{
+					{Ref: "ghcr.io/strangelove-ventures/heighliner/gaia:v7.0.0", Digest: "ghcr.io/strangelove-ventures/heighliner/gaia@sha256:abcd"},
+				},
+				HostRPCAddress: "localhost:49153",
+			},
+		},
+		{
+			Message: testreporter.RelayerConfigMessage{
+				Name:        "foo",
+				RelayerName: "r",
+				Image:       testreporter.Image{Ref: "ghcr.io/cosmos/relayer:v2.1.2", Digest: "ghcr.io/cosmos/relayer@sha256:abcd"},
+			},
+		},
+		{
+			Message: testreporter.NodeCommandMessage{
+				Name:          "foo",
+				ContainerName: "gaia-1-val-0-foo",
+				Command:       []string{"sh", "-c", "exec gaiad start"},
+				Env:           []string{"GODEBUG=gctrace=1"},
+			},
+		},
+		{
+			Message: testreporter.TimingMessage{
+				Name:       "foo",
+				Phase:      testreporter.PhaseImagePull,
+				Subject:    "ghcr.io/strangelove-ventures/heighliner/gaia:v7.0.0",
+				StartedAt:  time.Now(),
+				FinishedAt: time.Now().Add(time.Second),
+			},
+		},
+		{
+			Message: testreporter.PhaseStartMessage{
+				Name:      "foo",
+				Phase:     testreporter.PhaseChainspec,
+				Subject:   "rococo-local",
+				StartedAt: time.Now(),
+			},
+		},
+		{
+			Message: testreporter.TimingSummaryMessage{
+				Name:   "foo",
+				Phases: map[string]time.Duration{testreporter.PhaseImagePull: time.Second},
+			},
+		},
 	}
 
 	for _, tc := range tcs {
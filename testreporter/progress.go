@@ -0,0 +1,121 @@
+package testreporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ProgressEvent reports that a phase of test setup for a subject started or completed.
+type ProgressEvent struct {
+	Phase   string
+	Subject string
+
+	// Completed is false when the phase starts, and true once it completes.
+	Completed bool
+
+	// When the phase started, and, once it completed, how long it took.
+	StartedAt time.Time
+	Duration  time.Duration
+}
+
+// ProgressFunc receives the ProgressEvents of test setup.
+// It is called from the goroutines running setup, so it must be safe for concurrent use and return quickly.
+type ProgressFunc func(ProgressEvent)
+
+type progressKey struct{}
+
+// WithProgress returns a context that carries fn in addition to the ProgressFuncs already carried by ctx,
+// so that phases tracked with StartPhase or TrackTiming deep in chain and docker setup are reported to fn.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	fns, _ := ctx.Value(progressKey{}).([]ProgressFunc)
+	return context.WithValue(ctx, progressKey{}, append(fns[:len(fns):len(fns)], fn))
+}
+
+// reportProgress calls the ProgressFuncs carried by ctx with ev.
+func reportProgress(ctx context.Context, ev ProgressEvent) {
+	fns, _ := ctx.Value(progressKey{}).([]ProgressFunc)
+	for _, fn := range fns {
+		fn(ev)
+	}
+}
+
+// PhaseStartTracker tracks when a phase of test setup starts.
+// The RelayerExecReporter satisfies PhaseStartTracker.
+type PhaseStartTracker interface {
+	TrackPhaseStart(phase, subject string, startedAt time.Time)
+}
+
+// StartPhase reports that the phase for subject, such as an image ref or a chain ID, starts now,
+// to the ProgressFuncs carried by ctx and to its TimingTracker, if that is also a PhaseStartTracker.
+// It returns a function that reports that the phase completed, as by TrackTiming.
+//
+// The typical usage is deferred at the start of the phase:
+//
+//	defer testreporter.StartPhase(ctx, testreporter.PhaseImagePull, ref)()
+func StartPhase(ctx context.Context, phase, subject string) (done func()) {
+	startedAt := time.Now()
+	if tr, ok := ctx.Value(timingTrackerKey{}).(PhaseStartTracker); ok {
+		tr.TrackPhaseStart(phase, subject, startedAt)
+	}
+	reportProgress(ctx, ProgressEvent{Phase: phase, Subject: subject, StartedAt: startedAt})
+	return func() {
+		TrackTiming(ctx, phase, subject, startedAt)
+	}
+}
+
+// TrackPhaseStart tracks when a phase of setup starts for subject.
+func (r *RelayerExecReporter) TrackPhaseStart(phase, subject string, startedAt time.Time) {
+	r.r.in <- PhaseStartMessage{
+		Name:      r.testName,
+		Phase:     phase,
+		Subject:   subject,
+		StartedAt: startedAt,
+	}
+}
+
+// ProgressBar returns a ProgressFunc that renders the progress of test setup on w, typically a terminal,
+// as a single line that is redrawn on each event, showing how many phases have completed and the latest event.
+func ProgressBar(w io.Writer) ProgressFunc {
+	var (
+		mu                 sync.Mutex
+		started, completed int
+	)
+	return func(ev ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		status := "started"
+		if ev.Completed {
+			completed++
+			status = "completed in " + ev.Duration.Round(time.Millisecond).String()
+		} else {
+			started++
+		}
+		// Phases tracked only on completion were never reported as started.
+		total := started
+		if completed > total {
+			total = completed
+		}
+		fmt.Fprintf(w, "\r\033[K%s %d/%d %s %s %s", progressBar(completed, total, 20), completed, total, ev.Phase, ev.Subject, status)
+	}
+}
+
+// progressBar draws a bar of width characters, filled in proportion to done out of total.
+func progressBar(done, total, width int) string {
+	filled := width
+	if total > 0 {
+		filled = done * width / total
+	}
+	bar := make([]byte, width)
+	for i := range bar {
+		if i < filled {
+			bar[i] = '#'
+		} else {
+			bar[i] = '-'
+		}
+	}
+	return "[" + string(bar) + "]"
+}
@@ -4,11 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"runtime/debug"
+	"sync"
 	"time"
 
+	"github.com/strangelove-ventures/ibctest/v6/internal/version"
 	"github.com/strangelove-ventures/ibctest/v6/label"
 )
 
+const modulePath = "github.com/strangelove-ventures/ibctest/v6"
+
 // T is a subset of testing.TB,
 // representing only the methods required by the reporter.
 type T interface {
@@ -23,6 +28,11 @@ type T interface {
 	Skipped() bool
 }
 
+// NamedT is the subset of testing.TB identifying a test.
+type NamedT interface {
+	Name() string
+}
+
 type Reporter struct {
 	w io.WriteCloser
 
@@ -40,7 +50,7 @@ func NewReporter(w io.WriteCloser) *Reporter {
 	}
 
 	go r.write()
-	r.in <- BeginSuiteMessage{StartedAt: time.Now()}
+	r.in <- BeginSuiteMessage{StartedAt: time.Now(), FrameworkVersion: frameworkVersion()}
 
 	return r
 }
@@ -52,12 +62,25 @@ func (r *Reporter) write() {
 	enc := json.NewEncoder(r.w)
 	enc.SetEscapeHTML(false)
 
-	for m := range r.in {
+	timings := make(timingAggregator)
+	encode := func(m Message) {
 		if err := enc.Encode(JSONMessage(m)); err != nil {
 			panic(fmt.Errorf("reporter failed to encode message; tests cannot continue: %w", err))
 		}
 	}
 
+	for m := range r.in {
+		switch m := m.(type) {
+		case TimingMessage:
+			timings.add(m)
+		case FinishTestMessage:
+			if summary, ok := timings.summary(m.Name); ok {
+				encode(summary)
+			}
+		}
+		encode(m)
+	}
+
 	r.writerDone <- r.w.Close()
 }
 
@@ -151,15 +174,22 @@ func (r *Reporter) TrackSkip(t T, format string, args ...any) {
 }
 
 // RelayerExecReporter returns a RelayerExecReporter associated with t.
-func (r *Reporter) RelayerExecReporter(t T) *RelayerExecReporter {
+// Only the name of t is used, so t may also be a benchmark or a custom test runner.
+func (r *Reporter) RelayerExecReporter(t NamedT) *RelayerExecReporter {
 	return &RelayerExecReporter{r: r, testName: t.Name()}
 }
 
+// maxRecentRelayerExecs is how many of the latest relayer commands a RelayerExecReporter keeps.
+const maxRecentRelayerExecs = 50
+
 // RelayerExecReporter provides one method that satisfies the ibc.RelayerExecReporter interface.
 // Instances of RelayerExecReporter must be retrieved through (*Reporter).RelayerExecReporter.
 type RelayerExecReporter struct {
 	r        *Reporter
 	testName string
+
+	mu     sync.Mutex
+	recent []RelayerExecMessage
 }
 
 // TrackRelayerExec tracks the execution of an individual relayer command.
@@ -175,7 +205,7 @@ func (r *RelayerExecReporter) TrackRelayerExec(
 	if err != nil {
 		errMsg = err.Error()
 	}
-	r.r.in <- RelayerExecMessage{
+	msg := RelayerExecMessage{
 		Name:          r.testName,
 		StartedAt:     startedAt,
 		FinishedAt:    finishedAt,
@@ -186,6 +216,37 @@ func (r *RelayerExecReporter) TrackRelayerExec(
 		ExitCode:      exitCode,
 		Error:         errMsg,
 	}
+
+	r.mu.Lock()
+	r.recent = append(r.recent, msg)
+	if len(r.recent) > maxRecentRelayerExecs {
+		r.recent = append([]RelayerExecMessage(nil), r.recent[len(r.recent)-maxRecentRelayerExecs:]...)
+	}
+	r.mu.Unlock()
+
+	r.r.in <- msg
+}
+
+// RecentRelayerExecs returns the latest relayer commands tracked by r, up to 50, oldest first,
+// e.g. to show what the relayer did last while debugging a test.
+func (r *RelayerExecReporter) RecentRelayerExecs() []RelayerExecMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RelayerExecMessage(nil), r.recent...)
+}
+
+// TrackChainConfig tracks the effective configuration of a started chain.
+// The Name field of msg is set to the test name.
+func (r *RelayerExecReporter) TrackChainConfig(msg ChainConfigMessage) {
+	msg.Name = r.testName
+	r.r.in <- msg
+}
+
+// TrackRelayerConfig tracks the relayer image used by the test.
+// The Name field of msg is set to the test name.
+func (r *RelayerExecReporter) TrackRelayerConfig(msg RelayerConfigMessage) {
+	msg.Name = r.testName
+	r.r.in <- msg
 }
 
 // TestifyT returns a TestifyReporter which will track logged errors in test.
@@ -252,3 +313,45 @@ func (nopWriteCloser) Close() error {
 func newNopWriteCloser() io.WriteCloser {
 	return nopWriteCloser{Writer: io.Discard}
 }
+
+// frameworkVersion returns the version of ibctest in the running executable, or "unknown".
+// The git commit set by the Makefile takes precedence;
+// otherwise the build info holds the module version when ibctest is a dependency,
+// or the VCS revision when testing ibctest itself.
+func frameworkVersion() string {
+	if version.GitSha != "unknown" {
+		return version.GitSha
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version.GitSha
+	}
+	return buildInfoVersion(bi)
+}
+
+func buildInfoVersion(bi *debug.BuildInfo) string {
+	for _, dep := range bi.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if r := dep.Replace; r != nil {
+			if r.Version == "" {
+				// Replaced with a local directory.
+				return r.Path
+			}
+			return r.Path + "@" + r.Version
+		}
+		return dep.Version
+	}
+	if bi.Main.Path == modulePath {
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.revision" {
+				return s.Value
+			}
+		}
+		if bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			return bi.Main.Version
+		}
+	}
+	return version.GitSha
+}
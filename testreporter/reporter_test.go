@@ -2,8 +2,11 @@ package testreporter_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -75,6 +78,7 @@ func TestReporter_TrackPassingSingleTest(t *testing.T) {
 
 	beginSuiteMsg := msgs[0].(testreporter.BeginSuiteMessage)
 	requireTimeInRange(t, beginSuiteMsg.StartedAt, beforeStartSuite, afterStartSuite)
+	require.NotEmpty(t, beginSuiteMsg.FrameworkVersion)
 
 	beginTestMsg := msgs[1].(testreporter.BeginTestMessage)
 	require.Equal(t, beginTestMsg.Name, "my_test")
@@ -261,6 +265,26 @@ func TestReporter_RelayerExec(t *testing.T) {
 	require.Empty(t, diff)
 }
 
+func TestRelayerExecReporter_RecentRelayerExecs(t *testing.T) {
+	t.Parallel()
+
+	r := testreporter.NewNopReporter()
+	rep := r.RelayerExecReporter(mocktesting.NewT("my_test"))
+	require.Empty(t, rep.RecentRelayerExecs())
+
+	now := time.Now()
+	for i := 0; i < 60; i++ {
+		rep.TrackRelayerExec("my_container", []string{"rly", strconv.Itoa(i)}, "", "", 0, now, now, nil)
+	}
+
+	recent := rep.RecentRelayerExecs()
+	require.Len(t, recent, 50)
+	require.Equal(t, []string{"rly", "10"}, recent[0].Command)
+	require.Equal(t, []string{"rly", "59"}, recent[49].Command)
+	require.Equal(t, "my_test", recent[49].Name)
+	require.NoError(t, r.Close())
+}
+
 // requireTimeInRange is a helper to assert that a time occurs between a given start and end.
 func requireTimeInRange(t *testing.T, actual, notBefore, notAfter time.Time) {
 	t.Helper()
@@ -268,3 +292,108 @@ func requireTimeInRange(t *testing.T, actual, notBefore, notAfter time.Time) {
 	require.Falsef(t, actual.Before(notBefore), "time %v should have occurred on or after %v", actual, notBefore)
 	require.Falsef(t, actual.After(notAfter), "time %v should have occurred on or before %v", actual, notAfter)
 }
+
+func TestReporter_TrackTiming(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+	r := testreporter.NewReporter(nopCloser{Writer: buf})
+
+	mt := mocktesting.NewT("my_test")
+
+	r.TrackTest(mt)
+
+	ctx := testreporter.WithTimingTracker(context.Background(), r.RelayerExecReporter(mt))
+	startedAt := time.Now().Add(-time.Second)
+	testreporter.TrackTiming(ctx, testreporter.PhaseImagePull, "image-a", startedAt)
+	testreporter.TrackTiming(ctx, testreporter.PhaseImagePull, "image-b", startedAt)
+
+	// Contexts without a tracker are ignored.
+	testreporter.TrackTiming(context.Background(), testreporter.PhaseImagePull, "image-c", startedAt)
+
+	mt.RunCleanups()
+
+	require.NoError(t, r.Close())
+
+	msgs := ReporterMessages(t, buf)
+	require.Len(t, msgs, 7)
+
+	timing := msgs[2].(testreporter.TimingMessage)
+	require.Equal(t, "my_test", timing.Name)
+	require.Equal(t, testreporter.PhaseImagePull, timing.Phase)
+	require.Equal(t, "image-a", timing.Subject)
+	require.True(t, timing.FinishedAt.After(startedAt))
+
+	summary := msgs[4].(testreporter.TimingSummaryMessage)
+	require.Equal(t, "my_test", summary.Name)
+	require.GreaterOrEqual(t, summary.Phases[testreporter.PhaseImagePull], 2*time.Second)
+
+	_ = msgs[5].(testreporter.FinishTestMessage)
+}
+
+func TestReporter_StartPhase(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+	r := testreporter.NewReporter(nopCloser{Writer: buf})
+
+	mt := mocktesting.NewT("my_test")
+
+	r.TrackTest(mt)
+
+	var (
+		mu     sync.Mutex
+		events []testreporter.ProgressEvent
+	)
+	ctx := testreporter.WithTimingTracker(context.Background(), r.RelayerExecReporter(mt))
+	ctx = testreporter.WithProgress(ctx, func(ev testreporter.ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, ev)
+	})
+
+	done := testreporter.StartPhase(ctx, testreporter.PhaseChainspec, "rococo-local")
+	done()
+	testreporter.TrackTiming(ctx, testreporter.PhaseImagePull, "image-a", time.Now().Add(-time.Second))
+
+	// Without a tracker or progress funcs, phases are not reported.
+	testreporter.StartPhase(context.Background(), testreporter.PhaseChainspec, "ignored")()
+
+	mt.RunCleanups()
+
+	require.NoError(t, r.Close())
+
+	require.Len(t, events, 3)
+	require.Equal(t, testreporter.PhaseChainspec, events[0].Phase)
+	require.Equal(t, "rococo-local", events[0].Subject)
+	require.False(t, events[0].Completed)
+	require.True(t, events[1].Completed)
+	require.Equal(t, events[0].StartedAt, events[1].StartedAt)
+	require.Equal(t, testreporter.PhaseImagePull, events[2].Phase)
+	require.True(t, events[2].Completed)
+	require.GreaterOrEqual(t, events[2].Duration, time.Second)
+
+	msgs := ReporterMessages(t, buf)
+	start := msgs[2].(testreporter.PhaseStartMessage)
+	require.Equal(t, "my_test", start.Name)
+	require.Equal(t, testreporter.PhaseChainspec, start.Phase)
+	require.Equal(t, "rococo-local", start.Subject)
+
+	timing := msgs[3].(testreporter.TimingMessage)
+	require.Equal(t, testreporter.PhaseChainspec, timing.Phase)
+	require.True(t, timing.StartedAt.Equal(start.StartedAt))
+}
+
+func TestProgressBar(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+	progress := testreporter.ProgressBar(buf)
+
+	progress(testreporter.ProgressEvent{Phase: testreporter.PhaseImagePull, Subject: "gaia:v7"})
+	require.Equal(t, "\r\033[K[--------------------] 0/1 ImagePull gaia:v7 started", buf.String())
+
+	buf.Reset()
+	progress(testreporter.ProgressEvent{Phase: testreporter.PhaseImagePull, Subject: "gaia:v7", Completed: true, Duration: 1500 * time.Millisecond})
+	require.Equal(t, "\r\033[K[####################] 1/1 ImagePull gaia:v7 completed in 1.5s", buf.String())
+}
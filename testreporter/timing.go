@@ -0,0 +1,112 @@
+package testreporter
+
+import (
+	"context"
+	"time"
+)
+
+// Phases of test setup tracked through TrackTiming.
+const (
+	PhaseImagePull        = "ImagePull"
+	PhaseKeyDerivation    = "KeyDerivation"
+	PhaseVolumeSetup      = "VolumeSetup"
+	PhaseChainspec        = "Chainspec"
+	PhaseContainerStart   = "ContainerStart"
+	PhaseFirstBlock       = "FirstBlock"
+	PhaseChainStart       = "ChainStart"
+	PhaseRelayerHandshake = "RelayerHandshake"
+)
+
+// TimingTracker tracks how long a phase of test setup took.
+// The RelayerExecReporter satisfies TimingTracker.
+type TimingTracker interface {
+	TrackTiming(phase, subject string, startedAt, finishedAt time.Time)
+}
+
+type timingTrackerKey struct{}
+
+// WithTimingTracker returns a context that carries tr,
+// so that TrackTiming calls deep in chain and docker setup can report to the test's reporter.
+func WithTimingTracker(ctx context.Context, tr TimingTracker) context.Context {
+	return context.WithValue(ctx, timingTrackerKey{}, tr)
+}
+
+// TrackTiming reports that the phase for subject, such as an image ref or a chain ID,
+// ran from startedAt until now, to the TimingTracker and ProgressFuncs carried by ctx.
+// It is a no-op if ctx carries neither.
+// To also report when the phase starts, use StartPhase.
+func TrackTiming(ctx context.Context, phase, subject string, startedAt time.Time) {
+	finishedAt := time.Now()
+	if tr, ok := ctx.Value(timingTrackerKey{}).(TimingTracker); ok {
+		tr.TrackTiming(phase, subject, startedAt, finishedAt)
+	}
+	reportProgress(ctx, ProgressEvent{
+		Phase:     phase,
+		Subject:   subject,
+		Completed: true,
+		StartedAt: startedAt,
+		Duration:  finishedAt.Sub(startedAt),
+	})
+}
+
+// NodeCommandTracker tracks the commands that start chain node containers.
+// The RelayerExecReporter satisfies NodeCommandTracker.
+type NodeCommandTracker interface {
+	TrackNodeCommand(containerName string, cmd, env []string)
+}
+
+// TrackNodeCommand reports the command and additional environment that start the named container
+// to the tracker carried by ctx, if that TimingTracker is also a NodeCommandTracker.
+// It is a no-op otherwise.
+func TrackNodeCommand(ctx context.Context, containerName string, cmd, env []string) {
+	tr, ok := ctx.Value(timingTrackerKey{}).(NodeCommandTracker)
+	if !ok {
+		return
+	}
+	tr.TrackNodeCommand(containerName, cmd, env)
+}
+
+// TrackNodeCommand tracks the command that starts a chain node container.
+func (r *RelayerExecReporter) TrackNodeCommand(containerName string, cmd, env []string) {
+	r.r.in <- NodeCommandMessage{
+		Name:          r.testName,
+		ContainerName: containerName,
+		Command:       cmd,
+		Env:           env,
+	}
+}
+
+// TrackTiming tracks how long a phase of setup took for subject.
+func (r *RelayerExecReporter) TrackTiming(phase, subject string, startedAt, finishedAt time.Time) {
+	r.r.in <- TimingMessage{
+		Name:       r.testName,
+		Phase:      phase,
+		Subject:    subject,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+	}
+}
+
+// timingAggregator sums the durations of TimingMessages per test and phase.
+// It is only used from the Reporter's write goroutine.
+type timingAggregator map[string]map[string]time.Duration
+
+func (a timingAggregator) add(m TimingMessage) {
+	phases, ok := a[m.Name]
+	if !ok {
+		phases = make(map[string]time.Duration)
+		a[m.Name] = phases
+	}
+	phases[m.Phase] += m.FinishedAt.Sub(m.StartedAt)
+}
+
+// summary removes and returns the summary for the named test,
+// or false if no timings were tracked for the test.
+func (a timingAggregator) summary(name string) (TimingSummaryMessage, bool) {
+	phases, ok := a[name]
+	if !ok {
+		return TimingSummaryMessage{}, false
+	}
+	delete(a, name)
+	return TimingSummaryMessage{Name: name, Phases: phases}, true
+}
@@ -0,0 +1,38 @@
+package testreporter
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildInfoVersion(t *testing.T) {
+	t.Parallel()
+
+	dep := &debug.Module{Path: modulePath, Version: "v6.0.1"}
+	require.Equal(t, "v6.0.1", buildInfoVersion(&debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/tests"},
+		Deps: []*debug.Module{{Path: "example.com/other", Version: "v1.0.0"}, dep},
+	}))
+
+	replaced := *dep
+	replaced.Replace = &debug.Module{Path: "github.com/fork/ibctest/v6", Version: "v6.0.2"}
+	require.Equal(t, "github.com/fork/ibctest/v6@v6.0.2", buildInfoVersion(&debug.BuildInfo{
+		Deps: []*debug.Module{&replaced},
+	}))
+
+	replaced.Replace = &debug.Module{Path: "../ibctest"}
+	require.Equal(t, "../ibctest", buildInfoVersion(&debug.BuildInfo{
+		Deps: []*debug.Module{&replaced},
+	}))
+
+	require.Equal(t, "abc123", buildInfoVersion(&debug.BuildInfo{
+		Main:     debug.Module{Path: modulePath, Version: "(devel)"},
+		Settings: []debug.BuildSetting{{Key: "vcs.revision", Value: "abc123"}},
+	}))
+
+	require.Equal(t, "unknown", buildInfoVersion(&debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/tests"},
+	}))
+}
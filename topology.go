@@ -0,0 +1,314 @@
+package ibctest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/client"
+	"github.com/strangelove-ventures/ibctest/v6/chain/polkadot"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/relayer"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Topology is a set of chains, relayers, and the paths linking the chains,
+// declared once in a YAML file, see LoadTopology,
+// and built by tests and by programs running a Runner alike.
+type Topology struct {
+	Chains   []*ChainSpec
+	Relayers []TopologyRelayer
+	Links    []TopologyLink
+}
+
+// TopologyRelayer is a relayer of a Topology.
+type TopologyRelayer struct {
+	// Name of the relayer in the Interchain, referenced by links.
+	Name string
+
+	Impl    ibc.RelayerImplementation
+	Options relayer.RelayerOptions
+}
+
+// Factory returns a RelayerFactory building the relayer.
+func (r TopologyRelayer) Factory(log *zap.Logger) RelayerFactory {
+	return NewBuiltinRelayerFactory(r.Impl, log, r.Options...)
+}
+
+// TopologyLink is a path of a Topology.
+type TopologyLink struct {
+	// Chain IDs of the linked chains.
+	Chain1, Chain2 string
+
+	// Name of the relayer relaying the path.
+	Relayer string
+
+	// Name of the path.
+	Path string
+}
+
+// LoadTopology reads the YAML topology file at path:
+//
+//	chains:
+//	  - name: gaia              # built-in chain config, as in ChainSpec.Name
+//	    version: v7.0.3
+//	    chain-id: gaia-1        # required for chains referenced by links
+//	    num-validators: 1
+//	    num-full-nodes: 0
+//	    gas-prices: 0.01uatom   # any other ibc.ChainConfig field, as in configuredChains.yaml
+//	  - name: composable
+//	    version: polkadot:v0.9.19,composable:centauri
+//	    chain-id: rococo-local
+//	    parachains:
+//	      - chain-id: dali-dev
+//	        bin: composable-node
+//	        image: {repository: ghcr.io/misko9/composable, version: centauri}
+//	        num-nodes: 1
+//	        flags: [--execution=wasm]
+//	        relay-chain-flags: [--execution=wasm]
+//	relayers:
+//	  - name: rly               # name in the Interchain
+//	    impl: rly               # registered relayer implementation; defaults to name
+//	    image: {repository: ghcr.io/cosmos/relayer, version: v2.1.2, uid-gid: "100:1000"}
+//	    startup-flags: [--processor, events]
+//	    log-level: debug
+//	links:
+//	  - chains: [gaia-1, osmosis-1]
+//	    relayer: rly
+//	    path: gaia-osmo
+//
+// Unknown keys are errors, so that a misspelled setting is not silently ignored.
+func LoadTopology(path string) (*Topology, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology: %w", err)
+	}
+	tp, err := ParseTopology(data)
+	if err != nil {
+		return nil, fmt.Errorf("topology %s: %w", path, err)
+	}
+	return tp, nil
+}
+
+// ParseTopology parses a YAML topology, see LoadTopology.
+func ParseTopology(data []byte) (*Topology, error) {
+	var f topologyFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid topology: %w", err)
+	}
+
+	// ChainSpec distinguishes omitted overrides from zero overrides.
+	var overrides struct {
+		Chains []struct {
+			GasAdjustment *float64 `yaml:"gas-adjustment"`
+			NoHostMount   *bool    `yaml:"no-host-mount"`
+		} `yaml:"chains"`
+	}
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid topology: %w", err)
+	}
+
+	if len(f.Chains) == 0 {
+		return nil, errors.New("topology has no chains")
+	}
+	tp := &Topology{}
+	for i, c := range f.Chains {
+		spec := c.spec()
+		spec.GasAdjustment = overrides.Chains[i].GasAdjustment
+		spec.NoHostMount = overrides.Chains[i].NoHostMount
+		tp.Chains = append(tp.Chains, spec)
+	}
+
+	relayerNames := make(map[string]bool, len(f.Relayers))
+	for i, r := range f.Relayers {
+		tr, err := r.relayer()
+		if err != nil {
+			return nil, fmt.Errorf("relayer at index %d: %w", i, err)
+		}
+		if relayerNames[tr.Name] {
+			return nil, fmt.Errorf("relayer %s is declared more than once", tr.Name)
+		}
+		relayerNames[tr.Name] = true
+		tp.Relayers = append(tp.Relayers, tr)
+	}
+
+	paths := make(map[[2]string]bool, len(f.Links))
+	for i, l := range f.Links {
+		switch {
+		case len(l.Chains) != 2:
+			return nil, fmt.Errorf("link at index %d must link 2 chains, got %d", i, len(l.Chains))
+		case l.Path == "":
+			return nil, fmt.Errorf("link at index %d has no path", i)
+		case !relayerNames[l.Relayer]:
+			return nil, fmt.Errorf("link %s: unknown relayer %q", l.Path, l.Relayer)
+		case paths[[2]string{l.Relayer, l.Path}]:
+			return nil, fmt.Errorf("link %s is declared more than once for relayer %s", l.Path, l.Relayer)
+		}
+		paths[[2]string{l.Relayer, l.Path}] = true
+		tp.Links = append(tp.Links, TopologyLink{Chain1: l.Chains[0], Chain2: l.Chains[1], Relayer: l.Relayer, Path: l.Path})
+	}
+	return tp, nil
+}
+
+// ChainFactory returns a chain factory building the chains of tp.
+func (tp *Topology) ChainFactory(log *zap.Logger) *BuiltinChainFactory {
+	return NewBuiltinChainFactory(log, tp.Chains)
+}
+
+// BuiltTopology is a Topology built by Topology.Build.
+type BuiltTopology struct {
+	// Interchain with the chains, relayers, and links of the topology; it still needs to be built.
+	Interchain *Interchain
+
+	// Chains by chain ID.
+	Chains map[string]ibc.Chain
+
+	// Relayers by name.
+	Relayers map[string]ibc.Relayer
+}
+
+// Build builds the chains and relayers of tp for t, on the docker network networkID,
+// and returns them, added to an Interchain along with tp's links.
+// The caller builds the Interchain, e.g. with InterchainBuildOptions from DockerSetup.
+func (tp *Topology) Build(t testing.TB, cli *client.Client, networkID string, log *zap.Logger) (BuiltTopology, error) {
+	chains, err := tp.ChainFactory(log).Chains(t.Name())
+	if err != nil {
+		return BuiltTopology{}, err
+	}
+
+	bt := BuiltTopology{
+		Interchain: NewInterchain(),
+		Chains:     make(map[string]ibc.Chain, len(chains)),
+		Relayers:   make(map[string]ibc.Relayer, len(tp.Relayers)),
+	}
+	for _, c := range chains {
+		bt.Chains[c.Config().ChainID] = c
+		bt.Interchain.AddChain(c)
+	}
+	for _, tr := range tp.Relayers {
+		r := tr.Factory(log).Build(t, cli, networkID)
+		bt.Relayers[tr.Name] = r
+		bt.Interchain.AddRelayer(r, tr.Name)
+	}
+
+	for _, l := range tp.Links {
+		c1, ok := bt.Chains[l.Chain1]
+		if !ok {
+			return BuiltTopology{}, fmt.Errorf("link %s: unknown chain ID %s", l.Path, l.Chain1)
+		}
+		c2, ok := bt.Chains[l.Chain2]
+		if !ok {
+			return BuiltTopology{}, fmt.Errorf("link %s: unknown chain ID %s", l.Path, l.Chain2)
+		}
+		bt.Interchain.AddLink(InterchainLink{
+			Chain1:  c1,
+			Chain2:  c2,
+			Relayer: bt.Relayers[l.Relayer],
+			Path:    l.Path,
+		})
+	}
+	return bt, nil
+}
+
+// topologyFile is the YAML representation of a Topology.
+type topologyFile struct {
+	Chains   []topologyChain   `yaml:"chains"`
+	Relayers []topologyRelayer `yaml:"relayers"`
+	Links    []topologyLink    `yaml:"links"`
+}
+
+type topologyChain struct {
+	// The name of the inlined config is the name of the built-in config, as in ChainSpec.Name.
+	ibc.ChainConfig `yaml:",inline"`
+
+	ChainName     string              `yaml:"chain-name"`
+	Version       string              `yaml:"version"`
+	NumValidators *int                `yaml:"num-validators"`
+	NumFullNodes  *int                `yaml:"num-full-nodes"`
+	Parachains    []topologyParachain `yaml:"parachains"`
+}
+
+func (c topologyChain) spec() *ChainSpec {
+	s := &ChainSpec{
+		Name:          c.ChainConfig.Name,
+		ChainName:     c.ChainName,
+		Version:       c.Version,
+		ChainConfig:   c.ChainConfig,
+		NumValidators: c.NumValidators,
+		NumFullNodes:  c.NumFullNodes,
+	}
+	s.ChainConfig.Name = ""
+	for _, p := range c.Parachains {
+		s.Parachains = append(s.Parachains, polkadot.ParachainConfig{
+			ChainID:         p.ChainID,
+			Bin:             p.Bin,
+			Image:           p.Image,
+			NumNodes:        p.NumNodes,
+			ParaID:          p.ParaID,
+			Flags:           p.Flags,
+			RelayChainFlags: p.RelayChainFlags,
+			Denom:           p.Denom,
+			AssetIDs:        p.AssetIDs,
+		})
+	}
+	return s
+}
+
+type topologyParachain struct {
+	ChainID         string            `yaml:"chain-id"`
+	Bin             string            `yaml:"bin"`
+	Image           ibc.DockerImage   `yaml:"image"`
+	NumNodes        int               `yaml:"num-nodes"`
+	ParaID          int               `yaml:"para-id"`
+	Flags           []string          `yaml:"flags"`
+	RelayChainFlags []string          `yaml:"relay-chain-flags"`
+	Denom           string            `yaml:"denom"`
+	AssetIDs        map[string]string `yaml:"asset-ids"`
+}
+
+type topologyRelayer struct {
+	Name         string           `yaml:"name"`
+	Impl         string           `yaml:"impl"`
+	Image        *ibc.DockerImage `yaml:"image"`
+	StartupFlags []string         `yaml:"startup-flags"`
+	LogLevel     string           `yaml:"log-level"`
+}
+
+func (r topologyRelayer) relayer() (TopologyRelayer, error) {
+	if r.Name == "" {
+		return TopologyRelayer{}, errors.New("relayer has no name")
+	}
+	implName := r.Impl
+	if implName == "" {
+		implName = r.Name
+	}
+	impl, ok := RelayerImplementationByName(implName)
+	if !ok {
+		return TopologyRelayer{}, fmt.Errorf("relayer %s: unknown relayer implementation %q (registered relayers are: %s)",
+			r.Name, implName, strings.Join(RegisteredRelayerNames(), ", "))
+	}
+
+	tr := TopologyRelayer{Name: r.Name, Impl: impl}
+	if r.Image != nil {
+		tr.Options = append(tr.Options, relayer.RelayerOptionDockerImage{DockerImage: *r.Image})
+	}
+	if len(r.StartupFlags) > 0 {
+		tr.Options = append(tr.Options, relayer.StartupFlags(r.StartupFlags...))
+	}
+	if r.LogLevel != "" {
+		tr.Options = append(tr.Options, relayer.Logging(r.LogLevel, ""))
+	}
+	return tr, nil
+}
+
+type topologyLink struct {
+	Chains  []string `yaml:"chains"`
+	Relayer string   `yaml:"relayer"`
+	Path    string   `yaml:"path"`
+}
@@ -0,0 +1,140 @@
+package ibctest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/strangelove-ventures/ibctest/v6"
+	"github.com/strangelove-ventures/ibctest/v6/chain/polkadot"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	"github.com/strangelove-ventures/ibctest/v6/relayer"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+const testTopology = `
+chains:
+  - name: gaia
+    version: v7.0.3
+    chain-id: gaia-1
+    num-validators: 1
+    num-full-nodes: 0
+    gas-adjustment: 0
+  - name: osmosis
+    chain-name: osmo
+    version: v11.0.0
+    chain-id: osmosis-1
+    log-level: debug
+  - name: composable
+    version: polkadot:v0.9.19
+    chain-id: rococo-local
+    parachains:
+      - chain-id: dali-dev
+        bin: composable-node
+        image: {repository: ghcr.io/misko9/composable, version: centauri}
+        num-nodes: 1
+        para-id: 2087
+        flags: [--execution=wasm]
+relayers:
+  - name: rly
+    image: {repository: ghcr.io/cosmos/relayer, version: v2.1.2, uid-gid: "100:1000"}
+    startup-flags: [--processor, events]
+    log-level: debug
+links:
+  - chains: [gaia-1, osmosis-1]
+    relayer: rly
+    path: gaia-osmo
+`
+
+func TestLoadTopology(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "topology.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testTopology), 0o644))
+
+	tp, err := ibctest.LoadTopology(path)
+	require.NoError(t, err)
+
+	require.Len(t, tp.Chains, 3)
+	gaia := tp.Chains[0]
+	require.Equal(t, "gaia", gaia.Name)
+	require.Equal(t, "v7.0.3", gaia.Version)
+	require.Equal(t, 1, *gaia.NumValidators)
+	require.Equal(t, 0, *gaia.NumFullNodes)
+	require.NotNil(t, gaia.GasAdjustment, "explicit zero override must be kept")
+	require.Zero(t, *gaia.GasAdjustment)
+	require.Nil(t, gaia.NoHostMount)
+	require.Equal(t, "osmo", tp.Chains[1].ChainName)
+	require.Equal(t, "debug", tp.Chains[1].LogLevel)
+	require.Nil(t, tp.Chains[1].GasAdjustment)
+	require.Equal(t, []polkadot.ParachainConfig{{
+		ChainID:  "dali-dev",
+		Bin:      "composable-node",
+		Image:    ibc.DockerImage{Repository: "ghcr.io/misko9/composable", Version: "centauri"},
+		NumNodes: 1,
+		ParaID:   2087,
+		Flags:    []string{"--execution=wasm"},
+	}}, tp.Chains[2].Parachains)
+
+	require.Len(t, tp.Relayers, 1)
+	require.Equal(t, "rly", tp.Relayers[0].Name)
+	require.Equal(t, ibc.CosmosRly, tp.Relayers[0].Impl)
+	require.Equal(t, relayer.RelayerOptions{
+		relayer.RelayerOptionDockerImage{DockerImage: ibc.DockerImage{Repository: "ghcr.io/cosmos/relayer", Version: "v2.1.2", UidGid: "100:1000"}},
+		relayer.StartupFlags("--processor", "events"),
+		relayer.Logging("debug", ""),
+	}, tp.Relayers[0].Options)
+	require.Equal(t, "rly@v2.1.2", tp.Relayers[0].Factory(zaptest.NewLogger(t)).Name())
+
+	require.Equal(t, []ibctest.TopologyLink{
+		{Chain1: "gaia-1", Chain2: "osmosis-1", Relayer: "rly", Path: "gaia-osmo"},
+	}, tp.Links)
+
+	chains, err := tp.ChainFactory(zaptest.NewLogger(t)).Chains(t.Name())
+	require.NoError(t, err)
+	require.Len(t, chains, 3)
+	require.Equal(t, "gaia-1", chains[0].Config().ChainID)
+	require.Zero(t, chains[0].Config().GasAdjustment)
+	require.Equal(t, "osmo", chains[1].Config().Name)
+	require.IsType(t, &polkadot.PolkadotChain{}, chains[2])
+}
+
+func TestParseTopology_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name, yaml, err string
+	}{
+		{"no chains", "relayers: []", "topology has no chains"},
+		{"unknown key", "chains:\n  - name: gaia\n    versoin: v7.0.3", "field versoin not found"},
+		{
+			"unknown relayer implementation",
+			"chains: [{name: gaia}]\nrelayers: [{name: foo}]",
+			`relayer foo: unknown relayer implementation "foo"`,
+		},
+		{
+			"duplicate relayer",
+			"chains: [{name: gaia}]\nrelayers: [{name: rly}, {name: rly}]",
+			"relayer rly is declared more than once",
+		},
+		{
+			"link with unknown relayer",
+			"chains: [{name: gaia}]\nlinks: [{chains: [a, b], relayer: rly, path: p}]",
+			`link p: unknown relayer "rly"`,
+		},
+		{
+			"link with one chain",
+			"chains: [{name: gaia}]\nrelayers: [{name: rly}]\nlinks: [{chains: [a], relayer: rly, path: p}]",
+			"link at index 0 must link 2 chains, got 1",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ibctest.ParseTopology([]byte(tc.yaml))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
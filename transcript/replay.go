@@ -0,0 +1,142 @@
+package transcript
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// Topology is the fresh topology a transcript is replayed against.
+type Topology struct {
+	// Chains, matched to entries by chain ID.
+	Chains []ibc.Chain
+
+	// Relayers, keyed by the name of their implementation as recorded, such as "rly".
+	Relayers map[string]ibc.Relayer
+
+	// Optional. Reporter of the replayed relayer commands.
+	Reporter ibc.RelayerExecReporter
+}
+
+// ReplayError is returned by Replay when an entry fails.
+type ReplayError struct {
+	// Index of the failed entry in the transcript.
+	Index int
+	Entry Entry
+
+	Err error
+}
+
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("transcript entry %d (%s): %v", e.Index, e.Entry.Kind, e.Err)
+}
+
+func (e *ReplayError) Unwrap() error {
+	return e.Err
+}
+
+// Replay runs the entries against topo in order, stopping at the first entry that fails, which it returns as a *ReplayError.
+//
+// Chain commands run through ibc.Chain.Exec, so on the chain's default node rather than the recorded container.
+// Commands referring to addresses or keys only match the fresh topology if it derives them the same way,
+// e.g. from the same master seed, see ibctest.SetMnemonicSeed.
+func Replay(ctx context.Context, entries []Entry, topo Topology) error {
+	chains := make(map[string]ibc.Chain, len(topo.Chains))
+	for _, c := range topo.Chains {
+		chains[c.Config().ChainID] = c
+	}
+	rep := topo.Reporter
+	if rep == nil {
+		rep = ibc.NopRelayerExecReporter{}
+	}
+
+	for i, e := range entries {
+		if err := replay(ctx, e, chains, topo.Relayers, rep); err != nil {
+			return &ReplayError{Index: i, Entry: e, Err: err}
+		}
+	}
+	return nil
+}
+
+func replay(ctx context.Context, e Entry, chains map[string]ibc.Chain, relayers map[string]ibc.Relayer, rep ibc.RelayerExecReporter) error {
+	switch e.Kind {
+	case KindChainExec:
+		c, ok := chains[e.ChainID]
+		if !ok {
+			return fmt.Errorf("no chain %s", e.ChainID)
+		}
+		_, _, err := c.Exec(ctx, e.Command, e.Env)
+		return err
+
+	case KindRelayerExec:
+		r, ok := relayers[e.Relayer]
+		if !ok {
+			return fmt.Errorf("no relayer %s", e.Relayer)
+		}
+		return r.Exec(ctx, rep, e.Command, e.Env).Err
+
+	case KindRelayerStart:
+		r, ok := relayers[e.Relayer]
+		if !ok {
+			return fmt.Errorf("no relayer %s", e.Relayer)
+		}
+		return r.StartRelayer(ctx, rep, e.Paths...)
+
+	case KindRelayerStop:
+		r, ok := relayers[e.Relayer]
+		if !ok {
+			return fmt.Errorf("no relayer %s", e.Relayer)
+		}
+		return r.StopRelayer(ctx, rep)
+
+	case KindTransfer:
+		c, ok := chains[e.ChainID]
+		if !ok {
+			return fmt.Errorf("no chain %s", e.ChainID)
+		}
+		if e.Transfer == nil {
+			return fmt.Errorf("transfer entry without transfer")
+		}
+		return replayTransfer(ctx, c, *e.Transfer)
+
+	case KindSendFunds:
+		c, ok := chains[e.ChainID]
+		if !ok {
+			return fmt.Errorf("no chain %s", e.ChainID)
+		}
+		if e.Funds == nil {
+			return fmt.Errorf("send funds entry without funds")
+		}
+		return c.SendFunds(ctx, e.Funds.KeyName, e.Funds.Amount)
+
+	default:
+		return fmt.Errorf("unknown entry kind %q", e.Kind)
+	}
+}
+
+func replayTransfer(ctx context.Context, c ibc.Chain, t Transfer) error {
+	chainID := c.Config().ChainID
+	if t.Batch {
+		bt, ok := c.(ibc.BatchIBCTransferer)
+		if !ok {
+			return fmt.Errorf("chain %s cannot send batch transfers", chainID)
+		}
+		_, err := bt.SendIBCTransferBatch(ctx, t.ChannelID, t.KeyName, t.Amounts, t.Timeout)
+		return err
+	}
+
+	if len(t.Amounts) != 1 {
+		return fmt.Errorf("transfer has %d amounts, want 1", len(t.Amounts))
+	}
+	if t.Memo != "" {
+		mt, ok := c.(ibc.MemoIBCTransferer)
+		if !ok {
+			return fmt.Errorf("chain %s cannot send transfers with a memo", chainID)
+		}
+		_, err := mt.SendIBCTransferWithMemo(ctx, t.ChannelID, t.KeyName, t.Amounts[0], t.Timeout, t.Memo)
+		return err
+	}
+	_, err := c.SendIBCTransfer(ctx, t.ChannelID, t.KeyName, t.Amounts[0], t.Timeout)
+	return err
+}
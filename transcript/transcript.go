@@ -0,0 +1,236 @@
+// Package transcript records the commands a test runs against its chains and relayers,
+// and replays them against a fresh topology, e.g. to reduce a flaky failure to a minimal reproduction.
+//
+// A transcript is a file of JSON lines, one Entry per line, which can be edited by hand
+// to remove the entries that do not matter to the failure.
+package transcript
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+)
+
+// Kinds of transcript entries.
+const (
+	KindChainExec    = "ChainExec"
+	KindRelayerExec  = "RelayerExec"
+	KindRelayerStart = "RelayerStart"
+	KindRelayerStop  = "RelayerStop"
+	KindTransfer     = "Transfer"
+	KindSendFunds    = "SendFunds"
+)
+
+// Entry is one recorded command.
+type Entry struct {
+	Kind string
+
+	RecordedAt time.Time
+
+	// Chain the command ran on, for KindChainExec, KindTransfer and KindSendFunds.
+	ChainID string `json:",omitempty"`
+
+	// Relayer the command ran on, for KindRelayerExec, KindRelayerStart and KindRelayerStop.
+	// This is the name of the relayer implementation, such as "rly" or "hermes".
+	Relayer string `json:",omitempty"`
+
+	// Node or relayer container the command is attributed to, for information only.
+	Container string `json:",omitempty"`
+
+	// Command and additional environment, for KindChainExec and KindRelayerExec.
+	Command []string `json:",omitempty"`
+	Env     []string `json:",omitempty"`
+
+	// Paths the relayer started relaying, for KindRelayerStart.
+	Paths []string `json:",omitempty"`
+
+	// Transfer, for KindTransfer.
+	Transfer *Transfer `json:",omitempty"`
+
+	// Funds sent, for KindSendFunds.
+	Funds *Funds `json:",omitempty"`
+}
+
+// Transfer is a recorded IBC transfer.
+type Transfer struct {
+	ChannelID string
+	KeyName   string
+
+	// Amounts of the transfer; a batch transfer has one amount per transfer.
+	Amounts []ibc.WalletAmount
+	Batch   bool `json:",omitempty"`
+
+	Timeout *ibc.IBCTimeout `json:",omitempty"`
+	Memo    string          `json:",omitempty"`
+}
+
+// Funds are recorded funds sent on a chain.
+type Funds struct {
+	KeyName string
+	Amount  ibc.WalletAmount
+}
+
+// Recorder writes the entries recorded through a context from WithRecorder to a transcript.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	c   io.Closer
+	err error
+}
+
+// NewRecorder returns a Recorder writing to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+// Create returns a Recorder writing to a new transcript file at path, which Close closes.
+func Create(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcript: %w", err)
+	}
+	r := NewRecorder(f)
+	r.c = f
+	return r, nil
+}
+
+// Record writes e to the transcript, setting its RecordedAt if zero.
+// Write errors do not affect the test; the first one is returned by Close.
+func (r *Recorder) Record(e Entry) {
+	if e.RecordedAt.IsZero() {
+		e.RecordedAt = time.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	if err := r.enc.Encode(e); err != nil {
+		r.err = fmt.Errorf("failed to write transcript entry: %w", err)
+	}
+}
+
+// Close closes the transcript file of a Recorder from Create,
+// and returns the first error writing the transcript.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.c != nil {
+		if err := r.c.Close(); err != nil && r.err == nil {
+			r.err = fmt.Errorf("failed to close transcript: %w", err)
+		}
+		r.c = nil
+	}
+	return r.err
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a context that carries r,
+// so that the chain and relayer commands run with the context are recorded to r.
+//
+// Interchain.Build runs the commands that set up the topology;
+// to record only what the test does with it, call WithRecorder after Build.
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+func recorder(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// RecordChainExec records that cmd runs with env in the container of a node of the chain chainID,
+// to the Recorder carried by ctx. It is a no-op if ctx does not carry a Recorder.
+func RecordChainExec(ctx context.Context, chainID, container string, cmd, env []string) {
+	if r := recorder(ctx); r != nil {
+		r.Record(Entry{Kind: KindChainExec, ChainID: chainID, Container: container, Command: cmd, Env: env})
+	}
+}
+
+// RecordRelayerExec records that cmd runs with env in the container of the relayer implementation relayer,
+// to the Recorder carried by ctx. It is a no-op if ctx does not carry a Recorder.
+func RecordRelayerExec(ctx context.Context, relayer, container string, cmd, env []string) {
+	if r := recorder(ctx); r != nil {
+		r.Record(Entry{Kind: KindRelayerExec, Relayer: relayer, Container: container, Command: cmd, Env: env})
+	}
+}
+
+// RecordRelayerStart records that the relayer implementation relayer started relaying paths in its container,
+// to the Recorder carried by ctx. It is a no-op if ctx does not carry a Recorder.
+func RecordRelayerStart(ctx context.Context, relayer, container string, paths []string) {
+	if r := recorder(ctx); r != nil {
+		r.Record(Entry{Kind: KindRelayerStart, Relayer: relayer, Container: container, Paths: paths})
+	}
+}
+
+// RecordRelayerStop records that the relayer implementation relayer stopped relaying,
+// to the Recorder carried by ctx. It is a no-op if ctx does not carry a Recorder.
+func RecordRelayerStop(ctx context.Context, relayer, container string) {
+	if r := recorder(ctx); r != nil {
+		r.Record(Entry{Kind: KindRelayerStop, Relayer: relayer, Container: container})
+	}
+}
+
+// RecordSendFunds records the funds f sent on the chain chainID, to the Recorder carried by ctx.
+// Like RecordTransfer, it returns a context to send the funds with, which carries no Recorder.
+func RecordSendFunds(ctx context.Context, chainID string, f Funds) context.Context {
+	r := recorder(ctx)
+	if r == nil {
+		return ctx
+	}
+	r.Record(Entry{Kind: KindSendFunds, ChainID: chainID, Funds: &f})
+	return context.WithValue(ctx, recorderKey{}, (*Recorder)(nil))
+}
+
+// RecordTransfer records the IBC transfer t sent on the chain chainID, to the Recorder carried by ctx.
+// It returns a context to send the transfer with, which carries no Recorder,
+// so that the commands sending the transfer are not recorded besides it.
+func RecordTransfer(ctx context.Context, chainID string, t Transfer) context.Context {
+	r := recorder(ctx)
+	if r == nil {
+		return ctx
+	}
+	r.Record(Entry{Kind: KindTransfer, ChainID: chainID, Transfer: &t})
+	return context.WithValue(ctx, recorderKey{}, (*Recorder)(nil))
+}
+
+// Read reads the entries of a transcript.
+func Read(rd io.Reader) ([]Entry, error) {
+	var entries []Entry
+	s := bufio.NewScanner(rd)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; s.Scan(); line++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("invalid transcript entry on line %d: %w", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return entries, nil
+}
+
+// ReadFile reads the entries of the transcript file at path.
+func ReadFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
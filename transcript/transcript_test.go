@@ -0,0 +1,125 @@
+package transcript_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/strangelove-ventures/ibctest/v6/chain/mock"
+	"github.com/strangelove-ventures/ibctest/v6/ibc"
+	relayermock "github.com/strangelove-ventures/ibctest/v6/relayer/mock"
+	"github.com/strangelove-ventures/ibctest/v6/transcript"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	rec := transcript.NewRecorder(&buf)
+	ctx := transcript.WithRecorder(context.Background(), rec)
+
+	amount := ibc.WalletAmount{Address: "cosmos1receiver", Denom: "uatom", Amount: math.NewInt(100)}
+	transcript.RecordChainExec(ctx, "gaia-1", "gaia-1-val-0", []string{"gaiad", "status"}, []string{"HOME=/home"})
+	transferCtx := transcript.RecordTransfer(ctx, "gaia-1", transcript.Transfer{ChannelID: "channel-0", KeyName: "user", Amounts: []ibc.WalletAmount{amount}})
+	// Commands sending the transfer are not recorded besides it.
+	transcript.RecordChainExec(transferCtx, "gaia-1", "gaia-1-val-0", []string{"gaiad", "tx", "ibc-transfer"}, nil)
+	transcript.RecordRelayerExec(ctx, "rly", "rly-test", []string{"rly", "tx", "flush"}, nil)
+	transcript.RecordRelayerStart(ctx, "rly", "rly-test", []string{"gaia-osmosis"})
+	fundsCtx := transcript.RecordSendFunds(ctx, "gaia-1", transcript.Funds{KeyName: "faucet", Amount: amount})
+	transcript.RecordChainExec(fundsCtx, "gaia-1", "gaia-1-val-0", []string{"gaiad", "tx", "bank", "send"}, nil)
+	transcript.RecordRelayerStop(ctx, "rly", "rly-test")
+	require.NoError(t, rec.Close())
+
+	// Recording without a Recorder is a no-op.
+	transcript.RecordChainExec(context.Background(), "gaia-1", "gaia-1-val-0", []string{"gaiad", "status"}, nil)
+	require.Equal(t, context.Background(), transcript.RecordTransfer(context.Background(), "gaia-1", transcript.Transfer{}))
+
+	entries, err := transcript.Read(&buf)
+	require.NoError(t, err)
+	kinds := make([]string, len(entries))
+	for i, e := range entries {
+		kinds[i] = e.Kind
+	}
+	require.Equal(t, []string{
+		transcript.KindChainExec, transcript.KindTransfer, transcript.KindRelayerExec,
+		transcript.KindRelayerStart, transcript.KindSendFunds, transcript.KindRelayerStop,
+	}, kinds)
+	require.Equal(t, []string{"HOME=/home"}, entries[0].Env)
+	require.Equal(t, "rly", entries[2].Relayer)
+	require.Equal(t, []string{"gaia-osmosis"}, entries[3].Paths)
+
+	var execs [][]string
+	var transfers []string
+	chain := mock.NewChain(ibc.ChainConfig{ChainID: "gaia-1", Bech32Prefix: "cosmos"})
+	chain.ExecFunc = func(ctx context.Context, cmd []string, env []string) ([]byte, []byte, error) {
+		execs = append(execs, cmd)
+		return nil, nil, nil
+	}
+	require.NoError(t, chain.CreateKey(context.Background(), "faucet"))
+	faucet, err := chain.GetAddress(context.Background(), "faucet")
+	require.NoError(t, err)
+	chain.SetBalance(types.MustBech32ifyAddressBytes("cosmos", faucet), "uatom", math.NewInt(1000))
+	chain.SendIBCTransferFunc = func(ctx context.Context, channelID, keyName string, amount ibc.WalletAmount, timeout *ibc.IBCTimeout) (ibc.Tx, error) {
+		require.Equal(t, "channel-0", channelID)
+		require.Equal(t, "user", keyName)
+		transfers = append(transfers, amount.Amount.String()+amount.Denom+" to "+amount.Address)
+		return ibc.Tx{}, nil
+	}
+	r := relayermock.NewRelayer()
+
+	err = transcript.Replay(context.Background(), entries, transcript.Topology{
+		Chains:   []ibc.Chain{chain},
+		Relayers: map[string]ibc.Relayer{"rly": r},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"gaiad", "status"}}, execs)
+	require.Equal(t, []string{"100uatom to cosmos1receiver"}, transfers)
+	balance, err := chain.GetBalance(context.Background(), "cosmos1receiver", "uatom")
+	require.NoError(t, err)
+	require.Equal(t, int64(100), balance.Int64())
+	require.Equal(t, []relayermock.Call{
+		{Method: "Exec", Args: []any{[]string{"rly", "tx", "flush"}, []string(nil)}},
+		{Method: "StartRelayer", Args: []any{"gaia-osmosis"}},
+		{Method: "StopRelayer"},
+	}, r.Calls())
+}
+
+func TestReplay_Error(t *testing.T) {
+	t.Parallel()
+
+	chain := mock.NewChain(ibc.ChainConfig{ChainID: "gaia-1"})
+	chain.ExecFunc = func(ctx context.Context, cmd []string, env []string) ([]byte, []byte, error) {
+		if cmd[0] == "fail" {
+			return nil, nil, errors.New("exit code 1")
+		}
+		return nil, nil, nil
+	}
+
+	entries := []transcript.Entry{
+		{Kind: transcript.KindChainExec, ChainID: "gaia-1", Command: []string{"ok"}},
+		{Kind: transcript.KindChainExec, ChainID: "gaia-1", Command: []string{"fail"}},
+		{Kind: transcript.KindChainExec, ChainID: "gaia-1", Command: []string{"never"}},
+	}
+	err := transcript.Replay(context.Background(), entries, transcript.Topology{Chains: []ibc.Chain{chain}})
+	var replayErr *transcript.ReplayError
+	require.ErrorAs(t, err, &replayErr)
+	require.Equal(t, 1, replayErr.Index)
+	require.EqualError(t, err, "transcript entry 1 (ChainExec): exit code 1")
+
+	err = transcript.Replay(context.Background(), []transcript.Entry{
+		{Kind: transcript.KindRelayerExec, Relayer: "hermes"},
+	}, transcript.Topology{})
+	require.EqualError(t, err, "transcript entry 0 (RelayerExec): no relayer hermes")
+}
+
+func TestRead_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := transcript.Read(strings.NewReader("{\"Kind\":\"ChainExec\"}\n\nnot json\n"))
+	require.ErrorContains(t, err, "line 3")
+}